@@ -9,6 +9,23 @@ REGION="${AWS_REGION:-us-east-1}"
 
 ESTIMATES_TABLE="${ESTIMATES_TABLE:-estimates}"
 PAYMENTS_TABLE="${PAYMENTS_TABLE:-payments}"
+AUDIT_LOGS_TABLE="${AUDIT_LOGS_TABLE:-audit_logs}"
+PAYMENT_STATUS_EVENTS_TABLE="${PAYMENT_STATUS_EVENTS_TABLE:-payment_status_events}"
+DISPUTES_TABLE="${DISPUTES_TABLE:-disputes}"
+RECONCILIATION_RUNS_TABLE="${RECONCILIATION_RUNS_TABLE:-reconciliation_runs}"
+RECONCILIATION_DISCREPANCIES_TABLE="${RECONCILIATION_DISCREPANCIES_TABLE:-reconciliation_discrepancies}"
+ESTIMATE_CONVERSIONS_TABLE="${ESTIMATE_CONVERSIONS_TABLE:-estimate_conversions}"
+ACCOUNTING_EXPORTS_TABLE="${ACCOUNTING_EXPORTS_TABLE:-accounting_exports}"
+ACCOUNTING_EXPORT_ENTRIES_TABLE="${ACCOUNTING_EXPORT_ENTRIES_TABLE:-accounting_export_entries}"
+LEDGER_ENTRIES_TABLE="${LEDGER_ENTRIES_TABLE:-ledger_entries}"
+LEDGER_BALANCES_TABLE="${LEDGER_BALANCES_TABLE:-ledger_balances}"
+PAYMENT_OUTBOX_TABLE="${PAYMENT_OUTBOX_TABLE:-payment_outbox}"
+INVOICES_TABLE="${INVOICES_TABLE:-invoices}"
+SEQUENCES_TABLE="${SEQUENCES_TABLE:-sequences}"
+NFSE_DOCUMENTS_TABLE="${NFSE_DOCUMENTS_TABLE:-nfse_documents}"
+COUPONS_TABLE="${COUPONS_TABLE:-coupons}"
+CATALOG_ITEMS_TABLE="${CATALOG_ITEMS_TABLE:-catalog_items}"
+APPROVAL_LINKS_TABLE="${APPROVAL_LINKS_TABLE:-estimate_approval_links}"
 
 wait_for_dynamo() {
   echo "Waiting for DynamoDB Local at ${ENDPOINT_URL}..."
@@ -41,20 +58,162 @@ create_table_if_missing "${ESTIMATES_TABLE}" \
   --attribute-definitions \
     AttributeName=id,AttributeType=S \
     AttributeName=os_id,AttributeType=S \
+    AttributeName=status,AttributeType=S \
   --key-schema AttributeName=id,KeyType=HASH \
   --global-secondary-indexes \
     "IndexName=os_id-index,KeySchema=[{AttributeName=os_id,KeyType=HASH}],Projection={ProjectionType=ALL}" \
+    "IndexName=status-index,KeySchema=[{AttributeName=status,KeyType=HASH}],Projection={ProjectionType=ALL}" \
   --billing-mode PAY_PER_REQUEST
 
 create_table_if_missing "${PAYMENTS_TABLE}" \
   --attribute-definitions \
     AttributeName=id,AttributeType=S \
     AttributeName=estimate_id,AttributeType=S \
+    AttributeName=date,AttributeType=S \
+    AttributeName=status,AttributeType=S \
+    AttributeName=payer_email_hash,AttributeType=S \
+    AttributeName=payer_doc_hash,AttributeType=S \
   --key-schema AttributeName=id,KeyType=HASH \
   --global-secondary-indexes \
-    "IndexName=estimate_id-index,KeySchema=[{AttributeName=estimate_id,KeyType=HASH}],Projection={ProjectionType=ALL}" \
+    "IndexName=estimate_id-date-index,KeySchema=[{AttributeName=estimate_id,KeyType=HASH},{AttributeName=date,KeyType=RANGE}],Projection={ProjectionType=ALL}" \
+    "IndexName=status-date-index,KeySchema=[{AttributeName=status,KeyType=HASH},{AttributeName=date,KeyType=RANGE}],Projection={ProjectionType=ALL}" \
+    "IndexName=payer_email_hash-index,KeySchema=[{AttributeName=payer_email_hash,KeyType=HASH}],Projection={ProjectionType=ALL}" \
+    "IndexName=payer_doc_hash-index,KeySchema=[{AttributeName=payer_doc_hash,KeyType=HASH}],Projection={ProjectionType=ALL}" \
   --billing-mode PAY_PER_REQUEST
 
+create_table_if_missing "${AUDIT_LOGS_TABLE}" \
+  --attribute-definitions \
+    AttributeName=id,AttributeType=S \
+  --key-schema AttributeName=id,KeyType=HASH \
+  --billing-mode PAY_PER_REQUEST
+
+create_table_if_missing "${PAYMENT_STATUS_EVENTS_TABLE}" \
+  --attribute-definitions \
+    AttributeName=payment_id,AttributeType=S \
+    AttributeName=event_key,AttributeType=S \
+    AttributeName=status,AttributeType=S \
+  --key-schema AttributeName=payment_id,KeyType=HASH AttributeName=event_key,KeyType=RANGE \
+  --global-secondary-indexes \
+    "IndexName=status-event_key-index,KeySchema=[{AttributeName=status,KeyType=HASH},{AttributeName=event_key,KeyType=RANGE}],Projection={ProjectionType=ALL}" \
+  --billing-mode PAY_PER_REQUEST
+
+create_table_if_missing "${DISPUTES_TABLE}" \
+  --attribute-definitions \
+    AttributeName=id,AttributeType=S \
+    AttributeName=status,AttributeType=S \
+  --key-schema AttributeName=id,KeyType=HASH \
+  --global-secondary-indexes \
+    "IndexName=status-index,KeySchema=[{AttributeName=status,KeyType=HASH}],Projection={ProjectionType=ALL}" \
+  --billing-mode PAY_PER_REQUEST
+
+create_table_if_missing "${RECONCILIATION_RUNS_TABLE}" \
+  --attribute-definitions \
+    AttributeName=id,AttributeType=S \
+  --key-schema AttributeName=id,KeyType=HASH \
+  --billing-mode PAY_PER_REQUEST
+
+create_table_if_missing "${RECONCILIATION_DISCREPANCIES_TABLE}" \
+  --attribute-definitions \
+    AttributeName=run_id,AttributeType=S \
+    AttributeName=seq,AttributeType=N \
+  --key-schema AttributeName=run_id,KeyType=HASH AttributeName=seq,KeyType=RANGE \
+  --billing-mode PAY_PER_REQUEST
+
+create_table_if_missing "${ESTIMATE_CONVERSIONS_TABLE}" \
+  --attribute-definitions \
+    AttributeName=id,AttributeType=S \
+    AttributeName=created_month,AttributeType=S \
+    AttributeName=created_at,AttributeType=S \
+  --key-schema AttributeName=id,KeyType=HASH \
+  --global-secondary-indexes \
+    "IndexName=created_month-created_at-index,KeySchema=[{AttributeName=created_month,KeyType=HASH},{AttributeName=created_at,KeyType=RANGE}],Projection={ProjectionType=ALL}" \
+  --billing-mode PAY_PER_REQUEST
+
+create_table_if_missing "${ACCOUNTING_EXPORTS_TABLE}" \
+  --attribute-definitions \
+    AttributeName=id,AttributeType=S \
+  --key-schema AttributeName=id,KeyType=HASH \
+  --billing-mode PAY_PER_REQUEST
+
+create_table_if_missing "${ACCOUNTING_EXPORT_ENTRIES_TABLE}" \
+  --attribute-definitions \
+    AttributeName=export_id,AttributeType=S \
+    AttributeName=seq,AttributeType=N \
+  --key-schema AttributeName=export_id,KeyType=HASH AttributeName=seq,KeyType=RANGE \
+  --billing-mode PAY_PER_REQUEST
+
+create_table_if_missing "${LEDGER_ENTRIES_TABLE}" \
+  --attribute-definitions \
+    AttributeName=id,AttributeType=S \
+    AttributeName=estimate_id,AttributeType=S \
+    AttributeName=created_at,AttributeType=S \
+  --key-schema AttributeName=id,KeyType=HASH \
+  --global-secondary-indexes \
+    "IndexName=estimate_id-created_at-index,KeySchema=[{AttributeName=estimate_id,KeyType=HASH},{AttributeName=created_at,KeyType=RANGE}],Projection={ProjectionType=ALL}" \
+  --billing-mode PAY_PER_REQUEST
+
+create_table_if_missing "${LEDGER_BALANCES_TABLE}" \
+  --attribute-definitions \
+    AttributeName=scope,AttributeType=S \
+    AttributeName=account,AttributeType=S \
+  --key-schema AttributeName=scope,KeyType=HASH AttributeName=account,KeyType=RANGE \
+  --global-secondary-indexes \
+    "IndexName=account-scope-index,KeySchema=[{AttributeName=account,KeyType=HASH},{AttributeName=scope,KeyType=RANGE}],Projection={ProjectionType=ALL}" \
+  --billing-mode PAY_PER_REQUEST
+
+create_table_if_missing "${PAYMENT_OUTBOX_TABLE}" \
+  --attribute-definitions \
+    AttributeName=payment_id,AttributeType=S \
+  --key-schema AttributeName=payment_id,KeyType=HASH \
+  --billing-mode PAY_PER_REQUEST
+
+create_table_if_missing "${INVOICES_TABLE}" \
+  --attribute-definitions \
+    AttributeName=id,AttributeType=S \
+  --key-schema AttributeName=id,KeyType=HASH \
+  --billing-mode PAY_PER_REQUEST
+
+create_table_if_missing "${SEQUENCES_TABLE}" \
+  --attribute-definitions \
+    AttributeName=id,AttributeType=S \
+  --key-schema AttributeName=id,KeyType=HASH \
+  --billing-mode PAY_PER_REQUEST
+
+create_table_if_missing "${NFSE_DOCUMENTS_TABLE}" \
+  --attribute-definitions \
+    AttributeName=id,AttributeType=S \
+    AttributeName=tomador_doc_hash,AttributeType=S \
+    AttributeName=tomador_email_hash,AttributeType=S \
+  --key-schema AttributeName=id,KeyType=HASH \
+  --global-secondary-indexes \
+    "IndexName=tomador_doc_hash-index,KeySchema=[{AttributeName=tomador_doc_hash,KeyType=HASH}],Projection={ProjectionType=ALL}" \
+    "IndexName=tomador_email_hash-index,KeySchema=[{AttributeName=tomador_email_hash,KeyType=HASH}],Projection={ProjectionType=ALL}" \
+  --billing-mode PAY_PER_REQUEST
+
+create_table_if_missing "${COUPONS_TABLE}" \
+  --attribute-definitions \
+    AttributeName=id,AttributeType=S \
+  --key-schema AttributeName=id,KeyType=HASH \
+  --billing-mode PAY_PER_REQUEST
+
+create_table_if_missing "${CATALOG_ITEMS_TABLE}" \
+  --attribute-definitions \
+    AttributeName=id,AttributeType=S \
+  --key-schema AttributeName=id,KeyType=HASH \
+  --billing-mode PAY_PER_REQUEST
+
+create_table_if_missing "${APPROVAL_LINKS_TABLE}" \
+  --attribute-definitions \
+    AttributeName=id,AttributeType=S \
+    AttributeName=recipient_hash,AttributeType=S \
+  --key-schema AttributeName=id,KeyType=HASH \
+  --global-secondary-indexes \
+    "IndexName=recipient_hash-index,KeySchema=[{AttributeName=recipient_hash,KeyType=HASH}],Projection={ProjectionType=ALL}" \
+  --billing-mode PAY_PER_REQUEST
+# Links expired for 90 days are removed by TTL (ignored where TTL is not supported).
+aws dynamodb update-time-to-live --table-name "${APPROVAL_LINKS_TABLE}" --endpoint-url "${ENDPOINT_URL}" --region "${REGION}" \
+  --time-to-live-specification Enabled=true,AttributeName=ttl >/dev/null 2>&1 || true
+
 echo "DynamoDB tables ready."
 
 # --- Seed demo data (1 record per table) ---
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"mecanica_xpto/internal/adapter/persistence/repository"
+	"mecanica_xpto/internal/infrastructure/database"
+	"mecanica_xpto/internal/usecase"
+
+	_ "github.com/joho/godotenv/autoload"
+)
+
+// backfill-ledger first completes the postings left in the payment outbox (status event
+// and ledger entry of payments whose creation transaction failed), then posts the ledger
+// entries of payments created before the ledger existed. Entries already posted are
+// skipped, so it is safe to re-run, and it should run periodically.
+//
+// Usage:
+//
+//	go run ./cmd/backfill-ledger [-batch 100] [-dry-run]
+func main() {
+	batch := flag.Int("batch", 100, "items read per scan page")
+	dryRun := flag.Bool("dry-run", false, "report what would be posted without writing")
+	flag.Parse()
+
+	ddb := database.ConnectDynamoDB()
+	uc := usecase.NewLedgerUseCase(repository.NewLedgerDynamoRepository(ddb), repository.NewBillingPaymentDynamoRepository(ddb))
+
+	pending, err := uc.PostPending(context.Background(), *dryRun)
+	if err != nil {
+		log.Fatalf("pending postings failed: %v", err)
+	}
+	log.Printf("pending postings done dry_run=%t found=%d completed=%d failed=%d",
+		*dryRun, pending.Scanned, pending.Updated, pending.Failed)
+
+	report, err := uc.Backfill(context.Background(), int32(*batch), *dryRun)
+	if err != nil {
+		log.Fatalf("ledger backfill failed: %v", err)
+	}
+	log.Printf("ledger backfill done dry_run=%t scanned=%d posted=%d skipped=%d failed=%d",
+		*dryRun, report.Scanned, report.Updated, report.Skipped, report.Failed)
+}
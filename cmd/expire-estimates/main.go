@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"log"
+	"mecanica_xpto/internal/adapter/persistence/repository"
+	"mecanica_xpto/internal/infrastructure/database"
+	"mecanica_xpto/internal/usecase"
+
+	_ "github.com/joho/godotenv/autoload"
+)
+
+// expire-estimates runs one sweep of the estimate expiration, like the API does every
+// ESTIMATE_EXPIRATION_INTERVAL. Use it when the sweeper is disabled in the API.
+//
+// Usage:
+//
+//	go run ./cmd/expire-estimates
+func main() {
+	ddb := database.ConnectDynamoDB()
+	uc := usecase.NewEstimateUseCase(repository.NewEstimateDynamoRepository(ddb)).
+		WithConversionProjection(repository.NewEstimateConversionDynamoRepository(ddb))
+
+	expired, err := uc.ExpireDue(context.Background())
+	if err != nil {
+		log.Fatalf("estimate expiration failed expired=%d: %v", expired, err)
+	}
+	log.Printf("estimate expiration done expired=%d", expired)
+}
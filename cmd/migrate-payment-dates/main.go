@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"mecanica_xpto/internal/adapter/persistence/repository"
+	"mecanica_xpto/internal/infrastructure/database"
+	"mecanica_xpto/internal/usecase"
+
+	_ "github.com/joho/godotenv/autoload"
+)
+
+// migrate-payment-dates rewrites the date sort key of payments written before the
+// fixed-width layout (RFC3339Nano), so the date GSIs sort and paginate them correctly.
+//
+// Usage:
+//
+//	go run ./cmd/migrate-payment-dates [-batch 100] [-dry-run]
+func main() {
+	batch := flag.Int("batch", 100, "items read per scan page")
+	dryRun := flag.Bool("dry-run", false, "report what would change without writing")
+	flag.Parse()
+
+	ddb := database.ConnectDynamoDB()
+	repo := repository.NewBillingPaymentDynamoRepository(ddb)
+	uc := usecase.NewPaymentDateMigrationUseCase(repo)
+
+	report, err := uc.Run(context.Background(), int32(*batch), *dryRun)
+	if err != nil {
+		log.Fatalf("payment date migration failed: %v", err)
+	}
+	log.Printf("payment date migration done dry_run=%t scanned=%d updated=%d skipped=%d failed=%d",
+		*dryRun, report.Scanned, report.Updated, report.Skipped, report.Failed)
+}
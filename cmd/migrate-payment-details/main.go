@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"mecanica_xpto/internal/adapter/persistence/repository"
+	"mecanica_xpto/internal/infrastructure/database"
+	"mecanica_xpto/internal/infrastructure/payments"
+	"mecanica_xpto/internal/usecase"
+
+	_ "github.com/joho/godotenv/autoload"
+)
+
+// migrate-payment-details backfills the typed payment detail attributes
+// (amount, fees, payment method, card, payer...) from mp_payload_raw.
+//
+// Usage:
+//
+//	go run ./cmd/migrate-payment-details [-batch 100] [-dry-run]
+func main() {
+	batch := flag.Int("batch", 100, "items read per scan page")
+	dryRun := flag.Bool("dry-run", false, "report what would change without writing")
+	flag.Parse()
+
+	ddb := database.ConnectDynamoDB()
+	repo := repository.NewBillingPaymentDynamoRepository(ddb)
+	uc := usecase.NewPaymentDetailsBackfillUseCase(repo, &payments.MercadoPagoGateway{})
+
+	report, err := uc.Run(context.Background(), int32(*batch), *dryRun)
+	if err != nil {
+		log.Fatalf("payment details backfill failed: %v", err)
+	}
+	log.Printf("payment details backfill done dry_run=%t scanned=%d updated=%d skipped=%d failed=%d",
+		*dryRun, report.Scanned, report.Updated, report.Skipped, report.Failed)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"mecanica_xpto/internal/adapter/persistence/repository"
+	"mecanica_xpto/internal/infrastructure/database"
+	"mecanica_xpto/internal/infrastructure/security"
+	"mecanica_xpto/internal/usecase"
+
+	_ "github.com/joho/godotenv/autoload"
+)
+
+// migrate-payment-encryption encrypts payer personal data stored in plaintext,
+// re-encrypts payments protected with a previous key (after PII_KEY_FILE rotation)
+// and fills the payer blind indexes used by data subject (LGPD) requests.
+//
+// Usage:
+//
+//	go run ./cmd/migrate-payment-encryption [-batch 100] [-dry-run]
+func main() {
+	batch := flag.Int("batch", 100, "items read per scan page")
+	dryRun := flag.Bool("dry-run", false, "report what would change without writing")
+	flag.Parse()
+
+	keys, err := security.NewKeyProviderFromEnv()
+	if err != nil {
+		log.Fatalf("failed to load encryption keys: %v", err)
+	}
+	if keys == nil {
+		log.Printf("PII_KEY_FILE is not set (development); only payer blind indexes will be filled")
+	}
+	index, err := security.NewBlindIndexFromEnv()
+	if err != nil {
+		log.Fatalf("failed to load blind index key: %v", err)
+	}
+
+	ddb := database.ConnectDynamoDB()
+	repo := repository.NewBillingPaymentDynamoRepository(ddb)
+	uc := usecase.NewPaymentEncryptionMigrationUseCase(repo, security.NewPaymentDataProtector(keys, index))
+
+	report, err := uc.Run(context.Background(), int32(*batch), *dryRun)
+	if err != nil {
+		log.Fatalf("payment encryption migration failed: %v", err)
+	}
+	log.Printf("payment encryption migration done dry_run=%t scanned=%d updated=%d skipped=%d failed=%d",
+		*dryRun, report.Scanned, report.Updated, report.Skipped, report.Failed)
+}
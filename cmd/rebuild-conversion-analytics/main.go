@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"log"
+	"mecanica_xpto/internal/adapter/persistence/repository"
+	"mecanica_xpto/internal/infrastructure/database"
+	"mecanica_xpto/internal/usecase"
+
+	_ "github.com/joho/godotenv/autoload"
+)
+
+// rebuild-conversion-analytics projects every estimate (and its first approved payment)
+// into the estimate_conversions read model. Run it once after creating the table, or to
+// repair projections that failed while the API was running.
+//
+// Usage:
+//
+//	go run ./cmd/rebuild-conversion-analytics
+func main() {
+	ddb := database.ConnectDynamoDB()
+	uc := usecase.NewConversionAnalyticsUseCase(
+		repository.NewEstimateConversionDynamoRepository(ddb),
+		repository.NewEstimateDynamoRepository(ddb),
+		repository.NewBillingPaymentDynamoRepository(ddb),
+	)
+
+	report, err := uc.Rebuild(context.Background())
+	if err != nil {
+		log.Fatalf("conversion analytics rebuild failed: %v", err)
+	}
+	log.Printf("conversion analytics rebuild done projected=%d paid=%d failed=%d", report.Projected, report.Paid, report.Failed)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"mecanica_xpto/internal/adapter/persistence/repository"
+	"mecanica_xpto/internal/infrastructure/database"
+	"mecanica_xpto/internal/infrastructure/payments"
+	"mecanica_xpto/internal/usecase"
+	"os"
+	"path/filepath"
+
+	_ "github.com/joho/godotenv/autoload"
+)
+
+// reconcile-settlement imports a Mercado Pago settlement/release report (CSV), reconciles
+// it with the stored payments and stores the run, like POST /v1/admin/reconciliations.
+//
+// Usage:
+//
+//	go run ./cmd/reconcile-settlement -file settlement.csv [-actor finance] [-out discrepancies.csv]
+func main() {
+	file := flag.String("file", "", "settlement report CSV exported from Mercado Pago")
+	actor := flag.String("actor", "cli", "operator recorded on the run")
+	out := flag.String("out", "", "write the discrepancy report (CSV) to this path")
+	flag.Parse()
+	if *file == "" {
+		log.Fatalf("-file is required")
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("failed to open settlement report: %v", err)
+	}
+	defer f.Close()
+
+	ddb := database.ConnectDynamoDB()
+	uc := usecase.NewSettlementReconciliationUseCase(
+		&payments.MercadoPagoGateway{},
+		repository.NewBillingPaymentDynamoRepository(ddb),
+		repository.NewReconciliationRunDynamoRepository(ddb),
+	)
+
+	run, err := uc.Reconcile(context.Background(), usecase.SettlementReport{
+		FileName: filepath.Base(*file),
+		Actor:    *actor,
+		Content:  f,
+	})
+	if err != nil {
+		log.Fatalf("settlement reconciliation failed: %v", err)
+	}
+	log.Printf("settlement reconciliation done run_id=%s lines=%d matched=%d ignored=%d discrepancies=%d summary=%v",
+		run.ID, run.TotalLines, run.MatchedLines, run.IgnoredLines, len(run.Discrepancies), run.CountByKind())
+
+	if *out == "" {
+		return
+	}
+	w, err := os.Create(*out)
+	if err != nil {
+		log.Fatalf("failed to create discrepancy report: %v", err)
+	}
+	defer w.Close()
+	if err := usecase.WriteDiscrepancyReport(w, run); err != nil {
+		log.Fatalf("failed to write discrepancy report: %v", err)
+	}
+}
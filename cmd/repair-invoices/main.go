@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"mecanica_xpto/internal/adapter/persistence/repository"
+	"mecanica_xpto/internal/infrastructure/database"
+	"mecanica_xpto/internal/usecase"
+
+	_ "github.com/joho/godotenv/autoload"
+)
+
+// repair-invoices issues the invoices that failed to be issued on approval and applies or
+// takes out the payments whose invoice update failed. Invoices already in line are left
+// alone, so it is safe to re-run.
+//
+// Usage:
+//
+//	go run ./cmd/repair-invoices [-dry-run]
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report what would be repaired without writing")
+	flag.Parse()
+
+	ddb := database.ConnectDynamoDB()
+	uc := usecase.NewInvoiceUseCase(
+		repository.NewInvoiceDynamoRepository(ddb),
+		repository.NewEstimateDynamoRepository(ddb),
+		repository.NewBillingPaymentDynamoRepository(ddb),
+	)
+
+	report, err := uc.Repair(context.Background(), *dryRun)
+	if err != nil {
+		log.Fatalf("invoice repair failed: %v", err)
+	}
+	log.Printf("invoice repair done dry_run=%t scanned=%d repaired=%d skipped=%d failed=%d",
+		*dryRun, report.Scanned, report.Updated, report.Skipped, report.Failed)
+}
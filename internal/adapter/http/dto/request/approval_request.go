@@ -0,0 +1,29 @@
+package request
+
+// ApprovalLinkRequest issues an approval link for an estimate, sent to the customer through
+// channel ("email" or "sms") at recipient.
+
+type ApprovalLinkRequest struct {
+	Channel   string `json:"channel" binding:"required"`
+	Recipient string `json:"recipient" binding:"required"`
+	CreatedBy string `json:"created_by"`
+}
+
+// CustomerDecisionRequest is the customer answer through an approval link: action is
+// "aprovar" or "rejeitar", confirmed by the otp received. approved_items and reason work
+// as in the approve and reject endpoints.
+
+type CustomerDecisionRequest struct {
+	Action        string               `json:"action" binding:"required"`
+	OTP           string               `json:"otp" binding:"required"`
+	ApprovedItems []string             `json:"approved_items"`
+	Reason        *StatusReasonRequest `json:"reason"`
+}
+
+// InternalApprovalRequest is a manager's sign-off of an estimate above the internal
+// approval threshold: action is "aprovar" or "rejeitar", the comment is required to reject.
+
+type InternalApprovalRequest struct {
+	Action  string `json:"action" binding:"required"`
+	Comment string `json:"comment"`
+}
@@ -9,3 +9,31 @@ import "encoding/json"
 type BillingPaymentCreateRequest struct {
 	MPPayload json.RawMessage `json:"mp_payload"`
 }
+
+// PaymentStatusChangeRequest is the payload of the admin status change route.
+// When status is empty it is derived from provider_status.
+
+type PaymentStatusChangeRequest struct {
+	Status               string `json:"status"`
+	ProviderStatus       string `json:"provider_status"`
+	ProviderStatusDetail string `json:"provider_status_detail"`
+}
+
+// PaymentPageQuery holds the pagination query parameters of the payment list routes.
+// `order=desc` lists newest payments first.
+
+type PaymentPageQuery struct {
+	Limit  int32  `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor string `form:"cursor"`
+	Order  string `form:"order" binding:"omitempty,oneof=asc desc"`
+}
+
+// PaymentListQuery filters the payment list by status and date range. `from`/`to`
+// accept RFC3339 timestamps or YYYY-MM-DD dates (a date-only `to` covers the whole day).
+
+type PaymentListQuery struct {
+	PaymentPageQuery
+	Status string `form:"status" binding:"required"`
+	From   string `form:"from"`
+	To     string `form:"to"`
+}
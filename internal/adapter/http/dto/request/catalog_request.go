@@ -0,0 +1,8 @@
+package request
+
+// CatalogItemRequest creates or replaces the catalog entry of the kind and code in the path.
+
+type CatalogItemRequest struct {
+	Name  string  `json:"name" binding:"required"`
+	Price float64 `json:"price" binding:"required"`
+}
@@ -0,0 +1,40 @@
+package request
+
+import (
+	"mecanica_xpto/internal/domain/entities"
+	"strings"
+	"time"
+)
+
+// CouponRequest creates a coupon. Dates are RFC 3339; expires_at is exclusive. Zero limits
+// mean unlimited.
+
+type CouponRequest struct {
+	Code           string     `json:"code" binding:"required"`
+	Description    string     `json:"description"`
+	Type           string     `json:"type" binding:"required"`
+	Value          float64    `json:"value" binding:"required"`
+	Scope          string     `json:"scope" binding:"required"`
+	ItemKind       string     `json:"item_kind"`
+	ValidFrom      *time.Time `json:"valid_from"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	MaxRedemptions int        `json:"max_redemptions"`
+	MaxPerCustomer int        `json:"max_per_customer"`
+}
+
+func (r CouponRequest) ToEntity() entities.Coupon {
+	return entities.Coupon{
+		Code:        r.Code,
+		Description: r.Description,
+		Rule: entities.DiscountRule{
+			Type:     entities.DiscountType(strings.TrimSpace(r.Type)),
+			Value:    r.Value,
+			Scope:    entities.DiscountScope(strings.TrimSpace(r.Scope)),
+			ItemKind: entities.EstimateItemKind(strings.TrimSpace(r.ItemKind)),
+		},
+		ValidFrom:      r.ValidFrom,
+		ExpiresAt:      r.ExpiresAt,
+		MaxRedemptions: r.MaxRedemptions,
+		MaxPerCustomer: r.MaxPerCustomer,
+	}
+}
@@ -0,0 +1,17 @@
+package request
+
+import "strings"
+
+// DataSubjectRequest identifies the payer (LGPD "titular") of an export or anonymization
+// request. At least one of email, document (CPF/CNPJ) or phone is required; the phone only
+// finds approval links sent by SMS.
+
+type DataSubjectRequest struct {
+	Email    string `json:"email"`
+	Document string `json:"document"`
+	Phone    string `json:"phone"`
+}
+
+func (r DataSubjectRequest) IsEmpty() bool {
+	return strings.TrimSpace(r.Email) == "" && strings.TrimSpace(r.Document) == "" && strings.TrimSpace(r.Phone) == ""
+}
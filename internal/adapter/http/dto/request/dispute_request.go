@@ -0,0 +1,20 @@
+package request
+
+// DisputeEvidenceRequest attaches an evidence reference (e.g. document storage key of the
+// signed service order) to a chargeback dispute.
+
+type DisputeEvidenceRequest struct {
+	Reference   string `json:"reference" binding:"required"`
+	Description string `json:"description"`
+}
+
+// MercadoPagoNotificationRequest is the webhook body sent by Mercado Pago.
+// Legacy IPN notifications send `topic`/`id` as query parameters instead.
+
+type MercadoPagoNotificationRequest struct {
+	Type   string `json:"type"`
+	Action string `json:"action"`
+	Data   struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
@@ -2,6 +2,7 @@ package request
 
 import (
 	"errors"
+	"mecanica_xpto/internal/domain/entities"
 	"strings"
 )
 
@@ -15,13 +16,27 @@ type PartsSupplyRequest struct {
 	Description string  `json:"description" binding:"required"`
 	Price       float64 `json:"price" binding:"required"`
 	Quantity    int     `json:"quantity" binding:"required"`
+	// Category groups parts for the pricing rules (e.g. markup by category).
+	Category string `json:"category"`
+	// Code is the SKU checked against the price catalog.
+	Code string `json:"code"`
 }
 
+// ServiceRequest is a flat-priced service, or a labor service when hours is given: then
+// price is ignored and the service is priced at the hourly rate of skill_tier.
 type ServiceRequest struct {
 	ID          string  `json:"id"`
 	Name        string  `json:"name" binding:"required"`
 	Description string  `json:"description" binding:"required"`
-	Price       float64 `json:"price" binding:"required"`
+	Price       float64 `json:"price"`
+	// Code is the service code checked against the price catalog.
+	Code      string  `json:"code"`
+	Hours     float64 `json:"hours"`
+	SkillTier string  `json:"skill_tier"`
+}
+
+func (s ServiceRequest) isLabor() bool {
+	return s.Hours != 0 || strings.TrimSpace(s.SkillTier) != ""
 }
 
 // EstimateRequest is an integration-facing payload accepted by compatibility
@@ -31,6 +46,54 @@ type EstimateRequest struct {
 	ServiceOrderID     string               `json:"service_order_id" binding:"required"`
 	Services           []ServiceRequest     `json:"services"`
 	PartsSupplies      []PartsSupplyRequest `json:"parts_supplies"`
+	// Municipality is the IBGE code used to pick the tax rates (default: configured one).
+	Municipality string `json:"municipality"`
+	// CustomerID is required by coupons limited per customer.
+	CustomerID string `json:"customer_id"`
+	// CustomerSegment (e.g. "frota") selects the segment pricing rules.
+	CustomerSegment string                  `json:"customer_segment"`
+	Coupons         []string                `json:"coupons"`
+	Discounts       []ManualDiscountRequest `json:"discounts"`
+	// ValidityDays overrides the configured validity (ESTIMATE_VALIDITY_DAYS) of the
+	// estimate, in days.
+	ValidityDays int `json:"validity_days"`
+	// Reason and Actor tell why and by whom an estimate is rejected or canceled.
+	Reason *StatusReasonRequest `json:"reason"`
+	Actor  string               `json:"actor"`
+	// ApprovedItems are the IDs of the items the customer approved; the others are
+	// declined. Empty approves the whole estimate.
+	ApprovedItems []string `json:"approved_items"`
+}
+
+// StatusReasonRequest is a code of the reason catalog (GET /v1/estimates/reasons) and an
+// optional free text.
+type StatusReasonRequest struct {
+	Code string `json:"code"`
+	Text string `json:"text"`
+}
+
+// ManualDiscountRequest is a discount granted at the front desk. type is "percentual" or
+// "fixo" and scope "orcamento" or "item" (optionally limited to item_kind).
+type ManualDiscountRequest struct {
+	Type       string  `json:"type"`
+	Value      float64 `json:"value"`
+	Scope      string  `json:"scope"`
+	ItemKind   string  `json:"item_kind"`
+	Reason     string  `json:"reason"`
+	ApprovedBy string  `json:"approved_by"`
+}
+
+func (r ManualDiscountRequest) ToEntity() entities.ManualDiscount {
+	return entities.ManualDiscount{
+		Rule: entities.DiscountRule{
+			Type:     entities.DiscountType(strings.TrimSpace(r.Type)),
+			Value:    r.Value,
+			Scope:    entities.DiscountScope(strings.TrimSpace(r.Scope)),
+			ItemKind: entities.EstimateItemKind(strings.TrimSpace(r.ItemKind)),
+		},
+		Reason:     r.Reason,
+		ApprovedBy: r.ApprovedBy,
+	}
 }
 
 func (r EstimateRequest) ResolveOSID() string {
@@ -47,10 +110,15 @@ func (r EstimateRequest) ResolveEstimateID() string {
 	return ""
 }
 
+// ResolvePrice sums the flat-priced items; labor services are priced by the use case, so an
+// estimate with only labor services resolves to 0.
 func (r EstimateRequest) ResolvePrice() (float64, error) {
 	totalFromItems := 0.0
+	labor := false
 	for _, s := range r.Services {
-		if s.Price > 0 {
+		if s.isLabor() {
+			labor = true
+		} else if s.Price > 0 {
 			totalFromItems += s.Price
 		}
 	}
@@ -59,9 +127,83 @@ func (r EstimateRequest) ResolvePrice() (float64, error) {
 			totalFromItems += p.Price * float64(p.Quantity)
 		}
 	}
-	if totalFromItems > 0 {
+	if totalFromItems > 0 || labor {
 		return totalFromItems, nil
 	}
 
 	return 0, ErrInvalidEstimateValue
 }
+
+// ResolveItems returns the items counted by ResolvePrice, services as ISS-taxed items and
+// parts as ICMS/IPI-taxed items, and the labor services, still unpriced.
+func (r EstimateRequest) ResolveItems() []entities.EstimateItem {
+	var items []entities.EstimateItem
+	for _, s := range r.Services {
+		if s.isLabor() {
+			items = append(items, entities.EstimateItem{
+				ID:          s.ID,
+				Kind:        entities.EstimateItemServico,
+				Code:        entities.NormalizeCatalogCode(s.Code),
+				Name:        s.Name,
+				Description: s.Description,
+				Hours:       s.Hours,
+				SkillTier:   entities.NormalizeSkillTier(s.SkillTier),
+			})
+		} else if s.Price > 0 {
+			items = append(items, entities.EstimateItem{
+				ID:          s.ID,
+				Kind:        entities.EstimateItemServico,
+				Code:        entities.NormalizeCatalogCode(s.Code),
+				Name:        s.Name,
+				Description: s.Description,
+				Quantity:    1,
+				UnitPrice:   s.Price,
+				Total:       s.Price,
+			})
+		}
+	}
+	for _, p := range r.PartsSupplies {
+		if p.Price > 0 && p.Quantity > 0 {
+			items = append(items, entities.EstimateItem{
+				ID:          p.ID,
+				Kind:        entities.EstimateItemPeca,
+				Code:        entities.NormalizeCatalogCode(p.Code),
+				Name:        p.Name,
+				Description: p.Description,
+				Category:    strings.TrimSpace(p.Category),
+				Quantity:    p.Quantity,
+				UnitPrice:   p.Price,
+				Total:       p.Price * float64(p.Quantity),
+			})
+		}
+	}
+	return items
+}
+
+func (r EstimateRequest) ResolveManualDiscounts() []entities.ManualDiscount {
+	var discounts []entities.ManualDiscount
+	for _, d := range r.Discounts {
+		discounts = append(discounts, d.ToEntity())
+	}
+	return discounts
+}
+
+// ActualHoursRequest records the hours worked on the labor services of an estimate when the
+// service order finishes.
+type ActualHoursRequest struct {
+	Services []ActualHoursItemRequest `json:"services" binding:"required,min=1,dive"`
+}
+
+type ActualHoursItemRequest struct {
+	ID    string  `json:"id" binding:"required"`
+	Hours float64 `json:"hours" binding:"required"`
+}
+
+// ResolveHours returns the hours by item ID; a repeated ID keeps the last hours.
+func (r ActualHoursRequest) ResolveHours() map[string]float64 {
+	hours := make(map[string]float64, len(r.Services))
+	for _, s := range r.Services {
+		hours[strings.TrimSpace(s.ID)] = s.Hours
+	}
+	return hours
+}
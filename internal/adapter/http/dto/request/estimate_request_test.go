@@ -3,6 +3,8 @@ package request
 import (
 	"errors"
 	"testing"
+
+	"mecanica_xpto/internal/domain/entities"
 )
 
 func TestEstimateRequest_ResolveOSIDAndEstimateID(t *testing.T) {
@@ -46,3 +48,53 @@ func TestEstimateRequest_ResolvePrice(t *testing.T) {
 		t.Fatalf("expected ErrInvalidEstimateValue, got %v", err)
 	}
 }
+
+func TestEstimateRequest_ResolveItems(t *testing.T) {
+	r := EstimateRequest{
+		Services:      []ServiceRequest{{Name: "Alinhamento", Price: 10}, {Price: -1}},
+		PartsSupplies: []PartsSupplyRequest{{Name: "Filtro", Price: 3, Quantity: 2}, {Price: 4, Quantity: 0}},
+	}
+	items := r.ResolveItems()
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %+v", items)
+	}
+	if items[0].Kind != entities.EstimateItemServico || items[0].Total != 10 || items[0].Quantity != 1 {
+		t.Fatalf("unexpected service item: %+v", items[0])
+	}
+	if items[1].Kind != entities.EstimateItemPeca || items[1].Total != 6 || items[1].UnitPrice != 3 {
+		t.Fatalf("unexpected part item: %+v", items[1])
+	}
+}
+
+func TestEstimateRequest_LaborServices(t *testing.T) {
+	r := EstimateRequest{Services: []ServiceRequest{
+		{ID: "srv-1", Name: "Retífica", Price: 999, Hours: 2.5, SkillTier: " Senior "},
+		{Name: "Diagnóstico", Hours: 1},
+	}}
+	price, err := r.ResolvePrice()
+	if err != nil || price != 0 {
+		t.Fatalf("expected labor services to resolve to 0, got %v err=%v", price, err)
+	}
+	items := r.ResolveItems()
+	if len(items) != 2 || items[0].Hours != 2.5 || items[0].SkillTier != "senior" || items[0].Total != 0 || items[1].SkillTier != "" {
+		t.Fatalf("unexpected labor items: %+v", items)
+	}
+
+	hours := ActualHoursRequest{Services: []ActualHoursItemRequest{{ID: " srv-1 ", Hours: 3}}}.ResolveHours()
+	if len(hours) != 1 || hours["srv-1"] != 3 {
+		t.Fatalf("unexpected hours: %v", hours)
+	}
+}
+
+func TestEstimateRequest_ResolveManualDiscounts(t *testing.T) {
+	r := EstimateRequest{Discounts: []ManualDiscountRequest{
+		{Type: "percentual", Value: 5, Scope: " item ", ItemKind: "peca", Reason: "fidelidade", ApprovedBy: "gerente"},
+	}}
+	got := r.ResolveManualDiscounts()
+	if len(got) != 1 || got[0].Rule.Scope != entities.DiscountScopeItem || got[0].Rule.ItemKind != entities.EstimateItemPeca || !got[0].IsValid() {
+		t.Fatalf("unexpected discounts: %+v", got)
+	}
+	if (EstimateRequest{}).ResolveManualDiscounts() != nil {
+		t.Fatalf("expected no discounts")
+	}
+}
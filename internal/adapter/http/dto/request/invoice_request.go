@@ -0,0 +1,56 @@
+package request
+
+import "mecanica_xpto/internal/domain/entities"
+
+// InvoiceIssueRequest issues the invoice of an approved estimate.
+
+type InvoiceIssueRequest struct {
+	EstimateID string `json:"estimate_id" binding:"required"`
+}
+
+// InvoiceVoidRequest cancels an invoice without applied payments.
+
+type InvoiceVoidRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// CreditNoteRequest credits Amount (reais) on an invoice.
+
+type CreditNoteRequest struct {
+	Amount float64 `json:"amount" binding:"required"`
+	Reason string  `json:"reason" binding:"required"`
+}
+
+// NFSeRequest generates the NFS-e of an invoice for the service taker (tomador).
+
+type NFSeRequest struct {
+	InvoiceID string             `json:"invoice_id" binding:"required"`
+	Tomador   NFSeTomadorRequest `json:"tomador" binding:"required"`
+}
+
+type NFSeTomadorRequest struct {
+	Document string              `json:"document" binding:"required"`
+	Name     string              `json:"name" binding:"required"`
+	Email    string              `json:"email"`
+	Address  *NFSeAddressRequest `json:"address"`
+}
+
+type NFSeAddressRequest struct {
+	Street     string `json:"street" binding:"required"`
+	Number     string `json:"number" binding:"required"`
+	Complement string `json:"complement"`
+	District   string `json:"district" binding:"required"`
+	CityCode   string `json:"city_code" binding:"required"`
+	State      string `json:"state" binding:"required"`
+	PostalCode string `json:"postal_code" binding:"required"`
+}
+
+// ToEntity converts the request into the domain tomador.
+func (r NFSeTomadorRequest) ToEntity() entities.NFSeTomador {
+	t := entities.NFSeTomador{Document: r.Document, Name: r.Name, Email: r.Email}
+	if r.Address != nil {
+		a := entities.NFSeAddress(*r.Address)
+		t.Address = &a
+	}
+	return t
+}
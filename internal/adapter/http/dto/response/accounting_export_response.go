@@ -0,0 +1,31 @@
+package response
+
+import (
+	"mecanica_xpto/internal/domain/entities"
+	"time"
+)
+
+// AccountingExportResponse is an export run; the file is served by /file.
+type AccountingExportResponse struct {
+	ID                 string    `json:"id"`
+	Format             string    `json:"format"`
+	From               time.Time `json:"from"`
+	To                 time.Time `json:"to"`
+	EntryCount         int       `json:"entry_count"`
+	BlockedByPaymentID string    `json:"blocked_by_payment_id,omitempty"`
+	Actor              string    `json:"actor,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+func FromAccountingExport(run entities.AccountingExport) AccountingExportResponse {
+	return AccountingExportResponse{
+		ID:                 run.ID,
+		Format:             string(run.Format),
+		From:               run.From,
+		To:                 run.To,
+		EntryCount:         run.EntryCount,
+		BlockedByPaymentID: run.BlockedByPaymentID,
+		Actor:              run.Actor,
+		CreatedAt:          run.CreatedAt,
+	}
+}
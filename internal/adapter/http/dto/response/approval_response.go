@@ -0,0 +1,111 @@
+package response
+
+import (
+	"mecanica_xpto/internal/domain/entities"
+	"time"
+)
+
+// ApprovalLinkResponse is an approval link just issued. The token (and the URL carrying
+// it) is only returned here.
+type ApprovalLinkResponse struct {
+	ID         string     `json:"id"`
+	EstimateID string     `json:"estimate_id"`
+	OSID       string     `json:"os_id"`
+	Channel    string     `json:"channel"`
+	Recipient  string     `json:"recipient"`
+	CreatedBy  string     `json:"created_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	Token      string     `json:"token"`
+	URL        string     `json:"url"`
+	UsedAt     *time.Time `json:"used_at,omitempty"`
+}
+
+func FromApprovalLink(l entities.ApprovalLink, token, url string) ApprovalLinkResponse {
+	return ApprovalLinkResponse{
+		ID:         l.ID,
+		EstimateID: l.EstimateID,
+		OSID:       l.OSID,
+		Channel:    string(l.Channel),
+		Recipient:  l.MaskedRecipient(),
+		CreatedBy:  l.CreatedBy,
+		CreatedAt:  l.CreatedAt,
+		ExpiresAt:  l.ExpiresAt,
+		Token:      token,
+		URL:        url,
+		UsedAt:     l.UsedAt,
+	}
+}
+
+// ApprovalLinkStatusResponse is what the customer sees of the link: where the OTP goes
+// (masked) and whether it was already used.
+type ApprovalLinkStatusResponse struct {
+	Channel      string     `json:"channel"`
+	Recipient    string     `json:"recipient"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	OTPExpiresAt *time.Time `json:"otp_expires_at,omitempty"`
+	UsedAt       *time.Time `json:"used_at,omitempty"`
+	Decision     string     `json:"decision,omitempty"`
+}
+
+func FromApprovalLinkStatus(l entities.ApprovalLink) ApprovalLinkStatusResponse {
+	return ApprovalLinkStatusResponse{
+		Channel:      string(l.Channel),
+		Recipient:    l.MaskedRecipient(),
+		ExpiresAt:    l.ExpiresAt,
+		OTPExpiresAt: l.OTPExpiresAt,
+		UsedAt:       l.UsedAt,
+		Decision:     string(l.Decision),
+	}
+}
+
+// CustomerEstimateResponse is the estimate as shown to the customer: prices and items,
+// without internal data (customer, catalog deviations, history).
+type CustomerEstimateResponse struct {
+	EstimateID        string                      `json:"estimate_id"`
+	OSID              string                      `json:"os_id"`
+	Status            string                      `json:"status"`
+	Price             float64                     `json:"price"`
+	Subtotal          float64                     `json:"subtotal"`
+	Discounts         []entities.EstimateDiscount `json:"discounts"`
+	DiscountTotal     float64                     `json:"discount_total"`
+	Taxes             []entities.TaxAmount        `json:"taxes"`
+	TaxTotal          float64                     `json:"tax_total"`
+	Items             []entities.EstimateItem     `json:"items"`
+	ExpiresAt         *time.Time                  `json:"expires_at,omitempty"`
+	ApprovedAt        *time.Time                  `json:"approved_at,omitempty"`
+	Reason            *entities.StatusReason      `json:"reason,omitempty"`
+	PartiallyApproved bool                        `json:"partially_approved"`
+	DeclinedItems     []entities.EstimateItem     `json:"declined_items"`
+}
+
+func FromCustomerEstimate(e entities.Estimate) CustomerEstimateResponse {
+	full := FromEstimate(e)
+	return CustomerEstimateResponse{
+		EstimateID:        full.EstimateID,
+		OSID:              full.OSID,
+		Status:            full.Status,
+		Price:             full.Price,
+		Subtotal:          full.Subtotal,
+		Discounts:         full.Discounts,
+		DiscountTotal:     full.DiscountTotal,
+		Taxes:             full.Taxes,
+		TaxTotal:          full.TaxTotal,
+		Items:             full.Items,
+		ExpiresAt:         full.ExpiresAt,
+		ApprovedAt:        full.ApprovedAt,
+		Reason:            full.Reason,
+		PartiallyApproved: full.PartiallyApproved,
+		DeclinedItems:     full.DeclinedItems,
+	}
+}
+
+// ApprovalViewResponse is the page of an approval link.
+type ApprovalViewResponse struct {
+	Link     ApprovalLinkStatusResponse `json:"link"`
+	Estimate CustomerEstimateResponse   `json:"estimate"`
+}
+
+func FromApprovalView(link entities.ApprovalLink, e entities.Estimate) ApprovalViewResponse {
+	return ApprovalViewResponse{Link: FromApprovalLinkStatus(link), Estimate: FromCustomerEstimate(e)}
+}
@@ -13,11 +13,65 @@ type BillingPaymentResponse struct {
 	Date        time.Time `json:"date"`
 	Status      string    `json:"status"`
 
+	Details *PaymentDetailsResponse `json:"details,omitempty"`
+
 	MPPayloadRaw string                 `json:"mp_payload_raw,omitempty"`
 	MPPayload    map[string]interface{} `json:"mp_payload,omitempty"`
 }
 
+type PaymentFeeResponse struct {
+	Type   string  `json:"type"`
+	Payer  string  `json:"payer"`
+	Amount float64 `json:"amount"`
+}
+
+type PaymentDetailsResponse struct {
+	Amount            float64              `json:"amount"`
+	NetReceivedAmount float64              `json:"net_received_amount"`
+	TotalFees         float64              `json:"total_fees"`
+	Fees              []PaymentFeeResponse `json:"fees,omitempty"`
+	PaymentMethodID   string               `json:"payment_method_id,omitempty"`
+	PaymentTypeID     string               `json:"payment_type_id,omitempty"`
+	Installments      int                  `json:"installments,omitempty"`
+	CardLastFour      string               `json:"card_last_four,omitempty"`
+	CardBrand         string               `json:"card_brand,omitempty"`
+	PayerEmail        string               `json:"payer_email,omitempty"`
+	PayerDocType      string               `json:"payer_doc_type,omitempty"`
+	PayerDocNumber    string               `json:"payer_doc_number,omitempty"`
+	ApprovedAt        *time.Time           `json:"approved_at,omitempty"`
+}
+
+func FromPaymentDetails(d entities.PaymentDetails) *PaymentDetailsResponse {
+	if d.IsZero() {
+		return nil
+	}
+	res := &PaymentDetailsResponse{
+		Amount:            d.Amount,
+		NetReceivedAmount: d.NetReceivedAmount,
+		TotalFees:         d.TotalFees(),
+		PaymentMethodID:   d.PaymentMethodID,
+		PaymentTypeID:     d.PaymentTypeID,
+		Installments:      d.Installments,
+		CardLastFour:      d.CardLastFour,
+		CardBrand:         d.CardBrand,
+		PayerEmail:        d.PayerEmail,
+		PayerDocType:      d.PayerDocType,
+		PayerDocNumber:    d.PayerDocNumber,
+		ApprovedAt:        d.ApprovedAt,
+	}
+	for _, f := range d.Fees {
+		res.Fees = append(res.Fees, PaymentFeeResponse{Type: f.Type, Payer: f.Payer, Amount: f.Amount})
+	}
+	return res
+}
+
+// FromBillingPayment builds the public view of a payment: payer personal data is redacted.
 func FromBillingPayment(p entities.BillingPayment) BillingPaymentResponse {
+	return FromRevealedBillingPayment(p.Redacted())
+}
+
+// FromRevealedBillingPayment builds the response without redaction (privileged endpoints only).
+func FromRevealedBillingPayment(p entities.BillingPayment) BillingPaymentResponse {
 	return BillingPaymentResponse{
 		PaymentID:    p.ID,
 		ID:           p.ID,
@@ -25,7 +79,26 @@ func FromBillingPayment(p entities.BillingPayment) BillingPaymentResponse {
 		PaymentDate:  p.Date,
 		Date:         p.Date,
 		Status:       string(p.Status),
+		Details:      FromPaymentDetails(p.Details),
 		MPPayloadRaw: string(p.MPPayloadRaw),
 		MPPayload:    p.MPPayload,
 	}
 }
+
+// BillingPaymentListResponse is a page of payments. NextCursor is empty on the last page.
+type BillingPaymentListResponse struct {
+	Items      []BillingPaymentResponse `json:"items"`
+	NextCursor string                   `json:"next_cursor,omitempty"`
+}
+
+// FromBillingPaymentPage builds a list response with payer personal data redacted.
+func FromBillingPaymentPage(page entities.Page[entities.BillingPayment]) BillingPaymentListResponse {
+	res := BillingPaymentListResponse{
+		Items:      make([]BillingPaymentResponse, 0, len(page.Items)),
+		NextCursor: page.NextCursor,
+	}
+	for _, p := range page.Items {
+		res.Items = append(res.Items, FromBillingPayment(p))
+	}
+	return res
+}
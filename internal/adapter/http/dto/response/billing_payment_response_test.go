@@ -2,6 +2,7 @@ package response
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
@@ -39,3 +40,68 @@ func TestFromBillingPayment(t *testing.T) {
 		t.Fatalf("unexpected parsed payload: %+v", res.MPPayload)
 	}
 }
+
+func TestFromBillingPayment_Details(t *testing.T) {
+	approvedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	p := entities.BillingPayment{
+		ID: "pay-1",
+		Details: entities.PaymentDetails{
+			Amount:            100,
+			NetReceivedAmount: 95.01,
+			Fees:              []entities.PaymentFee{{Type: "mercadopago_fee", Payer: "collector", Amount: 4.99}},
+			PaymentMethodID:   "visa",
+			PaymentTypeID:     "credit_card",
+			Installments:      2,
+			CardLastFour:      "1234",
+			CardBrand:         "visa",
+			ApprovedAt:        &approvedAt,
+		},
+	}
+
+	res := FromBillingPayment(p)
+	if res.Details == nil {
+		t.Fatalf("expected details")
+	}
+	if res.Details.TotalFees != 4.99 || len(res.Details.Fees) != 1 || res.Details.CardBrand != "visa" {
+		t.Fatalf("unexpected details: %+v", res.Details)
+	}
+	if !res.Details.ApprovedAt.Equal(approvedAt) {
+		t.Fatalf("unexpected approved_at: %v", res.Details.ApprovedAt)
+	}
+
+	if FromBillingPayment(entities.BillingPayment{ID: "pay-2"}).Details != nil {
+		t.Fatalf("expected nil details for payments without extracted data")
+	}
+}
+
+func TestFromBillingPayment_RedactsPersonalData(t *testing.T) {
+	raw := json.RawMessage(`{"payer":{"email":"ana@test.com","identification":{"type":"CPF","number":"12345678909"}},"transaction_amount":10}`)
+	var parsed map[string]interface{}
+	_ = json.Unmarshal(raw, &parsed)
+	p := entities.BillingPayment{
+		ID:           "pay-1",
+		MPPayloadRaw: raw,
+		MPPayload:    parsed,
+		Details:      entities.PaymentDetails{Amount: 10, PayerEmail: "enc:v1:abc", PayerDocNumber: "12345678909"},
+	}
+
+	res := FromBillingPayment(p)
+	if strings.Contains(res.MPPayloadRaw, "ana@test.com") || strings.Contains(res.MPPayloadRaw, "12345678909") {
+		t.Fatalf("raw payload not redacted: %s", res.MPPayloadRaw)
+	}
+	payer := res.MPPayload["payer"].(map[string]interface{})
+	if payer["email"] != "a***@test.com" {
+		t.Fatalf("unexpected masked email: %v", payer["email"])
+	}
+	if res.Details.PayerEmail != "***" || res.Details.PayerDocNumber != "***" {
+		t.Fatalf("details not redacted: %+v", res.Details)
+	}
+	if parsed["payer"].(map[string]interface{})["email"] != "ana@test.com" {
+		t.Fatalf("entity payload must not be modified")
+	}
+
+	revealed := FromRevealedBillingPayment(p)
+	if revealed.Details.PayerDocNumber != "12345678909" {
+		t.Fatalf("revealed response must not be redacted: %+v", revealed.Details)
+	}
+}
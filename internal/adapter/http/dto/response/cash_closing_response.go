@@ -0,0 +1,96 @@
+package response
+
+import (
+	"math"
+	"mecanica_xpto/internal/domain/entities"
+	"time"
+)
+
+// CashClosingTotalsResponse has amounts rounded to cents.
+type CashClosingTotalsResponse struct {
+	Count int     `json:"count"`
+	Gross float64 `json:"gross"`
+	Fees  float64 `json:"fees"`
+	Net   float64 `json:"net"`
+}
+
+type CashClosingStatusResponse struct {
+	Status string `json:"status"`
+	CashClosingTotalsResponse
+}
+
+type CashClosingMethodResponse struct {
+	Status          string `json:"status"`
+	Provider        string `json:"provider"`
+	PaymentMethodID string `json:"payment_method_id"`
+	PaymentTypeID   string `json:"payment_type_id"`
+	CashClosingTotalsResponse
+}
+
+type CashClosingEstimateResponse struct {
+	Status     string   `json:"status"`
+	EstimateID string   `json:"estimate_id"`
+	PaymentIDs []string `json:"payment_ids"`
+	CashClosingTotalsResponse
+}
+
+type CashClosingResponse struct {
+	BusinessDay string                        `json:"business_day"`
+	TimeZone    string                        `json:"time_zone"`
+	From        time.Time                     `json:"from"`
+	To          time.Time                     `json:"to"`
+	ByStatus    []CashClosingStatusResponse   `json:"by_status"`
+	ByMethod    []CashClosingMethodResponse   `json:"by_method"`
+	ByEstimate  []CashClosingEstimateResponse `json:"by_estimate"`
+	GeneratedAt time.Time                     `json:"generated_at"`
+}
+
+func FromCashClosingReport(r entities.CashClosingReport) CashClosingResponse {
+	res := CashClosingResponse{
+		BusinessDay: r.BusinessDay,
+		TimeZone:    r.TimeZone,
+		From:        r.From,
+		To:          r.To,
+		ByStatus:    make([]CashClosingStatusResponse, 0, len(r.ByStatus)),
+		ByMethod:    make([]CashClosingMethodResponse, 0, len(r.ByMethod)),
+		ByEstimate:  make([]CashClosingEstimateResponse, 0, len(r.ByEstimate)),
+		GeneratedAt: r.GeneratedAt,
+	}
+	for _, l := range r.ByStatus {
+		res.ByStatus = append(res.ByStatus, CashClosingStatusResponse{
+			Status:                    string(l.Status),
+			CashClosingTotalsResponse: fromCashClosingTotals(l.CashClosingTotals),
+		})
+	}
+	for _, l := range r.ByMethod {
+		res.ByMethod = append(res.ByMethod, CashClosingMethodResponse{
+			Status:                    string(l.Status),
+			Provider:                  l.Provider,
+			PaymentMethodID:           l.PaymentMethodID,
+			PaymentTypeID:             l.PaymentTypeID,
+			CashClosingTotalsResponse: fromCashClosingTotals(l.CashClosingTotals),
+		})
+	}
+	for _, l := range r.ByEstimate {
+		res.ByEstimate = append(res.ByEstimate, CashClosingEstimateResponse{
+			Status:                    string(l.Status),
+			EstimateID:                l.EstimateID,
+			PaymentIDs:                l.PaymentIDs,
+			CashClosingTotalsResponse: fromCashClosingTotals(l.CashClosingTotals),
+		})
+	}
+	return res
+}
+
+func fromCashClosingTotals(t entities.CashClosingTotals) CashClosingTotalsResponse {
+	return CashClosingTotalsResponse{
+		Count: t.Count,
+		Gross: roundCents(t.Gross),
+		Fees:  roundCents(t.Fees),
+		Net:   roundCents(t.Net),
+	}
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,41 @@
+package response
+
+import (
+	"mecanica_xpto/internal/domain/entities"
+	"time"
+)
+
+type CatalogItemResponse struct {
+	Kind      string    `json:"kind"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Price     float64   `json:"price"`
+	Version   int64     `json:"version"`
+	UpdatedBy string    `json:"updated_by,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CatalogResponse struct {
+	Version int64                 `json:"version"`
+	Items   []CatalogItemResponse `json:"items"`
+}
+
+func FromCatalogItem(i entities.CatalogItem) CatalogItemResponse {
+	return CatalogItemResponse{
+		Kind:      string(i.Kind),
+		Code:      i.Code,
+		Name:      i.Name,
+		Price:     i.Price,
+		Version:   i.Version,
+		UpdatedBy: i.UpdatedBy,
+		UpdatedAt: i.UpdatedAt,
+	}
+}
+
+func FromCatalog(version int64, items []entities.CatalogItem) CatalogResponse {
+	resp := CatalogResponse{Version: version, Items: make([]CatalogItemResponse, 0, len(items))}
+	for _, i := range items {
+		resp.Items = append(resp.Items, FromCatalogItem(i))
+	}
+	return resp
+}
@@ -0,0 +1,111 @@
+package response
+
+import (
+	"math"
+	"mecanica_xpto/internal/domain/entities"
+	"time"
+)
+
+// ConversionMetricsResponse has amounts rounded to cents, rates to 4 decimals and
+// durations to 2 decimals.
+type ConversionMetricsResponse struct {
+	PeriodStart               string  `json:"period_start"`
+	Estimates                 int     `json:"estimates"`
+	Approved                  int     `json:"approved"`
+	Rejected                  int     `json:"rejected"`
+	Canceled                  int     `json:"canceled"`
+	Expired                   int     `json:"expired"`
+	Paid                      int     `json:"paid"`
+	ApprovalRate              float64 `json:"approval_rate"`
+	AverageTicket             float64 `json:"average_ticket"`
+	AvgHoursToApproval        float64 `json:"avg_hours_to_approval"`
+	AvgHoursApprovalToPayment float64 `json:"avg_hours_approval_to_payment"`
+}
+
+type ConversionReportResponse struct {
+	From        string                      `json:"from"`
+	To          string                      `json:"to"`
+	TimeZone    string                      `json:"time_zone"`
+	GroupBy     string                      `json:"group_by"`
+	Periods     []ConversionMetricsResponse `json:"periods"`
+	Total       ConversionMetricsResponse   `json:"total"`
+	GeneratedAt time.Time                   `json:"generated_at"`
+}
+
+func FromConversionReport(r entities.ConversionReport) ConversionReportResponse {
+	res := ConversionReportResponse{
+		From:        r.From,
+		To:          r.To,
+		TimeZone:    r.TimeZone,
+		GroupBy:     string(r.Granularity),
+		Periods:     make([]ConversionMetricsResponse, 0, len(r.Periods)),
+		Total:       fromConversionMetrics(r.Total),
+		GeneratedAt: r.GeneratedAt,
+	}
+	res.Total.PeriodStart = r.From
+	for _, p := range r.Periods {
+		res.Periods = append(res.Periods, fromConversionMetrics(p))
+	}
+	return res
+}
+
+func fromConversionMetrics(m entities.ConversionMetrics) ConversionMetricsResponse {
+	return ConversionMetricsResponse{
+		PeriodStart:               m.PeriodStart.Format(time.DateOnly),
+		Estimates:                 m.Estimates,
+		Approved:                  m.Approved,
+		Rejected:                  m.Rejected,
+		Canceled:                  m.Canceled,
+		Expired:                   m.Expired,
+		Paid:                      m.Paid,
+		ApprovalRate:              math.Round(m.ApprovalRate*10000) / 10000,
+		AverageTicket:             roundCents(m.AverageTicket),
+		AvgHoursToApproval:        roundCents(m.AvgHoursToApproval),
+		AvgHoursApprovalToPayment: roundCents(m.AvgHoursApprovalToPayment),
+	}
+}
+
+// ReasonMetricsResponse has the share rounded to 4 decimals and the value to cents.
+type ReasonMetricsResponse struct {
+	Status string  `json:"status"`
+	Code   string  `json:"code"`
+	Label  string  `json:"label"`
+	Count  int     `json:"count"`
+	Share  float64 `json:"share"`
+	Value  float64 `json:"value"`
+}
+
+type RejectionReasonReportResponse struct {
+	From        string                  `json:"from"`
+	To          string                  `json:"to"`
+	TimeZone    string                  `json:"time_zone"`
+	Estimates   int                     `json:"estimates"`
+	Rejected    int                     `json:"rejected"`
+	Canceled    int                     `json:"canceled"`
+	Reasons     []ReasonMetricsResponse `json:"reasons"`
+	GeneratedAt time.Time               `json:"generated_at"`
+}
+
+func FromRejectionReasonReport(r entities.RejectionReasonReport) RejectionReasonReportResponse {
+	res := RejectionReasonReportResponse{
+		From:        r.From,
+		To:          r.To,
+		TimeZone:    r.TimeZone,
+		Estimates:   r.Estimates,
+		Rejected:    r.Rejected,
+		Canceled:    r.Canceled,
+		Reasons:     make([]ReasonMetricsResponse, 0, len(r.Reasons)),
+		GeneratedAt: r.GeneratedAt,
+	}
+	for _, m := range r.Reasons {
+		res.Reasons = append(res.Reasons, ReasonMetricsResponse{
+			Status: string(m.Status),
+			Code:   m.Code,
+			Label:  m.Label,
+			Count:  m.Count,
+			Share:  math.Round(m.Share*10000) / 10000,
+			Value:  roundCents(m.Value),
+		})
+	}
+	return res
+}
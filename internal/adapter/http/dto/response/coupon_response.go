@@ -0,0 +1,42 @@
+package response
+
+import (
+	"mecanica_xpto/internal/domain/entities"
+	"time"
+)
+
+type CouponResponse struct {
+	Code           string     `json:"code"`
+	Description    string     `json:"description"`
+	Type           string     `json:"type"`
+	Value          float64    `json:"value"`
+	Scope          string     `json:"scope"`
+	ItemKind       string     `json:"item_kind,omitempty"`
+	ValidFrom      *time.Time `json:"valid_from,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	MaxRedemptions int        `json:"max_redemptions"`
+	MaxPerCustomer int        `json:"max_per_customer"`
+	Redemptions    int        `json:"redemptions"`
+	Active         bool       `json:"active"`
+	CreatedBy      string     `json:"created_by,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+func FromCoupon(c entities.Coupon) CouponResponse {
+	return CouponResponse{
+		Code:           c.Code,
+		Description:    c.Description,
+		Type:           string(c.Rule.Type),
+		Value:          c.Rule.Value,
+		Scope:          string(c.Rule.Scope),
+		ItemKind:       string(c.Rule.ItemKind),
+		ValidFrom:      c.ValidFrom,
+		ExpiresAt:      c.ExpiresAt,
+		MaxRedemptions: c.MaxRedemptions,
+		MaxPerCustomer: c.MaxPerCustomer,
+		Redemptions:    c.Redemptions,
+		Active:         c.Active,
+		CreatedBy:      c.CreatedBy,
+		CreatedAt:      c.CreatedAt,
+	}
+}
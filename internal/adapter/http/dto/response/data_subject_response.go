@@ -0,0 +1,73 @@
+package response
+
+import (
+	"mecanica_xpto/internal/domain/entities"
+	"time"
+)
+
+// DataSubjectExportResponse is the LGPD access report: payer data is returned unredacted.
+type DataSubjectExportResponse struct {
+	GeneratedAt   time.Time                 `json:"generated_at"`
+	Payments      []BillingPaymentResponse  `json:"payments"`
+	Estimates     []EstimateResponse        `json:"estimates"`
+	NFSeDocuments []DataSubjectNFSeResponse `json:"nfse_documents"`
+	ApprovalLinks []entities.ApprovalLink   `json:"approval_links"`
+}
+
+// DataSubjectNFSeResponse is an NFS-e document with the tomador data as issued.
+type DataSubjectNFSeResponse struct {
+	NFSeResponse
+	Tomador entities.NFSeTomador `json:"tomador"`
+}
+
+type DataSubjectAnonymizeResponse struct {
+	PaymentIDs        []string  `json:"payment_ids"`
+	ApprovalLinkIDs   []string  `json:"approval_link_ids"`
+	RetainedNFSeIDs   []string  `json:"retained_nfse_ids"`
+	Anonymized        int       `json:"anonymized"`
+	AlreadyAnonymized int       `json:"already_anonymized"`
+	AnonymizedAt      time.Time `json:"anonymized_at"`
+}
+
+func FromDataSubjectExport(generatedAt time.Time, payments []entities.BillingPayment, estimates []entities.Estimate, docs []entities.NFSeDocument, links []entities.ApprovalLink) DataSubjectExportResponse {
+	out := DataSubjectExportResponse{
+		GeneratedAt:   generatedAt,
+		Payments:      make([]BillingPaymentResponse, 0, len(payments)),
+		Estimates:     make([]EstimateResponse, 0, len(estimates)),
+		NFSeDocuments: make([]DataSubjectNFSeResponse, 0, len(docs)),
+		ApprovalLinks: links,
+	}
+	if out.ApprovalLinks == nil {
+		out.ApprovalLinks = []entities.ApprovalLink{}
+	}
+	for _, p := range payments {
+		out.Payments = append(out.Payments, FromRevealedBillingPayment(p))
+	}
+	for _, est := range estimates {
+		out.Estimates = append(out.Estimates, FromEstimate(est))
+	}
+	for _, doc := range docs {
+		out.NFSeDocuments = append(out.NFSeDocuments, DataSubjectNFSeResponse{NFSeResponse: FromNFSeDocument(doc), Tomador: doc.RPS.Tomador})
+	}
+	return out
+}
+
+func FromDataSubjectAnonymization(paymentIDs, linkIDs, nfseIDs []string, anonymized, alreadyAnonymized int, at time.Time) DataSubjectAnonymizeResponse {
+	if paymentIDs == nil {
+		paymentIDs = []string{}
+	}
+	if linkIDs == nil {
+		linkIDs = []string{}
+	}
+	if nfseIDs == nil {
+		nfseIDs = []string{}
+	}
+	return DataSubjectAnonymizeResponse{
+		PaymentIDs:        paymentIDs,
+		ApprovalLinkIDs:   linkIDs,
+		RetainedNFSeIDs:   nfseIDs,
+		Anonymized:        anonymized,
+		AlreadyAnonymized: alreadyAnonymized,
+		AnonymizedAt:      at,
+	}
+}
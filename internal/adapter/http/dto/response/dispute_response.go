@@ -0,0 +1,62 @@
+package response
+
+import (
+	"mecanica_xpto/internal/domain/entities"
+	"time"
+)
+
+type DisputeEvidenceResponse struct {
+	Reference   string    `json:"reference"`
+	Description string    `json:"description,omitempty"`
+	AddedBy     string    `json:"added_by"`
+	AddedAt     time.Time `json:"added_at"`
+}
+
+type DisputeResponse struct {
+	DisputeID        string                    `json:"dispute_id"`
+	PaymentID        string                    `json:"payment_id"`
+	EstimateID       string                    `json:"estimate_id"`
+	Status           string                    `json:"status"`
+	Amount           float64                   `json:"amount"`
+	Reason           string                    `json:"reason,omitempty"`
+	EvidenceDeadline *time.Time                `json:"evidence_deadline,omitempty"`
+	Overdue          bool                      `json:"overdue"`
+	Evidence         []DisputeEvidenceResponse `json:"evidence"`
+	CreatedAt        time.Time                 `json:"created_at"`
+	UpdatedAt        time.Time                 `json:"updated_at"`
+	ResolvedAt       *time.Time                `json:"resolved_at,omitempty"`
+}
+
+func FromDispute(d entities.Dispute, now time.Time) DisputeResponse {
+	out := DisputeResponse{
+		DisputeID:        d.ID,
+		PaymentID:        d.PaymentID,
+		EstimateID:       d.EstimateID,
+		Status:           string(d.Status),
+		Amount:           d.Amount,
+		Reason:           d.Reason,
+		EvidenceDeadline: d.EvidenceDeadline,
+		Overdue:          d.IsOverdue(now),
+		Evidence:         make([]DisputeEvidenceResponse, 0, len(d.Evidence)),
+		CreatedAt:        d.CreatedAt,
+		UpdatedAt:        d.UpdatedAt,
+		ResolvedAt:       d.ResolvedAt,
+	}
+	for _, e := range d.Evidence {
+		out.Evidence = append(out.Evidence, DisputeEvidenceResponse{
+			Reference:   e.Reference,
+			Description: e.Description,
+			AddedBy:     e.AddedBy,
+			AddedAt:     e.AddedAt,
+		})
+	}
+	return out
+}
+
+func FromDisputes(ds []entities.Dispute, now time.Time) []DisputeResponse {
+	out := make([]DisputeResponse, 0, len(ds))
+	for _, d := range ds {
+		out = append(out, FromDispute(d, now))
+	}
+	return out
+}
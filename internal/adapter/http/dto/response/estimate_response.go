@@ -6,25 +6,112 @@ import (
 )
 
 type EstimateResponse struct {
-	EstimateID     string    `json:"estimate_id"`
-	ID             string    `json:"id"`
-	ServiceOrderID string    `json:"service_order_id"`
-	OSID           string    `json:"os_id"`
-	Price          float64   `json:"price"`
-	Status         string    `json:"status"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	EstimateID        string                          `json:"estimate_id"`
+	ID                string                          `json:"id"`
+	ServiceOrderID    string                          `json:"service_order_id"`
+	OSID              string                          `json:"os_id"`
+	Price             float64                         `json:"price"`
+	BalanceDue        float64                         `json:"balance_due"`
+	Status            string                          `json:"status"`
+	CreatedAt         time.Time                       `json:"created_at"`
+	UpdatedAt         time.Time                       `json:"updated_at"`
+	ApprovedAt        *time.Time                      `json:"approved_at,omitempty"`
+	ExpiresAt         *time.Time                      `json:"expires_at,omitempty"`
+	RenewedAt         *time.Time                      `json:"renewed_at,omitempty"`
+	Reason            *entities.StatusReason          `json:"reason,omitempty"`
+	DecidedBy         string                          `json:"decided_by,omitempty"`
+	InternalApproval  *entities.InternalApproval      `json:"internal_approval,omitempty"`
+	Municipality      string                          `json:"municipality,omitempty"`
+	CustomerID        string                          `json:"customer_id,omitempty"`
+	Subtotal          float64                         `json:"subtotal"`
+	Discounts         []entities.EstimateDiscount     `json:"discounts"`
+	DiscountTotal     float64                         `json:"discount_total"`
+	PricingVersion    string                          `json:"pricing_version,omitempty"`
+	Adjustments       []entities.PricingAdjustment    `json:"adjustments"`
+	AdjustmentTotal   float64                         `json:"adjustment_total"`
+	CatalogVersion    int64                           `json:"catalog_version,omitempty"`
+	CatalogDeviations []entities.CatalogDeviation     `json:"catalog_deviations"`
+	Items             []entities.EstimateItem         `json:"items"`
+	Taxes             []entities.TaxAmount            `json:"taxes"`
+	TaxTotal          float64                         `json:"tax_total"`
+	History           []entities.EstimateStatusChange `json:"history"`
+	// Items are the approved items once the estimate is partially approved.
+	PartiallyApproved bool                    `json:"partially_approved"`
+	DeclinedItems     []entities.EstimateItem `json:"declined_items"`
 }
 
 func FromEstimate(e entities.Estimate) EstimateResponse {
-	return EstimateResponse{
-		EstimateID:     e.ID,
-		ID:             e.ID,
-		ServiceOrderID: e.OSID,
-		OSID:           e.OSID,
-		Price:          e.Price,
-		Status:         string(e.Status),
-		CreatedAt:      e.CreatedAt,
-		UpdatedAt:      e.UpdatedAt,
+	resp := EstimateResponse{
+		EstimateID:        e.ID,
+		ID:                e.ID,
+		ServiceOrderID:    e.OSID,
+		OSID:              e.OSID,
+		Price:             e.Price,
+		BalanceDue:        e.BalanceDue,
+		Status:            string(e.Status),
+		CreatedAt:         e.CreatedAt,
+		UpdatedAt:         e.UpdatedAt,
+		ApprovedAt:        e.ApprovedAt,
+		ExpiresAt:         e.ExpiresAt,
+		RenewedAt:         e.RenewedAt,
+		Reason:            e.Reason,
+		DecidedBy:         e.DecidedBy,
+		InternalApproval:  e.InternalApproval,
+		Municipality:      e.Municipality,
+		CustomerID:        e.CustomerID,
+		Subtotal:          e.GrossTotal(),
+		Discounts:         e.Discounts,
+		DiscountTotal:     e.DiscountTotal,
+		PricingVersion:    e.PricingVersion,
+		Adjustments:       e.Adjustments,
+		AdjustmentTotal:   e.AdjustmentTotal,
+		CatalogVersion:    e.CatalogVersion,
+		CatalogDeviations: e.CatalogDeviations,
+		Items:             e.Items,
+		Taxes:             e.Taxes,
+		TaxTotal:          e.TaxTotal,
+		History:           e.History,
+		PartiallyApproved: e.IsPartiallyApproved(),
+		DeclinedItems:     e.DeclinedItems,
 	}
+	if resp.Items == nil {
+		resp.Items = []entities.EstimateItem{}
+	}
+	if resp.Discounts == nil {
+		resp.Discounts = []entities.EstimateDiscount{}
+	}
+	if resp.Adjustments == nil {
+		resp.Adjustments = []entities.PricingAdjustment{}
+	}
+	if resp.CatalogDeviations == nil {
+		resp.CatalogDeviations = []entities.CatalogDeviation{}
+	}
+	if resp.Taxes == nil {
+		resp.Taxes = []entities.TaxAmount{}
+	}
+	if resp.History == nil {
+		resp.History = []entities.EstimateStatusChange{}
+	}
+	if resp.DeclinedItems == nil {
+		resp.DeclinedItems = []entities.EstimateItem{}
+	}
+	return resp
+}
+
+func FromEstimates(es []entities.Estimate) []EstimateResponse {
+	out := make([]EstimateResponse, 0, len(es))
+	for _, e := range es {
+		out = append(out, FromEstimate(e))
+	}
+	return out
+}
+
+// ReasonCatalogResponse lists the reason codes accepted by the reject and cancel endpoints.
+type ReasonCatalogResponse struct {
+	Rejection    []entities.ReasonCode `json:"rejeicao"`
+	Cancellation []entities.ReasonCode `json:"cancelamento"`
+}
+
+func FromReasonCatalog(c entities.ReasonCatalog) ReasonCatalogResponse {
+	return ReasonCatalogResponse{Rejection: c.Rejection, Cancellation: c.Cancellation}
 }
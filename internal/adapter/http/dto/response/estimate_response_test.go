@@ -31,4 +31,8 @@ func TestFromEstimate(t *testing.T) {
 	if !res.CreatedAt.Equal(now) || !res.UpdatedAt.Equal(now) {
 		t.Fatalf("unexpected dates: %+v", res)
 	}
+	// Estimates priced before discounts existed report their price as the subtotal.
+	if res.Subtotal != 99.9 || res.Discounts == nil || res.DiscountTotal != 0 {
+		t.Fatalf("unexpected discount fields: %+v", res)
+	}
 }
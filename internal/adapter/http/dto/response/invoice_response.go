@@ -0,0 +1,58 @@
+package response
+
+import (
+	"mecanica_xpto/internal/domain/entities"
+	"time"
+)
+
+// InvoiceResponse adds the derived amounts (paid, credited, balance) to the invoice.
+
+type InvoiceResponse struct {
+	ID             string                    `json:"id"`
+	Number         int64                     `json:"number"`
+	EstimateID     string                    `json:"estimate_id"`
+	OSID           string                    `json:"os_id"`
+	Status         string                    `json:"status"`
+	Lines          []entities.InvoiceLine    `json:"lines"`
+	Taxes          []entities.InvoiceTax     `json:"taxes"`
+	Subtotal       float64                   `json:"subtotal"`
+	TaxTotal       float64                   `json:"tax_total"`
+	Total          float64                   `json:"total"`
+	AmountPaid     float64                   `json:"amount_paid"`
+	CreditedAmount float64                   `json:"credited_amount"`
+	Balance        float64                   `json:"balance"`
+	Paid           bool                      `json:"paid"`
+	Payments       []entities.InvoicePayment `json:"payments"`
+	CreditNotes    []entities.CreditNote     `json:"credit_notes"`
+	IssuedBy       string                    `json:"issued_by,omitempty"`
+	IssuedAt       time.Time                 `json:"issued_at"`
+	VoidedAt       *time.Time                `json:"voided_at,omitempty"`
+	VoidReason     string                    `json:"void_reason,omitempty"`
+	VoidedBy       string                    `json:"voided_by,omitempty"`
+}
+
+func FromInvoice(inv entities.Invoice) InvoiceResponse {
+	return InvoiceResponse{
+		ID:             inv.ID,
+		Number:         inv.Number,
+		EstimateID:     inv.EstimateID,
+		OSID:           inv.OSID,
+		Status:         string(inv.Status),
+		Lines:          inv.Lines,
+		Taxes:          inv.Taxes,
+		Subtotal:       inv.Subtotal,
+		TaxTotal:       inv.TaxTotal,
+		Total:          inv.Total,
+		AmountPaid:     inv.AmountPaid(),
+		CreditedAmount: inv.CreditedAmount(),
+		Balance:        inv.Balance(),
+		Paid:           inv.IsPaid(),
+		Payments:       inv.Payments,
+		CreditNotes:    inv.CreditNotes,
+		IssuedBy:       inv.IssuedBy,
+		IssuedAt:       inv.IssuedAt,
+		VoidedAt:       inv.VoidedAt,
+		VoidReason:     inv.VoidReason,
+		VoidedBy:       inv.VoidedBy,
+	}
+}
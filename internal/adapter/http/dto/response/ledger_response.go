@@ -0,0 +1,87 @@
+package response
+
+import (
+	"mecanica_xpto/internal/domain/entities"
+	"time"
+)
+
+// Ledger amounts are stored in cents and returned in reais.
+
+type LedgerBalanceResponse struct {
+	Account   string    `json:"account"`
+	Debits    float64   `json:"debits"`
+	Credits   float64   `json:"credits"`
+	Balance   float64   `json:"balance"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+type LedgerLineResponse struct {
+	Account string  `json:"account"`
+	Debit   float64 `json:"debit,omitempty"`
+	Credit  float64 `json:"credit,omitempty"`
+}
+
+type LedgerEntryResponse struct {
+	ID        string               `json:"id"`
+	Kind      string               `json:"kind"`
+	PaymentID string               `json:"payment_id"`
+	Lines     []LedgerLineResponse `json:"lines"`
+	CreatedAt time.Time            `json:"created_at"`
+}
+
+type LedgerAccountsResponse struct {
+	Accounts []LedgerBalanceResponse `json:"accounts"`
+}
+
+type EstimateLedgerResponse struct {
+	EstimateID string                  `json:"estimate_id"`
+	Balances   []LedgerBalanceResponse `json:"balances"`
+	Entries    []LedgerEntryResponse   `json:"entries"`
+}
+
+func fromCents(v int64) float64 {
+	return float64(v) / 100
+}
+
+func FromLedgerBalance(b entities.LedgerBalance) LedgerBalanceResponse {
+	return LedgerBalanceResponse{
+		Account:   string(b.Account),
+		Debits:    fromCents(b.Debits),
+		Credits:   fromCents(b.Credits),
+		Balance:   fromCents(b.Balance()),
+		UpdatedAt: b.UpdatedAt,
+	}
+}
+
+func FromLedgerBalances(balances []entities.LedgerBalance) LedgerAccountsResponse {
+	res := LedgerAccountsResponse{Accounts: make([]LedgerBalanceResponse, 0, len(balances))}
+	for _, b := range balances {
+		res.Accounts = append(res.Accounts, FromLedgerBalance(b))
+	}
+	return res
+}
+
+func FromEstimateLedger(l entities.EstimateLedger) EstimateLedgerResponse {
+	res := EstimateLedgerResponse{
+		EstimateID: l.EstimateID,
+		Balances:   make([]LedgerBalanceResponse, 0, len(l.Balances)),
+		Entries:    make([]LedgerEntryResponse, 0, len(l.Entries)),
+	}
+	for _, b := range l.Balances {
+		res.Balances = append(res.Balances, FromLedgerBalance(b))
+	}
+	for _, e := range l.Entries {
+		lines := make([]LedgerLineResponse, 0, len(e.Lines))
+		for _, line := range e.Lines {
+			lines = append(lines, LedgerLineResponse{Account: string(line.Account), Debit: fromCents(line.Debit), Credit: fromCents(line.Credit)})
+		}
+		res.Entries = append(res.Entries, LedgerEntryResponse{
+			ID:        e.ID,
+			Kind:      string(e.Kind),
+			PaymentID: e.PaymentID,
+			Lines:     lines,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+	return res
+}
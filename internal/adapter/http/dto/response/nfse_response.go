@@ -0,0 +1,44 @@
+package response
+
+import (
+	"mecanica_xpto/internal/domain/entities"
+	"time"
+)
+
+// NFSeResponse describes a signed NFS-e document; the XML itself is downloaded apart.
+
+type NFSeResponse struct {
+	ID                string     `json:"id"`
+	InvoiceID         string     `json:"invoice_id"`
+	EstimateID        string     `json:"estimate_id"`
+	RPSNumber         int64      `json:"rps_number"`
+	RPSSeries         string     `json:"rps_series"`
+	ServiceAmount     float64    `json:"service_amount"`
+	TomadorDocument   string     `json:"tomador_document"`
+	TomadorName       string     `json:"tomador_name"`
+	Status            string     `json:"status"`
+	Protocol          string     `json:"protocol,omitempty"`
+	TransmissionError string     `json:"transmission_error,omitempty"`
+	Attempts          int        `json:"attempts"`
+	CreatedAt         time.Time  `json:"created_at"`
+	TransmittedAt     *time.Time `json:"transmitted_at,omitempty"`
+}
+
+func FromNFSeDocument(doc entities.NFSeDocument) NFSeResponse {
+	return NFSeResponse{
+		ID:                doc.ID,
+		InvoiceID:         doc.InvoiceID,
+		EstimateID:        doc.EstimateID,
+		RPSNumber:         doc.RPS.Number,
+		RPSSeries:         doc.RPS.Series,
+		ServiceAmount:     doc.RPS.ServiceAmount,
+		TomadorDocument:   doc.RPS.Tomador.Document,
+		TomadorName:       doc.RPS.Tomador.Name,
+		Status:            string(doc.Status),
+		Protocol:          doc.Protocol,
+		TransmissionError: doc.TransmissionError,
+		Attempts:          doc.Attempts,
+		CreatedAt:         doc.CreatedAt,
+		TransmittedAt:     doc.TransmittedAt,
+	}
+}
@@ -0,0 +1,48 @@
+package response
+
+import (
+	"mecanica_xpto/internal/domain/entities"
+	"time"
+)
+
+type PaymentStatusEventResponse struct {
+	ID                   string    `json:"id"`
+	PaymentID            string    `json:"payment_id"`
+	Status               string    `json:"status"`
+	PreviousStatus       string    `json:"previous_status,omitempty"`
+	Source               string    `json:"source"`
+	Actor                string    `json:"actor,omitempty"`
+	ProviderStatus       string    `json:"provider_status,omitempty"`
+	ProviderStatusDetail string    `json:"provider_status_detail,omitempty"`
+	CreatedAt            time.Time `json:"created_at"`
+}
+
+type PaymentStatusHistoryResponse struct {
+	PaymentID string                       `json:"payment_id"`
+	Events    []PaymentStatusEventResponse `json:"events"`
+}
+
+func FromPaymentStatusEvent(e entities.PaymentStatusEvent) PaymentStatusEventResponse {
+	return PaymentStatusEventResponse{
+		ID:                   e.ID,
+		PaymentID:            e.PaymentID,
+		Status:               string(e.Status),
+		PreviousStatus:       string(e.PreviousStatus),
+		Source:               string(e.Source),
+		Actor:                e.Actor,
+		ProviderStatus:       e.ProviderStatus,
+		ProviderStatusDetail: e.ProviderStatusDetail,
+		CreatedAt:            e.CreatedAt,
+	}
+}
+
+func FromPaymentStatusHistory(paymentID string, events []entities.PaymentStatusEvent) PaymentStatusHistoryResponse {
+	out := PaymentStatusHistoryResponse{
+		PaymentID: paymentID,
+		Events:    make([]PaymentStatusEventResponse, 0, len(events)),
+	}
+	for _, e := range events {
+		out.Events = append(out.Events, FromPaymentStatusEvent(e))
+	}
+	return out
+}
@@ -0,0 +1,63 @@
+package response
+
+import (
+	"mecanica_xpto/internal/domain/entities"
+	"time"
+)
+
+type ReceivableItemResponse struct {
+	EstimateID      string    `json:"estimate_id"`
+	ServiceOrderID  string    `json:"service_order_id"`
+	OSID            string    `json:"os_id"`
+	ApprovedAt      time.Time `json:"approved_at"`
+	DaysOutstanding int       `json:"days_outstanding"`
+	Bucket          string    `json:"bucket"`
+	Price           float64   `json:"price"`
+	Paid            float64   `json:"paid"`
+	Outstanding     float64   `json:"outstanding"`
+	PaymentIDs      []string  `json:"payment_ids"`
+}
+
+type AgingBucketResponse struct {
+	Bucket      string  `json:"bucket"`
+	Count       int     `json:"count"`
+	Outstanding float64 `json:"outstanding"`
+}
+
+type ReceivablesAgingResponse struct {
+	AsOf             time.Time                `json:"as_of"`
+	Buckets          []AgingBucketResponse    `json:"buckets"`
+	Items            []ReceivableItemResponse `json:"items"`
+	TotalOutstanding float64                  `json:"total_outstanding"`
+}
+
+func FromReceivablesAgingReport(r entities.ReceivablesAgingReport) ReceivablesAgingResponse {
+	res := ReceivablesAgingResponse{
+		AsOf:             r.AsOf,
+		Buckets:          make([]AgingBucketResponse, 0, len(r.Buckets)),
+		Items:            make([]ReceivableItemResponse, 0, len(r.Items)),
+		TotalOutstanding: roundCents(r.TotalOutstanding),
+	}
+	for _, b := range r.Buckets {
+		res.Buckets = append(res.Buckets, AgingBucketResponse{
+			Bucket:      b.Bucket,
+			Count:       b.Count,
+			Outstanding: roundCents(b.Outstanding),
+		})
+	}
+	for _, it := range r.Items {
+		res.Items = append(res.Items, ReceivableItemResponse{
+			EstimateID:      it.EstimateID,
+			ServiceOrderID:  it.OSID,
+			OSID:            it.OSID,
+			ApprovedAt:      it.ApprovedAt,
+			DaysOutstanding: it.DaysOutstanding,
+			Bucket:          it.Bucket,
+			Price:           roundCents(it.Price),
+			Paid:            roundCents(it.Paid),
+			Outstanding:     roundCents(it.Outstanding),
+			PaymentIDs:      it.PaymentIDs,
+		})
+	}
+	return res
+}
@@ -0,0 +1,69 @@
+package response
+
+import (
+	"mecanica_xpto/internal/domain/entities"
+	"time"
+)
+
+type ReconciliationDiscrepancyResponse struct {
+	Kind              string  `json:"kind"`
+	Line              int     `json:"line,omitempty"`
+	SourceID          string  `json:"source_id,omitempty"`
+	ExternalReference string  `json:"external_reference,omitempty"`
+	LineType          string  `json:"line_type,omitempty"`
+	PaymentID         string  `json:"payment_id,omitempty"`
+	ReportAmount      float64 `json:"report_amount"`
+	PaymentAmount     float64 `json:"payment_amount"`
+	PaymentStatus     string  `json:"payment_status,omitempty"`
+	Detail            string  `json:"detail"`
+}
+
+// ReconciliationRunResponse is a stored reconciliation run. Summary counts discrepancies
+// by kind; the CSV report has the same rows as Discrepancies.
+type ReconciliationRunResponse struct {
+	ID            string                              `json:"id"`
+	FileName      string                              `json:"file_name"`
+	Actor         string                              `json:"actor"`
+	PeriodStart   *time.Time                          `json:"period_start,omitempty"`
+	PeriodEnd     *time.Time                          `json:"period_end,omitempty"`
+	TotalLines    int                                 `json:"total_lines"`
+	MatchedLines  int                                 `json:"matched_lines"`
+	IgnoredLines  int                                 `json:"ignored_lines"`
+	Summary       map[string]int                      `json:"summary"`
+	Discrepancies []ReconciliationDiscrepancyResponse `json:"discrepancies"`
+	CreatedAt     time.Time                           `json:"created_at"`
+}
+
+func FromReconciliationRun(run entities.ReconciliationRun) ReconciliationRunResponse {
+	res := ReconciliationRunResponse{
+		ID:            run.ID,
+		FileName:      run.FileName,
+		Actor:         run.Actor,
+		PeriodStart:   run.PeriodStart,
+		PeriodEnd:     run.PeriodEnd,
+		TotalLines:    run.TotalLines,
+		MatchedLines:  run.MatchedLines,
+		IgnoredLines:  run.IgnoredLines,
+		Summary:       map[string]int{},
+		Discrepancies: make([]ReconciliationDiscrepancyResponse, 0, len(run.Discrepancies)),
+		CreatedAt:     run.CreatedAt,
+	}
+	for kind, n := range run.CountByKind() {
+		res.Summary[string(kind)] = n
+	}
+	for _, d := range run.Discrepancies {
+		res.Discrepancies = append(res.Discrepancies, ReconciliationDiscrepancyResponse{
+			Kind:              string(d.Kind),
+			Line:              d.Line,
+			SourceID:          d.SourceID,
+			ExternalReference: d.ExternalReference,
+			LineType:          string(d.LineType),
+			PaymentID:         d.PaymentID,
+			ReportAmount:      d.ReportAmount,
+			PaymentAmount:     d.PaymentAmount,
+			PaymentStatus:     string(d.PaymentStatus),
+			Detail:            d.Detail,
+		})
+	}
+	return res
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	response "mecanica_xpto/internal/adapter/http/dto/response"
+	"mecanica_xpto/internal/adapter/http/middlewares"
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase"
+	"mecanica_xpto/pkg"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccountingExportHandler creates incremental accounting exports and serves their files.
+// Privileged: must be routed behind the admin middleware.
+
+type AccountingExportHandler struct {
+	usecase usecase.IAccountingExportUseCase
+}
+
+func NewAccountingExportHandler(uc usecase.IAccountingExportUseCase) *AccountingExportHandler {
+	return &AccountingExportHandler{usecase: uc}
+}
+
+// CreateAccountingExport exports the journal entries since the previous export, in
+// `?format=csv|ofx|fixed-width` (csv when omitted).
+func (h *AccountingExportHandler) CreateAccountingExport(c *gin.Context) {
+	format := c.DefaultQuery("format", string(entities.AccountingExportCSV))
+	actor := middlewares.AdminActor(c)
+
+	run, err := h.usecase.Export(c.Request.Context(), entities.AccountingExportFormat(format), actor)
+	if err != nil {
+		log.Printf("[payment][handler] accounting export failed format=%s actor=%s err=%v", format, actor, err)
+		appErr := mapAccountingExportError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.FromAccountingExport(run))
+}
+
+// GetAccountingExport returns a stored export run.
+func (h *AccountingExportHandler) GetAccountingExport(c *gin.Context) {
+	run, err := h.usecase.GetExport(c.Request.Context(), c.Param("export_id"))
+	if err != nil {
+		appErr := mapAccountingExportError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromAccountingExport(run))
+}
+
+// DownloadAccountingExport returns the export file as an attachment. Downloading again
+// yields the same entries.
+func (h *AccountingExportHandler) DownloadAccountingExport(c *gin.Context) {
+	_, file, err := h.usecase.RenderExport(c.Request.Context(), c.Param("export_id"))
+	if err != nil {
+		log.Printf("[payment][handler] accounting export render failed export_id=%s err=%v", c.Param("export_id"), err)
+		appErr := mapAccountingExportError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, file.Name))
+	c.Data(http.StatusOK, file.ContentType, file.Content)
+}
+
+func mapAccountingExportError(err error) *pkg.AppError {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidAccountingFormat):
+		return pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest).WithDetails("format")
+	case errors.Is(err, usecase.ErrInvalidAccountingExportID):
+		return pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
+	case errors.Is(err, usecase.ErrAccountingExportNotFound):
+		return pkg.NewDomainErrorSimple("ACCOUNTING_EXPORT_NOT_FOUND", "Accounting export not found", http.StatusNotFound)
+	case errors.Is(err, usecase.ErrAccountingExportConflict):
+		return pkg.NewDomainErrorSimple("ACCOUNTING_EXPORT_CONFLICT", "Another accounting export is in progress", http.StatusConflict)
+	case errors.Is(err, usecase.ErrAccountingExportUpToDate):
+		return pkg.NewDomainErrorSimple("ACCOUNTING_EXPORT_UP_TO_DATE", "No new events to export", http.StatusConflict)
+	case errors.Is(err, usecase.ErrAccountingExportBlocked):
+		return pkg.NewDomainErrorSimple("ACCOUNTING_EXPORT_BLOCKED", "Accounting export blocked by a payment without amount", http.StatusConflict).WithDetails(err.Error())
+	default:
+		return pkg.NewDomainError("INTERNAL_ERROR", "An internal error occurred", err, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"mecanica_xpto/internal/adapter/http/handlers/mocks"
+	"mecanica_xpto/internal/adapter/http/middlewares"
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAccountingExportHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(t *testing.T) (*gin.Engine, *mocks.MockIAccountingExportUseCase) {
+		ctrl := gomock.NewController(t)
+		uc := mocks.NewMockIAccountingExportUseCase(ctrl)
+		h := NewAccountingExportHandler(uc)
+		r := gin.New()
+		r.POST("/v1/admin/accounting/exports", h.CreateAccountingExport)
+		r.GET("/v1/admin/accounting/exports/:export_id", h.GetAccountingExport)
+		r.GET("/v1/admin/accounting/exports/:export_id/file", h.DownloadAccountingExport)
+		return r, uc
+	}
+
+	t.Run("create", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().Export(gomock.Any(), entities.AccountingExportOFX, "maria").
+			Return(entities.AccountingExport{ID: "run-1", Format: entities.AccountingExportOFX, EntryCount: 4, Actor: "maria"}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/admin/accounting/exports?format=ofx", nil)
+		req.Header.Set(middlewares.AdminActorHeader, "maria")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d", w.Code)
+		}
+		var body struct {
+			ID         string `json:"id"`
+			Format     string `json:"format"`
+			EntryCount int    `json:"entry_count"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		if body.ID != "run-1" || body.Format != "ofx" || body.EntryCount != 4 {
+			t.Fatalf("unexpected body: %s", w.Body.String())
+		}
+	})
+
+	t.Run("download", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().RenderExport(gomock.Any(), "run-1").Return(entities.AccountingExport{ID: "run-1"}, usecase.AccountingFile{
+			Name: "contabil-run-1.ofx", ContentType: "application/x-ofx", Content: []byte("OFXHEADER:100"),
+		}, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/accounting/exports/run-1/file", nil))
+		if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/x-ofx" || w.Body.String() != "OFXHEADER:100" {
+			t.Fatalf("unexpected response %d %q: %s", w.Code, w.Header().Get("Content-Type"), w.Body.String())
+		}
+		if got := w.Header().Get("Content-Disposition"); got != `attachment; filename="contabil-run-1.ofx"` {
+			t.Fatalf("unexpected Content-Disposition: %s", got)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().Export(gomock.Any(), entities.AccountingExportCSV, "admin").Return(entities.AccountingExport{}, usecase.ErrInvalidAccountingFormat)
+		uc.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).Return(entities.AccountingExport{}, usecase.ErrAccountingExportConflict)
+		uc.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).Return(entities.AccountingExport{}, usecase.ErrAccountingExportUpToDate)
+		uc.EXPECT().GetExport(gomock.Any(), "missing").Return(entities.AccountingExport{}, usecase.ErrAccountingExportNotFound)
+		uc.EXPECT().GetExport(gomock.Any(), "boom").Return(entities.AccountingExport{}, errors.New("ddb"))
+
+		cases := []struct {
+			method, path string
+			want         int
+		}{
+			{http.MethodPost, "/v1/admin/accounting/exports", http.StatusBadRequest},
+			{http.MethodPost, "/v1/admin/accounting/exports", http.StatusConflict},
+			{http.MethodPost, "/v1/admin/accounting/exports", http.StatusConflict},
+			{http.MethodGet, "/v1/admin/accounting/exports/missing", http.StatusNotFound},
+			{http.MethodGet, "/v1/admin/accounting/exports/boom", http.StatusInternalServerError},
+		}
+		for _, tc := range cases {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+			if w.Code != tc.want {
+				t.Fatalf("%s %s: expected %d, got %d", tc.method, tc.path, tc.want, w.Code)
+			}
+		}
+	})
+}
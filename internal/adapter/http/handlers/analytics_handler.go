@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	response "mecanica_xpto/internal/adapter/http/dto/response"
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase"
+	"mecanica_xpto/pkg"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AnalyticsHandler serves the revenue and conversion metrics.
+// Privileged: must be routed behind the admin middleware.
+
+type AnalyticsHandler struct {
+	usecase usecase.IConversionAnalyticsUseCase
+}
+
+func NewAnalyticsHandler(uc usecase.IConversionAnalyticsUseCase) *AnalyticsHandler {
+	return &AnalyticsHandler{usecase: uc}
+}
+
+// GetConversion returns the metrics of the estimates created between `?from` and `?to`
+// (YYYY-MM-DD), grouped by `?group_by=day|week|month` (day when omitted).
+func (h *AnalyticsHandler) GetConversion(c *gin.Context) {
+	report, err := h.usecase.Report(c.Request.Context(), usecase.ConversionFilter{
+		From:        c.Query("from"),
+		To:          c.Query("to"),
+		Granularity: entities.AnalyticsGranularity(c.Query("group_by")),
+	})
+	if err != nil {
+		log.Printf("[payment][handler] conversion analytics failed from=%s to=%s err=%v", c.Query("from"), c.Query("to"), err)
+		appErr := mapAnalyticsError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromConversionReport(report))
+}
+
+// GetRejectionReasons tells why the estimates created between `?from` and `?to`
+// (YYYY-MM-DD) were rejected or canceled.
+func (h *AnalyticsHandler) GetRejectionReasons(c *gin.Context) {
+	report, err := h.usecase.RejectionReasons(c.Request.Context(), usecase.ConversionFilter{
+		From: c.Query("from"),
+		To:   c.Query("to"),
+	})
+	if err != nil {
+		log.Printf("[payment][handler] rejection reasons failed from=%s to=%s err=%v", c.Query("from"), c.Query("to"), err)
+		appErr := mapAnalyticsError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromRejectionReasonReport(report))
+}
+
+func mapAnalyticsError(err error) *pkg.AppError {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidAnalyticsRange):
+		return pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest).WithDetails("from/to")
+	case errors.Is(err, usecase.ErrInvalidAnalyticsGranularity):
+		return pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest).WithDetails("group_by")
+	default:
+		return pkg.NewDomainError("INTERNAL_ERROR", "An internal error occurred", err, http.StatusInternalServerError)
+	}
+}
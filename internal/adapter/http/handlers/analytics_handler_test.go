@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"mecanica_xpto/internal/adapter/http/handlers/mocks"
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAnalyticsHandler_GetConversion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(t *testing.T) (*gin.Engine, *mocks.MockIConversionAnalyticsUseCase) {
+		ctrl := gomock.NewController(t)
+		uc := mocks.NewMockIConversionAnalyticsUseCase(ctrl)
+		r := gin.New()
+		r.GET("/v1/admin/reports/conversion", NewAnalyticsHandler(uc).GetConversion)
+		return r, uc
+	}
+
+	t.Run("success", func(t *testing.T) {
+		r, uc := newRouter(t)
+		week := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+		uc.EXPECT().Report(gomock.Any(), usecase.ConversionFilter{From: "2026-03-01", To: "2026-03-31", Granularity: "week"}).Return(entities.ConversionReport{
+			From:        "2026-03-01",
+			To:          "2026-03-31",
+			Granularity: entities.AnalyticsGranularityWeek,
+			Periods:     []entities.ConversionMetrics{{PeriodStart: week, Estimates: 3, Approved: 2, ApprovalRate: 2.0 / 3, AvgHoursToApproval: 10.0 / 3}},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/reports/conversion?from=2026-03-01&to=2026-03-31&group_by=week", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", w.Code)
+		}
+		var body struct {
+			GroupBy string `json:"group_by"`
+			Periods []struct {
+				PeriodStart        string  `json:"period_start"`
+				ApprovalRate       float64 `json:"approval_rate"`
+				AvgHoursToApproval float64 `json:"avg_hours_to_approval"`
+			} `json:"periods"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		if body.GroupBy != "week" || len(body.Periods) != 1 || body.Periods[0].PeriodStart != "2026-03-02" {
+			t.Fatalf("unexpected body: %s", w.Body.String())
+		}
+		if body.Periods[0].ApprovalRate != 0.6667 || body.Periods[0].AvgHoursToApproval != 3.33 {
+			t.Fatalf("unexpected rounding: %s", w.Body.String())
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().Report(gomock.Any(), gomock.Any()).Return(entities.ConversionReport{}, usecase.ErrInvalidAnalyticsRange)
+		uc.EXPECT().Report(gomock.Any(), gomock.Any()).Return(entities.ConversionReport{}, usecase.ErrInvalidAnalyticsGranularity)
+		uc.EXPECT().Report(gomock.Any(), gomock.Any()).Return(entities.ConversionReport{}, errors.New("ddb"))
+
+		for _, want := range []int{http.StatusBadRequest, http.StatusBadRequest, http.StatusInternalServerError} {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/reports/conversion", nil))
+			if w.Code != want {
+				t.Fatalf("expected %d, got %d", want, w.Code)
+			}
+		}
+	})
+}
+
+func TestAnalyticsHandler_GetRejectionReasons(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	uc := mocks.NewMockIConversionAnalyticsUseCase(ctrl)
+	r := gin.New()
+	r.GET("/v1/admin/reports/rejection-reasons", NewAnalyticsHandler(uc).GetRejectionReasons)
+
+	uc.EXPECT().RejectionReasons(gomock.Any(), usecase.ConversionFilter{From: "2026-03-01", To: "2026-03-31"}).Return(entities.RejectionReasonReport{
+		From:     "2026-03-01",
+		To:       "2026-03-31",
+		Rejected: 3,
+		Reasons:  []entities.ReasonMetrics{{Status: entities.EstimateStatusRejeitado, Code: "preco", Label: "Preço", Count: 2, Share: 2.0 / 3, Value: 400.004}},
+	}, nil)
+	uc.EXPECT().RejectionReasons(gomock.Any(), gomock.Any()).Return(entities.RejectionReasonReport{}, usecase.ErrInvalidAnalyticsRange)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/reports/rejection-reasons?from=2026-03-01&to=2026-03-31", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var body struct {
+		Reasons []struct {
+			Code  string  `json:"code"`
+			Share float64 `json:"share"`
+			Value float64 `json:"value"`
+		} `json:"reasons"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	if len(body.Reasons) != 1 || body.Reasons[0].Code != "preco" || body.Reasons[0].Share != 0.6667 || body.Reasons[0].Value != 400 {
+		t.Fatalf("unexpected body: %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/reports/rejection-reasons", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
@@ -4,12 +4,16 @@ import (
 	"encoding/json"
 	"errors"
 	"log"
+	request "mecanica_xpto/internal/adapter/http/dto/request"
 	response "mecanica_xpto/internal/adapter/http/dto/response"
+	"mecanica_xpto/internal/adapter/http/middlewares"
+	"mecanica_xpto/internal/domain/entities"
 	"mecanica_xpto/internal/usecase"
 	"mecanica_xpto/pkg"
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -59,30 +63,172 @@ func (h *BillingPaymentHandler) GetPaymentByEstimateID(c *gin.Context) {
 	estimateID := c.Param("estimate_id")
 	log.Printf("[payment][handler] get-by-estimate start estimate_id=%s", estimateID)
 
-	payments, err := h.usecase.ListByEstimateID(c.Request.Context(), estimateID)
+	latest, err := h.usecase.LatestByEstimateID(c.Request.Context(), estimateID)
 	if err != nil {
 		log.Printf("[payment][handler] get-by-estimate failed estimate_id=%s err=%v", estimateID, err)
 		appErr := mapBillingPaymentError(err)
 		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
 		return
 	}
+	log.Printf("[payment][handler] get-by-estimate success estimate_id=%s payment_id=%s status=%s", estimateID, latest.ID, latest.Status)
+
+	c.JSON(http.StatusOK, response.FromBillingPayment(latest))
+}
+
+// GetPaymentByID returns a payment by its own ID.
+func (h *BillingPaymentHandler) GetPaymentByID(c *gin.Context) {
+	paymentID := c.Param("payment_id")
 
-	if len(payments) == 0 {
-		log.Printf("[payment][handler] get-by-estimate not-found estimate_id=%s", estimateID)
-		appErr := pkg.NewDomainErrorSimple("PAYMENT_NOT_FOUND", "Payment not found", http.StatusNotFound)
+	p, err := h.usecase.GetByID(c.Request.Context(), paymentID)
+	if err != nil {
+		log.Printf("[payment][handler] get failed payment_id=%s err=%v", paymentID, err)
+		appErr := mapBillingPaymentError(err)
 		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
 		return
 	}
 
-	latest := payments[0]
-	for _, p := range payments[1:] {
-		if p.Date.After(latest.Date) {
-			latest = p
-		}
+	c.JSON(http.StatusOK, response.FromBillingPayment(p))
+}
+
+// ListEstimatePayments returns every payment of an estimate ordered by date, paginated.
+func (h *BillingPaymentHandler) ListEstimatePayments(c *gin.Context) {
+	estimateID := c.Param("estimate_id")
+
+	var query request.PaymentPageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		appErr := pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
 	}
-	log.Printf("[payment][handler] get-by-estimate success estimate_id=%s payment_id=%s status=%s", estimateID, latest.ID, latest.Status)
 
-	c.JSON(http.StatusOK, response.FromBillingPayment(latest))
+	page, err := h.usecase.ListByEstimateID(c.Request.Context(), estimateID, toPageRequest(query))
+	if err != nil {
+		log.Printf("[payment][handler] list-by-estimate failed estimate_id=%s err=%v", estimateID, err)
+		appErr := mapBillingPaymentError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromBillingPaymentPage(page))
+}
+
+// ListPayments returns payments with a given status, optionally within a date range.
+func (h *BillingPaymentHandler) ListPayments(c *gin.Context) {
+	var query request.PaymentListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		appErr := pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	from, errFrom := parsePaymentDateParam(query.From, false)
+	to, errTo := parsePaymentDateParam(query.To, true)
+	if errFrom != nil || errTo != nil {
+		appErr := pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest).WithDetails("from", "to")
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	filter := usecase.PaymentListFilter{
+		Status: entities.PaymentStatus(strings.TrimSpace(query.Status)),
+		From:   from,
+		To:     to,
+	}
+	page, err := h.usecase.ListByStatus(c.Request.Context(), filter, toPageRequest(query.PaymentPageQuery))
+	if err != nil {
+		log.Printf("[payment][handler] list-by-status failed status=%s err=%v", filter.Status, err)
+		appErr := mapBillingPaymentError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromBillingPaymentPage(page))
+}
+
+// RevealPayment returns a payment with payer personal data decrypted.
+// Privileged: must be routed behind the admin middleware.
+func (h *BillingPaymentHandler) RevealPayment(c *gin.Context) {
+	paymentID := c.Param("payment_id")
+	log.Printf("[payment][handler] reveal start payment_id=%s", paymentID)
+
+	p, err := h.usecase.Reveal(c.Request.Context(), paymentID)
+	if err != nil {
+		log.Printf("[payment][handler] reveal failed payment_id=%s err=%v", paymentID, err)
+		appErr := mapBillingPaymentError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromRevealedBillingPayment(p))
+}
+
+// GetPaymentHistory returns the status timeline of a payment.
+// Privileged: must be routed behind the admin middleware.
+func (h *BillingPaymentHandler) GetPaymentHistory(c *gin.Context) {
+	paymentID := c.Param("payment_id")
+
+	events, err := h.usecase.History(c.Request.Context(), paymentID)
+	if err != nil {
+		log.Printf("[payment][handler] history failed payment_id=%s err=%v", paymentID, err)
+		appErr := mapBillingPaymentError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromPaymentStatusHistory(paymentID, events))
+}
+
+// ChangePaymentStatus records a manual status change (e.g. refund done in the provider
+// dashboard). Privileged: must be routed behind the admin middleware.
+func (h *BillingPaymentHandler) ChangePaymentStatus(c *gin.Context) {
+	paymentID := c.Param("payment_id")
+
+	var payload request.PaymentStatusChangeRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		appErr := pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	p, err := h.usecase.ChangeStatus(c.Request.Context(), paymentID, usecase.PaymentStatusChange{
+		Status:               entities.PaymentStatus(strings.TrimSpace(payload.Status)),
+		Source:               entities.PaymentEventSourceAdmin,
+		Actor:                middlewares.AdminActor(c),
+		ProviderStatus:       strings.TrimSpace(payload.ProviderStatus),
+		ProviderStatusDetail: strings.TrimSpace(payload.ProviderStatusDetail),
+	})
+	if err != nil {
+		log.Printf("[payment][handler] status change failed payment_id=%s err=%v", paymentID, err)
+		appErr := mapBillingPaymentError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromBillingPayment(p))
+}
+
+func toPageRequest(q request.PaymentPageQuery) entities.PageRequest {
+	return entities.PageRequest{Limit: q.Limit, Cursor: q.Cursor, Descending: q.Order == "desc"}
+}
+
+// parsePaymentDateParam accepts RFC3339 or YYYY-MM-DD (UTC). A date-only upper bound is
+// moved to the end of the day so the range stays inclusive.
+func parsePaymentDateParam(value string, upper bool) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, err
+	}
+	if upper {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &t, nil
 }
 
 func readMPPayload(c *gin.Context) (json.RawMessage, error) {
@@ -111,9 +257,18 @@ func readMPPayload(c *gin.Context) (json.RawMessage, error) {
 }
 
 func mapBillingPaymentError(err error) *pkg.AppError {
+	var schemaErr *usecase.PaymentPayloadSchemaError
+	if errors.As(err, &schemaErr) {
+		return pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest).WithDetails(schemaErr.Pointers...)
+	}
+
 	switch {
-	case errors.Is(err, usecase.ErrInvalidPaymentEstimateID), errors.Is(err, usecase.ErrInvalidMPPayload), errors.Is(err, usecase.ErrPaymentGatewayBadRequest):
+	case errors.Is(err, usecase.ErrInvalidPaymentEstimateID), errors.Is(err, usecase.ErrInvalidPaymentID), errors.Is(err, usecase.ErrInvalidMPPayload), errors.Is(err, usecase.ErrPaymentGatewayBadRequest),
+		errors.Is(err, usecase.ErrInvalidPaymentStatus), errors.Is(err, usecase.ErrInvalidPaymentEventSource),
+		errors.Is(err, usecase.ErrInvalidPaymentDateRange), errors.Is(err, entities.ErrInvalidPageCursor):
 		return pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
+	case errors.Is(err, entities.ErrUnsupportedPaymentMethod):
+		return pkg.NewDomainErrorSimple("UNSUPPORTED_PAYMENT_METHOD", "Payment method is not supported", http.StatusBadRequest).WithDetails("/payment_method_id")
 	case errors.Is(err, usecase.ErrPaymentGatewayCustomerNotFound):
 		return pkg.NewDomainErrorSimple("PAYMENT_PROVIDER_CUSTOMER_NOT_FOUND", "Payer not found for this Mercado Pago test context", http.StatusBadRequest)
 	case errors.Is(err, usecase.ErrPaymentGatewayInvalidUsers):
@@ -124,8 +279,16 @@ func mapBillingPaymentError(err error) *pkg.AppError {
 		return pkg.NewDomainErrorSimple("ESTIMATE_NOT_FOUND", "Estimate not found", http.StatusNotFound)
 	case errors.Is(err, usecase.ErrEstimateNotApproved):
 		return pkg.NewDomainErrorSimple("ESTIMATE_NOT_APPROVED", "Estimate not approved", http.StatusConflict)
+	case errors.Is(err, usecase.ErrInternalApprovalPending):
+		return pkg.NewDomainErrorSimple("INTERNAL_APPROVAL_PENDING", "Estimate awaits internal approval", http.StatusConflict)
 	case errors.Is(err, usecase.ErrBillingPaymentNotFound):
 		return pkg.NewDomainErrorSimple("PAYMENT_NOT_FOUND", "Payment not found", http.StatusNotFound)
+	case errors.Is(err, usecase.ErrPaymentStatusConflict):
+		return pkg.NewDomainErrorSimple("PAYMENT_STATUS_CONFLICT", "Payment status changed concurrently", http.StatusConflict)
+	case errors.Is(err, entities.ErrPaymentAlreadyExists):
+		return pkg.NewDomainErrorSimple("PAYMENT_ALREADY_EXISTS", "Payment already exists", http.StatusConflict)
+	case errors.Is(err, usecase.ErrPaymentTransitionNotAllowed):
+		return pkg.NewDomainErrorSimple("PAYMENT_TRANSITION_NOT_ALLOWED", "Payment status transition not allowed", http.StatusConflict)
 	default:
 		return pkg.NewDomainError("INTERNAL_ERROR", "An internal error occurred", err, http.StatusInternalServerError)
 	}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -67,6 +68,35 @@ func TestBillingPaymentHandler_CreatePaymentByEstimateID(t *testing.T) {
 		}
 	})
 
+	t.Run("schema violation returns pointers", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockIBillingPaymentUseCase(ctrl)
+		h := NewBillingPaymentHandler(uc)
+
+		r := gin.New()
+		r.POST("/v1/payments/:estimate_id", h.CreatePaymentByEstimateID)
+
+		uc.EXPECT().CreateAndApprove(gomock.Any(), "est-1", gomock.Any()).Return(entities.BillingPayment{}, &usecase.PaymentPayloadSchemaError{Pointers: []string{"/payer/email", "/token"}})
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/payments/est-1", bytes.NewBufferString(`{"payment_method_id":"visa"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", w.Code)
+		}
+		var body struct {
+			Code    string   `json:"code"`
+			Details []string `json:"details"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		if body.Code != "INVALID_REQUEST" || len(body.Details) != 2 || body.Details[1] != "/token" {
+			t.Fatalf("unexpected body: %s", w.Body.String())
+		}
+	})
+
 	t.Run("success", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
@@ -109,7 +139,7 @@ func TestBillingPaymentHandler_GetPaymentByEstimateID(t *testing.T) {
 		r := gin.New()
 		r.GET("/v1/payments/:estimate_id", h.GetPaymentByEstimateID)
 
-		uc.EXPECT().ListByEstimateID(gomock.Any(), "est-1").Return(nil, usecase.ErrInvalidPaymentEstimateID)
+		uc.EXPECT().LatestByEstimateID(gomock.Any(), "est-1").Return(entities.BillingPayment{}, usecase.ErrInvalidPaymentEstimateID)
 
 		req := httptest.NewRequest(http.MethodGet, "/v1/payments/est-1", nil)
 		w := httptest.NewRecorder()
@@ -129,7 +159,7 @@ func TestBillingPaymentHandler_GetPaymentByEstimateID(t *testing.T) {
 		r := gin.New()
 		r.GET("/v1/payments/:estimate_id", h.GetPaymentByEstimateID)
 
-		uc.EXPECT().ListByEstimateID(gomock.Any(), "est-1").Return([]entities.BillingPayment{}, nil)
+		uc.EXPECT().LatestByEstimateID(gomock.Any(), "est-1").Return(entities.BillingPayment{}, usecase.ErrBillingPaymentNotFound)
 
 		req := httptest.NewRequest(http.MethodGet, "/v1/payments/est-1", nil)
 		w := httptest.NewRecorder()
@@ -149,9 +179,8 @@ func TestBillingPaymentHandler_GetPaymentByEstimateID(t *testing.T) {
 		r := gin.New()
 		r.GET("/v1/payments/:estimate_id", h.GetPaymentByEstimateID)
 
-		old := entities.BillingPayment{ID: "old", EstimateID: "est-1", Date: time.Now().Add(-time.Hour), Status: entities.PaymentStatusPendente}
 		latest := entities.BillingPayment{ID: "latest", EstimateID: "est-1", Date: time.Now(), Status: entities.PaymentStatusAprovado}
-		uc.EXPECT().ListByEstimateID(gomock.Any(), "est-1").Return([]entities.BillingPayment{old, latest}, nil)
+		uc.EXPECT().LatestByEstimateID(gomock.Any(), "est-1").Return(latest, nil)
 
 		req := httptest.NewRequest(http.MethodGet, "/v1/payments/est-1", nil)
 		w := httptest.NewRecorder()
@@ -168,6 +197,161 @@ func TestBillingPaymentHandler_GetPaymentByEstimateID(t *testing.T) {
 	})
 }
 
+func TestBillingPaymentHandler_GetPaymentByID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	uc := mocks.NewMockIBillingPaymentUseCase(ctrl)
+	h := NewBillingPaymentHandler(uc)
+
+	r := gin.New()
+	r.GET("/v1/payments/:estimate_id", h.GetPaymentByEstimateID)
+	r.GET("/v1/payments/id/:payment_id", h.GetPaymentByID)
+
+	uc.EXPECT().GetByID(gomock.Any(), "pay-1").Return(entities.BillingPayment{ID: "pay-1", EstimateID: "est-1"}, nil)
+	uc.EXPECT().GetByID(gomock.Any(), "pay-2").Return(entities.BillingPayment{}, usecase.ErrBillingPaymentNotFound)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/payments/id/pay-1", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var body map[string]any
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	if body["payment_id"] != "pay-1" {
+		t.Fatalf("unexpected body: %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/payments/id/pay-2", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
+
+func TestBillingPaymentHandler_ListEstimatePayments(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("passes pagination and returns next cursor", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockIBillingPaymentUseCase(ctrl)
+		h := NewBillingPaymentHandler(uc)
+
+		r := gin.New()
+		r.GET("/v1/estimates/:estimate_id/payments", h.ListEstimatePayments)
+
+		page := entities.Page[entities.BillingPayment]{
+			Items:      []entities.BillingPayment{{ID: "p2"}, {ID: "p1"}},
+			NextCursor: "next",
+		}
+		uc.EXPECT().ListByEstimateID(gomock.Any(), "est-1", entities.PageRequest{Limit: 2, Cursor: "abc", Descending: true}).Return(page, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/estimates/est-1/payments?limit=2&cursor=abc&order=desc", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var body struct {
+			Items      []map[string]any `json:"items"`
+			NextCursor string           `json:"next_cursor"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		if len(body.Items) != 2 || body.Items[0]["payment_id"] != "p2" || body.NextCursor != "next" {
+			t.Fatalf("unexpected body: %s", w.Body.String())
+		}
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		h := NewBillingPaymentHandler(mocks.NewMockIBillingPaymentUseCase(ctrl))
+
+		r := gin.New()
+		r.GET("/v1/estimates/:estimate_id/payments", h.ListEstimatePayments)
+
+		for _, q := range []string{"limit=-1", "limit=500", "order=newest"} {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/estimates/est-1/payments?"+q, nil))
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("%s: expected 400, got %d", q, w.Code)
+			}
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockIBillingPaymentUseCase(ctrl)
+		h := NewBillingPaymentHandler(uc)
+
+		r := gin.New()
+		r.GET("/v1/estimates/:estimate_id/payments", h.ListEstimatePayments)
+
+		uc.EXPECT().ListByEstimateID(gomock.Any(), "est-1", gomock.Any()).Return(entities.Page[entities.BillingPayment]{}, entities.ErrInvalidPageCursor)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/estimates/est-1/payments?cursor=bad", nil))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestBillingPaymentHandler_ListPayments(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("parses status and date range", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockIBillingPaymentUseCase(ctrl)
+		h := NewBillingPaymentHandler(uc)
+
+		r := gin.New()
+		r.GET("/v1/payments", h.ListPayments)
+
+		uc.EXPECT().ListByStatus(gomock.Any(), gomock.Any(), entities.PageRequest{}).DoAndReturn(
+			func(_ any, f usecase.PaymentListFilter, _ entities.PageRequest) (entities.Page[entities.BillingPayment], error) {
+				if f.Status != entities.PaymentStatusAprovado {
+					t.Fatalf("unexpected status: %s", f.Status)
+				}
+				wantFrom := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+				wantTo := time.Date(2026, 3, 31, 23, 59, 59, int(time.Second-time.Nanosecond), time.UTC)
+				if f.From == nil || !f.From.Equal(wantFrom) || f.To == nil || !f.To.Equal(wantTo) {
+					t.Fatalf("unexpected range: %v %v", f.From, f.To)
+				}
+				return entities.Page[entities.BillingPayment]{Items: []entities.BillingPayment{{ID: "p1"}}}, nil
+			})
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/payments?status=aprovado&from=2026-03-01T10:00:00Z&to=2026-03-31", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("rejects bad input", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockIBillingPaymentUseCase(ctrl)
+		h := NewBillingPaymentHandler(uc)
+
+		r := gin.New()
+		r.GET("/v1/payments", h.ListPayments)
+
+		uc.EXPECT().ListByStatus(gomock.Any(), gomock.Any(), gomock.Any()).Return(entities.Page[entities.BillingPayment]{}, usecase.ErrInvalidPaymentDateRange)
+
+		for _, q := range []string{"", "status=aprovado&from=ontem", "status=aprovado&from=2026-03-02&to=2026-03-01"} {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/payments?"+q, nil))
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("%q: expected 400, got %d", q, w.Code)
+			}
+		}
+	})
+}
+
 func TestReadMPPayload(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	t.Setenv("PAYMENT_GATEWAY_MOCK", "")
@@ -236,10 +420,12 @@ func TestMapBillingPaymentError(t *testing.T) {
 		{usecase.ErrInvalidMPPayload, http.StatusBadRequest},
 		{usecase.ErrPaymentGatewayBadRequest, http.StatusBadRequest},
 		{usecase.ErrPaymentGatewayCustomerNotFound, http.StatusBadRequest},
+		{fmt.Errorf("%w: boleto_novo", entities.ErrUnsupportedPaymentMethod), http.StatusBadRequest},
 		{usecase.ErrPaymentGatewayInvalidUsers, http.StatusBadRequest},
 		{usecase.ErrPaymentGatewayUnauthorized, http.StatusUnauthorized},
 		{usecase.ErrEstimateNotFound, http.StatusNotFound},
 		{usecase.ErrEstimateNotApproved, http.StatusConflict},
+		{usecase.ErrInternalApprovalPending, http.StatusConflict},
 		{usecase.ErrBillingPaymentNotFound, http.StatusNotFound},
 		{errors.New("other"), http.StatusInternalServerError},
 	}
@@ -251,3 +437,132 @@ func TestMapBillingPaymentError(t *testing.T) {
 		}
 	}
 }
+
+func TestBillingPaymentHandler_RevealPayment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockIBillingPaymentUseCase(ctrl)
+		h := NewBillingPaymentHandler(uc)
+
+		r := gin.New()
+		r.GET("/v1/admin/payments/:payment_id/reveal", h.RevealPayment)
+
+		uc.EXPECT().Reveal(gomock.Any(), "pay-1").Return(entities.BillingPayment{}, usecase.ErrBillingPaymentNotFound)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/payments/pay-1/reveal", nil))
+
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("success returns plaintext", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockIBillingPaymentUseCase(ctrl)
+		h := NewBillingPaymentHandler(uc)
+
+		r := gin.New()
+		r.GET("/v1/admin/payments/:payment_id/reveal", h.RevealPayment)
+
+		uc.EXPECT().Reveal(gomock.Any(), "pay-1").Return(entities.BillingPayment{ID: "pay-1", Details: entities.PaymentDetails{Amount: 1, PayerEmail: "x@test.com"}}, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/payments/pay-1/reveal", nil))
+
+		if w.Code != http.StatusOK || !bytes.Contains(w.Body.Bytes(), []byte("x@test.com")) {
+			t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
+
+func TestBillingPaymentHandler_GetPaymentHistory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	uc := mocks.NewMockIBillingPaymentUseCase(ctrl)
+	h := NewBillingPaymentHandler(uc)
+
+	r := gin.New()
+	r.GET("/v1/admin/payments/:payment_id/history", h.GetPaymentHistory)
+
+	uc.EXPECT().History(gomock.Any(), "pay-1").Return([]entities.PaymentStatusEvent{
+		{ID: "ev-1", PaymentID: "pay-1", Status: entities.PaymentStatusAprovado, Source: entities.PaymentEventSourceAPI, ProviderStatus: "approved"},
+		{ID: "ev-2", PaymentID: "pay-1", Status: entities.PaymentStatusReembolsado, PreviousStatus: entities.PaymentStatusAprovado, Source: entities.PaymentEventSourceAdmin, Actor: "ops"},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/payments/pay-1/history", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var body struct {
+		Events []struct {
+			ID     string `json:"id"`
+			Source string `json:"source"`
+		} `json:"events"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if len(body.Events) != 2 || body.Events[1].Source != "admin" {
+		t.Fatalf("unexpected body: %s", w.Body.String())
+	}
+}
+
+func TestBillingPaymentHandler_ChangePaymentStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("invalid status", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockIBillingPaymentUseCase(ctrl)
+		h := NewBillingPaymentHandler(uc)
+
+		r := gin.New()
+		r.PATCH("/v1/admin/payments/:payment_id/status", h.ChangePaymentStatus)
+
+		uc.EXPECT().ChangeStatus(gomock.Any(), "pay-1", gomock.Any()).Return(entities.BillingPayment{}, usecase.ErrInvalidPaymentStatus)
+
+		req := httptest.NewRequest(http.MethodPatch, "/v1/admin/payments/pay-1/status", bytes.NewBufferString(`{"status":"x"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("success records admin source and actor", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockIBillingPaymentUseCase(ctrl)
+		h := NewBillingPaymentHandler(uc)
+
+		r := gin.New()
+		r.PATCH("/v1/admin/payments/:payment_id/status", h.ChangePaymentStatus)
+
+		uc.EXPECT().ChangeStatus(gomock.Any(), "pay-1", usecase.PaymentStatusChange{
+			Source:         entities.PaymentEventSourceAdmin,
+			Actor:          "ops",
+			ProviderStatus: "refunded",
+		}).Return(entities.BillingPayment{ID: "pay-1", Status: entities.PaymentStatusReembolsado}, nil)
+
+		req := httptest.NewRequest(http.MethodPatch, "/v1/admin/payments/pay-1/status", bytes.NewBufferString(`{"provider_status":"refunded"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Admin-Actor", "ops")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK || !bytes.Contains(w.Body.Bytes(), []byte(`"reembolsado"`)) {
+			t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
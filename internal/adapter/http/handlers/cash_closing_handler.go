@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	response "mecanica_xpto/internal/adapter/http/dto/response"
+	"mecanica_xpto/internal/usecase"
+	"mecanica_xpto/pkg"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CashClosingHandler serves the daily cash closing report.
+// Privileged: must be routed behind the admin middleware.
+
+type CashClosingHandler struct {
+	usecase usecase.ICashClosingUseCase
+}
+
+func NewCashClosingHandler(uc usecase.ICashClosingUseCase) *CashClosingHandler {
+	return &CashClosingHandler{usecase: uc}
+}
+
+// GetCashClosing returns the report of `?date=YYYY-MM-DD` (today when omitted) as JSON,
+// or as a CSV attachment with `?format=csv`.
+func (h *CashClosingHandler) GetCashClosing(c *gin.Context) {
+	format := strings.ToLower(strings.TrimSpace(c.DefaultQuery("format", "json")))
+	if format != "json" && format != "csv" {
+		appErr := pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest).WithDetails("format")
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	report, err := h.usecase.Report(c.Request.Context(), c.Query("date"))
+	if err != nil {
+		log.Printf("[payment][handler] cash closing failed date=%s err=%v", c.Query("date"), err)
+		appErr := mapCashClosingError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, response.FromCashClosingReport(report))
+		return
+	}
+	var buf bytes.Buffer
+	if err := usecase.WriteCashClosingCSV(&buf, report); err != nil {
+		appErr := mapCashClosingError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="fechamento-caixa-%s.csv"`, report.BusinessDay))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+func mapCashClosingError(err error) *pkg.AppError {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidBusinessDay):
+		return pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest).WithDetails("date")
+	default:
+		return pkg.NewDomainError("INTERNAL_ERROR", "An internal error occurred", err, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"mecanica_xpto/internal/adapter/http/handlers/mocks"
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCashClosingHandler_GetCashClosing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	report := entities.CashClosingReport{
+		BusinessDay: "2026-03-01",
+		ByStatus: []entities.CashClosingStatusLine{
+			{Status: entities.PaymentStatusAprovado, CashClosingTotals: entities.CashClosingTotals{Count: 3, Gross: 0.1 + 0.2, Net: 0.3}},
+		},
+	}
+
+	newRouter := func(t *testing.T) (*gin.Engine, *mocks.MockICashClosingUseCase) {
+		ctrl := gomock.NewController(t)
+		uc := mocks.NewMockICashClosingUseCase(ctrl)
+		r := gin.New()
+		r.GET("/v1/admin/reports/cash-closing", NewCashClosingHandler(uc).GetCashClosing)
+		return r, uc
+	}
+
+	t.Run("json with amounts rounded to cents", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().Report(gomock.Any(), "2026-03-01").Return(report, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/reports/cash-closing?date=2026-03-01", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", w.Code)
+		}
+		var body struct {
+			BusinessDay string `json:"business_day"`
+			ByStatus    []struct {
+				Status string  `json:"status"`
+				Count  int     `json:"count"`
+				Gross  float64 `json:"gross"`
+			} `json:"by_status"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		if body.BusinessDay != "2026-03-01" || len(body.ByStatus) != 1 || body.ByStatus[0].Count != 3 || body.ByStatus[0].Gross != 0.3 {
+			t.Fatalf("unexpected body: %s", w.Body.String())
+		}
+	})
+
+	t.Run("csv attachment", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().Report(gomock.Any(), "").Return(report, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/reports/cash-closing?format=csv", nil))
+		if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/csv") {
+			t.Fatalf("unexpected response %d: %v", w.Code, w.Header())
+		}
+		if !strings.Contains(w.Header().Get("Content-Disposition"), "fechamento-caixa-2026-03-01.csv") {
+			t.Fatalf("unexpected disposition: %s", w.Header().Get("Content-Disposition"))
+		}
+		if !strings.Contains(w.Body.String(), "2026-03-01,status,aprovado,,,,,3,0.30,0.00,0.30") {
+			t.Fatalf("unexpected csv: %s", w.Body.String())
+		}
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().Report(gomock.Any(), "ontem").Return(entities.CashClosingReport{}, usecase.ErrInvalidBusinessDay)
+
+		for _, q := range []string{"format=pdf", "date=ontem"} {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/reports/cash-closing?"+q, nil))
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("%s: expected 400, got %d", q, w.Code)
+			}
+		}
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().Report(gomock.Any(), gomock.Any()).Return(entities.CashClosingReport{}, errors.New("ddb"))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/reports/cash-closing", nil))
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("expected 500, got %d", w.Code)
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	request "mecanica_xpto/internal/adapter/http/dto/request"
+	response "mecanica_xpto/internal/adapter/http/dto/response"
+	"mecanica_xpto/internal/adapter/http/middlewares"
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase"
+	"mecanica_xpto/pkg"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxCatalogFileBytes bounds an imported catalog CSV.
+const maxCatalogFileBytes = 10 << 20
+
+// CatalogHandler manages the service and parts price catalog estimates are checked against.
+// All endpoints are privileged and must be routed behind the admin middleware.
+
+type CatalogHandler struct {
+	usecase usecase.ICatalogUseCase
+}
+
+func NewCatalogHandler(uc usecase.ICatalogUseCase) *CatalogHandler {
+	return &CatalogHandler{usecase: uc}
+}
+
+// ListCatalog returns every catalog item with the current catalog version.
+func (h *CatalogHandler) ListCatalog(c *gin.Context) {
+	snapshot, err := h.usecase.List(c.Request.Context())
+	if err != nil {
+		appErr := mapCatalogError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromCatalog(snapshot.Version, snapshot.Items))
+}
+
+func (h *CatalogHandler) GetCatalogItem(c *gin.Context) {
+	item, err := h.usecase.Get(c.Request.Context(), catalogKind(c), c.Param("code"))
+	if err != nil {
+		appErr := mapCatalogError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromCatalogItem(item))
+}
+
+// UpsertCatalogItem creates or replaces an item; every change is a new catalog version.
+func (h *CatalogHandler) UpsertCatalogItem(c *gin.Context) {
+	var payload request.CatalogItemRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		appErr := pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	item, err := h.usecase.Upsert(c.Request.Context(), entities.CatalogItem{
+		Kind:  catalogKind(c),
+		Code:  c.Param("code"),
+		Name:  payload.Name,
+		Price: payload.Price,
+	}, middlewares.AdminActor(c))
+	if err != nil {
+		appErr := mapCatalogError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromCatalogItem(item))
+}
+
+// DeleteCatalogItem removes an item; estimates already checked against it are not changed.
+func (h *CatalogHandler) DeleteCatalogItem(c *gin.Context) {
+	item, err := h.usecase.Delete(c.Request.Context(), catalogKind(c), c.Param("code"))
+	if err != nil {
+		appErr := mapCatalogError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromCatalogItem(item))
+}
+
+// ImportCatalog upserts the items of a CSV (columns kind, code, name, price) sent either as
+// the multipart field `file` or as the raw request body (Content-Type text/csv).
+func (h *CatalogHandler) ImportCatalog(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxCatalogFileBytes)
+
+	fileName, content, err := readCSVUpload(c)
+	if err != nil {
+		log.Printf("[catalog][handler] catalog upload invalid err=%v", err)
+		appErr := pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+	actor := middlewares.AdminActor(c)
+
+	snapshot, err := h.usecase.Import(c.Request.Context(), content, actor)
+	if err != nil {
+		log.Printf("[catalog][handler] catalog import failed file=%s actor=%s err=%v", fileName, actor, err)
+		appErr := mapCatalogError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromCatalog(snapshot.Version, snapshot.Items))
+}
+
+func catalogKind(c *gin.Context) entities.EstimateItemKind {
+	return entities.EstimateItemKind(strings.ToLower(strings.TrimSpace(c.Param("kind"))))
+}
+
+func mapCatalogError(err error) *pkg.AppError {
+	switch {
+	case errors.Is(err, entities.ErrInvalidCatalogItem):
+		return pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest).
+			WithDetails("kind", "code", "name", "price")
+	case errors.Is(err, usecase.ErrInvalidCatalogCSV):
+		return pkg.NewDomainError("INVALID_CATALOG_FILE", "Invalid catalog file", err, http.StatusBadRequest).WithDetails(err.Error())
+	case errors.Is(err, usecase.ErrCatalogItemNotFound):
+		return pkg.NewDomainErrorSimple("CATALOG_ITEM_NOT_FOUND", "Catalog item not found", http.StatusNotFound)
+	default:
+		return pkg.NewDomainError("INTERNAL_ERROR", "An internal error occurred", err, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"mecanica_xpto/internal/adapter/http/handlers/mocks"
+	"mecanica_xpto/internal/adapter/http/middlewares"
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCatalogHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(t *testing.T) (*gin.Engine, *mocks.MockICatalogUseCase) {
+		ctrl := gomock.NewController(t)
+		uc := mocks.NewMockICatalogUseCase(ctrl)
+		h := NewCatalogHandler(uc)
+		r := gin.New()
+		r.GET("/v1/admin/catalog", h.ListCatalog)
+		r.POST("/v1/admin/catalog/import", h.ImportCatalog)
+		r.GET("/v1/admin/catalog/:kind/:code", h.GetCatalogItem)
+		r.PUT("/v1/admin/catalog/:kind/:code", h.UpsertCatalogItem)
+		r.DELETE("/v1/admin/catalog/:kind/:code", h.DeleteCatalogItem)
+		return r, uc
+	}
+
+	t.Run("upsert", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().Upsert(gomock.Any(), gomock.Any(), "ana").
+			DoAndReturn(func(_ any, item entities.CatalogItem, _ string) (entities.CatalogItem, error) {
+				if item.Kind != entities.EstimateItemPeca || item.Code != "fil-1" || item.Name != "Filtro" || item.Price != 32.5 {
+					t.Fatalf("unexpected item: %+v", item)
+				}
+				item.Code = "FIL-1"
+				item.Version = 3
+				return item, nil
+			})
+
+		req := httptest.NewRequest(http.MethodPut, "/v1/admin/catalog/Peca/fil-1", strings.NewReader(`{"name":"Filtro","price":32.5}`))
+		req.Header.Set(middlewares.AdminActorHeader, "ana")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"code":"FIL-1"`) || !strings.Contains(w.Body.String(), `"version":3`) {
+			t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+		}
+
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/v1/admin/catalog/peca/fil-1", strings.NewReader(`{"name":"Filtro"}`)))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("get, list and delete", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().Get(gomock.Any(), entities.EstimateItemServico, "SRV-9").Return(entities.CatalogItem{}, usecase.ErrCatalogItemNotFound)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/catalog/servico/SRV-9", nil))
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", w.Code)
+		}
+
+		uc.EXPECT().List(gomock.Any()).Return(usecase.CatalogSnapshot{Version: 5}, nil)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/catalog", nil))
+		if w.Code != http.StatusOK || w.Body.String() != `{"version":5,"items":[]}` {
+			t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+		}
+
+		uc.EXPECT().Delete(gomock.Any(), entities.EstimateItemServico, "SRV-1").
+			Return(entities.CatalogItem{Kind: entities.EstimateItemServico, Code: "SRV-1"}, nil)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/v1/admin/catalog/servico/SRV-1", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", w.Code)
+		}
+	})
+
+	t.Run("import", func(t *testing.T) {
+		r, uc := newRouter(t)
+		csvFile := "kind;code;name;price\nservico;SRV-1;Troca de óleo;80\n"
+		uc.EXPECT().Import(gomock.Any(), gomock.Any(), "ana").
+			DoAndReturn(func(_ any, content io.Reader, _ string) (usecase.CatalogSnapshot, error) {
+				raw, _ := io.ReadAll(content)
+				if string(raw) != csvFile {
+					t.Fatalf("unexpected content: %q", raw)
+				}
+				return usecase.CatalogSnapshot{Version: 6, Items: []entities.CatalogItem{{Kind: entities.EstimateItemServico, Code: "SRV-1", Version: 6}}}, nil
+			})
+
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		part, _ := mw.CreateFormFile("file", "catalogo.csv")
+		fmt.Fprint(part, csvFile)
+		mw.Close()
+		req := httptest.NewRequest(http.MethodPost, "/v1/admin/catalog/import", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		req.Header.Set(middlewares.AdminActorHeader, "ana")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"version":6`) {
+			t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+		}
+
+		uc.EXPECT().Import(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(usecase.CatalogSnapshot{}, fmt.Errorf("%w: line 3 repeats line 2", usecase.ErrInvalidCatalogCSV))
+		req = httptest.NewRequest(http.MethodPost, "/v1/admin/catalog/import", strings.NewReader(csvFile))
+		req.Header.Set("Content-Type", "text/csv")
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "line 3 repeats line 2") {
+			t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	request "mecanica_xpto/internal/adapter/http/dto/request"
+	response "mecanica_xpto/internal/adapter/http/dto/response"
+	"mecanica_xpto/internal/adapter/http/middlewares"
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase"
+	"mecanica_xpto/pkg"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CouponHandler manages the discount coupons applied to estimates.
+// All endpoints are privileged and must be routed behind the admin middleware.
+
+type CouponHandler struct {
+	usecase usecase.ICouponUseCase
+}
+
+func NewCouponHandler(uc usecase.ICouponUseCase) *CouponHandler {
+	return &CouponHandler{usecase: uc}
+}
+
+func (h *CouponHandler) CreateCoupon(c *gin.Context) {
+	var payload request.CouponRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		appErr := pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	coupon, err := h.usecase.Create(c.Request.Context(), payload.ToEntity(), middlewares.AdminActor(c))
+	if err != nil {
+		appErr := mapCouponError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.FromCoupon(coupon))
+}
+
+func (h *CouponHandler) GetCoupon(c *gin.Context) {
+	coupon, err := h.usecase.GetByCode(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		appErr := mapCouponError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromCoupon(coupon))
+}
+
+// DeactivateCoupon stops new redemptions; estimates already discounted are not changed.
+func (h *CouponHandler) DeactivateCoupon(c *gin.Context) {
+	coupon, err := h.usecase.Deactivate(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		appErr := mapCouponError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromCoupon(coupon))
+}
+
+func mapCouponError(err error) *pkg.AppError {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidCoupon):
+		return pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest).
+			WithDetails("code", "type", "value", "scope", "item_kind", "expires_at", "max_redemptions", "max_per_customer")
+	case errors.Is(err, entities.ErrCouponAlreadyExists):
+		return pkg.NewDomainErrorSimple("COUPON_ALREADY_EXISTS", "Coupon code already exists", http.StatusConflict)
+	case errors.Is(err, usecase.ErrCouponNotFound):
+		return pkg.NewDomainErrorSimple("COUPON_NOT_FOUND", "Coupon not found", http.StatusNotFound)
+	default:
+		return pkg.NewDomainError("INTERNAL_ERROR", "An internal error occurred", err, http.StatusInternalServerError)
+	}
+}
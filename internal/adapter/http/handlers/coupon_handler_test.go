@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"mecanica_xpto/internal/adapter/http/handlers/mocks"
+	"mecanica_xpto/internal/adapter/http/middlewares"
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCouponHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(t *testing.T) (*gin.Engine, *mocks.MockICouponUseCase) {
+		ctrl := gomock.NewController(t)
+		uc := mocks.NewMockICouponUseCase(ctrl)
+		h := NewCouponHandler(uc)
+		r := gin.New()
+		r.POST("/v1/admin/coupons", h.CreateCoupon)
+		r.GET("/v1/admin/coupons/:code", h.GetCoupon)
+		r.POST("/v1/admin/coupons/:code/deactivate", h.DeactivateCoupon)
+		return r, uc
+	}
+
+	t.Run("create", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().Create(gomock.Any(), gomock.Any(), "ana").
+			DoAndReturn(func(_ any, c entities.Coupon, _ string) (entities.Coupon, error) {
+				if c.Code != "promo10" || c.Rule.Type != entities.DiscountPercentual || c.Rule.Scope != entities.DiscountScopeItem ||
+					c.Rule.ItemKind != entities.EstimateItemServico || c.ExpiresAt == nil || c.MaxPerCustomer != 1 {
+					t.Fatalf("unexpected coupon: %+v", c)
+				}
+				c.Code = "PROMO10"
+				c.Active = true
+				return c, nil
+			})
+
+		body := `{"code":"promo10","type":"percentual","value":10,"scope":"item","item_kind":"servico","expires_at":"2026-12-31T03:00:00Z","max_per_customer":1}`
+		req := httptest.NewRequest(http.MethodPost, "/v1/admin/coupons", strings.NewReader(body))
+		req.Header.Set(middlewares.AdminActorHeader, "ana")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusCreated || !strings.Contains(w.Body.String(), `"code":"PROMO10"`) {
+			t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("create errors", func(t *testing.T) {
+		r, uc := newRouter(t)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/admin/coupons", strings.NewReader(`{"code":"x"}`)))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", w.Code)
+		}
+
+		uc.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(entities.Coupon{}, entities.ErrCouponAlreadyExists)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/admin/coupons", strings.NewReader(`{"code":"x","type":"fixo","value":5,"scope":"orcamento"}`)))
+		if w.Code != http.StatusConflict {
+			t.Fatalf("expected 409, got %d", w.Code)
+		}
+	})
+
+	t.Run("get and deactivate", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().GetByCode(gomock.Any(), "nope").Return(entities.Coupon{}, usecase.ErrCouponNotFound)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/coupons/nope", nil))
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", w.Code)
+		}
+
+		uc.EXPECT().Deactivate(gomock.Any(), "PROMO10").Return(entities.Coupon{Code: "PROMO10", Redemptions: 3}, nil)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/admin/coupons/PROMO10/deactivate", nil))
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"active":false`) {
+			t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	request "mecanica_xpto/internal/adapter/http/dto/request"
+	response "mecanica_xpto/internal/adapter/http/dto/response"
+	"mecanica_xpto/internal/adapter/http/middlewares"
+	"mecanica_xpto/internal/usecase"
+	"mecanica_xpto/pkg"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DataSubjectHandler handles LGPD data subject requests (export and anonymization).
+// Privileged: must be routed behind the admin middleware.
+
+type DataSubjectHandler struct {
+	usecase usecase.IDataSubjectUseCase
+}
+
+func NewDataSubjectHandler(uc usecase.IDataSubjectUseCase) *DataSubjectHandler {
+	return &DataSubjectHandler{usecase: uc}
+}
+
+// Export returns every payment (decrypted), estimate, NFS-e document and approval link
+// linked to a payer email/document/phone.
+func (h *DataSubjectHandler) Export(c *gin.Context) {
+	subject, ok := bindDataSubject(c)
+	if !ok {
+		return
+	}
+	actor := middlewares.AdminActor(c)
+
+	out, err := h.usecase.Export(c.Request.Context(), subject, actor)
+	if err != nil {
+		log.Printf("[payment][handler] data subject export failed actor=%s err=%v", actor, err)
+		appErr := mapDataSubjectError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromDataSubjectExport(out.GeneratedAt, out.Payments, out.Estimates, out.NFSeDocuments, out.ApprovalLinks))
+}
+
+// Anonymize erases personal data from the payments and approval links linked to a payer
+// email/document/phone.
+func (h *DataSubjectHandler) Anonymize(c *gin.Context) {
+	subject, ok := bindDataSubject(c)
+	if !ok {
+		return
+	}
+	actor := middlewares.AdminActor(c)
+
+	out, err := h.usecase.Anonymize(c.Request.Context(), subject, actor)
+	if err != nil {
+		log.Printf("[payment][handler] data subject anonymize failed actor=%s err=%v", actor, err)
+		appErr := mapDataSubjectError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromDataSubjectAnonymization(out.PaymentIDs, out.ApprovalLinkIDs, out.RetainedNFSeIDs, out.Anonymized, out.AlreadyAnonymized, out.AnonymizedAt))
+}
+
+func bindDataSubject(c *gin.Context) (usecase.DataSubject, bool) {
+	var payload request.DataSubjectRequest
+	if err := c.ShouldBindJSON(&payload); err != nil || payload.IsEmpty() {
+		appErr := pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return usecase.DataSubject{}, false
+	}
+	return usecase.DataSubject{Email: payload.Email, Document: payload.Document, Phone: payload.Phone}, true
+}
+
+func mapDataSubjectError(err error) *pkg.AppError {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidDataSubject):
+		return pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
+	default:
+		return pkg.NewDomainError("INTERNAL_ERROR", "An internal error occurred", err, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"mecanica_xpto/internal/adapter/http/handlers/mocks"
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+)
+
+func newDataSubjectRouter(uc usecase.IDataSubjectUseCase) *gin.Engine {
+	h := NewDataSubjectHandler(uc)
+	r := gin.New()
+	r.POST("/v1/admin/data-subjects/export", h.Export)
+	r.POST("/v1/admin/data-subjects/anonymize", h.Anonymize)
+	return r
+}
+
+func TestDataSubjectHandler_Export(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("missing email and document", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		r := newDataSubjectRouter(mocks.NewMockIDataSubjectUseCase(ctrl))
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/admin/data-subjects/export", bytes.NewBufferString(`{"email":" "}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("success returns unredacted data", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockIDataSubjectUseCase(ctrl)
+		r := newDataSubjectRouter(uc)
+
+		uc.EXPECT().Export(gomock.Any(), usecase.DataSubject{Email: "ana@example.com"}, "dpo").Return(usecase.DataSubjectExport{
+			Payments:    []entities.BillingPayment{{ID: "p1", EstimateID: "e1", Details: entities.PaymentDetails{Amount: 10, PayerEmail: "ana@example.com"}}},
+			Estimates:   []entities.Estimate{{ID: "e1"}},
+			GeneratedAt: time.Now(),
+		}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/admin/data-subjects/export", bytes.NewBufferString(`{"email":"ana@example.com"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Admin-Actor", "dpo")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+		}
+		var body struct {
+			Payments []struct {
+				Details struct {
+					PayerEmail string `json:"payer_email"`
+				} `json:"details"`
+			} `json:"payments"`
+			Estimates []map[string]any `json:"estimates"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("invalid json: %v", err)
+		}
+		if len(body.Payments) != 1 || body.Payments[0].Details.PayerEmail != "ana@example.com" || len(body.Estimates) != 1 {
+			t.Fatalf("unexpected body: %s", w.Body.String())
+		}
+	})
+}
+
+func TestDataSubjectHandler_Anonymize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockIDataSubjectUseCase(ctrl)
+		r := newDataSubjectRouter(uc)
+
+		uc.EXPECT().Anonymize(gomock.Any(), usecase.DataSubject{Document: "123.456.789-09"}, "admin").
+			Return(usecase.DataSubjectAnonymization{PaymentIDs: []string{"p1"}, Anonymized: 1}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/admin/data-subjects/anonymize", bytes.NewBufferString(`{"document":"123.456.789-09"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", w.Code)
+		}
+	})
+
+	t.Run("usecase error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockIDataSubjectUseCase(ctrl)
+		r := newDataSubjectRouter(uc)
+
+		uc.EXPECT().Anonymize(gomock.Any(), gomock.Any(), gomock.Any()).Return(usecase.DataSubjectAnonymization{}, errors.New("ddb"))
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/admin/data-subjects/anonymize", bytes.NewBufferString(`{"email":"ana@example.com"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("expected 500, got %d", w.Code)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	request "mecanica_xpto/internal/adapter/http/dto/request"
+	response "mecanica_xpto/internal/adapter/http/dto/response"
+	"mecanica_xpto/internal/adapter/http/middlewares"
+	"mecanica_xpto/internal/usecase"
+	"mecanica_xpto/pkg"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// mercadoPagoChargebackTopics are the notification types Mercado Pago uses for chargebacks
+// (webhooks and legacy IPN).
+var mercadoPagoChargebackTopics = map[string]bool{
+	"chargebacks":          true,
+	"topic_chargebacks_wh": true,
+}
+
+// DisputeHandler handles chargeback notifications and dispute management.
+
+type DisputeHandler struct {
+	usecase usecase.IDisputeUseCase
+}
+
+func NewDisputeHandler(uc usecase.IDisputeUseCase) *DisputeHandler {
+	return &DisputeHandler{usecase: uc}
+}
+
+// MercadoPagoNotification receives Mercado Pago webhooks. Chargeback notifications open or
+// update a dispute; other topics are acknowledged and ignored.
+func (h *DisputeHandler) MercadoPagoNotification(c *gin.Context) {
+	var payload request.MercadoPagoNotificationRequest
+	_ = c.ShouldBindJSON(&payload)
+
+	topic := firstNonEmpty(payload.Type, c.Query("type"), c.Query("topic"))
+	// Only the query data.id is covered by the signature (see VerifyMercadoPagoSignature).
+	id := c.Query("data.id")
+	if !mercadoPagoChargebackTopics[topic] {
+		c.JSON(http.StatusOK, gin.H{"status": "ignored"})
+		return
+	}
+	log.Printf("[dispute][handler] chargeback notification chargeback_id=%s action=%s", id, payload.Action)
+
+	d, err := h.usecase.HandleChargebackNotification(c.Request.Context(), id)
+	if errors.Is(err, usecase.ErrBillingPaymentNotFound) {
+		// Chargeback of a payment not created by this service: retrying will not help.
+		c.JSON(http.StatusOK, gin.H{"status": "ignored"})
+		return
+	}
+	if err != nil {
+		log.Printf("[dispute][handler] chargeback notification failed chargeback_id=%s err=%v", id, err)
+		appErr := mapDisputeError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromDispute(d, time.Now()))
+}
+
+// ListOpenDisputes returns disputes waiting for a provider decision, closest deadline first.
+func (h *DisputeHandler) ListOpenDisputes(c *gin.Context) {
+	ds, err := h.usecase.ListOpen(c.Request.Context())
+	if err != nil {
+		appErr := mapDisputeError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromDisputes(ds, time.Now()))
+}
+
+func (h *DisputeHandler) GetDispute(c *gin.Context) {
+	d, err := h.usecase.GetByID(c.Request.Context(), c.Param("dispute_id"))
+	if err != nil {
+		appErr := mapDisputeError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromDispute(d, time.Now()))
+}
+
+// AddDisputeEvidence attaches an evidence reference to an open dispute.
+func (h *DisputeHandler) AddDisputeEvidence(c *gin.Context) {
+	var payload request.DisputeEvidenceRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		appErr := pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	d, err := h.usecase.AddEvidence(c.Request.Context(), c.Param("dispute_id"), usecase.DisputeEvidenceInput{
+		Reference:   payload.Reference,
+		Description: payload.Description,
+	}, middlewares.AdminActor(c))
+	if err != nil {
+		appErr := mapDisputeError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromDispute(d, time.Now()))
+}
+
+func mapDisputeError(err error) *pkg.AppError {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidDisputeID), errors.Is(err, usecase.ErrInvalidDisputeEvidence):
+		return pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
+	case errors.Is(err, usecase.ErrDisputeNotFound):
+		return pkg.NewDomainErrorSimple("DISPUTE_NOT_FOUND", "Dispute not found", http.StatusNotFound)
+	case errors.Is(err, usecase.ErrDisputeClosed):
+		return pkg.NewDomainErrorSimple("DISPUTE_CLOSED", "Dispute already resolved", http.StatusConflict)
+	default:
+		return pkg.NewDomainError("INTERNAL_ERROR", "An internal error occurred", err, http.StatusInternalServerError)
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"mecanica_xpto/internal/adapter/http/handlers/mocks"
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+)
+
+func newDisputeRouter(uc usecase.IDisputeUseCase) *gin.Engine {
+	h := NewDisputeHandler(uc)
+	r := gin.New()
+	r.POST("/v1/webhooks/mercadopago", h.MercadoPagoNotification)
+	r.GET("/v1/admin/disputes", h.ListOpenDisputes)
+	r.GET("/v1/admin/disputes/:dispute_id", h.GetDispute)
+	r.POST("/v1/admin/disputes/:dispute_id/evidence", h.AddDisputeEvidence)
+	return r
+}
+
+func TestDisputeHandler_MercadoPagoNotification(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	send := func(r *gin.Engine, url, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("other topics are ignored", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		r := newDisputeRouter(mocks.NewMockIDisputeUseCase(ctrl))
+
+		w := send(r, "/v1/webhooks/mercadopago", `{"type":"payment","data":{"id":"1"}}`)
+		if w.Code != http.StatusOK || !bytes.Contains(w.Body.Bytes(), []byte("ignored")) {
+			t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("signed query id wins over the body", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockIDisputeUseCase(ctrl)
+		r := newDisputeRouter(uc)
+
+		uc.EXPECT().HandleChargebackNotification(gomock.Any(), "cb1").Return(entities.Dispute{ID: "cb1", Status: entities.DisputeStatusAberta}, nil)
+
+		w := send(r, "/v1/webhooks/mercadopago?data.id=cb1&type=topic_chargebacks_wh", `{"type":"topic_chargebacks_wh","action":"created","data":{"id":"cb-other"}}`)
+		if w.Code != http.StatusOK || !bytes.Contains(w.Body.Bytes(), []byte(`"dispute_id":"cb1"`)) {
+			t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("topic from the query and unknown payment", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockIDisputeUseCase(ctrl)
+		r := newDisputeRouter(uc)
+
+		uc.EXPECT().HandleChargebackNotification(gomock.Any(), "cb2").Return(entities.Dispute{}, usecase.ErrBillingPaymentNotFound)
+
+		w := send(r, "/v1/webhooks/mercadopago?topic=chargebacks&data.id=cb2", ``)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", w.Code)
+		}
+	})
+
+	t.Run("provider failure asks for retry", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockIDisputeUseCase(ctrl)
+		r := newDisputeRouter(uc)
+
+		uc.EXPECT().HandleChargebackNotification(gomock.Any(), "cb1").Return(entities.Dispute{}, errors.New("timeout"))
+
+		w := send(r, "/v1/webhooks/mercadopago?data.id=cb1", `{"type":"chargebacks","data":{"id":"cb1"}}`)
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("expected 500, got %d", w.Code)
+		}
+	})
+}
+
+func TestDisputeHandler_Admin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("list open disputes flags overdue", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockIDisputeUseCase(ctrl)
+		r := newDisputeRouter(uc)
+
+		past := time.Now().Add(-time.Hour)
+		uc.EXPECT().ListOpen(gomock.Any()).Return([]entities.Dispute{{ID: "cb1", Status: entities.DisputeStatusAberta, EvidenceDeadline: &past}}, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/disputes", nil))
+		if w.Code != http.StatusOK || !bytes.Contains(w.Body.Bytes(), []byte(`"overdue":true`)) {
+			t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("get not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockIDisputeUseCase(ctrl)
+		r := newDisputeRouter(uc)
+
+		uc.EXPECT().GetByID(gomock.Any(), "cb9").Return(entities.Dispute{}, usecase.ErrDisputeNotFound)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/disputes/cb9", nil))
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("add evidence", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockIDisputeUseCase(ctrl)
+		r := newDisputeRouter(uc)
+
+		uc.EXPECT().AddEvidence(gomock.Any(), "cb1", usecase.DisputeEvidenceInput{Reference: "docs/os-1.pdf"}, "ops").
+			Return(entities.Dispute{}, usecase.ErrDisputeClosed)
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/admin/disputes/cb1/evidence", bytes.NewBufferString(`{"reference":"docs/os-1.pdf"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Admin-Actor", "ops")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusConflict {
+			t.Fatalf("expected 409, got %d", w.Code)
+		}
+	})
+
+	t.Run("add evidence without reference", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		r := newDisputeRouter(mocks.NewMockIDisputeUseCase(ctrl))
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/admin/disputes/cb1/evidence", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", w.Code)
+		}
+	})
+}
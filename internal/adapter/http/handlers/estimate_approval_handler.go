@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"errors"
+	request "mecanica_xpto/internal/adapter/http/dto/request"
+	response "mecanica_xpto/internal/adapter/http/dto/response"
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase"
+	"mecanica_xpto/pkg"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EstimateApprovalHandler handles approval links: the workshop issues them and the customer
+// views, confirms (OTP) and decides the estimate through them.
+
+type EstimateApprovalHandler struct {
+	usecase usecase.IEstimateApprovalUseCase
+}
+
+func NewEstimateApprovalHandler(uc usecase.IEstimateApprovalUseCase) *EstimateApprovalHandler {
+	return &EstimateApprovalHandler{usecase: uc}
+}
+
+// CreateApprovalLink issues an approval link for a pending estimate and sends it to the
+// customer.
+func (h *EstimateApprovalHandler) CreateApprovalLink(c *gin.Context) {
+	var payload request.ApprovalLinkRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		appErr := pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	issued, err := h.usecase.CreateLink(c.Request.Context(), c.Param("estimate_id"), usecase.ApprovalLinkRequest{
+		Channel:   entities.NotificationChannel(strings.ToLower(strings.TrimSpace(payload.Channel))),
+		Recipient: payload.Recipient,
+		CreatedBy: payload.CreatedBy,
+	})
+	if err != nil {
+		appErr := mapApprovalError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.FromApprovalLink(issued.Link, issued.Token, issued.URL))
+}
+
+// GetApproval shows the estimate of an approval link to the customer.
+func (h *EstimateApprovalHandler) GetApproval(c *gin.Context) {
+	view, err := h.usecase.View(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		appErr := mapApprovalError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromApprovalView(view.Link, view.Estimate))
+}
+
+// SendApprovalOTP sends the customer a code to confirm the decision.
+func (h *EstimateApprovalHandler) SendApprovalOTP(c *gin.Context) {
+	link, err := h.usecase.SendOTP(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		appErr := mapApprovalError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response.FromApprovalLinkStatus(link))
+}
+
+// DecideApproval approves or rejects the estimate of the link, recording the IP and user
+// agent of the request as evidence.
+func (h *EstimateApprovalHandler) DecideApproval(c *gin.Context) {
+	var payload request.CustomerDecisionRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		appErr := pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	decision := usecase.CustomerDecision{
+		Action:    payload.Action,
+		OTP:       payload.OTP,
+		ItemIDs:   payload.ApprovedItems,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+	if payload.Reason != nil {
+		decision.ReasonCode = payload.Reason.Code
+		decision.ReasonText = payload.Reason.Text
+	}
+	estimate, err := h.usecase.Decide(c.Request.Context(), c.Param("token"), decision)
+	if err != nil {
+		appErr := mapApprovalError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromCustomerEstimate(estimate))
+}
+
+func mapApprovalError(err error) *pkg.AppError {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidApprovalToken):
+		return pkg.NewDomainErrorSimple("INVALID_APPROVAL_LINK", "Approval link is invalid or expired", http.StatusNotFound)
+	case errors.Is(err, usecase.ErrApprovalLinkUsed):
+		return pkg.NewDomainErrorSimple("APPROVAL_LINK_USED", "Approval link was already used", http.StatusConflict)
+	case errors.Is(err, usecase.ErrInvalidRecipient):
+		return pkg.NewDomainErrorSimple("INVALID_RECIPIENT", "Recipient must be an e-mail (channel email) or a phone number (channel sms)", http.StatusBadRequest).WithDetails("channel", "recipient")
+	case errors.Is(err, usecase.ErrInvalidApprovalAction):
+		return pkg.NewDomainErrorSimple("INVALID_ACTION", "Action must be aprovar or rejeitar", http.StatusBadRequest).WithDetails("action")
+	case errors.Is(err, usecase.ErrOTPNotSent):
+		return pkg.NewDomainErrorSimple("OTP_NOT_SENT", "Request a confirmation code first; codes expire in 10 minutes", http.StatusConflict)
+	case errors.Is(err, usecase.ErrInvalidOTP):
+		return pkg.NewDomainErrorSimple("INVALID_OTP", "Invalid confirmation code", http.StatusUnprocessableEntity).WithDetails("otp")
+	case errors.Is(err, usecase.ErrOTPLocked):
+		return pkg.NewDomainErrorSimple("OTP_LOCKED", "Too many invalid codes; ask the workshop for a new link", http.StatusTooManyRequests)
+	case errors.Is(err, usecase.ErrOTPSendLimit):
+		return pkg.NewDomainErrorSimple("OTP_SEND_LIMIT", "Confirmation code sent too many times; ask the workshop for a new link", http.StatusTooManyRequests)
+	case errors.Is(err, entities.ErrApprovalLinkChanged):
+		return pkg.NewDomainErrorSimple("APPROVAL_LINK_CHANGED", "Approval link changed concurrently, retry", http.StatusConflict)
+	default:
+		return mapEstimateError(err)
+	}
+}
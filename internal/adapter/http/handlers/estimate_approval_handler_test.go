@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"mecanica_xpto/internal/adapter/http/handlers/mocks"
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+)
+
+func newEstimateApprovalRouter(uc usecase.IEstimateApprovalUseCase) *gin.Engine {
+	h := NewEstimateApprovalHandler(uc)
+	r := gin.New()
+	r.POST("/v1/estimates/:estimate_id/approval-links", h.CreateApprovalLink)
+	r.GET("/v1/approval/:token", h.GetApproval)
+	r.POST("/v1/approval/:token/otp", h.SendApprovalOTP)
+	r.POST("/v1/approval/:token/decision", h.DecideApproval)
+	return r
+}
+
+func TestEstimateApprovalHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	send := func(r *gin.Engine, method, url, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "Mozilla/5.0")
+		req.RemoteAddr = "203.0.113.7:5123"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	link := entities.ApprovalLink{
+		ID: "link-1", EstimateID: "est-1", OSID: "os-1",
+		Channel: entities.NotificationChannelEmail, Recipient: "maria@example.com",
+		ExpiresAt: time.Date(2026, 5, 8, 12, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("create link", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockIEstimateApprovalUseCase(ctrl)
+		r := newEstimateApprovalRouter(uc)
+
+		uc.EXPECT().CreateLink(gomock.Any(), "est-1", usecase.ApprovalLinkRequest{
+			Channel: entities.NotificationChannelEmail, Recipient: "maria@example.com", CreatedBy: "atendente",
+		}).Return(usecase.IssuedApprovalLink{Link: link, Token: "tok-1", URL: "https://oficina.example.com/aprovacao/tok-1"}, nil)
+
+		w := send(r, http.MethodPost, "/v1/estimates/est-1/approval-links", `{"channel":"EMAIL","recipient":"maria@example.com","created_by":"atendente"}`)
+		if w.Code != http.StatusCreated || !strings.Contains(w.Body.String(), `"token":"tok-1"`) || !strings.Contains(w.Body.String(), `"recipient":"m***@example.com"`) {
+			t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+		}
+
+		uc.EXPECT().CreateLink(gomock.Any(), "est-1", gomock.Any()).Return(usecase.IssuedApprovalLink{}, usecase.ErrInvalidRecipient)
+		w = send(r, http.MethodPost, "/v1/estimates/est-1/approval-links", `{"channel":"sms","recipient":"12"}`)
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "INVALID_RECIPIENT") {
+			t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("view hides internal data", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockIEstimateApprovalUseCase(ctrl)
+		r := newEstimateApprovalRouter(uc)
+
+		uc.EXPECT().View(gomock.Any(), "tok-1").Return(usecase.ApprovalView{
+			Link:     link,
+			Estimate: entities.Estimate{ID: "est-1", OSID: "os-1", Price: 150, Status: entities.EstimateStatusPendente, CustomerID: "cust-1"},
+		}, nil)
+		uc.EXPECT().View(gomock.Any(), "forged").Return(usecase.ApprovalView{}, usecase.ErrInvalidApprovalToken)
+
+		w := send(r, http.MethodGet, "/v1/approval/tok-1", "")
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"price":150`) || strings.Contains(w.Body.String(), "cust-1") || strings.Contains(w.Body.String(), "maria@") {
+			t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+		}
+		if w := send(r, http.MethodGet, "/v1/approval/forged", ""); w.Code != http.StatusNotFound {
+			t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("send otp", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockIEstimateApprovalUseCase(ctrl)
+		r := newEstimateApprovalRouter(uc)
+
+		uc.EXPECT().SendOTP(gomock.Any(), "tok-1").Return(link, nil)
+		uc.EXPECT().SendOTP(gomock.Any(), "tok-2").Return(entities.ApprovalLink{}, usecase.ErrOTPSendLimit)
+
+		if w := send(r, http.MethodPost, "/v1/approval/tok-1/otp", ""); w.Code != http.StatusAccepted {
+			t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+		}
+		if w := send(r, http.MethodPost, "/v1/approval/tok-2/otp", ""); w.Code != http.StatusTooManyRequests {
+			t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("decide records evidence", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockIEstimateApprovalUseCase(ctrl)
+		r := newEstimateApprovalRouter(uc)
+
+		uc.EXPECT().Decide(gomock.Any(), "tok-1", usecase.CustomerDecision{
+			Action: "rejeitar", OTP: "123456", ReasonCode: "preco",
+			IP: "203.0.113.7", UserAgent: "Mozilla/5.0",
+		}).Return(entities.Estimate{ID: "est-1", Status: entities.EstimateStatusRejeitado}, nil)
+		w := send(r, http.MethodPost, "/v1/approval/tok-1/decision", `{"action":"rejeitar","otp":"123456","reason":{"code":"preco"}}`)
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"status":"rejeitado"`) {
+			t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+		}
+
+		cases := []struct {
+			err    error
+			status int
+			code   string
+		}{
+			{usecase.ErrInvalidOTP, http.StatusUnprocessableEntity, "INVALID_OTP"},
+			{usecase.ErrOTPLocked, http.StatusTooManyRequests, "OTP_LOCKED"},
+			{usecase.ErrApprovalLinkUsed, http.StatusConflict, "APPROVAL_LINK_USED"},
+			{usecase.ErrEstimateNotPending, http.StatusConflict, "ESTIMATE_NOT_PENDING"},
+			{usecase.ErrEstimateTransitionNotAllowed, http.StatusConflict, "ESTIMATE_TRANSITION_NOT_ALLOWED"},
+			{usecase.ErrUnknownEstimateItem, http.StatusUnprocessableEntity, "UNKNOWN_ESTIMATE_ITEM"},
+		}
+		for _, tc := range cases {
+			uc.EXPECT().Decide(gomock.Any(), "tok-1", gomock.Any()).Return(entities.Estimate{}, tc.err)
+			w := send(r, http.MethodPost, "/v1/approval/tok-1/decision", `{"action":"aprovar","otp":"000000","approved_items":["item-1"]}`)
+			if w.Code != tc.status || !strings.Contains(w.Body.String(), tc.code) {
+				t.Fatalf("%v: unexpected response %d: %s", tc.err, w.Code, w.Body.String())
+			}
+		}
+
+		if w := send(r, http.MethodPost, "/v1/approval/tok-1/decision", `{"action":"aprovar"}`); w.Code != http.StatusBadRequest {
+			t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
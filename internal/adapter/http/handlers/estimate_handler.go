@@ -5,6 +5,7 @@ import (
 	"errors"
 	request "mecanica_xpto/internal/adapter/http/dto/request"
 	response "mecanica_xpto/internal/adapter/http/dto/response"
+	"mecanica_xpto/internal/adapter/http/middlewares"
 	"mecanica_xpto/internal/domain/entities"
 	"mecanica_xpto/internal/usecase"
 	"mecanica_xpto/pkg"
@@ -46,13 +47,13 @@ func (h *EstimateHandler) CreateEstimate(c *gin.Context) {
 		return
 	}
 
-	price, err := payload.ResolvePrice()
+	pricing, err := estimatePricing(payload)
 	if err != nil {
 		c.JSON(errInvalidEstimatePayload.HTTPStatus, errInvalidEstimatePayload.ToHTTPError())
 		return
 	}
 
-	estimate, err := h.usecase.CalculateEstimate(c.Request.Context(), osID, price)
+	estimate, err := h.usecase.CalculateEstimate(c.Request.Context(), osID, pricing)
 	if err != nil {
 		appErr := mapEstimateError(err)
 		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
@@ -62,21 +63,191 @@ func (h *EstimateHandler) CreateEstimate(c *gin.Context) {
 	c.JSON(http.StatusCreated, response.FromEstimate(estimate))
 }
 
+// PreviewEstimate prices the CreateEstimate payload (pricing rules, discounts and taxes)
+// without creating the estimate.
+func (h *EstimateHandler) PreviewEstimate(c *gin.Context) {
+	var payload request.EstimateRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(errInvalidEstimatePayload.HTTPStatus, errInvalidEstimatePayload.ToHTTPError())
+		return
+	}
+	pricing, err := estimatePricing(payload)
+	if err != nil {
+		c.JSON(errInvalidEstimatePayload.HTTPStatus, errInvalidEstimatePayload.ToHTTPError())
+		return
+	}
+
+	estimate, err := h.usecase.PreviewEstimate(c.Request.Context(), payload.ResolveOSID(), pricing)
+	if err != nil {
+		appErr := mapEstimateError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromEstimate(estimate))
+}
+
+// RecalculateEstimate reprices a pending estimate from the same payload as CreateEstimate.
+// Discounts already applied are kept; coupons and discounts in the payload are added.
+func (h *EstimateHandler) RecalculateEstimate(c *gin.Context) {
+	var payload request.EstimateRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(errInvalidEstimatePayload.HTTPStatus, errInvalidEstimatePayload.ToHTTPError())
+		return
+	}
+	pricing, err := estimatePricing(payload)
+	if err != nil {
+		c.JSON(errInvalidEstimatePayload.HTTPStatus, errInvalidEstimatePayload.ToHTTPError())
+		return
+	}
+
+	estimate, err := h.usecase.UpdateEstimatePrice(c.Request.Context(), c.Param("estimate_id"), pricing)
+	if err != nil {
+		appErr := mapEstimateError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromEstimate(estimate))
+}
+
+// RecordActualHours reprices the labor services of an estimate with the hours worked,
+// once the service order finishes.
+func (h *EstimateHandler) RecordActualHours(c *gin.Context) {
+	var payload request.ActualHoursRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(errInvalidEstimatePayload.HTTPStatus, errInvalidEstimatePayload.ToHTTPError())
+		return
+	}
+
+	estimate, err := h.usecase.RecordActualHours(c.Request.Context(), c.Param("estimate_id"), payload.ResolveHours())
+	if err != nil {
+		appErr := mapEstimateError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromEstimate(estimate))
+}
+
+// RenewEstimate reissues a pending or expired estimate from the same payload as
+// CreateEstimate, priced now and valid for a new period.
+func (h *EstimateHandler) RenewEstimate(c *gin.Context) {
+	var payload request.EstimateRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(errInvalidEstimatePayload.HTTPStatus, errInvalidEstimatePayload.ToHTTPError())
+		return
+	}
+	pricing, err := estimatePricing(payload)
+	if err != nil {
+		c.JSON(errInvalidEstimatePayload.HTTPStatus, errInvalidEstimatePayload.ToHTTPError())
+		return
+	}
+
+	estimate, err := h.usecase.RenewEstimate(c.Request.Context(), c.Param("estimate_id"), pricing)
+	if err != nil {
+		appErr := mapEstimateError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromEstimate(estimate))
+}
+
+func estimatePricing(payload request.EstimateRequest) (usecase.EstimatePricing, error) {
+	price, err := payload.ResolvePrice()
+	if err != nil {
+		return usecase.EstimatePricing{}, err
+	}
+	return usecase.EstimatePricing{
+		Price:           price,
+		Items:           payload.ResolveItems(),
+		Municipality:    payload.Municipality,
+		CustomerID:      payload.CustomerID,
+		CustomerSegment: payload.CustomerSegment,
+		CouponCodes:     payload.Coupons,
+		ManualDiscounts: payload.ResolveManualDiscounts(),
+		ValidityDays:    payload.ValidityDays,
+	}, nil
+}
+
+// ApproveEstimate approves the estimate, or only the items in `approved_items` (by ID);
+// `actor` is optional.
 func (h *EstimateHandler) ApproveEstimate(c *gin.Context) {
-	h.patchEstimateStatusByRequest(c, h.usecase.ApproveByOSID)
+	h.patchEstimateStatusByRequest(c, func(ctx context.Context, osID string, payload request.EstimateRequest) (entities.Estimate, error) {
+		decision := statusDecision(payload)
+		decision.ItemIDs = payload.ApprovedItems
+		return h.usecase.ApproveByOSID(ctx, osID, decision)
+	})
 }
 
+// RejectEstimate rejects the estimate; `reason` ({"code", "text"}) and `actor` are optional.
 func (h *EstimateHandler) RejectEstimate(c *gin.Context) {
-	h.patchEstimateStatusByRequest(c, h.usecase.RejectByOSID)
+	h.patchEstimateStatusByRequest(c, func(ctx context.Context, osID string, payload request.EstimateRequest) (entities.Estimate, error) {
+		return h.usecase.RejectByOSID(ctx, osID, statusDecision(payload))
+	})
 }
 
+// CancelEstimate cancels the estimate; `reason` ({"code", "text"}) and `actor` are optional.
 func (h *EstimateHandler) CancelEstimate(c *gin.Context) {
-	h.patchEstimateStatusByRequest(c, h.usecase.CancelByOSID)
+	h.patchEstimateStatusByRequest(c, func(ctx context.Context, osID string, payload request.EstimateRequest) (entities.Estimate, error) {
+		return h.usecase.CancelByOSID(ctx, osID, statusDecision(payload))
+	})
+}
+
+// ListReasons returns the reason catalog of rejections and cancellations.
+func (h *EstimateHandler) ListReasons(c *gin.Context) {
+	c.JSON(http.StatusOK, response.FromReasonCatalog(h.usecase.ReasonCatalog()))
+}
+
+// ListInternalApprovals returns the estimates waiting for a manager's sign-off, oldest
+// request first.
+func (h *EstimateHandler) ListInternalApprovals(c *gin.Context) {
+	estimates, err := h.usecase.ListAwaitingInternalApproval(c.Request.Context())
+	if err != nil {
+		appErr := mapEstimateError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromEstimates(estimates))
+}
+
+// DecideInternalApproval records the sign-off of the admin actor (the manager) on an
+// estimate awaiting internal approval.
+func (h *EstimateHandler) DecideInternalApproval(c *gin.Context) {
+	var payload request.InternalApprovalRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(errInvalidEstimatePayload.HTTPStatus, errInvalidEstimatePayload.ToHTTPError())
+		return
+	}
+
+	estimate, err := h.usecase.DecideInternalApproval(c.Request.Context(), c.Param("estimate_id"), usecase.InternalDecision{
+		Action:   payload.Action,
+		Approver: middlewares.AdminActor(c),
+		Comment:  payload.Comment,
+	})
+	if err != nil {
+		appErr := mapEstimateError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromEstimate(estimate))
+}
+
+func statusDecision(payload request.EstimateRequest) usecase.StatusDecision {
+	d := usecase.StatusDecision{Actor: payload.Actor}
+	if payload.Reason != nil {
+		d.ReasonCode = payload.Reason.Code
+		d.ReasonText = payload.Reason.Text
+	}
+	return d
 }
 
 func (h *EstimateHandler) patchEstimateStatusByRequest(
 	c *gin.Context,
-	updater func(ctx context.Context, osID string) (entities.Estimate, error),
+	updater func(ctx context.Context, osID string, payload request.EstimateRequest) (entities.Estimate, error),
 ) {
 	var payload request.EstimateRequest
 	if err := c.ShouldBindJSON(&payload); err != nil {
@@ -90,7 +261,7 @@ func (h *EstimateHandler) patchEstimateStatusByRequest(
 		return
 	}
 
-	estimate, err := updater(c.Request.Context(), osID)
+	estimate, err := updater(c.Request.Context(), osID, payload)
 	if err != nil {
 		appErr := mapEstimateError(err)
 		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
@@ -101,6 +272,19 @@ func (h *EstimateHandler) patchEstimateStatusByRequest(
 }
 
 func mapEstimateError(err error) *pkg.AppError {
+	var deviationErr *usecase.CatalogDeviationError
+	if errors.As(err, &deviationErr) {
+		details := make([]string, 0, len(deviationErr.Deviations))
+		for _, d := range deviationErr.Deviations {
+			item := d.Code
+			if item == "" {
+				item = d.Name
+			}
+			details = append(details, item+": "+string(d.Reason))
+		}
+		return pkg.NewDomainErrorSimple("CATALOG_DEVIATION", "Estimate items do not match the price catalog", http.StatusUnprocessableEntity).WithDetails(details...)
+	}
+
 	switch {
 	case errors.Is(err, usecase.ErrInvalidOSID), errors.Is(err, usecase.ErrInvalidEstimateID), errors.Is(err, usecase.ErrInvalidEstimateVal):
 		return pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
@@ -108,6 +292,48 @@ func mapEstimateError(err error) *pkg.AppError {
 		return pkg.NewDomainErrorSimple("ESTIMATE_ALREADY_EXISTS", "Estimate already exists for this OS", http.StatusConflict)
 	case errors.Is(err, usecase.ErrEstimateNotFound):
 		return pkg.NewDomainErrorSimple("ESTIMATE_NOT_FOUND", "Estimate not found", http.StatusNotFound)
+	case errors.Is(err, usecase.ErrEstimateNotPending):
+		return pkg.NewDomainErrorSimple("ESTIMATE_NOT_PENDING", "Only pending estimates can be recalculated or approved by item", http.StatusConflict)
+	case errors.Is(err, usecase.ErrEstimateTransitionNotAllowed):
+		return pkg.NewDomainErrorSimple("ESTIMATE_TRANSITION_NOT_ALLOWED", "Estimate status transition not allowed", http.StatusConflict)
+	case errors.Is(err, usecase.ErrUnknownEstimateItem):
+		return pkg.NewDomainErrorSimple("UNKNOWN_ESTIMATE_ITEM", "Approved items must be items of the estimate", http.StatusUnprocessableEntity).WithDetails("approved_items")
+	case errors.Is(err, usecase.ErrEstimateExpired):
+		return pkg.NewDomainErrorSimple("ESTIMATE_EXPIRED", "Estimate validity ended; renew it first", http.StatusConflict)
+	case errors.Is(err, usecase.ErrInvalidValidity):
+		return pkg.NewDomainErrorSimple("INVALID_VALIDITY", "Validity must be a positive number of days", http.StatusBadRequest).WithDetails("validity_days")
+	case errors.Is(err, usecase.ErrUnknownReason):
+		return pkg.NewDomainErrorSimple("UNKNOWN_REASON", "Reason code is not in the reason catalog", http.StatusUnprocessableEntity).WithDetails("reason.code")
+	case errors.Is(err, usecase.ErrInvalidReasonText):
+		return pkg.NewDomainErrorSimple("INVALID_REASON_TEXT", "Reason text is required for this code and limited to 500 characters", http.StatusBadRequest).WithDetails("reason.text")
+	case errors.Is(err, usecase.ErrEstimateClosed):
+		return pkg.NewDomainErrorSimple("ESTIMATE_CLOSED", "Estimate was rejected or canceled", http.StatusConflict)
+	case errors.Is(err, usecase.ErrUnknownSkillTier):
+		return pkg.NewDomainErrorSimple("UNKNOWN_SKILL_TIER", "No hourly rate configured for the skill tier", http.StatusUnprocessableEntity).WithDetails("skill_tier")
+	case errors.Is(err, usecase.ErrInvalidLaborHours):
+		return pkg.NewDomainErrorSimple("INVALID_LABOR_HOURS", "Hours must be positive and refer to labor services", http.StatusBadRequest).WithDetails("hours", "id")
+	case errors.Is(err, usecase.ErrInternalApprovalPending):
+		return pkg.NewDomainErrorSimple("INTERNAL_APPROVAL_PENDING", "Estimate awaits a manager's internal approval", http.StatusConflict)
+	case errors.Is(err, usecase.ErrNoInternalApprovalPending):
+		return pkg.NewDomainErrorSimple("NO_INTERNAL_APPROVAL_PENDING", "Estimate has no internal approval waiting for a decision", http.StatusConflict)
+	case errors.Is(err, usecase.ErrInvalidInternalDecision):
+		return pkg.NewDomainErrorSimple("INVALID_INTERNAL_DECISION", "Action must be aprovar or rejeitar; rejecting requires a comment", http.StatusBadRequest).WithDetails("action", "comment")
+	case errors.Is(err, entities.ErrEstimateChanged):
+		return pkg.NewDomainErrorSimple("ESTIMATE_CHANGED", "Estimate changed concurrently, retry", http.StatusConflict)
+	case errors.Is(err, usecase.ErrInvalidDiscount):
+		return pkg.NewDomainErrorSimple("INVALID_DISCOUNT", "Discounts need a valid rule, a reason and the approver", http.StatusBadRequest)
+	case errors.Is(err, usecase.ErrDiscountNotApplicable):
+		return pkg.NewDomainErrorSimple("DISCOUNT_NOT_APPLICABLE", "Discount does not apply to any estimate item", http.StatusUnprocessableEntity)
+	case errors.Is(err, usecase.ErrDiscountExceedsPrice):
+		return pkg.NewDomainErrorSimple("DISCOUNT_EXCEEDS_PRICE", "Discounts exceed the estimate price", http.StatusUnprocessableEntity)
+	case errors.Is(err, usecase.ErrCouponNotFound):
+		return pkg.NewDomainErrorSimple("COUPON_NOT_FOUND", "Coupon not found", http.StatusNotFound)
+	case errors.Is(err, usecase.ErrCouponNeedsCustomer):
+		return pkg.NewDomainErrorSimple("COUPON_REQUIRES_CUSTOMER", "Coupon is limited per customer; customer_id is required", http.StatusBadRequest)
+	case errors.Is(err, entities.ErrCouponUnavailable):
+		return pkg.NewDomainErrorSimple("COUPON_UNAVAILABLE", "Coupon is inactive, expired or out of redemptions", http.StatusUnprocessableEntity)
+	case errors.Is(err, entities.ErrCouponCustomerLimit):
+		return pkg.NewDomainErrorSimple("COUPON_CUSTOMER_LIMIT", "Customer already used this coupon the allowed number of times", http.StatusUnprocessableEntity)
 	default:
 		return pkg.NewDomainError("INTERNAL_ERROR", "An internal error occurred", err, http.StatusInternalServerError)
 	}
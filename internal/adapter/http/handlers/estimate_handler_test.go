@@ -2,10 +2,12 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -86,7 +88,7 @@ func TestEstimateHandler_CreateEstimate(t *testing.T) {
 		r := gin.New()
 		r.POST("/v1/estimates", h.CreateEstimate)
 
-		uc.EXPECT().CalculateEstimate(gomock.Any(), "os-1", 10.0).Return(entities.Estimate{}, usecase.ErrEstimateAlreadyExists)
+		uc.EXPECT().CalculateEstimate(gomock.Any(), "os-1", gomock.Any()).Return(entities.Estimate{}, usecase.ErrEstimateAlreadyExists)
 
 		req := httptest.NewRequest(http.MethodPost, "/v1/estimates", bytes.NewBufferString(`{"service_order_id":"os-1","services":[{"price":10}]}`))
 		req.Header.Set("Content-Type", "application/json")
@@ -108,9 +110,15 @@ func TestEstimateHandler_CreateEstimate(t *testing.T) {
 		r.POST("/v1/estimates", h.CreateEstimate)
 
 		now := time.Now().UTC()
-		uc.EXPECT().CalculateEstimate(gomock.Any(), "os-1", 10.0).Return(entities.Estimate{ID: "est-1", OSID: "os-1", Price: 10, Status: entities.EstimateStatusPendente, CreatedAt: now, UpdatedAt: now}, nil)
-
-		req := httptest.NewRequest(http.MethodPost, "/v1/estimates", bytes.NewBufferString(`{"service_order_id":"os-1","services":[{"price":10}]}`))
+		uc.EXPECT().CalculateEstimate(gomock.Any(), "os-1", gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ string, p usecase.EstimatePricing) (entities.Estimate, error) {
+				if p.Price != 10 || p.CustomerID != "cust-1" || len(p.CouponCodes) != 1 || len(p.ManualDiscounts) != 1 || p.ManualDiscounts[0].Reason != "fidelidade" {
+					t.Fatalf("unexpected pricing: %+v", p)
+				}
+				return entities.Estimate{ID: "est-1", OSID: "os-1", Price: 10, Status: entities.EstimateStatusPendente, CreatedAt: now, UpdatedAt: now}, nil
+			})
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/estimates", bytes.NewBufferString(`{"service_order_id":"os-1","services":[{"price":10}],"customer_id":"cust-1","coupons":["PROMO10"],"discounts":[{"type":"fixo","value":1,"scope":"orcamento","reason":"fidelidade","approved_by":"gerente"}]}`))
 		req.Header.Set("Content-Type", "application/json")
 		w := httptest.NewRecorder()
 		r.ServeHTTP(w, req)
@@ -142,7 +150,7 @@ func TestEstimateHandler_PatchStatus(t *testing.T) {
 		h := NewEstimateHandler(uc)
 		r, path := build(http.MethodPatch, "/v1/estimates/approve", h.ApproveEstimate)
 
-		uc.EXPECT().ApproveByOSID(gomock.Any(), "os-1").Return(entities.Estimate{ID: "est-1", OSID: "os-1", Status: entities.EstimateStatusAprovado}, nil)
+		uc.EXPECT().ApproveByOSID(gomock.Any(), "os-1", usecase.StatusDecision{}).Return(entities.Estimate{ID: "est-1", OSID: "os-1", Status: entities.EstimateStatusAprovado}, nil)
 
 		req := httptest.NewRequest(http.MethodPatch, path, bytes.NewBufferString(`{"service_order_id":"os-1","services":[{"price":1}]}`))
 		req.Header.Set("Content-Type", "application/json")
@@ -153,6 +161,41 @@ func TestEstimateHandler_PatchStatus(t *testing.T) {
 		}
 	})
 
+	t.Run("approve items", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockIEstimateUseCase(ctrl)
+		h := NewEstimateHandler(uc)
+		r, path := build(http.MethodPatch, "/v1/estimates/approve", h.ApproveEstimate)
+
+		uc.EXPECT().ApproveByOSID(gomock.Any(), "os-1", usecase.StatusDecision{ItemIDs: []string{"brk-1"}}).Return(entities.Estimate{
+			ID: "est-1", OSID: "os-1", Status: entities.EstimateStatusAprovado, Price: 270,
+			Items:         []entities.EstimateItem{{ID: "brk-1", Name: "Freios", Total: 300}},
+			DeclinedItems: []entities.EstimateItem{{ID: "tyr-1", Name: "Pneus", Total: 800}},
+		}, nil)
+
+		req := httptest.NewRequest(http.MethodPatch, path, bytes.NewBufferString(`{"service_order_id":"os-1","approved_items":["brk-1"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", w.Code)
+		}
+		var body struct {
+			PartiallyApproved bool `json:"partially_approved"`
+			Items             []struct {
+				ID string `json:"id"`
+			} `json:"items"`
+			DeclinedItems []struct {
+				ID string `json:"id"`
+			} `json:"declined_items"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		if !body.PartiallyApproved || len(body.Items) != 1 || body.Items[0].ID != "brk-1" || len(body.DeclinedItems) != 1 {
+			t.Fatalf("unexpected body: %s", w.Body.String())
+		}
+	})
+
 	t.Run("reject invalid json", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
@@ -169,6 +212,29 @@ func TestEstimateHandler_PatchStatus(t *testing.T) {
 		}
 	})
 
+	t.Run("reject with reason", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockIEstimateUseCase(ctrl)
+		h := NewEstimateHandler(uc)
+		r, path := build(http.MethodPatch, "/v1/estimates/reject", h.RejectEstimate)
+
+		reason := &entities.StatusReason{Code: "preco", Label: "Preço acima do esperado"}
+		uc.EXPECT().RejectByOSID(gomock.Any(), "os-1", usecase.StatusDecision{Actor: "cliente", ReasonCode: "preco", ReasonText: "achou caro"}).
+			Return(entities.Estimate{ID: "est-1", OSID: "os-1", Status: entities.EstimateStatusRejeitado, Reason: reason, DecidedBy: "cliente"}, nil)
+
+		req := httptest.NewRequest(http.MethodPatch, path, bytes.NewBufferString(`{"service_order_id":"os-1","services":[{"price":1}],"actor":"cliente","reason":{"code":"preco","text":"achou caro"}}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", w.Code)
+		}
+		if !strings.Contains(w.Body.String(), `"decided_by":"cliente"`) || !strings.Contains(w.Body.String(), `"code":"preco"`) {
+			t.Fatalf("unexpected body: %s", w.Body.String())
+		}
+	})
+
 	t.Run("cancel missing os id", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
@@ -192,7 +258,7 @@ func TestEstimateHandler_PatchStatus(t *testing.T) {
 		h := NewEstimateHandler(uc)
 		r, path := build(http.MethodPatch, "/v1/estimates/approve", h.ApproveEstimate)
 
-		uc.EXPECT().ApproveByOSID(gomock.Any(), "os-1").Return(entities.Estimate{}, usecase.ErrEstimateNotFound)
+		uc.EXPECT().ApproveByOSID(gomock.Any(), "os-1", usecase.StatusDecision{}).Return(entities.Estimate{}, usecase.ErrEstimateNotFound)
 
 		req := httptest.NewRequest(http.MethodPatch, path, bytes.NewBufferString(`{"service_order_id":"os-1","services":[{"price":1}]}`))
 		req.Header.Set("Content-Type", "application/json")
@@ -204,6 +270,191 @@ func TestEstimateHandler_PatchStatus(t *testing.T) {
 	})
 }
 
+func TestEstimateHandler_RecalculateEstimate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	uc := mocks.NewMockIEstimateUseCase(ctrl)
+	h := NewEstimateHandler(uc)
+
+	r := gin.New()
+	r.POST("/v1/estimates/:estimate_id/recalculate", h.RecalculateEstimate)
+
+	uc.EXPECT().UpdateEstimatePrice(gomock.Any(), "est-1", gomock.Any()).Return(entities.Estimate{}, usecase.ErrEstimateNotPending)
+	req := httptest.NewRequest(http.MethodPost, "/v1/estimates/est-1/recalculate", bytes.NewBufferString(`{"service_order_id":"os-1","services":[{"price":20}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+
+	uc.EXPECT().UpdateEstimatePrice(gomock.Any(), "est-1", usecase.EstimatePricing{Price: 20, Items: []entities.EstimateItem{
+		{Kind: entities.EstimateItemServico, Quantity: 1, UnitPrice: 20, Total: 20},
+	}}).Return(entities.Estimate{ID: "est-1", Subtotal: 20, Price: 20, Status: entities.EstimateStatusPendente}, nil)
+	req = httptest.NewRequest(http.MethodPost, "/v1/estimates/est-1/recalculate", bytes.NewBufferString(`{"service_order_id":"os-1","services":[{"price":20}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestEstimateHandler_RecordActualHours(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	uc := mocks.NewMockIEstimateUseCase(ctrl)
+	h := NewEstimateHandler(uc)
+
+	r := gin.New()
+	r.POST("/v1/estimates/:estimate_id/actual-hours", h.RecordActualHours)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/estimates/est-1/actual-hours", bytes.NewBufferString(`{"services":[{"hours":3}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+
+	uc.EXPECT().RecordActualHours(gomock.Any(), "est-1", map[string]float64{"srv-1": 3}).Return(entities.Estimate{ID: "est-1", Price: 480, Items: []entities.EstimateItem{
+		{ID: "srv-1", Kind: entities.EstimateItemServico, Hours: 2.5, ActualHours: 3, SkillTier: "senior", HourlyRate: 160, Quantity: 1, UnitPrice: 480, Total: 480},
+	}}, nil)
+	req = httptest.NewRequest(http.MethodPost, "/v1/estimates/est-1/actual-hours", bytes.NewBufferString(`{"services":[{"id":"srv-1","hours":3}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !bytes.Contains(w.Body.Bytes(), []byte(`"actual_hours":3,"skill_tier":"senior","hourly_rate":160`)) {
+		t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+	}
+
+	uc.EXPECT().RecordActualHours(gomock.Any(), "est-2", gomock.Any()).Return(entities.Estimate{}, usecase.ErrEstimateClosed)
+	req = httptest.NewRequest(http.MethodPost, "/v1/estimates/est-2/actual-hours", bytes.NewBufferString(`{"services":[{"id":"srv-1","hours":3}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+}
+
+func TestEstimateHandler_RenewEstimate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	uc := mocks.NewMockIEstimateUseCase(ctrl)
+	h := NewEstimateHandler(uc)
+
+	r := gin.New()
+	r.POST("/v1/estimates/:estimate_id/renew", h.RenewEstimate)
+
+	expiresAt := time.Date(2026, 5, 20, 9, 0, 0, 0, time.UTC)
+	uc.EXPECT().RenewEstimate(gomock.Any(), "est-1", gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, p usecase.EstimatePricing) (entities.Estimate, error) {
+			if p.Price != 120 || p.ValidityDays != 7 {
+				t.Fatalf("unexpected pricing: %+v", p)
+			}
+			return entities.Estimate{ID: "est-1", Status: entities.EstimateStatusPendente, Price: 120, ExpiresAt: &expiresAt, RenewedAt: &expiresAt}, nil
+		})
+	body := `{"service_order_id":"os-1","validity_days":7,"services":[{"name":"Revisão","description":"x","price":120}]}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/estimates/est-1/renew", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !bytes.Contains(w.Body.Bytes(), []byte(`"expires_at":"2026-05-20T09:00:00Z"`)) {
+		t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+	}
+
+	uc.EXPECT().RenewEstimate(gomock.Any(), "est-2", gomock.Any()).Return(entities.Estimate{}, usecase.ErrEstimateNotPending)
+	req = httptest.NewRequest(http.MethodPost, "/v1/estimates/est-2/renew", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+}
+
+func TestEstimateHandler_InternalApproval(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	uc := mocks.NewMockIEstimateUseCase(ctrl)
+	h := NewEstimateHandler(uc)
+
+	r := gin.New()
+	r.GET("/v1/admin/estimates/internal-approvals", h.ListInternalApprovals)
+	r.POST("/v1/admin/estimates/:estimate_id/internal-approval", h.DecideInternalApproval)
+
+	requestedAt := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	uc.EXPECT().ListAwaitingInternalApproval(gomock.Any()).Return([]entities.Estimate{{
+		ID: "est-1", Status: entities.EstimateStatusAguardandoAprovacao, Price: 1500,
+		InternalApproval: &entities.InternalApproval{Threshold: 1000, Price: 1500, RequestedAt: requestedAt},
+	}}, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/estimates/internal-approvals", nil))
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Body.String(), "[") ||
+		!strings.Contains(w.Body.String(), `"status":"aguardando_aprovacao"`) || !strings.Contains(w.Body.String(), `"threshold":1000`) {
+		t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+	}
+
+	decide := func(id, actor, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/v1/admin/estimates/"+id+"/internal-approval", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Admin-Actor", actor)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	uc.EXPECT().DecideInternalApproval(gomock.Any(), "est-1", usecase.InternalDecision{Action: "rejeitar", Approver: "gerente", Comment: "revisar peças"}).
+		Return(entities.Estimate{ID: "est-1", Status: entities.EstimateStatusAguardandoAprovacao}, nil)
+	if w := decide("est-1", "gerente", `{"action":"rejeitar","comment":"revisar peças"}`); w.Code != http.StatusOK {
+		t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+	}
+
+	uc.EXPECT().DecideInternalApproval(gomock.Any(), "est-1", gomock.Any()).Return(entities.Estimate{}, usecase.ErrInvalidInternalDecision)
+	if w := decide("est-1", "gerente", `{"action":"rejeitar"}`); w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "INVALID_INTERNAL_DECISION") {
+		t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+	}
+	uc.EXPECT().DecideInternalApproval(gomock.Any(), "est-2", gomock.Any()).Return(entities.Estimate{}, usecase.ErrNoInternalApprovalPending)
+	if w := decide("est-2", "gerente", `{"action":"aprovar"}`); w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), "NO_INTERNAL_APPROVAL_PENDING") {
+		t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+	}
+	if w := decide("est-1", "gerente", `{}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestEstimateHandler_PreviewEstimate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	uc := mocks.NewMockIEstimateUseCase(ctrl)
+	h := NewEstimateHandler(uc)
+
+	r := gin.New()
+	r.POST("/v1/estimates/preview", h.PreviewEstimate)
+
+	uc.EXPECT().PreviewEstimate(gomock.Any(), "os-1", gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, p usecase.EstimatePricing) (entities.Estimate, error) {
+			if p.CustomerSegment != "frota" || len(p.Items) != 1 || p.Items[0].Category != "freios" || p.Items[0].Code != "PAS-1" {
+				t.Fatalf("unexpected pricing: %+v", p)
+			}
+			return entities.Estimate{OSID: "os-1", Subtotal: 57, Price: 57, PricingVersion: "v1", AdjustmentTotal: 7,
+				Adjustments: []entities.PricingAdjustment{{RuleID: "markup", Amount: 7}}}, nil
+		})
+	body := `{"service_order_id":"os-1","customer_segment":"frota","parts_supplies":[{"name":"Pastilha","description":"x","price":50,"quantity":1,"category":"freios","code":" pas-1"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/estimates/preview", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !bytes.Contains(w.Body.Bytes(), []byte(`"rule_id":"markup"`)) {
+		t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+	}
+}
+
 func TestMapEstimateError(t *testing.T) {
 	if got := mapEstimateError(usecase.ErrInvalidOSID); got.HTTPStatus != http.StatusBadRequest {
 		t.Fatalf("expected 400")
@@ -220,6 +471,38 @@ func TestMapEstimateError(t *testing.T) {
 	if got := mapEstimateError(usecase.ErrEstimateNotFound); got.HTTPStatus != http.StatusNotFound {
 		t.Fatalf("expected 404")
 	}
+	if got := mapEstimateError(entities.ErrCouponUnavailable); got.HTTPStatus != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422")
+	}
+	if got := mapEstimateError(entities.ErrEstimateChanged); got.HTTPStatus != http.StatusConflict {
+		t.Fatalf("expected 409")
+	}
+	if got := mapEstimateError(usecase.ErrUnknownSkillTier); got.HTTPStatus != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422")
+	}
+	if got := mapEstimateError(usecase.ErrEstimateExpired); got.HTTPStatus != http.StatusConflict || got.Code != "ESTIMATE_EXPIRED" {
+		t.Fatalf("expected 409 ESTIMATE_EXPIRED")
+	}
+	if got := mapEstimateError(usecase.ErrInternalApprovalPending); got.HTTPStatus != http.StatusConflict || got.Code != "INTERNAL_APPROVAL_PENDING" {
+		t.Fatalf("expected 409 INTERNAL_APPROVAL_PENDING")
+	}
+	deviations := &usecase.CatalogDeviationError{Deviations: []entities.CatalogDeviation{
+		{Code: "FIL-1", Name: "Filtro", Reason: entities.CatalogPriceDeviation},
+		{Name: "Abraçadeira", Reason: entities.CatalogNoCode},
+	}}
+	if got := mapEstimateError(deviations); got.HTTPStatus != http.StatusUnprocessableEntity || got.Code != "CATALOG_DEVIATION" ||
+		len(got.Details) != 2 || got.Details[1] != "Abraçadeira: sem_codigo" {
+		t.Fatalf("unexpected catalog deviation error: %+v", got)
+	}
+	if got := mapEstimateError(usecase.ErrUnknownEstimateItem); got.HTTPStatus != http.StatusUnprocessableEntity || got.Code != "UNKNOWN_ESTIMATE_ITEM" {
+		t.Fatalf("expected 422 UNKNOWN_ESTIMATE_ITEM")
+	}
+	if got := mapEstimateError(usecase.ErrUnknownReason); got.HTTPStatus != http.StatusUnprocessableEntity || got.Code != "UNKNOWN_REASON" {
+		t.Fatalf("expected 422 UNKNOWN_REASON")
+	}
+	if got := mapEstimateError(usecase.ErrInvalidReasonText); got.HTTPStatus != http.StatusBadRequest || got.Code != "INVALID_REASON_TEXT" {
+		t.Fatalf("expected 400 INVALID_REASON_TEXT")
+	}
 	if got := mapEstimateError(errors.New("x")); got.HTTPStatus != http.StatusInternalServerError {
 		t.Fatalf("expected 500")
 	}
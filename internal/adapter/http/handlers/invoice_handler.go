@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	request "mecanica_xpto/internal/adapter/http/dto/request"
+	response "mecanica_xpto/internal/adapter/http/dto/response"
+	"mecanica_xpto/internal/adapter/http/middlewares"
+	"mecanica_xpto/internal/usecase"
+	"mecanica_xpto/pkg"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InvoiceHandler issues and serves invoices (faturas) of approved estimates.
+// VoidInvoice and AddCreditNote are privileged and must be routed behind the admin middleware.
+
+type InvoiceHandler struct {
+	usecase usecase.IInvoiceUseCase
+}
+
+func NewInvoiceHandler(uc usecase.IInvoiceUseCase) *InvoiceHandler {
+	return &InvoiceHandler{usecase: uc}
+}
+
+// IssueInvoice issues the invoice of an approved estimate. It is idempotent: an estimate
+// already invoiced gets its active invoice back.
+func (h *InvoiceHandler) IssueInvoice(c *gin.Context) {
+	var payload request.InvoiceIssueRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		appErr := pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	inv, err := h.usecase.Issue(c.Request.Context(), payload.EstimateID, "")
+	if err != nil {
+		log.Printf("[invoice][handler] issue failed estimate_id=%s err=%v", payload.EstimateID, err)
+		appErr := mapInvoiceError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromInvoice(inv))
+}
+
+// CloseServiceOrder is called by os-service-api when a service order (OS) is closed: it
+// issues the invoice of the approved estimate of the OS. Like IssueInvoice, it is
+// idempotent.
+func (h *InvoiceHandler) CloseServiceOrder(c *gin.Context) {
+	var payload request.EstimateRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		appErr := pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	osID := payload.ResolveOSID()
+	inv, err := h.usecase.IssueForServiceOrder(c.Request.Context(), osID, payload.Actor)
+	if err != nil {
+		log.Printf("[invoice][handler] service order close failed os_id=%s err=%v", osID, err)
+		appErr := mapInvoiceError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromInvoice(inv))
+}
+
+// GetInvoice returns an invoice by ID.
+func (h *InvoiceHandler) GetInvoice(c *gin.Context) {
+	inv, err := h.usecase.GetByID(c.Request.Context(), c.Param("invoice_id"))
+	if err != nil {
+		appErr := mapInvoiceError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromInvoice(inv))
+}
+
+// GetEstimateInvoice returns the active invoice of an estimate.
+func (h *InvoiceHandler) GetEstimateInvoice(c *gin.Context) {
+	inv, err := h.usecase.GetByEstimateID(c.Request.Context(), c.Param("estimate_id"))
+	if err != nil {
+		appErr := mapInvoiceError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromInvoice(inv))
+}
+
+// VoidInvoice cancels an invoice without applied payments.
+func (h *InvoiceHandler) VoidInvoice(c *gin.Context) {
+	var payload request.InvoiceVoidRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		appErr := pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	invoiceID := c.Param("invoice_id")
+	actor := middlewares.AdminActor(c)
+	inv, err := h.usecase.Void(c.Request.Context(), invoiceID, payload.Reason, actor)
+	if err != nil {
+		log.Printf("[invoice][handler] void failed invoice_id=%s actor=%s err=%v", invoiceID, actor, err)
+		appErr := mapInvoiceError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromInvoice(inv))
+}
+
+// AddCreditNote credits an amount on an invoice.
+func (h *InvoiceHandler) AddCreditNote(c *gin.Context) {
+	var payload request.CreditNoteRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		appErr := pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	invoiceID := c.Param("invoice_id")
+	actor := middlewares.AdminActor(c)
+	inv, err := h.usecase.AddCreditNote(c.Request.Context(), invoiceID, payload.Amount, payload.Reason, actor)
+	if err != nil {
+		log.Printf("[invoice][handler] credit note failed invoice_id=%s actor=%s err=%v", invoiceID, actor, err)
+		appErr := mapInvoiceError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.FromInvoice(inv))
+}
+
+func mapInvoiceError(err error) *pkg.AppError {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidInvoiceID),
+		errors.Is(err, usecase.ErrInvalidInvoiceEstimateID),
+		errors.Is(err, usecase.ErrInvalidOSID),
+		errors.Is(err, usecase.ErrInvalidInvoiceVoid):
+		return pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
+	case errors.Is(err, usecase.ErrInvalidCreditNote):
+		return pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest).WithDetails("amount")
+	case errors.Is(err, usecase.ErrInvoiceNotFound):
+		return pkg.NewDomainErrorSimple("INVOICE_NOT_FOUND", "Invoice not found", http.StatusNotFound)
+	case errors.Is(err, usecase.ErrEstimateNotFound):
+		return pkg.NewDomainErrorSimple("ESTIMATE_NOT_FOUND", "Estimate not found", http.StatusNotFound)
+	case errors.Is(err, usecase.ErrInvoiceEstimateNotApproved):
+		return pkg.NewDomainErrorSimple("ESTIMATE_NOT_APPROVED", "Estimate not approved", http.StatusConflict)
+	case errors.Is(err, usecase.ErrInvoiceVoided):
+		return pkg.NewDomainErrorSimple("INVOICE_VOIDED", "Invoice voided", http.StatusConflict)
+	case errors.Is(err, usecase.ErrInvoiceNotVoidable):
+		return pkg.NewDomainErrorSimple("INVOICE_HAS_PAYMENTS", "Invoice has applied payments; issue a credit note", http.StatusConflict)
+	case errors.Is(err, usecase.ErrInvoiceConflict):
+		return pkg.NewDomainErrorSimple("INVOICE_CONFLICT", "Invoice changed concurrently", http.StatusConflict)
+	default:
+		return pkg.NewDomainError("INTERNAL_ERROR", "An internal error occurred", err, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"mecanica_xpto/internal/adapter/http/handlers/mocks"
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+)
+
+func TestInvoiceHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(t *testing.T) (*gin.Engine, *mocks.MockIInvoiceUseCase) {
+		ctrl := gomock.NewController(t)
+		uc := mocks.NewMockIInvoiceUseCase(ctrl)
+		h := NewInvoiceHandler(uc)
+		r := gin.New()
+		r.POST("/v1/invoices", h.IssueInvoice)
+		r.PATCH("/v1/estimates/close", h.CloseServiceOrder)
+		r.GET("/v1/invoices/:invoice_id", h.GetInvoice)
+		r.POST("/v1/admin/invoices/:invoice_id/void", h.VoidInvoice)
+		r.POST("/v1/admin/invoices/:invoice_id/credit-notes", h.AddCreditNote)
+		return r, uc
+	}
+
+	t.Run("issue", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().Issue(gomock.Any(), "est-1", "").Return(entities.Invoice{
+			ID: "inv-1", Number: 12, Status: entities.InvoiceStatusEmitida, Total: 200,
+			Payments: []entities.InvoicePayment{{PaymentID: "p1", Amount: 150}},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/invoices", strings.NewReader(`{"estimate_id":"est-1"}`)))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", w.Code)
+		}
+		var body struct {
+			Number  int64   `json:"number"`
+			Balance float64 `json:"balance"`
+			Paid    bool    `json:"paid"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		if body.Number != 12 || body.Balance != 50 || body.Paid {
+			t.Fatalf("unexpected body: %s", w.Body.String())
+		}
+	})
+
+	t.Run("service order closed", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().IssueForServiceOrder(gomock.Any(), "os-1", "os-service").Return(entities.Invoice{ID: "inv-1", Number: 13}, nil)
+		uc.EXPECT().IssueForServiceOrder(gomock.Any(), "os-2", "").Return(entities.Invoice{}, usecase.ErrInvoiceEstimateNotApproved)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPatch, "/v1/estimates/close", strings.NewReader(`{"service_order_id":"os-1","actor":"os-service"}`)))
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"number":13`) {
+			t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+		}
+
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPatch, "/v1/estimates/close", strings.NewReader(`{"service_order_id":"os-2"}`)))
+		if w.Code != http.StatusConflict {
+			t.Fatalf("expected 409, got %d", w.Code)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().GetByID(gomock.Any(), "inv-x").Return(entities.Invoice{}, usecase.ErrInvoiceNotFound)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/invoices/inv-x", nil))
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("void with payments", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().Void(gomock.Any(), "inv-1", "duplicada", gomock.Any()).Return(entities.Invoice{}, usecase.ErrInvoiceNotVoidable)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/admin/invoices/inv-1/void", strings.NewReader(`{"reason":"duplicada"}`)))
+		if w.Code != http.StatusConflict {
+			t.Fatalf("expected 409, got %d", w.Code)
+		}
+	})
+
+	t.Run("credit note", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().AddCreditNote(gomock.Any(), "inv-1", 25.5, "desconto", gomock.Any()).
+			Return(entities.Invoice{ID: "inv-1", Total: 100, CreditNotes: []entities.CreditNote{{Number: 1, Amount: 25.5}}}, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/admin/invoices/inv-1/credit-notes", strings.NewReader(`{"amount":25.5,"reason":"desconto"}`)))
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d", w.Code)
+		}
+
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/admin/invoices/inv-1/credit-notes", strings.NewReader(`{"reason":"desconto"}`)))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", w.Code)
+		}
+	})
+}
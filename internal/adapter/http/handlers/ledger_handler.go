@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	response "mecanica_xpto/internal/adapter/http/dto/response"
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase"
+	"mecanica_xpto/pkg"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LedgerHandler serves the balances of the double-entry ledger.
+// Privileged: must be routed behind the admin middleware.
+
+type LedgerHandler struct {
+	usecase usecase.ILedgerUseCase
+}
+
+func NewLedgerHandler(uc usecase.ILedgerUseCase) *LedgerHandler {
+	return &LedgerHandler{usecase: uc}
+}
+
+// GetAccountBalances returns the ledger-wide balances, of every account or of `?account`.
+func (h *LedgerHandler) GetAccountBalances(c *gin.Context) {
+	balances, err := h.usecase.AccountBalances(c.Request.Context(), entities.LedgerAccount(c.Query("account")))
+	if err != nil {
+		log.Printf("[payment][handler] ledger balances failed account=%s err=%v", c.Query("account"), err)
+		appErr := mapLedgerError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromLedgerBalances(balances))
+}
+
+// GetEstimateLedger returns the balances and entries of an estimate.
+func (h *LedgerHandler) GetEstimateLedger(c *gin.Context) {
+	ledger, err := h.usecase.EstimateLedger(c.Request.Context(), c.Param("estimate_id"))
+	if err != nil {
+		log.Printf("[payment][handler] estimate ledger failed estimate_id=%s err=%v", c.Param("estimate_id"), err)
+		appErr := mapLedgerError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromEstimateLedger(ledger))
+}
+
+func mapLedgerError(err error) *pkg.AppError {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidLedgerAccount):
+		return pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest).WithDetails("account")
+	case errors.Is(err, usecase.ErrInvalidLedgerEstimateID):
+		return pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
+	default:
+		return pkg.NewDomainError("INTERNAL_ERROR", "An internal error occurred", err, http.StatusInternalServerError)
+	}
+}
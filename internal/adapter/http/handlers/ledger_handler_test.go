@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"mecanica_xpto/internal/adapter/http/handlers/mocks"
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLedgerHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(t *testing.T) (*gin.Engine, *mocks.MockILedgerUseCase) {
+		ctrl := gomock.NewController(t)
+		uc := mocks.NewMockILedgerUseCase(ctrl)
+		h := NewLedgerHandler(uc)
+		r := gin.New()
+		r.GET("/v1/admin/ledger/accounts", h.GetAccountBalances)
+		r.GET("/v1/admin/ledger/estimates/:estimate_id", h.GetEstimateLedger)
+		return r, uc
+	}
+
+	t.Run("account balances", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().AccountBalances(gomock.Any(), entities.LedgerAccountRevenue).
+			Return([]entities.LedgerBalance{{Account: entities.LedgerAccountRevenue, Debits: 5000, Credits: 15050}}, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/ledger/accounts?account=revenue", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", w.Code)
+		}
+		var body struct {
+			Accounts []struct {
+				Account string  `json:"account"`
+				Balance float64 `json:"balance"`
+			} `json:"accounts"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		if len(body.Accounts) != 1 || body.Accounts[0].Balance != 100.5 {
+			t.Fatalf("unexpected body: %s", w.Body.String())
+		}
+	})
+
+	t.Run("estimate ledger", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().EstimateLedger(gomock.Any(), "est-1").Return(entities.EstimateLedger{
+			EstimateID: "est-1",
+			Entries: []entities.LedgerEntry{{ID: "pay-1-refund", Kind: entities.LedgerEntryRefund, PaymentID: "pay-1", Lines: []entities.LedgerLine{
+				{Account: entities.LedgerAccountRefunds, Debit: 1999},
+				{Account: entities.LedgerAccountProviderClearing, Credit: 1999},
+			}}},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/ledger/estimates/est-1", nil))
+		var body struct {
+			Entries []struct {
+				Lines []struct {
+					Debit  float64 `json:"debit"`
+					Credit float64 `json:"credit"`
+				} `json:"lines"`
+			} `json:"entries"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		if w.Code != http.StatusOK || len(body.Entries) != 1 || body.Entries[0].Lines[0].Debit != 19.99 || body.Entries[0].Lines[1].Credit != 19.99 {
+			t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().AccountBalances(gomock.Any(), gomock.Any()).Return(nil, usecase.ErrInvalidLedgerAccount)
+		uc.EXPECT().EstimateLedger(gomock.Any(), gomock.Any()).Return(entities.EstimateLedger{}, errors.New("ddb"))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/ledger/accounts?account=cash", nil))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", w.Code)
+		}
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/ledger/estimates/est-1", nil))
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("expected 500, got %d", w.Code)
+		}
+	})
+}
@@ -13,6 +13,7 @@ import (
 	context "context"
 	json "encoding/json"
 	entities "mecanica_xpto/internal/domain/entities"
+	usecase "mecanica_xpto/internal/usecase"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
@@ -42,6 +43,21 @@ func (m *MockIBillingPaymentUseCase) EXPECT() *MockIBillingPaymentUseCaseMockRec
 	return m.recorder
 }
 
+// ChangeStatus mocks base method.
+func (m *MockIBillingPaymentUseCase) ChangeStatus(ctx context.Context, id string, change usecase.PaymentStatusChange) (entities.BillingPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeStatus", ctx, id, change)
+	ret0, _ := ret[0].(entities.BillingPayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeStatus indicates an expected call of ChangeStatus.
+func (mr *MockIBillingPaymentUseCaseMockRecorder) ChangeStatus(ctx, id, change any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeStatus", reflect.TypeOf((*MockIBillingPaymentUseCase)(nil).ChangeStatus), ctx, id, change)
+}
+
 // CreateAndApprove mocks base method.
 func (m *MockIBillingPaymentUseCase) CreateAndApprove(ctx context.Context, estimateID string, mpPayload json.RawMessage) (entities.BillingPayment, error) {
 	m.ctrl.T.Helper()
@@ -72,17 +88,77 @@ func (mr *MockIBillingPaymentUseCaseMockRecorder) GetByID(ctx, id any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockIBillingPaymentUseCase)(nil).GetByID), ctx, id)
 }
 
+// History mocks base method.
+func (m *MockIBillingPaymentUseCase) History(ctx context.Context, id string) ([]entities.PaymentStatusEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, id)
+	ret0, _ := ret[0].([]entities.PaymentStatusEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockIBillingPaymentUseCaseMockRecorder) History(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockIBillingPaymentUseCase)(nil).History), ctx, id)
+}
+
+// LatestByEstimateID mocks base method.
+func (m *MockIBillingPaymentUseCase) LatestByEstimateID(ctx context.Context, estimateID string) (entities.BillingPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LatestByEstimateID", ctx, estimateID)
+	ret0, _ := ret[0].(entities.BillingPayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LatestByEstimateID indicates an expected call of LatestByEstimateID.
+func (mr *MockIBillingPaymentUseCaseMockRecorder) LatestByEstimateID(ctx, estimateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LatestByEstimateID", reflect.TypeOf((*MockIBillingPaymentUseCase)(nil).LatestByEstimateID), ctx, estimateID)
+}
+
 // ListByEstimateID mocks base method.
-func (m *MockIBillingPaymentUseCase) ListByEstimateID(ctx context.Context, estimateID string) ([]entities.BillingPayment, error) {
+func (m *MockIBillingPaymentUseCase) ListByEstimateID(ctx context.Context, estimateID string, page entities.PageRequest) (entities.Page[entities.BillingPayment], error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListByEstimateID", ctx, estimateID)
-	ret0, _ := ret[0].([]entities.BillingPayment)
+	ret := m.ctrl.Call(m, "ListByEstimateID", ctx, estimateID, page)
+	ret0, _ := ret[0].(entities.Page[entities.BillingPayment])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListByEstimateID indicates an expected call of ListByEstimateID.
-func (mr *MockIBillingPaymentUseCaseMockRecorder) ListByEstimateID(ctx, estimateID any) *gomock.Call {
+func (mr *MockIBillingPaymentUseCaseMockRecorder) ListByEstimateID(ctx, estimateID, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByEstimateID", reflect.TypeOf((*MockIBillingPaymentUseCase)(nil).ListByEstimateID), ctx, estimateID, page)
+}
+
+// ListByStatus mocks base method.
+func (m *MockIBillingPaymentUseCase) ListByStatus(ctx context.Context, filter usecase.PaymentListFilter, page entities.PageRequest) (entities.Page[entities.BillingPayment], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByStatus", ctx, filter, page)
+	ret0, _ := ret[0].(entities.Page[entities.BillingPayment])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByStatus indicates an expected call of ListByStatus.
+func (mr *MockIBillingPaymentUseCaseMockRecorder) ListByStatus(ctx, filter, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByStatus", reflect.TypeOf((*MockIBillingPaymentUseCase)(nil).ListByStatus), ctx, filter, page)
+}
+
+// Reveal mocks base method.
+func (m *MockIBillingPaymentUseCase) Reveal(ctx context.Context, id string) (entities.BillingPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reveal", ctx, id)
+	ret0, _ := ret[0].(entities.BillingPayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reveal indicates an expected call of Reveal.
+func (mr *MockIBillingPaymentUseCaseMockRecorder) Reveal(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByEstimateID", reflect.TypeOf((*MockIBillingPaymentUseCase)(nil).ListByEstimateID), ctx, estimateID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reveal", reflect.TypeOf((*MockIBillingPaymentUseCase)(nil).Reveal), ctx, id)
 }
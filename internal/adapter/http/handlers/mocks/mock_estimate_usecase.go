@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	entities "mecanica_xpto/internal/domain/entities"
+	usecase "mecanica_xpto/internal/usecase"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
@@ -42,48 +43,78 @@ func (m *MockIEstimateUseCase) EXPECT() *MockIEstimateUseCaseMockRecorder {
 }
 
 // ApproveByOSID mocks base method.
-func (m *MockIEstimateUseCase) ApproveByOSID(ctx context.Context, osID string) (entities.Estimate, error) {
+func (m *MockIEstimateUseCase) ApproveByOSID(ctx context.Context, osID string, decision usecase.StatusDecision) (entities.Estimate, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ApproveByOSID", ctx, osID)
+	ret := m.ctrl.Call(m, "ApproveByOSID", ctx, osID, decision)
 	ret0, _ := ret[0].(entities.Estimate)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ApproveByOSID indicates an expected call of ApproveByOSID.
-func (mr *MockIEstimateUseCaseMockRecorder) ApproveByOSID(ctx, osID any) *gomock.Call {
+func (mr *MockIEstimateUseCaseMockRecorder) ApproveByOSID(ctx, osID, decision any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveByOSID", reflect.TypeOf((*MockIEstimateUseCase)(nil).ApproveByOSID), ctx, osID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveByOSID", reflect.TypeOf((*MockIEstimateUseCase)(nil).ApproveByOSID), ctx, osID, decision)
 }
 
 // CalculateEstimate mocks base method.
-func (m *MockIEstimateUseCase) CalculateEstimate(ctx context.Context, osID string, price float64) (entities.Estimate, error) {
+func (m *MockIEstimateUseCase) CalculateEstimate(ctx context.Context, osID string, pricing usecase.EstimatePricing) (entities.Estimate, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CalculateEstimate", ctx, osID, price)
+	ret := m.ctrl.Call(m, "CalculateEstimate", ctx, osID, pricing)
 	ret0, _ := ret[0].(entities.Estimate)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CalculateEstimate indicates an expected call of CalculateEstimate.
-func (mr *MockIEstimateUseCaseMockRecorder) CalculateEstimate(ctx, osID, price any) *gomock.Call {
+func (mr *MockIEstimateUseCaseMockRecorder) CalculateEstimate(ctx, osID, pricing any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CalculateEstimate", reflect.TypeOf((*MockIEstimateUseCase)(nil).CalculateEstimate), ctx, osID, price)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CalculateEstimate", reflect.TypeOf((*MockIEstimateUseCase)(nil).CalculateEstimate), ctx, osID, pricing)
 }
 
 // CancelByOSID mocks base method.
-func (m *MockIEstimateUseCase) CancelByOSID(ctx context.Context, osID string) (entities.Estimate, error) {
+func (m *MockIEstimateUseCase) CancelByOSID(ctx context.Context, osID string, decision usecase.StatusDecision) (entities.Estimate, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CancelByOSID", ctx, osID)
+	ret := m.ctrl.Call(m, "CancelByOSID", ctx, osID, decision)
 	ret0, _ := ret[0].(entities.Estimate)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CancelByOSID indicates an expected call of CancelByOSID.
-func (mr *MockIEstimateUseCaseMockRecorder) CancelByOSID(ctx, osID any) *gomock.Call {
+func (mr *MockIEstimateUseCaseMockRecorder) CancelByOSID(ctx, osID, decision any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelByOSID", reflect.TypeOf((*MockIEstimateUseCase)(nil).CancelByOSID), ctx, osID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelByOSID", reflect.TypeOf((*MockIEstimateUseCase)(nil).CancelByOSID), ctx, osID, decision)
+}
+
+// DecideInternalApproval mocks base method.
+func (m *MockIEstimateUseCase) DecideInternalApproval(ctx context.Context, estimateID string, decision usecase.InternalDecision) (entities.Estimate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideInternalApproval", ctx, estimateID, decision)
+	ret0, _ := ret[0].(entities.Estimate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideInternalApproval indicates an expected call of DecideInternalApproval.
+func (mr *MockIEstimateUseCaseMockRecorder) DecideInternalApproval(ctx, estimateID, decision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideInternalApproval", reflect.TypeOf((*MockIEstimateUseCase)(nil).DecideInternalApproval), ctx, estimateID, decision)
+}
+
+// ExpireDue mocks base method.
+func (m *MockIEstimateUseCase) ExpireDue(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireDue", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireDue indicates an expected call of ExpireDue.
+func (mr *MockIEstimateUseCaseMockRecorder) ExpireDue(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireDue", reflect.TypeOf((*MockIEstimateUseCase)(nil).ExpireDue), ctx)
 }
 
 // GetByID mocks base method.
@@ -116,32 +147,106 @@ func (mr *MockIEstimateUseCaseMockRecorder) GetByOSID(ctx, osID any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByOSID", reflect.TypeOf((*MockIEstimateUseCase)(nil).GetByOSID), ctx, osID)
 }
 
+// ListAwaitingInternalApproval mocks base method.
+func (m *MockIEstimateUseCase) ListAwaitingInternalApproval(ctx context.Context) ([]entities.Estimate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAwaitingInternalApproval", ctx)
+	ret0, _ := ret[0].([]entities.Estimate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAwaitingInternalApproval indicates an expected call of ListAwaitingInternalApproval.
+func (mr *MockIEstimateUseCaseMockRecorder) ListAwaitingInternalApproval(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAwaitingInternalApproval", reflect.TypeOf((*MockIEstimateUseCase)(nil).ListAwaitingInternalApproval), ctx)
+}
+
+// PreviewEstimate mocks base method.
+func (m *MockIEstimateUseCase) PreviewEstimate(ctx context.Context, osID string, pricing usecase.EstimatePricing) (entities.Estimate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviewEstimate", ctx, osID, pricing)
+	ret0, _ := ret[0].(entities.Estimate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviewEstimate indicates an expected call of PreviewEstimate.
+func (mr *MockIEstimateUseCaseMockRecorder) PreviewEstimate(ctx, osID, pricing any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewEstimate", reflect.TypeOf((*MockIEstimateUseCase)(nil).PreviewEstimate), ctx, osID, pricing)
+}
+
+// ReasonCatalog mocks base method.
+func (m *MockIEstimateUseCase) ReasonCatalog() entities.ReasonCatalog {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReasonCatalog")
+	ret0, _ := ret[0].(entities.ReasonCatalog)
+	return ret0
+}
+
+// ReasonCatalog indicates an expected call of ReasonCatalog.
+func (mr *MockIEstimateUseCaseMockRecorder) ReasonCatalog() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReasonCatalog", reflect.TypeOf((*MockIEstimateUseCase)(nil).ReasonCatalog))
+}
+
+// RecordActualHours mocks base method.
+func (m *MockIEstimateUseCase) RecordActualHours(ctx context.Context, estimateID string, hours map[string]float64) (entities.Estimate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordActualHours", ctx, estimateID, hours)
+	ret0, _ := ret[0].(entities.Estimate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordActualHours indicates an expected call of RecordActualHours.
+func (mr *MockIEstimateUseCaseMockRecorder) RecordActualHours(ctx, estimateID, hours any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordActualHours", reflect.TypeOf((*MockIEstimateUseCase)(nil).RecordActualHours), ctx, estimateID, hours)
+}
+
 // RejectByOSID mocks base method.
-func (m *MockIEstimateUseCase) RejectByOSID(ctx context.Context, osID string) (entities.Estimate, error) {
+func (m *MockIEstimateUseCase) RejectByOSID(ctx context.Context, osID string, decision usecase.StatusDecision) (entities.Estimate, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RejectByOSID", ctx, osID)
+	ret := m.ctrl.Call(m, "RejectByOSID", ctx, osID, decision)
 	ret0, _ := ret[0].(entities.Estimate)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // RejectByOSID indicates an expected call of RejectByOSID.
-func (mr *MockIEstimateUseCaseMockRecorder) RejectByOSID(ctx, osID any) *gomock.Call {
+func (mr *MockIEstimateUseCaseMockRecorder) RejectByOSID(ctx, osID, decision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectByOSID", reflect.TypeOf((*MockIEstimateUseCase)(nil).RejectByOSID), ctx, osID, decision)
+}
+
+// RenewEstimate mocks base method.
+func (m *MockIEstimateUseCase) RenewEstimate(ctx context.Context, estimateID string, pricing usecase.EstimatePricing) (entities.Estimate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewEstimate", ctx, estimateID, pricing)
+	ret0, _ := ret[0].(entities.Estimate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenewEstimate indicates an expected call of RenewEstimate.
+func (mr *MockIEstimateUseCaseMockRecorder) RenewEstimate(ctx, estimateID, pricing any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectByOSID", reflect.TypeOf((*MockIEstimateUseCase)(nil).RejectByOSID), ctx, osID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewEstimate", reflect.TypeOf((*MockIEstimateUseCase)(nil).RenewEstimate), ctx, estimateID, pricing)
 }
 
 // UpdateEstimatePrice mocks base method.
-func (m *MockIEstimateUseCase) UpdateEstimatePrice(ctx context.Context, estimateID string, newPrice float64) (entities.Estimate, error) {
+func (m *MockIEstimateUseCase) UpdateEstimatePrice(ctx context.Context, estimateID string, pricing usecase.EstimatePricing) (entities.Estimate, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateEstimatePrice", ctx, estimateID, newPrice)
+	ret := m.ctrl.Call(m, "UpdateEstimatePrice", ctx, estimateID, pricing)
 	ret0, _ := ret[0].(entities.Estimate)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateEstimatePrice indicates an expected call of UpdateEstimatePrice.
-func (mr *MockIEstimateUseCaseMockRecorder) UpdateEstimatePrice(ctx, estimateID, newPrice any) *gomock.Call {
+func (mr *MockIEstimateUseCaseMockRecorder) UpdateEstimatePrice(ctx, estimateID, pricing any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEstimatePrice", reflect.TypeOf((*MockIEstimateUseCase)(nil).UpdateEstimatePrice), ctx, estimateID, newPrice)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEstimatePrice", reflect.TypeOf((*MockIEstimateUseCase)(nil).UpdateEstimatePrice), ctx, estimateID, pricing)
 }
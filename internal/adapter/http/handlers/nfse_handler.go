@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	request "mecanica_xpto/internal/adapter/http/dto/request"
+	response "mecanica_xpto/internal/adapter/http/dto/response"
+	"mecanica_xpto/internal/adapter/http/middlewares"
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase"
+	"mecanica_xpto/pkg"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NFSeHandler generates and transmits the signed NFS-e (RPS) of invoices.
+// All endpoints are privileged and must be routed behind the admin middleware.
+
+type NFSeHandler struct {
+	usecase usecase.INFSeUseCase
+}
+
+func NewNFSeHandler(uc usecase.INFSeUseCase) *NFSeHandler {
+	return &NFSeHandler{usecase: uc}
+}
+
+// GenerateNFSe signs the RPS of an invoice and transmits it. It is idempotent per invoice;
+// a failed transmission is reported in the document status, not as an error.
+func (h *NFSeHandler) GenerateNFSe(c *gin.Context) {
+	var payload request.NFSeRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		appErr := pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	actor := middlewares.AdminActor(c)
+	doc, err := h.usecase.Generate(c.Request.Context(), payload.InvoiceID, payload.Tomador.ToEntity(), actor)
+	if err != nil {
+		log.Printf("[nfse][handler] generate failed invoice_id=%s actor=%s err=%v", payload.InvoiceID, actor, err)
+		appErr := mapNFSeError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromNFSeDocument(doc))
+}
+
+// GetNFSe returns a document by ID.
+func (h *NFSeHandler) GetNFSe(c *gin.Context) {
+	doc, err := h.usecase.GetByID(c.Request.Context(), c.Param("nfse_id"))
+	if err != nil {
+		appErr := mapNFSeError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromNFSeDocument(doc))
+}
+
+// DownloadNFSeXML returns the signed XML of a document.
+func (h *NFSeHandler) DownloadNFSeXML(c *gin.Context) {
+	doc, err := h.usecase.GetByID(c.Request.Context(), c.Param("nfse_id"))
+	if err != nil {
+		appErr := mapNFSeError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="rps-%s-%d.xml"`, doc.RPS.Series, doc.RPS.Number))
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", doc.SignedXML)
+}
+
+// TransmitNFSe retries the transmission of a document; a sent document is returned as is.
+func (h *NFSeHandler) TransmitNFSe(c *gin.Context) {
+	id := c.Param("nfse_id")
+	doc, err := h.usecase.Transmit(c.Request.Context(), id)
+	if err != nil {
+		log.Printf("[nfse][handler] transmit failed nfse_id=%s err=%v", id, err)
+		appErr := mapNFSeError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromNFSeDocument(doc))
+}
+
+func mapNFSeError(err error) *pkg.AppError {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidNFSeID),
+		errors.Is(err, usecase.ErrInvalidNFSeInvoiceID):
+		return pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
+	case errors.Is(err, usecase.ErrInvalidNFSeTomador):
+		return pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest).WithDetails("tomador")
+	case errors.Is(err, usecase.ErrNFSeNotFound):
+		return pkg.NewDomainErrorSimple("NFSE_NOT_FOUND", "NFS-e not found", http.StatusNotFound)
+	case errors.Is(err, usecase.ErrInvoiceNotFound):
+		return pkg.NewDomainErrorSimple("INVOICE_NOT_FOUND", "Invoice not found", http.StatusNotFound)
+	case errors.Is(err, usecase.ErrInvoiceVoided):
+		return pkg.NewDomainErrorSimple("INVOICE_VOIDED", "Invoice voided", http.StatusConflict)
+	case errors.Is(err, entities.ErrRPSNumberTaken):
+		return pkg.NewDomainErrorSimple("NFSE_CONFLICT", "RPS number taken concurrently; retry", http.StatusConflict)
+	case errors.Is(err, usecase.ErrNFSeNotConfigured):
+		return pkg.NewDomainErrorSimple("NFSE_NOT_CONFIGURED", "NFS-e certificate not configured", http.StatusServiceUnavailable)
+	default:
+		return pkg.NewDomainError("INTERNAL_ERROR", "An internal error occurred", err, http.StatusInternalServerError)
+	}
+}
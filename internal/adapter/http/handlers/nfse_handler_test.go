@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"mecanica_xpto/internal/adapter/http/handlers/mocks"
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNFSeHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(t *testing.T) (*gin.Engine, *mocks.MockINFSeUseCase) {
+		ctrl := gomock.NewController(t)
+		uc := mocks.NewMockINFSeUseCase(ctrl)
+		h := NewNFSeHandler(uc)
+		r := gin.New()
+		r.POST("/v1/admin/nfse", h.GenerateNFSe)
+		r.GET("/v1/admin/nfse/:nfse_id/xml", h.DownloadNFSeXML)
+		return r, uc
+	}
+
+	t.Run("generate", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().Generate(gomock.Any(), "inv-1", gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ any, _ string, tomador entities.NFSeTomador, _ string) (entities.NFSeDocument, error) {
+				if tomador.Address == nil || tomador.Address.CityCode != "3550308" {
+					t.Fatalf("unexpected tomador: %+v", tomador)
+				}
+				return entities.NFSeDocument{ID: "n1", RPS: entities.NFSeRPS{Number: 11, Series: "A"}, Status: entities.NFSeStatusFalha}, nil
+			})
+
+		body := `{"invoice_id":"inv-1","tomador":{"document":"12345678909","name":"Maria","address":{"street":"Rua A","number":"1","district":"Centro","city_code":"3550308","state":"SP","postal_code":"01001000"}}}`
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/admin/nfse", strings.NewReader(body)))
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"status":"falha"`) {
+			t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().Generate(gomock.Any(), "inv-1", gomock.Any(), gomock.Any()).Return(entities.NFSeDocument{}, usecase.ErrNFSeNotConfigured)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/admin/nfse", strings.NewReader(`{"invoice_id":"inv-1","tomador":{"document":"12345678909","name":"Maria"}}`)))
+		if w.Code != http.StatusServiceUnavailable {
+			t.Fatalf("expected 503, got %d", w.Code)
+		}
+	})
+
+	t.Run("download xml", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().GetByID(gomock.Any(), "n1").Return(entities.NFSeDocument{ID: "n1", RPS: entities.NFSeRPS{Number: 11, Series: "A"}, SignedXML: []byte("<GerarNfseEnvio/>")}, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/nfse/n1/xml", nil))
+		if w.Code != http.StatusOK || w.Body.String() != "<GerarNfseEnvio/>" ||
+			!strings.Contains(w.Header().Get("Content-Disposition"), "rps-A-11.xml") {
+			t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
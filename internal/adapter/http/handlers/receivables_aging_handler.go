@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	response "mecanica_xpto/internal/adapter/http/dto/response"
+	"mecanica_xpto/internal/usecase"
+	"mecanica_xpto/pkg"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReceivablesAgingHandler serves the accounts receivable aging report.
+// Privileged: must be routed behind the admin middleware.
+
+type ReceivablesAgingHandler struct {
+	usecase usecase.IReceivablesAgingUseCase
+}
+
+func NewReceivablesAgingHandler(uc usecase.IReceivablesAgingUseCase) *ReceivablesAgingHandler {
+	return &ReceivablesAgingHandler{usecase: uc}
+}
+
+// GetReceivablesAging returns the report as JSON, or as a CSV attachment with `?format=csv`.
+func (h *ReceivablesAgingHandler) GetReceivablesAging(c *gin.Context) {
+	format := strings.ToLower(strings.TrimSpace(c.DefaultQuery("format", "json")))
+	if format != "json" && format != "csv" {
+		appErr := pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest).WithDetails("format")
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	report, err := h.usecase.Report(c.Request.Context())
+	if err != nil {
+		log.Printf("[payment][handler] receivables aging failed err=%v", err)
+		appErr := pkg.NewDomainError("INTERNAL_ERROR", "An internal error occurred", err, http.StatusInternalServerError)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, response.FromReceivablesAgingReport(report))
+		return
+	}
+	var buf bytes.Buffer
+	if err := usecase.WriteReceivablesAgingCSV(&buf, report); err != nil {
+		appErr := pkg.NewDomainError("INTERNAL_ERROR", "An internal error occurred", err, http.StatusInternalServerError)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="contas-a-receber-%s.csv"`, report.AsOf.Format(time.DateOnly)))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
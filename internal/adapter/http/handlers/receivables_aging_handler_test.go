@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"mecanica_xpto/internal/adapter/http/handlers/mocks"
+	"mecanica_xpto/internal/domain/entities"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReceivablesAgingHandler_GetReceivablesAging(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	asOf := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+	report := entities.ReceivablesAgingReport{
+		AsOf:    asOf,
+		Buckets: []entities.AgingBucketTotals{{Bucket: "31-60", Count: 1, Outstanding: 199.999}},
+		Items: []entities.ReceivableItem{
+			{EstimateID: "e1", OSID: "os-1", ApprovedAt: asOf.AddDate(0, 0, -45), DaysOutstanding: 45, Bucket: "31-60", Price: 300, Paid: 100, Outstanding: 200},
+		},
+		TotalOutstanding: 200,
+	}
+
+	newRouter := func(t *testing.T) (*gin.Engine, *mocks.MockIReceivablesAgingUseCase) {
+		ctrl := gomock.NewController(t)
+		uc := mocks.NewMockIReceivablesAgingUseCase(ctrl)
+		r := gin.New()
+		r.GET("/v1/admin/reports/ar-aging", NewReceivablesAgingHandler(uc).GetReceivablesAging)
+		return r, uc
+	}
+
+	t.Run("json", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().Report(gomock.Any()).Return(report, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/reports/ar-aging", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", w.Code)
+		}
+		var body struct {
+			Buckets []struct {
+				Outstanding float64 `json:"outstanding"`
+			} `json:"buckets"`
+			Items []struct {
+				ServiceOrderID string `json:"service_order_id"`
+			} `json:"items"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		if len(body.Buckets) != 1 || body.Buckets[0].Outstanding != 200 || len(body.Items) != 1 || body.Items[0].ServiceOrderID != "os-1" {
+			t.Fatalf("unexpected body: %s", w.Body.String())
+		}
+	})
+
+	t.Run("csv attachment", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().Report(gomock.Any()).Return(report, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/reports/ar-aging?format=csv", nil))
+		if w.Code != http.StatusOK || !strings.Contains(w.Header().Get("Content-Disposition"), "contas-a-receber-2026-06-30.csv") {
+			t.Fatalf("unexpected response %d: %v", w.Code, w.Header())
+		}
+		if !strings.Contains(w.Body.String(), "e1,os-1,2026-05-16T12:00:00Z,45,31-60,300.00,100.00,200.00") {
+			t.Fatalf("unexpected csv: %s", w.Body.String())
+		}
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		r, _ := newRouter(t)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/reports/ar-aging?format=pdf", nil))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		r, uc := newRouter(t)
+		uc.EXPECT().Report(gomock.Any()).Return(entities.ReceivablesAgingReport{}, errors.New("ddb"))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/reports/ar-aging", nil))
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("expected 500, got %d", w.Code)
+		}
+	})
+}
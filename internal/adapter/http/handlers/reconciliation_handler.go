@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	response "mecanica_xpto/internal/adapter/http/dto/response"
+	"mecanica_xpto/internal/adapter/http/middlewares"
+	"mecanica_xpto/internal/usecase"
+	"mecanica_xpto/pkg"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxSettlementReportBytes bounds the uploaded report (a month of settlements is far below).
+const maxSettlementReportBytes = 20 << 20
+
+// ReconciliationHandler imports provider settlement reports and serves reconciliation runs.
+// Privileged: must be routed behind the admin middleware.
+
+type ReconciliationHandler struct {
+	usecase usecase.ISettlementReconciliationUseCase
+}
+
+func NewReconciliationHandler(uc usecase.ISettlementReconciliationUseCase) *ReconciliationHandler {
+	return &ReconciliationHandler{usecase: uc}
+}
+
+// ImportSettlementReport reconciles an uploaded report. The CSV is sent either as the
+// multipart field `file` or as the raw request body (Content-Type text/csv).
+func (h *ReconciliationHandler) ImportSettlementReport(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSettlementReportBytes)
+
+	fileName, content, err := readCSVUpload(c)
+	if err != nil {
+		log.Printf("[payment][handler] settlement report upload invalid err=%v", err)
+		appErr := pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+	actor := middlewares.AdminActor(c)
+
+	run, err := h.usecase.Reconcile(c.Request.Context(), usecase.SettlementReport{
+		FileName: fileName,
+		Actor:    actor,
+		Content:  content,
+	})
+	if err != nil {
+		log.Printf("[payment][handler] reconciliation failed file=%s actor=%s err=%v", fileName, actor, err)
+		appErr := mapReconciliationError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.FromReconciliationRun(run))
+}
+
+// GetReconciliationRun returns a stored run with its discrepancies.
+func (h *ReconciliationHandler) GetReconciliationRun(c *gin.Context) {
+	run, err := h.usecase.GetRun(c.Request.Context(), c.Param("run_id"))
+	if err != nil {
+		appErr := mapReconciliationError(err)
+		c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromReconciliationRun(run))
+}
+
+// DownloadDiscrepancyReport streams the run discrepancies as a CSV attachment.
+func (h *ReconciliationHandler) DownloadDiscrepancyReport(c *gin.Context) {
+	runID := c.Param("run_id")
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="reconciliation-%s.csv"`, runID))
+
+	err := h.usecase.WriteRunReport(c.Request.Context(), runID, c.Writer)
+	if err == nil {
+		return
+	}
+	if c.Writer.Written() {
+		// The status line is already out; the truncated download is all we can report.
+		log.Printf("[payment][handler] discrepancy report interrupted run_id=%s err=%v", runID, err)
+		return
+	}
+	c.Writer.Header().Del("Content-Disposition")
+	appErr := mapReconciliationError(err)
+	c.JSON(appErr.HTTPStatus, appErr.ToHTTPError())
+}
+
+// readCSVUpload reads a CSV sent as the multipart field `file` or as the raw request body,
+// with its file name (the `file_name` query parameter for raw bodies).
+func readCSVUpload(c *gin.Context) (string, io.Reader, error) {
+	if c.ContentType() == "multipart/form-data" {
+		fh, err := c.FormFile("file")
+		if err != nil {
+			return "", nil, err
+		}
+		f, err := fh.Open()
+		if err != nil {
+			return "", nil, err
+		}
+		defer f.Close()
+		raw, err := io.ReadAll(f)
+		if err != nil {
+			return "", nil, err
+		}
+		return fh.Filename, bytes.NewReader(raw), nil
+	}
+
+	raw, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return "", nil, errors.New("empty upload")
+	}
+	return c.Query("file_name"), bytes.NewReader(raw), nil
+}
+
+func mapReconciliationError(err error) *pkg.AppError {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidSettlementReport):
+		return pkg.NewDomainError("INVALID_SETTLEMENT_REPORT", "Invalid settlement report", err, http.StatusBadRequest).WithDetails(err.Error())
+	case errors.Is(err, usecase.ErrInvalidReconciliationRunID):
+		return pkg.NewDomainErrorSimple("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
+	case errors.Is(err, usecase.ErrReconciliationRunNotFound):
+		return pkg.NewDomainErrorSimple("RECONCILIATION_RUN_NOT_FOUND", "Reconciliation run not found", http.StatusNotFound)
+	default:
+		return pkg.NewDomainError("INTERNAL_ERROR", "An internal error occurred", err, http.StatusInternalServerError)
+	}
+}
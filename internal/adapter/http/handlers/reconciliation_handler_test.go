@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"mecanica_xpto/internal/adapter/http/handlers/mocks"
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+)
+
+func newReconciliationRouter(uc usecase.ISettlementReconciliationUseCase) *gin.Engine {
+	h := NewReconciliationHandler(uc)
+	r := gin.New()
+	r.POST("/v1/admin/reconciliations", h.ImportSettlementReport)
+	r.GET("/v1/admin/reconciliations/:run_id", h.GetReconciliationRun)
+	r.GET("/v1/admin/reconciliations/:run_id/discrepancies.csv", h.DownloadDiscrepancyReport)
+	return r
+}
+
+func TestReconciliationHandler_ImportSettlementReport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const report = "SOURCE_ID,TRANSACTION_TYPE,TRANSACTION_AMOUNT\n111,SETTLEMENT,150\n"
+
+	expectReport := func(uc *mocks.MockISettlementReconciliationUseCase, fileName string) {
+		uc.EXPECT().Reconcile(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, in usecase.SettlementReport) (entities.ReconciliationRun, error) {
+				raw, _ := io.ReadAll(in.Content)
+				if string(raw) != report || in.FileName != fileName || in.Actor != "finance" {
+					t.Fatalf("unexpected input file=%q actor=%q content=%q", in.FileName, in.Actor, raw)
+				}
+				return entities.ReconciliationRun{ID: "run-1", TotalLines: 1, Discrepancies: []entities.ReconciliationDiscrepancy{
+					{Kind: entities.DiscrepancyMissingPayment, Line: 2, SourceID: "111"},
+				}}, nil
+			})
+	}
+
+	t.Run("multipart upload", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockISettlementReconciliationUseCase(ctrl)
+		r := newReconciliationRouter(uc)
+		expectReport(uc, "marco.csv")
+
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		fw, _ := mw.CreateFormFile("file", "marco.csv")
+		_, _ = fw.Write([]byte(report))
+		_ = mw.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/admin/reconciliations", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		req.Header.Set("X-Admin-Actor", "finance")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+		}
+		var res struct {
+			ID      string         `json:"id"`
+			Summary map[string]int `json:"summary"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &res)
+		if res.ID != "run-1" || res.Summary["missing_payment"] != 1 {
+			t.Fatalf("unexpected body: %s", w.Body.String())
+		}
+	})
+
+	t.Run("raw csv body", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockISettlementReconciliationUseCase(ctrl)
+		r := newReconciliationRouter(uc)
+		expectReport(uc, "marco.csv")
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/admin/reconciliations?file_name=marco.csv", strings.NewReader(report))
+		req.Header.Set("Content-Type", "text/csv")
+		req.Header.Set("X-Admin-Actor", "finance")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("empty body", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		r := newReconciliationRouter(mocks.NewMockISettlementReconciliationUseCase(ctrl))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/admin/reconciliations", strings.NewReader(" \n")))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("invalid report exposes the parse error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := mocks.NewMockISettlementReconciliationUseCase(ctrl)
+		r := newReconciliationRouter(uc)
+		uc.EXPECT().Reconcile(gomock.Any(), gomock.Any()).
+			Return(entities.ReconciliationRun{}, fmt.Errorf("%w: line 2: invalid TRANSACTION_AMOUNT", usecase.ErrInvalidSettlementReport))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/admin/reconciliations", strings.NewReader("x")))
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "line 2") {
+			t.Fatalf("expected 400 with details, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
+
+func TestReconciliationHandler_GetAndDownload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	uc := mocks.NewMockISettlementReconciliationUseCase(ctrl)
+	r := newReconciliationRouter(uc)
+
+	run := entities.ReconciliationRun{ID: "run-1", Discrepancies: []entities.ReconciliationDiscrepancy{
+		{Kind: entities.DiscrepancyAmountMismatch, Line: 3, SourceID: "p2", PaymentID: "p2", ReportAmount: 90, PaymentAmount: 100},
+	}}
+	uc.EXPECT().GetRun(gomock.Any(), "run-1").Return(run, nil)
+	uc.EXPECT().WriteRunReport(gomock.Any(), "run-1", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, w io.Writer) error {
+		return usecase.WriteDiscrepancyReport(w, run)
+	})
+	uc.EXPECT().WriteRunReport(gomock.Any(), "nope", gomock.Any()).Return(usecase.ErrReconciliationRunNotFound)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/reconciliations/run-1", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"amount_mismatch":1`) {
+		t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/reconciliations/run-1/discrepancies.csv", nil))
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("unexpected response %d: %v", w.Code, w.Header())
+	}
+	if !strings.Contains(w.Header().Get("Content-Disposition"), "reconciliation-run-1.csv") {
+		t.Fatalf("unexpected disposition: %s", w.Header().Get("Content-Disposition"))
+	}
+	if !strings.Contains(w.Body.String(), "amount_mismatch,3,p2,,,p2,90.00,100.00") {
+		t.Fatalf("unexpected csv: %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/reconciliations/nope/discrepancies.csv", nil))
+	if w.Code != http.StatusNotFound || w.Header().Get("Content-Disposition") != "" {
+		t.Fatalf("expected 404 without attachment, got %d: %v", w.Code, w.Header())
+	}
+}
@@ -0,0 +1,45 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"log"
+	"mecanica_xpto/pkg"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminTokenHeader carries the shared secret for privileged endpoints.
+const AdminTokenHeader = "X-Admin-Token"
+
+// AdminActorHeader optionally identifies the operator calling a privileged endpoint.
+const AdminActorHeader = "X-Admin-Actor"
+
+// RequireAdminToken protects privileged routes with the ADMIN_API_TOKEN shared secret.
+//
+// When ADMIN_API_TOKEN is not configured every request is refused, so privileged
+// endpoints are never exposed by accident.
+func RequireAdminToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		expected := strings.TrimSpace(os.Getenv("ADMIN_API_TOKEN"))
+		provided := strings.TrimSpace(c.GetHeader(AdminTokenHeader))
+
+		if expected == "" || subtle.ConstantTimeCompare([]byte(expected), []byte(provided)) != 1 {
+			log.Printf("[admin][auth] denied path=%s configured=%t", c.FullPath(), expected != "")
+			appErr := pkg.NewDomainErrorSimple("FORBIDDEN", "Forbidden", http.StatusForbidden)
+			c.AbortWithStatusJSON(appErr.HTTPStatus, appErr.ToHTTPError())
+			return
+		}
+		c.Next()
+	}
+}
+
+// AdminActor returns the operator identity sent with a privileged request.
+func AdminActor(c *gin.Context) string {
+	if v := strings.TrimSpace(c.GetHeader(AdminActorHeader)); v != "" {
+		return v
+	}
+	return "admin"
+}
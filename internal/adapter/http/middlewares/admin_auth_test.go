@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireAdminToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name       string
+		configured string
+		header     string
+		want       int
+	}{
+		{name: "token not configured", configured: "", header: "", want: http.StatusForbidden},
+		{name: "missing header", configured: "secret", header: "", want: http.StatusForbidden},
+		{name: "wrong token", configured: "secret", header: "other", want: http.StatusForbidden},
+		{name: "valid token", configured: "secret", header: "secret", want: http.StatusOK},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("ADMIN_API_TOKEN", tc.configured)
+
+			r := gin.New()
+			r.GET("/admin", RequireAdminToken(), func(c *gin.Context) {
+				c.String(http.StatusOK, AdminActor(c))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tc.header != "" {
+				req.Header.Set(AdminTokenHeader, tc.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tc.want {
+				t.Fatalf("expected %d, got %d", tc.want, w.Code)
+			}
+		})
+	}
+}
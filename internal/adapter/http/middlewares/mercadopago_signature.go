@@ -0,0 +1,117 @@
+package middlewares
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"mecanica_xpto/internal/infrastructure/security"
+	"mecanica_xpto/pkg"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MercadoPagoSignatureHeader carries "ts=<unix>,v1=<hmac hex>" on Mercado Pago webhooks.
+const MercadoPagoSignatureHeader = "X-Signature"
+
+// MercadoPagoRequestIDHeader is part of the signed manifest.
+const MercadoPagoRequestIDHeader = "X-Request-Id"
+
+// MercadoPagoSignatureMaxAge bounds how far ts may be from now, so a captured signature
+// cannot be replayed later.
+const MercadoPagoSignatureMaxAge = 5 * time.Minute
+
+// signatureNow is the clock ts is checked against.
+var signatureNow = time.Now
+
+// VerifyMercadoPagoSignature validates the webhook signature with MERCADOPAGO_WEBHOOK_SECRET.
+//
+// The manifest is "id:<data.id>;request-id:<x-request-id>;ts:<ts>;" as documented by
+// Mercado Pago; only the query data.id is signed, so handlers must not read the id from
+// the body. ts (seconds or milliseconds) must be within MercadoPagoSignatureMaxAge.
+// The secret is required outside development: without it every notification is refused,
+// and only with GIN_MODE=debug the check is skipped.
+func VerifyMercadoPagoSignature() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		secret := strings.TrimSpace(os.Getenv("MERCADOPAGO_WEBHOOK_SECRET"))
+		if secret == "" {
+			if security.DevelopmentMode() {
+				c.Next()
+				return
+			}
+			log.Printf("[webhook][mercadopago] MERCADOPAGO_WEBHOOK_SECRET not set; notification refused path=%s", c.FullPath())
+			abortInvalidSignature(c)
+			return
+		}
+
+		ts, v1 := parseMercadoPagoSignature(c.GetHeader(MercadoPagoSignatureHeader))
+		expected := mercadoPagoSignature(secret, c.Query("data.id"), c.GetHeader(MercadoPagoRequestIDHeader), ts)
+		if ts == "" || v1 == "" || !hmac.Equal([]byte(expected), []byte(strings.ToLower(v1))) {
+			log.Printf("[webhook][mercadopago] invalid signature path=%s", c.FullPath())
+			abortInvalidSignature(c)
+			return
+		}
+		if !freshSignature(ts, signatureNow()) {
+			log.Printf("[webhook][mercadopago] stale signature ts=%s path=%s", ts, c.FullPath())
+			abortInvalidSignature(c)
+			return
+		}
+		c.Next()
+	}
+}
+
+func abortInvalidSignature(c *gin.Context) {
+	appErr := pkg.NewDomainErrorSimple("UNAUTHORIZED", "Invalid signature", http.StatusUnauthorized)
+	c.AbortWithStatusJSON(appErr.HTTPStatus, appErr.ToHTTPError())
+}
+
+// freshSignature tells whether ts, in unix seconds or milliseconds, is within
+// MercadoPagoSignatureMaxAge of now, either way.
+func freshSignature(ts string, now time.Time) bool {
+	n, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || n <= 0 {
+		return false
+	}
+	signedAt := time.Unix(n, 0)
+	if n > 1e12 {
+		signedAt = time.UnixMilli(n)
+	}
+	age := now.Sub(signedAt)
+	return age <= MercadoPagoSignatureMaxAge && age >= -MercadoPagoSignatureMaxAge
+}
+
+func parseMercadoPagoSignature(header string) (ts, v1 string) {
+	for _, part := range strings.Split(header, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch k {
+		case "ts":
+			ts = v
+		case "v1":
+			v1 = v
+		}
+	}
+	return ts, v1
+}
+
+func mercadoPagoSignature(secret, dataID, requestID, ts string) string {
+	var manifest strings.Builder
+	if dataID != "" {
+		manifest.WriteString("id:" + strings.ToLower(dataID) + ";")
+	}
+	if requestID != "" {
+		manifest.WriteString("request-id:" + requestID + ";")
+	}
+	manifest.WriteString("ts:" + ts + ";")
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(manifest.String()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,82 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestVerifyMercadoPagoSignature(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func() *gin.Engine {
+		r := gin.New()
+		r.POST("/webhook", VerifyMercadoPagoSignature(), func(c *gin.Context) { c.Status(http.StatusOK) })
+		return r
+	}
+	send := func(signature string) int {
+		req := httptest.NewRequest(http.MethodPost, "/webhook?data.id=ABC123&type=chargebacks", nil)
+		req.Header.Set(MercadoPagoRequestIDHeader, "req-1")
+		if signature != "" {
+			req.Header.Set(MercadoPagoSignatureHeader, signature)
+		}
+		w := httptest.NewRecorder()
+		newRouter().ServeHTTP(w, req)
+		return w.Code
+	}
+
+	now := time.Unix(1704908010, 0)
+	signatureNow = func() time.Time { return now }
+	t.Cleanup(func() { signatureNow = time.Now })
+
+	t.Run("skipped without secret in development", func(t *testing.T) {
+		t.Setenv("MERCADOPAGO_WEBHOOK_SECRET", "")
+		t.Setenv("GIN_MODE", "debug")
+		if code := send(""); code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", code)
+		}
+	})
+
+	t.Run("refused without secret outside development", func(t *testing.T) {
+		t.Setenv("MERCADOPAGO_WEBHOOK_SECRET", "")
+		t.Setenv("GIN_MODE", "release")
+		if code := send(""); code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", code)
+		}
+	})
+
+	t.Run("valid signature", func(t *testing.T) {
+		t.Setenv("MERCADOPAGO_WEBHOOK_SECRET", "s3cr3t")
+		sig := mercadoPagoSignature("s3cr3t", "abc123", "req-1", "1704908010")
+		if code := send("ts=1704908010,v1=" + sig); code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", code)
+		}
+		sig = mercadoPagoSignature("s3cr3t", "abc123", "req-1", "1704908070123")
+		if code := send("ts=1704908070123,v1=" + sig); code != http.StatusOK {
+			t.Fatalf("expected 200 for a millisecond ts, got %d", code)
+		}
+	})
+
+	t.Run("stale signature", func(t *testing.T) {
+		t.Setenv("MERCADOPAGO_WEBHOOK_SECRET", "s3cr3t")
+		ts := strconv.FormatInt(now.Add(-MercadoPagoSignatureMaxAge-time.Second).Unix(), 10)
+		sig := mercadoPagoSignature("s3cr3t", "abc123", "req-1", ts)
+		if code := send("ts=" + ts + ",v1=" + sig); code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", code)
+		}
+	})
+
+	t.Run("invalid or missing signature", func(t *testing.T) {
+		t.Setenv("MERCADOPAGO_WEBHOOK_SECRET", "s3cr3t")
+		if code := send("ts=1704908010,v1=deadbeef"); code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", code)
+		}
+		if code := send(""); code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", code)
+		}
+	})
+}
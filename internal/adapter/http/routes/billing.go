@@ -2,28 +2,167 @@ package routes
 
 import (
 	"mecanica_xpto/internal/adapter/http/handlers"
+	"mecanica_xpto/internal/adapter/http/middlewares"
 
 	"github.com/gin-gonic/gin"
 )
 
 const (
-	PathEstimates = "/estimates"
+	PathEstimates    = "/estimates"
+	PathDataSubjects = "/data-subjects"
+	PathDisputes     = "/disputes"
+	PathWebhooks     = "/webhooks"
+	PathInvoices     = "/invoices"
+
+	PathReconciliations = "/reconciliations"
+	PathReports         = "/reports"
+
+	PathAccountingExports = "/accounting/exports"
+	PathLedger            = "/ledger"
+	PathNFSe              = "/nfse"
+	PathCoupons           = "/coupons"
+	PathCatalog           = "/catalog"
+	PathApproval          = "/approval"
 )
 
-func addBillingRoutes(rg *gin.RouterGroup, estimateHandler *handlers.EstimateHandler, paymentHandler *handlers.BillingPaymentHandler) {
+type billingHandlers struct {
+	estimate    *handlers.EstimateHandler
+	payment     *handlers.BillingPaymentHandler
+	dataSubject *handlers.DataSubjectHandler
+	dispute     *handlers.DisputeHandler
+
+	reconciliation *handlers.ReconciliationHandler
+	cashClosing    *handlers.CashClosingHandler
+	receivables    *handlers.ReceivablesAgingHandler
+	analytics      *handlers.AnalyticsHandler
+	accounting     *handlers.AccountingExportHandler
+	ledger         *handlers.LedgerHandler
+	invoice        *handlers.InvoiceHandler
+	nfse           *handlers.NFSeHandler
+	coupon         *handlers.CouponHandler
+	catalog        *handlers.CatalogHandler
+	approval       *handlers.EstimateApprovalHandler
+}
+
+func addBillingRoutes(rg *gin.RouterGroup, h billingHandlers) {
 	estimates := rg.Group(PathEstimates)
 	{
 		// Endpoints compatíveis com IBillingServiceRepository.
-		estimates.POST("", estimateHandler.CreateEstimate)
-		estimates.PATCH("/approve", estimateHandler.ApproveEstimate)
-		estimates.PATCH("/reject", estimateHandler.RejectEstimate)
-		estimates.PATCH("/cancel", estimateHandler.CancelEstimate)
+		estimates.POST("", h.estimate.CreateEstimate)
+		estimates.PATCH("/approve", h.estimate.ApproveEstimate)
+		estimates.PATCH("/reject", h.estimate.RejectEstimate)
+		estimates.PATCH("/cancel", h.estimate.CancelEstimate)
+		// OS encerrada: emite a fatura do orçamento aprovado (idempotente).
+		estimates.PATCH("/close", h.invoice.CloseServiceOrder)
+		// Catálogo de motivos aceitos na rejeição e no cancelamento.
+		estimates.GET("/reasons", h.estimate.ListReasons)
+		// Simula o orçamento (regras de preço, descontos e impostos) sem gravar.
+		estimates.POST("/preview", h.estimate.PreviewEstimate)
+		// Recalcula o orçamento pendente mantendo os descontos já aplicados.
+		estimates.POST("/:estimate_id/recalculate", h.estimate.RecalculateEstimate)
+		// Reprecifica a mão de obra com as horas realizadas ao concluir a OS.
+		estimates.POST("/:estimate_id/actual-hours", h.estimate.RecordActualHours)
+		// Reemite o orçamento pendente ou expirado com preços atuais e nova validade.
+		estimates.POST("/:estimate_id/renew", h.estimate.RenewEstimate)
+		// Gera o link assinado de aprovação e o envia ao cliente.
+		estimates.POST("/:estimate_id/approval-links", h.approval.CreateApprovalLink)
+		estimates.GET("/:estimate_id/payments", h.payment.ListEstimatePayments)
+		estimates.GET("/:estimate_id/invoice", h.invoice.GetEstimateInvoice)
+	}
+
+	invoices := rg.Group(PathInvoices)
+	{
+		// Fatura do orçamento aprovado (emissão idempotente).
+		invoices.POST("", h.invoice.IssueInvoice)
+		invoices.GET("/:invoice_id", h.invoice.GetInvoice)
 	}
 
 	payments := rg.Group(PathPayments)
 	{
 		// Endpoints compatíveis com IBillingServiceRepository.
-		payments.POST("/:estimate_id", paymentHandler.CreatePaymentByEstimateID)
-		payments.GET("/:estimate_id", paymentHandler.GetPaymentByEstimateID)
+		payments.POST("/:estimate_id", h.payment.CreatePaymentByEstimateID)
+		payments.GET("/:estimate_id", h.payment.GetPaymentByEstimateID)
+		payments.GET("", h.payment.ListPayments)
+		payments.GET("/id/:payment_id", h.payment.GetPaymentByID)
+	}
+
+	admin := rg.Group(PathAdmin, middlewares.RequireAdminToken())
+	{
+		admin.GET(PathPayments+"/:payment_id/reveal", h.payment.RevealPayment)
+		admin.GET(PathPayments+"/:payment_id/history", h.payment.GetPaymentHistory)
+		admin.PATCH(PathPayments+"/:payment_id/status", h.payment.ChangePaymentStatus)
+
+		// LGPD: requisições do titular (acesso e eliminação).
+		admin.POST(PathDataSubjects+"/export", h.dataSubject.Export)
+		admin.POST(PathDataSubjects+"/anonymize", h.dataSubject.Anonymize)
+
+		// Aprovação interna (gerente) dos orçamentos acima do limite configurado.
+		admin.GET(PathEstimates+"/internal-approvals", h.estimate.ListInternalApprovals)
+		admin.POST(PathEstimates+"/:estimate_id/internal-approval", h.estimate.DecideInternalApproval)
+
+		admin.GET(PathDisputes, h.dispute.ListOpenDisputes)
+		admin.GET(PathDisputes+"/:dispute_id", h.dispute.GetDispute)
+		admin.POST(PathDisputes+"/:dispute_id/evidence", h.dispute.AddDisputeEvidence)
+
+		// Conciliação com os relatórios de liberação/vendas do Mercado Pago.
+		admin.POST(PathReconciliations, h.reconciliation.ImportSettlementReport)
+		admin.GET(PathReconciliations+"/:run_id", h.reconciliation.GetReconciliationRun)
+		admin.GET(PathReconciliations+"/:run_id/discrepancies.csv", h.reconciliation.DownloadDiscrepancyReport)
+
+		// Fechamento de caixa diário (America/Sao_Paulo).
+		admin.GET(PathReports+"/cash-closing", h.cashClosing.GetCashClosing)
+		// Aging de contas a receber (orçamentos aprovados não pagos).
+		admin.GET(PathReports+"/ar-aging", h.receivables.GetReceivablesAging)
+		// Conversão de orçamentos (read model estimate_conversions).
+		admin.GET(PathReports+"/conversion", h.analytics.GetConversion)
+		// Motivos de rejeição e cancelamento dos orçamentos criados no período.
+		admin.GET(PathReports+"/rejection-reasons", h.analytics.GetRejectionReasons)
+
+		// Exportação contábil incremental (CSV, OFX ou layout de largura fixa).
+		admin.POST(PathAccountingExports, h.accounting.CreateAccountingExport)
+		admin.GET(PathAccountingExports+"/:export_id", h.accounting.GetAccountingExport)
+		admin.GET(PathAccountingExports+"/:export_id/file", h.accounting.DownloadAccountingExport)
+
+		// Razão de partidas dobradas: saldos por conta e por orçamento.
+		admin.GET(PathLedger+"/accounts", h.ledger.GetAccountBalances)
+		admin.GET(PathLedger+"/estimates/:estimate_id", h.ledger.GetEstimateLedger)
+
+		// Cancelamento de fatura sem pagamentos e notas de crédito.
+		admin.POST(PathInvoices+"/:invoice_id/void", h.invoice.VoidInvoice)
+		admin.POST(PathInvoices+"/:invoice_id/credit-notes", h.invoice.AddCreditNote)
+
+		// NFS-e: RPS ABRASF assinado com certificado A1 e transmissão à prefeitura.
+		admin.POST(PathNFSe, h.nfse.GenerateNFSe)
+		admin.GET(PathNFSe+"/:nfse_id", h.nfse.GetNFSe)
+		admin.GET(PathNFSe+"/:nfse_id/xml", h.nfse.DownloadNFSeXML)
+		admin.POST(PathNFSe+"/:nfse_id/transmit", h.nfse.TransmitNFSe)
+
+		// Cupons de desconto (percentual ou fixo, por item ou no orçamento).
+		admin.POST(PathCoupons, h.coupon.CreateCoupon)
+		admin.GET(PathCoupons+"/:code", h.coupon.GetCoupon)
+		admin.POST(PathCoupons+"/:code/deactivate", h.coupon.DeactivateCoupon)
+
+		// Catálogo de serviços e peças (preços de referência dos orçamentos).
+		admin.GET(PathCatalog, h.catalog.ListCatalog)
+		// Importação em lote via CSV (kind, code, name, price).
+		admin.POST(PathCatalog+"/import", h.catalog.ImportCatalog)
+		admin.GET(PathCatalog+"/:kind/:code", h.catalog.GetCatalogItem)
+		admin.PUT(PathCatalog+"/:kind/:code", h.catalog.UpsertCatalogItem)
+		admin.DELETE(PathCatalog+"/:kind/:code", h.catalog.DeleteCatalogItem)
+	}
+
+	// Rotas do cliente: o token do link autentica o acesso ao orçamento.
+	approval := rg.Group(PathApproval)
+	{
+		approval.GET("/:token", h.approval.GetApproval)
+		// Envia o código (OTP) que confirma a decisão.
+		approval.POST("/:token/otp", h.approval.SendApprovalOTP)
+		// Aprova ou rejeita o orçamento, registrando IP e user agent como evidência.
+		approval.POST("/:token/decision", h.approval.DecideApproval)
+	}
+
+	webhooks := rg.Group(PathWebhooks)
+	{
+		webhooks.POST("/mercadopago", middlewares.VerifyMercadoPagoSignature(), h.dispute.MercadoPagoNotification)
 	}
 }
@@ -10,4 +10,5 @@ const (
 	PathServiceOrders    = "/service-orders"
 	PathPayments         = "/payments"
 	PathAdditionalRepair = "/additional-repair"
+	PathAdmin            = "/admin"
 )
@@ -1,12 +1,20 @@
 package routes
 
 import (
+	"context"
 	"log"
 	_ "mecanica_xpto/docs" // This will be auto-generated
 	"mecanica_xpto/internal/adapter/http/handlers"
+	"mecanica_xpto/internal/adapter/jobs"
 	repository2 "mecanica_xpto/internal/adapter/persistence/repository"
+	"mecanica_xpto/internal/infrastructure/accounting"
 	"mecanica_xpto/internal/infrastructure/database"
+	"mecanica_xpto/internal/infrastructure/fiscal"
+	"mecanica_xpto/internal/infrastructure/notifications"
 	"mecanica_xpto/internal/infrastructure/payments"
+	"mecanica_xpto/internal/infrastructure/payments/schemas"
+	"mecanica_xpto/internal/infrastructure/pricing"
+	"mecanica_xpto/internal/infrastructure/security"
 	"mecanica_xpto/internal/usecase"
 	"mecanica_xpto/internal/usecase/interfaces"
 	"os"
@@ -41,30 +49,203 @@ func getRoutes() {
 
 	estimateRepo := repository2.NewEstimateDynamoRepository(ddb)
 	paymentRepo := repository2.NewBillingPaymentDynamoRepository(ddb)
+	auditLogRepo := repository2.NewAuditLogDynamoRepository(ddb)
+	paymentStatusEventRepo := repository2.NewPaymentStatusEventDynamoRepository(ddb)
+	disputeRepo := repository2.NewDisputeDynamoRepository(ddb)
+	reconciliationRunRepo := repository2.NewReconciliationRunDynamoRepository(ddb)
+	estimateConversionRepo := repository2.NewEstimateConversionDynamoRepository(ddb)
+	accountingExportRepo := repository2.NewAccountingExportDynamoRepository(ddb)
+	ledgerRepo := repository2.NewLedgerDynamoRepository(ddb)
+	invoiceRepo := repository2.NewInvoiceDynamoRepository(ddb)
+	nfseRepo := repository2.NewNFSeDynamoRepository(ddb)
+	couponRepo := repository2.NewCouponDynamoRepository(ddb)
+	catalogRepo := repository2.NewCatalogDynamoRepository(ddb)
+	approvalLinkRepo := repository2.NewApprovalLinkDynamoRepository(ddb)
 
-	estimateUseCase := usecase.NewEstimateUseCase(estimateRepo)
+	taxTable, err := fiscal.LoadTaxTableFromEnv()
+	if err != nil {
+		log.Fatalf("failed to load tax rates: %v", err)
+	}
+
+	pricingRules, err := pricing.LoadRulesFromEnv()
+	if err != nil {
+		log.Fatalf("failed to load pricing rules: %v", err)
+	}
+	laborRates, err := pricing.LoadLaborRatesFromEnv()
+	if err != nil {
+		log.Fatalf("failed to load labor rates: %v", err)
+	}
+	catalogPolicy, err := pricing.LoadCatalogPolicyFromEnv()
+	if err != nil {
+		log.Fatalf("failed to load catalog validation: %v", err)
+	}
+	validity, err := pricing.LoadValidityFromEnv()
+	if err != nil {
+		log.Fatalf("failed to load estimate validity: %v", err)
+	}
+	reasonCatalog, err := pricing.LoadReasonCatalogFromEnv()
+	if err != nil {
+		log.Fatalf("failed to load estimate reasons: %v", err)
+	}
+	internalApprovalThreshold, err := pricing.LoadInternalApprovalThresholdFromEnv()
+	if err != nil {
+		log.Fatalf("failed to load internal approval threshold: %v", err)
+	}
+
+	invoiceUseCase := usecase.NewInvoiceUseCase(invoiceRepo, estimateRepo, paymentRepo)
+	estimateUseCase := usecase.NewEstimateUseCase(estimateRepo).
+		WithConversionProjection(estimateConversionRepo).
+		WithInvoicing(invoiceUseCase).
+		WithTaxes(taxTable).
+		WithCoupons(couponRepo).
+		WithCatalog(catalogRepo, catalogPolicy).
+		WithValidity(validity.Days).
+		WithInternalApproval(internalApprovalThreshold)
+	if pricingRules != nil {
+		estimateUseCase.WithPricingRules(*pricingRules)
+	}
+	if laborRates != nil {
+		estimateUseCase.WithLaborRates(*laborRates)
+	}
+	if reasonCatalog != nil {
+		estimateUseCase.WithReasonCatalog(*reasonCatalog)
+	}
+	if validity.SweepInterval > 0 {
+		go jobs.RunEstimateExpiration(context.Background(), estimateUseCase, validity.SweepInterval)
+	}
+	linkSigner, err := security.NewLinkSignerFromEnv()
+	if err != nil {
+		log.Fatalf("failed to load approval link key: %v", err)
+	}
+	approvalConfig, err := security.LoadApprovalLinkConfigFromEnv()
+	if err != nil {
+		log.Fatalf("failed to load approval link config: %v", err)
+	}
+	notifier, err := notifications.NewNotifierFromEnv()
+	if err != nil {
+		log.Fatalf("failed to load notifier: %v", err)
+	}
+	approvalUseCase := usecase.NewEstimateApprovalUseCase(approvalLinkRepo, estimateUseCase, linkSigner, notifier).
+		WithBaseURL(approvalConfig.BaseURL).
+		WithLinkTTL(approvalConfig.TTL)
+	couponUseCase := usecase.NewCouponUseCase(couponRepo)
+	catalogUseCase := usecase.NewCatalogUseCase(catalogRepo)
 
 	// DEBUG ONLY: explicit credential print requested by user.
 	log.Printf("[debug][mp] MERCADOPAGO_PUBLIC_KEY=%s", os.Getenv("MERCADOPAGO_PUBLIC_KEY"))
 	log.Printf("[debug][mp] MERCADOPAGO_ACCESS_TOKEN=%s", os.Getenv("MERCADOPAGO_ACCESS_TOKEN"))
 
 	var paymentGateway interfaces.IPaymentGateway
+	var chargebackProvider interfaces.IChargebackProvider
+	if os.Getenv("MERCADOPAGO_WEBHOOK_SECRET") == "" {
+		if !security.DevelopmentMode() {
+			log.Fatalf("MERCADOPAGO_WEBHOOK_SECRET is required outside development (GIN_MODE=debug)")
+		}
+		log.Printf("MERCADOPAGO_WEBHOOK_SECRET not set (development); webhook signatures will not be verified")
+	}
 	mpGateway, err := payments.NewMercadoPagoGateway(os.Getenv("MERCADOPAGO_ACCESS_TOKEN"))
 	if err != nil {
 		log.Printf("Mercado Pago gateway not configured: %v", err)
 	} else {
 		paymentGateway = mpGateway
+		chargebackProvider = mpGateway
+	}
+
+	paymentUseCase := usecase.NewBillingPaymentUseCase(paymentRepo, estimateRepo, paymentGateway).
+		WithStatusHistory(paymentStatusEventRepo).
+		WithConversionProjection(estimateConversionRepo).
+		WithLedgerPosting(ledgerRepo).
+		WithInvoicing(invoiceUseCase)
+	if mpGateway != nil {
+		paymentUseCase.WithDetailsExtractor(mpGateway)
+	}
+	payloadValidator, err := schemas.NewMercadoPagoPayloadValidator(os.Getenv("PAYMENT_SCHEMA_VERSION"))
+	if err != nil {
+		log.Fatalf("failed to load payment payload schemas: %v", err)
+	}
+	paymentUseCase.WithPayloadValidator(payloadValidator)
+
+	piiKeys, err := security.NewKeyProviderFromEnv()
+	if err != nil {
+		log.Fatalf("failed to load PII encryption keys: %v", err)
 	}
+	if piiKeys == nil {
+		log.Printf("PII_KEY_FILE not set (development); payer personal data will be stored unencrypted")
+	}
+	payerIndex, err := security.NewBlindIndexFromEnv()
+	if err != nil {
+		log.Fatalf("failed to load PII blind index key: %v", err)
+	}
+	dataProtector := security.NewPaymentDataProtector(piiKeys, payerIndex)
+	paymentUseCase.WithSensitiveDataProtector(dataProtector)
 
-	paymentUseCase := usecase.NewBillingPaymentUseCase(paymentRepo, estimateRepo, paymentGateway)
+	dataSubjectUseCase := usecase.NewDataSubjectUseCase(paymentRepo, estimateRepo, payerIndex, dataProtector, auditLogRepo)
+	disputeUseCase := usecase.NewDisputeUseCase(disputeRepo, chargebackProvider, paymentUseCase, estimateRepo)
+	// Report parsing needs no credentials, so reconciliation works without a configured gateway.
+	reconciliationUseCase := usecase.NewSettlementReconciliationUseCase(&payments.MercadoPagoGateway{}, paymentRepo, reconciliationRunRepo)
+	cashClosingUseCase := usecase.NewCashClosingUseCase(paymentStatusEventRepo, paymentRepo)
+	receivablesAgingUseCase := usecase.NewReceivablesAgingUseCase(estimateRepo, paymentRepo)
+	conversionAnalyticsUseCase := usecase.NewConversionAnalyticsUseCase(estimateConversionRepo, estimateRepo, paymentRepo)
+	accountingConfig, err := accounting.LoadConfigFromEnv()
+	if err != nil {
+		log.Fatalf("failed to load accounting config: %v", err)
+	}
+	accountingExportUseCase := usecase.NewAccountingExportUseCase(paymentStatusEventRepo, paymentRepo, accountingExportRepo,
+		accountingConfig.ChartOfAccounts, accounting.NewFormatters(accountingConfig))
+	ledgerUseCase := usecase.NewLedgerUseCase(ledgerRepo, paymentRepo)
+	nfseBuilder, err := fiscal.NewBuilderFromEnv()
+	if err != nil {
+		log.Fatalf("failed to load NFS-e signing certificate: %v", err)
+	}
+	if nfseBuilder == nil {
+		log.Printf("NFSE_CERT_FILE not set; NFS-e generation disabled")
+	}
+	nfseTransmitter, err := fiscal.NewTransmitterFromEnv()
+	if err != nil {
+		log.Fatalf("failed to load NFS-e transmitter: %v", err)
+	}
+	nfseUseCase := usecase.NewNFSeUseCase(nfseRepo, invoiceRepo, nfseBuilder, nfseTransmitter).
+		WithDataProtection(security.NewRecordSealer(piiKeys), payerIndex)
+	approvalUseCase.WithDataProtection(security.NewRecordSealer(piiKeys), payerIndex)
+	dataSubjectUseCase.WithNFSeDocuments(nfseUseCase).WithApprovalLinks(approvalUseCase)
 
 	estimateHandler := handlers.NewEstimateHandler(estimateUseCase)
 	billingPaymentHandler := handlers.NewBillingPaymentHandler(paymentUseCase)
+	dataSubjectHandler := handlers.NewDataSubjectHandler(dataSubjectUseCase)
+	disputeHandler := handlers.NewDisputeHandler(disputeUseCase)
+	reconciliationHandler := handlers.NewReconciliationHandler(reconciliationUseCase)
+	cashClosingHandler := handlers.NewCashClosingHandler(cashClosingUseCase)
+	receivablesAgingHandler := handlers.NewReceivablesAgingHandler(receivablesAgingUseCase)
+	analyticsHandler := handlers.NewAnalyticsHandler(conversionAnalyticsUseCase)
+	accountingExportHandler := handlers.NewAccountingExportHandler(accountingExportUseCase)
+	ledgerHandler := handlers.NewLedgerHandler(ledgerUseCase)
+	invoiceHandler := handlers.NewInvoiceHandler(invoiceUseCase)
+	nfseHandler := handlers.NewNFSeHandler(nfseUseCase)
+	couponHandler := handlers.NewCouponHandler(couponUseCase)
+	catalogHandler := handlers.NewCatalogHandler(catalogUseCase)
+	approvalHandler := handlers.NewEstimateApprovalHandler(approvalUseCase)
 
 	// Rotas publicas
 	v1 := router.Group("/v1")
 	addPingRoutes(v1)
-	addBillingRoutes(v1, estimateHandler, billingPaymentHandler)
+	addBillingRoutes(v1, billingHandlers{
+		estimate:    estimateHandler,
+		payment:     billingPaymentHandler,
+		dataSubject: dataSubjectHandler,
+		dispute:     disputeHandler,
+
+		reconciliation: reconciliationHandler,
+		cashClosing:    cashClosingHandler,
+		receivables:    receivablesAgingHandler,
+		analytics:      analyticsHandler,
+		accounting:     accountingExportHandler,
+		ledger:         ledgerHandler,
+		invoice:        invoiceHandler,
+		nfse:           nfseHandler,
+		coupon:         couponHandler,
+		catalog:        catalogHandler,
+		approval:       approvalHandler,
+	})
 }
 
 func setMiddlewares() {
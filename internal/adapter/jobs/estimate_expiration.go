@@ -0,0 +1,40 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// EstimateExpirer expires the pending estimates past their validity
+// (usecase.IEstimateUseCase).
+type EstimateExpirer interface {
+	ExpireDue(ctx context.Context) (int, error)
+}
+
+// RunEstimateExpiration sweeps expired estimates every interval until ctx is done. The
+// sweep queries the pending estimates (status-index) and expires them with a conditional
+// write, so every API replica can run it. Failures are logged and retried on the next tick.
+func RunEstimateExpiration(ctx context.Context, expirer EstimateExpirer, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for ctx.Err() == nil {
+		sweepEstimates(ctx, expirer)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func sweepEstimates(ctx context.Context, expirer EstimateExpirer) {
+	expired, err := expirer.ExpireDue(ctx)
+	if err != nil {
+		log.Printf("[estimate][expiration] sweep failed expired=%d err=%v", expired, err)
+		return
+	}
+	if expired > 0 {
+		log.Printf("[estimate][expiration] expired=%d", expired)
+	}
+}
@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type expirerFunc func(ctx context.Context) (int, error)
+
+func (f expirerFunc) ExpireDue(ctx context.Context) (int, error) {
+	return f(ctx)
+}
+
+func TestRunEstimateExpiration(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sweeps := 0
+	expirer := expirerFunc(func(context.Context) (int, error) {
+		sweeps++
+		if sweeps == 3 {
+			cancel()
+		}
+		if sweeps == 1 {
+			return 0, errors.New("throttled")
+		}
+		return 1, nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		RunEstimateExpiration(ctx, expirer, time.Millisecond)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("sweeper did not stop")
+	}
+	if sweeps != 3 {
+		t.Fatalf("expected 3 sweeps, got %d", sweeps)
+	}
+}
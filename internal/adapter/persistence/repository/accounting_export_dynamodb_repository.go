@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	defaultAccountingExportsTableName       = "accounting_exports"
+	defaultAccountingExportEntriesTableName = "accounting_export_entries"
+	// accountingWatermarkID is the item holding the watermark, next to the runs.
+	accountingWatermarkID = "watermark"
+)
+
+type accountingExportItem struct {
+	ID                 string `dynamodbav:"id"`
+	Format             string `dynamodbav:"format"`
+	From               string `dynamodbav:"from"`
+	To                 string `dynamodbav:"to"`
+	EntryCount         int    `dynamodbav:"entry_count"`
+	BlockedByPaymentID string `dynamodbav:"blocked_by_payment_id,omitempty"`
+	Actor              string `dynamodbav:"actor,omitempty"`
+	CreatedAt          string `dynamodbav:"created_at"`
+}
+
+type journalEntryItem struct {
+	ExportID        string  `dynamodbav:"export_id"`
+	Seq             int     `dynamodbav:"seq"`
+	ID              string  `dynamodbav:"id"`
+	Kind            string  `dynamodbav:"kind"`
+	Date            string  `dynamodbav:"date"`
+	DebitAccount    string  `dynamodbav:"debit_account"`
+	CreditAccount   string  `dynamodbav:"credit_account"`
+	Amount          float64 `dynamodbav:"amount"`
+	PaymentID       string  `dynamodbav:"payment_id"`
+	EstimateID      string  `dynamodbav:"estimate_id"`
+	PaymentMethodID string  `dynamodbav:"payment_method_id,omitempty"`
+	Description     string  `dynamodbav:"description"`
+}
+
+// AccountingExportDynamoRepository persists accounting export runs in DynamoDB.
+//
+// Table requirements:
+//   - runs: PK id (string)
+//   - entries: PK export_id (string), SK seq (number)
+//
+// The watermark is the item id=watermark; it is only written together with a run, in a
+// transaction conditioned on its previous value. The journal entries are written before
+// that transaction: a run that loses the race leaves entries no run points to.
+
+type AccountingExportDynamoRepository struct {
+	ddb          *dynamodb.Client
+	tableName    string
+	entriesTable string
+}
+
+var _ interfaces.IAccountingExportRepository = (*AccountingExportDynamoRepository)(nil)
+
+func NewAccountingExportDynamoRepository(ddb *dynamodb.Client) *AccountingExportDynamoRepository {
+	return &AccountingExportDynamoRepository{
+		ddb:          ddb,
+		tableName:    getenvDefault("ACCOUNTING_EXPORTS_TABLE", defaultAccountingExportsTableName),
+		entriesTable: getenvDefault("ACCOUNTING_EXPORT_ENTRIES_TABLE", defaultAccountingExportEntriesTableName),
+	}
+}
+
+func (r *AccountingExportDynamoRepository) GetWatermark(ctx context.Context) (time.Time, error) {
+	out, err := r.ddb.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: accountingWatermarkID},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	var it struct {
+		Watermark string `dynamodbav:"watermark"`
+	}
+	if err := attributevalue.UnmarshalMap(out.Item, &it); err != nil {
+		return time.Time{}, err
+	}
+	if it.Watermark == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, it.Watermark)
+}
+
+func (r *AccountingExportDynamoRepository) Commit(ctx context.Context, run entities.AccountingExport, entries []entities.JournalEntry, previous time.Time) error {
+	items := make([]map[string]types.AttributeValue, 0, len(entries))
+	for i, e := range entries {
+		av, err := attributevalue.MarshalMap(toJournalEntryItem(run.ID, i+1, e))
+		if err != nil {
+			return err
+		}
+		items = append(items, av)
+	}
+	if err := batchPut(ctx, r.ddb, r.entriesTable, items); err != nil {
+		return err
+	}
+
+	av, err := attributevalue.MarshalMap(toAccountingExportItem(run))
+	if err != nil {
+		return err
+	}
+
+	condition := "attribute_not_exists(#watermark)"
+	values := map[string]types.AttributeValue{
+		":to": &types.AttributeValueMemberS{Value: run.To.UTC().Format(sortableTimeLayout)},
+	}
+	if !previous.IsZero() {
+		condition = "#watermark = :previous"
+		values[":previous"] = &types.AttributeValueMemberS{Value: previous.UTC().Format(sortableTimeLayout)}
+	}
+
+	_, err = r.ddb.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Put: &types.Put{
+					TableName:           aws.String(r.tableName),
+					Item:                av,
+					ConditionExpression: aws.String("attribute_not_exists(#id)"),
+					ExpressionAttributeNames: map[string]string{
+						"#id": "id",
+					},
+				},
+			},
+			{
+				Update: &types.Update{
+					TableName: aws.String(r.tableName),
+					Key: map[string]types.AttributeValue{
+						"id": &types.AttributeValueMemberS{Value: accountingWatermarkID},
+					},
+					ConditionExpression: aws.String(condition),
+					UpdateExpression:    aws.String("SET #watermark = :to"),
+					ExpressionAttributeNames: map[string]string{
+						"#watermark": "watermark",
+					},
+					ExpressionAttributeValues: values,
+				},
+			},
+		},
+	})
+	if err != nil {
+		var canceled *types.TransactionCanceledException
+		if errors.As(err, &canceled) {
+			return entities.ErrAccountingWatermarkMoved
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *AccountingExportDynamoRepository) GetByID(ctx context.Context, id string) (entities.AccountingExport, error) {
+	if id == accountingWatermarkID {
+		return entities.AccountingExport{}, nil
+	}
+	out, err := r.ddb.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return entities.AccountingExport{}, err
+	}
+	if len(out.Item) == 0 {
+		return entities.AccountingExport{}, nil
+	}
+
+	var it accountingExportItem
+	if err := attributevalue.UnmarshalMap(out.Item, &it); err != nil {
+		return entities.AccountingExport{}, err
+	}
+	return fromAccountingExportItem(it), nil
+}
+
+func (r *AccountingExportDynamoRepository) ListEntries(ctx context.Context, exportID string) ([]entities.JournalEntry, error) {
+	p := dynamodb.NewQueryPaginator(r.ddb, &dynamodb.QueryInput{
+		TableName:              aws.String(r.entriesTable),
+		KeyConditionExpression: aws.String("#export_id = :export_id"),
+		ExpressionAttributeNames: map[string]string{
+			"#export_id": "export_id",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":export_id": &types.AttributeValueMemberS{Value: exportID},
+		},
+	})
+	entries := []entities.JournalEntry{}
+	for p.HasMorePages() {
+		out, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var items []journalEntryItem
+		if err := attributevalue.UnmarshalListOfMaps(out.Items, &items); err != nil {
+			return nil, err
+		}
+		for _, it := range items {
+			entries = append(entries, fromJournalEntryItem(it))
+		}
+	}
+	return entries, nil
+}
+
+func toAccountingExportItem(run entities.AccountingExport) accountingExportItem {
+	return accountingExportItem{
+		ID:                 run.ID,
+		Format:             string(run.Format),
+		From:               run.From.UTC().Format(sortableTimeLayout),
+		To:                 run.To.UTC().Format(sortableTimeLayout),
+		EntryCount:         run.EntryCount,
+		BlockedByPaymentID: run.BlockedByPaymentID,
+		Actor:              run.Actor,
+		CreatedAt:          run.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+func fromAccountingExportItem(it accountingExportItem) entities.AccountingExport {
+	from, _ := time.Parse(time.RFC3339Nano, it.From)
+	to, _ := time.Parse(time.RFC3339Nano, it.To)
+	createdAt, _ := time.Parse(time.RFC3339Nano, it.CreatedAt)
+	return entities.AccountingExport{
+		ID:                 it.ID,
+		Format:             entities.AccountingExportFormat(it.Format),
+		From:               from,
+		To:                 to,
+		EntryCount:         it.EntryCount,
+		BlockedByPaymentID: it.BlockedByPaymentID,
+		Actor:              it.Actor,
+		CreatedAt:          createdAt,
+	}
+}
+
+func toJournalEntryItem(exportID string, seq int, e entities.JournalEntry) journalEntryItem {
+	return journalEntryItem{
+		ExportID:        exportID,
+		Seq:             seq,
+		ID:              e.ID,
+		Kind:            string(e.Kind),
+		Date:            e.Date.UTC().Format(time.RFC3339Nano),
+		DebitAccount:    e.DebitAccount,
+		CreditAccount:   e.CreditAccount,
+		Amount:          e.Amount,
+		PaymentID:       e.PaymentID,
+		EstimateID:      e.EstimateID,
+		PaymentMethodID: e.PaymentMethodID,
+		Description:     e.Description,
+	}
+}
+
+func fromJournalEntryItem(it journalEntryItem) entities.JournalEntry {
+	date, _ := time.Parse(time.RFC3339Nano, it.Date)
+	return entities.JournalEntry{
+		ID:              it.ID,
+		Kind:            entities.JournalEntryKind(it.Kind),
+		Date:            date,
+		DebitAccount:    it.DebitAccount,
+		CreditAccount:   it.CreditAccount,
+		Amount:          it.Amount,
+		PaymentID:       it.PaymentID,
+		EstimateID:      it.EstimateID,
+		PaymentMethodID: it.PaymentMethodID,
+		Description:     it.Description,
+	}
+}
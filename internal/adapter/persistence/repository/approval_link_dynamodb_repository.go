@@ -0,0 +1,320 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	defaultApprovalLinksTableName = "estimate_approval_links"
+
+	approvalLinksRecipientHashIndex = "recipient_hash-index"
+)
+
+// approvalLinkRetention is how long a link stays in the table after it expires, for
+// support; the decision evidence itself is kept on the estimate history.
+const approvalLinkRetention = 90 * 24 * time.Hour
+
+type approvalLinkItem struct {
+	ID             string                        `dynamodbav:"id"`
+	EstimateID     string                        `dynamodbav:"estimate_id"`
+	OSID           string                        `dynamodbav:"os_id"`
+	Channel        string                        `dynamodbav:"channel"`
+	Recipient      string                        `dynamodbav:"recipient"`
+	CreatedBy      string                        `dynamodbav:"created_by,omitempty"`
+	CreatedAt      string                        `dynamodbav:"created_at"`
+	UpdatedAt      string                        `dynamodbav:"updated_at"`
+	ExpiresAt      string                        `dynamodbav:"expires_at"`
+	OTPDigest      string                        `dynamodbav:"otp_digest,omitempty"`
+	OTPExpiresAt   string                        `dynamodbav:"otp_expires_at,omitempty"`
+	OTPSends       int                           `dynamodbav:"otp_sends"`
+	FailedAttempts int                           `dynamodbav:"failed_attempts"`
+	UsedAt         string                        `dynamodbav:"used_at,omitempty"`
+	Decision       string                        `dynamodbav:"decision,omitempty"`
+	Evidence       *estimateDecisionEvidenceItem `dynamodbav:"evidence,omitempty"`
+	TTL            int64                         `dynamodbav:"ttl"`
+	AnonymizedAt   string                        `dynamodbav:"anonymized_at,omitempty"`
+	PIIKeyID       string                        `dynamodbav:"pii_key_id,omitempty"`
+	PIIWrappedKey  []byte                        `dynamodbav:"pii_wrapped_key,omitempty"`
+	RecipientHash  string                        `dynamodbav:"recipient_hash,omitempty"`
+}
+
+// ApprovalLinkDynamoRepository persists ApprovalLink entities in DynamoDB.
+//
+// Table requirements:
+//   - PK: id (string)
+//   - TTL attribute: ttl (epoch seconds)
+//   - GSI: recipient_hash-index (PK: recipient_hash)
+
+type ApprovalLinkDynamoRepository struct {
+	ddb       *dynamodb.Client
+	tableName string
+}
+
+var _ interfaces.IApprovalLinkRepository = (*ApprovalLinkDynamoRepository)(nil)
+
+func NewApprovalLinkDynamoRepository(ddb *dynamodb.Client) *ApprovalLinkDynamoRepository {
+	return &ApprovalLinkDynamoRepository{
+		ddb:       ddb,
+		tableName: getenvDefault("APPROVAL_LINKS_TABLE", defaultApprovalLinksTableName),
+	}
+}
+
+func (r *ApprovalLinkDynamoRepository) Create(ctx context.Context, l entities.ApprovalLink) (entities.ApprovalLink, error) {
+	av, err := attributevalue.MarshalMap(toApprovalLinkItem(l))
+	if err != nil {
+		return entities.ApprovalLink{}, err
+	}
+
+	_, err = r.ddb.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(#id)"),
+		ExpressionAttributeNames: map[string]string{
+			"#id": "id",
+		},
+	})
+	if err != nil {
+		return entities.ApprovalLink{}, err
+	}
+	return l, nil
+}
+
+func (r *ApprovalLinkDynamoRepository) GetByID(ctx context.Context, id string) (entities.ApprovalLink, error) {
+	out, err := r.ddb.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return entities.ApprovalLink{}, err
+	}
+	if len(out.Item) == 0 {
+		return entities.ApprovalLink{}, nil
+	}
+
+	var it approvalLinkItem
+	if err := attributevalue.UnmarshalMap(out.Item, &it); err != nil {
+		return entities.ApprovalLink{}, err
+	}
+	return fromApprovalLinkItem(it), nil
+}
+
+// Update replaces the link if it was not written since previousUpdatedAt.
+func (r *ApprovalLinkDynamoRepository) Update(ctx context.Context, l entities.ApprovalLink, previousUpdatedAt time.Time) (entities.ApprovalLink, error) {
+	av, err := attributevalue.MarshalMap(toApprovalLinkItem(l))
+	if err != nil {
+		return entities.ApprovalLink{}, err
+	}
+
+	_, err = r.ddb.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("#updated_at = :previous"),
+		ExpressionAttributeNames: map[string]string{
+			"#updated_at": "updated_at",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":previous": &types.AttributeValueMemberS{Value: previousUpdatedAt.UTC().Format(time.RFC3339Nano)},
+		},
+	})
+	var cfe *types.ConditionalCheckFailedException
+	if errors.As(err, &cfe) {
+		return entities.ApprovalLink{}, entities.ErrApprovalLinkChanged
+	}
+	if err != nil {
+		return entities.ApprovalLink{}, err
+	}
+	return l, nil
+}
+
+// ListByRecipientHash returns the links sent to the recipient with the given blind index.
+// Local databases created before the index existed fall back to a filtered scan.
+func (r *ApprovalLinkDynamoRepository) ListByRecipientHash(ctx context.Context, hash string) ([]entities.ApprovalLink, error) {
+	names := map[string]string{"#hash": "recipient_hash"}
+	values := map[string]types.AttributeValue{
+		":hash": &types.AttributeValueMemberS{Value: hash},
+	}
+
+	var links []entities.ApprovalLink
+	useScan := false
+	var startKey map[string]types.AttributeValue
+	for {
+		var items []map[string]types.AttributeValue
+		var lastKey map[string]types.AttributeValue
+		if !useScan {
+			out, err := r.ddb.Query(ctx, &dynamodb.QueryInput{
+				TableName:                 aws.String(r.tableName),
+				IndexName:                 aws.String(approvalLinksRecipientHashIndex),
+				KeyConditionExpression:    aws.String("#hash = :hash"),
+				ExpressionAttributeNames:  names,
+				ExpressionAttributeValues: values,
+				ExclusiveStartKey:         startKey,
+			})
+			if isIndexNotAvailableError(err) {
+				useScan, startKey = true, nil
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			items, lastKey = out.Items, out.LastEvaluatedKey
+		} else {
+			out, err := r.ddb.Scan(ctx, &dynamodb.ScanInput{
+				TableName:                 aws.String(r.tableName),
+				FilterExpression:          aws.String("#hash = :hash"),
+				ExpressionAttributeNames:  names,
+				ExpressionAttributeValues: values,
+				ExclusiveStartKey:         startKey,
+			})
+			if err != nil {
+				return nil, err
+			}
+			items, lastKey = out.Items, out.LastEvaluatedKey
+		}
+		for _, item := range items {
+			var it approvalLinkItem
+			if err := attributevalue.UnmarshalMap(item, &it); err != nil {
+				return nil, err
+			}
+			links = append(links, fromApprovalLinkItem(it))
+		}
+		if len(lastKey) == 0 {
+			return links, nil
+		}
+		startKey = lastKey
+	}
+}
+
+// Anonymize removes the recipient, its blind index, the evidence IP/user agent and the
+// data key of l, keeping the decision and the masked recipient. Like Update it is
+// conditioned on the updated_at l was read with.
+func (r *ApprovalLinkDynamoRepository) Anonymize(ctx context.Context, l entities.ApprovalLink, at time.Time) error {
+	names := map[string]string{
+		"#recipient":     "recipient",
+		"#hash":          "recipient_hash",
+		"#key_id":        "pii_key_id",
+		"#wrapped_key":   "pii_wrapped_key",
+		"#anonymized_at": "anonymized_at",
+		"#updated_at":    "updated_at",
+	}
+	remove := "#recipient, #hash, #key_id, #wrapped_key"
+	if l.Evidence != nil {
+		names["#evidence"], names["#ip"], names["#user_agent"] = "evidence", "ip", "user_agent"
+		remove += ", #evidence.#ip, #evidence.#user_agent"
+	}
+	_, err := r.ddb.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: l.ID},
+		},
+		ConditionExpression:      aws.String("#updated_at = :previous"),
+		UpdateExpression:         aws.String("SET #anonymized_at = :at, #updated_at = :at REMOVE " + remove),
+		ExpressionAttributeNames: names,
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":at":       &types.AttributeValueMemberS{Value: at.UTC().Format(time.RFC3339Nano)},
+			":previous": &types.AttributeValueMemberS{Value: l.UpdatedAt.UTC().Format(time.RFC3339Nano)},
+		},
+	})
+	var cfe *types.ConditionalCheckFailedException
+	if errors.As(err, &cfe) {
+		return entities.ErrApprovalLinkChanged
+	}
+	return err
+}
+
+func toApprovalLinkItem(l entities.ApprovalLink) approvalLinkItem {
+	it := approvalLinkItem{
+		ID:             l.ID,
+		EstimateID:     l.EstimateID,
+		OSID:           l.OSID,
+		Channel:        string(l.Channel),
+		Recipient:      l.Recipient,
+		CreatedBy:      l.CreatedBy,
+		CreatedAt:      l.CreatedAt.UTC().Format(time.RFC3339Nano),
+		UpdatedAt:      l.UpdatedAt.UTC().Format(time.RFC3339Nano),
+		ExpiresAt:      l.ExpiresAt.UTC().Format(time.RFC3339Nano),
+		OTPDigest:      l.OTPDigest,
+		OTPSends:       l.OTPSends,
+		FailedAttempts: l.FailedAttempts,
+		Decision:       string(l.Decision),
+		TTL:            l.ExpiresAt.Add(approvalLinkRetention).Unix(),
+		RecipientHash:  l.RecipientHash,
+	}
+	if l.DataKey != nil {
+		it.PIIKeyID = l.DataKey.KeyID
+		it.PIIWrappedKey = l.DataKey.WrappedKey
+	}
+	if l.AnonymizedAt != nil {
+		it.AnonymizedAt = l.AnonymizedAt.UTC().Format(time.RFC3339Nano)
+	}
+	if l.OTPExpiresAt != nil {
+		it.OTPExpiresAt = l.OTPExpiresAt.UTC().Format(time.RFC3339Nano)
+	}
+	if l.UsedAt != nil {
+		it.UsedAt = l.UsedAt.UTC().Format(time.RFC3339Nano)
+	}
+	if l.Evidence != nil {
+		it.Evidence = &estimateDecisionEvidenceItem{
+			LinkID:    l.Evidence.LinkID,
+			IP:        l.Evidence.IP,
+			UserAgent: l.Evidence.UserAgent,
+			Recipient: l.Evidence.Recipient,
+		}
+	}
+	return it
+}
+
+func fromApprovalLinkItem(it approvalLinkItem) entities.ApprovalLink {
+	createdAt, _ := time.Parse(time.RFC3339Nano, it.CreatedAt)
+	updatedAt, _ := time.Parse(time.RFC3339Nano, it.UpdatedAt)
+	expiresAt, _ := time.Parse(time.RFC3339Nano, it.ExpiresAt)
+	l := entities.ApprovalLink{
+		ID:             it.ID,
+		EstimateID:     it.EstimateID,
+		OSID:           it.OSID,
+		Channel:        entities.NotificationChannel(it.Channel),
+		Recipient:      it.Recipient,
+		CreatedBy:      it.CreatedBy,
+		CreatedAt:      createdAt,
+		UpdatedAt:      updatedAt,
+		ExpiresAt:      expiresAt,
+		OTPDigest:      it.OTPDigest,
+		OTPSends:       it.OTPSends,
+		FailedAttempts: it.FailedAttempts,
+		Decision:       entities.EstimateStatus(it.Decision),
+		RecipientHash:  it.RecipientHash,
+	}
+	if it.PIIKeyID != "" {
+		l.DataKey = &entities.EncryptedDataKey{KeyID: it.PIIKeyID, WrappedKey: it.PIIWrappedKey}
+	}
+	if t, err := time.Parse(time.RFC3339Nano, it.AnonymizedAt); err == nil {
+		l.AnonymizedAt = &t
+	}
+	if t, err := time.Parse(time.RFC3339Nano, it.OTPExpiresAt); err == nil {
+		l.OTPExpiresAt = &t
+	}
+	if t, err := time.Parse(time.RFC3339Nano, it.UsedAt); err == nil {
+		l.UsedAt = &t
+	}
+	if it.Evidence != nil {
+		l.Evidence = &entities.DecisionEvidence{
+			LinkID:    it.Evidence.LinkID,
+			IP:        it.Evidence.IP,
+			UserAgent: it.Evidence.UserAgent,
+			Recipient: it.Evidence.Recipient,
+		}
+	}
+	return l
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+const defaultAuditLogsTableName = "audit_logs"
+
+type auditRecordItem struct {
+	ID        string            `dynamodbav:"id"`
+	Action    string            `dynamodbav:"action"`
+	Actor     string            `dynamodbav:"actor"`
+	Subject   string            `dynamodbav:"subject"`
+	Metadata  map[string]string `dynamodbav:"metadata,omitempty"`
+	CreatedAt string            `dynamodbav:"created_at"`
+}
+
+// AuditLogDynamoRepository persists AuditRecord entities in DynamoDB.
+//
+// Table requirements:
+//   - PK: id (string)
+//
+// Records are never updated; Create refuses to overwrite an existing id.
+
+type AuditLogDynamoRepository struct {
+	ddb       *dynamodb.Client
+	tableName string
+}
+
+var _ interfaces.IAuditLogRepository = (*AuditLogDynamoRepository)(nil)
+
+func NewAuditLogDynamoRepository(ddb *dynamodb.Client) *AuditLogDynamoRepository {
+	return &AuditLogDynamoRepository{
+		ddb:       ddb,
+		tableName: getenvDefault("AUDIT_LOGS_TABLE", defaultAuditLogsTableName),
+	}
+}
+
+func (r *AuditLogDynamoRepository) Create(ctx context.Context, rec entities.AuditRecord) error {
+	av, err := attributevalue.MarshalMap(auditRecordItem{
+		ID:        rec.ID,
+		Action:    string(rec.Action),
+		Actor:     rec.Actor,
+		Subject:   rec.Subject,
+		Metadata:  rec.Metadata,
+		CreatedAt: rec.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.ddb.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(#id)"),
+		ExpressionAttributeNames: map[string]string{
+			"#id": "id",
+		},
+	})
+	return err
+}
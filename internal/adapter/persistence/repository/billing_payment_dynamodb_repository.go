@@ -2,6 +2,10 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"mecanica_xpto/internal/domain/entities"
@@ -14,8 +18,12 @@ import (
 )
 
 const (
-	defaultPaymentsTableName = "payments"
-	paymentsEstimateIDIndex  = "estimate_id-index"
+	defaultPaymentsTableName  = "payments"
+	defaultPaymentOutboxTable = "payment_outbox"
+	paymentsEstimateDateIndex = "estimate_id-date-index"
+	paymentsStatusDateIndex   = "status-date-index"
+	paymentsPayerEmailIndex   = "payer_email_hash-index"
+	paymentsPayerDocHashIndex = "payer_doc_hash-index"
 )
 
 type billingPaymentItem struct {
@@ -25,47 +33,231 @@ type billingPaymentItem struct {
 	Status       string                 `dynamodbav:"status"`
 	MPPayload    map[string]interface{} `dynamodbav:"mp_payload,omitempty"`
 	MPPayloadRaw string                 `dynamodbav:"mp_payload_raw,omitempty"`
+
+	// Envelope encryption of payer personal data (see entities.SensitivePayloadPaths).
+	PIIKeyID      string `dynamodbav:"pii_key_id,omitempty"`
+	PIIWrappedKey []byte `dynamodbav:"pii_wrapped_key,omitempty"`
+	AnonymizedAt  string `dynamodbav:"anonymized_at,omitempty"`
+
+	paymentDetailsItem
+}
+
+// paymentDetailsItem holds the typed fields extracted from the provider payload.
+// Attributes are flattened on the payment item so they can be used in filters/indexes.
+type paymentDetailsItem struct {
+	Amount            float64          `dynamodbav:"amount,omitempty"`
+	NetReceivedAmount float64          `dynamodbav:"net_received_amount,omitempty"`
+	Fees              []paymentFeeItem `dynamodbav:"fees,omitempty"`
+	PaymentMethodID   string           `dynamodbav:"payment_method_id,omitempty"`
+	PaymentTypeID     string           `dynamodbav:"payment_type_id,omitempty"`
+	Installments      int              `dynamodbav:"installments,omitempty"`
+	CardLastFour      string           `dynamodbav:"card_last_four,omitempty"`
+	CardBrand         string           `dynamodbav:"card_brand,omitempty"`
+	PayerEmail        string           `dynamodbav:"payer_email,omitempty"`
+	PayerDocType      string           `dynamodbav:"payer_doc_type,omitempty"`
+	PayerDocNumber    string           `dynamodbav:"payer_doc_number,omitempty"`
+	ApprovedAt        string           `dynamodbav:"approved_at,omitempty"`
+	PayerEmailHash    string           `dynamodbav:"payer_email_hash,omitempty"`
+	PayerDocHash      string           `dynamodbav:"payer_doc_hash,omitempty"`
+}
+
+// paymentOutboxItem is the posting of a payment created without it (see
+// CreateWithPendingPosting), kept until CompletePosting writes it.
+type paymentOutboxItem struct {
+	PaymentID string                 `dynamodbav:"payment_id"`
+	Event     paymentStatusEventItem `dynamodbav:"event"`
+	Entry     *ledgerEntryItem       `dynamodbav:"entry,omitempty"`
+	CreatedAt string                 `dynamodbav:"created_at"`
+}
+
+type paymentFeeItem struct {
+	Type   string  `dynamodbav:"type"`
+	Payer  string  `dynamodbav:"payer"`
+	Amount float64 `dynamodbav:"amount"`
 }
 
 // BillingPaymentDynamoRepository persists BillingPayment entities in DynamoDB.
 //
 // Table requirements:
 //   - PK: id (string)
-//   - GSI: estimate_id-index (PK: estimate_id)
+//   - GSI: estimate_id-date-index (PK: estimate_id, SK: date)
+//   - GSI: status-date-index (PK: status, SK: date)
+//   - GSI: payer_email_hash-index (PK: payer_email_hash)
+//   - GSI: payer_doc_hash-index (PK: payer_doc_hash)
+//
+// Payment writes also go to the payment_status_events table and the ledger tables (see
+// LedgerDynamoRepository). The payment_outbox table (PK payment_id) holds the postings of
+// payments created without them.
 
 type BillingPaymentDynamoRepository struct {
-	ddb       *dynamodb.Client
-	tableName string
+	ddb         *dynamodb.Client
+	tableName   string
+	eventsTable string
+	outboxTable string
+	ledger      ledgerTables
 }
 
 var _ interfaces.IBillingPaymentRepository = (*BillingPaymentDynamoRepository)(nil)
 
 func NewBillingPaymentDynamoRepository(ddb *dynamodb.Client) *BillingPaymentDynamoRepository {
 	return &BillingPaymentDynamoRepository{
-		ddb:       ddb,
-		tableName: getenvDefault("PAYMENTS_TABLE", defaultPaymentsTableName),
+		ddb:         ddb,
+		tableName:   getenvDefault("PAYMENTS_TABLE", defaultPaymentsTableName),
+		eventsTable: getenvDefault("PAYMENT_STATUS_EVENTS_TABLE", defaultPaymentStatusEventsTableName),
+		outboxTable: getenvDefault("PAYMENT_OUTBOX_TABLE", defaultPaymentOutboxTable),
+		ledger:      newLedgerTables(),
+	}
+}
+
+// CreateWithPosting puts a new payment, its status event and its ledger entry (when not
+// nil) in a single transaction. It fails with entities.ErrPaymentAlreadyExists when the
+// ID is taken and with entities.ErrLedgerConflict when the entry was already posted.
+func (r *BillingPaymentDynamoRepository) CreateWithPosting(ctx context.Context, p entities.BillingPayment, posting entities.PaymentPosting) (entities.BillingPayment, error) {
+	paymentPut, err := r.paymentCreatePut(p)
+	if err != nil {
+		return entities.BillingPayment{}, err
+	}
+	items, err := r.postingItems(posting)
+	if err != nil {
+		return entities.BillingPayment{}, err
+	}
+	items = append([]types.TransactWriteItem{{Put: paymentPut}}, items...)
+	if err := r.ledger.transact(ctx, r.ddb, items); err != nil {
+		if errors.Is(err, entities.ErrPaymentStatusChanged) {
+			return entities.BillingPayment{}, entities.ErrPaymentAlreadyExists
+		}
+		return entities.BillingPayment{}, err
 	}
+	return p, nil
 }
 
-func (r *BillingPaymentDynamoRepository) Create(ctx context.Context, p entities.BillingPayment) (entities.BillingPayment, error) {
-	it := toBillingPaymentItem(p)
+// CreateWithPendingPosting puts a new payment and its posting in the outbox, in a single
+// transaction that touches no ledger balance. It fails with
+// entities.ErrPaymentAlreadyExists when the ID is taken.
+func (r *BillingPaymentDynamoRepository) CreateWithPendingPosting(ctx context.Context, p entities.BillingPayment, posting entities.PaymentPosting) (entities.BillingPayment, error) {
+	paymentPut, err := r.paymentCreatePut(p)
+	if err != nil {
+		return entities.BillingPayment{}, err
+	}
+	it := paymentOutboxItem{
+		PaymentID: p.ID,
+		Event:     toPaymentStatusEventItem(posting.Event),
+		CreatedAt: time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	if posting.Entry != nil {
+		entry := toLedgerEntryItem(*posting.Entry)
+		it.Entry = &entry
+	}
 	av, err := attributevalue.MarshalMap(it)
 	if err != nil {
 		return entities.BillingPayment{}, err
 	}
 
-	_, err = r.ddb.PutItem(ctx, &dynamodb.PutItemInput{
+	_, err = r.ddb.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Put: paymentPut},
+			{Put: &types.Put{TableName: aws.String(r.outboxTable), Item: av}},
+		},
+	})
+	var canceled *types.TransactionCanceledException
+	if errors.As(err, &canceled) && len(canceled.CancellationReasons) > 0 &&
+		aws.ToString(canceled.CancellationReasons[0].Code) == "ConditionalCheckFailed" {
+		return entities.BillingPayment{}, entities.ErrPaymentAlreadyExists
+	}
+	if err != nil {
+		return entities.BillingPayment{}, err
+	}
+	return p, nil
+}
+
+// ListPendingPostings returns the postings waiting in the outbox.
+func (r *BillingPaymentDynamoRepository) ListPendingPostings(ctx context.Context) ([]entities.PaymentPosting, error) {
+	postings := []entities.PaymentPosting{}
+	var startKey map[string]types.AttributeValue
+	for {
+		out, err := r.ddb.Scan(ctx, &dynamodb.ScanInput{
+			TableName:         aws.String(r.outboxTable),
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, raw := range out.Items {
+			var it paymentOutboxItem
+			if err := attributevalue.UnmarshalMap(raw, &it); err != nil {
+				return nil, err
+			}
+			posting := entities.PaymentPosting{Event: fromPaymentStatusEventItem(it.Event)}
+			if it.Entry != nil {
+				entry := fromLedgerEntryItem(*it.Entry)
+				posting.Entry = &entry
+			}
+			postings = append(postings, posting)
+		}
+		if len(out.LastEvaluatedKey) == 0 {
+			return postings, nil
+		}
+		startKey = out.LastEvaluatedKey
+	}
+}
+
+// CompletePosting writes a posting from the outbox and removes it, in a single
+// transaction. A posting already completed is left alone; it fails with
+// entities.ErrLedgerConflict when the entry was posted by other means (the backfill).
+func (r *BillingPaymentDynamoRepository) CompletePosting(ctx context.Context, posting entities.PaymentPosting) error {
+	items, err := r.postingItems(posting)
+	if err != nil {
+		return err
+	}
+	items = append([]types.TransactWriteItem{{
+		Delete: &types.Delete{
+			TableName: aws.String(r.outboxTable),
+			Key: map[string]types.AttributeValue{
+				"payment_id": &types.AttributeValueMemberS{Value: posting.Event.PaymentID},
+			},
+			ConditionExpression: aws.String("attribute_exists(#payment_id)"),
+			ExpressionAttributeNames: map[string]string{
+				"#payment_id": "payment_id",
+			},
+		},
+	}}, items...)
+	err = r.ledger.transact(ctx, r.ddb, items)
+	if errors.Is(err, entities.ErrPaymentStatusChanged) {
+		return nil
+	}
+	return err
+}
+
+func (r *BillingPaymentDynamoRepository) paymentCreatePut(p entities.BillingPayment) (*types.Put, error) {
+	av, err := attributevalue.MarshalMap(toBillingPaymentItem(p))
+	if err != nil {
+		return nil, err
+	}
+	return &types.Put{
 		TableName:           aws.String(r.tableName),
 		Item:                av,
 		ConditionExpression: aws.String("attribute_not_exists(#id)"),
 		ExpressionAttributeNames: map[string]string{
 			"#id": "id",
 		},
-	})
+	}, nil
+}
+
+// postingItems are the status event put and the ledger items of a posting.
+func (r *BillingPaymentDynamoRepository) postingItems(posting entities.PaymentPosting) ([]types.TransactWriteItem, error) {
+	eventPut, err := paymentStatusEventPut(r.eventsTable, posting.Event)
 	if err != nil {
-		return entities.BillingPayment{}, err
+		return nil, err
 	}
-	return p, nil
+	items := []types.TransactWriteItem{{Put: eventPut}}
+	if posting.Entry != nil {
+		ledgerItems, err := r.ledger.writeItems(*posting.Entry)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, ledgerItems...)
+	}
+	return items, nil
 }
 
 func (r *BillingPaymentDynamoRepository) GetByID(ctx context.Context, id string) (entities.BillingPayment, error) {
@@ -90,21 +282,356 @@ func (r *BillingPaymentDynamoRepository) GetByID(ctx context.Context, id string)
 	return fromBillingPaymentItem(it), nil
 }
 
-func (r *BillingPaymentDynamoRepository) ListByEstimateID(ctx context.Context, estimateID string) ([]entities.BillingPayment, error) {
-	out, err := r.ddb.Query(ctx, &dynamodb.QueryInput{
-		TableName:              aws.String(r.tableName),
-		IndexName:              aws.String(paymentsEstimateIDIndex),
-		KeyConditionExpression: aws.String("estimate_id = :eid"),
-		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":eid": &types.AttributeValueMemberS{Value: estimateID},
+// ListByEstimateID returns a page of the estimate payments ordered by date.
+func (r *BillingPaymentDynamoRepository) ListByEstimateID(ctx context.Context, estimateID string, page entities.PageRequest) (entities.Page[entities.BillingPayment], error) {
+	return r.queryPage(ctx, paymentsEstimateDateIndex, "#estimate_id = :estimate_id",
+		map[string]string{"#estimate_id": "estimate_id"},
+		map[string]types.AttributeValue{":estimate_id": &types.AttributeValueMemberS{Value: estimateID}},
+		page, "id", "estimate_id", "date")
+}
+
+// ListByStatus returns a page of payments with the given status, ordered by date and
+// optionally restricted to [from, to].
+func (r *BillingPaymentDynamoRepository) ListByStatus(ctx context.Context, status entities.PaymentStatus, from, to *time.Time, page entities.PageRequest) (entities.Page[entities.BillingPayment], error) {
+	names := map[string]string{"#status": "status"}
+	values := map[string]types.AttributeValue{
+		":status": &types.AttributeValueMemberS{Value: string(status)},
+	}
+	cond := "#status = :status"
+	switch {
+	case from != nil && to != nil:
+		cond += " AND #date BETWEEN :from AND :to"
+	case from != nil:
+		cond += " AND #date >= :from"
+	case to != nil:
+		cond += " AND #date <= :to"
+	}
+	if from != nil {
+		names["#date"] = "date"
+		values[":from"] = &types.AttributeValueMemberS{Value: from.UTC().Format(sortableTimeLayout)}
+	}
+	if to != nil {
+		names["#date"] = "date"
+		values[":to"] = &types.AttributeValueMemberS{Value: to.UTC().Format(sortableTimeLayout)}
+	}
+	return r.queryPage(ctx, paymentsStatusDateIndex, cond, names, values, page, "id", "status", "date")
+}
+
+func (r *BillingPaymentDynamoRepository) queryPage(
+	ctx context.Context,
+	index, cond string,
+	names map[string]string,
+	values map[string]types.AttributeValue,
+	page entities.PageRequest,
+	cursorAttrs ...string,
+) (entities.Page[entities.BillingPayment], error) {
+	startKey, err := decodePageCursor(page.Cursor, cursorAttrs...)
+	if err != nil {
+		return entities.Page[entities.BillingPayment]{}, err
+	}
+
+	in := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.tableName),
+		IndexName:                 aws.String(index),
+		KeyConditionExpression:    aws.String(cond),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+		ScanIndexForward:          aws.Bool(!page.Descending),
+		ExclusiveStartKey:         startKey,
+	}
+	if page.Limit > 0 {
+		in.Limit = aws.Int32(page.Limit)
+	}
+	out, err := r.ddb.Query(ctx, in)
+	if err != nil {
+		return entities.Page[entities.BillingPayment]{}, err
+	}
+
+	items, err := unmarshalBillingPayments(out.Items)
+	if err != nil {
+		return entities.Page[entities.BillingPayment]{}, err
+	}
+	next, err := encodePageCursor(out.LastEvaluatedKey)
+	if err != nil {
+		return entities.Page[entities.BillingPayment]{}, err
+	}
+	return entities.Page[entities.BillingPayment]{Items: items, NextCursor: next}, nil
+}
+
+// Replace overwrites an existing payment (used by data migrations).
+func (r *BillingPaymentDynamoRepository) Replace(ctx context.Context, p entities.BillingPayment) error {
+	av, err := attributevalue.MarshalMap(toBillingPaymentItem(p))
+	if err != nil {
+		return err
+	}
+
+	_, err = r.ddb.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_exists(#id)"),
+		ExpressionAttributeNames: map[string]string{
+			"#id": "id",
 		},
 	})
+	return err
+}
+
+// ScanPage reads payments in table order, starting after the given id (empty = first page).
+// It returns the id to resume from, or "" when the table was fully read.
+func (r *BillingPaymentDynamoRepository) ScanPage(ctx context.Context, startAfterID string, limit int32) ([]entities.BillingPayment, string, error) {
+	return r.scanPage(ctx, &dynamodb.ScanInput{
+		TableName: aws.String(r.tableName),
+		Limit:     aws.Int32(limit),
+	}, startAfterID)
+}
+
+// ScanLegacyDatePage scans the payments whose date sort key predates the fixed-width
+// layout: RFC3339Nano drops trailing zeros, so those dates are shorter than
+// sortableTimeLayout (a full-width one is already in the layout). The filter applies after
+// limit, so a page may be empty while the scan goes on.
+func (r *BillingPaymentDynamoRepository) ScanLegacyDatePage(ctx context.Context, startAfterID string, limit int32) ([]entities.BillingPayment, string, error) {
+	return r.scanPage(ctx, &dynamodb.ScanInput{
+		TableName:                 aws.String(r.tableName),
+		Limit:                     aws.Int32(limit),
+		FilterExpression:          aws.String("size(#date) <> :width"),
+		ExpressionAttributeNames:  map[string]string{"#date": "date"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{":width": &types.AttributeValueMemberN{Value: strconv.Itoa(len(sortableTimeLayout))}},
+	}, startAfterID)
+}
+
+func (r *BillingPaymentDynamoRepository) scanPage(ctx context.Context, in *dynamodb.ScanInput, startAfterID string) ([]entities.BillingPayment, string, error) {
+	if startAfterID != "" {
+		in.ExclusiveStartKey = map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: startAfterID},
+		}
+	}
+
+	out, err := r.ddb.Scan(ctx, in)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	items := make([]entities.BillingPayment, 0, len(out.Items))
 	for _, raw := range out.Items {
+		var it billingPaymentItem
+		if err := attributevalue.UnmarshalMap(raw, &it); err != nil {
+			return nil, "", err
+		}
+		items = append(items, fromBillingPaymentItem(it))
+	}
+
+	next := ""
+	if v, ok := out.LastEvaluatedKey["id"].(*types.AttributeValueMemberS); ok {
+		next = v.Value
+	}
+	return items, next, nil
+}
+
+// RewriteDate writes the date sort key of an existing payment in the fixed-width layout.
+// It is a no-op when the key is already in it, so the migration can run again safely.
+func (r *BillingPaymentDynamoRepository) RewriteDate(ctx context.Context, id string, date time.Time) error {
+	_, err := r.ddb.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		ConditionExpression:      aws.String("attribute_exists(#id) AND #date <> :date"),
+		UpdateExpression:         aws.String("SET #date = :date"),
+		ExpressionAttributeNames: map[string]string{"#id": "id", "#date": "date"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":date": &types.AttributeValueMemberS{Value: date.UTC().Format(sortableTimeLayout)},
+		},
+	})
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		return nil
+	}
+	return err
+}
+
+// UpdateDetails overwrites the typed payment detail attributes of an existing payment.
+func (r *BillingPaymentDynamoRepository) UpdateDetails(ctx context.Context, id string, details entities.PaymentDetails) error {
+	av, err := attributevalue.MarshalMap(toPaymentDetailsItem(details))
+	if err != nil {
+		return err
+	}
+
+	names := map[string]string{"#id": "id"}
+	values := map[string]types.AttributeValue{}
+	var sets []string
+	for attr, v := range av {
+		names["#"+attr] = attr
+		values[":"+attr] = v
+		sets = append(sets, "#"+attr+" = :"+attr)
+	}
+	if len(sets) == 0 {
+		return nil
+	}
+	sort.Strings(sets)
+
+	_, err = r.ddb.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		ConditionExpression:       aws.String("attribute_exists(#id)"),
+		UpdateExpression:          aws.String("SET " + strings.Join(sets, ", ")),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	})
+	return err
+}
+
+// UpdateStatusWithEvent moves the payment from event.PreviousStatus to event.Status,
+// appends event to the status history and, when entry is not nil, posts it, all in a
+// single transaction. It fails with entities.ErrPaymentStatusChanged when the payment is
+// no longer in the previous status and with entities.ErrLedgerConflict when the entry
+// was already posted.
+func (r *BillingPaymentDynamoRepository) UpdateStatusWithEvent(ctx context.Context, event entities.PaymentStatusEvent, entry *entities.LedgerEntry) error {
+	items, err := r.postingItems(entities.PaymentPosting{Event: event, Entry: entry})
+	if err != nil {
+		return err
+	}
+	items = append([]types.TransactWriteItem{{
+		Update: &types.Update{
+			TableName: aws.String(r.tableName),
+			Key: map[string]types.AttributeValue{
+				"id": &types.AttributeValueMemberS{Value: event.PaymentID},
+			},
+			ConditionExpression: aws.String("#status = :previous"),
+			UpdateExpression:    aws.String("SET #status = :status"),
+			ExpressionAttributeNames: map[string]string{
+				"#status": "status",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":previous": &types.AttributeValueMemberS{Value: string(event.PreviousStatus)},
+				":status":   &types.AttributeValueMemberS{Value: string(event.Status)},
+			},
+		},
+	}}, items...)
+	return r.ledger.transact(ctx, r.ddb, items)
+}
+
+// Anonymize erases the payer personal data of p, as read, leaving every other attribute
+// as stored: the sensitive payload fields are replaced and the payer fields, their hashes
+// and the data key removed. It fails with entities.ErrPaymentStatusChanged when the
+// payment changed status or was anonymized since read.
+func (r *BillingPaymentDynamoRepository) Anonymize(ctx context.Context, p entities.BillingPayment, at time.Time) error {
+	anonymized := toBillingPaymentItem(p.Anonymized(at))
+	names := map[string]string{
+		"#status":        "status",
+		"#anonymized_at": "anonymized_at",
+		"#payer_email":   "payer_email",
+		"#payer_doc":     "payer_doc_number",
+		"#email_hash":    "payer_email_hash",
+		"#doc_hash":      "payer_doc_hash",
+		"#key_id":        "pii_key_id",
+		"#wrapped_key":   "pii_wrapped_key",
+	}
+	values := map[string]types.AttributeValue{
+		":status": &types.AttributeValueMemberS{Value: string(p.Status)},
+		":at":     &types.AttributeValueMemberS{Value: anonymized.AnonymizedAt},
+	}
+	sets := []string{"#anonymized_at = :at"}
+	if anonymized.MPPayload != nil {
+		payload, err := attributevalue.Marshal(anonymized.MPPayload)
+		if err != nil {
+			return err
+		}
+		names["#payload"], values[":payload"] = "mp_payload", payload
+		sets = append(sets, "#payload = :payload")
+	}
+	if anonymized.MPPayloadRaw != "" {
+		names["#payload_raw"] = "mp_payload_raw"
+		values[":payload_raw"] = &types.AttributeValueMemberS{Value: anonymized.MPPayloadRaw}
+		sets = append(sets, "#payload_raw = :payload_raw")
+	}
+
+	_, err := r.ddb.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: p.ID},
+		},
+		ConditionExpression:       aws.String("#status = :status AND attribute_not_exists(#anonymized_at)"),
+		UpdateExpression:          aws.String("SET " + strings.Join(sets, ", ") + " REMOVE #payer_email, #payer_doc, #email_hash, #doc_hash, #key_id, #wrapped_key"),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	})
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		return entities.ErrPaymentStatusChanged
+	}
+	return err
+}
+
+func (r *BillingPaymentDynamoRepository) ListByPayerEmailHash(ctx context.Context, hash string) ([]entities.BillingPayment, error) {
+	return r.listByAttribute(ctx, paymentsPayerEmailIndex, "payer_email_hash", hash)
+}
+
+func (r *BillingPaymentDynamoRepository) ListByPayerDocHash(ctx context.Context, hash string) ([]entities.BillingPayment, error) {
+	return r.listByAttribute(ctx, paymentsPayerDocHashIndex, "payer_doc_hash", hash)
+}
+
+// listByAttribute queries a single-attribute GSI, following pagination. Local databases
+// created before the index existed fall back to a filtered scan.
+func (r *BillingPaymentDynamoRepository) listByAttribute(ctx context.Context, index, attr, value string) ([]entities.BillingPayment, error) {
+	names := map[string]string{"#attr": attr}
+	values := map[string]types.AttributeValue{
+		":v": &types.AttributeValueMemberS{Value: value},
+	}
+
+	var raws []map[string]types.AttributeValue
+	var startKey map[string]types.AttributeValue
+	for {
+		out, err := r.ddb.Query(ctx, &dynamodb.QueryInput{
+			TableName:                 aws.String(r.tableName),
+			IndexName:                 aws.String(index),
+			KeyConditionExpression:    aws.String("#attr = :v"),
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+			ExclusiveStartKey:         startKey,
+		})
+		if err != nil {
+			if !isIndexNotAvailableError(err) {
+				return nil, err
+			}
+			return r.scanByFilter(ctx, "#attr = :v", names, values)
+		}
+		raws = append(raws, out.Items...)
+		if len(out.LastEvaluatedKey) == 0 {
+			break
+		}
+		startKey = out.LastEvaluatedKey
+	}
+	return unmarshalBillingPayments(raws)
+}
+
+func (r *BillingPaymentDynamoRepository) scanByFilter(ctx context.Context, filter string, names map[string]string, values map[string]types.AttributeValue) ([]entities.BillingPayment, error) {
+	var raws []map[string]types.AttributeValue
+	var startKey map[string]types.AttributeValue
+	for {
+		out, err := r.ddb.Scan(ctx, &dynamodb.ScanInput{
+			TableName:                 aws.String(r.tableName),
+			FilterExpression:          aws.String(filter),
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+			ExclusiveStartKey:         startKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+		raws = append(raws, out.Items...)
+		if len(out.LastEvaluatedKey) == 0 {
+			break
+		}
+		startKey = out.LastEvaluatedKey
+	}
+	return unmarshalBillingPayments(raws)
+}
+
+func unmarshalBillingPayments(raws []map[string]types.AttributeValue) ([]entities.BillingPayment, error) {
+	items := make([]entities.BillingPayment, 0, len(raws))
+	for _, raw := range raws {
 		var it billingPaymentItem
 		if err := attributevalue.UnmarshalMap(raw, &it); err != nil {
 			return nil, err
@@ -115,24 +642,93 @@ func (r *BillingPaymentDynamoRepository) ListByEstimateID(ctx context.Context, e
 }
 
 func toBillingPaymentItem(p entities.BillingPayment) billingPaymentItem {
-	return billingPaymentItem{
-		ID:           p.ID,
-		EstimateID:   p.EstimateID,
-		Date:         p.Date.UTC().Format(time.RFC3339Nano),
-		Status:       string(p.Status),
-		MPPayload:    p.MPPayload,
-		MPPayloadRaw: string(p.MPPayloadRaw),
+	it := billingPaymentItem{
+		ID:                 p.ID,
+		EstimateID:         p.EstimateID,
+		Date:               p.Date.UTC().Format(sortableTimeLayout),
+		Status:             string(p.Status),
+		MPPayload:          p.MPPayload,
+		MPPayloadRaw:       string(p.MPPayloadRaw),
+		paymentDetailsItem: toPaymentDetailsItem(p.Details),
+	}
+	if p.DataKey != nil {
+		it.PIIKeyID = p.DataKey.KeyID
+		it.PIIWrappedKey = p.DataKey.WrappedKey
+	}
+	if p.AnonymizedAt != nil {
+		it.AnonymizedAt = p.AnonymizedAt.UTC().Format(time.RFC3339Nano)
 	}
+	return it
 }
 
 func fromBillingPaymentItem(it billingPaymentItem) entities.BillingPayment {
 	dt, _ := time.Parse(time.RFC3339Nano, it.Date)
-	return entities.BillingPayment{
+	p := entities.BillingPayment{
 		ID:           it.ID,
 		EstimateID:   it.EstimateID,
 		Date:         dt,
 		Status:       entities.PaymentStatus(it.Status),
+		Details:      fromPaymentDetailsItem(it.paymentDetailsItem),
 		MPPayload:    it.MPPayload,
 		MPPayloadRaw: []byte(it.MPPayloadRaw),
 	}
+	if it.PIIKeyID != "" {
+		p.DataKey = &entities.EncryptedDataKey{KeyID: it.PIIKeyID, WrappedKey: it.PIIWrappedKey}
+	}
+	if it.AnonymizedAt != "" {
+		if t, err := time.Parse(time.RFC3339Nano, it.AnonymizedAt); err == nil {
+			p.AnonymizedAt = &t
+		}
+	}
+	return p
+}
+
+func toPaymentDetailsItem(d entities.PaymentDetails) paymentDetailsItem {
+	it := paymentDetailsItem{
+		Amount:            d.Amount,
+		NetReceivedAmount: d.NetReceivedAmount,
+		PaymentMethodID:   d.PaymentMethodID,
+		PaymentTypeID:     d.PaymentTypeID,
+		Installments:      d.Installments,
+		CardLastFour:      d.CardLastFour,
+		CardBrand:         d.CardBrand,
+		PayerEmail:        d.PayerEmail,
+		PayerDocType:      d.PayerDocType,
+		PayerDocNumber:    d.PayerDocNumber,
+		PayerEmailHash:    d.PayerEmailHash,
+		PayerDocHash:      d.PayerDocHash,
+	}
+	for _, f := range d.Fees {
+		it.Fees = append(it.Fees, paymentFeeItem{Type: f.Type, Payer: f.Payer, Amount: f.Amount})
+	}
+	if d.ApprovedAt != nil {
+		it.ApprovedAt = d.ApprovedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return it
+}
+
+func fromPaymentDetailsItem(it paymentDetailsItem) entities.PaymentDetails {
+	d := entities.PaymentDetails{
+		Amount:            it.Amount,
+		NetReceivedAmount: it.NetReceivedAmount,
+		PaymentMethodID:   it.PaymentMethodID,
+		PaymentTypeID:     it.PaymentTypeID,
+		Installments:      it.Installments,
+		CardLastFour:      it.CardLastFour,
+		CardBrand:         it.CardBrand,
+		PayerEmail:        it.PayerEmail,
+		PayerDocType:      it.PayerDocType,
+		PayerDocNumber:    it.PayerDocNumber,
+		PayerEmailHash:    it.PayerEmailHash,
+		PayerDocHash:      it.PayerDocHash,
+	}
+	for _, f := range it.Fees {
+		d.Fees = append(d.Fees, entities.PaymentFee{Type: f.Type, Payer: f.Payer, Amount: f.Amount})
+	}
+	if it.ApprovedAt != "" {
+		if t, err := time.Parse(time.RFC3339Nano, it.ApprovedAt); err == nil {
+			d.ApprovedAt = &t
+		}
+	}
+	return d
 }
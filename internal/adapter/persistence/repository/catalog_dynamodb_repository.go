@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	defaultCatalogItemsTableName = "catalog_items"
+
+	// catalogSequence is the sequences item holding the catalog version.
+	catalogSequence = "catalog"
+	// catalogBatchSize is the BatchWriteItem limit.
+	catalogBatchSize = 25
+)
+
+type catalogItem struct {
+	ID        string  `dynamodbav:"id"`
+	Kind      string  `dynamodbav:"kind"`
+	Code      string  `dynamodbav:"code"`
+	Name      string  `dynamodbav:"name"`
+	Price     float64 `dynamodbav:"price"`
+	Version   int64   `dynamodbav:"version"`
+	UpdatedBy string  `dynamodbav:"updated_by,omitempty"`
+	UpdatedAt string  `dynamodbav:"updated_at"`
+}
+
+func catalogKey(kind entities.EstimateItemKind, code string) string {
+	return string(kind) + "#" + code
+}
+
+// CatalogDynamoRepository persists the price catalog in DynamoDB.
+//
+// Table requirements:
+//   - catalog_items: PK id (string: "<kind>#<code>").
+//   - sequences: item "catalog" (seq) holds the catalog version.
+//
+// The version is a change counter, not a gapless sequence: it is advanced before the
+// items are written, so a failed write only skips a number.
+
+type CatalogDynamoRepository struct {
+	ddb       *dynamodb.Client
+	tableName string
+	sequences sequenceTable
+}
+
+var _ interfaces.ICatalogRepository = (*CatalogDynamoRepository)(nil)
+
+func NewCatalogDynamoRepository(ddb *dynamodb.Client) *CatalogDynamoRepository {
+	return &CatalogDynamoRepository{
+		ddb:       ddb,
+		tableName: getenvDefault("CATALOG_ITEMS_TABLE", defaultCatalogItemsTableName),
+		sequences: newSequenceTable(ddb),
+	}
+}
+
+func (r *CatalogDynamoRepository) Put(ctx context.Context, item entities.CatalogItem) (entities.CatalogItem, error) {
+	version, err := r.nextVersion(ctx)
+	if err != nil {
+		return entities.CatalogItem{}, err
+	}
+	item.Version = version
+	av, err := attributevalue.MarshalMap(toCatalogItem(item))
+	if err != nil {
+		return entities.CatalogItem{}, err
+	}
+	if _, err := r.ddb.PutItem(ctx, &dynamodb.PutItemInput{TableName: aws.String(r.tableName), Item: av}); err != nil {
+		return entities.CatalogItem{}, err
+	}
+	return item, nil
+}
+
+// PutMany writes the items as one catalog version, in batches.
+func (r *CatalogDynamoRepository) PutMany(ctx context.Context, items []entities.CatalogItem) (int64, error) {
+	version, err := r.nextVersion(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for start := 0; start < len(items); start += catalogBatchSize {
+		end := min(start+catalogBatchSize, len(items))
+		requests := make([]types.WriteRequest, 0, end-start)
+		for _, item := range items[start:end] {
+			item.Version = version
+			av, err := attributevalue.MarshalMap(toCatalogItem(item))
+			if err != nil {
+				return 0, err
+			}
+			requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: av}})
+		}
+		if err := r.batchWrite(ctx, requests); err != nil {
+			return 0, err
+		}
+	}
+	return version, nil
+}
+
+func (r *CatalogDynamoRepository) batchWrite(ctx context.Context, requests []types.WriteRequest) error {
+	pending := map[string][]types.WriteRequest{r.tableName: requests}
+	for attempt := 0; len(pending[r.tableName]) > 0; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(attempt*50) * time.Millisecond):
+			}
+		}
+		out, err := r.ddb.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{RequestItems: pending})
+		if err != nil {
+			return err
+		}
+		pending = out.UnprocessedItems
+	}
+	return nil
+}
+
+func (r *CatalogDynamoRepository) Get(ctx context.Context, kind entities.EstimateItemKind, code string) (entities.CatalogItem, error) {
+	out, err := r.ddb.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: catalogKey(kind, code)},
+		},
+	})
+	if err != nil {
+		return entities.CatalogItem{}, err
+	}
+	if len(out.Item) == 0 {
+		return entities.CatalogItem{}, nil
+	}
+	var it catalogItem
+	if err := attributevalue.UnmarshalMap(out.Item, &it); err != nil {
+		return entities.CatalogItem{}, err
+	}
+	return fromCatalogItem(it), nil
+}
+
+// List returns the whole catalog, by kind and code.
+func (r *CatalogDynamoRepository) List(ctx context.Context) ([]entities.CatalogItem, error) {
+	items := []entities.CatalogItem{}
+	var startKey map[string]types.AttributeValue
+	for {
+		out, err := r.ddb.Scan(ctx, &dynamodb.ScanInput{
+			TableName:         aws.String(r.tableName),
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, raw := range out.Items {
+			var it catalogItem
+			if err := attributevalue.UnmarshalMap(raw, &it); err != nil {
+				return nil, err
+			}
+			items = append(items, fromCatalogItem(it))
+		}
+		if len(out.LastEvaluatedKey) == 0 {
+			break
+		}
+		startKey = out.LastEvaluatedKey
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return catalogKey(items[i].Kind, items[i].Code) < catalogKey(items[j].Kind, items[j].Code)
+	})
+	return items, nil
+}
+
+func (r *CatalogDynamoRepository) Delete(ctx context.Context, kind entities.EstimateItemKind, code string) (entities.CatalogItem, error) {
+	existing, err := r.Get(ctx, kind, code)
+	if err != nil || existing.Code == "" {
+		return entities.CatalogItem{}, err
+	}
+	if _, err := r.nextVersion(ctx); err != nil {
+		return entities.CatalogItem{}, err
+	}
+	_, err = r.ddb.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: catalogKey(kind, code)},
+		},
+	})
+	if err != nil {
+		return entities.CatalogItem{}, err
+	}
+	return existing, nil
+}
+
+func (r *CatalogDynamoRepository) CurrentVersion(ctx context.Context) (int64, error) {
+	return r.sequences.current(ctx, catalogSequence)
+}
+
+func (r *CatalogDynamoRepository) nextVersion(ctx context.Context) (int64, error) {
+	out, err := r.ddb.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.sequences.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: catalogSequence},
+		},
+		UpdateExpression: aws.String("ADD #seq :one"),
+		ExpressionAttributeNames: map[string]string{
+			"#seq": "seq",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":one": &types.AttributeValueMemberN{Value: "1"},
+		},
+		ReturnValues: types.ReturnValueUpdatedNew,
+	})
+	if err != nil {
+		return 0, err
+	}
+	seq, ok := out.Attributes["seq"].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, nil
+	}
+	return strconv.ParseInt(seq.Value, 10, 64)
+}
+
+func toCatalogItem(i entities.CatalogItem) catalogItem {
+	return catalogItem{
+		ID:        catalogKey(i.Kind, i.Code),
+		Kind:      string(i.Kind),
+		Code:      i.Code,
+		Name:      i.Name,
+		Price:     i.Price,
+		Version:   i.Version,
+		UpdatedBy: i.UpdatedBy,
+		UpdatedAt: i.UpdatedAt.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+func fromCatalogItem(it catalogItem) entities.CatalogItem {
+	updatedAt, _ := time.Parse(time.RFC3339Nano, it.UpdatedAt)
+	return entities.CatalogItem{
+		Kind:      entities.EstimateItemKind(it.Kind),
+		Code:      it.Code,
+		Name:      it.Name,
+		Price:     it.Price,
+		Version:   it.Version,
+		UpdatedBy: it.UpdatedBy,
+		UpdatedAt: updatedAt,
+	}
+}
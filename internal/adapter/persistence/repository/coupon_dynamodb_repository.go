@@ -0,0 +1,288 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const defaultCouponsTableName = "coupons"
+
+// couponTimeLayout is fixed-width UTC, so stored dates compare as strings in conditions.
+const couponTimeLayout = "2006-01-02T15:04:05Z"
+
+type couponItem struct {
+	ID             string  `dynamodbav:"id"`
+	Description    string  `dynamodbav:"description"`
+	Type           string  `dynamodbav:"type"`
+	Value          float64 `dynamodbav:"value"`
+	Scope          string  `dynamodbav:"scope"`
+	ItemKind       string  `dynamodbav:"item_kind,omitempty"`
+	ValidFrom      string  `dynamodbav:"valid_from,omitempty"`
+	ExpiresAt      string  `dynamodbav:"expires_at,omitempty"`
+	MaxRedemptions int     `dynamodbav:"max_redemptions"`
+	MaxPerCustomer int     `dynamodbav:"max_per_customer"`
+	Redemptions    int     `dynamodbav:"redemptions"`
+	Active         bool    `dynamodbav:"active"`
+	CreatedBy      string  `dynamodbav:"created_by,omitempty"`
+	CreatedAt      string  `dynamodbav:"created_at"`
+}
+
+func couponCustomerKey(code, customerID string) string {
+	return "redemption#" + code + "#" + customerID
+}
+
+// couponTable counts coupon redemptions inside the transactions of other repositories.
+type couponTable struct {
+	tableName string
+}
+
+func newCouponTable() couponTable {
+	return couponTable{tableName: getenvDefault("COUPONS_TABLE", defaultCouponsTableName)}
+}
+
+// redeemItems returns the transaction items counting the redemptions and, per item, the
+// error its failed condition means: the coupon is no longer usable, or the customer
+// reached the coupon limit.
+func (t couponTable) redeemItems(redemptions []entities.CouponRedemption) ([]types.TransactWriteItem, []error) {
+	var items []types.TransactWriteItem
+	var errs []error
+	for _, red := range redemptions {
+		now := red.RedeemedAt.UTC().Format(couponTimeLayout)
+		items = append(items, types.TransactWriteItem{
+			Update: &types.Update{
+				TableName: aws.String(t.tableName),
+				Key: map[string]types.AttributeValue{
+					"id": &types.AttributeValueMemberS{Value: red.Code},
+				},
+				ConditionExpression: aws.String("attribute_exists(#id) AND #active = :true" +
+					" AND (#max = :zero OR #redemptions < #max)" +
+					" AND (attribute_not_exists(#valid_from) OR #valid_from <= :now)" +
+					" AND (attribute_not_exists(#expires_at) OR #expires_at > :now)"),
+				UpdateExpression: aws.String("ADD #redemptions :one"),
+				ExpressionAttributeNames: map[string]string{
+					"#id":          "id",
+					"#active":      "active",
+					"#max":         "max_redemptions",
+					"#redemptions": "redemptions",
+					"#valid_from":  "valid_from",
+					"#expires_at":  "expires_at",
+				},
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":true": &types.AttributeValueMemberBOOL{Value: true},
+					":zero": &types.AttributeValueMemberN{Value: "0"},
+					":one":  &types.AttributeValueMemberN{Value: "1"},
+					":now":  &types.AttributeValueMemberS{Value: now},
+				},
+			},
+		})
+		errs = append(errs, entities.ErrCouponUnavailable)
+
+		if red.CustomerID == "" {
+			continue
+		}
+		update := &types.Update{
+			TableName: aws.String(t.tableName),
+			Key: map[string]types.AttributeValue{
+				"id": &types.AttributeValueMemberS{Value: couponCustomerKey(red.Code, red.CustomerID)},
+			},
+			UpdateExpression: aws.String("ADD #count :one SET #code = :code, #customer_id = :customer_id, #updated_at = :now"),
+			ExpressionAttributeNames: map[string]string{
+				"#count":       "count",
+				"#code":        "code",
+				"#customer_id": "customer_id",
+				"#updated_at":  "updated_at",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":one":         &types.AttributeValueMemberN{Value: "1"},
+				":code":        &types.AttributeValueMemberS{Value: red.Code},
+				":customer_id": &types.AttributeValueMemberS{Value: red.CustomerID},
+				":now":         &types.AttributeValueMemberS{Value: now},
+			},
+		}
+		if red.MaxPerCustomer > 0 {
+			update.ConditionExpression = aws.String("attribute_not_exists(#count) OR #count < :max")
+			update.ExpressionAttributeValues[":max"] = &types.AttributeValueMemberN{Value: strconv.Itoa(red.MaxPerCustomer)}
+		}
+		items = append(items, types.TransactWriteItem{Update: update})
+		errs = append(errs, entities.ErrCouponCustomerLimit)
+	}
+	return items, errs
+}
+
+// CouponDynamoRepository persists coupons in DynamoDB.
+//
+// Table requirements:
+//   - coupons: PK id (string: the coupon code). Besides the coupons it holds one counter
+//     per coupon and customer (id "redemption#<code>#<customer_id>", count).
+//
+// Redemptions are counted by the estimate repository, in the transaction that writes the
+// estimate (see couponTable).
+
+type CouponDynamoRepository struct {
+	ddb    *dynamodb.Client
+	coupon couponTable
+}
+
+var _ interfaces.ICouponRepository = (*CouponDynamoRepository)(nil)
+
+func NewCouponDynamoRepository(ddb *dynamodb.Client) *CouponDynamoRepository {
+	return &CouponDynamoRepository{ddb: ddb, coupon: newCouponTable()}
+}
+
+func (r *CouponDynamoRepository) Create(ctx context.Context, c entities.Coupon) error {
+	av, err := attributevalue.MarshalMap(toCouponItem(c))
+	if err != nil {
+		return err
+	}
+	_, err = r.ddb.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.coupon.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(#id)"),
+		ExpressionAttributeNames: map[string]string{
+			"#id": "id",
+		},
+	})
+	if err != nil {
+		var cfe *types.ConditionalCheckFailedException
+		if errors.As(err, &cfe) {
+			return entities.ErrCouponAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *CouponDynamoRepository) GetByCode(ctx context.Context, code string) (entities.Coupon, error) {
+	out, err := r.ddb.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.coupon.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: code},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return entities.Coupon{}, err
+	}
+	if len(out.Item) == 0 {
+		return entities.Coupon{}, nil
+	}
+	var it couponItem
+	if err := attributevalue.UnmarshalMap(out.Item, &it); err != nil {
+		return entities.Coupon{}, err
+	}
+	return fromCouponItem(it), nil
+}
+
+// CustomerRedemptions returns how many estimates of the customer used the coupon.
+func (r *CouponDynamoRepository) CustomerRedemptions(ctx context.Context, code, customerID string) (int, error) {
+	out, err := r.ddb.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.coupon.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: couponCustomerKey(code, customerID)},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return 0, err
+	}
+	var it struct {
+		Count int `dynamodbav:"count"`
+	}
+	if err := attributevalue.UnmarshalMap(out.Item, &it); err != nil {
+		return 0, err
+	}
+	return it.Count, nil
+}
+
+// SetActive enables or disables a coupon; it returns an empty coupon when none exists.
+func (r *CouponDynamoRepository) SetActive(ctx context.Context, code string, active bool) (entities.Coupon, error) {
+	out, err := r.ddb.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.coupon.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: code},
+		},
+		ConditionExpression: aws.String("attribute_exists(#id)"),
+		UpdateExpression:    aws.String("SET #active = :active"),
+		ExpressionAttributeNames: map[string]string{
+			"#id":     "id",
+			"#active": "active",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":active": &types.AttributeValueMemberBOOL{Value: active},
+		},
+		ReturnValues: types.ReturnValueAllNew,
+	})
+	if err != nil {
+		var cfe *types.ConditionalCheckFailedException
+		if errors.As(err, &cfe) {
+			return entities.Coupon{}, nil
+		}
+		return entities.Coupon{}, err
+	}
+	var it couponItem
+	if err := attributevalue.UnmarshalMap(out.Attributes, &it); err != nil {
+		return entities.Coupon{}, err
+	}
+	return fromCouponItem(it), nil
+}
+
+func toCouponItem(c entities.Coupon) couponItem {
+	it := couponItem{
+		ID:             c.Code,
+		Description:    c.Description,
+		Type:           string(c.Rule.Type),
+		Value:          c.Rule.Value,
+		Scope:          string(c.Rule.Scope),
+		ItemKind:       string(c.Rule.ItemKind),
+		MaxRedemptions: c.MaxRedemptions,
+		MaxPerCustomer: c.MaxPerCustomer,
+		Redemptions:    c.Redemptions,
+		Active:         c.Active,
+		CreatedBy:      c.CreatedBy,
+		CreatedAt:      c.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	if c.ValidFrom != nil {
+		it.ValidFrom = c.ValidFrom.UTC().Format(couponTimeLayout)
+	}
+	if c.ExpiresAt != nil {
+		it.ExpiresAt = c.ExpiresAt.UTC().Format(couponTimeLayout)
+	}
+	return it
+}
+
+func fromCouponItem(it couponItem) entities.Coupon {
+	createdAt, _ := time.Parse(time.RFC3339Nano, it.CreatedAt)
+	c := entities.Coupon{
+		Code:        it.ID,
+		Description: it.Description,
+		Rule: entities.DiscountRule{
+			Type:     entities.DiscountType(it.Type),
+			Value:    it.Value,
+			Scope:    entities.DiscountScope(it.Scope),
+			ItemKind: entities.EstimateItemKind(it.ItemKind),
+		},
+		MaxRedemptions: it.MaxRedemptions,
+		MaxPerCustomer: it.MaxPerCustomer,
+		Redemptions:    it.Redemptions,
+		Active:         it.Active,
+		CreatedBy:      it.CreatedBy,
+		CreatedAt:      createdAt,
+	}
+	if validFrom, err := time.Parse(couponTimeLayout, it.ValidFrom); err == nil {
+		c.ValidFrom = &validFrom
+	}
+	if expiresAt, err := time.Parse(couponTimeLayout, it.ExpiresAt); err == nil {
+		c.ExpiresAt = &expiresAt
+	}
+	return c
+}
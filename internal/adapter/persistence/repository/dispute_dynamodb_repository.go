@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	defaultDisputesTableName = "disputes"
+	disputesStatusIndex      = "status-index"
+)
+
+type disputeItem struct {
+	ID               string                `dynamodbav:"id"`
+	PaymentID        string                `dynamodbav:"payment_id"`
+	EstimateID       string                `dynamodbav:"estimate_id"`
+	Status           string                `dynamodbav:"status"`
+	Amount           float64               `dynamodbav:"amount"`
+	Reason           string                `dynamodbav:"reason,omitempty"`
+	EvidenceDeadline string                `dynamodbav:"evidence_deadline,omitempty"`
+	Evidence         []disputeEvidenceItem `dynamodbav:"evidence,omitempty"`
+	CreatedAt        string                `dynamodbav:"created_at"`
+	UpdatedAt        string                `dynamodbav:"updated_at"`
+	ResolvedAt       string                `dynamodbav:"resolved_at,omitempty"`
+}
+
+type disputeEvidenceItem struct {
+	Reference   string `dynamodbav:"reference"`
+	Description string `dynamodbav:"description,omitempty"`
+	AddedBy     string `dynamodbav:"added_by"`
+	AddedAt     string `dynamodbav:"added_at"`
+}
+
+// DisputeDynamoRepository persists Dispute entities in DynamoDB.
+//
+// Table requirements:
+//   - PK: id (string)
+//   - GSI: status-index (PK: status)
+
+type DisputeDynamoRepository struct {
+	ddb       *dynamodb.Client
+	tableName string
+}
+
+var _ interfaces.IDisputeRepository = (*DisputeDynamoRepository)(nil)
+
+func NewDisputeDynamoRepository(ddb *dynamodb.Client) *DisputeDynamoRepository {
+	return &DisputeDynamoRepository{
+		ddb:       ddb,
+		tableName: getenvDefault("DISPUTES_TABLE", defaultDisputesTableName),
+	}
+}
+
+func (r *DisputeDynamoRepository) Create(ctx context.Context, d entities.Dispute) (entities.Dispute, error) {
+	av, err := attributevalue.MarshalMap(toDisputeItem(d))
+	if err != nil {
+		return entities.Dispute{}, err
+	}
+
+	_, err = r.ddb.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(#id)"),
+		ExpressionAttributeNames: map[string]string{
+			"#id": "id",
+		},
+	})
+	if err != nil {
+		return entities.Dispute{}, err
+	}
+	return d, nil
+}
+
+func (r *DisputeDynamoRepository) GetByID(ctx context.Context, id string) (entities.Dispute, error) {
+	out, err := r.ddb.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return entities.Dispute{}, err
+	}
+	if len(out.Item) == 0 {
+		return entities.Dispute{}, nil
+	}
+
+	var it disputeItem
+	if err := attributevalue.UnmarshalMap(out.Item, &it); err != nil {
+		return entities.Dispute{}, err
+	}
+	return fromDisputeItem(it), nil
+}
+
+// Update overwrites an existing dispute.
+func (r *DisputeDynamoRepository) Update(ctx context.Context, d entities.Dispute) error {
+	av, err := attributevalue.MarshalMap(toDisputeItem(d))
+	if err != nil {
+		return err
+	}
+
+	_, err = r.ddb.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_exists(#id)"),
+		ExpressionAttributeNames: map[string]string{
+			"#id": "id",
+		},
+	})
+	var cfe *types.ConditionalCheckFailedException
+	if errors.As(err, &cfe) {
+		return errors.New("dispute not found")
+	}
+	return err
+}
+
+func (r *DisputeDynamoRepository) ListByStatus(ctx context.Context, status entities.DisputeStatus) ([]entities.Dispute, error) {
+	names := map[string]string{"#status": "status"}
+	values := map[string]types.AttributeValue{
+		":status": &types.AttributeValueMemberS{Value: string(status)},
+	}
+
+	var raws []map[string]types.AttributeValue
+	var startKey map[string]types.AttributeValue
+	useScan := false
+	for {
+		var items []map[string]types.AttributeValue
+		var lastKey map[string]types.AttributeValue
+		if useScan {
+			out, err := r.ddb.Scan(ctx, &dynamodb.ScanInput{
+				TableName:                 aws.String(r.tableName),
+				FilterExpression:          aws.String("#status = :status"),
+				ExpressionAttributeNames:  names,
+				ExpressionAttributeValues: values,
+				ExclusiveStartKey:         startKey,
+			})
+			if err != nil {
+				return nil, err
+			}
+			items, lastKey = out.Items, out.LastEvaluatedKey
+		} else {
+			out, err := r.ddb.Query(ctx, &dynamodb.QueryInput{
+				TableName:                 aws.String(r.tableName),
+				IndexName:                 aws.String(disputesStatusIndex),
+				KeyConditionExpression:    aws.String("#status = :status"),
+				ExpressionAttributeNames:  names,
+				ExpressionAttributeValues: values,
+				ExclusiveStartKey:         startKey,
+			})
+			if err != nil {
+				if !isIndexNotAvailableError(err) {
+					return nil, err
+				}
+				useScan, startKey, raws = true, nil, nil
+				continue
+			}
+			items, lastKey = out.Items, out.LastEvaluatedKey
+		}
+
+		raws = append(raws, items...)
+		if len(lastKey) == 0 {
+			break
+		}
+		startKey = lastKey
+	}
+
+	disputes := make([]entities.Dispute, 0, len(raws))
+	for _, raw := range raws {
+		var it disputeItem
+		if err := attributevalue.UnmarshalMap(raw, &it); err != nil {
+			return nil, err
+		}
+		disputes = append(disputes, fromDisputeItem(it))
+	}
+	return disputes, nil
+}
+
+func toDisputeItem(d entities.Dispute) disputeItem {
+	it := disputeItem{
+		ID:         d.ID,
+		PaymentID:  d.PaymentID,
+		EstimateID: d.EstimateID,
+		Status:     string(d.Status),
+		Amount:     d.Amount,
+		Reason:     d.Reason,
+		CreatedAt:  d.CreatedAt.UTC().Format(time.RFC3339Nano),
+		UpdatedAt:  d.UpdatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	if d.EvidenceDeadline != nil {
+		it.EvidenceDeadline = d.EvidenceDeadline.UTC().Format(time.RFC3339Nano)
+	}
+	if d.ResolvedAt != nil {
+		it.ResolvedAt = d.ResolvedAt.UTC().Format(time.RFC3339Nano)
+	}
+	for _, e := range d.Evidence {
+		it.Evidence = append(it.Evidence, disputeEvidenceItem{
+			Reference:   e.Reference,
+			Description: e.Description,
+			AddedBy:     e.AddedBy,
+			AddedAt:     e.AddedAt.UTC().Format(time.RFC3339Nano),
+		})
+	}
+	return it
+}
+
+func fromDisputeItem(it disputeItem) entities.Dispute {
+	createdAt, _ := time.Parse(time.RFC3339Nano, it.CreatedAt)
+	updatedAt, _ := time.Parse(time.RFC3339Nano, it.UpdatedAt)
+	d := entities.Dispute{
+		ID:         it.ID,
+		PaymentID:  it.PaymentID,
+		EstimateID: it.EstimateID,
+		Status:     entities.DisputeStatus(it.Status),
+		Amount:     it.Amount,
+		Reason:     it.Reason,
+		CreatedAt:  createdAt,
+		UpdatedAt:  updatedAt,
+	}
+	if t, err := time.Parse(time.RFC3339Nano, it.EvidenceDeadline); err == nil {
+		d.EvidenceDeadline = &t
+	}
+	if t, err := time.Parse(time.RFC3339Nano, it.ResolvedAt); err == nil {
+		d.ResolvedAt = &t
+	}
+	for _, e := range it.Evidence {
+		addedAt, _ := time.Parse(time.RFC3339Nano, e.AddedAt)
+		d.Evidence = append(d.Evidence, entities.DisputeEvidence{
+			Reference:   e.Reference,
+			Description: e.Description,
+			AddedBy:     e.AddedBy,
+			AddedAt:     addedAt,
+		})
+	}
+	return d
+}
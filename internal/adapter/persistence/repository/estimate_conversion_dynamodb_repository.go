@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	defaultEstimateConversionsTableName = "estimate_conversions"
+	estimateConversionsCreatedIndex     = "created_month-created_at-index"
+	createdMonthLayout                  = "2006-01"
+)
+
+type estimateConversionItem struct {
+	ID           string  `dynamodbav:"id"`
+	OSID         string  `dynamodbav:"os_id"`
+	Price        float64 `dynamodbav:"price"`
+	Status       string  `dynamodbav:"status"`
+	CreatedAt    string  `dynamodbav:"created_at"`
+	CreatedMonth string  `dynamodbav:"created_month"`
+	DecidedAt    string  `dynamodbav:"decided_at,omitempty"`
+	ApprovedAt   string  `dynamodbav:"approved_at,omitempty"`
+	FirstPaidAt  string  `dynamodbav:"first_paid_at,omitempty"`
+	ReasonCode   string  `dynamodbav:"reason_code,omitempty"`
+	ReasonLabel  string  `dynamodbav:"reason_label,omitempty"`
+	UpdatedAt    string  `dynamodbav:"updated_at"`
+}
+
+// EstimateConversionDynamoRepository persists the estimate analytics read model.
+//
+// Table requirements:
+//   - PK: id (string, estimate id)
+//   - GSI created_month-created_at-index: PK created_month (YYYY-MM, UTC), SK created_at
+//
+// Range reads query one created_month partition per month, so reports never scan.
+
+type EstimateConversionDynamoRepository struct {
+	ddb       *dynamodb.Client
+	tableName string
+}
+
+var _ interfaces.IEstimateConversionRepository = (*EstimateConversionDynamoRepository)(nil)
+
+func NewEstimateConversionDynamoRepository(ddb *dynamodb.Client) *EstimateConversionDynamoRepository {
+	return &EstimateConversionDynamoRepository{
+		ddb:       ddb,
+		tableName: getenvDefault("ESTIMATE_CONVERSIONS_TABLE", defaultEstimateConversionsTableName),
+	}
+}
+
+func (r *EstimateConversionDynamoRepository) Upsert(ctx context.Context, c entities.EstimateConversion) error {
+	it := toEstimateConversionItem(c)
+	expr := "SET #os_id = :os_id, #price = :price, #status = :status, #created_at = :created_at, #created_month = :created_month, #updated_at = :updated_at"
+	names := map[string]string{
+		"#os_id":         "os_id",
+		"#price":         "price",
+		"#status":        "status",
+		"#created_at":    "created_at",
+		"#created_month": "created_month",
+		"#updated_at":    "updated_at",
+	}
+	values := map[string]types.AttributeValue{
+		":os_id":         &types.AttributeValueMemberS{Value: it.OSID},
+		":price":         &types.AttributeValueMemberN{Value: floatToString(it.Price)},
+		":status":        &types.AttributeValueMemberS{Value: it.Status},
+		":created_at":    &types.AttributeValueMemberS{Value: it.CreatedAt},
+		":created_month": &types.AttributeValueMemberS{Value: it.CreatedMonth},
+		":updated_at":    &types.AttributeValueMemberS{Value: it.UpdatedAt},
+	}
+	if it.DecidedAt != "" {
+		expr += ", #decided_at = if_not_exists(#decided_at, :decided_at)"
+		names["#decided_at"] = "decided_at"
+		values[":decided_at"] = &types.AttributeValueMemberS{Value: it.DecidedAt}
+	}
+	if it.ApprovedAt != "" {
+		expr += ", #approved_at = :approved_at"
+		names["#approved_at"] = "approved_at"
+		values[":approved_at"] = &types.AttributeValueMemberS{Value: it.ApprovedAt}
+	}
+	names["#reason_code"], names["#reason_label"] = "reason_code", "reason_label"
+	if it.ReasonCode != "" {
+		expr += ", #reason_code = :reason_code, #reason_label = :reason_label"
+		values[":reason_code"] = &types.AttributeValueMemberS{Value: it.ReasonCode}
+		values[":reason_label"] = &types.AttributeValueMemberS{Value: it.ReasonLabel}
+	} else {
+		// An estimate moved out of rejeitado/cancelado no longer has a reason.
+		expr += " REMOVE #reason_code, #reason_label"
+	}
+
+	_, err := r.ddb.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: it.ID},
+		},
+		UpdateExpression:          aws.String(expr),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	})
+	return err
+}
+
+func (r *EstimateConversionDynamoRepository) MarkPaid(ctx context.Context, estimateID string, paidAt time.Time) error {
+	_, err := r.ddb.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: estimateID},
+		},
+		ConditionExpression: aws.String("attribute_exists(#id)"),
+		UpdateExpression:    aws.String("SET #first_paid_at = if_not_exists(#first_paid_at, :paid_at)"),
+		ExpressionAttributeNames: map[string]string{
+			"#id":            "id",
+			"#first_paid_at": "first_paid_at",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":paid_at": &types.AttributeValueMemberS{Value: paidAt.UTC().Format(sortableTimeLayout)},
+		},
+	})
+	if err != nil {
+		var cfe *types.ConditionalCheckFailedException
+		if errors.As(err, &cfe) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *EstimateConversionDynamoRepository) ListCreatedBetween(ctx context.Context, from, to time.Time) ([]entities.EstimateConversion, error) {
+	from, to = from.UTC(), to.UTC()
+	if to.Before(from) {
+		return []entities.EstimateConversion{}, nil
+	}
+
+	out := []entities.EstimateConversion{}
+	month := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for !month.After(to) {
+		var startKey map[string]types.AttributeValue
+		for {
+			res, err := r.ddb.Query(ctx, &dynamodb.QueryInput{
+				TableName:              aws.String(r.tableName),
+				IndexName:              aws.String(estimateConversionsCreatedIndex),
+				KeyConditionExpression: aws.String("#created_month = :month AND #created_at BETWEEN :from AND :to"),
+				ExpressionAttributeNames: map[string]string{
+					"#created_month": "created_month",
+					"#created_at":    "created_at",
+				},
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":month": &types.AttributeValueMemberS{Value: month.Format(createdMonthLayout)},
+					":from":  &types.AttributeValueMemberS{Value: from.Format(sortableTimeLayout)},
+					":to":    &types.AttributeValueMemberS{Value: to.Format(sortableTimeLayout)},
+				},
+				ExclusiveStartKey: startKey,
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, raw := range res.Items {
+				var it estimateConversionItem
+				if err := attributevalue.UnmarshalMap(raw, &it); err != nil {
+					return nil, err
+				}
+				out = append(out, fromEstimateConversionItem(it))
+			}
+			if len(res.LastEvaluatedKey) == 0 {
+				break
+			}
+			startKey = res.LastEvaluatedKey
+		}
+		month = month.AddDate(0, 1, 0)
+	}
+	return out, nil
+}
+
+func toEstimateConversionItem(c entities.EstimateConversion) estimateConversionItem {
+	it := estimateConversionItem{
+		ID:           c.EstimateID,
+		OSID:         c.OSID,
+		Price:        c.Price,
+		Status:       string(c.Status),
+		CreatedAt:    c.CreatedAt.UTC().Format(sortableTimeLayout),
+		CreatedMonth: c.CreatedAt.UTC().Format(createdMonthLayout),
+		UpdatedAt:    c.UpdatedAt.UTC().Format(sortableTimeLayout),
+	}
+	if c.DecidedAt != nil {
+		it.DecidedAt = c.DecidedAt.UTC().Format(sortableTimeLayout)
+	}
+	if c.ApprovedAt != nil {
+		it.ApprovedAt = c.ApprovedAt.UTC().Format(sortableTimeLayout)
+	}
+	if c.FirstPaidAt != nil {
+		it.FirstPaidAt = c.FirstPaidAt.UTC().Format(sortableTimeLayout)
+	}
+	it.ReasonCode, it.ReasonLabel = c.ReasonCode, c.ReasonLabel
+	return it
+}
+
+func fromEstimateConversionItem(it estimateConversionItem) entities.EstimateConversion {
+	createdAt, _ := time.Parse(time.RFC3339Nano, it.CreatedAt)
+	updatedAt, _ := time.Parse(time.RFC3339Nano, it.UpdatedAt)
+	c := entities.EstimateConversion{
+		EstimateID: it.ID,
+		OSID:       it.OSID,
+		Price:      it.Price,
+		Status:     entities.EstimateStatus(it.Status),
+		CreatedAt:  createdAt,
+		UpdatedAt:  updatedAt,
+	}
+	c.DecidedAt = parseOptionalTime(it.DecidedAt)
+	c.ApprovedAt = parseOptionalTime(it.ApprovedAt)
+	c.FirstPaidAt = parseOptionalTime(it.FirstPaidAt)
+	c.ReasonCode, c.ReasonLabel = it.ReasonCode, it.ReasonLabel
+	return c
+}
+
+func parseOptionalTime(v string) *time.Time {
+	t, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 	"time"
@@ -18,20 +19,139 @@ import (
 
 const defaultEstimatesTableName = "estimates"
 const estimatesOSIDIndexName = "os_id-index"
+const estimatesStatusIndexName = "status-index"
+
+type estimateLineTaxItem struct {
+	Name   string  `dynamodbav:"name"`
+	Rate   float64 `dynamodbav:"rate"`
+	Amount float64 `dynamodbav:"amount"`
+}
+
+type estimateLineItem struct {
+	ID          string                `dynamodbav:"id,omitempty"`
+	Kind        string                `dynamodbav:"kind"`
+	Code        string                `dynamodbav:"code,omitempty"`
+	Name        string                `dynamodbav:"name"`
+	Description string                `dynamodbav:"description"`
+	Category    string                `dynamodbav:"category,omitempty"`
+	Hours       float64               `dynamodbav:"hours,omitempty"`
+	ActualHours float64               `dynamodbav:"actual_hours,omitempty"`
+	SkillTier   string                `dynamodbav:"skill_tier,omitempty"`
+	HourlyRate  float64               `dynamodbav:"hourly_rate,omitempty"`
+	Quantity    int                   `dynamodbav:"quantity"`
+	UnitPrice   float64               `dynamodbav:"unit_price"`
+	Total       float64               `dynamodbav:"total"`
+	Adjustment  float64               `dynamodbav:"adjustment,omitempty"`
+	Discount    float64               `dynamodbav:"discount,omitempty"`
+	Taxes       []estimateLineTaxItem `dynamodbav:"taxes"`
+}
+
+type estimateTaxItem struct {
+	Name   string  `dynamodbav:"name"`
+	Amount float64 `dynamodbav:"amount"`
+}
+
+type estimateDiscountItem struct {
+	Source     string  `dynamodbav:"source"`
+	CouponCode string  `dynamodbav:"coupon_code,omitempty"`
+	Type       string  `dynamodbav:"type"`
+	Value      float64 `dynamodbav:"value"`
+	Scope      string  `dynamodbav:"scope"`
+	ItemKind   string  `dynamodbav:"item_kind,omitempty"`
+	Reason     string  `dynamodbav:"reason,omitempty"`
+	ApprovedBy string  `dynamodbav:"approved_by,omitempty"`
+	Amount     float64 `dynamodbav:"amount"`
+}
+
+type estimateAdjustmentItem struct {
+	RuleID string  `dynamodbav:"rule_id"`
+	Name   string  `dynamodbav:"name"`
+	Type   string  `dynamodbav:"type"`
+	Reason string  `dynamodbav:"reason"`
+	Amount float64 `dynamodbav:"amount"`
+}
+
+type estimateCatalogDeviationItem struct {
+	Code      string  `dynamodbav:"code,omitempty"`
+	Name      string  `dynamodbav:"name"`
+	Reason    string  `dynamodbav:"reason"`
+	UnitPrice float64 `dynamodbav:"unit_price"`
+	ListPrice float64 `dynamodbav:"list_price,omitempty"`
+	Deviation float64 `dynamodbav:"deviation,omitempty"`
+}
+
+type estimateReasonItem struct {
+	Code  string `dynamodbav:"code"`
+	Label string `dynamodbav:"label"`
+	Text  string `dynamodbav:"text,omitempty"`
+}
+
+type estimateDecisionEvidenceItem struct {
+	LinkID    string `dynamodbav:"link_id"`
+	IP        string `dynamodbav:"ip"`
+	UserAgent string `dynamodbav:"user_agent"`
+	Recipient string `dynamodbav:"recipient"`
+}
+
+type estimateStatusChangeItem struct {
+	Status   string                        `dynamodbav:"status"`
+	At       string                        `dynamodbav:"at"`
+	Actor    string                        `dynamodbav:"actor,omitempty"`
+	Reason   *estimateReasonItem           `dynamodbav:"reason,omitempty"`
+	Evidence *estimateDecisionEvidenceItem `dynamodbav:"evidence,omitempty"`
+	Comment  string                        `dynamodbav:"comment,omitempty"`
+}
+
+type estimateInternalApprovalItem struct {
+	Threshold   float64 `dynamodbav:"threshold"`
+	Price       float64 `dynamodbav:"price"`
+	RequestedAt string  `dynamodbav:"requested_at"`
+	Decision    string  `dynamodbav:"decision,omitempty"`
+	Approver    string  `dynamodbav:"approver,omitempty"`
+	Comment     string  `dynamodbav:"comment,omitempty"`
+	DecidedAt   string  `dynamodbav:"decided_at,omitempty"`
+}
 
 type estimateItem struct {
-	ID        string `dynamodbav:"id"`
-	OSID      string `dynamodbav:"os_id"`
-	Price     string `dynamodbav:"price"`
-	Status    string `dynamodbav:"status"`
-	CreatedAt string `dynamodbav:"created_at"`
-	UpdatedAt string `dynamodbav:"updated_at"`
+	ID                string                         `dynamodbav:"id"`
+	OSID              string                         `dynamodbav:"os_id"`
+	Price             string                         `dynamodbav:"price"`
+	BalanceDue        float64                        `dynamodbav:"balance_due,omitempty"`
+	BalanceDueRefs    []string                       `dynamodbav:"balance_due_refs,stringset,omitempty"`
+	Status            string                         `dynamodbav:"status"`
+	CreatedAt         string                         `dynamodbav:"created_at"`
+	UpdatedAt         string                         `dynamodbav:"updated_at"`
+	ApprovedAt        string                         `dynamodbav:"approved_at,omitempty"`
+	Municipality      string                         `dynamodbav:"municipality,omitempty"`
+	Items             []estimateLineItem             `dynamodbav:"items,omitempty"`
+	Taxes             []estimateTaxItem              `dynamodbav:"taxes,omitempty"`
+	TaxTotal          float64                        `dynamodbav:"tax_total,omitempty"`
+	CustomerID        string                         `dynamodbav:"customer_id,omitempty"`
+	CustomerSegment   string                         `dynamodbav:"customer_segment,omitempty"`
+	Subtotal          float64                        `dynamodbav:"subtotal,omitempty"`
+	Discounts         []estimateDiscountItem         `dynamodbav:"discounts,omitempty"`
+	DiscountTotal     float64                        `dynamodbav:"discount_total,omitempty"`
+	PricingVersion    string                         `dynamodbav:"pricing_version,omitempty"`
+	Adjustments       []estimateAdjustmentItem       `dynamodbav:"adjustments,omitempty"`
+	AdjustmentTotal   float64                        `dynamodbav:"adjustment_total,omitempty"`
+	CatalogVersion    int64                          `dynamodbav:"catalog_version,omitempty"`
+	CatalogDeviations []estimateCatalogDeviationItem `dynamodbav:"catalog_deviations,omitempty"`
+	ExpiresAt         string                         `dynamodbav:"expires_at,omitempty"`
+	RenewedAt         string                         `dynamodbav:"renewed_at,omitempty"`
+	Reason            *estimateReasonItem            `dynamodbav:"reason,omitempty"`
+	DecidedBy         string                         `dynamodbav:"decided_by,omitempty"`
+	History           []estimateStatusChangeItem     `dynamodbav:"history,omitempty"`
+	DeclinedItems     []estimateLineItem             `dynamodbav:"declined_items,omitempty"`
+	InternalApproval  *estimateInternalApprovalItem  `dynamodbav:"internal_approval,omitempty"`
 }
 
 // EstimateDynamoRepository persists Estimate entities in DynamoDB.
 //
 // Table requirements:
 //   - PK: id (string)
+//   - GSI os_id-index: PK os_id
+//   - GSI status-index: PK status (receivables aging, expiration sweeper, internal
+//     approval queue)
 //
 // We purposely use OS id as PK (estimate ID) to guarantee 1 estimate per OS.
 // This keeps "PATCH /os/{id}/estimate" operations simple and efficient.
@@ -39,6 +159,7 @@ type estimateItem struct {
 type EstimateDynamoRepository struct {
 	ddb       *dynamodb.Client
 	tableName string
+	coupons   couponTable
 }
 
 var _ interfaces.IEstimateRepository = (*EstimateDynamoRepository)(nil)
@@ -47,6 +168,7 @@ func NewEstimateDynamoRepository(ddb *dynamodb.Client) *EstimateDynamoRepository
 	return &EstimateDynamoRepository{
 		ddb:       ddb,
 		tableName: getenvDefault("ESTIMATES_TABLE", defaultEstimatesTableName),
+		coupons:   newCouponTable(),
 	}
 }
 
@@ -143,6 +265,69 @@ func (r *EstimateDynamoRepository) GetByOSID(ctx context.Context, osID string) (
 	return fromEstimateItem(it), nil
 }
 
+// ListByStatus returns every estimate in the given status. It queries status-index and
+// falls back to a scan for local databases created without it.
+func (r *EstimateDynamoRepository) ListByStatus(ctx context.Context, status entities.EstimateStatus) ([]entities.Estimate, error) {
+	names := map[string]string{"#status": "status"}
+	values := map[string]types.AttributeValue{
+		":status": &types.AttributeValueMemberS{Value: string(status)},
+	}
+
+	var raws []map[string]types.AttributeValue
+	var startKey map[string]types.AttributeValue
+	useScan := false
+	for {
+		var items []map[string]types.AttributeValue
+		var lastKey map[string]types.AttributeValue
+		if useScan {
+			out, err := r.ddb.Scan(ctx, &dynamodb.ScanInput{
+				TableName:                 aws.String(r.tableName),
+				FilterExpression:          aws.String("#status = :status"),
+				ExpressionAttributeNames:  names,
+				ExpressionAttributeValues: values,
+				ExclusiveStartKey:         startKey,
+			})
+			if err != nil {
+				return nil, err
+			}
+			items, lastKey = out.Items, out.LastEvaluatedKey
+		} else {
+			out, err := r.ddb.Query(ctx, &dynamodb.QueryInput{
+				TableName:                 aws.String(r.tableName),
+				IndexName:                 aws.String(estimatesStatusIndexName),
+				KeyConditionExpression:    aws.String("#status = :status"),
+				ExpressionAttributeNames:  names,
+				ExpressionAttributeValues: values,
+				ExclusiveStartKey:         startKey,
+			})
+			if err != nil {
+				if !isIndexNotAvailableError(err) {
+					return nil, err
+				}
+				useScan, startKey, raws = true, nil, nil
+				continue
+			}
+			items, lastKey = out.Items, out.LastEvaluatedKey
+		}
+
+		raws = append(raws, items...)
+		if len(lastKey) == 0 {
+			break
+		}
+		startKey = lastKey
+	}
+
+	estimates := make([]entities.Estimate, 0, len(raws))
+	for _, raw := range raws {
+		var it estimateItem
+		if err := attributevalue.UnmarshalMap(raw, &it); err != nil {
+			return nil, err
+		}
+		estimates = append(estimates, fromEstimateItem(it))
+	}
+	return estimates, nil
+}
+
 func isIndexNotAvailableError(err error) bool {
 	if err == nil {
 		return false
@@ -151,39 +336,234 @@ func isIndexNotAvailableError(err error) bool {
 	return strings.Contains(msg, "validationexception") || strings.Contains(msg, "index")
 }
 
-func (r *EstimateDynamoRepository) UpdateStatusByOSID(ctx context.Context, osID string, status entities.EstimateStatus) (entities.Estimate, error) {
-	estimate, err := r.GetByOSID(ctx, osID)
+// UpdateStatus moves current to change.Status, recording its reason and actor and
+// appending change, dated now, to the history. The write is conditioned on the status and
+// updated_at current was read with, so a concurrent reprice, decision or expiry is not
+// overwritten: it returns entities.ErrEstimateChanged instead.
+func (r *EstimateDynamoRepository) UpdateStatus(ctx context.Context, current entities.Estimate, change entities.EstimateStatusChange) (entities.Estimate, error) {
+	updated, err := r.updateIf(ctx, current.ID, "#status = :previous_status AND #updated_at = :previous_updated_at", func(now string) (string, map[string]types.AttributeValue, map[string]string) {
+		expr := "SET #status = :status, #updated_at = :updated_at, #history = list_append(if_not_exists(#history, :empty), :change)"
+		vals := map[string]types.AttributeValue{
+			":status":              &types.AttributeValueMemberS{Value: string(change.Status)},
+			":updated_at":          &types.AttributeValueMemberS{Value: now},
+			":empty":               &types.AttributeValueMemberL{Value: []types.AttributeValue{}},
+			":change":              historyEntry(change, now),
+			":previous_status":     &types.AttributeValueMemberS{Value: string(current.Status)},
+			":previous_updated_at": &types.AttributeValueMemberS{Value: current.UpdatedAt.UTC().Format(time.RFC3339Nano)},
+		}
+		names := map[string]string{
+			"#status":     "status",
+			"#updated_at": "updated_at",
+			"#history":    "history",
+			"#reason":     "reason",
+			"#decided_by": "decided_by",
+		}
+		if change.Status == entities.EstimateStatusAprovado {
+			expr += ", #approved_at = :updated_at"
+			names["#approved_at"] = "approved_at"
+		}
+		var remove []string
+		if change.Reason != nil {
+			expr += ", #reason = :reason"
+			vals[":reason"], _ = attributevalue.Marshal(toEstimateReasonItem(change.Reason))
+		} else {
+			remove = append(remove, "#reason")
+		}
+		if change.Actor != "" {
+			expr += ", #decided_by = :decided_by"
+			vals[":decided_by"] = &types.AttributeValueMemberS{Value: change.Actor}
+		} else {
+			remove = append(remove, "#decided_by")
+		}
+		if len(remove) > 0 {
+			expr += " REMOVE " + strings.Join(remove, ", ")
+		}
+		return expr, vals, names
+	})
 	if err != nil {
 		return entities.Estimate{}, err
 	}
-	if estimate.ID == "" {
-		return entities.Estimate{}, nil
+	if updated.ID == "" {
+		return entities.Estimate{}, entities.ErrEstimateChanged
 	}
+	return updated, nil
+}
 
-	return r.update(ctx, estimate.ID, func(now string) (string, map[string]types.AttributeValue, map[string]string) {
-		expr := "SET #status = :status, #updated_at = :updated_at"
+// EraseDecisionEvidence removes the IP, user agent and recipient from the history entries
+// decided through one of linkIDs, keeping the link reference. The entries are located in
+// current, so the write is conditioned on the updated_at current was read with
+// (entities.ErrEstimateChanged otherwise). An estimate without such entries is returned
+// unchanged.
+func (r *EstimateDynamoRepository) EraseDecisionEvidence(ctx context.Context, current entities.Estimate, linkIDs []string) (entities.Estimate, error) {
+	links := map[string]bool{}
+	for _, id := range linkIDs {
+		links[id] = true
+	}
+	var remove []string
+	for i, change := range current.History {
+		if change.Evidence == nil || !links[change.Evidence.LinkID] {
+			continue
+		}
+		entry := fmt.Sprintf("#history[%d].#evidence", i)
+		remove = append(remove, entry+".#ip", entry+".#user_agent", entry+".#recipient")
+	}
+	if len(remove) == 0 {
+		return current, nil
+	}
+
+	updated, err := r.updateIf(ctx, current.ID, "#updated_at = :previous_updated_at", func(now string) (string, map[string]types.AttributeValue, map[string]string) {
+		expr := "SET #updated_at = :updated_at REMOVE " + strings.Join(remove, ", ")
 		vals := map[string]types.AttributeValue{
-			":status":     &types.AttributeValueMemberS{Value: string(status)},
+			":updated_at":          &types.AttributeValueMemberS{Value: now},
+			":previous_updated_at": &types.AttributeValueMemberS{Value: current.UpdatedAt.UTC().Format(time.RFC3339Nano)},
+		}
+		names := map[string]string{
+			"#updated_at": "updated_at",
+			"#history":    "history",
+			"#evidence":   "evidence",
+			"#ip":         "ip",
+			"#user_agent": "user_agent",
+			"#recipient":  "recipient",
+		}
+		return expr, vals, names
+	})
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	if updated.ID == "" {
+		return entities.Estimate{}, entities.ErrEstimateChanged
+	}
+	return updated, nil
+}
+
+// historyEntry is change, dated at, as a one-element list to append to the history.
+func historyEntry(change entities.EstimateStatusChange, at string) types.AttributeValue {
+	item := toEstimateStatusChangeItem(change)
+	item.At = at
+	av, _ := attributevalue.Marshal([]estimateStatusChangeItem{item})
+	return av
+}
+
+// Expire sets the estimate expirado if it is pending and its expires_at is still
+// expiresAt; a failed condition returns an empty Estimate.
+func (r *EstimateDynamoRepository) Expire(ctx context.Context, id string, expiresAt time.Time) (entities.Estimate, error) {
+	return r.updateIf(ctx, id, "#status = :pending AND #expires_at = :expires_at", func(now string) (string, map[string]types.AttributeValue, map[string]string) {
+		expr := "SET #status = :status, #updated_at = :updated_at, #history = list_append(if_not_exists(#history, :empty), :change)"
+		vals := map[string]types.AttributeValue{
+			":status":     &types.AttributeValueMemberS{Value: string(entities.EstimateStatusExpirado)},
+			":pending":    &types.AttributeValueMemberS{Value: string(entities.EstimateStatusPendente)},
+			":expires_at": &types.AttributeValueMemberS{Value: expiresAt.UTC().Format(time.RFC3339Nano)},
 			":updated_at": &types.AttributeValueMemberS{Value: now},
+			":empty":      &types.AttributeValueMemberL{Value: []types.AttributeValue{}},
+			":change":     historyEntry(entities.EstimateStatusChange{Status: entities.EstimateStatusExpirado}, now),
 		}
 		names := map[string]string{
 			"#status":     "status",
+			"#expires_at": "expires_at",
 			"#updated_at": "updated_at",
+			"#history":    "history",
 		}
 		return expr, vals, names
 	})
 }
 
-func (r *EstimateDynamoRepository) UpdatePriceByID(ctx context.Context, id string, newPrice float64) (entities.Estimate, error) {
-	return r.update(ctx, id, func(now string) (string, map[string]types.AttributeValue, map[string]string) {
-		expr := "SET #price = :price, #updated_at = :updated_at"
+// CreateWithRedemptions creates the estimate and counts its coupon redemptions in a
+// single transaction.
+func (r *EstimateDynamoRepository) CreateWithRedemptions(ctx context.Context, e entities.Estimate, redemptions []entities.CouponRedemption) (entities.Estimate, error) {
+	av, err := attributevalue.MarshalMap(toEstimateItem(e))
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	put := types.TransactWriteItem{
+		Put: &types.Put{
+			TableName:           aws.String(r.tableName),
+			Item:                av,
+			ConditionExpression: aws.String("attribute_not_exists(#id)"),
+			ExpressionAttributeNames: map[string]string{
+				"#id": "id",
+			},
+		},
+	}
+	err = r.transactRedemptions(ctx, put, redemptions)
+	if errors.Is(err, errEstimateWriteFailed) {
+		return entities.Estimate{}, entities.ErrEstimateChanged
+	}
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	return e, nil
+}
+
+// UpdatePricing replaces the estimate pricing if it was not written since
+// previousUpdatedAt, counting the new coupon redemptions in the same transaction.
+func (r *EstimateDynamoRepository) UpdatePricing(ctx context.Context, e entities.Estimate, previousUpdatedAt time.Time, redemptions []entities.CouponRedemption) (entities.Estimate, error) {
+	av, err := attributevalue.MarshalMap(toEstimateItem(e))
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	put := types.TransactWriteItem{
+		Put: &types.Put{
+			TableName:           aws.String(r.tableName),
+			Item:                av,
+			ConditionExpression: aws.String("#updated_at = :previous"),
+			ExpressionAttributeNames: map[string]string{
+				"#updated_at": "updated_at",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":previous": &types.AttributeValueMemberS{Value: previousUpdatedAt.UTC().Format(time.RFC3339Nano)},
+			},
+		},
+	}
+	err = r.transactRedemptions(ctx, put, redemptions)
+	if errors.Is(err, errEstimateWriteFailed) {
+		return entities.Estimate{}, entities.ErrEstimateChanged
+	}
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	return e, nil
+}
+
+var errEstimateWriteFailed = errors.New("estimate write condition failed")
+
+// transactRedemptions writes the estimate with the redemption counters; a failed counter
+// condition becomes its coupon error, a failed estimate condition errEstimateWriteFailed.
+func (r *EstimateDynamoRepository) transactRedemptions(ctx context.Context, write types.TransactWriteItem, redemptions []entities.CouponRedemption) error {
+	items, errs := r.coupons.redeemItems(redemptions)
+	_, err := r.ddb.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: append([]types.TransactWriteItem{write}, items...),
+	})
+	if err == nil {
+		return nil
+	}
+	failed := canceledItems(err)
+	if failed[0] {
+		return errEstimateWriteFailed
+	}
+	for i, couponErr := range errs {
+		if failed[i+1] {
+			return couponErr
+		}
+	}
+	return err
+}
+
+// AddBalanceDue atomically adds delta (may be negative) to the estimate balance due and
+// records ref in balance_due_refs, in the same update conditioned on ref not being there
+// yet; a failed condition returns an empty Estimate.
+func (r *EstimateDynamoRepository) AddBalanceDue(ctx context.Context, id string, delta float64, ref string) (entities.Estimate, error) {
+	return r.updateIf(ctx, id, "NOT contains(#balance_due_refs, :ref)", func(now string) (string, map[string]types.AttributeValue, map[string]string) {
+		expr := "ADD #balance_due :delta, #balance_due_refs :refs SET #updated_at = :updated_at"
 		vals := map[string]types.AttributeValue{
-			":price":      &types.AttributeValueMemberN{Value: floatToString(newPrice)},
+			":delta":      &types.AttributeValueMemberN{Value: floatToString(delta)},
+			":ref":        &types.AttributeValueMemberS{Value: ref},
+			":refs":       &types.AttributeValueMemberSS{Value: []string{ref}},
 			":updated_at": &types.AttributeValueMemberS{Value: now},
 		}
 		names := map[string]string{
-			"#price":      "price",
-			"#updated_at": "updated_at",
+			"#balance_due":      "balance_due",
+			"#balance_due_refs": "balance_due_refs",
+			"#updated_at":       "updated_at",
 		}
 		return expr, vals, names
 	})
@@ -193,16 +573,31 @@ func (r *EstimateDynamoRepository) update(
 	ctx context.Context,
 	id string,
 	build func(now string) (updateExpr string, values map[string]types.AttributeValue, names map[string]string),
+) (entities.Estimate, error) {
+	return r.updateIf(ctx, id, "", build)
+}
+
+// updateIf is update with an extra condition on the item; its values and names come from
+// build.
+func (r *EstimateDynamoRepository) updateIf(
+	ctx context.Context,
+	id string,
+	condition string,
+	build func(now string) (updateExpr string, values map[string]types.AttributeValue, names map[string]string),
 ) (entities.Estimate, error) {
 	now := time.Now().UTC().Format(time.RFC3339Nano)
 	updateExpr, values, names := build(now)
+	conditionExpr := "attribute_exists(#id)"
+	if condition != "" {
+		conditionExpr += " AND " + condition
+	}
 
 	out, err := r.ddb.UpdateItem(ctx, &dynamodb.UpdateItemInput{
 		TableName: aws.String(r.tableName),
 		Key: map[string]types.AttributeValue{
 			"id": &types.AttributeValueMemberS{Value: id},
 		},
-		ConditionExpression:       aws.String("attribute_exists(#id)"),
+		ConditionExpression:       aws.String(conditionExpr),
 		UpdateExpression:          aws.String(updateExpr),
 		ExpressionAttributeValues: values,
 		ExpressionAttributeNames:  mergeNames(names, map[string]string{"#id": "id"}),
@@ -226,28 +621,285 @@ func (r *EstimateDynamoRepository) update(
 }
 
 func toEstimateItem(e entities.Estimate) estimateItem {
-	return estimateItem{
-		ID:        e.ID,
-		OSID:      e.OSID,
-		Price:     floatToString(e.Price),
-		Status:    string(e.Status),
-		CreatedAt: e.CreatedAt.UTC().Format(time.RFC3339Nano),
-		UpdatedAt: e.UpdatedAt.UTC().Format(time.RFC3339Nano),
+	it := estimateItem{
+		ID:             e.ID,
+		OSID:           e.OSID,
+		Price:          floatToString(e.Price),
+		BalanceDue:     e.BalanceDue,
+		BalanceDueRefs: e.BalanceDueRefs,
+		Status:         string(e.Status),
+		CreatedAt:      e.CreatedAt.UTC().Format(time.RFC3339Nano),
+		UpdatedAt:      e.UpdatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	if e.ApprovedAt != nil {
+		it.ApprovedAt = e.ApprovedAt.UTC().Format(time.RFC3339Nano)
+	}
+	it.Municipality = e.Municipality
+	for _, line := range e.Items {
+		it.Items = append(it.Items, toEstimateLineItem(line))
+	}
+	for _, t := range e.Taxes {
+		it.Taxes = append(it.Taxes, estimateTaxItem(t))
+	}
+	it.TaxTotal = e.TaxTotal
+	it.CustomerID = e.CustomerID
+	it.CustomerSegment = e.CustomerSegment
+	it.Subtotal = e.Subtotal
+	for _, d := range e.Discounts {
+		it.Discounts = append(it.Discounts, estimateDiscountItem{
+			Source:     string(d.Source),
+			CouponCode: d.CouponCode,
+			Type:       string(d.Rule.Type),
+			Value:      d.Rule.Value,
+			Scope:      string(d.Rule.Scope),
+			ItemKind:   string(d.Rule.ItemKind),
+			Reason:     d.Reason,
+			ApprovedBy: d.ApprovedBy,
+			Amount:     d.Amount,
+		})
+	}
+	it.DiscountTotal = e.DiscountTotal
+	it.PricingVersion = e.PricingVersion
+	for _, a := range e.Adjustments {
+		it.Adjustments = append(it.Adjustments, estimateAdjustmentItem{
+			RuleID: a.RuleID,
+			Name:   a.Name,
+			Type:   string(a.Type),
+			Reason: a.Reason,
+			Amount: a.Amount,
+		})
+	}
+	it.AdjustmentTotal = e.AdjustmentTotal
+	it.CatalogVersion = e.CatalogVersion
+	if e.ExpiresAt != nil {
+		it.ExpiresAt = e.ExpiresAt.UTC().Format(time.RFC3339Nano)
+	}
+	if e.RenewedAt != nil {
+		it.RenewedAt = e.RenewedAt.UTC().Format(time.RFC3339Nano)
+	}
+	it.Reason = toEstimateReasonItem(e.Reason)
+	it.DecidedBy = e.DecidedBy
+	for _, change := range e.History {
+		it.History = append(it.History, toEstimateStatusChangeItem(change))
+	}
+	for _, line := range e.DeclinedItems {
+		it.DeclinedItems = append(it.DeclinedItems, toEstimateLineItem(line))
+	}
+	if a := e.InternalApproval; a != nil {
+		it.InternalApproval = &estimateInternalApprovalItem{
+			Threshold:   a.Threshold,
+			Price:       a.Price,
+			RequestedAt: a.RequestedAt.UTC().Format(time.RFC3339Nano),
+			Decision:    string(a.Decision),
+			Approver:    a.Approver,
+			Comment:     a.Comment,
+		}
+		if a.DecidedAt != nil {
+			it.InternalApproval.DecidedAt = a.DecidedAt.UTC().Format(time.RFC3339Nano)
+		}
+	}
+	for _, d := range e.CatalogDeviations {
+		it.CatalogDeviations = append(it.CatalogDeviations, estimateCatalogDeviationItem{
+			Code:      d.Code,
+			Name:      d.Name,
+			Reason:    string(d.Reason),
+			UnitPrice: d.UnitPrice,
+			ListPrice: d.ListPrice,
+			Deviation: d.Deviation,
+		})
 	}
+	return it
 }
 
 func fromEstimateItem(it estimateItem) entities.Estimate {
 	createdAt, _ := time.Parse(time.RFC3339Nano, it.CreatedAt)
 	updatedAt, _ := time.Parse(time.RFC3339Nano, it.UpdatedAt)
 	price, _ := strconv.ParseFloat(it.Price, 64)
-	return entities.Estimate{
-		ID:        it.ID,
-		OSID:      it.OSID,
-		Price:     price,
-		Status:    entities.EstimateStatus(it.Status),
-		CreatedAt: createdAt,
-		UpdatedAt: updatedAt,
+	e := entities.Estimate{
+		ID:             it.ID,
+		OSID:           it.OSID,
+		Price:          price,
+		BalanceDue:     it.BalanceDue,
+		BalanceDueRefs: it.BalanceDueRefs,
+		Status:         entities.EstimateStatus(it.Status),
+		CreatedAt:      createdAt,
+		UpdatedAt:      updatedAt,
+	}
+	if approvedAt, err := time.Parse(time.RFC3339Nano, it.ApprovedAt); err == nil {
+		e.ApprovedAt = &approvedAt
+	}
+	e.Municipality = it.Municipality
+	for _, li := range it.Items {
+		e.Items = append(e.Items, fromEstimateLineItem(li))
+	}
+	for _, t := range it.Taxes {
+		e.Taxes = append(e.Taxes, entities.TaxAmount(t))
+	}
+	e.TaxTotal = it.TaxTotal
+	e.CustomerID = it.CustomerID
+	e.CustomerSegment = it.CustomerSegment
+	e.Subtotal = it.Subtotal
+	for _, d := range it.Discounts {
+		e.Discounts = append(e.Discounts, entities.EstimateDiscount{
+			Source:     entities.DiscountSource(d.Source),
+			CouponCode: d.CouponCode,
+			Rule: entities.DiscountRule{
+				Type:     entities.DiscountType(d.Type),
+				Value:    d.Value,
+				Scope:    entities.DiscountScope(d.Scope),
+				ItemKind: entities.EstimateItemKind(d.ItemKind),
+			},
+			Reason:     d.Reason,
+			ApprovedBy: d.ApprovedBy,
+			Amount:     d.Amount,
+		})
+	}
+	e.DiscountTotal = it.DiscountTotal
+	e.PricingVersion = it.PricingVersion
+	for _, a := range it.Adjustments {
+		e.Adjustments = append(e.Adjustments, entities.PricingAdjustment{
+			RuleID: a.RuleID,
+			Name:   a.Name,
+			Type:   entities.PricingRuleType(a.Type),
+			Reason: a.Reason,
+			Amount: a.Amount,
+		})
+	}
+	e.AdjustmentTotal = it.AdjustmentTotal
+	e.CatalogVersion = it.CatalogVersion
+	if expiresAt, err := time.Parse(time.RFC3339Nano, it.ExpiresAt); err == nil {
+		e.ExpiresAt = &expiresAt
+	}
+	if renewedAt, err := time.Parse(time.RFC3339Nano, it.RenewedAt); err == nil {
+		e.RenewedAt = &renewedAt
+	}
+	e.Reason = fromEstimateReasonItem(it.Reason)
+	e.DecidedBy = it.DecidedBy
+	for _, change := range it.History {
+		at, _ := time.Parse(time.RFC3339Nano, change.At)
+		entry := entities.EstimateStatusChange{
+			Status:  entities.EstimateStatus(change.Status),
+			At:      at,
+			Actor:   change.Actor,
+			Reason:  fromEstimateReasonItem(change.Reason),
+			Comment: change.Comment,
+		}
+		if ev := change.Evidence; ev != nil {
+			entry.Evidence = &entities.DecisionEvidence{LinkID: ev.LinkID, IP: ev.IP, UserAgent: ev.UserAgent, Recipient: ev.Recipient}
+		}
+		e.History = append(e.History, entry)
+	}
+	for _, li := range it.DeclinedItems {
+		e.DeclinedItems = append(e.DeclinedItems, fromEstimateLineItem(li))
+	}
+	if a := it.InternalApproval; a != nil {
+		requestedAt, _ := time.Parse(time.RFC3339Nano, a.RequestedAt)
+		e.InternalApproval = &entities.InternalApproval{
+			Threshold:   a.Threshold,
+			Price:       a.Price,
+			RequestedAt: requestedAt,
+			Decision:    entities.InternalApprovalDecision(a.Decision),
+			Approver:    a.Approver,
+			Comment:     a.Comment,
+		}
+		if decidedAt, err := time.Parse(time.RFC3339Nano, a.DecidedAt); err == nil {
+			e.InternalApproval.DecidedAt = &decidedAt
+		}
+	}
+	for _, d := range it.CatalogDeviations {
+		e.CatalogDeviations = append(e.CatalogDeviations, entities.CatalogDeviation{
+			Code:      d.Code,
+			Name:      d.Name,
+			Reason:    entities.CatalogDeviationReason(d.Reason),
+			UnitPrice: d.UnitPrice,
+			ListPrice: d.ListPrice,
+			Deviation: d.Deviation,
+		})
+	}
+	return e
+}
+
+func toEstimateLineItem(line entities.EstimateItem) estimateLineItem {
+	li := estimateLineItem{
+		ID:          line.ID,
+		Kind:        string(line.Kind),
+		Code:        line.Code,
+		Name:        line.Name,
+		Description: line.Description,
+		Category:    line.Category,
+		Hours:       line.Hours,
+		ActualHours: line.ActualHours,
+		SkillTier:   line.SkillTier,
+		HourlyRate:  line.HourlyRate,
+		Quantity:    line.Quantity,
+		UnitPrice:   line.UnitPrice,
+		Total:       line.Total,
+		Adjustment:  line.Adjustment,
+		Discount:    line.Discount,
+		Taxes:       make([]estimateLineTaxItem, 0, len(line.Taxes)),
+	}
+	for _, t := range line.Taxes {
+		li.Taxes = append(li.Taxes, estimateLineTaxItem(t))
+	}
+	return li
+}
+
+func fromEstimateLineItem(li estimateLineItem) entities.EstimateItem {
+	line := entities.EstimateItem{
+		ID:          li.ID,
+		Kind:        entities.EstimateItemKind(li.Kind),
+		Code:        li.Code,
+		Name:        li.Name,
+		Description: li.Description,
+		Category:    li.Category,
+		Hours:       li.Hours,
+		ActualHours: li.ActualHours,
+		SkillTier:   li.SkillTier,
+		HourlyRate:  li.HourlyRate,
+		Quantity:    li.Quantity,
+		UnitPrice:   li.UnitPrice,
+		Total:       li.Total,
+		Adjustment:  li.Adjustment,
+		Discount:    li.Discount,
+		Taxes:       make([]entities.LineTax, 0, len(li.Taxes)),
+	}
+	for _, t := range li.Taxes {
+		line.Taxes = append(line.Taxes, entities.LineTax(t))
+	}
+	return line
+}
+
+func toEstimateReasonItem(r *entities.StatusReason) *estimateReasonItem {
+	if r == nil {
+		return nil
+	}
+	return &estimateReasonItem{Code: r.Code, Label: r.Label, Text: r.Text}
+}
+
+func fromEstimateReasonItem(r *estimateReasonItem) *entities.StatusReason {
+	if r == nil {
+		return nil
+	}
+	return &entities.StatusReason{Code: r.Code, Label: r.Label, Text: r.Text}
+}
+
+func toEstimateStatusChangeItem(change entities.EstimateStatusChange) estimateStatusChangeItem {
+	item := estimateStatusChangeItem{
+		Status:  string(change.Status),
+		At:      change.At.UTC().Format(time.RFC3339Nano),
+		Actor:   change.Actor,
+		Reason:  toEstimateReasonItem(change.Reason),
+		Comment: change.Comment,
+	}
+	if change.Evidence != nil {
+		item.Evidence = &estimateDecisionEvidenceItem{
+			LinkID:    change.Evidence.LinkID,
+			IP:        change.Evidence.IP,
+			UserAgent: change.Evidence.UserAgent,
+			Recipient: change.Evidence.Recipient,
+		}
 	}
+	return item
 }
 
 func floatToString(v float64) string {
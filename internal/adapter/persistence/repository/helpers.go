@@ -1,6 +1,30 @@
 package repository
 
-import "os"
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// sortableTimeLayout is fixed-width (UTC, nanoseconds) so that string sort keys sort
+// chronologically.
+const sortableTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+const (
+	// batchWriteMaxItems is the BatchWriteItem limit per request.
+	batchWriteMaxItems = 25
+	// batchWriteMaxAttempts bounds the resubmission of unprocessed items.
+	batchWriteMaxAttempts = 8
+)
 
 func getenvDefault(key, def string) string {
 	if v := os.Getenv(key); v != "" {
@@ -8,3 +32,78 @@ func getenvDefault(key, def string) string {
 	}
 	return def
 }
+
+// batchPut writes items to table in BatchWriteItem chunks, resubmitting the items
+// DynamoDB leaves unprocessed with a growing delay.
+func batchPut(ctx context.Context, ddb *dynamodb.Client, table string, items []map[string]types.AttributeValue) error {
+	for start := 0; start < len(items); start += batchWriteMaxItems {
+		end := min(start+batchWriteMaxItems, len(items))
+		requests := make([]types.WriteRequest, 0, end-start)
+		for _, av := range items[start:end] {
+			requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: av}})
+		}
+
+		pending := map[string][]types.WriteRequest{table: requests}
+		for attempt := 0; len(pending) > 0; attempt++ {
+			if attempt == batchWriteMaxAttempts {
+				return fmt.Errorf("%s: %d items left unprocessed", table, len(pending[table]))
+			}
+			if attempt > 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(time.Duration(50<<attempt) * time.Millisecond):
+				}
+			}
+			out, err := ddb.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{RequestItems: pending})
+			if err != nil {
+				return err
+			}
+			pending = out.UnprocessedItems
+		}
+	}
+	return nil
+}
+
+// encodePageCursor turns a DynamoDB LastEvaluatedKey (string attributes only) into an
+// opaque cursor.
+func encodePageCursor(key map[string]types.AttributeValue) (string, error) {
+	if len(key) == 0 {
+		return "", nil
+	}
+	var plain map[string]string
+	if err := attributevalue.UnmarshalMap(key, &plain); err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(plain)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodePageCursor reverses encodePageCursor; required lists the key attributes the
+// cursor must carry for the queried index.
+func decodePageCursor(cursor string, required ...string) (map[string]types.AttributeValue, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, entities.ErrInvalidPageCursor
+	}
+	var plain map[string]string
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return nil, entities.ErrInvalidPageCursor
+	}
+	for _, attr := range required {
+		if plain[attr] == "" {
+			return nil, entities.ErrInvalidPageCursor
+		}
+	}
+	key, err := attributevalue.MarshalMap(plain)
+	if err != nil {
+		return nil, entities.ErrInvalidPageCursor
+	}
+	return key, nil
+}
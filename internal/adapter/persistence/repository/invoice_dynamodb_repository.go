@@ -0,0 +1,373 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	defaultInvoicesTableName = "invoices"
+
+	invoiceSequence    = "invoice"
+	creditNoteSequence = "credit_note"
+)
+
+type invoiceLineItem struct {
+	Description string  `dynamodbav:"description"`
+	Quantity    float64 `dynamodbav:"quantity"`
+	UnitPrice   float64 `dynamodbav:"unit_price"`
+	Total       float64 `dynamodbav:"total"`
+}
+
+type invoiceTaxItem struct {
+	Name   string  `dynamodbav:"name"`
+	Rate   float64 `dynamodbav:"rate"`
+	Amount float64 `dynamodbav:"amount"`
+}
+
+type invoicePaymentItem struct {
+	PaymentID string  `dynamodbav:"payment_id"`
+	Amount    float64 `dynamodbav:"amount"`
+	AppliedAt string  `dynamodbav:"applied_at"`
+}
+
+type creditNoteItem struct {
+	Number    int64   `dynamodbav:"number"`
+	Amount    float64 `dynamodbav:"amount"`
+	Reason    string  `dynamodbav:"reason"`
+	Actor     string  `dynamodbav:"actor,omitempty"`
+	CreatedAt string  `dynamodbav:"created_at"`
+}
+
+type invoiceItem struct {
+	ID          string               `dynamodbav:"id"`
+	Number      int64                `dynamodbav:"number"`
+	EstimateID  string               `dynamodbav:"estimate_id"`
+	OSID        string               `dynamodbav:"os_id"`
+	Status      string               `dynamodbav:"status"`
+	Lines       []invoiceLineItem    `dynamodbav:"lines"`
+	Taxes       []invoiceTaxItem     `dynamodbav:"taxes"`
+	Subtotal    float64              `dynamodbav:"subtotal"`
+	TaxTotal    float64              `dynamodbav:"tax_total"`
+	Total       float64              `dynamodbav:"total"`
+	Payments    []invoicePaymentItem `dynamodbav:"payments"`
+	CreditNotes []creditNoteItem     `dynamodbav:"credit_notes"`
+	IssuedBy    string               `dynamodbav:"issued_by,omitempty"`
+	IssuedAt    string               `dynamodbav:"issued_at"`
+	VoidedAt    string               `dynamodbav:"voided_at,omitempty"`
+	VoidReason  string               `dynamodbav:"void_reason,omitempty"`
+	VoidedBy    string               `dynamodbav:"voided_by,omitempty"`
+	Version     int64                `dynamodbav:"version"`
+}
+
+// InvoiceDynamoRepository persists invoices in DynamoDB.
+//
+// Table requirements:
+//   - invoices: PK id (string). Besides the invoices it holds one reservation item per
+//     invoiced estimate (id "estimate#<estimate_id>", invoice_id), removed on void.
+//   - sequences: PK id (string: "invoice", "credit_note"), attribute seq (number).
+//
+// A number is taken by moving seq from n to n+1 in the same transaction that stores the
+// document, so a failed write leaves no gap.
+
+type InvoiceDynamoRepository struct {
+	ddb       *dynamodb.Client
+	tableName string
+	sequences sequenceTable
+}
+
+var _ interfaces.IInvoiceRepository = (*InvoiceDynamoRepository)(nil)
+
+func NewInvoiceDynamoRepository(ddb *dynamodb.Client) *InvoiceDynamoRepository {
+	return &InvoiceDynamoRepository{
+		ddb:       ddb,
+		tableName: getenvDefault("INVOICES_TABLE", defaultInvoicesTableName),
+		sequences: newSequenceTable(ddb),
+	}
+}
+
+func invoiceEstimateKey(estimateID string) string {
+	return "estimate#" + estimateID
+}
+
+func (r *InvoiceDynamoRepository) Create(ctx context.Context, inv entities.Invoice) (entities.Invoice, error) {
+	for attempt := 0; attempt < maxSequenceAttempts; attempt++ {
+		current, err := r.sequences.current(ctx, invoiceSequence)
+		if err != nil {
+			return entities.Invoice{}, err
+		}
+		inv.Number = current + 1
+		inv.Version = 1
+		av, err := attributevalue.MarshalMap(toInvoiceItem(inv))
+		if err != nil {
+			return entities.Invoice{}, err
+		}
+
+		_, err = r.ddb.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+			TransactItems: []types.TransactWriteItem{
+				r.sequences.update(invoiceSequence, current),
+				{
+					Put: &types.Put{
+						TableName:           aws.String(r.tableName),
+						Item:                av,
+						ConditionExpression: aws.String("attribute_not_exists(#id)"),
+						ExpressionAttributeNames: map[string]string{
+							"#id": "id",
+						},
+					},
+				},
+				{
+					Put: &types.Put{
+						TableName: aws.String(r.tableName),
+						Item: map[string]types.AttributeValue{
+							"id":         &types.AttributeValueMemberS{Value: invoiceEstimateKey(inv.EstimateID)},
+							"invoice_id": &types.AttributeValueMemberS{Value: inv.ID},
+						},
+						ConditionExpression: aws.String("attribute_not_exists(#id)"),
+						ExpressionAttributeNames: map[string]string{
+							"#id": "id",
+						},
+					},
+				},
+			},
+		})
+		switch failed := canceledItems(err); {
+		case err == nil:
+			return inv, nil
+		case failed[0]:
+			continue
+		case failed[2]:
+			return entities.Invoice{}, entities.ErrInvoiceAlreadyIssued
+		default:
+			return entities.Invoice{}, err
+		}
+	}
+	return entities.Invoice{}, errSequenceContention
+}
+
+func (r *InvoiceDynamoRepository) GetByID(ctx context.Context, id string) (entities.Invoice, error) {
+	out, err := r.ddb.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return entities.Invoice{}, err
+	}
+	if len(out.Item) == 0 {
+		return entities.Invoice{}, nil
+	}
+	if _, ok := out.Item["invoice_id"]; ok {
+		// Estimate reservation, not an invoice.
+		return entities.Invoice{}, nil
+	}
+
+	var it invoiceItem
+	if err := attributevalue.UnmarshalMap(out.Item, &it); err != nil {
+		return entities.Invoice{}, err
+	}
+	return fromInvoiceItem(it), nil
+}
+
+func (r *InvoiceDynamoRepository) GetActiveByEstimateID(ctx context.Context, estimateID string) (entities.Invoice, error) {
+	out, err := r.ddb.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: invoiceEstimateKey(estimateID)},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return entities.Invoice{}, err
+	}
+	var it struct {
+		InvoiceID string `dynamodbav:"invoice_id"`
+	}
+	if err := attributevalue.UnmarshalMap(out.Item, &it); err != nil {
+		return entities.Invoice{}, err
+	}
+	if it.InvoiceID == "" {
+		return entities.Invoice{}, nil
+	}
+	return r.GetByID(ctx, it.InvoiceID)
+}
+
+func (r *InvoiceDynamoRepository) Update(ctx context.Context, inv entities.Invoice) (entities.Invoice, error) {
+	put, next, err := r.versionedPut(inv)
+	if err != nil {
+		return entities.Invoice{}, err
+	}
+	items := []types.TransactWriteItem{put}
+	if inv.Status == entities.InvoiceStatusCancelada {
+		items = append(items, types.TransactWriteItem{
+			Delete: &types.Delete{
+				TableName: aws.String(r.tableName),
+				Key: map[string]types.AttributeValue{
+					"id": &types.AttributeValueMemberS{Value: invoiceEstimateKey(inv.EstimateID)},
+				},
+				ConditionExpression: aws.String("attribute_not_exists(#id) OR #invoice_id = :invoice_id"),
+				ExpressionAttributeNames: map[string]string{
+					"#id":         "id",
+					"#invoice_id": "invoice_id",
+				},
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":invoice_id": &types.AttributeValueMemberS{Value: inv.ID},
+				},
+			},
+		})
+	}
+
+	_, err = r.ddb.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+	if failed := canceledItems(err); failed[0] {
+		return entities.Invoice{}, entities.ErrInvoiceChanged
+	}
+	if err != nil {
+		return entities.Invoice{}, err
+	}
+	return next, nil
+}
+
+func (r *InvoiceDynamoRepository) AddCreditNote(ctx context.Context, inv entities.Invoice, note entities.CreditNote) (entities.Invoice, error) {
+	for attempt := 0; attempt < maxSequenceAttempts; attempt++ {
+		current, err := r.sequences.current(ctx, creditNoteSequence)
+		if err != nil {
+			return entities.Invoice{}, err
+		}
+		note.Number = current + 1
+		withNote := inv
+		withNote.CreditNotes = append(append([]entities.CreditNote{}, inv.CreditNotes...), note)
+		put, next, err := r.versionedPut(withNote)
+		if err != nil {
+			return entities.Invoice{}, err
+		}
+
+		_, err = r.ddb.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+			TransactItems: []types.TransactWriteItem{r.sequences.update(creditNoteSequence, current), put},
+		})
+		switch failed := canceledItems(err); {
+		case err == nil:
+			return next, nil
+		case failed[1]:
+			return entities.Invoice{}, entities.ErrInvoiceChanged
+		case failed[0]:
+			continue
+		default:
+			return entities.Invoice{}, err
+		}
+	}
+	return entities.Invoice{}, errSequenceContention
+}
+
+// versionedPut replaces the invoice only if it still has inv.Version, bumping it.
+func (r *InvoiceDynamoRepository) versionedPut(inv entities.Invoice) (types.TransactWriteItem, entities.Invoice, error) {
+	expected := inv.Version
+	inv.Version++
+	av, err := attributevalue.MarshalMap(toInvoiceItem(inv))
+	if err != nil {
+		return types.TransactWriteItem{}, entities.Invoice{}, err
+	}
+	return types.TransactWriteItem{
+		Put: &types.Put{
+			TableName:           aws.String(r.tableName),
+			Item:                av,
+			ConditionExpression: aws.String("#version = :expected"),
+			ExpressionAttributeNames: map[string]string{
+				"#version": "version",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":expected": &types.AttributeValueMemberN{Value: strconv.FormatInt(expected, 10)},
+			},
+		},
+	}, inv, nil
+}
+
+func toInvoiceItem(inv entities.Invoice) invoiceItem {
+	it := invoiceItem{
+		ID:          inv.ID,
+		Number:      inv.Number,
+		EstimateID:  inv.EstimateID,
+		OSID:        inv.OSID,
+		Status:      string(inv.Status),
+		Lines:       make([]invoiceLineItem, 0, len(inv.Lines)),
+		Taxes:       make([]invoiceTaxItem, 0, len(inv.Taxes)),
+		Subtotal:    inv.Subtotal,
+		TaxTotal:    inv.TaxTotal,
+		Total:       inv.Total,
+		Payments:    make([]invoicePaymentItem, 0, len(inv.Payments)),
+		CreditNotes: make([]creditNoteItem, 0, len(inv.CreditNotes)),
+		IssuedBy:    inv.IssuedBy,
+		IssuedAt:    inv.IssuedAt.UTC().Format(time.RFC3339Nano),
+		VoidReason:  inv.VoidReason,
+		VoidedBy:    inv.VoidedBy,
+		Version:     inv.Version,
+	}
+	for _, l := range inv.Lines {
+		it.Lines = append(it.Lines, invoiceLineItem(l))
+	}
+	for _, t := range inv.Taxes {
+		it.Taxes = append(it.Taxes, invoiceTaxItem(t))
+	}
+	for _, p := range inv.Payments {
+		it.Payments = append(it.Payments, invoicePaymentItem{PaymentID: p.PaymentID, Amount: p.Amount, AppliedAt: p.AppliedAt.UTC().Format(time.RFC3339Nano)})
+	}
+	for _, c := range inv.CreditNotes {
+		it.CreditNotes = append(it.CreditNotes, creditNoteItem{Number: c.Number, Amount: c.Amount, Reason: c.Reason, Actor: c.Actor, CreatedAt: c.CreatedAt.UTC().Format(time.RFC3339Nano)})
+	}
+	if inv.VoidedAt != nil {
+		it.VoidedAt = inv.VoidedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return it
+}
+
+func fromInvoiceItem(it invoiceItem) entities.Invoice {
+	inv := entities.Invoice{
+		ID:          it.ID,
+		Number:      it.Number,
+		EstimateID:  it.EstimateID,
+		OSID:        it.OSID,
+		Status:      entities.InvoiceStatus(it.Status),
+		Lines:       make([]entities.InvoiceLine, 0, len(it.Lines)),
+		Taxes:       make([]entities.InvoiceTax, 0, len(it.Taxes)),
+		Subtotal:    it.Subtotal,
+		TaxTotal:    it.TaxTotal,
+		Total:       it.Total,
+		Payments:    make([]entities.InvoicePayment, 0, len(it.Payments)),
+		CreditNotes: make([]entities.CreditNote, 0, len(it.CreditNotes)),
+		IssuedBy:    it.IssuedBy,
+		VoidReason:  it.VoidReason,
+		VoidedBy:    it.VoidedBy,
+		Version:     it.Version,
+	}
+	inv.IssuedAt, _ = time.Parse(time.RFC3339Nano, it.IssuedAt)
+	for _, l := range it.Lines {
+		inv.Lines = append(inv.Lines, entities.InvoiceLine(l))
+	}
+	for _, t := range it.Taxes {
+		inv.Taxes = append(inv.Taxes, entities.InvoiceTax(t))
+	}
+	for _, p := range it.Payments {
+		appliedAt, _ := time.Parse(time.RFC3339Nano, p.AppliedAt)
+		inv.Payments = append(inv.Payments, entities.InvoicePayment{PaymentID: p.PaymentID, Amount: p.Amount, AppliedAt: appliedAt})
+	}
+	for _, c := range it.CreditNotes {
+		createdAt, _ := time.Parse(time.RFC3339Nano, c.CreatedAt)
+		inv.CreditNotes = append(inv.CreditNotes, entities.CreditNote{Number: c.Number, Amount: c.Amount, Reason: c.Reason, Actor: c.Actor, CreatedAt: createdAt})
+	}
+	if it.VoidedAt != "" {
+		if t, err := time.Parse(time.RFC3339Nano, it.VoidedAt); err == nil {
+			inv.VoidedAt = &t
+		}
+	}
+	return inv
+}
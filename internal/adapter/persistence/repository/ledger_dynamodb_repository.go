@@ -0,0 +1,444 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	defaultLedgerEntriesTableName  = "ledger_entries"
+	defaultLedgerBalancesTableName = "ledger_balances"
+	ledgerEntriesEstimateIndex     = "estimate_id-created_at-index"
+	ledgerBalancesAccountIndex     = "account-scope-index"
+
+	// ledgerEstimateScopePrefix starts the scope of every estimate balance. Ledger-wide
+	// account totals are the sum of those rows; rows of other scopes (the "all" rows
+	// written by earlier versions) are ignored.
+	ledgerEstimateScopePrefix = "estimate#"
+
+	// ledgerTransactAttempts bounds the retries of a posting canceled by a concurrent
+	// one; the wait starts at ledgerTransactBackoff and doubles, with jitter.
+	ledgerTransactAttempts = 5
+	ledgerTransactBackoff  = 50 * time.Millisecond
+)
+
+func ledgerEstimateScope(estimateID string) string {
+	return ledgerEstimateScopePrefix + estimateID
+}
+
+type ledgerLineItem struct {
+	Account string `dynamodbav:"account"`
+	Debit   int64  `dynamodbav:"debit,omitempty"`
+	Credit  int64  `dynamodbav:"credit,omitempty"`
+}
+
+type ledgerEntryItem struct {
+	ID         string           `dynamodbav:"id"`
+	Kind       string           `dynamodbav:"kind"`
+	PaymentID  string           `dynamodbav:"payment_id"`
+	EstimateID string           `dynamodbav:"estimate_id"`
+	Lines      []ledgerLineItem `dynamodbav:"lines"`
+	CreatedAt  string           `dynamodbav:"created_at"`
+}
+
+type ledgerBalanceItem struct {
+	Scope     string `dynamodbav:"scope"`
+	Account   string `dynamodbav:"account"`
+	Debits    int64  `dynamodbav:"debits"`
+	Credits   int64  `dynamodbav:"credits"`
+	UpdatedAt string `dynamodbav:"updated_at"`
+}
+
+// ledgerTables builds the transaction items of a ledger posting, so the payments
+// repository can post an entry in the same transaction as the payment write.
+type ledgerTables struct {
+	entries  string
+	balances string
+}
+
+func newLedgerTables() ledgerTables {
+	return ledgerTables{
+		entries:  getenvDefault("LEDGER_ENTRIES_TABLE", defaultLedgerEntriesTableName),
+		balances: getenvDefault("LEDGER_BALANCES_TABLE", defaultLedgerBalancesTableName),
+	}
+}
+
+// writeItems puts the entry (only once per ID) and adds its lines to the estimate
+// balances. Lines of the same account are summed first: a transaction cannot
+// touch the same balance item twice.
+func (t ledgerTables) writeItems(entry entities.LedgerEntry) ([]types.TransactWriteItem, error) {
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+	av, err := attributevalue.MarshalMap(toLedgerEntryItem(entry))
+	if err != nil {
+		return nil, err
+	}
+	items := []types.TransactWriteItem{{
+		Put: &types.Put{
+			TableName:           aws.String(t.entries),
+			Item:                av,
+			ConditionExpression: aws.String("attribute_not_exists(#id)"),
+			ExpressionAttributeNames: map[string]string{
+				"#id": "id",
+			},
+		},
+	}}
+
+	totals := map[entities.LedgerAccount]*entities.LedgerLine{}
+	var accounts []entities.LedgerAccount
+	for _, l := range entry.Lines {
+		sum, ok := totals[l.Account]
+		if !ok {
+			sum = &entities.LedgerLine{Account: l.Account}
+			totals[l.Account] = sum
+			accounts = append(accounts, l.Account)
+		}
+		sum.Debit += l.Debit
+		sum.Credit += l.Credit
+	}
+
+	updatedAt := entry.CreatedAt.UTC().Format(time.RFC3339Nano)
+	scope := ledgerEstimateScope(entry.EstimateID)
+	for _, account := range accounts {
+		sum := totals[account]
+		items = append(items, types.TransactWriteItem{
+			Update: &types.Update{
+				TableName: aws.String(t.balances),
+				Key: map[string]types.AttributeValue{
+					"scope":   &types.AttributeValueMemberS{Value: scope},
+					"account": &types.AttributeValueMemberS{Value: string(account)},
+				},
+				UpdateExpression: aws.String("ADD #debits :debits, #credits :credits SET #updated_at = :updated_at"),
+				ExpressionAttributeNames: map[string]string{
+					"#debits":     "debits",
+					"#credits":    "credits",
+					"#updated_at": "updated_at",
+				},
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":debits":     &types.AttributeValueMemberN{Value: strconv.FormatInt(sum.Debit, 10)},
+					":credits":    &types.AttributeValueMemberN{Value: strconv.FormatInt(sum.Credit, 10)},
+					":updated_at": &types.AttributeValueMemberS{Value: updatedAt},
+				},
+			},
+		})
+	}
+	return items, nil
+}
+
+// transact runs a transaction holding a ledger posting. Postings of the same estimate
+// update the same balance rows, so concurrent ones cancel each other with
+// TransactionConflict; those are retried with backoff. A failed condition on the entry
+// put becomes entities.ErrLedgerConflict and one on any other item (the payment status)
+// entities.ErrPaymentStatusChanged.
+func (t ledgerTables) transact(ctx context.Context, ddb *dynamodb.Client, items []types.TransactWriteItem) error {
+	backoff := ledgerTransactBackoff
+	for attempt := 1; ; attempt++ {
+		_, err := ddb.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+		if err == nil {
+			return nil
+		}
+		var canceled *types.TransactionCanceledException
+		if !errors.As(err, &canceled) {
+			return err
+		}
+		retry, err := t.cancellation(items, canceled)
+		if !retry || attempt == ledgerTransactAttempts {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff + rand.N(backoff)):
+		}
+		backoff *= 2
+	}
+}
+
+// cancellation classifies a canceled ledger transaction by its reasons, which are
+// listed in the order of items. It reports whether the transaction is worth retrying.
+func (t ledgerTables) cancellation(items []types.TransactWriteItem, canceled *types.TransactionCanceledException) (bool, error) {
+	conflict := false
+	for i, reason := range canceled.CancellationReasons {
+		switch aws.ToString(reason.Code) {
+		case "ConditionalCheckFailed":
+			if i < len(items) && items[i].Put != nil && aws.ToString(items[i].Put.TableName) == t.entries {
+				return false, entities.ErrLedgerConflict
+			}
+			return false, entities.ErrPaymentStatusChanged
+		case "TransactionConflict":
+			conflict = true
+		}
+	}
+	return conflict, canceled
+}
+
+// LedgerDynamoRepository persists the double-entry ledger in DynamoDB.
+//
+// Table requirements:
+//   - ledger_entries: PK id (string); GSI estimate_id-created_at-index (PK estimate_id,
+//     SK created_at)
+//   - ledger_balances: PK scope (string: "estimate#<id>"), SK account (string); GSI
+//     account-scope-index (PK account, SK scope)
+//
+// Entries are never updated; balances are only changed in the transaction that puts
+// the entry. There is no ledger-wide balance row: it would be updated by every posting.
+// GetAccountBalance sums the estimate rows of the account instead.
+
+type LedgerDynamoRepository struct {
+	ddb    *dynamodb.Client
+	tables ledgerTables
+}
+
+var _ interfaces.ILedgerRepository = (*LedgerDynamoRepository)(nil)
+
+func NewLedgerDynamoRepository(ddb *dynamodb.Client) *LedgerDynamoRepository {
+	return &LedgerDynamoRepository{ddb: ddb, tables: newLedgerTables()}
+}
+
+func (r *LedgerDynamoRepository) Post(ctx context.Context, entry entities.LedgerEntry) error {
+	items, err := r.tables.writeItems(entry)
+	if err != nil {
+		return err
+	}
+	return r.tables.transact(ctx, r.ddb, items)
+}
+
+func (r *LedgerDynamoRepository) GetAccountBalance(ctx context.Context, account entities.LedgerAccount) (entities.LedgerBalance, error) {
+	total := entities.LedgerBalance{Account: account}
+	var startKey map[string]types.AttributeValue
+	for {
+		out, err := r.ddb.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(r.tables.balances),
+			IndexName:              aws.String(ledgerBalancesAccountIndex),
+			KeyConditionExpression: aws.String("#account = :account AND begins_with(#scope, :prefix)"),
+			ExpressionAttributeNames: map[string]string{
+				"#account": "account",
+				"#scope":   "scope",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":account": &types.AttributeValueMemberS{Value: string(account)},
+				":prefix":  &types.AttributeValueMemberS{Value: ledgerEstimateScopePrefix},
+			},
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			if isIndexNotAvailableError(err) {
+				return r.scanAccountBalance(ctx, account)
+			}
+			return entities.LedgerBalance{}, err
+		}
+		if err := addLedgerBalanceItems(&total, out.Items); err != nil {
+			return entities.LedgerBalance{}, err
+		}
+		if len(out.LastEvaluatedKey) == 0 {
+			return total, nil
+		}
+		startKey = out.LastEvaluatedKey
+	}
+}
+
+func (r *LedgerDynamoRepository) scanAccountBalance(ctx context.Context, account entities.LedgerAccount) (entities.LedgerBalance, error) {
+	total := entities.LedgerBalance{Account: account}
+	var startKey map[string]types.AttributeValue
+	for {
+		out, err := r.ddb.Scan(ctx, &dynamodb.ScanInput{
+			TableName:        aws.String(r.tables.balances),
+			FilterExpression: aws.String("#account = :account AND begins_with(#scope, :prefix)"),
+			ExpressionAttributeNames: map[string]string{
+				"#account": "account",
+				"#scope":   "scope",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":account": &types.AttributeValueMemberS{Value: string(account)},
+				":prefix":  &types.AttributeValueMemberS{Value: ledgerEstimateScopePrefix},
+			},
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return entities.LedgerBalance{}, err
+		}
+		if err := addLedgerBalanceItems(&total, out.Items); err != nil {
+			return entities.LedgerBalance{}, err
+		}
+		if len(out.LastEvaluatedKey) == 0 {
+			return total, nil
+		}
+		startKey = out.LastEvaluatedKey
+	}
+}
+
+// addLedgerBalanceItems adds estimate balance rows to an account total, which keeps the
+// latest update time among them.
+func addLedgerBalanceItems(total *entities.LedgerBalance, raws []map[string]types.AttributeValue) error {
+	for _, raw := range raws {
+		var it ledgerBalanceItem
+		if err := attributevalue.UnmarshalMap(raw, &it); err != nil {
+			return err
+		}
+		b := fromLedgerBalanceItem(it, "")
+		total.Debits += b.Debits
+		total.Credits += b.Credits
+		if b.UpdatedAt.After(total.UpdatedAt) {
+			total.UpdatedAt = b.UpdatedAt
+		}
+	}
+	return nil
+}
+
+func (r *LedgerDynamoRepository) ListEstimateBalances(ctx context.Context, estimateID string) ([]entities.LedgerBalance, error) {
+	balances := []entities.LedgerBalance{}
+	var startKey map[string]types.AttributeValue
+	for {
+		out, err := r.ddb.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(r.tables.balances),
+			KeyConditionExpression: aws.String("#scope = :scope"),
+			ExpressionAttributeNames: map[string]string{
+				"#scope": "scope",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":scope": &types.AttributeValueMemberS{Value: ledgerEstimateScope(estimateID)},
+			},
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, raw := range out.Items {
+			var it ledgerBalanceItem
+			if err := attributevalue.UnmarshalMap(raw, &it); err != nil {
+				return nil, err
+			}
+			balances = append(balances, fromLedgerBalanceItem(it, estimateID))
+		}
+		if len(out.LastEvaluatedKey) == 0 {
+			return balances, nil
+		}
+		startKey = out.LastEvaluatedKey
+	}
+}
+
+func (r *LedgerDynamoRepository) ListEntriesByEstimate(ctx context.Context, estimateID string) ([]entities.LedgerEntry, error) {
+	entries := []entities.LedgerEntry{}
+	var startKey map[string]types.AttributeValue
+	for {
+		out, err := r.ddb.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(r.tables.entries),
+			IndexName:              aws.String(ledgerEntriesEstimateIndex),
+			KeyConditionExpression: aws.String("#estimate_id = :estimate_id"),
+			ExpressionAttributeNames: map[string]string{
+				"#estimate_id": "estimate_id",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":estimate_id": &types.AttributeValueMemberS{Value: estimateID},
+			},
+			ScanIndexForward:  aws.Bool(true),
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			if isIndexNotAvailableError(err) {
+				return r.scanEntriesByEstimate(ctx, estimateID)
+			}
+			return nil, err
+		}
+		for _, raw := range out.Items {
+			var it ledgerEntryItem
+			if err := attributevalue.UnmarshalMap(raw, &it); err != nil {
+				return nil, err
+			}
+			entries = append(entries, fromLedgerEntryItem(it))
+		}
+		if len(out.LastEvaluatedKey) == 0 {
+			return entries, nil
+		}
+		startKey = out.LastEvaluatedKey
+	}
+}
+
+func (r *LedgerDynamoRepository) scanEntriesByEstimate(ctx context.Context, estimateID string) ([]entities.LedgerEntry, error) {
+	entries := []entities.LedgerEntry{}
+	var startKey map[string]types.AttributeValue
+	for {
+		out, err := r.ddb.Scan(ctx, &dynamodb.ScanInput{
+			TableName:        aws.String(r.tables.entries),
+			FilterExpression: aws.String("#estimate_id = :estimate_id"),
+			ExpressionAttributeNames: map[string]string{
+				"#estimate_id": "estimate_id",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":estimate_id": &types.AttributeValueMemberS{Value: estimateID},
+			},
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, raw := range out.Items {
+			var it ledgerEntryItem
+			if err := attributevalue.UnmarshalMap(raw, &it); err != nil {
+				return nil, err
+			}
+			entries = append(entries, fromLedgerEntryItem(it))
+		}
+		if len(out.LastEvaluatedKey) == 0 {
+			break
+		}
+		startKey = out.LastEvaluatedKey
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].CreatedAt.Before(entries[j].CreatedAt) })
+	return entries, nil
+}
+
+func toLedgerEntryItem(e entities.LedgerEntry) ledgerEntryItem {
+	lines := make([]ledgerLineItem, 0, len(e.Lines))
+	for _, l := range e.Lines {
+		lines = append(lines, ledgerLineItem{Account: string(l.Account), Debit: l.Debit, Credit: l.Credit})
+	}
+	return ledgerEntryItem{
+		ID:         e.ID,
+		Kind:       string(e.Kind),
+		PaymentID:  e.PaymentID,
+		EstimateID: e.EstimateID,
+		Lines:      lines,
+		CreatedAt:  e.CreatedAt.UTC().Format(sortableTimeLayout),
+	}
+}
+
+func fromLedgerEntryItem(it ledgerEntryItem) entities.LedgerEntry {
+	lines := make([]entities.LedgerLine, 0, len(it.Lines))
+	for _, l := range it.Lines {
+		lines = append(lines, entities.LedgerLine{Account: entities.LedgerAccount(l.Account), Debit: l.Debit, Credit: l.Credit})
+	}
+	createdAt, _ := time.Parse(time.RFC3339Nano, it.CreatedAt)
+	return entities.LedgerEntry{
+		ID:         it.ID,
+		Kind:       entities.LedgerEntryKind(it.Kind),
+		PaymentID:  it.PaymentID,
+		EstimateID: it.EstimateID,
+		Lines:      lines,
+		CreatedAt:  createdAt,
+	}
+}
+
+func fromLedgerBalanceItem(it ledgerBalanceItem, estimateID string) entities.LedgerBalance {
+	updatedAt, _ := time.Parse(time.RFC3339Nano, it.UpdatedAt)
+	return entities.LedgerBalance{
+		Account:    entities.LedgerAccount(it.Account),
+		EstimateID: estimateID,
+		Debits:     it.Debits,
+		Credits:    it.Credits,
+		UpdatedAt:  updatedAt,
+	}
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"mecanica_xpto/internal/domain/entities"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestLedgerTables_Cancellation(t *testing.T) {
+	tables := ledgerTables{entries: "ledger_entries", balances: "ledger_balances"}
+	// payment status update, entry put, "all" balance update
+	items := []types.TransactWriteItem{
+		{Update: &types.Update{TableName: aws.String("payments")}},
+		{Put: &types.Put{TableName: aws.String("ledger_entries")}},
+		{Update: &types.Update{TableName: aws.String("ledger_balances")}},
+	}
+	reasons := func(codes ...string) *types.TransactionCanceledException {
+		canceled := &types.TransactionCanceledException{}
+		for _, c := range codes {
+			canceled.CancellationReasons = append(canceled.CancellationReasons, types.CancellationReason{Code: aws.String(c)})
+		}
+		return canceled
+	}
+
+	cases := []struct {
+		name      string
+		canceled  *types.TransactionCanceledException
+		wantRetry bool
+		wantErr   error
+	}{
+		{"conflict on the shared balance", reasons("None", "None", "TransactionConflict"), true, nil},
+		{"entry already posted", reasons("None", "ConditionalCheckFailed", "None"), false, entities.ErrLedgerConflict},
+		{"payment status changed", reasons("ConditionalCheckFailed", "None", "None"), false, entities.ErrPaymentStatusChanged},
+		{"failed condition wins over conflict", reasons("None", "ConditionalCheckFailed", "TransactionConflict"), false, entities.ErrLedgerConflict},
+		{"throttled", reasons("None", "None", "ThrottlingError"), false, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			retry, err := tables.cancellation(items, tc.canceled)
+			if retry != tc.wantRetry {
+				t.Fatalf("retry = %v, want %v", retry, tc.wantRetry)
+			}
+			if tc.wantErr != nil && !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected %v, got %v", tc.wantErr, err)
+			}
+			if errors.Is(err, entities.ErrLedgerConflict) && tc.wantErr == nil {
+				t.Fatalf("unexpected ErrLedgerConflict")
+			}
+		})
+	}
+}
@@ -0,0 +1,368 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	defaultNFSeDocumentsTableName = "nfse_documents"
+
+	nfseTomadorDocHashIndex   = "tomador_doc_hash-index"
+	nfseTomadorEmailHashIndex = "tomador_email_hash-index"
+
+	rpsSequence = "rps"
+)
+
+type nfseAddressItem struct {
+	Street     string `dynamodbav:"street"`
+	Number     string `dynamodbav:"number"`
+	Complement string `dynamodbav:"complement,omitempty"`
+	District   string `dynamodbav:"district"`
+	CityCode   string `dynamodbav:"city_code"`
+	State      string `dynamodbav:"state"`
+	PostalCode string `dynamodbav:"postal_code"`
+}
+
+type nfseTomadorItem struct {
+	Document string           `dynamodbav:"document"`
+	Name     string           `dynamodbav:"name"`
+	Email    string           `dynamodbav:"email,omitempty"`
+	Address  *nfseAddressItem `dynamodbav:"address,omitempty"`
+}
+
+type nfseItem struct {
+	ID                string          `dynamodbav:"id"`
+	InvoiceID         string          `dynamodbav:"invoice_id"`
+	EstimateID        string          `dynamodbav:"estimate_id"`
+	RPSNumber         int64           `dynamodbav:"rps_number"`
+	RPSSeries         string          `dynamodbav:"rps_series"`
+	RPSIssuedAt       string          `dynamodbav:"rps_issued_at"`
+	Tomador           nfseTomadorItem `dynamodbav:"tomador"`
+	ServiceDesc       string          `dynamodbav:"service_description"`
+	ServiceAmount     float64         `dynamodbav:"service_amount"`
+	Status            string          `dynamodbav:"status"`
+	SignedXML         []byte          `dynamodbav:"signed_xml"`
+	Protocol          string          `dynamodbav:"protocol,omitempty"`
+	TransmissionError string          `dynamodbav:"transmission_error,omitempty"`
+	Attempts          int             `dynamodbav:"attempts"`
+	CreatedBy         string          `dynamodbav:"created_by,omitempty"`
+	CreatedAt         string          `dynamodbav:"created_at"`
+	TransmittedAt     string          `dynamodbav:"transmitted_at,omitempty"`
+	PIIKeyID          string          `dynamodbav:"pii_key_id,omitempty"`
+	PIIWrappedKey     []byte          `dynamodbav:"pii_wrapped_key,omitempty"`
+	TomadorDocHash    string          `dynamodbav:"tomador_doc_hash,omitempty"`
+	TomadorEmailHash  string          `dynamodbav:"tomador_email_hash,omitempty"`
+}
+
+// NFSeDynamoRepository persists signed NFS-e documents in DynamoDB.
+//
+// Table requirements:
+//   - nfse_documents: PK id (string). Besides the documents it holds one reservation item
+//     per invoice (id "invoice#<invoice_id>", nfse_id).
+//   - GSI: tomador_doc_hash-index (PK: tomador_doc_hash)
+//   - GSI: tomador_email_hash-index (PK: tomador_email_hash)
+//   - sequences: the "rps" counter (see sequenceTable).
+
+type NFSeDynamoRepository struct {
+	ddb       *dynamodb.Client
+	tableName string
+	sequences sequenceTable
+}
+
+var _ interfaces.INFSeRepository = (*NFSeDynamoRepository)(nil)
+
+func NewNFSeDynamoRepository(ddb *dynamodb.Client) *NFSeDynamoRepository {
+	return &NFSeDynamoRepository{
+		ddb:       ddb,
+		tableName: getenvDefault("NFSE_DOCUMENTS_TABLE", defaultNFSeDocumentsTableName),
+		sequences: newSequenceTable(ddb),
+	}
+}
+
+func nfseInvoiceKey(invoiceID string) string {
+	return "invoice#" + invoiceID
+}
+
+func (r *NFSeDynamoRepository) CurrentRPSNumber(ctx context.Context) (int64, error) {
+	return r.sequences.current(ctx, rpsSequence)
+}
+
+func (r *NFSeDynamoRepository) Create(ctx context.Context, doc entities.NFSeDocument) error {
+	av, err := attributevalue.MarshalMap(toNFSeItem(doc))
+	if err != nil {
+		return err
+	}
+
+	_, err = r.ddb.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			r.sequences.update(rpsSequence, doc.RPS.Number-1),
+			{
+				Put: &types.Put{
+					TableName:           aws.String(r.tableName),
+					Item:                av,
+					ConditionExpression: aws.String("attribute_not_exists(#id)"),
+					ExpressionAttributeNames: map[string]string{
+						"#id": "id",
+					},
+				},
+			},
+			{
+				Put: &types.Put{
+					TableName: aws.String(r.tableName),
+					Item: map[string]types.AttributeValue{
+						"id":      &types.AttributeValueMemberS{Value: nfseInvoiceKey(doc.InvoiceID)},
+						"nfse_id": &types.AttributeValueMemberS{Value: doc.ID},
+					},
+					ConditionExpression: aws.String("attribute_not_exists(#id)"),
+					ExpressionAttributeNames: map[string]string{
+						"#id": "id",
+					},
+				},
+			},
+		},
+	})
+	switch failed := canceledItems(err); {
+	case err == nil:
+		return nil
+	case failed[2]:
+		return entities.ErrNFSeAlreadyGenerated
+	case failed[0]:
+		return entities.ErrRPSNumberTaken
+	default:
+		return err
+	}
+}
+
+func (r *NFSeDynamoRepository) GetByID(ctx context.Context, id string) (entities.NFSeDocument, error) {
+	out, err := r.ddb.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return entities.NFSeDocument{}, err
+	}
+	if len(out.Item) == 0 {
+		return entities.NFSeDocument{}, nil
+	}
+	if _, ok := out.Item["nfse_id"]; ok {
+		// Invoice reservation, not a document.
+		return entities.NFSeDocument{}, nil
+	}
+
+	var it nfseItem
+	if err := attributevalue.UnmarshalMap(out.Item, &it); err != nil {
+		return entities.NFSeDocument{}, err
+	}
+	return fromNFSeItem(it), nil
+}
+
+func (r *NFSeDynamoRepository) GetByInvoiceID(ctx context.Context, invoiceID string) (entities.NFSeDocument, error) {
+	out, err := r.ddb.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: nfseInvoiceKey(invoiceID)},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return entities.NFSeDocument{}, err
+	}
+	var it struct {
+		NFSeID string `dynamodbav:"nfse_id"`
+	}
+	if err := attributevalue.UnmarshalMap(out.Item, &it); err != nil {
+		return entities.NFSeDocument{}, err
+	}
+	if it.NFSeID == "" {
+		return entities.NFSeDocument{}, nil
+	}
+	return r.GetByID(ctx, it.NFSeID)
+}
+
+func (r *NFSeDynamoRepository) UpdateTransmission(ctx context.Context, doc entities.NFSeDocument) error {
+	values := map[string]types.AttributeValue{
+		":status":   &types.AttributeValueMemberS{Value: string(doc.Status)},
+		":protocol": &types.AttributeValueMemberS{Value: doc.Protocol},
+		":error":    &types.AttributeValueMemberS{Value: doc.TransmissionError},
+		":attempts": &types.AttributeValueMemberN{Value: strconv.Itoa(doc.Attempts)},
+	}
+	update := "SET #status = :status, #protocol = :protocol, #error = :error, #attempts = :attempts"
+	names := map[string]string{
+		"#status":   "status",
+		"#protocol": "protocol",
+		"#error":    "transmission_error",
+		"#attempts": "attempts",
+		"#id":       "id",
+	}
+	if doc.TransmittedAt != nil {
+		update += ", #transmitted_at = :transmitted_at"
+		names["#transmitted_at"] = "transmitted_at"
+		values[":transmitted_at"] = &types.AttributeValueMemberS{Value: doc.TransmittedAt.UTC().Format(time.RFC3339Nano)}
+	}
+
+	_, err := r.ddb.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: doc.ID},
+		},
+		ConditionExpression:       aws.String("attribute_exists(#id)"),
+		UpdateExpression:          aws.String(update),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	})
+	return err
+}
+
+func (r *NFSeDynamoRepository) ListByTomadorDocHash(ctx context.Context, hash string) ([]entities.NFSeDocument, error) {
+	return r.listByAttribute(ctx, nfseTomadorDocHashIndex, "tomador_doc_hash", hash)
+}
+
+func (r *NFSeDynamoRepository) ListByTomadorEmailHash(ctx context.Context, hash string) ([]entities.NFSeDocument, error) {
+	return r.listByAttribute(ctx, nfseTomadorEmailHashIndex, "tomador_email_hash", hash)
+}
+
+// listByAttribute queries a single-attribute GSI, following pagination. Local databases
+// created before the index existed fall back to a filtered scan.
+func (r *NFSeDynamoRepository) listByAttribute(ctx context.Context, index, attr, value string) ([]entities.NFSeDocument, error) {
+	names := map[string]string{"#attr": attr}
+	values := map[string]types.AttributeValue{
+		":v": &types.AttributeValueMemberS{Value: value},
+	}
+
+	var docs []entities.NFSeDocument
+	useScan := false
+	var startKey map[string]types.AttributeValue
+	for {
+		var items []map[string]types.AttributeValue
+		var lastKey map[string]types.AttributeValue
+		if !useScan {
+			out, err := r.ddb.Query(ctx, &dynamodb.QueryInput{
+				TableName:                 aws.String(r.tableName),
+				IndexName:                 aws.String(index),
+				KeyConditionExpression:    aws.String("#attr = :v"),
+				ExpressionAttributeNames:  names,
+				ExpressionAttributeValues: values,
+				ExclusiveStartKey:         startKey,
+			})
+			if isIndexNotAvailableError(err) {
+				useScan, startKey = true, nil
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			items, lastKey = out.Items, out.LastEvaluatedKey
+		} else {
+			out, err := r.ddb.Scan(ctx, &dynamodb.ScanInput{
+				TableName:                 aws.String(r.tableName),
+				FilterExpression:          aws.String("#attr = :v"),
+				ExpressionAttributeNames:  names,
+				ExpressionAttributeValues: values,
+				ExclusiveStartKey:         startKey,
+			})
+			if err != nil {
+				return nil, err
+			}
+			items, lastKey = out.Items, out.LastEvaluatedKey
+		}
+		for _, item := range items {
+			var it nfseItem
+			if err := attributevalue.UnmarshalMap(item, &it); err != nil {
+				return nil, err
+			}
+			docs = append(docs, fromNFSeItem(it))
+		}
+		if len(lastKey) == 0 {
+			return docs, nil
+		}
+		startKey = lastKey
+	}
+}
+
+func toNFSeItem(doc entities.NFSeDocument) nfseItem {
+	t := doc.RPS.Tomador
+	tomador := nfseTomadorItem{Document: t.Document, Name: t.Name, Email: t.Email}
+	if t.Address != nil {
+		a := nfseAddressItem(*t.Address)
+		tomador.Address = &a
+	}
+	it := nfseItem{
+		ID:                doc.ID,
+		InvoiceID:         doc.InvoiceID,
+		EstimateID:        doc.EstimateID,
+		RPSNumber:         doc.RPS.Number,
+		RPSSeries:         doc.RPS.Series,
+		RPSIssuedAt:       doc.RPS.IssuedAt.UTC().Format(time.RFC3339Nano),
+		Tomador:           tomador,
+		ServiceDesc:       doc.RPS.ServiceDescription,
+		ServiceAmount:     doc.RPS.ServiceAmount,
+		Status:            string(doc.Status),
+		SignedXML:         doc.SignedXML,
+		Protocol:          doc.Protocol,
+		TransmissionError: doc.TransmissionError,
+		Attempts:          doc.Attempts,
+		CreatedBy:         doc.CreatedBy,
+		CreatedAt:         doc.CreatedAt.UTC().Format(time.RFC3339Nano),
+		TomadorDocHash:    doc.TomadorDocHash,
+		TomadorEmailHash:  doc.TomadorEmailHash,
+	}
+	if doc.DataKey != nil {
+		it.PIIKeyID = doc.DataKey.KeyID
+		it.PIIWrappedKey = doc.DataKey.WrappedKey
+	}
+	if doc.TransmittedAt != nil {
+		it.TransmittedAt = doc.TransmittedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return it
+}
+
+func fromNFSeItem(it nfseItem) entities.NFSeDocument {
+	doc := entities.NFSeDocument{
+		ID:         it.ID,
+		InvoiceID:  it.InvoiceID,
+		EstimateID: it.EstimateID,
+		RPS: entities.NFSeRPS{
+			Number:             it.RPSNumber,
+			Series:             it.RPSSeries,
+			Tomador:            entities.NFSeTomador{Document: it.Tomador.Document, Name: it.Tomador.Name, Email: it.Tomador.Email},
+			ServiceDescription: it.ServiceDesc,
+			ServiceAmount:      it.ServiceAmount,
+		},
+		Status:            entities.NFSeStatus(it.Status),
+		SignedXML:         it.SignedXML,
+		Protocol:          it.Protocol,
+		TransmissionError: it.TransmissionError,
+		Attempts:          it.Attempts,
+		CreatedBy:         it.CreatedBy,
+		TomadorDocHash:    it.TomadorDocHash,
+		TomadorEmailHash:  it.TomadorEmailHash,
+	}
+	if it.PIIKeyID != "" {
+		doc.DataKey = &entities.EncryptedDataKey{KeyID: it.PIIKeyID, WrappedKey: it.PIIWrappedKey}
+	}
+	if it.Tomador.Address != nil {
+		a := entities.NFSeAddress(*it.Tomador.Address)
+		doc.RPS.Tomador.Address = &a
+	}
+	doc.RPS.IssuedAt, _ = time.Parse(time.RFC3339Nano, it.RPSIssuedAt)
+	doc.CreatedAt, _ = time.Parse(time.RFC3339Nano, it.CreatedAt)
+	if it.TransmittedAt != "" {
+		if t, err := time.Parse(time.RFC3339Nano, it.TransmittedAt); err == nil {
+			doc.TransmittedAt = &t
+		}
+	}
+	return doc
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	defaultPaymentStatusEventsTableName = "payment_status_events"
+	paymentStatusEventsStatusIndex      = "status-event_key-index"
+)
+
+type paymentStatusEventItem struct {
+	PaymentID            string `dynamodbav:"payment_id"`
+	EventKey             string `dynamodbav:"event_key"`
+	ID                   string `dynamodbav:"id"`
+	Status               string `dynamodbav:"status"`
+	PreviousStatus       string `dynamodbav:"previous_status,omitempty"`
+	Source               string `dynamodbav:"source"`
+	Actor                string `dynamodbav:"actor,omitempty"`
+	ProviderStatus       string `dynamodbav:"provider_status,omitempty"`
+	ProviderStatusDetail string `dynamodbav:"provider_status_detail,omitempty"`
+	CreatedAt            string `dynamodbav:"created_at"`
+}
+
+// PaymentStatusEventDynamoRepository persists the payment status timeline in DynamoDB.
+//
+// Table requirements:
+//   - PK: payment_id (string)
+//   - SK: event_key (string)
+//   - GSI status-event_key-index: PK status, SK event_key
+
+type PaymentStatusEventDynamoRepository struct {
+	ddb       *dynamodb.Client
+	tableName string
+}
+
+var _ interfaces.IPaymentStatusEventRepository = (*PaymentStatusEventDynamoRepository)(nil)
+
+func NewPaymentStatusEventDynamoRepository(ddb *dynamodb.Client) *PaymentStatusEventDynamoRepository {
+	return &PaymentStatusEventDynamoRepository{
+		ddb:       ddb,
+		tableName: getenvDefault("PAYMENT_STATUS_EVENTS_TABLE", defaultPaymentStatusEventsTableName),
+	}
+}
+
+func (r *PaymentStatusEventDynamoRepository) Append(ctx context.Context, e entities.PaymentStatusEvent) error {
+	put, err := paymentStatusEventPut(r.tableName, e)
+	if err != nil {
+		return err
+	}
+	_, err = r.ddb.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                put.TableName,
+		Item:                     put.Item,
+		ConditionExpression:      put.ConditionExpression,
+		ExpressionAttributeNames: put.ExpressionAttributeNames,
+	})
+	return err
+}
+
+// paymentStatusEventPut is the write of a new event, shared with the payment
+// transactions (BillingPaymentDynamoRepository.postingItems).
+func paymentStatusEventPut(tableName string, e entities.PaymentStatusEvent) (*types.Put, error) {
+	av, err := attributevalue.MarshalMap(toPaymentStatusEventItem(e))
+	if err != nil {
+		return nil, err
+	}
+	return &types.Put{
+		TableName:           aws.String(tableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(#event_key)"),
+		ExpressionAttributeNames: map[string]string{
+			"#event_key": "event_key",
+		},
+	}, nil
+}
+
+// ListByPaymentID returns the timeline of a payment, oldest event first.
+func (r *PaymentStatusEventDynamoRepository) ListByPaymentID(ctx context.Context, paymentID string) ([]entities.PaymentStatusEvent, error) {
+	var events []entities.PaymentStatusEvent
+	var startKey map[string]types.AttributeValue
+	for {
+		out, err := r.ddb.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(r.tableName),
+			KeyConditionExpression: aws.String("#payment_id = :payment_id"),
+			ExpressionAttributeNames: map[string]string{
+				"#payment_id": "payment_id",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":payment_id": &types.AttributeValueMemberS{Value: paymentID},
+			},
+			ScanIndexForward:  aws.Bool(true),
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, raw := range out.Items {
+			var it paymentStatusEventItem
+			if err := attributevalue.UnmarshalMap(raw, &it); err != nil {
+				return nil, err
+			}
+			events = append(events, fromPaymentStatusEventItem(it))
+		}
+		if len(out.LastEvaluatedKey) == 0 {
+			return events, nil
+		}
+		startKey = out.LastEvaluatedKey
+	}
+}
+
+// ListByStatusBetween queries status-event_key-index. event_key starts with the sortable
+// creation time, so the range is a BETWEEN on the key prefix.
+func (r *PaymentStatusEventDynamoRepository) ListByStatusBetween(ctx context.Context, status entities.PaymentStatus, from, to time.Time) ([]entities.PaymentStatusEvent, error) {
+	events := []entities.PaymentStatusEvent{}
+	var startKey map[string]types.AttributeValue
+	for {
+		out, err := r.ddb.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(r.tableName),
+			IndexName:              aws.String(paymentStatusEventsStatusIndex),
+			KeyConditionExpression: aws.String("#status = :status AND #event_key BETWEEN :from AND :to"),
+			ExpressionAttributeNames: map[string]string{
+				"#status":    "status",
+				"#event_key": "event_key",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":status": &types.AttributeValueMemberS{Value: string(status)},
+				":from":   &types.AttributeValueMemberS{Value: from.UTC().Format(sortableTimeLayout)},
+				// "~" sorts after the "#<event id>" suffix of the last key.
+				":to": &types.AttributeValueMemberS{Value: to.UTC().Format(sortableTimeLayout) + "~"},
+			},
+			ScanIndexForward:  aws.Bool(true),
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, raw := range out.Items {
+			var it paymentStatusEventItem
+			if err := attributevalue.UnmarshalMap(raw, &it); err != nil {
+				return nil, err
+			}
+			events = append(events, fromPaymentStatusEventItem(it))
+		}
+		if len(out.LastEvaluatedKey) == 0 {
+			return events, nil
+		}
+		startKey = out.LastEvaluatedKey
+	}
+}
+
+func toPaymentStatusEventItem(e entities.PaymentStatusEvent) paymentStatusEventItem {
+	createdAt := e.CreatedAt.UTC()
+	return paymentStatusEventItem{
+		PaymentID:            e.PaymentID,
+		EventKey:             createdAt.Format(sortableTimeLayout) + "#" + e.ID,
+		ID:                   e.ID,
+		Status:               string(e.Status),
+		PreviousStatus:       string(e.PreviousStatus),
+		Source:               string(e.Source),
+		Actor:                e.Actor,
+		ProviderStatus:       e.ProviderStatus,
+		ProviderStatusDetail: e.ProviderStatusDetail,
+		CreatedAt:            createdAt.Format(time.RFC3339Nano),
+	}
+}
+
+func fromPaymentStatusEventItem(it paymentStatusEventItem) entities.PaymentStatusEvent {
+	createdAt, _ := time.Parse(time.RFC3339Nano, it.CreatedAt)
+	return entities.PaymentStatusEvent{
+		ID:                   it.ID,
+		PaymentID:            it.PaymentID,
+		Status:               entities.PaymentStatus(it.Status),
+		PreviousStatus:       entities.PaymentStatus(it.PreviousStatus),
+		Source:               entities.PaymentEventSource(it.Source),
+		Actor:                it.Actor,
+		ProviderStatus:       it.ProviderStatus,
+		ProviderStatusDetail: it.ProviderStatusDetail,
+		CreatedAt:            createdAt,
+	}
+}
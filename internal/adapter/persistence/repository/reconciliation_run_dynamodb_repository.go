@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	defaultReconciliationRunsTableName          = "reconciliation_runs"
+	defaultReconciliationDiscrepanciesTableName = "reconciliation_discrepancies"
+)
+
+type reconciliationRunItem struct {
+	ID           string         `dynamodbav:"id"`
+	FileName     string         `dynamodbav:"file_name"`
+	Actor        string         `dynamodbav:"actor"`
+	PeriodStart  string         `dynamodbav:"period_start,omitempty"`
+	PeriodEnd    string         `dynamodbav:"period_end,omitempty"`
+	TotalLines   int            `dynamodbav:"total_lines"`
+	MatchedLines int            `dynamodbav:"matched_lines"`
+	IgnoredLines int            `dynamodbav:"ignored_lines"`
+	Summary      map[string]int `dynamodbav:"summary,omitempty"`
+	CreatedAt    string         `dynamodbav:"created_at"`
+}
+
+type reconciliationDiscrepancyItem struct {
+	RunID             string  `dynamodbav:"run_id"`
+	Seq               int     `dynamodbav:"seq"`
+	Kind              string  `dynamodbav:"kind"`
+	Line              int     `dynamodbav:"line,omitempty"`
+	SourceID          string  `dynamodbav:"source_id,omitempty"`
+	ExternalReference string  `dynamodbav:"external_reference,omitempty"`
+	LineType          string  `dynamodbav:"line_type,omitempty"`
+	PaymentID         string  `dynamodbav:"payment_id,omitempty"`
+	ReportAmount      float64 `dynamodbav:"report_amount,omitempty"`
+	PaymentAmount     float64 `dynamodbav:"payment_amount,omitempty"`
+	PaymentStatus     string  `dynamodbav:"payment_status,omitempty"`
+	Detail            string  `dynamodbav:"detail"`
+}
+
+// ReconciliationRunDynamoRepository persists ReconciliationRun entities in DynamoDB.
+//
+// Table requirements:
+//   - runs: PK id (string)
+//   - discrepancies: PK run_id (string), SK seq (number)
+//
+// Discrepancies are child items of the run, one per finding, so a run is not bounded by
+// the DynamoDB item size. seq is the position of the finding in the run: a report line
+// may have several findings and missing_in_report findings have no line. The run item is
+// written last, so a run is only visible once all its discrepancies are stored.
+
+type ReconciliationRunDynamoRepository struct {
+	ddb                *dynamodb.Client
+	tableName          string
+	discrepanciesTable string
+}
+
+var _ interfaces.IReconciliationRunRepository = (*ReconciliationRunDynamoRepository)(nil)
+
+func NewReconciliationRunDynamoRepository(ddb *dynamodb.Client) *ReconciliationRunDynamoRepository {
+	return &ReconciliationRunDynamoRepository{
+		ddb:                ddb,
+		tableName:          getenvDefault("RECONCILIATION_RUNS_TABLE", defaultReconciliationRunsTableName),
+		discrepanciesTable: getenvDefault("RECONCILIATION_DISCREPANCIES_TABLE", defaultReconciliationDiscrepanciesTableName),
+	}
+}
+
+func (r *ReconciliationRunDynamoRepository) Create(ctx context.Context, run entities.ReconciliationRun) error {
+	if err := r.putDiscrepancies(ctx, run.ID, run.Discrepancies); err != nil {
+		return err
+	}
+
+	av, err := attributevalue.MarshalMap(toReconciliationRunItem(run))
+	if err != nil {
+		return err
+	}
+
+	_, err = r.ddb.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(#id)"),
+		ExpressionAttributeNames: map[string]string{
+			"#id": "id",
+		},
+	})
+	return err
+}
+
+func (r *ReconciliationRunDynamoRepository) GetByID(ctx context.Context, id string) (entities.ReconciliationRun, error) {
+	out, err := r.ddb.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return entities.ReconciliationRun{}, err
+	}
+	if len(out.Item) == 0 {
+		return entities.ReconciliationRun{}, nil
+	}
+
+	var it reconciliationRunItem
+	if err := attributevalue.UnmarshalMap(out.Item, &it); err != nil {
+		return entities.ReconciliationRun{}, err
+	}
+	return fromReconciliationRunItem(it), nil
+}
+
+// ForEachDiscrepancy calls fn with the run discrepancies in the order they were found,
+// reading them page by page.
+func (r *ReconciliationRunDynamoRepository) ForEachDiscrepancy(ctx context.Context, runID string, fn func(entities.ReconciliationDiscrepancy) error) error {
+	p := dynamodb.NewQueryPaginator(r.ddb, &dynamodb.QueryInput{
+		TableName:              aws.String(r.discrepanciesTable),
+		KeyConditionExpression: aws.String("#run_id = :run_id"),
+		ExpressionAttributeNames: map[string]string{
+			"#run_id": "run_id",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":run_id": &types.AttributeValueMemberS{Value: runID},
+		},
+	})
+	for p.HasMorePages() {
+		out, err := p.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		var items []reconciliationDiscrepancyItem
+		if err := attributevalue.UnmarshalListOfMaps(out.Items, &items); err != nil {
+			return err
+		}
+		for _, it := range items {
+			if err := fn(fromReconciliationDiscrepancyItem(it)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *ReconciliationRunDynamoRepository) putDiscrepancies(ctx context.Context, runID string, ds []entities.ReconciliationDiscrepancy) error {
+	items := make([]map[string]types.AttributeValue, 0, len(ds))
+	for i, d := range ds {
+		av, err := attributevalue.MarshalMap(toReconciliationDiscrepancyItem(runID, i+1, d))
+		if err != nil {
+			return err
+		}
+		items = append(items, av)
+	}
+	return batchPut(ctx, r.ddb, r.discrepanciesTable, items)
+}
+
+func toReconciliationRunItem(run entities.ReconciliationRun) reconciliationRunItem {
+	it := reconciliationRunItem{
+		ID:           run.ID,
+		FileName:     run.FileName,
+		Actor:        run.Actor,
+		TotalLines:   run.TotalLines,
+		MatchedLines: run.MatchedLines,
+		IgnoredLines: run.IgnoredLines,
+		Summary:      map[string]int{},
+		CreatedAt:    run.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	for kind, n := range run.CountByKind() {
+		it.Summary[string(kind)] = n
+	}
+	if run.PeriodStart != nil {
+		it.PeriodStart = run.PeriodStart.UTC().Format(time.RFC3339Nano)
+	}
+	if run.PeriodEnd != nil {
+		it.PeriodEnd = run.PeriodEnd.UTC().Format(time.RFC3339Nano)
+	}
+	return it
+}
+
+func fromReconciliationRunItem(it reconciliationRunItem) entities.ReconciliationRun {
+	createdAt, _ := time.Parse(time.RFC3339Nano, it.CreatedAt)
+	run := entities.ReconciliationRun{
+		ID:           it.ID,
+		FileName:     it.FileName,
+		Actor:        it.Actor,
+		TotalLines:   it.TotalLines,
+		MatchedLines: it.MatchedLines,
+		IgnoredLines: it.IgnoredLines,
+		Summary:      map[entities.DiscrepancyKind]int{},
+		CreatedAt:    createdAt,
+	}
+	for kind, n := range it.Summary {
+		run.Summary[entities.DiscrepancyKind(kind)] = n
+	}
+	if t, err := time.Parse(time.RFC3339Nano, it.PeriodStart); err == nil {
+		run.PeriodStart = &t
+	}
+	if t, err := time.Parse(time.RFC3339Nano, it.PeriodEnd); err == nil {
+		run.PeriodEnd = &t
+	}
+	return run
+}
+
+func toReconciliationDiscrepancyItem(runID string, seq int, d entities.ReconciliationDiscrepancy) reconciliationDiscrepancyItem {
+	return reconciliationDiscrepancyItem{
+		RunID:             runID,
+		Seq:               seq,
+		Kind:              string(d.Kind),
+		Line:              d.Line,
+		SourceID:          d.SourceID,
+		ExternalReference: d.ExternalReference,
+		LineType:          string(d.LineType),
+		PaymentID:         d.PaymentID,
+		ReportAmount:      d.ReportAmount,
+		PaymentAmount:     d.PaymentAmount,
+		PaymentStatus:     string(d.PaymentStatus),
+		Detail:            d.Detail,
+	}
+}
+
+func fromReconciliationDiscrepancyItem(d reconciliationDiscrepancyItem) entities.ReconciliationDiscrepancy {
+	return entities.ReconciliationDiscrepancy{
+		Kind:              entities.DiscrepancyKind(d.Kind),
+		Line:              d.Line,
+		SourceID:          d.SourceID,
+		ExternalReference: d.ExternalReference,
+		LineType:          entities.SettlementLineType(d.LineType),
+		PaymentID:         d.PaymentID,
+		ReportAmount:      d.ReportAmount,
+		PaymentAmount:     d.PaymentAmount,
+		PaymentStatus:     entities.PaymentStatus(d.PaymentStatus),
+		Detail:            d.Detail,
+	}
+}
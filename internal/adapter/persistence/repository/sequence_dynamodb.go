@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	defaultSequencesTableName = "sequences"
+
+	// maxSequenceAttempts bounds the retries when another writer took the next number.
+	maxSequenceAttempts = 5
+)
+
+var errSequenceContention = errors.New("sequence contention")
+
+// sequenceTable holds the gapless document counters (invoice, credit note, RPS).
+//
+// Table requirements:
+//   - PK id (string: the sequence name), attribute seq (number).
+//
+// A number is taken by moving seq from n to n+1 in the same transaction that stores the
+// document, so a failed write leaves no gap.
+
+type sequenceTable struct {
+	ddb       *dynamodb.Client
+	tableName string
+}
+
+func newSequenceTable(ddb *dynamodb.Client) sequenceTable {
+	return sequenceTable{ddb: ddb, tableName: getenvDefault("SEQUENCES_TABLE", defaultSequencesTableName)}
+}
+
+// current returns the last number taken from the sequence (0 when none was).
+func (s sequenceTable) current(ctx context.Context, name string) (int64, error) {
+	out, err := s.ddb.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: name},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return 0, err
+	}
+	var it struct {
+		Seq int64 `dynamodbav:"seq"`
+	}
+	if err := attributevalue.UnmarshalMap(out.Item, &it); err != nil {
+		return 0, err
+	}
+	return it.Seq, nil
+}
+
+// update moves the sequence from current to current+1, failing if another writer moved
+// it first.
+func (s sequenceTable) update(name string, current int64) types.TransactWriteItem {
+	condition := "#seq = :current"
+	values := map[string]types.AttributeValue{
+		":next":    &types.AttributeValueMemberN{Value: strconv.FormatInt(current+1, 10)},
+		":current": &types.AttributeValueMemberN{Value: strconv.FormatInt(current, 10)},
+	}
+	if current == 0 {
+		condition = "attribute_not_exists(#seq)"
+		delete(values, ":current")
+	}
+	return types.TransactWriteItem{
+		Update: &types.Update{
+			TableName: aws.String(s.tableName),
+			Key: map[string]types.AttributeValue{
+				"id": &types.AttributeValueMemberS{Value: name},
+			},
+			ConditionExpression: aws.String(condition),
+			UpdateExpression:    aws.String("SET #seq = :next"),
+			ExpressionAttributeNames: map[string]string{
+				"#seq": "seq",
+			},
+			ExpressionAttributeValues: values,
+		},
+	}
+}
+
+// canceledItems reports, per transaction item, whether its condition failed.
+func canceledItems(err error) map[int]bool {
+	failed := map[int]bool{}
+	var canceled *types.TransactionCanceledException
+	if !errors.As(err, &canceled) {
+		return failed
+	}
+	for i, reason := range canceled.CancellationReasons {
+		if aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+			failed[i] = true
+		}
+	}
+	return failed
+}
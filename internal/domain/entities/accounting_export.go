@@ -0,0 +1,110 @@
+package entities
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrAccountingWatermarkMoved is returned when another export advanced the watermark first.
+var ErrAccountingWatermarkMoved = errors.New("accounting export watermark moved")
+
+// AccountingExportFormat is the file layout of an accounting export.
+type AccountingExportFormat string
+
+const (
+	AccountingExportCSV        AccountingExportFormat = "csv"
+	AccountingExportOFX        AccountingExportFormat = "ofx"
+	AccountingExportFixedWidth AccountingExportFormat = "fixed-width"
+)
+
+// JournalEntryKind is the business event behind a journal entry.
+type JournalEntryKind string
+
+const (
+	JournalEntryPayment JournalEntryKind = "payment"
+	JournalEntryFee     JournalEntryKind = "fee"
+	JournalEntryRefund  JournalEntryKind = "refund"
+)
+
+// ChartOfAccounts maps the journal entries to the accountant's chart of accounts.
+//
+// Notes:
+//   - Bank is the provider balance account; BankByPaymentMethod overrides it per
+//     payment_method_id (e.g. a separate clearing account for boleto).
+//   - Revenue is credited by payments, Refunds and Fees are debited by refunds and
+//     provider fees.
+type ChartOfAccounts struct {
+	Bank                string            `json:"bank"`
+	BankByPaymentMethod map[string]string `json:"bank_by_payment_method,omitempty"`
+	Revenue             string            `json:"revenue"`
+	Refunds             string            `json:"refunds"`
+	Fees                string            `json:"fees"`
+}
+
+// DefaultChartOfAccounts is used when no accounting config is provided.
+var DefaultChartOfAccounts = ChartOfAccounts{
+	Bank:    "1.1.1.02",
+	Revenue: "3.1.1.01",
+	Refunds: "3.2.1.01",
+	Fees:    "4.1.2.05",
+}
+
+// BankFor returns the bank account of a payment method.
+func (c ChartOfAccounts) BankFor(paymentMethodID string) string {
+	if acc := c.BankByPaymentMethod[paymentMethodID]; acc != "" {
+		return acc
+	}
+	return c.Bank
+}
+
+// JournalEntry is a double-entry line exported to the accountant's ERP.
+//
+// ID is derived from the payment status event and the kind, so the same event always
+// yields the same entry IDs. Amount is always positive.
+type JournalEntry struct {
+	ID              string           `json:"id"`
+	Kind            JournalEntryKind `json:"kind"`
+	Date            time.Time        `json:"date"`
+	DebitAccount    string           `json:"debit_account"`
+	CreditAccount   string           `json:"credit_account"`
+	Amount          float64          `json:"amount"`
+	PaymentID       string           `json:"payment_id"`
+	EstimateID      string           `json:"estimate_id"`
+	PaymentMethodID string           `json:"payment_method_id,omitempty"`
+	Description     string           `json:"description"`
+}
+
+// AccountingExport is an export run. It covers the payment status events created in
+// (From, To]; To becomes the watermark of the next run.
+//
+// BlockedByPaymentID is set when a payment without amount stopped the run short: To is
+// then just before that payment's event, which the next run exports once the amount is
+// backfilled.
+type AccountingExport struct {
+	ID                 string                 `json:"id"`
+	Format             AccountingExportFormat `json:"format"`
+	From               time.Time              `json:"from"`
+	To                 time.Time              `json:"to"`
+	EntryCount         int                    `json:"entry_count"`
+	BlockedByPaymentID string                 `json:"blocked_by_payment_id,omitempty"`
+	Actor              string                 `json:"actor,omitempty"`
+	CreatedAt          time.Time              `json:"created_at"`
+}
+
+// FixedWidthField is a column of the fixed-width layout.
+//
+// Name is one of: date, entry_id, kind, debit_account, credit_account, amount (in
+// cents), payment_id, estimate_id, payment_method_id, description or a literal
+// "filler". Align is "left" (default) or "right"; Pad defaults to a space.
+type FixedWidthField struct {
+	Name  string `json:"name"`
+	Width int    `json:"width"`
+	Align string `json:"align,omitempty"`
+	Pad   string `json:"pad,omitempty"`
+}
+
+// FixedWidthLayout describes the fixed-width export. DateFormat is a Go time layout.
+type FixedWidthLayout struct {
+	DateFormat string            `json:"date_format"`
+	Fields     []FixedWidthField `json:"fields"`
+}
@@ -0,0 +1,98 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrApprovalLinkChanged = errors.New("approval link changed concurrently")
+
+// NotificationChannel is how a customer is reached: an e-mail address or a phone number
+// (SMS).
+type NotificationChannel string
+
+const (
+	NotificationChannelEmail NotificationChannel = "email"
+	NotificationChannelSMS   NotificationChannel = "sms"
+)
+
+// Notification is a message to a customer, delivered by the configured notifier.
+type Notification struct {
+	Channel   NotificationChannel `json:"channel"`
+	Recipient string              `json:"recipient"`
+	Subject   string              `json:"subject"`
+	Body      string              `json:"body"`
+}
+
+// DecisionEvidence is what proves a customer decided an estimate through an approval
+// link: the link, and the IP and user agent of the request. The time is the one of the
+// status change it belongs to.
+type DecisionEvidence struct {
+	LinkID    string `json:"link_id"`
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+	// Recipient is where the OTP confirming the decision was sent, masked.
+	Recipient string `json:"recipient"`
+}
+
+// ApprovalLink lets a customer view an estimate and approve or reject it without going
+// through the OS service. The link URL carries a signed token with ID and ExpiresAt; the
+// decision needs an OTP sent to Recipient, of which only a digest is kept.
+//
+// Storage model (DynamoDB):
+//   - PK: id
+//   - TTL: ttl, some time after ExpiresAt (the evidence stays on the estimate history)
+//   - GSI: recipient_hash-index (PK: recipient_hash), for data subject requests
+//
+// A link is used once: UsedAt, Decision and Evidence record the decision made with it.
+// Recipient and the evidence IP/user agent are sealed at rest with DataKey; AnonymizedAt
+// is set when they were erased (LGPD request), which also disables the link.
+type ApprovalLink struct {
+	ID             string              `json:"id"`
+	EstimateID     string              `json:"estimate_id"`
+	OSID           string              `json:"os_id"`
+	Channel        NotificationChannel `json:"channel"`
+	Recipient      string              `json:"recipient"`
+	CreatedBy      string              `json:"created_by,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+	ExpiresAt      time.Time           `json:"expires_at"`
+	OTPDigest      string              `json:"-"`
+	OTPExpiresAt   *time.Time          `json:"otp_expires_at,omitempty"`
+	OTPSends       int                 `json:"otp_sends"`
+	FailedAttempts int                 `json:"failed_attempts"`
+	UsedAt         *time.Time          `json:"used_at,omitempty"`
+	Decision       EstimateStatus      `json:"decision,omitempty"`
+	Evidence       *DecisionEvidence   `json:"evidence,omitempty"`
+	AnonymizedAt   *time.Time          `json:"anonymized_at,omitempty"`
+	DataKey        *EncryptedDataKey   `json:"-"`
+	RecipientHash  string              `json:"-"`
+}
+
+// IsExpired tells whether the link can no longer be used at the given time.
+func (l ApprovalLink) IsExpired(at time.Time) bool {
+	return !at.Before(l.ExpiresAt)
+}
+
+// HasValidOTP tells whether an OTP was sent and can still be confirmed at the given time.
+func (l ApprovalLink) HasValidOTP(at time.Time) bool {
+	return l.OTPDigest != "" && l.OTPExpiresAt != nil && at.Before(*l.OTPExpiresAt)
+}
+
+// MaskedRecipient shows only enough of the recipient for the customer to recognize it:
+// the first letter and the domain of an e-mail, the last 4 digits of a phone.
+func (l ApprovalLink) MaskedRecipient() string {
+	r := strings.TrimSpace(l.Recipient)
+	if l.Channel == NotificationChannelEmail {
+		at := strings.LastIndex(r, "@")
+		if at <= 0 {
+			return "***"
+		}
+		return r[:1] + "***" + r[at:]
+	}
+	if len(r) <= 4 {
+		return "***"
+	}
+	return strings.Repeat("*", len(r)-4) + r[len(r)-4:]
+}
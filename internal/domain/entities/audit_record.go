@@ -0,0 +1,26 @@
+package entities
+
+import "time"
+
+// AuditAction identifies a privileged operation recorded in the audit log.
+type AuditAction string
+
+const (
+	AuditActionDataSubjectExport    AuditAction = "data_subject.export"
+	AuditActionDataSubjectAnonymize AuditAction = "data_subject.anonymize"
+)
+
+// AuditRecord is an append-only trace of a privileged operation.
+//
+// Storage model (DynamoDB):
+//   - PK: id
+//
+// Subject holds a non-reversible reference (e.g. blind index hash), never raw personal data.
+type AuditRecord struct {
+	ID        string            `json:"id"`
+	Action    AuditAction       `json:"action"`
+	Actor     string            `json:"actor"`
+	Subject   string            `json:"subject"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
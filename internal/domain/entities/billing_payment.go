@@ -2,32 +2,98 @@ package entities
 
 import (
 	"encoding/json"
+	"errors"
 	"time"
 )
 
+var (
+	// ErrUnsupportedPaymentMethod is returned for a payment_method_id the provider payload
+	// schemas do not know.
+	ErrUnsupportedPaymentMethod = errors.New("unsupported payment method")
+	// ErrPaymentStatusChanged is returned by a conditional status write when the payment
+	// is no longer in the status it was read in.
+	ErrPaymentStatusChanged = errors.New("payment status changed")
+	// ErrPaymentAlreadyExists is returned when creating a payment whose ID is taken.
+	ErrPaymentAlreadyExists = errors.New("payment already exists")
+)
+
 // PaymentStatus represents the payment processing outcome.
 //
 // In the requested scope we only need to create/process and persist an approved payment.
-// The type supports a denied status for completeness.
+// The type supports a denied status for completeness; refunds and chargebacks happen after
+// approval and are tracked in the payment status history. A payment is "contestado" while
+// a chargeback dispute is open and "estornado" once the dispute is lost.
 
 type PaymentStatus string
 
 const (
-	PaymentStatusPendente PaymentStatus = "pendente"
-	PaymentStatusAprovado PaymentStatus = "aprovado"
-	PaymentStatusNegado   PaymentStatus = "negado"
+	PaymentStatusPendente    PaymentStatus = "pendente"
+	PaymentStatusAprovado    PaymentStatus = "aprovado"
+	PaymentStatusNegado      PaymentStatus = "negado"
+	PaymentStatusReembolsado PaymentStatus = "reembolsado"
+	PaymentStatusContestado  PaymentStatus = "contestado"
+	PaymentStatusEstornado   PaymentStatus = "estornado"
 )
 
+// IsValid reports whether s is one of the known payment statuses.
+func (s PaymentStatus) IsValid() bool {
+	switch s {
+	case PaymentStatusPendente, PaymentStatusAprovado, PaymentStatusNegado, PaymentStatusReembolsado, PaymentStatusContestado, PaymentStatusEstornado:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo reports whether a payment may move from s to next. A pending payment is
+// decided once; an approved one may only be refunded or contested, and a contested one
+// is reversed (dispute lost) or approved again (dispute won). Denied, refunded and
+// reversed payments are final.
+func (s PaymentStatus) CanTransitionTo(next PaymentStatus) bool {
+	switch s {
+	case PaymentStatusPendente:
+		return next == PaymentStatusAprovado || next == PaymentStatusNegado
+	case PaymentStatusAprovado:
+		return next == PaymentStatusReembolsado || next == PaymentStatusContestado
+	case PaymentStatusContestado:
+		return next == PaymentStatusEstornado || next == PaymentStatusAprovado
+	}
+	return false
+}
+
+// PaymentStatusFromProvider maps a Mercado Pago payment status to the domain status.
+// Unknown values return an empty status.
+func PaymentStatusFromProvider(providerStatus string) PaymentStatus {
+	switch providerStatus {
+	case "pending", "in_process", "authorized":
+		return PaymentStatusPendente
+	case "approved":
+		return PaymentStatusAprovado
+	case "rejected", "cancelled":
+		return PaymentStatusNegado
+	case "refunded":
+		return PaymentStatusReembolsado
+	case "charged_back", "in_mediation":
+		return PaymentStatusContestado
+	}
+	return ""
+}
+
 // BillingPayment is the payment entity persisted by the billing-service.
 //
 // Storage model (DynamoDB):
 //   - PK: id
-//   - GSI1 (estimate_id-index): estimate_id
+//   - GSI estimate_id-date-index: estimate_id, date (sortable date layout)
+//   - GSI status-date-index: status, date
+//   - GSIs payer_email_hash-index / payer_doc_hash-index: blind indexes of the payer
 //
 // MercadoPago payload:
 //   - MPPayloadRaw keeps the original body (JSON) for traceability/audit.
 //   - MPPayload is an optional parsed representation, useful for querying/debugging.
 //     (We persist both because different MP integrations may vary in schema.)
+//   - Details holds the typed fields extracted from the provider response, so
+//     amounts, fees and payment method can be read without parsing the payload.
+//   - Payer personal data inside the payloads/details may be encrypted at rest with
+//     a per-payment data key (DataKey); see SensitivePayloadPaths.
 
 type BillingPayment struct {
 	ID         string        `json:"id"`
@@ -35,6 +101,58 @@ type BillingPayment struct {
 	Date       time.Time     `json:"date"`
 	Status     PaymentStatus `json:"status"`
 
+	Details PaymentDetails `json:"details"`
+
 	MPPayloadRaw json.RawMessage        `json:"mp_payload_raw,omitempty"`
 	MPPayload    map[string]interface{} `json:"mp_payload,omitempty"`
+
+	DataKey *EncryptedDataKey `json:"data_key,omitempty"`
+
+	// AnonymizedAt is set when the payer personal data was erased (LGPD request).
+	AnonymizedAt *time.Time `json:"anonymized_at,omitempty"`
+}
+
+// PaymentFee is a single fee charged by the provider on a payment.
+type PaymentFee struct {
+	Type   string  `json:"type"`
+	Payer  string  `json:"payer"`
+	Amount float64 `json:"amount"`
+}
+
+// PaymentDetails are the structured fields extracted from the provider payload.
+//
+// Amounts use the same float representation as Estimate.Price.
+// Card fields are empty for non-card payments (pix, boleto).
+type PaymentDetails struct {
+	Amount            float64      `json:"amount"`
+	NetReceivedAmount float64      `json:"net_received_amount"`
+	Fees              []PaymentFee `json:"fees,omitempty"`
+	PaymentMethodID   string       `json:"payment_method_id"`
+	PaymentTypeID     string       `json:"payment_type_id"`
+	Installments      int          `json:"installments"`
+	CardLastFour      string       `json:"card_last_four,omitempty"`
+	CardBrand         string       `json:"card_brand,omitempty"`
+	PayerEmail        string       `json:"payer_email,omitempty"`
+	PayerDocType      string       `json:"payer_doc_type,omitempty"`
+	PayerDocNumber    string       `json:"payer_doc_number,omitempty"`
+	ApprovedAt        *time.Time   `json:"approved_at,omitempty"`
+
+	// Blind indexes (keyed hashes) of the normalized payer email/document, used to find
+	// a data subject's payments without storing or querying the plaintext values.
+	PayerEmailHash string `json:"-"`
+	PayerDocHash   string `json:"-"`
+}
+
+// TotalFees sums every fee charged on the payment.
+func (d PaymentDetails) TotalFees() float64 {
+	total := 0.0
+	for _, f := range d.Fees {
+		total += f.Amount
+	}
+	return total
+}
+
+// IsZero reports whether no details were extracted (e.g. rows created before extraction existed).
+func (d PaymentDetails) IsZero() bool {
+	return d.Amount == 0 && d.PaymentMethodID == "" && d.PaymentTypeID == "" && d.ApprovedAt == nil
 }
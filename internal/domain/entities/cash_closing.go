@@ -0,0 +1,68 @@
+package entities
+
+import "time"
+
+// PaymentProviderMercadoPago is the provider of every payment created by the service.
+// Payments carry no provider attribute while Mercado Pago is the only gateway.
+const PaymentProviderMercadoPago = "mercadopago"
+
+// CashClosingStatuses are the payment statuses reported in the daily cash closing.
+var CashClosingStatuses = []PaymentStatus{PaymentStatusAprovado, PaymentStatusReembolsado, PaymentStatusPendente}
+
+// CashClosingTotals accumulates payment amounts.
+type CashClosingTotals struct {
+	Count int     `json:"count"`
+	Gross float64 `json:"gross"`
+	Fees  float64 `json:"fees"`
+	Net   float64 `json:"net"`
+}
+
+// Add accumulates a payment. Net falls back to gross minus fees for providers (or mock
+// payments) that do not report the net received amount.
+func (t *CashClosingTotals) Add(p BillingPayment) {
+	fees := p.Details.TotalFees()
+	net := p.Details.NetReceivedAmount
+	if net == 0 {
+		net = p.Details.Amount - fees
+	}
+	t.Count++
+	t.Gross += p.Details.Amount
+	t.Fees += fees
+	t.Net += net
+}
+
+// CashClosingStatusLine totals the payments of one status.
+type CashClosingStatusLine struct {
+	Status PaymentStatus `json:"status"`
+	CashClosingTotals
+}
+
+// CashClosingMethodLine totals the payments of one status, provider and payment method.
+type CashClosingMethodLine struct {
+	Status          PaymentStatus `json:"status"`
+	Provider        string        `json:"provider"`
+	PaymentMethodID string        `json:"payment_method_id"`
+	PaymentTypeID   string        `json:"payment_type_id"`
+	CashClosingTotals
+}
+
+// CashClosingEstimateLine totals the payments of one status for an estimate.
+type CashClosingEstimateLine struct {
+	Status     PaymentStatus `json:"status"`
+	EstimateID string        `json:"estimate_id"`
+	PaymentIDs []string      `json:"payment_ids"`
+	CashClosingTotals
+}
+
+// CashClosingReport aggregates the payments that entered each status during a business
+// day ([From, To) in TimeZone), by the date of the status change.
+type CashClosingReport struct {
+	BusinessDay string                    `json:"business_day"`
+	TimeZone    string                    `json:"time_zone"`
+	From        time.Time                 `json:"from"`
+	To          time.Time                 `json:"to"`
+	ByStatus    []CashClosingStatusLine   `json:"by_status"`
+	ByMethod    []CashClosingMethodLine   `json:"by_method"`
+	ByEstimate  []CashClosingEstimateLine `json:"by_estimate"`
+	GeneratedAt time.Time                 `json:"generated_at"`
+}
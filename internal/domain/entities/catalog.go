@@ -0,0 +1,95 @@
+package entities
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidCatalogItem = errors.New("invalid catalog item")
+	// ErrCatalogDeviation is returned when, validating in reject mode, an estimate item is
+	// not in the catalog or its price deviates beyond the tolerance.
+	ErrCatalogDeviation = errors.New("estimate item deviates from the catalog")
+)
+
+// CatalogItem is a service (code with its labor price) or a part (SKU with its list price).
+// Version is the catalog version of its last change.
+type CatalogItem struct {
+	Kind      EstimateItemKind `json:"kind"`
+	Code      string           `json:"code"`
+	Name      string           `json:"name"`
+	Price     float64          `json:"price"`
+	Version   int64            `json:"version"`
+	UpdatedBy string           `json:"updated_by,omitempty"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+func NormalizeCatalogCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func (i CatalogItem) IsValid() bool {
+	return (i.Kind == EstimateItemServico || i.Kind == EstimateItemPeca) &&
+		i.Code != "" && strings.TrimSpace(i.Name) != "" && i.Price > 0
+}
+
+// CatalogMode tells what CalculateEstimate does with items deviating from the catalog.
+type CatalogMode string
+
+const (
+	CatalogModeOff    CatalogMode = "off"
+	CatalogModeFlag   CatalogMode = "flag"
+	CatalogModeReject CatalogMode = "reject"
+)
+
+// CatalogPolicy is how estimate items are checked: a unit price may deviate from the list
+// price by up to Tolerance percent.
+type CatalogPolicy struct {
+	Mode      CatalogMode
+	Tolerance float64
+}
+
+// CatalogDeviationReason says why an estimate item was flagged.
+type CatalogDeviationReason string
+
+const (
+	CatalogNoCode         CatalogDeviationReason = "sem_codigo"
+	CatalogNotFound       CatalogDeviationReason = "fora_do_catalogo"
+	CatalogPriceDeviation CatalogDeviationReason = "preco_divergente"
+)
+
+// CatalogDeviation is an estimate item that does not match the catalog. Deviation is the
+// difference from the list price in percent (negative when below it).
+type CatalogDeviation struct {
+	Code      string                 `json:"code,omitempty"`
+	Name      string                 `json:"name"`
+	Reason    CatalogDeviationReason `json:"reason"`
+	UnitPrice float64                `json:"unit_price"`
+	ListPrice float64                `json:"list_price,omitempty"`
+	Deviation float64                `json:"deviation,omitempty"`
+}
+
+// Check compares an estimate item with its catalog entry (found is false when there is
+// none) and returns the deviation, if any. Only the code of labor lines is checked.
+func (p CatalogPolicy) Check(item EstimateItem, entry CatalogItem, found bool) (CatalogDeviation, bool) {
+	d := CatalogDeviation{Code: item.Code, Name: item.Name, UnitPrice: item.UnitPrice}
+	switch {
+	case item.Code == "":
+		d.Reason = CatalogNoCode
+	case !found || entry.Kind != item.Kind:
+		d.Reason = CatalogNotFound
+	case item.IsLabor():
+		// Priced by the hourly rates, not the list price.
+		return CatalogDeviation{}, false
+	default:
+		d.ListPrice = entry.Price
+		d.Deviation = math.Round((item.UnitPrice-entry.Price)/entry.Price*10000) / 100
+		if math.Abs(d.Deviation) <= p.Tolerance {
+			return CatalogDeviation{}, false
+		}
+		d.Reason = CatalogPriceDeviation
+	}
+	return d, true
+}
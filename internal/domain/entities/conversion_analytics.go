@@ -0,0 +1,153 @@
+package entities
+
+import "time"
+
+// EstimateConversion is the analytics read model of an estimate: the milestones of the
+// estimate-to-payment funnel, projected as estimates and payments change.
+//
+// Notes:
+//   - DecidedAt is when the estimate left pendente (approved, rejected, canceled or expired).
+//   - FirstPaidAt is when the first payment of the estimate was approved; later payments
+//     do not move it.
+//   - ReasonCode and ReasonLabel are the reason of a rejected or canceled estimate.
+type EstimateConversion struct {
+	EstimateID  string         `json:"estimate_id"`
+	OSID        string         `json:"os_id"`
+	Price       float64        `json:"price"`
+	Status      EstimateStatus `json:"status"`
+	CreatedAt   time.Time      `json:"created_at"`
+	DecidedAt   *time.Time     `json:"decided_at,omitempty"`
+	ApprovedAt  *time.Time     `json:"approved_at,omitempty"`
+	FirstPaidAt *time.Time     `json:"first_paid_at,omitempty"`
+	ReasonCode  string         `json:"reason_code,omitempty"`
+	ReasonLabel string         `json:"reason_label,omitempty"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// NewEstimateConversion projects the current state of an estimate. FirstPaidAt is left
+// empty: it is recorded from payments.
+func NewEstimateConversion(e Estimate) EstimateConversion {
+	c := EstimateConversion{
+		EstimateID: e.ID,
+		OSID:       e.OSID,
+		Price:      e.Price,
+		Status:     e.Status,
+		CreatedAt:  e.CreatedAt,
+		UpdatedAt:  e.UpdatedAt,
+	}
+	if e.Status != EstimateStatusPendente && e.Status != EstimateStatusAguardandoAprovacao {
+		decidedAt := e.UpdatedAt
+		if e.Status == EstimateStatusAprovado {
+			decidedAt = e.ApprovalTime()
+			c.ApprovedAt = &decidedAt
+		}
+		c.DecidedAt = &decidedAt
+	}
+	if e.Reason != nil && (e.Status == EstimateStatusRejeitado || e.Status == EstimateStatusCancelado) {
+		c.ReasonCode, c.ReasonLabel = e.Reason.Code, e.Reason.Label
+	}
+	return c
+}
+
+// AnalyticsGranularity groups analytics periods.
+type AnalyticsGranularity string
+
+const (
+	AnalyticsGranularityDay   AnalyticsGranularity = "day"
+	AnalyticsGranularityWeek  AnalyticsGranularity = "week"
+	AnalyticsGranularityMonth AnalyticsGranularity = "month"
+)
+
+func (g AnalyticsGranularity) IsValid() bool {
+	switch g {
+	case AnalyticsGranularityDay, AnalyticsGranularityWeek, AnalyticsGranularityMonth:
+		return true
+	default:
+		return false
+	}
+}
+
+// PeriodStart returns the start of the period containing t in t's location.
+// Weeks start on Monday.
+func (g AnalyticsGranularity) PeriodStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch g {
+	case AnalyticsGranularityWeek:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case AnalyticsGranularityMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	default:
+		return day
+	}
+}
+
+// Next returns the start of the period following the one starting at start.
+func (g AnalyticsGranularity) Next(start time.Time) time.Time {
+	switch g {
+	case AnalyticsGranularityWeek:
+		return start.AddDate(0, 0, 7)
+	case AnalyticsGranularityMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// ConversionMetrics are the funnel metrics of the estimates created in a period.
+//
+// Notes:
+//   - ApprovalRate is Approved / Estimates (0 when no estimate was created).
+//   - AverageTicket is the mean price of the approved estimates.
+//   - AvgHoursToApproval and AvgHoursApprovalToPayment only consider the estimates that
+//     reached both milestones.
+type ConversionMetrics struct {
+	PeriodStart               time.Time `json:"period_start"`
+	Estimates                 int       `json:"estimates"`
+	Approved                  int       `json:"approved"`
+	Rejected                  int       `json:"rejected"`
+	Canceled                  int       `json:"canceled"`
+	Expired                   int       `json:"expired"`
+	Paid                      int       `json:"paid"`
+	ApprovalRate              float64   `json:"approval_rate"`
+	AverageTicket             float64   `json:"average_ticket"`
+	AvgHoursToApproval        float64   `json:"avg_hours_to_approval"`
+	AvgHoursApprovalToPayment float64   `json:"avg_hours_approval_to_payment"`
+}
+
+// ReasonMetrics are the rejected or canceled estimates of a reason: how many, their share
+// of the estimates in Status and their total price. Code is empty for the estimates
+// decided without a reason.
+type ReasonMetrics struct {
+	Status EstimateStatus `json:"status"`
+	Code   string         `json:"code"`
+	Label  string         `json:"label"`
+	Count  int            `json:"count"`
+	Share  float64        `json:"share"`
+	Value  float64        `json:"value"`
+}
+
+// RejectionReasonReport tells why the estimates created between From and To (business
+// days in TimeZone, both inclusive) were rejected or canceled, most frequent first.
+type RejectionReasonReport struct {
+	From        string          `json:"from"`
+	To          string          `json:"to"`
+	TimeZone    string          `json:"time_zone"`
+	Estimates   int             `json:"estimates"`
+	Rejected    int             `json:"rejected"`
+	Canceled    int             `json:"canceled"`
+	Reasons     []ReasonMetrics `json:"reasons"`
+	GeneratedAt time.Time       `json:"generated_at"`
+}
+
+// ConversionReport groups the conversion metrics of the estimates created between From
+// and To (business days in TimeZone, both inclusive).
+type ConversionReport struct {
+	From        string               `json:"from"`
+	To          string               `json:"to"`
+	TimeZone    string               `json:"time_zone"`
+	Granularity AnalyticsGranularity `json:"granularity"`
+	Periods     []ConversionMetrics  `json:"periods"`
+	Total       ConversionMetrics    `json:"total"`
+	GeneratedAt time.Time            `json:"generated_at"`
+}
@@ -0,0 +1,238 @@
+package entities
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+)
+
+var (
+	ErrCouponAlreadyExists = errors.New("coupon already exists")
+	// ErrCouponUnavailable is returned when a redemption finds the coupon inactive,
+	// expired or out of redemptions.
+	ErrCouponUnavailable = errors.New("coupon unavailable")
+	// ErrCouponCustomerLimit is returned when the customer already used the coupon the
+	// allowed number of times.
+	ErrCouponCustomerLimit = errors.New("coupon customer limit reached")
+	// ErrEstimateChanged is returned when an estimate being repriced or decided changed
+	// concurrently.
+	ErrEstimateChanged = errors.New("estimate changed concurrently")
+)
+
+// DiscountType is how a discount value is read: a percentage or an amount in reais.
+type DiscountType string
+
+const (
+	DiscountPercentual DiscountType = "percentual"
+	DiscountFixo       DiscountType = "fixo"
+)
+
+// DiscountScope tells whether a discount applies to each matching line or to the whole
+// estimate.
+type DiscountScope string
+
+const (
+	DiscountScopeOrcamento DiscountScope = "orcamento"
+	DiscountScopeItem      DiscountScope = "item"
+)
+
+type DiscountSource string
+
+const (
+	DiscountSourceCupom  DiscountSource = "cupom"
+	DiscountSourceManual DiscountSource = "manual"
+)
+
+// DiscountRule is the part shared by coupons and manual discounts. Item-scoped rules apply
+// to the lines of ItemKind, or to every line when it is empty; a fixed item discount is
+// taken from each matching line.
+type DiscountRule struct {
+	Type     DiscountType     `json:"type"`
+	Value    float64          `json:"value"`
+	Scope    DiscountScope    `json:"scope"`
+	ItemKind EstimateItemKind `json:"item_kind,omitempty"`
+}
+
+func (r DiscountRule) IsValid() bool {
+	if r.Scope != DiscountScopeOrcamento && r.Scope != DiscountScopeItem {
+		return false
+	}
+	if r.ItemKind != "" && (r.Scope != DiscountScopeItem || (r.ItemKind != EstimateItemServico && r.ItemKind != EstimateItemPeca)) {
+		return false
+	}
+	switch r.Type {
+	case DiscountPercentual:
+		return r.Value > 0 && r.Value <= 100
+	case DiscountFixo:
+		return r.Value > 0
+	default:
+		return false
+	}
+}
+
+func (r DiscountRule) matches(item EstimateItem) bool {
+	return r.ItemKind == "" || r.ItemKind == item.Kind
+}
+
+// Coupon is a discount code. Codes are stored upper case.
+//
+// Limits: MaxRedemptions bounds the estimates using the coupon overall and MaxPerCustomer
+// the ones of a customer; zero means unlimited. Redemptions is maintained by the
+// repository in the same transaction that writes the estimate.
+type Coupon struct {
+	Code           string       `json:"code"`
+	Description    string       `json:"description"`
+	Rule           DiscountRule `json:"rule"`
+	ValidFrom      *time.Time   `json:"valid_from,omitempty"`
+	ExpiresAt      *time.Time   `json:"expires_at,omitempty"`
+	MaxRedemptions int          `json:"max_redemptions"`
+	MaxPerCustomer int          `json:"max_per_customer"`
+	Redemptions    int          `json:"redemptions"`
+	Active         bool         `json:"active"`
+	CreatedBy      string       `json:"created_by,omitempty"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func (c Coupon) IsValid() bool {
+	if c.Code == "" || !c.Rule.IsValid() || c.MaxRedemptions < 0 || c.MaxPerCustomer < 0 {
+		return false
+	}
+	return c.ValidFrom == nil || c.ExpiresAt == nil || c.ExpiresAt.After(*c.ValidFrom)
+}
+
+// UsableAt tells whether the coupon can be redeemed at a date, before counting usage.
+func (c Coupon) UsableAt(at time.Time) bool {
+	if !c.Active || (c.ValidFrom != nil && at.Before(*c.ValidFrom)) || (c.ExpiresAt != nil && !at.Before(*c.ExpiresAt)) {
+		return false
+	}
+	return c.MaxRedemptions == 0 || c.Redemptions < c.MaxRedemptions
+}
+
+// CouponRedemption is one use of a coupon by an estimate, counted atomically with the
+// estimate write.
+type CouponRedemption struct {
+	Code           string
+	CustomerID     string
+	MaxPerCustomer int
+	RedeemedAt     time.Time
+}
+
+// ManualDiscount is a discount granted by the front desk; it needs a reason and the
+// approver.
+type ManualDiscount struct {
+	Rule       DiscountRule
+	Reason     string
+	ApprovedBy string
+}
+
+func (m ManualDiscount) IsValid() bool {
+	return m.Rule.IsValid() && strings.TrimSpace(m.Reason) != "" && strings.TrimSpace(m.ApprovedBy) != ""
+}
+
+// EstimateDiscount is a discount applied to an estimate and the amount it took off.
+type EstimateDiscount struct {
+	Source     DiscountSource `json:"source"`
+	CouponCode string         `json:"coupon_code,omitempty"`
+	Rule       DiscountRule   `json:"rule"`
+	Reason     string         `json:"reason,omitempty"`
+	ApprovedBy string         `json:"approved_by,omitempty"`
+	Amount     float64        `json:"amount"`
+}
+
+// ApplyDiscounts computes the amount of each discount over a gross price and its items and
+// returns the items with their share of the discounts, the discounts with their amounts
+// and the total taken off.
+//
+// Item-scoped discounts are applied first, line by line; estimate-scoped discounts then
+// apply to what is left, in the given order, and are spread over the lines in proportion
+// to their remaining amount (so taxes are computed on the discounted lines). Without
+// items, item-scoped discounts take nothing off. The total never exceeds the gross price.
+func ApplyDiscounts(gross float64, items []EstimateItem, discounts []EstimateDiscount) ([]EstimateItem, []EstimateDiscount, float64) {
+	lines := make([]EstimateItem, len(items))
+	for i, item := range items {
+		item.Discount = 0
+		lines[i] = item
+	}
+	applied := make([]EstimateDiscount, len(discounts))
+	copy(applied, discounts)
+
+	total := 0.0
+	for i, d := range applied {
+		if d.Rule.Scope != DiscountScopeItem {
+			continue
+		}
+		amount := 0.0
+		for j, line := range lines {
+			if !d.Rule.matches(line) {
+				continue
+			}
+			off := math.Min(discountAmount(d.Rule, line.Total), line.Total-line.Discount)
+			lines[j].Discount = roundCents(line.Discount + off)
+			amount += off
+		}
+		applied[i].Amount = roundCents(amount)
+		total += amount
+	}
+
+	for i, d := range applied {
+		if d.Rule.Scope != DiscountScopeOrcamento {
+			continue
+		}
+		remaining := roundCents(gross - total)
+		amount := math.Min(discountAmount(d.Rule, remaining), remaining)
+		applied[i].Amount = amount
+		total += amount
+		spread(lines, amount)
+	}
+	return lines, applied, roundCents(total)
+}
+
+func discountAmount(r DiscountRule, base float64) float64 {
+	if r.Type == DiscountPercentual {
+		return roundCents(base * r.Value / 100)
+	}
+	return roundCents(r.Value)
+}
+
+// spread allocates amount over the lines in proportion to what is left of each; the last
+// line with something left takes the rounding difference.
+func spread(lines []EstimateItem, amount float64) {
+	left := 0.0
+	last := -1
+	for i, line := range lines {
+		if net := line.Total - line.Discount; net > 0 {
+			left += net
+			last = i
+		}
+	}
+	if last < 0 || amount <= 0 {
+		return
+	}
+	allocated := 0.0
+	for i, line := range lines {
+		net := line.Total - line.Discount
+		if net <= 0 {
+			continue
+		}
+		share := roundCents(amount * net / left)
+		if i == last {
+			share = roundCents(amount - allocated)
+		}
+		share = math.Min(share, net)
+		lines[i].Discount = roundCents(line.Discount + share)
+		allocated += share
+	}
+}
+
+// ApplyPricing prices e from a gross total, its items and discounts (see ApplyDiscounts):
+// Subtotal is the gross total and Price what is left to charge.
+func (e *Estimate) ApplyPricing(gross float64, items []EstimateItem, discounts []EstimateDiscount) {
+	e.Subtotal = roundCents(gross)
+	e.Items, e.Discounts, e.DiscountTotal = ApplyDiscounts(e.Subtotal, items, discounts)
+	e.Price = roundCents(e.Subtotal - e.DiscountTotal)
+}
@@ -0,0 +1,76 @@
+package entities
+
+import "time"
+
+// DisputeStatus is the lifecycle of a chargeback dispute.
+type DisputeStatus string
+
+const (
+	DisputeStatusAberta           DisputeStatus = "aberta"
+	DisputeStatusEvidenciaEnviada DisputeStatus = "evidencia_enviada"
+	DisputeStatusGanha            DisputeStatus = "ganha"
+	DisputeStatusPerdida          DisputeStatus = "perdida"
+)
+
+// IsOpen reports whether the dispute still waits for a provider decision.
+func (s DisputeStatus) IsOpen() bool {
+	return s == DisputeStatusAberta || s == DisputeStatusEvidenciaEnviada
+}
+
+// CanTransitionTo reports whether a dispute may move from s to next.
+// Resolved disputes (won/lost) are final.
+func (s DisputeStatus) CanTransitionTo(next DisputeStatus) bool {
+	switch s {
+	case DisputeStatusAberta:
+		return next == DisputeStatusEvidenciaEnviada || next == DisputeStatusGanha || next == DisputeStatusPerdida
+	case DisputeStatusEvidenciaEnviada:
+		return next == DisputeStatusGanha || next == DisputeStatusPerdida
+	}
+	return false
+}
+
+// DisputeEvidence references a document sent to the provider (e.g. a signed service order).
+// Only the reference is stored; the file lives in the document storage.
+type DisputeEvidence struct {
+	Reference   string    `json:"reference"`
+	Description string    `json:"description,omitempty"`
+	AddedBy     string    `json:"added_by"`
+	AddedAt     time.Time `json:"added_at"`
+}
+
+// Dispute tracks a chargeback opened by the payer against a BillingPayment.
+//
+// Storage model (DynamoDB):
+//   - PK: id (provider chargeback id)
+//   - GSI1 (status-index): status
+//
+// Amount is the disputed amount; when the dispute is lost it becomes balance due on the
+// estimate again.
+type Dispute struct {
+	ID               string            `json:"id"`
+	PaymentID        string            `json:"payment_id"`
+	EstimateID       string            `json:"estimate_id"`
+	Status           DisputeStatus     `json:"status"`
+	Amount           float64           `json:"amount"`
+	Reason           string            `json:"reason,omitempty"`
+	EvidenceDeadline *time.Time        `json:"evidence_deadline,omitempty"`
+	Evidence         []DisputeEvidence `json:"evidence,omitempty"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+	ResolvedAt       *time.Time        `json:"resolved_at,omitempty"`
+}
+
+// IsOverdue reports whether the evidence deadline passed while the dispute was still open.
+func (d Dispute) IsOverdue(now time.Time) bool {
+	return d.Status.IsOpen() && d.EvidenceDeadline != nil && now.After(*d.EvidenceDeadline)
+}
+
+// ProviderChargeback is the chargeback state reported by the payment provider.
+type ProviderChargeback struct {
+	ID               string
+	PaymentID        string
+	Amount           float64
+	Reason           string
+	Status           DisputeStatus
+	EvidenceDeadline *time.Time
+}
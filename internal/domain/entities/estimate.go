@@ -17,8 +17,36 @@ const (
 	EstimateStatusAprovado  EstimateStatus = "aprovado"
 	EstimateStatusRejeitado EstimateStatus = "rejeitado"
 	EstimateStatusCancelado EstimateStatus = "cancelado"
+	// EstimateStatusExpirado is a pending estimate whose validity ended before approval.
+	EstimateStatusExpirado EstimateStatus = "expirado"
+	// EstimateStatusAguardandoAprovacao is an estimate above the internal approval threshold
+	// waiting for a manager's sign-off; it becomes pendente once signed off.
+	EstimateStatusAguardandoAprovacao EstimateStatus = "aguardando_aprovacao"
 )
 
+// CanTransitionTo reports whether an estimate in s can move to next:
+//   - pendente → aprovado, rejeitado, cancelado, expirado or aguardando_aprovacao
+//   - aguardando_aprovacao → pendente (signed off or repriced below the threshold) or
+//     cancelado
+//   - expirado → pendente (renewed) or cancelado
+//   - aprovado → cancelado
+//
+// rejeitado and cancelado are final.
+func (s EstimateStatus) CanTransitionTo(next EstimateStatus) bool {
+	switch s {
+	case EstimateStatusPendente:
+		return next == EstimateStatusAprovado || next == EstimateStatusRejeitado || next == EstimateStatusCancelado ||
+			next == EstimateStatusExpirado || next == EstimateStatusAguardandoAprovacao
+	case EstimateStatusAguardandoAprovacao:
+		return next == EstimateStatusPendente || next == EstimateStatusCancelado
+	case EstimateStatusExpirado:
+		return next == EstimateStatusPendente || next == EstimateStatusCancelado
+	case EstimateStatusAprovado:
+		return next == EstimateStatusCancelado
+	}
+	return false
+}
+
 // Estimate is the billing estimate (orçamento) persisted in DynamoDB.
 //
 // Storage model (DynamoDB):
@@ -27,12 +55,121 @@ const (
 //
 // Monetary representation:
 //   - Price represents the calculated estimate total.
+//   - BalanceDue is what the customer owes again after a payment was reversed
+//     (e.g. a lost chargeback). It stays zero while payments stand. BalanceDueRefs are
+//     the reversals (e.g. "dispute#<id>") already added, so a retried one is not added
+//     twice.
+//
+// ApprovedAt is set when the estimate is approved; estimates approved before it existed
+// have it nil (see ApprovalTime).
+//
+// Tax breakdown:
+//   - Items are the services and parts priced, with the taxes included in each line
+//     (see TaxTable.Apply); Taxes and TaxTotal sum them.
+//   - They are computed with the rates effective at PricedAt, also when the estimate is
+//     recalculated, so later rate changes do not alter existing estimates.
+//
+// Discounts: Subtotal is the gross total and Price what is charged, Subtotal minus
+// DiscountTotal (see ApplyDiscounts). Coupon discounts of a customer (CustomerID) count
+// towards the coupon per-customer limit.
+//
+// Pricing rules: the rules of PricingVersion adjust the items before discounts (see
+// PricingRuleSet.Apply); Adjustments explain each change and are part of Subtotal.
+// CustomerSegment is kept so the rules apply the same way when the estimate is repriced.
+//
+// Catalog: CatalogVersion is the catalog the items were checked against and
+// CatalogDeviations the items accepted although they did not match it.
+//
+// Validity: a pending estimate can be approved until ExpiresAt (nil: no limit); after it,
+// it is expired (see IsExpired) until renewed, which prices it again at RenewedAt.
+//
+// Decision: DecidedBy tells by whom the estimate was approved, rejected or canceled, and
+// Reason why it was rejected or canceled. History lists its status changes, oldest first
+// (estimates created before it existed start at their first change).
+//
+// Partial approval: when the customer approves only some items, Items keep the approved
+// ones, priced again without the others, and DeclinedItems the declined ones as they were
+// priced, for follow-up offers.
+//
+// Internal approval: estimates priced above the threshold wait in aguardando_aprovacao
+// for a manager's sign-off (InternalApproval) before the customer can approve them.
 //
 type Estimate struct {
-	ID        string         `json:"id"`
-	OSID      string         `json:"os_id"`
-	Price     float64        `json:"price"`
-	Status    EstimateStatus `json:"status"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
+	ID                string                 `json:"id"`
+	OSID              string                 `json:"os_id"`
+	Price             float64                `json:"price"`
+	BalanceDue        float64                `json:"balance_due"`
+	BalanceDueRefs    []string               `json:"-"`
+	Status            EstimateStatus         `json:"status"`
+	CreatedAt         time.Time              `json:"created_at"`
+	UpdatedAt         time.Time              `json:"updated_at"`
+	ApprovedAt        *time.Time             `json:"approved_at,omitempty"`
+	Municipality      string                 `json:"municipality,omitempty"`
+	Items             []EstimateItem         `json:"items,omitempty"`
+	Taxes             []TaxAmount            `json:"taxes,omitempty"`
+	TaxTotal          float64                `json:"tax_total"`
+	CustomerID        string                 `json:"customer_id,omitempty"`
+	CustomerSegment   string                 `json:"customer_segment,omitempty"`
+	Subtotal          float64                `json:"subtotal"`
+	Discounts         []EstimateDiscount     `json:"discounts,omitempty"`
+	DiscountTotal     float64                `json:"discount_total"`
+	PricingVersion    string                 `json:"pricing_version,omitempty"`
+	Adjustments       []PricingAdjustment    `json:"adjustments,omitempty"`
+	AdjustmentTotal   float64                `json:"adjustment_total"`
+	CatalogVersion    int64                  `json:"catalog_version,omitempty"`
+	CatalogDeviations []CatalogDeviation     `json:"catalog_deviations,omitempty"`
+	ExpiresAt         *time.Time             `json:"expires_at,omitempty"`
+	RenewedAt         *time.Time             `json:"renewed_at,omitempty"`
+	Reason            *StatusReason          `json:"reason,omitempty"`
+	DecidedBy         string                 `json:"decided_by,omitempty"`
+	History           []EstimateStatusChange `json:"history,omitempty"`
+	DeclinedItems     []EstimateItem         `json:"declined_items,omitempty"`
+	InternalApproval  *InternalApproval      `json:"internal_approval,omitempty"`
+}
+
+// ApprovalTime returns when the estimate was approved, falling back to the last update
+// for estimates approved before ApprovedAt was recorded.
+func (e Estimate) ApprovalTime() time.Time {
+	if e.ApprovedAt != nil {
+		return *e.ApprovedAt
+	}
+	return e.UpdatedAt
+}
+
+// IsExpired tells whether the estimate can no longer be approved at the given time: it was
+// expired, or it is pending past ExpiresAt and was not swept yet.
+func (e Estimate) IsExpired(at time.Time) bool {
+	if e.Status == EstimateStatusExpirado {
+		return true
+	}
+	return e.Status == EstimateStatusPendente && e.ExpiresAt != nil && !at.Before(*e.ExpiresAt)
+}
+
+// PricedAt is when the estimate prices (pricing rule schedules and tax rates) are read:
+// its creation, or its last renewal.
+func (e Estimate) PricedAt() time.Time {
+	if e.RenewedAt != nil {
+		return *e.RenewedAt
+	}
+	return e.CreatedAt
+}
+
+// AwaitsInternalApproval tells whether the estimate still needs a manager's sign-off
+// before the customer can approve it and it can be charged.
+func (e Estimate) AwaitsInternalApproval() bool {
+	return e.Status == EstimateStatusAguardandoAprovacao
+}
+
+// IsPartiallyApproved tells whether the customer declined some of the items on approval.
+func (e Estimate) IsPartiallyApproved() bool {
+	return e.Status == EstimateStatusAprovado && len(e.DeclinedItems) > 0
+}
+
+// GrossTotal is the total before discounts; estimates priced before discounts existed
+// have no Subtotal and were charged their Price.
+func (e Estimate) GrossTotal() float64 {
+	if e.Subtotal > 0 {
+		return e.Subtotal
+	}
+	return e.Price
 }
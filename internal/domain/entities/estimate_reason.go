@@ -0,0 +1,117 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrInvalidReasonCatalog = errors.New("invalid reason catalog")
+
+// maxReasonTextLength bounds the free text of a reason.
+const maxReasonTextLength = 500
+
+// ReasonCode is an entry of the reason catalog. TextRequired asks for a free text
+// explaining it (e.g. "outro").
+type ReasonCode struct {
+	Code         string `json:"code"`
+	Label        string `json:"label"`
+	TextRequired bool   `json:"text_required,omitempty"`
+}
+
+// ReasonCatalog lists the reasons an estimate can be rejected or canceled with.
+type ReasonCatalog struct {
+	Rejection    []ReasonCode `json:"rejeicao"`
+	Cancellation []ReasonCode `json:"cancelamento"`
+}
+
+// DefaultReasonCatalog is used when no catalog is configured.
+func DefaultReasonCatalog() ReasonCatalog {
+	return ReasonCatalog{
+		Rejection: []ReasonCode{
+			{Code: "preco", Label: "Preço acima do esperado"},
+			{Code: "prazo", Label: "Prazo de execução"},
+			{Code: "concorrente", Label: "Fechou com outra oficina"},
+			{Code: "sem_necessidade", Label: "Serviço não é necessário agora"},
+			{Code: "outro", Label: "Outro motivo", TextRequired: true},
+		},
+		Cancellation: []ReasonCode{
+			{Code: "desistencia", Label: "Cliente desistiu do serviço"},
+			{Code: "duplicado", Label: "Orçamento duplicado"},
+			{Code: "erro_orcamento", Label: "Erro no orçamento"},
+			{Code: "outro", Label: "Outro motivo", TextRequired: true},
+		},
+	}
+}
+
+func NormalizeReasonCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
+
+// Validate rejects empty catalogs, entries without code or label and repeated codes.
+func (c ReasonCatalog) Validate() error {
+	for _, codes := range [][]ReasonCode{c.Rejection, c.Cancellation} {
+		if len(codes) == 0 {
+			return ErrInvalidReasonCatalog
+		}
+		seen := map[string]bool{}
+		for _, r := range codes {
+			code := NormalizeReasonCode(r.Code)
+			if code == "" || strings.TrimSpace(r.Label) == "" || seen[code] {
+				return ErrInvalidReasonCatalog
+			}
+			seen[code] = true
+		}
+	}
+	return nil
+}
+
+// Lookup returns the reason code of the status (rejeitado or cancelado).
+func (c ReasonCatalog) Lookup(status EstimateStatus, code string) (ReasonCode, bool) {
+	var codes []ReasonCode
+	switch status {
+	case EstimateStatusRejeitado:
+		codes = c.Rejection
+	case EstimateStatusCancelado:
+		codes = c.Cancellation
+	}
+	code = NormalizeReasonCode(code)
+	for _, r := range codes {
+		if NormalizeReasonCode(r.Code) == code {
+			r.Code = code
+			return r, true
+		}
+	}
+	return ReasonCode{}, false
+}
+
+// Reason builds the reason of a status change from a catalog code and a free text; ok is
+// false when the code is unknown, the text is missing where required or too long.
+func (r ReasonCode) Reason(text string) (StatusReason, bool) {
+	text = strings.TrimSpace(text)
+	if (r.TextRequired && text == "") || len([]rune(text)) > maxReasonTextLength {
+		return StatusReason{}, false
+	}
+	return StatusReason{Code: r.Code, Label: r.Label, Text: text}, true
+}
+
+// StatusReason is why an estimate was rejected or canceled: a catalog code, its label at
+// the time and an optional free text.
+type StatusReason struct {
+	Code  string `json:"code"`
+	Label string `json:"label"`
+	Text  string `json:"text,omitempty"`
+}
+
+// EstimateStatusChange is an entry of the estimate history: the status it moved to, when
+// and by whom (empty for the service itself, e.g. the expiration sweeper). Evidence is set
+// when the customer decided through an approval link.
+type EstimateStatusChange struct {
+	Status   EstimateStatus    `json:"status"`
+	At       time.Time         `json:"at"`
+	Actor    string            `json:"actor,omitempty"`
+	Reason   *StatusReason     `json:"reason,omitempty"`
+	Evidence *DecisionEvidence `json:"evidence,omitempty"`
+	// Comment is the note of a manager deciding an internal approval.
+	Comment string `json:"comment,omitempty"`
+}
@@ -0,0 +1,37 @@
+package entities
+
+import "time"
+
+// InternalApprovalDecision is a manager's answer to an internal approval request.
+type InternalApprovalDecision string
+
+const (
+	InternalApprovalAprovado  InternalApprovalDecision = "aprovado"
+	InternalApprovalRejeitado InternalApprovalDecision = "rejeitado"
+)
+
+// InternalApproval is the workshop manager sign-off required by estimates priced above the
+// internal approval threshold, before the customer can approve them.
+//
+// Price is the price submitted for sign-off and Threshold the one in force at RequestedAt.
+// Decision is empty while the request is pending; Approver and Comment tell who decided
+// and why (a rejection sends the estimate back for revision).
+type InternalApproval struct {
+	Threshold   float64                  `json:"threshold"`
+	Price       float64                  `json:"price"`
+	RequestedAt time.Time                `json:"requested_at"`
+	Decision    InternalApprovalDecision `json:"decision,omitempty"`
+	Approver    string                   `json:"approver,omitempty"`
+	Comment     string                   `json:"comment,omitempty"`
+	DecidedAt   *time.Time               `json:"decided_at,omitempty"`
+}
+
+// IsPending tells whether the request still waits for a manager.
+func (a InternalApproval) IsPending() bool {
+	return a.Decision == ""
+}
+
+// Covers tells whether a manager signed off a price at least as high as price.
+func (a InternalApproval) Covers(price float64) bool {
+	return a.Decision == InternalApprovalAprovado && price <= a.Price
+}
@@ -0,0 +1,159 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+var (
+	// ErrInvoiceAlreadyIssued is returned when the estimate already has an active invoice.
+	ErrInvoiceAlreadyIssued = errors.New("estimate already invoiced")
+	// ErrInvoiceChanged is returned when the invoice changed between read and write
+	// (voided, credited or paid concurrently).
+	ErrInvoiceChanged = errors.New("invoice changed concurrently")
+)
+
+// InvoiceStatus is the lifecycle of an invoice (fatura).
+type InvoiceStatus string
+
+const (
+	InvoiceStatusEmitida   InvoiceStatus = "emitida"
+	InvoiceStatusCancelada InvoiceStatus = "cancelada"
+)
+
+// InvoiceLine is a billed item, snapshotted when the invoice is issued.
+type InvoiceLine struct {
+	Description string  `json:"description"`
+	Quantity    float64 `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	Total       float64 `json:"total"`
+}
+
+// InvoiceTax is a tax added to the invoice total.
+type InvoiceTax struct {
+	Name   string  `json:"name"`
+	Rate   float64 `json:"rate"`
+	Amount float64 `json:"amount"`
+}
+
+// CreditNote reduces what is owed on an invoice. Numbers come from their own sequence.
+type CreditNote struct {
+	Number    int64     `json:"number"`
+	Amount    float64   `json:"amount"`
+	Reason    string    `json:"reason"`
+	Actor     string    `json:"actor,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// InvoicePayment is a payment applied against an invoice.
+type InvoicePayment struct {
+	PaymentID string    `json:"payment_id"`
+	Amount    float64   `json:"amount"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+// Invoice is the bill of an approved estimate (the estimate is only the quote).
+//
+// Notes:
+//   - Lines, Taxes and totals are a snapshot taken at issue; later estimate price changes
+//     do not change the invoice (void it and issue again).
+//   - Number is sequential and assigned by the repository when the invoice is stored.
+//   - An estimate has at most one active (emitida) invoice.
+//   - Version guards concurrent updates (optimistic locking); it is not exposed.
+type Invoice struct {
+	ID          string           `json:"id"`
+	Number      int64            `json:"number"`
+	EstimateID  string           `json:"estimate_id"`
+	OSID        string           `json:"os_id"`
+	Status      InvoiceStatus    `json:"status"`
+	Lines       []InvoiceLine    `json:"lines"`
+	Taxes       []InvoiceTax     `json:"taxes"`
+	Subtotal    float64          `json:"subtotal"`
+	TaxTotal    float64          `json:"tax_total"`
+	Total       float64          `json:"total"`
+	Payments    []InvoicePayment `json:"payments"`
+	CreditNotes []CreditNote     `json:"credit_notes"`
+	IssuedBy    string           `json:"issued_by,omitempty"`
+	IssuedAt    time.Time        `json:"issued_at"`
+	VoidedAt    *time.Time       `json:"voided_at,omitempty"`
+	VoidReason  string           `json:"void_reason,omitempty"`
+	VoidedBy    string           `json:"voided_by,omitempty"`
+	Version     int64            `json:"-"`
+}
+
+// NewInvoiceFromEstimate snapshots an estimate as a single service line; taxes are added
+// on top of the line totals.
+func NewInvoiceFromEstimate(e Estimate, taxes []InvoiceTax, at time.Time) Invoice {
+	inv := Invoice{
+		EstimateID: e.ID,
+		OSID:       e.OSID,
+		Status:     InvoiceStatusEmitida,
+		Lines: []InvoiceLine{{
+			Description: fmt.Sprintf("Serviços da ordem de serviço %s", e.OSID),
+			Quantity:    1,
+			UnitPrice:   e.Price,
+			Total:       e.Price,
+		}},
+		Taxes:       taxes,
+		Payments:    []InvoicePayment{},
+		CreditNotes: []CreditNote{},
+		IssuedAt:    at.UTC(),
+	}
+	if inv.Taxes == nil {
+		inv.Taxes = []InvoiceTax{}
+	}
+	for _, l := range inv.Lines {
+		inv.Subtotal += l.Total
+	}
+	for _, t := range inv.Taxes {
+		inv.TaxTotal += t.Amount
+	}
+	inv.Subtotal = roundCents(inv.Subtotal)
+	inv.TaxTotal = roundCents(inv.TaxTotal)
+	inv.Total = roundCents(inv.Subtotal + inv.TaxTotal)
+	return inv
+}
+
+// AmountPaid sums the payments applied to the invoice.
+func (i Invoice) AmountPaid() float64 {
+	total := 0.0
+	for _, p := range i.Payments {
+		total += p.Amount
+	}
+	return roundCents(total)
+}
+
+// CreditedAmount sums the credit notes of the invoice.
+func (i Invoice) CreditedAmount() float64 {
+	total := 0.0
+	for _, c := range i.CreditNotes {
+		total += c.Amount
+	}
+	return roundCents(total)
+}
+
+// Balance is what is still owed: total minus payments and credit notes.
+func (i Invoice) Balance() float64 {
+	return roundCents(i.Total - i.AmountPaid() - i.CreditedAmount())
+}
+
+// IsPaid reports whether an active invoice has nothing left to pay.
+func (i Invoice) IsPaid() bool {
+	return i.Status == InvoiceStatusEmitida && i.Balance() <= 0
+}
+
+// HasPayment reports whether the payment was already applied.
+func (i Invoice) HasPayment(paymentID string) bool {
+	for _, p := range i.Payments {
+		if p.PaymentID == paymentID {
+			return true
+		}
+	}
+	return false
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,65 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidLaborRates = errors.New("invalid labor rates")
+
+// LaborRateTable holds the hourly rate of each mechanic skill tier. Services quoted in
+// hours without a tier are priced as DefaultTier.
+type LaborRateTable struct {
+	DefaultTier string
+	Rates       map[string]float64
+}
+
+// Validate rejects non-positive rates and a default tier without a rate.
+func (t LaborRateTable) Validate() error {
+	for tier, rate := range t.Rates {
+		if strings.TrimSpace(tier) == "" || rate <= 0 {
+			return ErrInvalidLaborRates
+		}
+	}
+	if t.DefaultTier != "" {
+		if _, ok := t.Rates[t.DefaultTier]; !ok {
+			return ErrInvalidLaborRates
+		}
+	}
+	return nil
+}
+
+// Rate returns the tier (normalized, or the default one when empty) and its hourly rate.
+func (t LaborRateTable) Rate(tier string) (string, float64, bool) {
+	tier = NormalizeSkillTier(tier)
+	if tier == "" {
+		tier = t.DefaultTier
+	}
+	rate, ok := t.Rates[tier]
+	return tier, rate, ok && tier != ""
+}
+
+func NormalizeSkillTier(tier string) string {
+	return strings.ToLower(strings.TrimSpace(tier))
+}
+
+// IsLabor tells whether the line is a service priced by the hour.
+func (i EstimateItem) IsLabor() bool {
+	return i.Hours > 0
+}
+
+// BilledHours are the actual hours once recorded, the estimated ones before.
+func (i EstimateItem) BilledHours() float64 {
+	if i.ActualHours > 0 {
+		return i.ActualHours
+	}
+	return i.Hours
+}
+
+// PriceLabor prices a labor line as its billed hours times its HourlyRate.
+func (i EstimateItem) PriceLabor() EstimateItem {
+	i.Quantity = 1
+	i.UnitPrice = roundCents(i.BilledHours() * i.HourlyRate)
+	i.Total = i.UnitPrice
+	return i
+}
@@ -0,0 +1,198 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+var (
+	// ErrUnbalancedLedgerEntry is returned when debits and credits of an entry differ.
+	ErrUnbalancedLedgerEntry = errors.New("unbalanced ledger entry")
+	// ErrLedgerConflict is returned when an entry with the same ID was already posted.
+	ErrLedgerConflict = errors.New("ledger transaction conflict")
+)
+
+// LedgerAccount is an account of the internal double-entry ledger.
+type LedgerAccount string
+
+const (
+	// LedgerAccountCustomerReceivable is what customers owe (a lost chargeback).
+	LedgerAccountCustomerReceivable LedgerAccount = "customer_receivable"
+	// LedgerAccountProviderClearing is the money held by the payment provider.
+	LedgerAccountProviderClearing LedgerAccount = "provider_clearing"
+	LedgerAccountFees             LedgerAccount = "fees"
+	LedgerAccountRevenue          LedgerAccount = "revenue"
+	LedgerAccountRefunds          LedgerAccount = "refunds"
+)
+
+// LedgerAccounts lists every ledger account.
+var LedgerAccounts = []LedgerAccount{
+	LedgerAccountCustomerReceivable,
+	LedgerAccountProviderClearing,
+	LedgerAccountFees,
+	LedgerAccountRevenue,
+	LedgerAccountRefunds,
+}
+
+func (a LedgerAccount) IsValid() bool {
+	for _, v := range LedgerAccounts {
+		if a == v {
+			return true
+		}
+	}
+	return false
+}
+
+// CreditNormal reports whether the account balance grows with credits (revenue);
+// the other accounts grow with debits.
+func (a LedgerAccount) CreditNormal() bool {
+	return a == LedgerAccountRevenue
+}
+
+// LedgerEntryKind is the money movement behind a ledger entry.
+type LedgerEntryKind string
+
+const (
+	LedgerEntryPayment    LedgerEntryKind = "payment"
+	LedgerEntryRefund     LedgerEntryKind = "refund"
+	LedgerEntryChargeback LedgerEntryKind = "chargeback"
+)
+
+// LedgerLine debits or credits one account. Amounts are in cents, so entries balance
+// exactly; exactly one of Debit and Credit is set.
+type LedgerLine struct {
+	Account LedgerAccount `json:"account"`
+	Debit   int64         `json:"debit,omitempty"`
+	Credit  int64         `json:"credit,omitempty"`
+}
+
+// LedgerEntry is an immutable journal entry. ID is derived from the payment and the kind
+// (see LedgerEntryID), so posting the same movement twice is rejected.
+type LedgerEntry struct {
+	ID         string          `json:"id"`
+	Kind       LedgerEntryKind `json:"kind"`
+	PaymentID  string          `json:"payment_id"`
+	EstimateID string          `json:"estimate_id"`
+	Lines      []LedgerLine    `json:"lines"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// LedgerEntryID is the ID of the entry of kind posted for a payment.
+func LedgerEntryID(paymentID string, kind LedgerEntryKind) string {
+	return paymentID + "-" + string(kind)
+}
+
+// Validate checks that the entry has at least two valid lines and balances.
+func (e LedgerEntry) Validate() error {
+	if e.ID == "" || e.EstimateID == "" || len(e.Lines) < 2 {
+		return fmt.Errorf("%w: id, estimate and two lines are required", ErrUnbalancedLedgerEntry)
+	}
+	var debits, credits int64
+	for _, l := range e.Lines {
+		if !l.Account.IsValid() || l.Debit < 0 || l.Credit < 0 || (l.Debit == 0) == (l.Credit == 0) {
+			return fmt.Errorf("%w: invalid line %+v", ErrUnbalancedLedgerEntry, l)
+		}
+		debits += l.Debit
+		credits += l.Credit
+	}
+	if debits != credits {
+		return fmt.Errorf("%w: debits %d != credits %d", ErrUnbalancedLedgerEntry, debits, credits)
+	}
+	return nil
+}
+
+// LedgerBalance is the running total of an account, either ledger-wide (EstimateID empty)
+// or restricted to the entries of one estimate.
+type LedgerBalance struct {
+	Account    LedgerAccount `json:"account"`
+	EstimateID string        `json:"estimate_id,omitempty"`
+	Debits     int64         `json:"debits"`
+	Credits    int64         `json:"credits"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+}
+
+// Balance is the balance on the normal side of the account, in cents.
+func (b LedgerBalance) Balance() int64 {
+	if b.Account.CreditNormal() {
+		return b.Credits - b.Debits
+	}
+	return b.Debits - b.Credits
+}
+
+// ToCents converts an amount with the float representation of Estimate.Price to cents.
+func ToCents(v float64) int64 {
+	return int64(math.Round(v * 100))
+}
+
+// LedgerEntryForTransition returns the entry posted when payment p moves from previous to
+// next, and false when the transition moves no money.
+//
+// Posting rules (gross = Details.Amount, fees = Details.TotalFees):
+//   - approval (not coming from aprovado/contestado): debit provider_clearing, credit
+//     revenue by gross; debit fees, credit provider_clearing by fees.
+//   - refund (reembolsado) of captured money: debit refunds, credit provider_clearing.
+//   - lost chargeback (estornado) of captured money: debit customer_receivable, credit
+//     provider_clearing; the customer owes the estimate again.
+//
+// A dispute (contestado) and its win (back to aprovado) move no money.
+func LedgerEntryForTransition(p BillingPayment, previous, next PaymentStatus, at time.Time) (LedgerEntry, bool) {
+	gross := ToCents(p.Details.Amount)
+	kind, ok := PaymentTransitionKind(previous, next)
+	if gross <= 0 || !ok {
+		return LedgerEntry{}, false
+	}
+
+	entry := LedgerEntry{Kind: kind, PaymentID: p.ID, EstimateID: p.EstimateID, CreatedAt: at.UTC()}
+	switch kind {
+	case LedgerEntryPayment:
+		entry.Lines = []LedgerLine{
+			{Account: LedgerAccountProviderClearing, Debit: gross},
+			{Account: LedgerAccountRevenue, Credit: gross},
+		}
+		if fees := ToCents(p.Details.TotalFees()); fees > 0 {
+			entry.Lines = append(entry.Lines,
+				LedgerLine{Account: LedgerAccountFees, Debit: fees},
+				LedgerLine{Account: LedgerAccountProviderClearing, Credit: fees},
+			)
+		}
+	case LedgerEntryRefund:
+		entry.Lines = []LedgerLine{
+			{Account: LedgerAccountRefunds, Debit: gross},
+			{Account: LedgerAccountProviderClearing, Credit: gross},
+		}
+	case LedgerEntryChargeback:
+		entry.Lines = []LedgerLine{
+			{Account: LedgerAccountCustomerReceivable, Debit: gross},
+			{Account: LedgerAccountProviderClearing, Credit: gross},
+		}
+	}
+	entry.ID = LedgerEntryID(p.ID, entry.Kind)
+	return entry, true
+}
+
+// PaymentTransitionKind classifies a payment status change by the money it moves: a
+// capture (payment), a refund or a lost chargeback. It returns false when no money moves.
+func PaymentTransitionKind(previous, next PaymentStatus) (LedgerEntryKind, bool) {
+	if previous == next {
+		return "", false
+	}
+	captured := previous == PaymentStatusAprovado || previous == PaymentStatusContestado
+	switch {
+	case next == PaymentStatusAprovado && !captured:
+		return LedgerEntryPayment, true
+	case next == PaymentStatusReembolsado && captured:
+		return LedgerEntryRefund, true
+	case next == PaymentStatusEstornado && captured:
+		return LedgerEntryChargeback, true
+	}
+	return "", false
+}
+
+// EstimateLedger is the ledger view of an estimate: its account balances and entries.
+type EstimateLedger struct {
+	EstimateID string          `json:"estimate_id"`
+	Balances   []LedgerBalance `json:"balances"`
+	Entries    []LedgerEntry   `json:"entries"`
+}
@@ -0,0 +1,130 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNFSeAlreadyGenerated is returned when the invoice already has an NFS-e document.
+	ErrNFSeAlreadyGenerated = errors.New("nfse already generated for invoice")
+	// ErrRPSNumberTaken is returned when another document took the RPS number first.
+	ErrRPSNumberTaken = errors.New("rps number taken")
+)
+
+// NFSeStatus tracks the delivery of a signed NFS-e document to the municipality.
+type NFSeStatus string
+
+const (
+	// NFSeStatusAssinada is a signed RPS not yet accepted by the transmitter.
+	NFSeStatusAssinada NFSeStatus = "assinada"
+	NFSeStatusEnviada  NFSeStatus = "enviada"
+	NFSeStatusFalha    NFSeStatus = "falha"
+)
+
+// NFSeAddress is the address of the service taker (tomador). CityCode is the IBGE code.
+type NFSeAddress struct {
+	Street     string `json:"street"`
+	Number     string `json:"number"`
+	Complement string `json:"complement,omitempty"`
+	District   string `json:"district"`
+	CityCode   string `json:"city_code"`
+	State      string `json:"state"`
+	PostalCode string `json:"postal_code"`
+}
+
+// NFSeTomador is the customer the service was provided to. Document is a CPF or CNPJ
+// (digits only once normalized).
+type NFSeTomador struct {
+	Document string       `json:"document"`
+	Name     string       `json:"name"`
+	Email    string       `json:"email,omitempty"`
+	Address  *NFSeAddress `json:"address,omitempty"`
+}
+
+// Normalize trims the fields and keeps only the digits of the document.
+func (t NFSeTomador) Normalize() NFSeTomador {
+	t.Document = digitsOnly(t.Document)
+	t.Name = strings.TrimSpace(t.Name)
+	t.Email = strings.TrimSpace(t.Email)
+	if t.Address != nil {
+		a := *t.Address
+		a.PostalCode = digitsOnly(a.PostalCode)
+		a.CityCode = digitsOnly(a.CityCode)
+		a.State = strings.ToUpper(strings.TrimSpace(a.State))
+		t.Address = &a
+	}
+	return t
+}
+
+// IsValid reports whether a normalized tomador has a CPF/CNPJ-sized document and a name.
+func (t NFSeTomador) IsValid() bool {
+	return (len(t.Document) == 11 || len(t.Document) == 14) && t.Name != ""
+}
+
+// IsCompany reports whether the document is a CNPJ.
+func (t NFSeTomador) IsCompany() bool {
+	return len(t.Document) == 14
+}
+
+// NFSeRPS is the provisional receipt (Recibo Provisório de Serviços) converted into an
+// NFS-e by the municipality. ServiceAmount excludes ISS, which the fiscal module computes
+// from its configured rate.
+type NFSeRPS struct {
+	Number             int64       `json:"number"`
+	Series             string      `json:"series"`
+	IssuedAt           time.Time   `json:"issued_at"`
+	Tomador            NFSeTomador `json:"tomador"`
+	ServiceDescription string      `json:"service_description"`
+	ServiceAmount      float64     `json:"service_amount"`
+}
+
+// NewNFSeRPS builds the RPS of an invoice: its lines become the service description
+// (discriminação) and its subtotal the service amount. Number is assigned on storage.
+func NewNFSeRPS(inv Invoice, tomador NFSeTomador, series string, at time.Time) NFSeRPS {
+	lines := make([]string, 0, len(inv.Lines))
+	for _, l := range inv.Lines {
+		lines = append(lines, l.Description)
+	}
+	return NFSeRPS{
+		Series:             series,
+		IssuedAt:           at.UTC(),
+		Tomador:            tomador,
+		ServiceDescription: strings.Join(lines, "; "),
+		ServiceAmount:      inv.Subtotal,
+	}
+}
+
+// NFSeDocument is the signed RPS XML of an invoice and the state of its transmission.
+// An invoice has at most one document; a failed transmission can be retried.
+type NFSeDocument struct {
+	ID                string     `json:"id"`
+	InvoiceID         string     `json:"invoice_id"`
+	EstimateID        string     `json:"estimate_id"`
+	RPS               NFSeRPS    `json:"rps"`
+	Status            NFSeStatus `json:"status"`
+	SignedXML         []byte     `json:"-"`
+	Protocol          string     `json:"protocol,omitempty"`
+	TransmissionError string     `json:"transmission_error,omitempty"`
+	Attempts          int        `json:"attempts"`
+	CreatedBy         string     `json:"created_by,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	TransmittedAt     *time.Time `json:"transmitted_at,omitempty"`
+
+	// DataKey seals the tomador and the signed XML at rest; TomadorDocHash and
+	// TomadorEmailHash are the blind indexes used by data subject requests.
+	DataKey          *EncryptedDataKey `json:"-"`
+	TomadorDocHash   string            `json:"-"`
+	TomadorEmailHash string            `json:"-"`
+}
+
+func digitsOnly(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,21 @@
+package entities
+
+import "errors"
+
+// ErrInvalidPageCursor is returned when a page cursor was not produced by the repository.
+var ErrInvalidPageCursor = errors.New("invalid page cursor")
+
+// PageRequest selects a page of a list ordered by a sort key.
+//
+// Cursor is opaque to callers: it is the NextCursor of the previous page.
+type PageRequest struct {
+	Limit      int32
+	Cursor     string
+	Descending bool
+}
+
+// Page is a page of results. NextCursor is empty on the last page.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+}
@@ -0,0 +1,237 @@
+package entities
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EncryptedValuePrefix marks a payload value encrypted with the payment data key.
+const EncryptedValuePrefix = "enc:v1:"
+
+const redactedValue = "***"
+
+// SensitivePayloadPaths lists the payer/card fields of the provider payload considered
+// personal data (LGPD). They are encrypted at rest and redacted in API responses. A path
+// ending at an object (the addresses) covers every value inside it.
+var SensitivePayloadPaths = [][]string{
+	{"payer", "id"},
+	{"payer", "email"},
+	{"payer", "first_name"},
+	{"payer", "last_name"},
+	{"payer", "identification", "number"},
+	{"payer", "phone", "number"},
+	{"payer", "address"},
+	{"card", "first_six_digits"},
+	{"card", "cardholder", "name"},
+	{"card", "cardholder", "identification", "number"},
+	{"additional_info", "payer", "first_name"},
+	{"additional_info", "payer", "last_name"},
+	{"additional_info", "payer", "phone", "number"},
+	{"additional_info", "payer", "address"},
+	{"additional_info", "shipments", "receiver_address"},
+}
+
+// AnonymizedValue replaces payer personal data erased on a data subject request.
+const AnonymizedValue = "ANONYMIZED"
+
+// EncryptedDataKey is the per-payment data key wrapped by the key provider (envelope encryption).
+type EncryptedDataKey struct {
+	KeyID      string `json:"key_id"`
+	WrappedKey []byte `json:"wrapped_key"`
+}
+
+// IsEncrypted reports whether a payload value was produced by the payment encryptor.
+func IsEncrypted(v string) bool {
+	return strings.HasPrefix(v, EncryptedValuePrefix)
+}
+
+// Redacted returns a copy of the payment safe to expose: sensitive payload fields and
+// payer details are masked, whether they are stored encrypted or in plaintext.
+func (p BillingPayment) Redacted() BillingPayment {
+	out := p
+	out.DataKey = nil
+	out.Details = p.Details.redacted()
+
+	if p.MPPayload != nil {
+		out.MPPayload = redactPayloadMap(p.MPPayload)
+	}
+	if len(p.MPPayloadRaw) > 0 {
+		var raw map[string]any
+		if err := json.Unmarshal(p.MPPayloadRaw, &raw); err == nil {
+			if b, err := json.Marshal(redactPayloadMap(raw)); err == nil {
+				out.MPPayloadRaw = b
+			}
+		} else {
+			out.MPPayloadRaw = nil
+		}
+	}
+	return out
+}
+
+// NormalizePayerEmail returns the canonical form used to index payer emails.
+func NormalizePayerEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// NormalizePayerDocument keeps only the digits of a CPF/CNPJ.
+func NormalizePayerDocument(doc string) string {
+	var b strings.Builder
+	for _, r := range doc {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Anonymized returns a copy of the payment with every payer personal field replaced by
+// AnonymizedValue. Monetary, fiscal and payment method data are kept for accounting
+// retention. Encrypted values are overwritten as well, so the data key is dropped.
+func (p BillingPayment) Anonymized(at time.Time) BillingPayment {
+	out := p
+	erase := func(_ []string, _ string) (string, error) { return AnonymizedValue, nil }
+
+	if p.MPPayload != nil {
+		out.MPPayload = deepCopyMap(p.MPPayload)
+		_ = WalkSensitivePayload(out.MPPayload, erase)
+	}
+	if len(p.MPPayloadRaw) > 0 {
+		var raw map[string]any
+		if err := json.Unmarshal(p.MPPayloadRaw, &raw); err == nil {
+			_ = WalkSensitivePayload(raw, erase)
+			if b, err := json.Marshal(raw); err == nil {
+				out.MPPayloadRaw = b
+			}
+		}
+	}
+
+	out.Details = p.Details
+	out.Details.Fees = append([]PaymentFee(nil), p.Details.Fees...)
+	out.Details.PayerEmail = ""
+	out.Details.PayerDocNumber = ""
+	out.Details.PayerEmailHash = ""
+	out.Details.PayerDocHash = ""
+	out.DataKey = nil
+	anonymizedAt := at.UTC()
+	out.AnonymizedAt = &anonymizedAt
+	return out
+}
+
+func (d PaymentDetails) redacted() PaymentDetails {
+	out := d
+	out.PayerEmail = maskEmail(d.PayerEmail)
+	out.PayerDocNumber = maskValue(d.PayerDocNumber)
+	out.Fees = append([]PaymentFee(nil), d.Fees...)
+	return out
+}
+
+// WalkSensitivePayload calls fn for every sensitive value present in the payload,
+// replacing it with the returned value. Numbers (payer id, street number) are passed as
+// their decimal text and stay strings afterwards. The map is modified in place.
+func WalkSensitivePayload(payload map[string]any, fn func(path []string, value string) (string, error)) error {
+	for _, path := range SensitivePayloadPaths {
+		parent := payload
+		for _, key := range path[:len(path)-1] {
+			next, ok := parent[key].(map[string]any)
+			if !ok {
+				parent = nil
+				break
+			}
+			parent = next
+		}
+		if parent == nil {
+			continue
+		}
+		if err := walkSensitiveValue(parent, path[len(path)-1], path, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkSensitiveValue applies fn to parent[key], or to every value inside it when it is
+// an object.
+func walkSensitiveValue(parent map[string]any, key string, path []string, fn func(path []string, value string) (string, error)) error {
+	var v string
+	switch val := parent[key].(type) {
+	case map[string]any:
+		for child := range val {
+			if err := walkSensitiveValue(val, child, append(path[:len(path):len(path)], child), fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	case string:
+		v = val
+	case float64:
+		v = strconv.FormatFloat(val, 'f', -1, 64)
+	case json.Number:
+		v = val.String()
+	default:
+		return nil
+	}
+	if v == "" {
+		return nil
+	}
+	replaced, err := fn(path, v)
+	if err != nil {
+		return err
+	}
+	parent[key] = replaced
+	return nil
+}
+
+func redactPayloadMap(payload map[string]any) map[string]any {
+	cp := deepCopyMap(payload)
+	_ = WalkSensitivePayload(cp, func(path []string, v string) (string, error) {
+		if path[len(path)-1] == "email" {
+			return maskEmail(v), nil
+		}
+		return maskValue(v), nil
+	})
+	return cp
+}
+
+func maskValue(v string) string {
+	if v == "" {
+		return ""
+	}
+	return redactedValue
+}
+
+// maskEmail keeps the first letter and the domain so support can still recognize the payer.
+func maskEmail(v string) string {
+	if v == "" || IsEncrypted(v) {
+		return maskValue(v)
+	}
+	at := strings.LastIndex(v, "@")
+	if at <= 0 {
+		return redactedValue
+	}
+	return v[:1] + redactedValue + v[at:]
+}
+
+func deepCopyMap(m map[string]any) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = deepCopyValue(v)
+	}
+	return out
+}
+
+func deepCopyValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		return deepCopyMap(val)
+	case []any:
+		cp := make([]any, len(val))
+		for i, item := range val {
+			cp[i] = deepCopyValue(item)
+		}
+		return cp
+	default:
+		return v
+	}
+}
@@ -0,0 +1,50 @@
+package entities
+
+import "time"
+
+// PaymentEventSource identifies what triggered a payment status change.
+type PaymentEventSource string
+
+const (
+	PaymentEventSourceAPI        PaymentEventSource = "api"
+	PaymentEventSourceWebhook    PaymentEventSource = "webhook"
+	PaymentEventSourceReconciler PaymentEventSource = "reconciler"
+	PaymentEventSourceAdmin      PaymentEventSource = "admin"
+)
+
+// IsValid reports whether s is one of the known event sources.
+func (s PaymentEventSource) IsValid() bool {
+	switch s {
+	case PaymentEventSourceAPI, PaymentEventSourceWebhook, PaymentEventSourceReconciler, PaymentEventSourceAdmin:
+		return true
+	}
+	return false
+}
+
+// PaymentStatusEvent is an append-only entry of a payment status timeline.
+//
+// Storage model (DynamoDB):
+//   - PK: payment_id
+//   - SK: event_key (created_at + id, sortable)
+//
+// ProviderStatus/ProviderStatusDetail keep the raw Mercado Pago values (e.g. "approved" /
+// "accredited") so disputes can be checked against the provider dashboard.
+type PaymentStatusEvent struct {
+	ID                   string             `json:"id"`
+	PaymentID            string             `json:"payment_id"`
+	Status               PaymentStatus      `json:"status"`
+	PreviousStatus       PaymentStatus      `json:"previous_status,omitempty"`
+	Source               PaymentEventSource `json:"source"`
+	Actor                string             `json:"actor,omitempty"`
+	ProviderStatus       string             `json:"provider_status,omitempty"`
+	ProviderStatusDetail string             `json:"provider_status_detail,omitempty"`
+	CreatedAt            time.Time          `json:"created_at"`
+}
+
+// PaymentPosting is what a new payment writes besides itself: its first status event
+// and, when money moves, its ledger entry. A posting that cannot be written with the
+// payment waits in the payment outbox until it is completed.
+type PaymentPosting struct {
+	Event PaymentStatusEvent
+	Entry *LedgerEntry
+}
@@ -0,0 +1,250 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+var ErrInvalidPricingRule = errors.New("invalid pricing rule")
+
+// PricingRuleType is the policy a pricing rule implements.
+type PricingRuleType string
+
+const (
+	// PricingSurcharge adds Percent to the lines of ItemKind when the estimate is made on
+	// one of Weekdays or outside BusinessHours.
+	PricingSurcharge PricingRuleType = "acrescimo_horario"
+	// PricingSegmentDiscount takes Percent off the lines of ItemKind for the customer
+	// Segments (e.g. fleet customers).
+	PricingSegmentDiscount PricingRuleType = "desconto_segmento"
+	// PricingMinimumLabor raises the service lines, in proportion, to Amount in total.
+	PricingMinimumLabor PricingRuleType = "minimo_mao_de_obra"
+	// PricingPartsMarkup adds Percent to the parts of Categories (every part when empty).
+	PricingPartsMarkup PricingRuleType = "markup_pecas"
+)
+
+// BusinessHours is a daily [Opens, Closes) period, in minutes since midnight.
+type BusinessHours struct {
+	Opens  int
+	Closes int
+}
+
+func (h BusinessHours) contains(at time.Time) bool {
+	minute := at.Hour()*60 + at.Minute()
+	return minute >= h.Opens && minute < h.Closes
+}
+
+func (h BusinessHours) String() string {
+	return fmt.Sprintf("%02d:%02d–%02d:%02d", h.Opens/60, h.Opens%60, h.Closes/60, h.Closes%60)
+}
+
+// PricingRule is one pricing policy; which fields apply depends on Type.
+type PricingRule struct {
+	ID            string
+	Name          string
+	Type          PricingRuleType
+	Percent       float64
+	Amount        float64
+	ItemKind      EstimateItemKind
+	Categories    []string
+	Segments      []string
+	Weekdays      []time.Weekday
+	BusinessHours *BusinessHours
+}
+
+func (r PricingRule) IsValid() bool {
+	if strings.TrimSpace(r.ID) == "" {
+		return false
+	}
+	if r.ItemKind != "" && r.ItemKind != EstimateItemServico && r.ItemKind != EstimateItemPeca {
+		return false
+	}
+	switch r.Type {
+	case PricingSurcharge:
+		hours := r.BusinessHours
+		validHours := hours == nil || (hours.Opens >= 0 && hours.Opens < hours.Closes && hours.Closes <= 24*60)
+		return r.Percent > 0 && validHours && (len(r.Weekdays) > 0 || hours != nil)
+	case PricingSegmentDiscount:
+		return r.Percent > 0 && r.Percent <= 100 && len(r.Segments) > 0
+	case PricingMinimumLabor:
+		return r.Amount > 0
+	case PricingPartsMarkup:
+		return r.Percent > 0
+	default:
+		return false
+	}
+}
+
+// PricingRuleSet is a version of the pricing rules. Rules are evaluated in order, each on
+// the lines as left by the previous ones; schedules are read in Location.
+type PricingRuleSet struct {
+	Version  string
+	Location *time.Location
+	Rules    []PricingRule
+}
+
+// Validate rejects invalid rules and repeated rule IDs.
+func (s PricingRuleSet) Validate() error {
+	if strings.TrimSpace(s.Version) == "" && len(s.Rules) > 0 {
+		return ErrInvalidPricingRule
+	}
+	seen := map[string]bool{}
+	for _, r := range s.Rules {
+		if !r.IsValid() || seen[r.ID] {
+			return ErrInvalidPricingRule
+		}
+		seen[r.ID] = true
+	}
+	return nil
+}
+
+// PricingContext is what rules are evaluated against besides the items.
+type PricingContext struct {
+	At              time.Time
+	CustomerSegment string
+}
+
+// PricingAdjustment explains what a rule changed: Amount is added to the estimate
+// (negative for discounts) and Reason tells why the rule applied.
+type PricingAdjustment struct {
+	RuleID string          `json:"rule_id"`
+	Name   string          `json:"name"`
+	Type   PricingRuleType `json:"type"`
+	Reason string          `json:"reason"`
+	Amount float64         `json:"amount"`
+}
+
+// Apply evaluates the rules on the items and returns the adjusted items (Total includes
+// the line Adjustment), the adjustments made and their total. Rules that change nothing
+// are left out.
+func (s PricingRuleSet) Apply(items []EstimateItem, pc PricingContext) ([]EstimateItem, []PricingAdjustment, float64) {
+	lines := make([]EstimateItem, len(items))
+	for i, item := range items {
+		item.Adjustment = 0
+		lines[i] = item
+	}
+	loc := s.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	at := pc.At.In(loc)
+
+	var adjustments []PricingAdjustment
+	total := 0.0
+	for _, r := range s.Rules {
+		var amount float64
+		var reason string
+		switch r.Type {
+		case PricingSurcharge:
+			if reason = r.scheduleReason(at); reason != "" {
+				amount = adjustLines(lines, r.Percent, func(l EstimateItem) bool { return r.ItemKind == "" || l.Kind == r.ItemKind })
+			}
+		case PricingSegmentDiscount:
+			if containsFold(r.Segments, pc.CustomerSegment) {
+				reason = "cliente " + strings.ToLower(strings.TrimSpace(pc.CustomerSegment))
+				amount = adjustLines(lines, -r.Percent, func(l EstimateItem) bool { return r.ItemKind == "" || l.Kind == r.ItemKind })
+			}
+		case PricingMinimumLabor:
+			amount = raiseLabor(lines, r.Amount)
+			reason = fmt.Sprintf("mão de obra abaixo do mínimo de %.2f", r.Amount)
+		case PricingPartsMarkup:
+			amount = adjustLines(lines, r.Percent, func(l EstimateItem) bool {
+				return l.Kind == EstimateItemPeca && (len(r.Categories) == 0 || containsFold(r.Categories, l.Category))
+			})
+			reason = "peças"
+			if len(r.Categories) > 0 {
+				reason += " de " + strings.Join(r.Categories, ", ")
+			}
+		}
+		if amount == 0 {
+			continue
+		}
+		adjustments = append(adjustments, PricingAdjustment{RuleID: r.ID, Name: r.Name, Type: r.Type, Reason: reason, Amount: amount})
+		total += amount
+	}
+	return lines, adjustments, roundCents(total)
+}
+
+// Quoted returns the line as quoted, before pricing rules, discounts and taxes, to be priced
+// again.
+func (i EstimateItem) Quoted() EstimateItem {
+	i.Total = roundCents(i.Total - i.Adjustment)
+	i.Adjustment, i.Discount, i.Taxes = 0, 0, nil
+	return i
+}
+
+var weekdayNames = [...]string{"domingo", "segunda", "terça", "quarta", "quinta", "sexta", "sábado"}
+
+func (r PricingRule) scheduleReason(at time.Time) string {
+	for _, d := range r.Weekdays {
+		if at.Weekday() == d {
+			return weekdayNames[d]
+		}
+	}
+	if r.BusinessHours != nil && !r.BusinessHours.contains(at) {
+		return "fora do horário " + r.BusinessHours.String()
+	}
+	return ""
+}
+
+// adjustLines changes the matching lines by percent of their total and returns the amount.
+func adjustLines(lines []EstimateItem, percent float64, match func(EstimateItem) bool) float64 {
+	total := 0.0
+	for i, line := range lines {
+		if !match(line) || line.Total <= 0 {
+			continue
+		}
+		delta := math.Max(roundCents(line.Total*percent/100), -line.Total)
+		lines[i].Total = roundCents(line.Total + delta)
+		lines[i].Adjustment = roundCents(line.Adjustment + delta)
+		total += delta
+	}
+	return roundCents(total)
+}
+
+// raiseLabor spreads what the service lines miss to reach minimum over them, in proportion
+// to their totals. Estimates without services are left alone.
+func raiseLabor(lines []EstimateItem, minimum float64) float64 {
+	labor := 0.0
+	last := -1
+	for i, line := range lines {
+		if line.Kind == EstimateItemServico && line.Total > 0 {
+			labor += line.Total
+			last = i
+		}
+	}
+	missing := roundCents(minimum - labor)
+	if last < 0 || missing <= 0 {
+		return 0
+	}
+	allocated := 0.0
+	for i, line := range lines {
+		if line.Kind != EstimateItemServico || line.Total <= 0 {
+			continue
+		}
+		share := roundCents(missing * line.Total / labor)
+		if i == last {
+			share = roundCents(missing - allocated)
+		}
+		lines[i].Total = roundCents(line.Total + share)
+		lines[i].Adjustment = roundCents(line.Adjustment + share)
+		allocated += share
+	}
+	return missing
+}
+
+func containsFold(values []string, v string) bool {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return false
+	}
+	for _, candidate := range values {
+		if strings.EqualFold(strings.TrimSpace(candidate), v) {
+			return true
+		}
+	}
+	return false
+}
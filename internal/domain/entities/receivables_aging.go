@@ -0,0 +1,62 @@
+package entities
+
+import "time"
+
+// AgingBucket labels, in report order.
+const (
+	AgingBucket0To30  = "0-30"
+	AgingBucket31To60 = "31-60"
+	AgingBucket61To90 = "61-90"
+	AgingBucketOver90 = "90+"
+)
+
+// AgingBuckets are the receivables aging buckets, in report order.
+var AgingBuckets = []string{AgingBucket0To30, AgingBucket31To60, AgingBucket61To90, AgingBucketOver90}
+
+// AgingBucketFor returns the bucket of a balance outstanding for the given number of days.
+func AgingBucketFor(days int) string {
+	switch {
+	case days <= 30:
+		return AgingBucket0To30
+	case days <= 60:
+		return AgingBucket31To60
+	case days <= 90:
+		return AgingBucket61To90
+	default:
+		return AgingBucketOver90
+	}
+}
+
+// PaymentSettlesEstimate tells whether a payment in the given status counts towards the
+// estimate price. Payments under dispute still count until the chargeback is lost.
+func PaymentSettlesEstimate(s PaymentStatus) bool {
+	return s == PaymentStatusAprovado || s == PaymentStatusContestado
+}
+
+// ReceivableItem is the outstanding balance of one approved estimate (one service order).
+type ReceivableItem struct {
+	EstimateID      string    `json:"estimate_id"`
+	OSID            string    `json:"os_id"`
+	ApprovedAt      time.Time `json:"approved_at"`
+	DaysOutstanding int       `json:"days_outstanding"`
+	Bucket          string    `json:"bucket"`
+	Price           float64   `json:"price"`
+	Paid            float64   `json:"paid"`
+	Outstanding     float64   `json:"outstanding"`
+	PaymentIDs      []string  `json:"payment_ids"`
+}
+
+// AgingBucketTotals totals the receivables of one bucket.
+type AgingBucketTotals struct {
+	Bucket      string  `json:"bucket"`
+	Count       int     `json:"count"`
+	Outstanding float64 `json:"outstanding"`
+}
+
+// ReceivablesAgingReport lists approved estimates not fully paid as of AsOf, oldest first.
+type ReceivablesAgingReport struct {
+	AsOf             time.Time           `json:"as_of"`
+	Buckets          []AgingBucketTotals `json:"buckets"`
+	Items            []ReceivableItem    `json:"items"`
+	TotalOutstanding float64             `json:"total_outstanding"`
+}
@@ -0,0 +1,108 @@
+package entities
+
+import "time"
+
+// SettlementLineType classifies a line of the provider settlement/release report.
+//
+// Only payment, refund and chargeback lines are reconciled against BillingPayment;
+// payouts, reserves and other movements are counted as ignored.
+
+type SettlementLineType string
+
+const (
+	SettlementLinePayment    SettlementLineType = "payment"
+	SettlementLineRefund     SettlementLineType = "refund"
+	SettlementLineChargeback SettlementLineType = "chargeback"
+	SettlementLineOther      SettlementLineType = "other"
+)
+
+// ExpectedPaymentStatuses lists the payment statuses consistent with the line type.
+// A released payment may have been refunded or disputed afterwards, so every status
+// reachable from "aprovado" is accepted for payment lines.
+func (t SettlementLineType) ExpectedPaymentStatuses() []PaymentStatus {
+	switch t {
+	case SettlementLinePayment:
+		return []PaymentStatus{PaymentStatusAprovado, PaymentStatusReembolsado, PaymentStatusContestado, PaymentStatusEstornado}
+	case SettlementLineRefund:
+		return []PaymentStatus{PaymentStatusReembolsado}
+	case SettlementLineChargeback:
+		return []PaymentStatus{PaymentStatusContestado, PaymentStatusEstornado}
+	}
+	return nil
+}
+
+// SettlementLine is a normalized line of a provider settlement report.
+//
+// SourceID is the provider payment ID (BillingPayment.ID) and ExternalReference the
+// estimate ID we send on payment creation. GrossAmount is always positive.
+type SettlementLine struct {
+	Line              int                `json:"line"`
+	SourceID          string             `json:"source_id"`
+	ExternalReference string             `json:"external_reference,omitempty"`
+	Type              SettlementLineType `json:"type"`
+	ProviderType      string             `json:"provider_type"`
+	GrossAmount       float64            `json:"gross_amount"`
+	NetAmount         float64            `json:"net_amount"`
+	FeeAmount         float64            `json:"fee_amount"`
+	TransactionDate   *time.Time         `json:"transaction_date,omitempty"`
+	SettlementDate    *time.Time         `json:"settlement_date,omitempty"`
+}
+
+// DiscrepancyKind classifies a reconciliation finding.
+
+type DiscrepancyKind string
+
+const (
+	// DiscrepancyMissingPayment: the report has a line with no matching payment.
+	DiscrepancyMissingPayment DiscrepancyKind = "missing_payment"
+	// DiscrepancyMissingInReport: an approved payment inside the report period is absent from the report.
+	DiscrepancyMissingInReport DiscrepancyKind = "missing_in_report"
+	DiscrepancyAmountMismatch  DiscrepancyKind = "amount_mismatch"
+	DiscrepancyStatusMismatch  DiscrepancyKind = "status_mismatch"
+)
+
+// ReconciliationDiscrepancy is a single finding of a reconciliation run.
+type ReconciliationDiscrepancy struct {
+	Kind              DiscrepancyKind    `json:"kind"`
+	Line              int                `json:"line,omitempty"`
+	SourceID          string             `json:"source_id,omitempty"`
+	ExternalReference string             `json:"external_reference,omitempty"`
+	LineType          SettlementLineType `json:"line_type,omitempty"`
+	PaymentID         string             `json:"payment_id,omitempty"`
+	ReportAmount      float64            `json:"report_amount,omitempty"`
+	PaymentAmount     float64            `json:"payment_amount,omitempty"`
+	PaymentStatus     PaymentStatus      `json:"payment_status,omitempty"`
+	Detail            string             `json:"detail"`
+}
+
+// ReconciliationRun is the stored result of reconciling one provider report.
+//
+// PeriodStart/PeriodEnd span the transaction dates of the payment lines; they are nil
+// when the report carries no transaction date (release reports), in which case
+// payments missing from the report are not checked.
+type ReconciliationRun struct {
+	ID            string                      `json:"id"`
+	FileName      string                      `json:"file_name"`
+	Actor         string                      `json:"actor"`
+	PeriodStart   *time.Time                  `json:"period_start,omitempty"`
+	PeriodEnd     *time.Time                  `json:"period_end,omitempty"`
+	TotalLines    int                         `json:"total_lines"`
+	MatchedLines  int                         `json:"matched_lines"`
+	IgnoredLines  int                         `json:"ignored_lines"`
+	Discrepancies []ReconciliationDiscrepancy `json:"discrepancies"`
+	// Summary is the stored CountByKind, available without loading the discrepancies.
+	Summary   map[DiscrepancyKind]int `json:"summary,omitempty"`
+	CreatedAt time.Time               `json:"created_at"`
+}
+
+// CountByKind returns how many discrepancies of each kind the run found.
+func (r ReconciliationRun) CountByKind() map[DiscrepancyKind]int {
+	if r.Summary != nil {
+		return r.Summary
+	}
+	counts := map[DiscrepancyKind]int{}
+	for _, d := range r.Discrepancies {
+		counts[d.Kind]++
+	}
+	return counts
+}
@@ -0,0 +1,173 @@
+package entities
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+var ErrInvalidTaxRate = errors.New("invalid tax rate")
+
+// EstimateItemKind tells which taxes apply to an estimate item.
+type EstimateItemKind string
+
+const (
+	EstimateItemServico EstimateItemKind = "servico" // ISS
+	EstimateItemPeca    EstimateItemKind = "peca"    // ICMS / IPI
+)
+
+const (
+	TaxISS  = "ISS"
+	TaxICMS = "ICMS"
+	TaxIPI  = "IPI"
+)
+
+// LineTax is the amount of one tax in an estimate item, with the rate used (percent).
+type LineTax struct {
+	Name   string  `json:"name"`
+	Rate   float64 `json:"rate"`
+	Amount float64 `json:"amount"`
+}
+
+// TaxAmount is the total of one tax over the estimate.
+type TaxAmount struct {
+	Name   string  `json:"name"`
+	Amount float64 `json:"amount"`
+}
+
+// EstimateItem is a service or part of an estimate. Total is the gross amount of the line,
+// including the Adjustment made by pricing rules, and Discount its share of the estimate
+// discounts (see ApplyDiscounts); Taxes are the part of the discounted amount due as each
+// tax. Code is the catalog service code or part SKU; Category groups parts for pricing
+// rules.
+//
+// Labor lines are services quoted in Hours by a mechanic SkillTier: their price is the
+// hours times the tier HourlyRate, recomputed from ActualHours once the work is done (see
+// PriceLabor).
+type EstimateItem struct {
+	ID          string           `json:"id,omitempty"`
+	Kind        EstimateItemKind `json:"kind"`
+	Code        string           `json:"code,omitempty"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Category    string           `json:"category,omitempty"`
+	Hours       float64          `json:"hours,omitempty"`
+	ActualHours float64          `json:"actual_hours,omitempty"`
+	SkillTier   string           `json:"skill_tier,omitempty"`
+	HourlyRate  float64          `json:"hourly_rate,omitempty"`
+	Quantity    int              `json:"quantity"`
+	UnitPrice   float64          `json:"unit_price"`
+	Total       float64          `json:"total"`
+	Adjustment  float64          `json:"adjustment"`
+	Discount    float64          `json:"discount"`
+	Taxes       []LineTax        `json:"taxes"`
+}
+
+// Net is the amount charged for the line after discounts.
+func (i EstimateItem) Net() float64 {
+	return roundCents(i.Total - i.Discount)
+}
+
+// TaxRate is the rate (percent) of a tax for an item kind, effective in
+// [EffectiveFrom, EffectiveTo). An empty Municipality (IBGE code) applies where no
+// municipality-specific rate exists.
+type TaxRate struct {
+	Tax           string           `json:"tax"`
+	ItemKind      EstimateItemKind `json:"item_kind"`
+	Municipality  string           `json:"municipality,omitempty"`
+	Rate          float64          `json:"rate"`
+	EffectiveFrom time.Time        `json:"effective_from"`
+	EffectiveTo   *time.Time       `json:"effective_to,omitempty"`
+}
+
+func (r TaxRate) effectiveAt(at time.Time) bool {
+	return !at.Before(r.EffectiveFrom) && (r.EffectiveTo == nil || at.Before(*r.EffectiveTo))
+}
+
+// TaxTable holds the configured rates. Estimates without a municipality are taxed as
+// DefaultMunicipality.
+type TaxTable struct {
+	DefaultMunicipality string    `json:"default_municipality"`
+	Rates               []TaxRate `json:"rates"`
+}
+
+// Validate checks every rate; overlapping periods of the same tax, kind and municipality
+// are rejected since the rate of a date would be ambiguous.
+func (t TaxTable) Validate() error {
+	for i, r := range t.Rates {
+		if r.Tax == "" || (r.ItemKind != EstimateItemServico && r.ItemKind != EstimateItemPeca) ||
+			r.Rate < 0 || r.Rate > 100 || r.EffectiveFrom.IsZero() ||
+			(r.EffectiveTo != nil && !r.EffectiveTo.After(r.EffectiveFrom)) {
+			return ErrInvalidTaxRate
+		}
+		for _, o := range t.Rates[:i] {
+			if o.Tax == r.Tax && o.ItemKind == r.ItemKind && o.Municipality == r.Municipality && overlaps(o, r) {
+				return ErrInvalidTaxRate
+			}
+		}
+	}
+	return nil
+}
+
+func overlaps(a, b TaxRate) bool {
+	aEndsAfterB := a.EffectiveTo == nil || a.EffectiveTo.After(b.EffectiveFrom)
+	bEndsAfterA := b.EffectiveTo == nil || b.EffectiveTo.After(a.EffectiveFrom)
+	return aEndsAfterB && bEndsAfterA
+}
+
+// RatesFor returns the rates applying to an item kind in a municipality at a date, one per
+// tax, in tax name order. A municipality-specific rate wins over the general one.
+func (t TaxTable) RatesFor(municipality string, kind EstimateItemKind, at time.Time) []TaxRate {
+	if municipality == "" {
+		municipality = t.DefaultMunicipality
+	}
+	byTax := map[string]TaxRate{}
+	for _, r := range t.Rates {
+		if r.ItemKind != kind || !r.effectiveAt(at) {
+			continue
+		}
+		if r.Municipality != "" && r.Municipality != municipality {
+			continue
+		}
+		if current, ok := byTax[r.Tax]; ok && current.Municipality != "" {
+			continue
+		}
+		byTax[r.Tax] = r
+	}
+	rates := make([]TaxRate, 0, len(byTax))
+	for _, r := range byTax {
+		rates = append(rates, r)
+	}
+	sort.Slice(rates, func(i, j int) bool { return rates[i].Tax < rates[j].Tax })
+	return rates
+}
+
+// Apply computes the taxes of each item, on its discounted amount, with the rates
+// effective at a date and returns the taxed items, the totals per tax and the overall
+// tax. Amounts are rounded per line, so the totals add up to the lines.
+func (t TaxTable) Apply(items []EstimateItem, municipality string, at time.Time) ([]EstimateItem, []TaxAmount, float64) {
+	taxed := make([]EstimateItem, len(items))
+	totals := map[string]float64{}
+	for i, item := range items {
+		item.Taxes = []LineTax{}
+		for _, r := range t.RatesFor(municipality, item.Kind, at) {
+			amount := roundCents(item.Net() * r.Rate / 100)
+			item.Taxes = append(item.Taxes, LineTax{Name: r.Tax, Rate: r.Rate, Amount: amount})
+			totals[r.Tax] += amount
+		}
+		taxed[i] = item
+	}
+
+	names := make([]string, 0, len(totals))
+	for name := range totals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	amounts := make([]TaxAmount, 0, len(names))
+	total := 0.0
+	for _, name := range names {
+		amounts = append(amounts, TaxAmount{Name: name, Amount: roundCents(totals[name])})
+		total += totals[name]
+	}
+	return taxed, amounts, roundCents(total)
+}
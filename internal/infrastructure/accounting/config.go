@@ -0,0 +1,123 @@
+package accounting
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+)
+
+// Config is the accounting export configuration, read from the JSON file in
+// ACCOUNTING_CONFIG_FILE. Keys left out of the file keep their defaults.
+type Config struct {
+	TimeZone        string                    `json:"time_zone"`
+	ChartOfAccounts entities.ChartOfAccounts  `json:"chart_of_accounts"`
+	OFX             OFXAccount                `json:"ofx"`
+	FixedWidth      entities.FixedWidthLayout `json:"fixed_width"`
+}
+
+// OFXAccount identifies the bank account of the OFX statement.
+type OFXAccount struct {
+	BankID    string `json:"bank_id"`
+	AccountID string `json:"account_id"`
+}
+
+// DefaultConfig exports in São Paulo time with the default chart of accounts, to a
+// Mercado Pago (bank 323) statement.
+func DefaultConfig() Config {
+	return Config{
+		TimeZone:        "America/Sao_Paulo",
+		ChartOfAccounts: entities.DefaultChartOfAccounts,
+		OFX:             OFXAccount{BankID: "0323", AccountID: entities.DefaultChartOfAccounts.Bank},
+		FixedWidth: entities.FixedWidthLayout{
+			DateFormat: "02012006",
+			Fields: []entities.FixedWidthField{
+				{Name: "date", Width: 8},
+				{Name: "kind", Width: 10},
+				{Name: "debit_account", Width: 20},
+				{Name: "credit_account", Width: 20},
+				{Name: "amount", Width: 15, Align: "right", Pad: "0"},
+				{Name: "payment_id", Width: 20},
+				{Name: "estimate_id", Width: 36},
+				{Name: "description", Width: 60},
+			},
+		},
+	}
+}
+
+// LoadConfigFromEnv reads ACCOUNTING_CONFIG_FILE, falling back to DefaultConfig when unset.
+func LoadConfigFromEnv() (Config, error) {
+	cfg := DefaultConfig()
+	path := strings.TrimSpace(os.Getenv("ACCOUNTING_CONFIG_FILE"))
+	if path == "" {
+		return cfg, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("read accounting config: %w", err)
+	}
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return Config{}, fmt.Errorf("parse accounting config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+var fixedWidthFieldNames = map[string]bool{
+	"date": true, "entry_id": true, "kind": true, "debit_account": true, "credit_account": true,
+	"amount": true, "payment_id": true, "estimate_id": true, "payment_method_id": true,
+	"description": true, "filler": true,
+}
+
+// Validate checks that every account is mapped and the fixed-width layout is usable.
+func (c Config) Validate() error {
+	chart := c.ChartOfAccounts
+	if chart.Bank == "" || chart.Revenue == "" || chart.Refunds == "" || chart.Fees == "" {
+		return fmt.Errorf("invalid accounting config: chart_of_accounts requires bank, revenue, refunds and fees")
+	}
+	if len(c.FixedWidth.Fields) == 0 {
+		return fmt.Errorf("invalid accounting config: fixed_width.fields is empty")
+	}
+	for i, f := range c.FixedWidth.Fields {
+		if !fixedWidthFieldNames[f.Name] {
+			return fmt.Errorf("invalid accounting config: fixed_width.fields[%d]: unknown field %q", i, f.Name)
+		}
+		if f.Width <= 0 {
+			return fmt.Errorf("invalid accounting config: fixed_width.fields[%d]: width must be positive", i)
+		}
+		if f.Align != "" && f.Align != "left" && f.Align != "right" {
+			return fmt.Errorf("invalid accounting config: fixed_width.fields[%d]: align must be left or right", i)
+		}
+		if len([]rune(f.Pad)) > 1 {
+			return fmt.Errorf("invalid accounting config: fixed_width.fields[%d]: pad must be a single character", i)
+		}
+	}
+	return nil
+}
+
+// Location returns the export time zone. Without a tz database it falls back to a fixed
+// UTC-3 offset, the São Paulo time (no daylight saving time since 2019).
+func (c Config) Location() *time.Location {
+	if loc, err := time.LoadLocation(c.TimeZone); err == nil {
+		return loc
+	}
+	log.Printf("[payment][accounting] tz database unavailable; using fixed UTC-3 for %s", c.TimeZone)
+	return time.FixedZone(c.TimeZone, -3*60*60)
+}
+
+// NewFormatters builds one formatter per supported export format.
+func NewFormatters(cfg Config) map[entities.AccountingExportFormat]interfaces.IJournalFormatter {
+	loc := cfg.Location()
+	return map[entities.AccountingExportFormat]interfaces.IJournalFormatter{
+		entities.AccountingExportCSV:        &CSVFormatter{Location: loc},
+		entities.AccountingExportOFX:        &OFXFormatter{Location: loc, Account: cfg.OFX},
+		entities.AccountingExportFixedWidth: &FixedWidthFormatter{Location: loc, Layout: cfg.FixedWidth},
+	}
+}
@@ -0,0 +1,175 @@
+package accounting
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+)
+
+var (
+	_ interfaces.IJournalFormatter = (*CSVFormatter)(nil)
+	_ interfaces.IJournalFormatter = (*OFXFormatter)(nil)
+	_ interfaces.IJournalFormatter = (*FixedWidthFormatter)(nil)
+)
+
+func formatAmount(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+// CSVFormatter writes one row per journal entry, dates as YYYY-MM-DD in Location.
+type CSVFormatter struct {
+	Location *time.Location
+}
+
+func (f *CSVFormatter) ContentType() string   { return "text/csv; charset=utf-8" }
+func (f *CSVFormatter) FileExtension() string { return "csv" }
+
+func (f *CSVFormatter) Write(w io.Writer, _ entities.AccountingExport, entries []entities.JournalEntry) error {
+	cw := csv.NewWriter(w)
+	rows := [][]string{{"date", "entry_id", "kind", "debit_account", "credit_account", "amount", "payment_id", "estimate_id", "payment_method_id", "description"}}
+	for _, e := range entries {
+		rows = append(rows, []string{
+			e.Date.In(f.Location).Format(time.DateOnly),
+			e.ID,
+			string(e.Kind),
+			e.DebitAccount,
+			e.CreditAccount,
+			formatAmount(e.Amount),
+			e.PaymentID,
+			e.EstimateID,
+			e.PaymentMethodID,
+			e.Description,
+		})
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// OFXFormatter writes an OFX 1.0.2 bank statement of the provider balance account:
+// payments are credits, fees and refunds are debits. The ledger balance is the net
+// movement of the export, since the service does not know the account balance.
+type OFXFormatter struct {
+	Location *time.Location
+	Account  OFXAccount
+}
+
+func (f *OFXFormatter) ContentType() string   { return "application/x-ofx" }
+func (f *OFXFormatter) FileExtension() string { return "ofx" }
+
+func (f *OFXFormatter) Write(w io.Writer, run entities.AccountingExport, entries []entities.JournalEntry) error {
+	bw := bufio.NewWriter(w)
+	date := func(t time.Time) string {
+		t = t.In(f.Location)
+		_, offset := t.Zone()
+		return fmt.Sprintf("%s[%d]", t.Format("20060102150405"), offset/3600)
+	}
+
+	fmt.Fprint(bw, "OFXHEADER:100\nDATA:OFXSGML\nVERSION:102\nSECURITY:NONE\nENCODING:UTF-8\nCHARSET:NONE\nCOMPRESSION:NONE\nOLDFILEUID:NONE\nNEWFILEUID:NONE\n\n")
+	fmt.Fprint(bw, "<OFX>\n<SIGNONMSGSRSV1>\n<SONRS>\n<STATUS>\n<CODE>0\n<SEVERITY>INFO\n</STATUS>\n")
+	fmt.Fprintf(bw, "<DTSERVER>%s\n<LANGUAGE>POR\n</SONRS>\n</SIGNONMSGSRSV1>\n", date(run.CreatedAt))
+	fmt.Fprintf(bw, "<BANKMSGSRSV1>\n<STMTTRNRS>\n<TRNUID>%s\n<STATUS>\n<CODE>0\n<SEVERITY>INFO\n</STATUS>\n", ofxText(run.ID))
+	fmt.Fprint(bw, "<STMTRS>\n<CURDEF>BRL\n<BANKACCTFROM>\n")
+	fmt.Fprintf(bw, "<BANKID>%s\n<ACCTID>%s\n<ACCTTYPE>CHECKING\n</BANKACCTFROM>\n", ofxText(f.Account.BankID), ofxText(f.Account.AccountID))
+	fmt.Fprintf(bw, "<BANKTRANLIST>\n<DTSTART>%s\n<DTEND>%s\n", date(run.From), date(run.To))
+
+	balance := 0.0
+	for _, e := range entries {
+		trnType, amount := "CREDIT", e.Amount
+		if e.Kind != entities.JournalEntryPayment {
+			trnType, amount = "DEBIT", -e.Amount
+		}
+		balance += amount
+		fmt.Fprintf(bw, "<STMTTRN>\n<TRNTYPE>%s\n<DTPOSTED>%s\n<TRNAMT>%s\n<FITID>%s\n<MEMO>%s\n</STMTTRN>\n",
+			trnType, date(e.Date), formatAmount(amount), ofxText(e.ID), ofxText(e.Description))
+	}
+
+	fmt.Fprint(bw, "</BANKTRANLIST>\n<LEDGERBAL>\n")
+	fmt.Fprintf(bw, "<BALAMT>%s\n<DTASOF>%s\n</LEDGERBAL>\n", formatAmount(balance), date(run.To))
+	fmt.Fprint(bw, "</STMTRS>\n</STMTTRNRS>\n</BANKMSGSRSV1>\n</OFX>\n")
+	return bw.Flush()
+}
+
+var ofxReplacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\n", " ", "\r", " ")
+
+func ofxText(s string) string {
+	return ofxReplacer.Replace(s)
+}
+
+// FixedWidthFormatter writes one line per journal entry following Layout. Values longer
+// than their field are truncated; amounts are written in cents.
+type FixedWidthFormatter struct {
+	Location *time.Location
+	Layout   entities.FixedWidthLayout
+}
+
+func (f *FixedWidthFormatter) ContentType() string   { return "text/plain; charset=utf-8" }
+func (f *FixedWidthFormatter) FileExtension() string { return "txt" }
+
+func (f *FixedWidthFormatter) Write(w io.Writer, _ entities.AccountingExport, entries []entities.JournalEntry) error {
+	bw := bufio.NewWriter(w)
+	for _, e := range entries {
+		var line strings.Builder
+		for _, field := range f.Layout.Fields {
+			line.WriteString(fixedWidth(f.value(field.Name, e), field))
+		}
+		line.WriteString("\r\n")
+		if _, err := bw.WriteString(line.String()); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func (f *FixedWidthFormatter) value(name string, e entities.JournalEntry) string {
+	switch name {
+	case "date":
+		return e.Date.In(f.Location).Format(f.Layout.DateFormat)
+	case "entry_id":
+		return e.ID
+	case "kind":
+		return string(e.Kind)
+	case "debit_account":
+		return e.DebitAccount
+	case "credit_account":
+		return e.CreditAccount
+	case "amount":
+		return strconv.FormatInt(int64(math.Round(e.Amount*100)), 10)
+	case "payment_id":
+		return e.PaymentID
+	case "estimate_id":
+		return e.EstimateID
+	case "payment_method_id":
+		return e.PaymentMethodID
+	case "description":
+		return e.Description
+	default:
+		return ""
+	}
+}
+
+func fixedWidth(value string, field entities.FixedWidthField) string {
+	value = strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+	runes := []rune(value)
+	if len(runes) > field.Width {
+		return string(runes[:field.Width])
+	}
+	pad := " "
+	if field.Pad != "" {
+		pad = field.Pad
+	}
+	padding := strings.Repeat(pad, field.Width-len(runes))
+	if field.Align == "right" {
+		return padding + value
+	}
+	return value + padding
+}
@@ -0,0 +1,106 @@
+package accounting
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+)
+
+func testJournal() (entities.AccountingExport, []entities.JournalEntry) {
+	at := time.Date(2026, 3, 2, 1, 30, 0, 0, time.UTC) // 2026-03-01 22:30 in São Paulo
+	run := entities.AccountingExport{ID: "run-1", From: at.Add(-time.Hour), To: at.Add(time.Hour), CreatedAt: at.Add(2 * time.Hour)}
+	return run, []entities.JournalEntry{
+		{ID: "ev1-payment", Kind: entities.JournalEntryPayment, Date: at, DebitAccount: "1.1.1.02", CreditAccount: "3.1.1.01", Amount: 150, PaymentID: "111", EstimateID: "e1", PaymentMethodID: "pix", Description: "Recebimento orçamento e1"},
+		{ID: "ev1-fee", Kind: entities.JournalEntryFee, Date: at, DebitAccount: "4.1.2.05", CreditAccount: "1.1.1.02", Amount: 1.49, PaymentID: "111", EstimateID: "e1", Description: "Tarifa <pix> & cia"},
+	}
+}
+
+func TestFormatters(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.TimeZone = "UTC-3"
+	formatters := NewFormatters(cfg)
+	run, entries := testJournal()
+
+	render := func(format entities.AccountingExportFormat) string {
+		var buf bytes.Buffer
+		if err := formatters[format].Write(&buf, run, entries); err != nil {
+			t.Fatalf("%s: unexpected error: %v", format, err)
+		}
+		return buf.String()
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		out := render(entities.AccountingExportCSV)
+		if !strings.Contains(out, "2026-03-01,ev1-payment,payment,1.1.1.02,3.1.1.01,150.00,111,e1,pix,Recebimento orçamento e1") {
+			t.Fatalf("unexpected csv:\n%s", out)
+		}
+	})
+
+	t.Run("ofx", func(t *testing.T) {
+		out := render(entities.AccountingExportOFX)
+		for _, want := range []string{
+			"OFXHEADER:100",
+			"<BANKID>0323\n<ACCTID>1.1.1.02",
+			"<TRNTYPE>CREDIT\n<DTPOSTED>20260301223000[-3]\n<TRNAMT>150.00\n<FITID>ev1-payment",
+			"<TRNTYPE>DEBIT\n<DTPOSTED>20260301223000[-3]\n<TRNAMT>-1.49",
+			"<MEMO>Tarifa &lt;pix&gt; &amp; cia",
+			"<BALAMT>148.51",
+		} {
+			if !strings.Contains(out, want) {
+				t.Fatalf("missing %q in ofx:\n%s", want, out)
+			}
+		}
+	})
+
+	t.Run("fixed width", func(t *testing.T) {
+		lines := strings.Split(strings.TrimSuffix(render(entities.AccountingExportFixedWidth), "\r\n"), "\r\n")
+		if len(lines) != 2 {
+			t.Fatalf("expected 2 lines, got %q", lines)
+		}
+		for _, l := range lines {
+			if n := len([]rune(l)); n != 189 {
+				t.Fatalf("expected 189 columns, got %d: %q", n, l)
+			}
+		}
+		if !strings.HasPrefix(lines[1], "01032026fee       4.1.2.05            1.1.1.02            000000000000149111") {
+			t.Fatalf("unexpected line: %q", lines[1])
+		}
+	})
+}
+
+func TestLoadConfigFromEnv(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		t.Setenv("ACCOUNTING_CONFIG_FILE", "")
+		cfg, err := LoadConfigFromEnv()
+		if err != nil || cfg.ChartOfAccounts.Revenue != entities.DefaultChartOfAccounts.Revenue {
+			t.Fatalf("unexpected config: %+v err=%v", cfg, err)
+		}
+	})
+
+	t.Run("file overrides", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "accounting.json")
+		_ = os.WriteFile(path, []byte(`{"chart_of_accounts":{"bank":"1.1","bank_by_payment_method":{"bolbradesco":"1.2"},"revenue":"3.1","refunds":"3.2","fees":"4.1"}}`), 0o600)
+		t.Setenv("ACCOUNTING_CONFIG_FILE", path)
+		cfg, err := LoadConfigFromEnv()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.ChartOfAccounts.BankFor("bolbradesco") != "1.2" || cfg.ChartOfAccounts.BankFor("pix") != "1.1" || len(cfg.FixedWidth.Fields) == 0 {
+			t.Fatalf("unexpected config: %+v", cfg)
+		}
+	})
+
+	t.Run("invalid layout", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "accounting.json")
+		_ = os.WriteFile(path, []byte(`{"fixed_width":{"fields":[{"name":"cpf","width":11}]}}`), 0o600)
+		t.Setenv("ACCOUNTING_CONFIG_FILE", path)
+		if _, err := LoadConfigFromEnv(); err == nil || !strings.Contains(err.Error(), "unknown field") {
+			t.Fatalf("expected unknown field error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,222 @@
+package fiscal
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/beevik/etree"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+)
+
+const abrasfNS = "http://www.abrasf.org.br/nfse.xsd"
+
+// ABRASF 2.04 codes and field limits.
+const (
+	abrasfSim = 1
+	abrasfNao = 2
+
+	rpsTipoRPS          = 1
+	rpsStatusNormal     = 1
+	exigibilidadeISS    = 1 // exigível
+	maxDiscriminacao    = 2000
+	maxRazaoSocial      = 150
+	abrasfDateLayout    = "2006-01-02"
+	abrasfAmountDecimal = 2
+)
+
+type abrasfCpfCnpj struct {
+	Cpf  string `xml:"Cpf,omitempty"`
+	Cnpj string `xml:"Cnpj,omitempty"`
+}
+
+type abrasfEndereco struct {
+	Endereco        string `xml:"Endereco"`
+	Numero          string `xml:"Numero"`
+	Complemento     string `xml:"Complemento,omitempty"`
+	Bairro          string `xml:"Bairro"`
+	CodigoMunicipio string `xml:"CodigoMunicipio"`
+	Uf              string `xml:"Uf"`
+	Cep             string `xml:"Cep"`
+}
+
+type abrasfTomador struct {
+	CpfCnpj     abrasfCpfCnpj   `xml:"IdentificacaoTomador>CpfCnpj"`
+	RazaoSocial string          `xml:"RazaoSocial"`
+	Endereco    *abrasfEndereco `xml:"Endereco,omitempty"`
+	Email       string          `xml:"Contato>Email,omitempty"`
+}
+
+type abrasfValores struct {
+	ValorServicos string `xml:"ValorServicos"`
+	ValorIss      string `xml:"ValorIss"`
+	Aliquota      string `xml:"Aliquota"`
+}
+
+type abrasfServico struct {
+	Valores                   abrasfValores `xml:"Valores"`
+	IssRetido                 int           `xml:"IssRetido"`
+	ItemListaServico          string        `xml:"ItemListaServico"`
+	CodigoCnae                string        `xml:"CodigoCnae,omitempty"`
+	CodigoTributacaoMunicipio string        `xml:"CodigoTributacaoMunicipio,omitempty"`
+	Discriminacao             string        `xml:"Discriminacao"`
+	CodigoMunicipio           string        `xml:"CodigoMunicipio"`
+	ExigibilidadeISS          int           `xml:"ExigibilidadeISS"`
+	MunicipioIncidencia       string        `xml:"MunicipioIncidencia"`
+}
+
+type abrasfRps struct {
+	Numero      int64  `xml:"IdentificacaoRps>Numero"`
+	Serie       string `xml:"IdentificacaoRps>Serie"`
+	Tipo        int    `xml:"IdentificacaoRps>Tipo"`
+	DataEmissao string `xml:"DataEmissao"`
+	Status      int    `xml:"Status"`
+}
+
+type abrasfPrestador struct {
+	CpfCnpj            abrasfCpfCnpj `xml:"CpfCnpj"`
+	InscricaoMunicipal string        `xml:"InscricaoMunicipal"`
+}
+
+// abrasfInfDeclaracao is the signed part of the RPS (InfDeclaracaoPrestacaoServico).
+type abrasfInfDeclaracao struct {
+	XMLName                xml.Name        `xml:"http://www.abrasf.org.br/nfse.xsd InfDeclaracaoPrestacaoServico"`
+	ID                     string          `xml:"Id,attr"`
+	Rps                    abrasfRps       `xml:"Rps"`
+	Competencia            string          `xml:"Competencia"`
+	Servico                abrasfServico   `xml:"Servico"`
+	Prestador              abrasfPrestador `xml:"Prestador"`
+	Tomador                abrasfTomador   `xml:"TomadorServico"`
+	OptanteSimplesNacional int             `xml:"OptanteSimplesNacional"`
+	IncentivoFiscal        int             `xml:"IncentivoFiscal"`
+}
+
+// ABRASFBuilder renders RPSs as ABRASF 2.04 GerarNfseEnvio documents signed with XMLDSig.
+type ABRASFBuilder struct {
+	cfg    Config
+	signer *Signer
+}
+
+var _ interfaces.INFSeBuilder = (*ABRASFBuilder)(nil)
+
+func NewABRASFBuilder(cfg Config, signer *Signer) *ABRASFBuilder {
+	return &ABRASFBuilder{cfg: cfg, signer: signer}
+}
+
+func (b *ABRASFBuilder) Series() string {
+	return b.cfg.RPSSeries
+}
+
+// Build returns the signed GerarNfseEnvio XML of rps. The signature is enveloped in the
+// Rps element, next to the signed InfDeclaracaoPrestacaoServico.
+func (b *ABRASFBuilder) Build(rps entities.NFSeRPS) ([]byte, error) {
+	if rps.Number <= 0 {
+		return nil, errors.New("nfse: rps number is required")
+	}
+	if !rps.Tomador.IsValid() {
+		return nil, errors.New("nfse: tomador document and name are required")
+	}
+	if rps.ServiceAmount <= 0 {
+		return nil, errors.New("nfse: service amount must be positive")
+	}
+
+	raw, err := xml.Marshal(b.declaracao(rps))
+	if err != nil {
+		return nil, err
+	}
+	inf := etree.NewDocument()
+	if err := inf.ReadFromBytes(raw); err != nil {
+		return nil, err
+	}
+
+	doc := etree.NewDocument()
+	doc.CreateProcInst("xml", `version="1.0" encoding="UTF-8"`)
+	envio := doc.CreateElement("GerarNfseEnvio")
+	envio.CreateAttr("xmlns", abrasfNS)
+	rpsEl := envio.CreateElement("Rps")
+	rpsEl.AddChild(inf.Root())
+	signature, err := b.signer.Sign(rpsEl.ChildElements()[0])
+	if err != nil {
+		return nil, err
+	}
+	rpsEl.AddChild(signature)
+	return doc.WriteToBytes()
+}
+
+func (b *ABRASFBuilder) declaracao(rps entities.NFSeRPS) abrasfInfDeclaracao {
+	date := rps.IssuedAt.In(b.cfg.Location()).Format(abrasfDateLayout)
+	city := digits(b.cfg.CityCode)
+	iss := math.Round(rps.ServiceAmount*b.cfg.ISSRate) / 100
+
+	inf := abrasfInfDeclaracao{
+		ID: fmt.Sprintf("rps%s%d", rps.Series, rps.Number),
+		Rps: abrasfRps{
+			Numero:      rps.Number,
+			Serie:       rps.Series,
+			Tipo:        rpsTipoRPS,
+			DataEmissao: date,
+			Status:      rpsStatusNormal,
+		},
+		Competencia: date,
+		Servico: abrasfServico{
+			Valores: abrasfValores{
+				ValorServicos: formatAmount(rps.ServiceAmount),
+				ValorIss:      formatAmount(iss),
+				Aliquota:      formatAmount(b.cfg.ISSRate),
+			},
+			IssRetido:                 abrasfNao,
+			ItemListaServico:          b.cfg.ServiceItem,
+			CodigoCnae:                b.cfg.CNAE,
+			CodigoTributacaoMunicipio: b.cfg.MunicipalTaxCode,
+			Discriminacao:             truncate(rps.ServiceDescription, maxDiscriminacao),
+			CodigoMunicipio:           city,
+			ExigibilidadeISS:          exigibilidadeISS,
+			MunicipioIncidencia:       city,
+		},
+		Prestador: abrasfPrestador{
+			CpfCnpj:            abrasfCpfCnpj{Cnpj: digits(b.cfg.CNPJ)},
+			InscricaoMunicipal: b.cfg.InscricaoMunicipal,
+		},
+		Tomador: abrasfTomador{
+			RazaoSocial: truncate(rps.Tomador.Name, maxRazaoSocial),
+			Email:       rps.Tomador.Email,
+		},
+		OptanteSimplesNacional: abrasfNao,
+		IncentivoFiscal:        abrasfNao,
+	}
+	if b.cfg.SimplesNacional {
+		inf.OptanteSimplesNacional = abrasfSim
+	}
+	if rps.Tomador.IsCompany() {
+		inf.Tomador.CpfCnpj.Cnpj = rps.Tomador.Document
+	} else {
+		inf.Tomador.CpfCnpj.Cpf = rps.Tomador.Document
+	}
+	if a := rps.Tomador.Address; a != nil {
+		inf.Tomador.Endereco = &abrasfEndereco{
+			Endereco:        a.Street,
+			Numero:          a.Number,
+			Complemento:     a.Complement,
+			Bairro:          a.District,
+			CodigoMunicipio: a.CityCode,
+			Uf:              a.State,
+			Cep:             a.PostalCode,
+		}
+	}
+	return inf
+}
+
+func formatAmount(v float64) string {
+	return strconv.FormatFloat(v, 'f', abrasfAmountDecimal, 64)
+}
+
+func truncate(s string, max int) string {
+	if r := []rune(s); len(r) > max {
+		return string(r[:max])
+	}
+	return s
+}
@@ -0,0 +1,161 @@
+package fiscal
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+	"software.sslmate.com/src/go-pkcs12"
+
+	"mecanica_xpto/internal/domain/entities"
+)
+
+func testCertificate(t *testing.T, notAfter time.Time) (*rsa.PrivateKey, *x509.Certificate) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "OFICINA XPTO LTDA:12345678000195"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, cert
+}
+
+func testConfig() Config {
+	cfg := DefaultConfig()
+	cfg.CNPJ = "12.345.678/0001-95"
+	cfg.InscricaoMunicipal = "123456"
+	cfg.CityCode = "3550308"
+	return cfg
+}
+
+func testRPS() entities.NFSeRPS {
+	return entities.NFSeRPS{
+		Number:             42,
+		Series:             "A",
+		IssuedAt:           time.Date(2025, 6, 1, 1, 0, 0, 0, time.UTC), // still May 31 in São Paulo
+		Tomador:            entities.NFSeTomador{Document: "12345678909", Name: "João & Filhos <Ltda>"},
+		ServiceDescription: "Serviços da ordem de serviço os-1",
+		ServiceAmount:      150.5,
+	}
+}
+
+func TestABRASFBuilder_SignsVerifiably(t *testing.T) {
+	key, cert := testCertificate(t, time.Now().Add(24*time.Hour))
+	b := NewABRASFBuilder(testConfig(), NewSigner(key, cert, SignatureRSASHA1))
+
+	out, err := b.Build(testRPS())
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	doc := string(out)
+	for _, want := range []string{
+		`<GerarNfseEnvio xmlns="http://www.abrasf.org.br/nfse.xsd"><Rps>`,
+		`Id="rpsA42"`,
+		`<DataEmissao>2025-05-31</DataEmissao>`,
+		`<ValorServicos>150.50</ValorServicos><ValorIss>7.53</ValorIss><Aliquota>5.00</Aliquota>`,
+		`<CpfCnpj><Cpf>12345678909</Cpf></CpfCnpj>`,
+		`<Cnpj>12345678000195</Cnpj>`,
+		`<RazaoSocial>João &amp; Filhos &lt;Ltda&gt;</RazaoSocial>`,
+		`<Reference URI="#rpsA42">`,
+	} {
+		if !strings.Contains(doc, want) {
+			t.Fatalf("expected %q in %s", want, doc)
+		}
+	}
+
+	// The signature sits next to the signed element, so verify it against a copy that envelops it.
+	parsed := etree.NewDocument()
+	if err := parsed.ReadFromBytes(out); err != nil {
+		t.Fatalf("invalid xml: %v", err)
+	}
+	inf := parsed.FindElement("//InfDeclaracaoPrestacaoServico").Copy()
+	inf.AddChild(parsed.FindElement("//Rps/Signature").Copy())
+	ctx := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{Roots: []*x509.Certificate{cert}})
+	ctx.IdAttribute = "Id"
+	ctx.Clock = dsig.NewFakeClockAt(time.Now())
+	if _, err := ctx.Validate(inf); err != nil {
+		t.Fatalf("signature does not verify: %v", err)
+	}
+}
+
+func TestABRASFBuilder_Rejects(t *testing.T) {
+	key, cert := testCertificate(t, time.Now().Add(-time.Minute))
+	b := NewABRASFBuilder(testConfig(), NewSigner(key, cert, SignatureRSASHA256))
+	if _, err := b.Build(testRPS()); !errors.Is(err, ErrCertificateExpired) {
+		t.Fatalf("expected ErrCertificateExpired, got %v", err)
+	}
+
+	rps := testRPS()
+	rps.Tomador.Document = "123"
+	if _, err := b.Build(rps); err == nil {
+		t.Fatal("expected invalid tomador error")
+	}
+}
+
+func TestLoadSignerAndFileDrop(t *testing.T) {
+	dir := t.TempDir()
+	key, cert := testCertificate(t, time.Now().Add(time.Hour))
+	var pemFile bytes.Buffer
+	_ = pem.Encode(&pemFile, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	_ = pem.Encode(&pemFile, &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	path := filepath.Join(dir, "a1.pem")
+	if err := os.WriteFile(path, pemFile.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	signer, err := LoadSigner(path, "", SignatureRSASHA1)
+	if err != nil || !signer.NotAfter().Equal(cert.NotAfter) {
+		t.Fatalf("unexpected signer: %v", err)
+	}
+
+	// Current tooling exports A1 certificates as AES-256 PKCS#12.
+	pfx, err := pkcs12.Modern.Encode(key, cert, nil, "s3nha")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pfxPath := filepath.Join(dir, "a1.pfx")
+	if err := os.WriteFile(pfxPath, pfx, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if signer, err = LoadSigner(pfxPath, "s3nha", SignatureRSASHA256); err != nil || !signer.NotAfter().Equal(cert.NotAfter) {
+		t.Fatalf("unexpected pfx signer: %v", err)
+	}
+	if _, err := LoadSigner(pfxPath, "wrong", SignatureRSASHA256); err == nil {
+		t.Fatal("expected wrong password error")
+	}
+
+	tr := NewFileDropTransmitter(filepath.Join(dir, "outbox"))
+	protocol, err := tr.Transmit(context.Background(), entities.NFSeDocument{RPS: entities.NFSeRPS{Number: 7, Series: "A"}, SignedXML: []byte("<x/>")})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	written, err := os.ReadFile(strings.TrimPrefix(protocol, "file:"))
+	if err != nil || string(written) != "<x/>" || !strings.HasSuffix(protocol, "rps-A-7.xml") {
+		t.Fatalf("unexpected file %s: %q err=%v", protocol, written, err)
+	}
+}
@@ -0,0 +1,144 @@
+package fiscal
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"mecanica_xpto/internal/usecase/interfaces"
+)
+
+const (
+	SignatureRSASHA1   = "rsa-sha1"
+	SignatureRSASHA256 = "rsa-sha256"
+)
+
+// Config describes the service provider (prestador) and the municipal service of the
+// NFS-e, read from the JSON file in NFSE_CONFIG_FILE. Keys left out keep their defaults.
+type Config struct {
+	CNPJ               string `json:"cnpj"`
+	InscricaoMunicipal string `json:"inscricao_municipal"`
+	// CityCode is the IBGE code of the municipality where the service is provided.
+	CityCode string `json:"city_code"`
+	// ServiceItem is the item of the LC 116/2003 service list (14.01: vehicle repair).
+	ServiceItem      string `json:"service_item"`
+	MunicipalTaxCode string `json:"municipal_tax_code,omitempty"`
+	CNAE             string `json:"cnae,omitempty"`
+	// ISSRate is the ISS rate in percent (e.g. 5 for 5%).
+	ISSRate         float64 `json:"iss_rate"`
+	SimplesNacional bool    `json:"simples_nacional"`
+	RPSSeries       string  `json:"rps_series"`
+	// SignatureAlgorithm is rsa-sha1 (ABRASF manual) or rsa-sha256, as the city accepts.
+	SignatureAlgorithm string `json:"signature_algorithm"`
+	TimeZone           string `json:"time_zone"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		ServiceItem:        "14.01",
+		ISSRate:            5,
+		RPSSeries:          "A",
+		SignatureAlgorithm: SignatureRSASHA1,
+		TimeZone:           "America/Sao_Paulo",
+	}
+}
+
+// LoadConfigFromEnv reads NFSE_CONFIG_FILE, falling back to DefaultConfig when unset.
+func LoadConfigFromEnv() (Config, error) {
+	cfg := DefaultConfig()
+	path := strings.TrimSpace(os.Getenv("NFSE_CONFIG_FILE"))
+	if path == "" {
+		return cfg, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("read nfse config: %w", err)
+	}
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return Config{}, fmt.Errorf("parse nfse config: %w", err)
+	}
+	return cfg, nil
+}
+
+// Validate checks the fields required to render an RPS.
+func (c Config) Validate() error {
+	if len(digits(c.CNPJ)) != 14 {
+		return fmt.Errorf("invalid nfse config: cnpj must have 14 digits")
+	}
+	if strings.TrimSpace(c.InscricaoMunicipal) == "" || len(digits(c.CityCode)) != 7 {
+		return fmt.Errorf("invalid nfse config: inscricao_municipal and a 7-digit city_code are required")
+	}
+	if strings.TrimSpace(c.ServiceItem) == "" || strings.TrimSpace(c.RPSSeries) == "" {
+		return fmt.Errorf("invalid nfse config: service_item and rps_series are required")
+	}
+	if c.ISSRate < 0 || c.ISSRate > 5 {
+		return fmt.Errorf("invalid nfse config: iss_rate must be between 0 and 5 (percent)")
+	}
+	if c.SignatureAlgorithm != SignatureRSASHA1 && c.SignatureAlgorithm != SignatureRSASHA256 {
+		return fmt.Errorf("invalid nfse config: signature_algorithm must be %s or %s", SignatureRSASHA1, SignatureRSASHA256)
+	}
+	return nil
+}
+
+// Location returns the time zone of the RPS dates, falling back to a fixed UTC-3 offset
+// without a tz database (see accounting.Config.Location).
+func (c Config) Location() *time.Location {
+	if loc, err := time.LoadLocation(c.TimeZone); err == nil {
+		return loc
+	}
+	log.Printf("[fiscal] tz database unavailable; using fixed UTC-3 for %s", c.TimeZone)
+	return time.FixedZone(c.TimeZone, -3*60*60)
+}
+
+// NewBuilderFromEnv builds the signing RPS builder from NFSE_CONFIG_FILE and the A1
+// certificate in NFSE_CERT_FILE (PKCS#12 with NFSE_CERT_PASSWORD, or PEM).
+//
+// It returns (nil, nil) when no certificate is configured, leaving NFS-e disabled.
+func NewBuilderFromEnv() (interfaces.INFSeBuilder, error) {
+	certFile := strings.TrimSpace(os.Getenv("NFSE_CERT_FILE"))
+	if certFile == "" {
+		return nil, nil
+	}
+	cfg, err := LoadConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	signer, err := LoadSigner(certFile, os.Getenv("NFSE_CERT_PASSWORD"), cfg.SignatureAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	return NewABRASFBuilder(cfg, signer), nil
+}
+
+// NewTransmitterFromEnv builds the transmitter selected by NFSE_TRANSMITTER. Only "file"
+// (the default) is available: it drops the signed XML in NFSE_OUTBOX_DIR for local runs
+// or for an external sender.
+func NewTransmitterFromEnv() (interfaces.INFSeTransmitter, error) {
+	kind := strings.TrimSpace(os.Getenv("NFSE_TRANSMITTER"))
+	switch kind {
+	case "", "file":
+		dir := strings.TrimSpace(os.Getenv("NFSE_OUTBOX_DIR"))
+		if dir == "" {
+			dir = "nfse-outbox"
+		}
+		return NewFileDropTransmitter(dir), nil
+	default:
+		return nil, fmt.Errorf("unknown NFSE_TRANSMITTER %q", kind)
+	}
+}
+
+func digits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,39 @@
+package fiscal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+)
+
+// FileDropTransmitter stands in for the municipality web service: it writes each signed
+// document to Dir as rps-<series>-<number>.xml and returns the file path as protocol.
+type FileDropTransmitter struct {
+	Dir string
+}
+
+var _ interfaces.INFSeTransmitter = (*FileDropTransmitter)(nil)
+
+func NewFileDropTransmitter(dir string) *FileDropTransmitter {
+	return &FileDropTransmitter{Dir: dir}
+}
+
+func (t *FileDropTransmitter) Transmit(_ context.Context, doc entities.NFSeDocument) (string, error) {
+	if err := os.MkdirAll(t.Dir, 0o750); err != nil {
+		return "", err
+	}
+	path := filepath.Join(t.Dir, fmt.Sprintf("rps-%s-%d.xml", doc.RPS.Series, doc.RPS.Number))
+	// Written under a temporary name so a reader never picks up a partial file.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, doc.SignedXML, 0o640); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", err
+	}
+	return "file:" + path, nil
+}
@@ -0,0 +1,132 @@
+package fiscal
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// ErrCertificateExpired is returned when signing after the certificate validity.
+var ErrCertificateExpired = errors.New("nfse certificate expired")
+
+// Signer produces enveloped XMLDSig signatures with an ICP-Brasil A1 certificate.
+type Signer struct {
+	key       *rsa.PrivateKey
+	cert      *x509.Certificate
+	algorithm string
+	now       func() time.Time
+}
+
+func NewSigner(key *rsa.PrivateKey, cert *x509.Certificate, algorithm string) *Signer {
+	return &Signer{key: key, cert: cert, algorithm: algorithm, now: time.Now}
+}
+
+// LoadSigner reads an A1 certificate: a PKCS#12 file (.pfx/.p12, legacy or AES-encrypted)
+// opened with password, or a PEM file with the certificate and its unencrypted RSA private key.
+func LoadSigner(path, password, algorithm string) (*Signer, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read nfse certificate: %w", err)
+	}
+	if !bytes.Contains(raw, []byte("-----BEGIN")) {
+		k, leaf, chain, err := pkcs12.DecodeChain(raw, password)
+		if err != nil {
+			return nil, fmt.Errorf("open nfse certificate: %w", err)
+		}
+		key, ok := k.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("nfse private key is not RSA")
+		}
+		return signerFor(key, append([]*x509.Certificate{leaf}, chain...), algorithm)
+	}
+
+	var key *rsa.PrivateKey
+	var certs []*x509.Certificate
+	for rest := raw; ; {
+		var b *pem.Block
+		if b, rest = pem.Decode(rest); b == nil {
+			break
+		}
+		switch b.Type {
+		case "CERTIFICATE":
+			c, err := x509.ParseCertificate(b.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("parse nfse certificate: %w", err)
+			}
+			certs = append(certs, c)
+		case "RSA PRIVATE KEY", "PRIVATE KEY":
+			if key, err = parseRSAKey(b.Bytes); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if key == nil {
+		return nil, errors.New("nfse certificate: no RSA private key found")
+	}
+	return signerFor(key, certs, algorithm)
+}
+
+// signerFor picks the certificate of key among certs: the file may carry the CA chain.
+func signerFor(key *rsa.PrivateKey, certs []*x509.Certificate, algorithm string) (*Signer, error) {
+	for _, c := range certs {
+		if pub, ok := c.PublicKey.(*rsa.PublicKey); ok && pub.Equal(&key.PublicKey) {
+			return NewSigner(key, c, algorithm), nil
+		}
+	}
+	return nil, errors.New("nfse certificate: no certificate matches the private key")
+}
+
+func parseRSAKey(der []byte) (*rsa.PrivateKey, error) {
+	if k, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return k, nil
+	}
+	k, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("parse nfse private key: %w", err)
+	}
+	rsaKey, ok := k.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("nfse private key is not RSA")
+	}
+	return rsaKey, nil
+}
+
+// NotAfter is the end of the certificate validity.
+func (s *Signer) NotAfter() time.Time {
+	return s.cert.NotAfter
+}
+
+// GetKeyPair implements dsig.X509KeyStore.
+func (s *Signer) GetKeyPair() (*rsa.PrivateKey, []byte, error) {
+	return s.key, s.cert.Raw, nil
+}
+
+// Sign returns the unprefixed <Signature> element signing el by its Id attribute with
+// enveloped-signature and Canonical XML 1.0 transforms, as the ABRASF schema expects. el
+// must already sit in its final document so the in-scope namespaces are digested.
+func (s *Signer) Sign(el *etree.Element) (*etree.Element, error) {
+	if s.now().After(s.cert.NotAfter) {
+		return nil, fmt.Errorf("%w on %s", ErrCertificateExpired, s.cert.NotAfter.Format(time.DateOnly))
+	}
+	ctx := dsig.NewDefaultSigningContext(s)
+	ctx.Prefix = ""
+	ctx.IdAttribute = "Id"
+	ctx.Canonicalizer = dsig.MakeC14N10RecCanonicalizer()
+	method := dsig.RSASHA1SignatureMethod
+	if s.algorithm == SignatureRSASHA256 {
+		method = dsig.RSASHA256SignatureMethod
+	}
+	if err := ctx.SetSignatureMethod(method); err != nil {
+		return nil, err
+	}
+	return ctx.ConstructSignature(el, true)
+}
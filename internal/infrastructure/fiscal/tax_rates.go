@@ -0,0 +1,87 @@
+package fiscal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+)
+
+// taxRatesFile is the JSON in TAX_RATES_FILE. Dates are days (YYYY-MM-DD) in time_zone;
+// effective_to is exclusive and may be left out for the current rate.
+type taxRatesFile struct {
+	TimeZone            string `json:"time_zone"`
+	DefaultMunicipality string `json:"default_municipality"`
+	Rates               []struct {
+		Tax           string  `json:"tax"`
+		ItemKind      string  `json:"item_kind"`
+		Municipality  string  `json:"municipality"`
+		Rate          float64 `json:"rate"`
+		EffectiveFrom string  `json:"effective_from"`
+		EffectiveTo   string  `json:"effective_to"`
+	} `json:"rates"`
+}
+
+// DefaultTaxTable taxes services with 5% ISS and parts with 18% ICMS (São Paulo rates).
+func DefaultTaxTable() entities.TaxTable {
+	since := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	return entities.TaxTable{
+		DefaultMunicipality: "3550308",
+		Rates: []entities.TaxRate{
+			{Tax: entities.TaxISS, ItemKind: entities.EstimateItemServico, Rate: 5, EffectiveFrom: since},
+			{Tax: entities.TaxICMS, ItemKind: entities.EstimateItemPeca, Rate: 18, EffectiveFrom: since},
+		},
+	}
+}
+
+// LoadTaxTableFromEnv reads TAX_RATES_FILE, falling back to DefaultTaxTable when unset.
+func LoadTaxTableFromEnv() (entities.TaxTable, error) {
+	path := strings.TrimSpace(os.Getenv("TAX_RATES_FILE"))
+	if path == "" {
+		return DefaultTaxTable(), nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return entities.TaxTable{}, fmt.Errorf("read tax rates: %w", err)
+	}
+	return parseTaxTable(raw)
+}
+
+func parseTaxTable(raw []byte) (entities.TaxTable, error) {
+	file := taxRatesFile{TimeZone: "America/Sao_Paulo"}
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return entities.TaxTable{}, fmt.Errorf("parse tax rates: %w", err)
+	}
+	loc, err := time.LoadLocation(file.TimeZone)
+	if err != nil {
+		return entities.TaxTable{}, fmt.Errorf("tax rates time_zone: %w", err)
+	}
+
+	table := entities.TaxTable{DefaultMunicipality: file.DefaultMunicipality}
+	for i, r := range file.Rates {
+		rate := entities.TaxRate{
+			Tax:          strings.ToUpper(strings.TrimSpace(r.Tax)),
+			ItemKind:     entities.EstimateItemKind(r.ItemKind),
+			Municipality: strings.TrimSpace(r.Municipality),
+			Rate:         r.Rate,
+		}
+		if rate.EffectiveFrom, err = time.ParseInLocation(time.DateOnly, r.EffectiveFrom, loc); err != nil {
+			return entities.TaxTable{}, fmt.Errorf("tax rates[%d] effective_from: %w", i, err)
+		}
+		if r.EffectiveTo != "" {
+			to, err := time.ParseInLocation(time.DateOnly, r.EffectiveTo, loc)
+			if err != nil {
+				return entities.TaxTable{}, fmt.Errorf("tax rates[%d] effective_to: %w", i, err)
+			}
+			rate.EffectiveTo = &to
+		}
+		table.Rates = append(table.Rates, rate)
+	}
+	if err := table.Validate(); err != nil {
+		return entities.TaxTable{}, fmt.Errorf("tax rates: %w", err)
+	}
+	return table, nil
+}
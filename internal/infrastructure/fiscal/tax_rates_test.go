@@ -0,0 +1,40 @@
+package fiscal
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+)
+
+func TestParseTaxTable(t *testing.T) {
+	table, err := parseTaxTable([]byte(`{
+		"default_municipality": "3550308",
+		"rates": [
+			{"tax": "iss", "item_kind": "servico", "municipality": "3550308", "rate": 2, "effective_from": "2024-01-01", "effective_to": "2025-01-01"},
+			{"tax": "iss", "item_kind": "servico", "municipality": "3550308", "rate": 5, "effective_from": "2025-01-01"},
+			{"tax": "ICMS", "item_kind": "peca", "rate": 18, "effective_from": "2024-01-01"}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	sp, _ := time.LoadLocation("America/Sao_Paulo")
+	// Still Dec 31 in São Paulo: the old rate applies.
+	if got := table.RatesFor("", entities.EstimateItemServico, time.Date(2025, 1, 1, 2, 0, 0, 0, time.UTC)); len(got) != 1 || got[0].Rate != 2 {
+		t.Fatalf("unexpected rates: %+v", got)
+	}
+	if got := table.RatesFor("", entities.EstimateItemServico, time.Date(2025, 1, 1, 0, 0, 0, 0, sp)); len(got) != 1 || got[0].Rate != 5 {
+		t.Fatalf("unexpected rates: %+v", got)
+	}
+
+	_, err = parseTaxTable([]byte(`{"rates": [
+		{"tax": "ISS", "item_kind": "servico", "rate": 2, "effective_from": "2024-01-01"},
+		{"tax": "ISS", "item_kind": "servico", "rate": 5, "effective_from": "2025-01-01"}
+	]}`))
+	if !errors.Is(err, entities.ErrInvalidTaxRate) {
+		t.Fatalf("expected overlapping rates to be rejected, got %v", err)
+	}
+}
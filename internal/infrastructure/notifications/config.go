@@ -0,0 +1,37 @@
+package notifications
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"mecanica_xpto/internal/usecase/interfaces"
+)
+
+// NewNotifierFromEnv builds the notifier selected by NOTIFIER:
+//   - "file" (default): writes the messages to NOTIFIER_OUTBOX_DIR (default
+//     notifications-outbox), for local runs or for an external sender;
+//   - "webhook": posts them to NOTIFIER_WEBHOOK_URL, with NOTIFIER_WEBHOOK_TOKEN as bearer
+//     token when set.
+func NewNotifierFromEnv() (interfaces.INotifier, error) {
+	kind := strings.TrimSpace(os.Getenv("NOTIFIER"))
+	switch kind {
+	case "", "file":
+		dir := strings.TrimSpace(os.Getenv("NOTIFIER_OUTBOX_DIR"))
+		if dir == "" {
+			dir = "notifications-outbox"
+		}
+		return NewFileOutboxNotifier(dir), nil
+	case "webhook":
+		url := strings.TrimSpace(os.Getenv("NOTIFIER_WEBHOOK_URL"))
+		if url == "" {
+			return nil, fmt.Errorf("NOTIFIER_WEBHOOK_URL is required by the webhook notifier")
+		}
+		client := &http.Client{Timeout: 10 * time.Second}
+		return NewWebhookNotifier(url, strings.TrimSpace(os.Getenv("NOTIFIER_WEBHOOK_TOKEN")), client), nil
+	default:
+		return nil, fmt.Errorf("unknown NOTIFIER %q", kind)
+	}
+}
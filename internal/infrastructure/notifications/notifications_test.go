@@ -0,0 +1,87 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+)
+
+var otpMessage = entities.Notification{
+	Channel:   entities.NotificationChannelSMS,
+	Recipient: "11987654321",
+	Subject:   "Código de aprovação",
+	Body:      "Seu código é 123456",
+}
+
+func TestFileOutboxNotifier(t *testing.T) {
+	dir := t.TempDir()
+	n := NewFileOutboxNotifier(dir)
+	n.now = func() time.Time { return time.Unix(0, 42) }
+	if err := n.Notify(context.Background(), otpMessage); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	raw, err := os.ReadFile(filepath.Join(dir, "sms-42.json"))
+	if err != nil {
+		t.Fatalf("outbox file: %v", err)
+	}
+	var got entities.Notification
+	if err := json.Unmarshal(raw, &got); err != nil || got != otpMessage {
+		t.Fatalf("unexpected notification: %s", raw)
+	}
+}
+
+type stubDoer struct {
+	status int
+	req    *http.Request
+	body   string
+}
+
+func (s *stubDoer) Do(req *http.Request) (*http.Response, error) {
+	s.req = req
+	raw, _ := io.ReadAll(req.Body)
+	s.body = string(raw)
+	return &http.Response{StatusCode: s.status, Body: io.NopCloser(strings.NewReader("down"))}, nil
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	doer := &stubDoer{status: http.StatusAccepted}
+	n := NewWebhookNotifier("https://gateway.example.com/messages", "secret", doer)
+	if err := n.Notify(context.Background(), otpMessage); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if doer.req.Header.Get("Authorization") != "Bearer secret" || !strings.Contains(doer.body, `"recipient":"11987654321"`) {
+		t.Fatalf("unexpected request: %v %s", doer.req.Header, doer.body)
+	}
+
+	doer.status = http.StatusServiceUnavailable
+	if err := n.Notify(context.Background(), otpMessage); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("expected delivery error, got %v", err)
+	}
+}
+
+func TestNewNotifierFromEnv(t *testing.T) {
+	t.Setenv("NOTIFIER", "webhook")
+	t.Setenv("NOTIFIER_WEBHOOK_URL", "")
+	if _, err := NewNotifierFromEnv(); err == nil {
+		t.Fatalf("expected missing url error")
+	}
+	t.Setenv("NOTIFIER", "pombo")
+	if _, err := NewNotifierFromEnv(); err == nil {
+		t.Fatalf("expected unknown notifier error")
+	}
+	t.Setenv("NOTIFIER", "")
+	if n, err := NewNotifierFromEnv(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if _, ok := n.(*FileOutboxNotifier); !ok {
+		t.Fatalf("expected file notifier, got %T", n)
+	}
+}
@@ -0,0 +1,43 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+)
+
+// FileOutboxNotifier stands in for an e-mail/SMS provider: it writes each notification to
+// Dir as <channel>-<unix nanos>.json, for local runs or for an external sender.
+type FileOutboxNotifier struct {
+	Dir string
+	now func() time.Time
+}
+
+var _ interfaces.INotifier = (*FileOutboxNotifier)(nil)
+
+func NewFileOutboxNotifier(dir string) *FileOutboxNotifier {
+	return &FileOutboxNotifier{Dir: dir, now: time.Now}
+}
+
+func (n *FileOutboxNotifier) Notify(_ context.Context, msg entities.Notification) error {
+	if err := os.MkdirAll(n.Dir, 0o750); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(n.Dir, fmt.Sprintf("%s-%d.json", msg.Channel, n.now().UnixNano()))
+	// Written under a temporary name so a sender never picks up a partial file.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o640); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,61 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+)
+
+// HTTPDoer is the part of *http.Client the webhook notifier uses.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// WebhookNotifier posts each notification as JSON to URL, where the e-mail/SMS gateway
+// of the workshop delivers it. Token, when set, goes as a bearer token.
+type WebhookNotifier struct {
+	URL    string
+	Token  string
+	client HTTPDoer
+}
+
+var _ interfaces.INotifier = (*WebhookNotifier)(nil)
+
+func NewWebhookNotifier(url, token string, client HTTPDoer) *WebhookNotifier {
+	return &WebhookNotifier{URL: url, Token: token, client: client}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, msg entities.Notification) error {
+	raw, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.Token)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		log.Printf("[notifications][webhook] delivery failed channel=%s err=%v", msg.Channel, err)
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		log.Printf("[notifications][webhook] delivery failed channel=%s status=%d", msg.Channel, resp.StatusCode)
+		return fmt.Errorf("notification webhook: status %d: %s", resp.StatusCode, body)
+	}
+	return nil
+}
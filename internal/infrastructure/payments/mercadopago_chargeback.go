@@ -0,0 +1,117 @@
+package payments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+)
+
+const mercadoPagoChargebacksURL = "https://api.mercadopago.com/v1/chargebacks/"
+
+// mercadoPagoChargebackBody is the subset of the Mercado Pago chargeback resource we use.
+type mercadoPagoChargebackBody struct {
+	ID                        json.RawMessage   `json:"id"`
+	Payments                  []json.RawMessage `json:"payments"`
+	Amount                    float64           `json:"amount"`
+	Reason                    string            `json:"reason"`
+	CoverageApplied           *bool             `json:"coverage_applied"`
+	DocumentationStatus       string            `json:"documentation_status"`
+	DateDocumentationDeadline string            `json:"date_documentation_deadline"`
+}
+
+// GetChargeback implements interfaces.IChargebackProvider.
+//
+// The SDK has no chargeback client, so the request goes through the SDK requester with
+// the same credentials used for payments.
+func (g *MercadoPagoGateway) GetChargeback(ctx context.Context, chargebackID string) (entities.ProviderChargeback, error) {
+	if g == nil || g.cfg == nil {
+		return entities.ProviderChargeback{}, ErrMercadoPagoGatewayNotConfigured
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, mercadoPagoChargebacksURL+url.PathEscape(chargebackID), nil)
+	if err != nil {
+		return entities.ProviderChargeback{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+g.cfg.AccessToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := g.cfg.Requester.Do(req)
+	if err != nil {
+		log.Printf("[payment][gateway] chargeback fetch failed chargeback_id=%s err=%v", chargebackID, err)
+		return entities.ProviderChargeback{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return entities.ProviderChargeback{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("[payment][gateway] chargeback fetch failed chargeback_id=%s status=%d", chargebackID, resp.StatusCode)
+		return entities.ProviderChargeback{}, fmt.Errorf("mercado pago chargeback %s: status %d: %s", chargebackID, resp.StatusCode, body)
+	}
+	return ParseMercadoPagoChargeback(body)
+}
+
+// ParseMercadoPagoChargeback maps a Mercado Pago chargeback body to the provider-neutral
+// representation. coverage_applied decides the outcome (true: seller covered, dispute won);
+// before the decision the documentation status tells whether evidence was sent.
+func ParseMercadoPagoChargeback(raw json.RawMessage) (entities.ProviderChargeback, error) {
+	var body mercadoPagoChargebackBody
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return entities.ProviderChargeback{}, err
+	}
+
+	cb := entities.ProviderChargeback{
+		ID:     rawID(body.ID),
+		Amount: body.Amount,
+		Reason: body.Reason,
+		Status: entities.DisputeStatusAberta,
+	}
+	if len(body.Payments) > 0 {
+		cb.PaymentID = rawID(body.Payments[0])
+	}
+
+	switch {
+	case body.CoverageApplied != nil && *body.CoverageApplied:
+		cb.Status = entities.DisputeStatusGanha
+	case body.CoverageApplied != nil:
+		cb.Status = entities.DisputeStatusPerdida
+	case body.DocumentationStatus == "review_pending" || body.DocumentationStatus == "valid":
+		cb.Status = entities.DisputeStatusEvidenciaEnviada
+	}
+
+	if body.DateDocumentationDeadline != "" {
+		if t, err := time.Parse(time.RFC3339Nano, body.DateDocumentationDeadline); err == nil {
+			t = t.UTC()
+			cb.EvidenceDeadline = &t
+		}
+	}
+	if cb.ID == "" {
+		return entities.ProviderChargeback{}, fmt.Errorf("mercado pago chargeback without id")
+	}
+	if cb.PaymentID == "" {
+		return entities.ProviderChargeback{}, fmt.Errorf("mercado pago chargeback %s without payment", cb.ID)
+	}
+	return cb, nil
+}
+
+// rawID accepts ids sent either as JSON numbers or strings.
+func rawID(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return n.String()
+	}
+	return ""
+}
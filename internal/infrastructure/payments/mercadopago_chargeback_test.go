@@ -0,0 +1,99 @@
+package payments
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"mecanica_xpto/internal/domain/entities"
+
+	"github.com/mercadopago/sdk-go/pkg/config"
+)
+
+type stubRequester struct {
+	status int
+	body   string
+	req    *http.Request
+}
+
+func (s *stubRequester) Do(req *http.Request) (*http.Response, error) {
+	s.req = req
+	return &http.Response{StatusCode: s.status, Body: io.NopCloser(strings.NewReader(s.body))}, nil
+}
+
+func TestParseMercadoPagoChargeback(t *testing.T) {
+	cases := []struct {
+		name string
+		raw  string
+		want entities.DisputeStatus
+	}{
+		{"opened", `{"id":"cb1","payments":[123],"amount":50,"coverage_applied":null,"documentation_status":"pending"}`, entities.DisputeStatusAberta},
+		{"evidence submitted", `{"id":"cb1","payments":[123],"documentation_status":"review_pending"}`, entities.DisputeStatusEvidenciaEnviada},
+		{"won", `{"id":"cb1","payments":[123],"coverage_applied":true,"documentation_status":"valid"}`, entities.DisputeStatusGanha},
+		{"lost", `{"id":"cb1","payments":[123],"coverage_applied":false,"documentation_status":"invalid"}`, entities.DisputeStatusPerdida},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cb, err := ParseMercadoPagoChargeback(json.RawMessage(tc.raw))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cb.Status != tc.want || cb.PaymentID != "123" || cb.ID != "cb1" {
+				t.Fatalf("unexpected chargeback: %+v", cb)
+			}
+		})
+	}
+
+	t.Run("deadline and numeric id", func(t *testing.T) {
+		cb, err := ParseMercadoPagoChargeback(json.RawMessage(`{"id":987,"payments":[1],"amount":10.5,"date_documentation_deadline":"2026-05-01T12:00:00.000-03:00"}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cb.ID != "987" || cb.Amount != 10.5 || cb.EvidenceDeadline == nil || cb.EvidenceDeadline.Hour() != 15 {
+			t.Fatalf("unexpected chargeback: %+v", cb)
+		}
+	})
+
+	t.Run("missing payment", func(t *testing.T) {
+		if _, err := ParseMercadoPagoChargeback(json.RawMessage(`{"id":"cb1","payments":[]}`)); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
+
+func TestMercadoPagoGateway_GetChargeback(t *testing.T) {
+	t.Run("not configured", func(t *testing.T) {
+		var g *MercadoPagoGateway
+		if _, err := g.GetChargeback(context.Background(), "cb1"); !errors.Is(err, ErrMercadoPagoGatewayNotConfigured) {
+			t.Fatalf("expected ErrMercadoPagoGatewayNotConfigured, got %v", err)
+		}
+	})
+
+	t.Run("fetches with credentials", func(t *testing.T) {
+		stub := &stubRequester{status: http.StatusOK, body: `{"id":"cb1","payments":[42],"amount":10}`}
+		g := &MercadoPagoGateway{cfg: &config.Config{AccessToken: "tok", Requester: stub}}
+
+		cb, err := g.GetChargeback(context.Background(), "cb1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cb.PaymentID != "42" {
+			t.Fatalf("unexpected chargeback: %+v", cb)
+		}
+		if stub.req.URL.String() != "https://api.mercadopago.com/v1/chargebacks/cb1" || stub.req.Header.Get("Authorization") != "Bearer tok" {
+			t.Fatalf("unexpected request: %s %v", stub.req.URL, stub.req.Header)
+		}
+	})
+
+	t.Run("provider error", func(t *testing.T) {
+		stub := &stubRequester{status: http.StatusNotFound, body: `{"message":"not found"}`}
+		g := &MercadoPagoGateway{cfg: &config.Config{AccessToken: "tok", Requester: stub}}
+		if _, err := g.GetChargeback(context.Background(), "cb1"); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package payments
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+)
+
+// mercadoPagoPaymentBody is the subset of the Mercado Pago payment resource used to
+// build entities.PaymentDetails. It matches both the SDK response and the mock payloads.
+type mercadoPagoPaymentBody struct {
+	TransactionAmount  float64 `json:"transaction_amount"`
+	PaymentMethodID    string  `json:"payment_method_id"`
+	PaymentTypeID      string  `json:"payment_type_id"`
+	Installments       int     `json:"installments"`
+	DateApproved       string  `json:"date_approved"`
+	TransactionDetails struct {
+		NetReceivedAmount float64 `json:"net_received_amount"`
+	} `json:"transaction_details"`
+	FeeDetails []struct {
+		Type     string  `json:"type"`
+		FeePayer string  `json:"fee_payer"`
+		Amount   float64 `json:"amount"`
+	} `json:"fee_details"`
+	Card struct {
+		LastFourDigits string `json:"last_four_digits"`
+	} `json:"card"`
+	Payer struct {
+		Email          string `json:"email"`
+		Identification struct {
+			Type   string `json:"type"`
+			Number string `json:"number"`
+		} `json:"identification"`
+	} `json:"payer"`
+}
+
+var cardPaymentTypes = map[string]bool{
+	"credit_card":  true,
+	"debit_card":   true,
+	"prepaid_card": true,
+}
+
+// ExtractDetails implements interfaces.IPaymentDetailsExtractor for Mercado Pago payloads.
+//
+// It is a pure function of the payload, so it also works for gateways in mock mode and
+// for backfilling rows persisted before the typed fields existed.
+func (g *MercadoPagoGateway) ExtractDetails(providerResponse json.RawMessage) (entities.PaymentDetails, error) {
+	return ParseMercadoPagoPaymentDetails(providerResponse)
+}
+
+// ParseMercadoPagoPaymentDetails builds typed payment details from a Mercado Pago payment body.
+func ParseMercadoPagoPaymentDetails(raw json.RawMessage) (entities.PaymentDetails, error) {
+	var body mercadoPagoPaymentBody
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return entities.PaymentDetails{}, err
+	}
+
+	d := entities.PaymentDetails{
+		Amount:            body.TransactionAmount,
+		NetReceivedAmount: body.TransactionDetails.NetReceivedAmount,
+		PaymentMethodID:   body.PaymentMethodID,
+		PaymentTypeID:     body.PaymentTypeID,
+		Installments:      body.Installments,
+		CardLastFour:      body.Card.LastFourDigits,
+		PayerEmail:        strings.TrimSpace(body.Payer.Email),
+		PayerDocType:      body.Payer.Identification.Type,
+		PayerDocNumber:    body.Payer.Identification.Number,
+	}
+	for _, f := range body.FeeDetails {
+		d.Fees = append(d.Fees, entities.PaymentFee{Type: f.Type, Payer: f.FeePayer, Amount: f.Amount})
+	}
+	// Mercado Pago uses the brand as payment_method_id for card payments (visa, master, ...).
+	if cardPaymentTypes[d.PaymentTypeID] || d.CardLastFour != "" {
+		d.CardBrand = d.PaymentMethodID
+	}
+	// Without fee information the net amount is the gross amount (e.g. mock mode).
+	if d.NetReceivedAmount == 0 && len(d.Fees) == 0 {
+		d.NetReceivedAmount = d.Amount
+	}
+	if approvedAt, ok := parseProviderTime(body.DateApproved); ok {
+		d.ApprovedAt = &approvedAt
+	}
+	return d, nil
+}
+
+func parseProviderTime(v string) (time.Time, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil || t.IsZero() {
+		return time.Time{}, false
+	}
+	return t.UTC(), true
+}
@@ -0,0 +1,56 @@
+package payments
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseMercadoPagoPaymentDetails(t *testing.T) {
+	t.Run("card payment with fees", func(t *testing.T) {
+		raw := json.RawMessage(`{
+			"id": 123,
+			"transaction_amount": 150.5,
+			"payment_method_id": "master",
+			"payment_type_id": "credit_card",
+			"installments": 3,
+			"date_approved": "2026-03-01T10:00:00.000-03:00",
+			"transaction_details": {"net_received_amount": 142.3},
+			"fee_details": [{"type": "mercadopago_fee", "fee_payer": "collector", "amount": 8.2}],
+			"card": {"last_four_digits": "4321", "first_six_digits": "503143"},
+			"payer": {"email": "client@test.com", "identification": {"type": "CPF", "number": "12345678909"}}
+		}`)
+
+		d, err := ParseMercadoPagoPaymentDetails(raw)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d.Amount != 150.5 || d.NetReceivedAmount != 142.3 || d.TotalFees() != 8.2 {
+			t.Fatalf("unexpected amounts: %+v", d)
+		}
+		if d.CardBrand != "master" || d.CardLastFour != "4321" || d.Installments != 3 {
+			t.Fatalf("unexpected card fields: %+v", d)
+		}
+		if d.PayerEmail != "client@test.com" || d.PayerDocType != "CPF" || d.PayerDocNumber != "12345678909" {
+			t.Fatalf("unexpected payer fields: %+v", d)
+		}
+		if d.ApprovedAt == nil || d.ApprovedAt.Hour() != 13 {
+			t.Fatalf("expected approved_at normalized to UTC, got %v", d.ApprovedAt)
+		}
+	})
+
+	t.Run("pix without fees uses gross as net", func(t *testing.T) {
+		d, err := ParseMercadoPagoPaymentDetails(json.RawMessage(`{"transaction_amount":10,"payment_method_id":"pix","payment_type_id":"bank_transfer","date_approved":"0001-01-01T00:00:00Z"}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d.NetReceivedAmount != 10 || d.CardBrand != "" || d.ApprovedAt != nil {
+			t.Fatalf("unexpected details: %+v", d)
+		}
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		if _, err := ParseMercadoPagoPaymentDetails(json.RawMessage(`{`)); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
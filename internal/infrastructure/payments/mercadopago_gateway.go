@@ -20,6 +20,7 @@ var ErrMercadoPagoGatewayNotConfigured = errors.New("mercado pago gateway not co
 
 type MercadoPagoGateway struct {
 	client   payment.Client
+	cfg      *config.Config
 	mockMode bool
 }
 
@@ -41,7 +42,7 @@ func NewMercadoPagoGateway(accessToken string) (*MercadoPagoGateway, error) {
 	}
 	log.Printf("[payment][gateway] Mercado Pago client initialized")
 
-	return &MercadoPagoGateway{client: payment.NewClient(cfg)}, nil
+	return &MercadoPagoGateway{client: payment.NewClient(cfg), cfg: cfg}, nil
 }
 
 func (g *MercadoPagoGateway) CreatePayment(ctx context.Context, requestPayload json.RawMessage) (providerPaymentID string, providerStatus string, providerResponse json.RawMessage, err error) {
@@ -0,0 +1,176 @@
+package payments
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+)
+
+// ParseSettlementReport implements interfaces.ISettlementReportParser for Mercado Pago
+// reports. Like ExtractDetails it needs no credentials.
+func (g *MercadoPagoGateway) ParseSettlementReport(r io.Reader) ([]entities.SettlementLine, error) {
+	return ParseMercadoPagoSettlementReport(r)
+}
+
+// ParseMercadoPagoSettlementReport reads a Mercado Pago settlement report ("relatório de
+// vendas", TRANSACTION_TYPE/TRANSACTION_AMOUNT columns) or release report ("relatório de
+// liberações", DESCRIPTION/GROSS_AMOUNT columns).
+//
+// The delimiter (`;` or `,`) is taken from the header line and column names are matched
+// case-insensitively, so reports exported with different column selections still parse.
+// Balance and total rows of release reports are skipped.
+func ParseMercadoPagoSettlementReport(r io.Reader) ([]entities.SettlementLine, error) {
+	br := bufio.NewReader(r)
+	header, err := br.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	header = strings.TrimPrefix(header, "\ufeff")
+	if strings.TrimSpace(header) == "" {
+		return nil, errors.New("empty settlement report")
+	}
+
+	cr := csv.NewReader(io.MultiReader(strings.NewReader(header), br))
+	cr.Comma = ','
+	if strings.Count(header, ";") > strings.Count(header, ",") {
+		cr.Comma = ';'
+	}
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	names, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	cols := map[string]int{}
+	for i, n := range names {
+		cols[strings.ToUpper(strings.TrimSpace(n))] = i
+	}
+	if _, ok := cols["SOURCE_ID"]; !ok {
+		return nil, errors.New("settlement report without SOURCE_ID column")
+	}
+	_, hasTxType := cols["TRANSACTION_TYPE"]
+	_, hasDescription := cols["DESCRIPTION"]
+	if !hasTxType && !hasDescription {
+		return nil, errors.New("settlement report without TRANSACTION_TYPE or DESCRIPTION column")
+	}
+
+	var lines []entities.SettlementLine
+	for lineNo := 2; ; lineNo++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		field := func(name string) string {
+			if i, ok := cols[name]; ok && i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}
+		if isBlankRecord(rec) {
+			continue
+		}
+		if recordType := strings.ToLower(field("RECORD_TYPE")); recordType != "" && recordType != "release" {
+			continue
+		}
+
+		line := entities.SettlementLine{
+			Line:              lineNo,
+			SourceID:          field("SOURCE_ID"),
+			ExternalReference: field("EXTERNAL_REFERENCE"),
+			ProviderType:      firstNonEmptyField(field("TRANSACTION_TYPE"), field("DESCRIPTION")),
+		}
+		line.Type = settlementLineType(line.ProviderType)
+
+		amounts := map[string]float64{}
+		for _, name := range []string{"TRANSACTION_AMOUNT", "GROSS_AMOUNT", "SETTLEMENT_NET_AMOUNT", "NET_CREDIT_AMOUNT", "NET_DEBIT_AMOUNT", "FEE_AMOUNT", "MP_FEE_AMOUNT", "FINANCING_FEE_AMOUNT"} {
+			v, err := parseReportAmount(field(name))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid %s: %w", lineNo, name, err)
+			}
+			amounts[name] = v
+		}
+		if _, ok := cols["TRANSACTION_AMOUNT"]; ok {
+			line.GrossAmount = math.Abs(amounts["TRANSACTION_AMOUNT"])
+			line.NetAmount = amounts["SETTLEMENT_NET_AMOUNT"]
+			line.FeeAmount = math.Abs(amounts["FEE_AMOUNT"])
+		} else {
+			line.GrossAmount = math.Abs(amounts["GROSS_AMOUNT"])
+			line.NetAmount = amounts["NET_CREDIT_AMOUNT"] - amounts["NET_DEBIT_AMOUNT"]
+			line.FeeAmount = math.Abs(amounts["MP_FEE_AMOUNT"]) + math.Abs(amounts["FINANCING_FEE_AMOUNT"])
+		}
+
+		if line.TransactionDate, err = parseReportDate(field("TRANSACTION_DATE")); err != nil {
+			return nil, fmt.Errorf("line %d: invalid TRANSACTION_DATE: %w", lineNo, err)
+		}
+		if line.SettlementDate, err = parseReportDate(firstNonEmptyField(field("SETTLEMENT_DATE"), field("DATE"))); err != nil {
+			return nil, fmt.Errorf("line %d: invalid settlement date: %w", lineNo, err)
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+func settlementLineType(providerType string) entities.SettlementLineType {
+	switch strings.ToLower(providerType) {
+	case "settlement", "payment":
+		return entities.SettlementLinePayment
+	case "refund":
+		return entities.SettlementLineRefund
+	case "chargeback":
+		return entities.SettlementLineChargeback
+	}
+	return entities.SettlementLineOther
+}
+
+// parseReportAmount accepts "1234.56" and the pt-BR "1.234,56" spreadsheet format.
+func parseReportAmount(v string) (float64, error) {
+	if v == "" {
+		return 0, nil
+	}
+	if strings.Contains(v, ",") {
+		v = strings.ReplaceAll(strings.ReplaceAll(v, ".", ""), ",", ".")
+	}
+	return strconv.ParseFloat(v, 64)
+}
+
+func parseReportDate(v string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05", time.DateOnly} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported date %q", v)
+}
+
+func isBlankRecord(rec []string) bool {
+	for _, v := range rec {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func firstNonEmptyField(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
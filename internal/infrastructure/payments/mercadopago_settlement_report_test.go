@@ -0,0 +1,86 @@
+package payments
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+)
+
+func TestParseMercadoPagoSettlementReport_SettlementFormat(t *testing.T) {
+	csv := "\ufeffEXTERNAL_REFERENCE;SOURCE_ID;TRANSACTION_TYPE;TRANSACTION_AMOUNT;TRANSACTION_DATE;FEE_AMOUNT;SETTLEMENT_NET_AMOUNT;SETTLEMENT_DATE\n" +
+		"est-1;111;SETTLEMENT;150.00;2026-03-01T10:00:00.000-03:00;-7.49;142.51;2026-03-15T00:00:00.000-03:00\n" +
+		";;;;;;;\n" +
+		"est-2;222;REFUND;-1.234,50;2026-03-02T11:00:00.000-03:00;0;-1234.50;2026-03-02T11:00:00.000-03:00\n" +
+		";333;WITHDRAWAL;-500;2026-03-03T09:00:00.000-03:00;0;-500;2026-03-03T09:00:00.000-03:00\n"
+
+	lines, err := ParseMercadoPagoSettlementReport(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d: %+v", len(lines), lines)
+	}
+
+	first := lines[0]
+	if first.Line != 2 || first.SourceID != "111" || first.ExternalReference != "est-1" || first.Type != entities.SettlementLinePayment {
+		t.Fatalf("unexpected first line: %+v", first)
+	}
+	if first.GrossAmount != 150 || first.FeeAmount != 7.49 || first.NetAmount != 142.51 {
+		t.Fatalf("unexpected amounts: %+v", first)
+	}
+	wantDate := time.Date(2026, 3, 1, 13, 0, 0, 0, time.UTC)
+	if first.TransactionDate == nil || !first.TransactionDate.Equal(wantDate) {
+		t.Fatalf("unexpected transaction date: %v", first.TransactionDate)
+	}
+
+	if lines[1].Line != 4 || lines[1].Type != entities.SettlementLineRefund || lines[1].GrossAmount != 1234.5 {
+		t.Fatalf("unexpected refund line: %+v", lines[1])
+	}
+	if lines[2].Type != entities.SettlementLineOther || lines[2].ProviderType != "WITHDRAWAL" {
+		t.Fatalf("unexpected withdrawal line: %+v", lines[2])
+	}
+}
+
+func TestParseMercadoPagoSettlementReport_ReleaseFormat(t *testing.T) {
+	csv := "DATE,SOURCE_ID,EXTERNAL_REFERENCE,RECORD_TYPE,DESCRIPTION,NET_CREDIT_AMOUNT,NET_DEBIT_AMOUNT,GROSS_AMOUNT,MP_FEE_AMOUNT,FINANCING_FEE_AMOUNT\n" +
+		"2026-03-01T00:00:00.000-03:00,,,initial_available_balance,,100,0,0,0,0\n" +
+		"2026-03-15T00:00:00.000-03:00,111,est-1,release,payment,142.51,0,150,-7.49,0\n" +
+		"2026-03-16T00:00:00.000-03:00,444,est-4,release,chargeback,0,80,80,0,0\n" +
+		"2026-03-31T00:00:00.000-03:00,,,total,,62.51,80,0,0,0\n"
+
+	lines, err := ParseMercadoPagoSettlementReport(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("expected balance/total rows skipped, got %+v", lines)
+	}
+	if lines[0].Type != entities.SettlementLinePayment || lines[0].GrossAmount != 150 || lines[0].NetAmount != 142.51 || lines[0].FeeAmount != 7.49 {
+		t.Fatalf("unexpected payment line: %+v", lines[0])
+	}
+	if lines[0].TransactionDate != nil || lines[0].SettlementDate == nil {
+		t.Fatalf("release reports only carry the release date: %+v", lines[0])
+	}
+	if lines[1].Type != entities.SettlementLineChargeback || lines[1].NetAmount != -80 {
+		t.Fatalf("unexpected chargeback line: %+v", lines[1])
+	}
+}
+
+func TestParseMercadoPagoSettlementReport_Errors(t *testing.T) {
+	cases := map[string]string{
+		"empty":          "",
+		"no source id":   "EXTERNAL_REFERENCE,TRANSACTION_TYPE\nest-1,SETTLEMENT\n",
+		"no type column": "SOURCE_ID,TRANSACTION_AMOUNT\n1,10\n",
+		"bad amount":     "SOURCE_ID,TRANSACTION_TYPE,TRANSACTION_AMOUNT\n1,SETTLEMENT,abc\n",
+		"bad date":       "SOURCE_ID,TRANSACTION_TYPE,TRANSACTION_DATE\n1,SETTLEMENT,ontem\n",
+	}
+	for name, raw := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseMercadoPagoSettlementReport(strings.NewReader(raw)); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
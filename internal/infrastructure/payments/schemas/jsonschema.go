@@ -0,0 +1,241 @@
+package schemas
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/mail"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// schema is the subset of JSON Schema (draft 2020-12) understood by the validator.
+//
+// Only the keywords used by the embedded provider schemas are supported; unknown
+// keywords are ignored, which keeps the schema files readable by standard tooling.
+type schema struct {
+	Type                 schemaType         `json:"type,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Properties           map[string]*schema `json:"properties,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *schema            `json:"items,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	AnyOf                []*schema          `json:"anyOf,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// schemaType accepts both `"type": "string"` and `"type": ["string", "null"]`.
+type schemaType []string
+
+func (t *schemaType) UnmarshalJSON(b []byte) error {
+	var single string
+	if err := json.Unmarshal(b, &single); err == nil {
+		*t = schemaType{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(b, &many); err != nil {
+		return err
+	}
+	*t = many
+	return nil
+}
+
+func parseSchema(b []byte) (*schema, error) {
+	var s schema
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, err
+	}
+	if err := s.compile(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (s *schema) compile() error {
+	if s == nil {
+		return nil
+	}
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", s.Pattern, err)
+		}
+		s.pattern = re
+	}
+	for _, p := range s.Properties {
+		if err := p.compile(); err != nil {
+			return err
+		}
+	}
+	for _, sub := range s.AnyOf {
+		if err := sub.compile(); err != nil {
+			return err
+		}
+	}
+	return s.Items.compile()
+}
+
+// validate returns the JSON pointers (RFC 6901) of every value violating the schema.
+func (s *schema) validate(v any, pointer string) []string {
+	if s == nil {
+		return nil
+	}
+
+	var out []string
+	if len(s.Type) > 0 && !s.matchesType(v) {
+		return []string{pointer}
+	}
+	if len(s.Enum) > 0 && !inEnum(s.Enum, v) {
+		return []string{pointer}
+	}
+	if len(s.AnyOf) > 0 && !s.matchesAnyOf(v, pointer) {
+		return []string{pointer}
+	}
+
+	switch val := v.(type) {
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := val[name]; !ok {
+				out = append(out, pointer+"/"+escapePointer(name))
+			}
+		}
+		for _, name := range sortedKeys(val) {
+			child := pointer + "/" + escapePointer(name)
+			if prop, ok := s.Properties[name]; ok {
+				out = append(out, prop.validate(val[name], child)...)
+				continue
+			}
+			if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				out = append(out, child)
+			}
+		}
+	case []any:
+		if s.MinItems != nil && len(val) < *s.MinItems {
+			out = append(out, pointer)
+		}
+		for i, item := range val {
+			out = append(out, s.Items.validate(item, fmt.Sprintf("%s/%d", pointer, i))...)
+		}
+	case string:
+		if !s.validString(val) {
+			out = append(out, pointer)
+		}
+	case float64:
+		if !s.validNumber(val) {
+			out = append(out, pointer)
+		}
+	}
+	return out
+}
+
+func (s *schema) matchesAnyOf(v any, pointer string) bool {
+	for _, sub := range s.AnyOf {
+		if len(sub.validate(v, pointer)) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *schema) matchesType(v any) bool {
+	for _, t := range s.Type {
+		switch t {
+		case "object":
+			if _, ok := v.(map[string]any); ok {
+				return true
+			}
+		case "array":
+			if _, ok := v.([]any); ok {
+				return true
+			}
+		case "string":
+			if _, ok := v.(string); ok {
+				return true
+			}
+		case "number":
+			if _, ok := v.(float64); ok {
+				return true
+			}
+		case "integer":
+			if f, ok := v.(float64); ok && f == math.Trunc(f) {
+				return true
+			}
+		case "boolean":
+			if _, ok := v.(bool); ok {
+				return true
+			}
+		case "null":
+			if v == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *schema) validString(v string) bool {
+	n := len([]rune(v))
+	if s.MinLength != nil && n < *s.MinLength {
+		return false
+	}
+	if s.MaxLength != nil && n > *s.MaxLength {
+		return false
+	}
+	if s.pattern != nil && !s.pattern.MatchString(v) {
+		return false
+	}
+	if s.Format == "email" {
+		addr, err := mail.ParseAddress(v)
+		if err != nil || addr.Address != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *schema) validNumber(v float64) bool {
+	if s.Minimum != nil && v < *s.Minimum {
+		return false
+	}
+	if s.ExclusiveMinimum != nil && v <= *s.ExclusiveMinimum {
+		return false
+	}
+	if s.Maximum != nil && v > *s.Maximum {
+		return false
+	}
+	return true
+}
+
+func inEnum(enum []any, v any) bool {
+	for _, e := range enum {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func escapePointer(token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	return strings.ReplaceAll(token, "/", "~1")
+}
@@ -0,0 +1,166 @@
+package schemas
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mecanica_xpto/internal/domain/entities"
+	"path"
+	"sort"
+	"strings"
+)
+
+// DefaultSchemaVersion is the schema set used when PAYMENT_SCHEMA_VERSION is not set.
+const DefaultSchemaVersion = "v1"
+
+var ErrUnknownSchemaVersion = errors.New("unknown payment schema version")
+
+//go:embed mercadopago
+var mercadoPagoSchemas embed.FS
+
+// methodMapping is mercadopago/{version}/methods.json: the method-specific schema of each
+// payment_type_id and, for payloads without one, of each payment_method_id. It is
+// versioned with the schemas, so a new brand is a schema release rather than a code change.
+type methodMapping struct {
+	PaymentTypes   map[string]string `json:"payment_types"`
+	PaymentMethods map[string]string `json:"payment_methods"`
+}
+
+// MercadoPagoPayloadValidator checks outgoing Mercado Pago payment payloads against the
+// embedded JSON schemas before they reach the provider.
+//
+// Schema layout (embedded):
+//   - mercadopago/{version}/common.json: fields required for every payment
+//   - mercadopago/{version}/methods.json: schema name per payment type and method
+//   - mercadopago/{version}/{name}.json: per payment method rules
+type MercadoPagoPayloadValidator struct {
+	version string
+	common  *schema
+	mapping methodMapping
+	methods map[string]*schema
+}
+
+func NewMercadoPagoPayloadValidator(version string) (*MercadoPagoPayloadValidator, error) {
+	version = strings.TrimSpace(version)
+	if version == "" {
+		version = DefaultSchemaVersion
+	}
+
+	dir := path.Join("mercadopago", version)
+	if _, err := mercadoPagoSchemas.ReadDir(dir); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSchemaVersion, version)
+	}
+
+	common, err := loadSchema(path.Join(dir, "common.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	mapping, err := loadMethodMapping(path.Join(dir, "methods.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	methods := map[string]*schema{}
+	for _, names := range []map[string]string{mapping.PaymentTypes, mapping.PaymentMethods} {
+		for _, name := range names {
+			if _, ok := methods[name]; ok {
+				continue
+			}
+			s, err := loadSchema(path.Join(dir, name+".json"))
+			if err != nil {
+				return nil, err
+			}
+			methods[name] = s
+		}
+	}
+	log.Printf("[payment][schema] mercado pago schemas loaded version=%s", version)
+
+	return &MercadoPagoPayloadValidator{version: version, common: common, mapping: mapping, methods: methods}, nil
+}
+
+// Validate returns the JSON pointers of every field violating the schemas.
+// An empty result means the payload is valid. The method schema is chosen by
+// payment_type_id when the payload has one, else by payment_method_id; one the mapping
+// does not know fails with entities.ErrUnsupportedPaymentMethod. A missing
+// payment_method_id is reported by the common schema.
+func (v *MercadoPagoPayloadValidator) Validate(_ context.Context, payload json.RawMessage) ([]string, error) {
+	var doc any
+	if err := json.Unmarshal(payload, &doc); err != nil {
+		return nil, err
+	}
+
+	violations := v.common.validate(doc, "")
+	if obj, ok := doc.(map[string]any); ok {
+		name, err := v.methodSchema(obj)
+		if err != nil {
+			return nil, err
+		}
+		if name != "" {
+			violations = append(violations, v.methods[name].validate(doc, "")...)
+		}
+	}
+	return dedupe(violations), nil
+}
+
+// methodSchema is the schema name of the payload's payment type or method; empty when
+// the payload has neither.
+func (v *MercadoPagoPayloadValidator) methodSchema(obj map[string]any) (string, error) {
+	if typeID, _ := obj["payment_type_id"].(string); strings.TrimSpace(typeID) != "" {
+		if name, ok := v.mapping.PaymentTypes[strings.ToLower(strings.TrimSpace(typeID))]; ok {
+			return name, nil
+		}
+		return "", fmt.Errorf("%w: payment_type_id %s", entities.ErrUnsupportedPaymentMethod, typeID)
+	}
+	if methodID, _ := obj["payment_method_id"].(string); strings.TrimSpace(methodID) != "" {
+		if name, ok := v.mapping.PaymentMethods[strings.ToLower(strings.TrimSpace(methodID))]; ok {
+			return name, nil
+		}
+		return "", fmt.Errorf("%w: %s", entities.ErrUnsupportedPaymentMethod, methodID)
+	}
+	return "", nil
+}
+
+func loadSchema(name string) (*schema, error) {
+	b, err := mercadoPagoSchemas.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	s, err := parseSchema(b)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema %s: %w", name, err)
+	}
+	return s, nil
+}
+
+func loadMethodMapping(name string) (methodMapping, error) {
+	b, err := mercadoPagoSchemas.ReadFile(name)
+	if err != nil {
+		return methodMapping{}, err
+	}
+	var m methodMapping
+	if err := json.Unmarshal(b, &m); err != nil {
+		return methodMapping{}, fmt.Errorf("invalid method mapping %s: %w", name, err)
+	}
+	return m, nil
+}
+
+func dedupe(pointers []string) []string {
+	if len(pointers) == 0 {
+		return nil
+	}
+	seen := make(map[string]struct{}, len(pointers))
+	out := make([]string, 0, len(pointers))
+	for _, p := range pointers {
+		if _, ok := seen[p]; ok {
+			continue
+		}
+		seen[p] = struct{}{}
+		out = append(out, p)
+	}
+	sort.Strings(out)
+	return out
+}
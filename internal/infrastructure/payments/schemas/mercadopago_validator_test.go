@@ -0,0 +1,99 @@
+package schemas
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"mecanica_xpto/internal/domain/entities"
+)
+
+func TestNewMercadoPagoPayloadValidator(t *testing.T) {
+	if _, err := NewMercadoPagoPayloadValidator(""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := NewMercadoPagoPayloadValidator("v999"); !errors.Is(err, ErrUnknownSchemaVersion) {
+		t.Fatalf("expected ErrUnknownSchemaVersion, got %v", err)
+	}
+}
+
+func TestMercadoPagoPayloadValidator_Validate(t *testing.T) {
+	v, err := NewMercadoPagoPayloadValidator(DefaultSchemaVersion)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		payload string
+		want    []string
+	}{
+		{
+			name:    "valid pix",
+			payload: `{"transaction_amount":10,"payment_method_id":"pix","external_reference":"est-1","description":"Estimate est-1","payer":{"type":"customer","email":"x@test.com"}}`,
+		},
+		{
+			name:    "valid card",
+			payload: `{"transaction_amount":10.5,"payment_method_id":"visa","token":"tok","installments":1,"external_reference":"est-1","description":"d","payer":{"id":"123"}}`,
+		},
+		{
+			name:    "card missing token and fractional installments",
+			payload: `{"transaction_amount":10,"payment_method_id":"master","installments":1.5,"external_reference":"est-1","description":"d","payer":{"email":"x@test.com"}}`,
+			want:    []string{"/installments", "/token"},
+		},
+		{
+			name:    "pix with non positive amount and invalid email",
+			payload: `{"transaction_amount":0,"payment_method_id":"pix","external_reference":"est-1","description":"d","payer":{"email":"not-an-email"}}`,
+			want:    []string{"/payer/email", "/transaction_amount"},
+		},
+		{
+			name:    "ticket missing identification",
+			payload: `{"transaction_amount":10,"payment_method_id":"bolbradesco","external_reference":"est-1","description":"d","payer":{"email":"x@test.com","first_name":"A","last_name":"B"}}`,
+			want:    []string{"/payer/identification"},
+		},
+		{
+			name:    "payer without email or id",
+			payload: `{"transaction_amount":10,"payment_method_id":"visa","token":"t","installments":1,"external_reference":"e","description":"d","payer":{"type":"customer"}}`,
+			want:    []string{"/payer"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := v.Validate(context.Background(), json.RawMessage(tt.payload))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("payment type picks the schema of a brand not mapped", func(t *testing.T) {
+		payload := `{"transaction_amount":10,"payment_method_id":"newdebit","payment_type_id":"debit_card","installments":1,"external_reference":"est-1","description":"d","payer":{"email":"x@test.com"}}`
+		got, err := v.Validate(context.Background(), json.RawMessage(payload))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(got, []string{"/token"}) {
+			t.Fatalf("expected card schema violations, got %v", got)
+		}
+	})
+
+	t.Run("unknown payment type", func(t *testing.T) {
+		payload := `{"transaction_amount":10,"payment_method_id":"account_money","payment_type_id":"account_money","external_reference":"est-1","description":"d","payer":{"email":"x@test.com"}}`
+		if _, err := v.Validate(context.Background(), json.RawMessage(payload)); !errors.Is(err, entities.ErrUnsupportedPaymentMethod) {
+			t.Fatalf("expected ErrUnsupportedPaymentMethod, got %v", err)
+		}
+	})
+
+	t.Run("unknown method is not validated as a card", func(t *testing.T) {
+		payload := `{"transaction_amount":10,"payment_method_id":"pix_automatico","external_reference":"est-1","description":"d","payer":{"email":"x@test.com"}}`
+		if _, err := v.Validate(context.Background(), json.RawMessage(payload)); !errors.Is(err, entities.ErrUnsupportedPaymentMethod) {
+			t.Fatalf("expected ErrUnsupportedPaymentMethod, got %v", err)
+		}
+	})
+}
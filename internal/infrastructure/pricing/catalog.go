@@ -0,0 +1,35 @@
+package pricing
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"mecanica_xpto/internal/domain/entities"
+)
+
+// LoadCatalogPolicyFromEnv reads CATALOG_VALIDATION (off, flag or reject; default off) and
+// CATALOG_PRICE_TOLERANCE, the accepted deviation from the list price in percent (default 0).
+func LoadCatalogPolicyFromEnv() (entities.CatalogPolicy, error) {
+	return parseCatalogPolicy(os.Getenv("CATALOG_VALIDATION"), os.Getenv("CATALOG_PRICE_TOLERANCE"))
+}
+
+func parseCatalogPolicy(mode, tolerance string) (entities.CatalogPolicy, error) {
+	policy := entities.CatalogPolicy{Mode: entities.CatalogMode(strings.ToLower(strings.TrimSpace(mode)))}
+	switch policy.Mode {
+	case "":
+		policy.Mode = entities.CatalogModeOff
+	case entities.CatalogModeOff, entities.CatalogModeFlag, entities.CatalogModeReject:
+	default:
+		return entities.CatalogPolicy{}, fmt.Errorf("invalid CATALOG_VALIDATION %q", mode)
+	}
+	if tolerance = strings.TrimSpace(tolerance); tolerance != "" {
+		v, err := strconv.ParseFloat(strings.Replace(tolerance, ",", ".", 1), 64)
+		if err != nil || v < 0 {
+			return entities.CatalogPolicy{}, fmt.Errorf("invalid CATALOG_PRICE_TOLERANCE %q", tolerance)
+		}
+		policy.Tolerance = v
+	}
+	return policy, nil
+}
@@ -0,0 +1,23 @@
+package pricing
+
+import (
+	"testing"
+
+	"mecanica_xpto/internal/domain/entities"
+)
+
+func TestParseCatalogPolicy(t *testing.T) {
+	policy, err := parseCatalogPolicy("", "")
+	if err != nil || policy.Mode != entities.CatalogModeOff || policy.Tolerance != 0 {
+		t.Fatalf("unexpected default policy: %+v err=%v", policy, err)
+	}
+	policy, err = parseCatalogPolicy(" Reject ", "2,5")
+	if err != nil || policy.Mode != entities.CatalogModeReject || policy.Tolerance != 2.5 {
+		t.Fatalf("unexpected policy: %+v err=%v", policy, err)
+	}
+	for _, c := range [][2]string{{"warn", ""}, {"flag", "abc"}, {"flag", "-1"}} {
+		if _, err := parseCatalogPolicy(c[0], c[1]); err == nil {
+			t.Fatalf("expected error for %v", c)
+		}
+	}
+}
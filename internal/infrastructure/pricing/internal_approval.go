@@ -0,0 +1,27 @@
+package pricing
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// LoadInternalApprovalThresholdFromEnv reads ESTIMATE_INTERNAL_APPROVAL_THRESHOLD, the price
+// (in reais) above which estimates need a manager's sign-off. Empty or 0 disables it.
+func LoadInternalApprovalThresholdFromEnv() (float64, error) {
+	return parseInternalApprovalThreshold(os.Getenv("ESTIMATE_INTERNAL_APPROVAL_THRESHOLD"))
+}
+
+func parseInternalApprovalThreshold(raw string) (float64, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil || v < 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+		return 0, fmt.Errorf("invalid ESTIMATE_INTERNAL_APPROVAL_THRESHOLD %q", raw)
+	}
+	return v, nil
+}
@@ -0,0 +1,17 @@
+package pricing
+
+import "testing"
+
+func TestParseInternalApprovalThreshold(t *testing.T) {
+	for raw, want := range map[string]float64{"": 0, " 0 ": 0, "5000": 5000, "2500.50": 2500.5} {
+		got, err := parseInternalApprovalThreshold(raw)
+		if err != nil || got != want {
+			t.Fatalf("parse %q: got %v err=%v, want %v", raw, got, err, want)
+		}
+	}
+	for _, raw := range []string{"abc", "-1", "NaN", "Inf"} {
+		if _, err := parseInternalApprovalThreshold(raw); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+}
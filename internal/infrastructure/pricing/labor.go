@@ -0,0 +1,56 @@
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"mecanica_xpto/internal/domain/entities"
+)
+
+// laborRatesFile is the JSON in LABOR_RATES_FILE: the hourly rate of each skill tier and
+// the tier of services quoted in hours without one.
+type laborRatesFile struct {
+	DefaultTier string             `json:"default_tier"`
+	Rates       map[string]float64 `json:"rates"`
+}
+
+// LoadLaborRatesFromEnv reads LABOR_RATES_FILE; without it services quoted in hours are
+// rejected (nil).
+func LoadLaborRatesFromEnv() (*entities.LaborRateTable, error) {
+	path := strings.TrimSpace(os.Getenv("LABOR_RATES_FILE"))
+	if path == "" {
+		return nil, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read labor rates: %w", err)
+	}
+	rates, err := parseLaborRates(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &rates, nil
+}
+
+func parseLaborRates(raw []byte) (entities.LaborRateTable, error) {
+	var file laborRatesFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return entities.LaborRateTable{}, fmt.Errorf("parse labor rates: %w", err)
+	}
+	table := entities.LaborRateTable{
+		DefaultTier: entities.NormalizeSkillTier(file.DefaultTier),
+		Rates:       make(map[string]float64, len(file.Rates)),
+	}
+	for tier, rate := range file.Rates {
+		table.Rates[entities.NormalizeSkillTier(tier)] = rate
+	}
+	if len(table.Rates) == 0 || len(table.Rates) != len(file.Rates) {
+		return entities.LaborRateTable{}, fmt.Errorf("labor rates: %w", entities.ErrInvalidLaborRates)
+	}
+	if err := table.Validate(); err != nil {
+		return entities.LaborRateTable{}, fmt.Errorf("labor rates: %w", err)
+	}
+	return table, nil
+}
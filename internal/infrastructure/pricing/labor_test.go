@@ -0,0 +1,36 @@
+package pricing
+
+import (
+	"errors"
+	"testing"
+
+	"mecanica_xpto/internal/domain/entities"
+)
+
+func TestParseLaborRates(t *testing.T) {
+	table, err := parseLaborRates([]byte(`{"default_tier": "Pleno", "rates": {"junior": 80, "Pleno": 120, "senior": 160}}`))
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if tier, rate, ok := table.Rate(""); !ok || tier != "pleno" || rate != 120 {
+		t.Fatalf("unexpected default rate: %s %v %v", tier, rate, ok)
+	}
+	if _, rate, ok := table.Rate(" SENIOR "); !ok || rate != 160 {
+		t.Fatalf("unexpected senior rate: %v %v", rate, ok)
+	}
+	if _, _, ok := table.Rate("estagiario"); ok {
+		t.Fatalf("expected unknown tier")
+	}
+
+	invalid := []string{
+		`{"rates": {}}`,
+		`{"rates": {"junior": 0}}`,
+		`{"default_tier": "master", "rates": {"junior": 80}}`,
+		`{"rates": {"Junior": 80, "junior": 90}}`,
+	}
+	for _, raw := range invalid {
+		if _, err := parseLaborRates([]byte(raw)); !errors.Is(err, entities.ErrInvalidLaborRates) {
+			t.Fatalf("expected ErrInvalidLaborRates for %s, got %v", raw, err)
+		}
+	}
+}
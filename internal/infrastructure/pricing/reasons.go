@@ -0,0 +1,45 @@
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"mecanica_xpto/internal/domain/entities"
+)
+
+// LoadReasonCatalogFromEnv reads ESTIMATE_REASONS_FILE, a JSON with the "rejeicao" and
+// "cancelamento" reason lists; without it the default catalog is used (nil).
+func LoadReasonCatalogFromEnv() (*entities.ReasonCatalog, error) {
+	path := strings.TrimSpace(os.Getenv("ESTIMATE_REASONS_FILE"))
+	if path == "" {
+		return nil, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read estimate reasons: %w", err)
+	}
+	catalog, err := parseReasonCatalog(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &catalog, nil
+}
+
+func parseReasonCatalog(raw []byte) (entities.ReasonCatalog, error) {
+	var catalog entities.ReasonCatalog
+	if err := json.Unmarshal(raw, &catalog); err != nil {
+		return entities.ReasonCatalog{}, fmt.Errorf("parse estimate reasons: %w", err)
+	}
+	for _, codes := range [][]entities.ReasonCode{catalog.Rejection, catalog.Cancellation} {
+		for i := range codes {
+			codes[i].Code = entities.NormalizeReasonCode(codes[i].Code)
+			codes[i].Label = strings.TrimSpace(codes[i].Label)
+		}
+	}
+	if err := catalog.Validate(); err != nil {
+		return entities.ReasonCatalog{}, fmt.Errorf("estimate reasons: %w", err)
+	}
+	return catalog, nil
+}
@@ -0,0 +1,37 @@
+package pricing
+
+import (
+	"errors"
+	"testing"
+
+	"mecanica_xpto/internal/domain/entities"
+)
+
+func TestParseReasonCatalog(t *testing.T) {
+	catalog, err := parseReasonCatalog([]byte(`{
+		"rejeicao": [
+			{"code": " Preco ", "label": "Preço"},
+			{"code": "outro", "label": "Outro motivo", "text_required": true}
+		],
+		"cancelamento": [{"code": "duplicado", "label": "Orçamento duplicado"}]
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(catalog.Rejection) != 2 || catalog.Rejection[0].Code != "preco" || !catalog.Rejection[1].TextRequired {
+		t.Fatalf("unexpected catalog: %+v", catalog)
+	}
+	if _, ok := catalog.Lookup(entities.EstimateStatusCancelado, "preco"); ok {
+		t.Fatalf("rejection code must not be a cancellation reason")
+	}
+
+	for _, bad := range []string{
+		`{"rejeicao": [{"code": "preco", "label": "Preço"}]}`,
+		`{"rejeicao": [{"code": "preco", "label": ""}], "cancelamento": [{"code": "x", "label": "X"}]}`,
+		`{"rejeicao": [{"code": "preco", "label": "A"}, {"code": "PRECO", "label": "B"}], "cancelamento": [{"code": "x", "label": "X"}]}`,
+	} {
+		if _, err := parseReasonCatalog([]byte(bad)); !errors.Is(err, entities.ErrInvalidReasonCatalog) {
+			t.Fatalf("expected invalid catalog for %s, got %v", bad, err)
+		}
+	}
+}
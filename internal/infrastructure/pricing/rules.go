@@ -0,0 +1,122 @@
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+)
+
+// rulesFile is the JSON in PRICING_RULES_FILE. version identifies the rule definitions and
+// is stored on every estimate priced with them; schedules are read in time_zone.
+type rulesFile struct {
+	Version  string `json:"version"`
+	TimeZone string `json:"time_zone"`
+	Rules    []struct {
+		ID            string   `json:"id"`
+		Name          string   `json:"name"`
+		Type          string   `json:"type"`
+		Percent       float64  `json:"percent"`
+		Amount        float64  `json:"amount"`
+		ItemKind      string   `json:"item_kind"`
+		Categories    []string `json:"categories"`
+		Segments      []string `json:"segments"`
+		Weekdays      []string `json:"weekdays"`
+		BusinessHours *struct {
+			Opens  string `json:"opens"`
+			Closes string `json:"closes"`
+		} `json:"business_hours"`
+	} `json:"rules"`
+}
+
+var weekdays = map[string]time.Weekday{
+	"domingo": time.Sunday,
+	"segunda": time.Monday,
+	"terca":   time.Tuesday,
+	"terça":   time.Tuesday,
+	"quarta":  time.Wednesday,
+	"quinta":  time.Thursday,
+	"sexta":   time.Friday,
+	"sabado":  time.Saturday,
+	"sábado":  time.Saturday,
+}
+
+// LoadRulesFromEnv reads PRICING_RULES_FILE; without it no pricing rule applies (nil).
+func LoadRulesFromEnv() (*entities.PricingRuleSet, error) {
+	path := strings.TrimSpace(os.Getenv("PRICING_RULES_FILE"))
+	if path == "" {
+		return nil, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read pricing rules: %w", err)
+	}
+	rules, err := parseRules(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
+
+func parseRules(raw []byte) (entities.PricingRuleSet, error) {
+	file := rulesFile{TimeZone: "America/Sao_Paulo"}
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return entities.PricingRuleSet{}, fmt.Errorf("parse pricing rules: %w", err)
+	}
+	loc, err := time.LoadLocation(file.TimeZone)
+	if err != nil {
+		return entities.PricingRuleSet{}, fmt.Errorf("pricing rules time_zone: %w", err)
+	}
+
+	set := entities.PricingRuleSet{Version: strings.TrimSpace(file.Version), Location: loc}
+	for i, r := range file.Rules {
+		rule := entities.PricingRule{
+			ID:         strings.TrimSpace(r.ID),
+			Name:       strings.TrimSpace(r.Name),
+			Type:       entities.PricingRuleType(strings.TrimSpace(r.Type)),
+			Percent:    r.Percent,
+			Amount:     r.Amount,
+			ItemKind:   entities.EstimateItemKind(strings.TrimSpace(r.ItemKind)),
+			Categories: r.Categories,
+			Segments:   r.Segments,
+		}
+		for _, name := range r.Weekdays {
+			day, ok := weekdays[strings.ToLower(strings.TrimSpace(name))]
+			if !ok {
+				return entities.PricingRuleSet{}, fmt.Errorf("pricing rules[%d] weekday %q: %w", i, name, entities.ErrInvalidPricingRule)
+			}
+			rule.Weekdays = append(rule.Weekdays, day)
+		}
+		if h := r.BusinessHours; h != nil {
+			opens, err := minuteOfDay(h.Opens)
+			if err != nil {
+				return entities.PricingRuleSet{}, fmt.Errorf("pricing rules[%d] business_hours.opens: %w", i, err)
+			}
+			closes, err := minuteOfDay(h.Closes)
+			if err != nil {
+				return entities.PricingRuleSet{}, fmt.Errorf("pricing rules[%d] business_hours.closes: %w", i, err)
+			}
+			rule.BusinessHours = &entities.BusinessHours{Opens: opens, Closes: closes}
+		}
+		set.Rules = append(set.Rules, rule)
+	}
+	if err := set.Validate(); err != nil {
+		return entities.PricingRuleSet{}, fmt.Errorf("pricing rules: %w", err)
+	}
+	return set, nil
+}
+
+// minuteOfDay parses "HH:MM"; "24:00" closes at midnight.
+func minuteOfDay(v string) (int, error) {
+	if strings.TrimSpace(v) == "24:00" {
+		return 24 * 60, nil
+	}
+	t, err := time.Parse("15:04", strings.TrimSpace(v))
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
@@ -0,0 +1,67 @@
+package pricing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+)
+
+func TestParseRules(t *testing.T) {
+	set, err := parseRules([]byte(`{
+		"version": "2026-05",
+		"rules": [
+			{"id": "markup-freios", "name": "Markup freios", "type": "markup_pecas", "percent": 20, "categories": ["freios"]},
+			{"id": "minimo", "name": "Mão de obra mínima", "type": "minimo_mao_de_obra", "amount": 120},
+			{"id": "fora-horario", "name": "Acréscimo fora do horário", "type": "acrescimo_horario", "percent": 10,
+			 "item_kind": "servico", "weekdays": ["sábado", "domingo"], "business_hours": {"opens": "08:00", "closes": "18:00"}},
+			{"id": "frota", "name": "Desconto frota", "type": "desconto_segmento", "percent": 5, "segments": ["frota"]}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if set.Version != "2026-05" || len(set.Rules) != 4 || set.Rules[2].BusinessHours.Closes != 18*60 {
+		t.Fatalf("unexpected rules: %+v", set)
+	}
+
+	items := []entities.EstimateItem{
+		{Kind: entities.EstimateItemServico, Name: "Troca de pastilhas", Total: 100},
+		{Kind: entities.EstimateItemPeca, Name: "Pastilha", Category: "Freios", Total: 200},
+		{Kind: entities.EstimateItemPeca, Name: "Óleo", Category: "lubrificantes", Total: 50},
+	}
+	// 19:30 of a Tuesday in São Paulo: after hours.
+	at := time.Date(2026, 5, 5, 22, 30, 0, 0, time.UTC)
+	lines, adjustments, total := set.Apply(items, entities.PricingContext{At: at, CustomerSegment: "Frota"})
+	if len(adjustments) != 4 {
+		t.Fatalf("unexpected adjustments: %+v", adjustments)
+	}
+	// +40 markup, +20 labor (100 → 120), +12 surcharge (10% of 120), -21.1 fleet (5% of 422).
+	if adjustments[0].Amount != 40 || adjustments[1].Amount != 20 || adjustments[2].Amount != 12 || adjustments[3].Amount != -21.1 {
+		t.Fatalf("unexpected amounts: %+v", adjustments)
+	}
+	if adjustments[2].Reason != "fora do horário 08:00–18:00" || total != 50.9 {
+		t.Fatalf("unexpected adjustments: %+v total=%v", adjustments, total)
+	}
+	if lines[0].Total != 125.4 || lines[0].Adjustment != 25.4 || lines[2].Total != 47.5 {
+		t.Fatalf("unexpected lines: %+v", lines)
+	}
+
+	// During business hours on a weekday only the markup and the minimum apply.
+	_, adjustments, _ = set.Apply(items, entities.PricingContext{At: time.Date(2026, 5, 5, 13, 0, 0, 0, time.UTC)})
+	if len(adjustments) != 2 {
+		t.Fatalf("unexpected adjustments: %+v", adjustments)
+	}
+
+	for _, bad := range []string{
+		`{"rules": [{"id": "x", "type": "markup_pecas", "percent": 10}]}`,
+		`{"version": "1", "rules": [{"id": "x", "type": "acrescimo_horario", "percent": 10}]}`,
+		`{"version": "1", "rules": [{"id": "x", "type": "markup_pecas", "percent": 10}, {"id": "x", "type": "minimo_mao_de_obra", "amount": 1}]}`,
+		`{"version": "1", "rules": [{"id": "x", "type": "acrescimo_horario", "percent": 10, "weekdays": ["feriado"]}]}`,
+	} {
+		if _, err := parseRules([]byte(bad)); !errors.Is(err, entities.ErrInvalidPricingRule) {
+			t.Fatalf("expected ErrInvalidPricingRule for %s, got %v", bad, err)
+		}
+	}
+}
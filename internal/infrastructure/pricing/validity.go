@@ -0,0 +1,46 @@
+package pricing
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultExpirationInterval = 5 * time.Minute
+
+// EstimateValidity is how long estimates can be approved and how often expired ones are
+// swept.
+type EstimateValidity struct {
+	// Days is the default validity of an estimate; 0 means estimates do not expire.
+	Days int
+	// SweepInterval is how often the API expires the estimates past their validity; 0
+	// disables the sweeper.
+	SweepInterval time.Duration
+}
+
+// LoadValidityFromEnv reads ESTIMATE_VALIDITY_DAYS (default 0) and
+// ESTIMATE_EXPIRATION_INTERVAL, a Go duration (default 5m).
+func LoadValidityFromEnv() (EstimateValidity, error) {
+	return parseValidity(os.Getenv("ESTIMATE_VALIDITY_DAYS"), os.Getenv("ESTIMATE_EXPIRATION_INTERVAL"))
+}
+
+func parseValidity(days, interval string) (EstimateValidity, error) {
+	validity := EstimateValidity{SweepInterval: defaultExpirationInterval}
+	if days = strings.TrimSpace(days); days != "" {
+		v, err := strconv.Atoi(days)
+		if err != nil || v < 0 {
+			return EstimateValidity{}, fmt.Errorf("invalid ESTIMATE_VALIDITY_DAYS %q", days)
+		}
+		validity.Days = v
+	}
+	if interval = strings.TrimSpace(interval); interval != "" {
+		v, err := time.ParseDuration(interval)
+		if err != nil || v < 0 {
+			return EstimateValidity{}, fmt.Errorf("invalid ESTIMATE_EXPIRATION_INTERVAL %q", interval)
+		}
+		validity.SweepInterval = v
+	}
+	return validity, nil
+}
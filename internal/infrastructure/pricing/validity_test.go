@@ -0,0 +1,22 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseValidity(t *testing.T) {
+	validity, err := parseValidity("", "")
+	if err != nil || validity.Days != 0 || validity.SweepInterval != 5*time.Minute {
+		t.Fatalf("unexpected default validity: %+v err=%v", validity, err)
+	}
+	validity, err = parseValidity(" 15 ", "0")
+	if err != nil || validity.Days != 15 || validity.SweepInterval != 0 {
+		t.Fatalf("unexpected validity: %+v err=%v", validity, err)
+	}
+	for _, c := range [][2]string{{"abc", ""}, {"-1", ""}, {"7", "5"}, {"7", "-1m"}} {
+		if _, err := parseValidity(c[0], c[1]); err == nil {
+			t.Fatalf("expected error for %v", c)
+		}
+	}
+}
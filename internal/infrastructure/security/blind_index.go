@@ -0,0 +1,33 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"mecanica_xpto/internal/usecase/interfaces"
+)
+
+// HMACBlindIndex hashes normalized personal data with HMAC-SHA256.
+//
+// The key must stay stable across deployments (changing it invalidates every stored
+// index); it is independent from the encryption keys so these can be rotated freely.
+type HMACBlindIndex struct {
+	key []byte
+}
+
+var _ interfaces.IBlindIndex = (*HMACBlindIndex)(nil)
+
+func NewHMACBlindIndex(key []byte) *HMACBlindIndex {
+	return &HMACBlindIndex{key: key}
+}
+
+// Hash returns "" for empty values so absent fields are never indexed.
+func (b *HMACBlindIndex) Hash(value string) string {
+	if value == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, b.key)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,122 @@
+package security
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"mecanica_xpto/internal/usecase/interfaces"
+)
+
+// NewKeyProviderFromEnv builds the key provider used to protect payer personal data.
+//
+// Supported env vars:
+//   - PII_KEY_FILE: current local master key file (base64, 32 bytes)
+//   - PII_PREVIOUS_KEY_FILES: comma-separated key files kept to decrypt older payments
+//
+// The key is required outside development; in development it returns (nil, nil) when no
+// key is configured, leaving encryption disabled. Production deployments backed by a KMS
+// build a KMSKeyProvider instead.
+func NewKeyProviderFromEnv() (interfaces.IKeyProvider, error) {
+	current := strings.TrimSpace(os.Getenv("PII_KEY_FILE"))
+	if current == "" {
+		if !DevelopmentMode() {
+			return nil, errors.New("PII_KEY_FILE is required outside development (GIN_MODE=debug)")
+		}
+		return nil, nil
+	}
+
+	var previous []string
+	for _, path := range strings.Split(os.Getenv("PII_PREVIOUS_KEY_FILES"), ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			previous = append(previous, path)
+		}
+	}
+	provider, err := NewLocalKeyProvider(current, previous...)
+	if err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+// developmentMode tells whether the process runs in local development (GIN_MODE debug or
+// test), the only place the public development keys below are accepted.
+func DevelopmentMode() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("GIN_MODE"))) {
+	case "debug", "test":
+		return true
+	}
+	return false
+}
+
+// devBlindIndexKey is only used when PII_INDEX_KEY is not set in development.
+const devBlindIndexKey = "billing-service-dev-blind-index"
+
+// NewBlindIndexFromEnv builds the payer blind index from PII_INDEX_KEY (base64). The key
+// is required outside development: the development key is public, and a blind index of
+// low-entropy data (CPF, phone) under a known key is reversed by brute force.
+func NewBlindIndexFromEnv() (*HMACBlindIndex, error) {
+	raw := strings.TrimSpace(os.Getenv("PII_INDEX_KEY"))
+	if raw == "" {
+		if !DevelopmentMode() {
+			return nil, errors.New("PII_INDEX_KEY is required outside development (GIN_MODE=debug)")
+		}
+		log.Printf("[security] PII_INDEX_KEY not set; using development blind index key")
+		return NewHMACBlindIndex([]byte(devBlindIndexKey)), nil
+	}
+	key, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil || len(key) < 16 {
+		return nil, errors.New("invalid PII_INDEX_KEY: expected at least 16 bytes encoded in base64")
+	}
+	return NewHMACBlindIndex(key), nil
+}
+
+// devLinkKey is only used when APPROVAL_LINK_KEY is not set in development.
+const devLinkKey = "billing-service-dev-approval-link"
+
+// NewLinkSignerFromEnv builds the approval link signer from APPROVAL_LINK_KEY (base64). The
+// key is required outside development: anyone can sign approval links with the public
+// development key.
+func NewLinkSignerFromEnv() (*HMACLinkSigner, error) {
+	raw := strings.TrimSpace(os.Getenv("APPROVAL_LINK_KEY"))
+	if raw == "" {
+		if !DevelopmentMode() {
+			return nil, errors.New("APPROVAL_LINK_KEY is required outside development (GIN_MODE=debug)")
+		}
+		log.Printf("[security] APPROVAL_LINK_KEY not set; using development approval link key")
+		return NewHMACLinkSigner([]byte(devLinkKey)), nil
+	}
+	key, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil || len(key) < 32 {
+		return nil, errors.New("invalid APPROVAL_LINK_KEY: expected at least 32 bytes encoded in base64")
+	}
+	return NewHMACLinkSigner(key), nil
+}
+
+// ApprovalLinkConfig is where approval links point to and how long they are valid (zero
+// keeps the use case default).
+type ApprovalLinkConfig struct {
+	BaseURL string
+	TTL     time.Duration
+}
+
+// LoadApprovalLinkConfigFromEnv reads APPROVAL_LINK_BASE_URL, the page the customer opens
+// (default /v1/approval of this API), and APPROVAL_LINK_TTL (e.g. 72h).
+func LoadApprovalLinkConfigFromEnv() (ApprovalLinkConfig, error) {
+	cfg := ApprovalLinkConfig{BaseURL: strings.TrimSpace(os.Getenv("APPROVAL_LINK_BASE_URL"))}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "/v1/approval"
+	}
+	if raw := strings.TrimSpace(os.Getenv("APPROVAL_LINK_TTL")); raw != "" {
+		ttl, err := time.ParseDuration(raw)
+		if err != nil || ttl <= 0 {
+			return ApprovalLinkConfig{}, fmt.Errorf("invalid APPROVAL_LINK_TTL %q", raw)
+		}
+		cfg.TTL = ttl
+	}
+	return cfg, nil
+}
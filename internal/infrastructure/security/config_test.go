@@ -0,0 +1,66 @@
+package security
+
+import "testing"
+
+func TestNewBlindIndexFromEnv(t *testing.T) {
+	t.Setenv("PII_INDEX_KEY", "")
+	t.Setenv("GIN_MODE", "release")
+	if _, err := NewBlindIndexFromEnv(); err == nil {
+		t.Fatalf("expected error without PII_INDEX_KEY outside development")
+	}
+	t.Setenv("GIN_MODE", "")
+	if _, err := NewBlindIndexFromEnv(); err == nil {
+		t.Fatalf("expected error without PII_INDEX_KEY and GIN_MODE")
+	}
+
+	t.Setenv("GIN_MODE", "debug")
+	if idx, err := NewBlindIndexFromEnv(); err != nil || idx == nil {
+		t.Fatalf("expected development key, got %v", err)
+	}
+
+	t.Setenv("GIN_MODE", "release")
+	t.Setenv("PII_INDEX_KEY", "c2hvcnQ=")
+	if _, err := NewBlindIndexFromEnv(); err == nil {
+		t.Fatalf("expected error for a short key")
+	}
+	t.Setenv("PII_INDEX_KEY", "J9LqKNdthVR53XxCIzBOxETky6jkwo00/0HKcMXu8ok=")
+	if idx, err := NewBlindIndexFromEnv(); err != nil || idx == nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestNewLinkSignerFromEnv(t *testing.T) {
+	t.Setenv("APPROVAL_LINK_KEY", "")
+	t.Setenv("GIN_MODE", "release")
+	if _, err := NewLinkSignerFromEnv(); err == nil {
+		t.Fatalf("expected error without APPROVAL_LINK_KEY outside development")
+	}
+
+	t.Setenv("GIN_MODE", "debug")
+	if signer, err := NewLinkSignerFromEnv(); err != nil || signer == nil {
+		t.Fatalf("expected development key, got %v", err)
+	}
+
+	t.Setenv("GIN_MODE", "release")
+	t.Setenv("APPROVAL_LINK_KEY", "c2hvcnQ=")
+	if _, err := NewLinkSignerFromEnv(); err == nil {
+		t.Fatalf("expected error for a short key")
+	}
+	t.Setenv("APPROVAL_LINK_KEY", "aNI3cEgSLDIeAvJcVsyjpRTG3vPrNcwtGNipAlRqeEo=")
+	if signer, err := NewLinkSignerFromEnv(); err != nil || signer == nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestNewKeyProviderFromEnv(t *testing.T) {
+	t.Setenv("PII_KEY_FILE", "")
+	t.Setenv("GIN_MODE", "release")
+	if _, err := NewKeyProviderFromEnv(); err == nil {
+		t.Fatalf("expected error without PII_KEY_FILE outside development")
+	}
+
+	t.Setenv("GIN_MODE", "debug")
+	if keys, err := NewKeyProviderFromEnv(); err != nil || keys != nil {
+		t.Fatalf("expected encryption disabled in development, got %v %v", keys, err)
+	}
+}
@@ -0,0 +1,51 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// KMSClient is the subset of a KMS API used for envelope encryption.
+//
+// It mirrors AWS KMS GenerateDataKey (AES_256) and Decrypt, so a thin adapter over the
+// AWS SDK (or any compatible service) can be plugged in production.
+type KMSClient interface {
+	GenerateDataKey(ctx context.Context, keyID string) (plaintext []byte, ciphertextBlob []byte, err error)
+	Decrypt(ctx context.Context, keyID string, ciphertextBlob []byte) ([]byte, error)
+}
+
+// KMSKeyProvider issues data keys from a managed KMS key.
+type KMSKeyProvider struct {
+	client KMSClient
+	keyID  string
+}
+
+func NewKMSKeyProvider(client KMSClient, keyID string) (*KMSKeyProvider, error) {
+	if client == nil || keyID == "" {
+		return nil, errors.New("kms key provider requires a client and a key id")
+	}
+	return &KMSKeyProvider{client: client, keyID: keyID}, nil
+}
+
+func (p *KMSKeyProvider) CurrentKeyID() string {
+	return p.keyID
+}
+
+func (p *KMSKeyProvider) GenerateDataKey(ctx context.Context) ([]byte, []byte, string, error) {
+	plaintext, wrapped, err := p.client.GenerateDataKey(ctx, p.keyID)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return plaintext, wrapped, p.keyID, nil
+}
+
+// DecryptDataKey unwraps keys issued by any key the KMS client can access (e.g. before
+// a key alias was rotated), so only the key id recorded with the payment is needed.
+func (p *KMSKeyProvider) DecryptDataKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	plaintext, err := p.client.Decrypt(ctx, keyID, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("kms decrypt: %w", err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,71 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"mecanica_xpto/internal/usecase/interfaces"
+)
+
+var ErrInvalidLinkToken = errors.New("invalid link token")
+
+// HMACLinkSigner signs approval link tokens with HMAC-SHA256.
+//
+// A token is base64url("<id>.<expires unix>") + "." + base64url(mac); changing the key
+// invalidates every link issued, which is the way to revoke them all. OTP digests use a
+// key derived from the same one.
+type HMACLinkSigner struct {
+	key []byte
+}
+
+var _ interfaces.ILinkSigner = (*HMACLinkSigner)(nil)
+
+func NewHMACLinkSigner(key []byte) *HMACLinkSigner {
+	return &HMACLinkSigner{key: key}
+}
+
+func (s *HMACLinkSigner) Sign(id string, expiresAt time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(id + "." + strconv.FormatInt(expiresAt.Unix(), 10)))
+	return payload + "." + base64.RawURLEncoding.EncodeToString(s.mac("link", payload))
+}
+
+// Verify checks the signature before reading the payload, so a forged token is never
+// parsed.
+func (s *HMACLinkSigner) Verify(token string, at time.Time) (string, error) {
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", ErrInvalidLinkToken
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, s.mac("link", payload)) {
+		return "", ErrInvalidLinkToken
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", ErrInvalidLinkToken
+	}
+	id, exp, ok := strings.Cut(string(raw), ".")
+	unix, err := strconv.ParseInt(exp, 10, 64)
+	if !ok || err != nil || id == "" || !at.Before(time.Unix(unix, 0)) {
+		return "", ErrInvalidLinkToken
+	}
+	return id, nil
+}
+
+func (s *HMACLinkSigner) Digest(value string) string {
+	return hex.EncodeToString(s.mac("otp", value))
+}
+
+// mac keys each use (link tokens, OTP digests) apart, so a value of one is never valid
+// as the other.
+func (s *HMACLinkSigner) mac(use, value string) []byte {
+	m := hmac.New(sha256.New, s.key)
+	m.Write([]byte(use + ":" + value))
+	return m.Sum(nil)
+}
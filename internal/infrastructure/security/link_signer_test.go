@@ -0,0 +1,32 @@
+package security
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHMACLinkSigner(t *testing.T) {
+	signer := NewHMACLinkSigner([]byte("0123456789abcdef0123456789abcdef"))
+	now := time.Date(2026, 5, 5, 12, 0, 0, 0, time.UTC)
+	token := signer.Sign("link-1", now.Add(time.Hour))
+
+	if id, err := signer.Verify(token, now); err != nil || id != "link-1" {
+		t.Fatalf("expected link-1, got %q err=%v", id, err)
+	}
+	if _, err := signer.Verify(token, now.Add(time.Hour)); err == nil {
+		t.Fatalf("expected expired token")
+	}
+
+	payload, sig, _ := strings.Cut(token, ".")
+	forged := NewHMACLinkSigner([]byte("another key of at least 32 bytes")).Sign("link-1", now.Add(24*time.Hour))
+	for _, bad := range []string{"", payload, payload + ".x" + sig, forged, strings.Replace(token, payload, payload+"A", 1)} {
+		if _, err := signer.Verify(bad, now); err == nil {
+			t.Fatalf("expected invalid token for %q", bad)
+		}
+	}
+
+	if signer.Digest("link-1:123456") != signer.Digest("link-1:123456") || signer.Digest("link-1:123456") == signer.Digest("link-1:123457") {
+		t.Fatalf("unexpected digests")
+	}
+}
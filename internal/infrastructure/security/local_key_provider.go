@@ -0,0 +1,123 @@
+package security
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const dataKeySize = 32
+
+var (
+	ErrInvalidMasterKey = errors.New("invalid master key: expected 32 bytes encoded in base64")
+	ErrUnknownKeyID     = errors.New("unknown encryption key id")
+)
+
+// LocalKeyProvider wraps data keys with AES-256 master keys read from local files.
+//
+// Intended for development: each file holds a base64-encoded 32-byte key
+// (e.g. `openssl rand -base64 32 > .keys/pii.key`). The first key is used to wrap
+// new data keys; the others are kept only to unwrap keys issued before a rotation.
+type LocalKeyProvider struct {
+	currentID string
+	keys      map[string][]byte
+}
+
+func NewLocalKeyProvider(currentPath string, previousPaths ...string) (*LocalKeyProvider, error) {
+	p := &LocalKeyProvider{keys: map[string][]byte{}}
+	for i, path := range append([]string{currentPath}, previousPaths...) {
+		key, err := readMasterKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		id := localKeyID(key)
+		p.keys[id] = key
+		if i == 0 {
+			p.currentID = id
+		}
+	}
+	return p, nil
+}
+
+func (p *LocalKeyProvider) CurrentKeyID() string {
+	return p.currentID
+}
+
+func (p *LocalKeyProvider) GenerateDataKey(_ context.Context) ([]byte, []byte, string, error) {
+	plaintext := make([]byte, dataKeySize)
+	if _, err := rand.Read(plaintext); err != nil {
+		return nil, nil, "", err
+	}
+	wrapped, err := sealAESGCM(p.keys[p.currentID], plaintext, []byte(p.currentID))
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return plaintext, wrapped, p.currentID, nil
+}
+
+func (p *LocalKeyProvider) DecryptDataKey(_ context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	master, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKeyID, keyID)
+	}
+	return openAESGCM(master, wrapped, []byte(keyID))
+}
+
+func readMasterKey(path string) ([]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(b)))
+	if err != nil || len(key) != dataKeySize {
+		return nil, ErrInvalidMasterKey
+	}
+	return key, nil
+}
+
+// localKeyID derives a stable, non-secret identifier from the master key.
+func localKeyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return "local:" + hex.EncodeToString(sum[:6])
+}
+
+// sealAESGCM encrypts with AES-256-GCM and returns nonce||ciphertext.
+func sealAESGCM(key, plaintext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func openAESGCM(key, sealed, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ct := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ct, aad)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
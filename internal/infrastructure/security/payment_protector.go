@@ -0,0 +1,196 @@
+package security
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+)
+
+var ErrInvalidEncryptedValue = errors.New("invalid encrypted value")
+
+// PaymentDataProtector applies field-level envelope encryption to the payer personal data
+// of a BillingPayment (see entities.SensitivePayloadPaths) and maintains the payer blind
+// indexes used by data subject requests.
+//
+// Each payment gets its own data key, wrapped by the key provider and stored with the
+// payment. Field ciphertexts are bound to the payment id (AAD), so values cannot be
+// copied between payments. Without a key provider only the blind indexes are filled.
+type PaymentDataProtector struct {
+	keys  interfaces.IKeyProvider
+	index interfaces.IBlindIndex
+}
+
+var _ interfaces.ISensitiveDataProtector = (*PaymentDataProtector)(nil)
+
+func NewPaymentDataProtector(keys interfaces.IKeyProvider, index interfaces.IBlindIndex) *PaymentDataProtector {
+	return &PaymentDataProtector{keys: keys, index: index}
+}
+
+func (s *PaymentDataProtector) NeedsProtection(p entities.BillingPayment) bool {
+	if p.AnonymizedAt != nil {
+		return false
+	}
+	if s.index != nil && missingBlindIndex(p.Details) {
+		return true
+	}
+	if s.keys == nil {
+		return false
+	}
+	if p.DataKey != nil && p.DataKey.KeyID != s.keys.CurrentKeyID() {
+		return true
+	}
+	return hasPlaintextSensitiveData(p)
+}
+
+func (s *PaymentDataProtector) Protect(ctx context.Context, p entities.BillingPayment) (entities.BillingPayment, error) {
+	if !s.NeedsProtection(p) {
+		return p, nil
+	}
+	if p.DataKey != nil {
+		revealed, err := s.Reveal(ctx, p)
+		if err != nil {
+			return entities.BillingPayment{}, err
+		}
+		p = revealed
+	}
+	if s.index != nil {
+		p.Details.PayerEmailHash = s.index.Hash(entities.NormalizePayerEmail(p.Details.PayerEmail))
+		p.Details.PayerDocHash = s.index.Hash(entities.NormalizePayerDocument(p.Details.PayerDocNumber))
+	}
+	if s.keys == nil {
+		return p, nil
+	}
+
+	dataKey, wrapped, keyID, err := s.keys.GenerateDataKey(ctx)
+	if err != nil {
+		return entities.BillingPayment{}, err
+	}
+	aad := []byte(p.ID)
+	encrypt := func(_ []string, v string) (string, error) {
+		if v == "" || entities.IsEncrypted(v) {
+			return v, nil
+		}
+		sealed, err := sealAESGCM(dataKey, []byte(v), aad)
+		if err != nil {
+			return "", err
+		}
+		return entities.EncryptedValuePrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+	}
+
+	out, err := transformPayment(p, encrypt)
+	if err != nil {
+		return entities.BillingPayment{}, err
+	}
+	out.DataKey = &entities.EncryptedDataKey{KeyID: keyID, WrappedKey: wrapped}
+	return out, nil
+}
+
+func (s *PaymentDataProtector) Reveal(ctx context.Context, p entities.BillingPayment) (entities.BillingPayment, error) {
+	if p.DataKey == nil {
+		return p, nil
+	}
+	if s.keys == nil {
+		return entities.BillingPayment{}, ErrUnknownKeyID
+	}
+	dataKey, err := s.keys.DecryptDataKey(ctx, p.DataKey.KeyID, p.DataKey.WrappedKey)
+	if err != nil {
+		return entities.BillingPayment{}, err
+	}
+	aad := []byte(p.ID)
+	decrypt := func(_ []string, v string) (string, error) {
+		if !entities.IsEncrypted(v) {
+			return v, nil
+		}
+		sealed, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(v, entities.EncryptedValuePrefix))
+		if err != nil {
+			return "", ErrInvalidEncryptedValue
+		}
+		plain, err := openAESGCM(dataKey, sealed, aad)
+		if err != nil {
+			return "", ErrInvalidEncryptedValue
+		}
+		return string(plain), nil
+	}
+
+	out, err := transformPayment(p, decrypt)
+	if err != nil {
+		return entities.BillingPayment{}, err
+	}
+	out.DataKey = nil
+	return out, nil
+}
+
+// transformPayment applies fn to every sensitive value of the payment and returns a copy.
+func transformPayment(p entities.BillingPayment, fn func(path []string, v string) (string, error)) (entities.BillingPayment, error) {
+	out := p
+
+	if p.MPPayload != nil {
+		var cp map[string]any
+		if err := roundTripJSON(p.MPPayload, &cp); err != nil {
+			return entities.BillingPayment{}, err
+		}
+		if err := entities.WalkSensitivePayload(cp, fn); err != nil {
+			return entities.BillingPayment{}, err
+		}
+		out.MPPayload = cp
+	}
+
+	if len(p.MPPayloadRaw) > 0 {
+		var raw map[string]any
+		if err := json.Unmarshal(p.MPPayloadRaw, &raw); err == nil {
+			if err := entities.WalkSensitivePayload(raw, fn); err != nil {
+				return entities.BillingPayment{}, err
+			}
+			b, err := json.Marshal(raw)
+			if err != nil {
+				return entities.BillingPayment{}, err
+			}
+			out.MPPayloadRaw = b
+		}
+	}
+
+	var err error
+	if out.Details.PayerEmail, err = applyIfSet(fn, []string{"details", "payer_email"}, p.Details.PayerEmail); err != nil {
+		return entities.BillingPayment{}, err
+	}
+	if out.Details.PayerDocNumber, err = applyIfSet(fn, []string{"details", "payer_doc_number"}, p.Details.PayerDocNumber); err != nil {
+		return entities.BillingPayment{}, err
+	}
+	return out, nil
+}
+
+func applyIfSet(fn func(path []string, v string) (string, error), path []string, v string) (string, error) {
+	if v == "" {
+		return v, nil
+	}
+	return fn(path, v)
+}
+
+func missingBlindIndex(d entities.PaymentDetails) bool {
+	return (d.PayerEmail != "" && d.PayerEmailHash == "") || (d.PayerDocNumber != "" && d.PayerDocHash == "")
+}
+
+func hasPlaintextSensitiveData(p entities.BillingPayment) bool {
+	found := false
+	check := func(_ []string, v string) (string, error) {
+		if !entities.IsEncrypted(v) {
+			found = true
+		}
+		return v, nil
+	}
+	_, _ = transformPayment(p, check)
+	return found
+}
+
+func roundTripJSON(in any, out any) error {
+	b, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
@@ -0,0 +1,222 @@
+package security
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mecanica_xpto/internal/domain/entities"
+)
+
+func writeKeyFile(t *testing.T, name string) string {
+	t.Helper()
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func samplePayment() entities.BillingPayment {
+	raw := json.RawMessage(`{"id":1,"transaction_amount":10,"payer":{"id":"778899","email":"ana@test.com","identification":{"type":"CPF","number":"12345678909"},"address":{"zip_code":"01310-100","street_name":"Av. Paulista","street_number":1578}},"additional_info":{"shipments":{"receiver_address":{"zip_code":"01310-100","street_name":"Av. Paulista","street_number":1578,"apartment":"52"}}},"card":{"last_four_digits":"1234","cardholder":{"name":"ANA"}}}`)
+	var parsed map[string]interface{}
+	_ = json.Unmarshal(raw, &parsed)
+	return entities.BillingPayment{
+		ID:           "pay-1",
+		MPPayloadRaw: raw,
+		MPPayload:    parsed,
+		Details:      entities.PaymentDetails{Amount: 10, PayerEmail: "ana@test.com", PayerDocNumber: "12345678909", CardLastFour: "1234"},
+	}
+}
+
+func TestLocalKeyProvider(t *testing.T) {
+	if _, err := NewLocalKeyProvider(filepath.Join(t.TempDir(), "missing.key")); err == nil {
+		t.Fatalf("expected error for missing key file")
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.key")
+	_ = os.WriteFile(bad, []byte("c2hvcnQ="), 0o600)
+	if _, err := NewLocalKeyProvider(bad); !errors.Is(err, ErrInvalidMasterKey) {
+		t.Fatalf("expected ErrInvalidMasterKey, got %v", err)
+	}
+
+	p, err := NewLocalKeyProvider(writeKeyFile(t, "k.key"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	plain, wrapped, keyID, err := p.GenerateDataKey(context.Background())
+	if err != nil || keyID != p.CurrentKeyID() {
+		t.Fatalf("unexpected result key_id=%s err=%v", keyID, err)
+	}
+	unwrapped, err := p.DecryptDataKey(context.Background(), keyID, wrapped)
+	if err != nil || string(unwrapped) != string(plain) {
+		t.Fatalf("data key round trip failed: %v", err)
+	}
+	if _, err := p.DecryptDataKey(context.Background(), "local:other", wrapped); !errors.Is(err, ErrUnknownKeyID) {
+		t.Fatalf("expected ErrUnknownKeyID, got %v", err)
+	}
+}
+
+func TestPaymentDataProtector_ProtectAndReveal(t *testing.T) {
+	keys, err := NewLocalKeyProvider(writeKeyFile(t, "k.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	protector := NewPaymentDataProtector(keys, NewHMACBlindIndex([]byte("k")))
+	ctx := context.Background()
+
+	original := samplePayment()
+	if !protector.NeedsProtection(original) {
+		t.Fatalf("plaintext payment should need protection")
+	}
+
+	protected, err := protector.Protect(ctx, original)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if protected.DataKey == nil || protected.DataKey.KeyID != keys.CurrentKeyID() {
+		t.Fatalf("expected data key, got %+v", protected.DataKey)
+	}
+	for _, pii := range []string{"ana@test.com", "12345678909", "778899", "Paulista", "01310-100", "1578"} {
+		if strings.Contains(string(protected.MPPayloadRaw), pii) {
+			t.Fatalf("raw payload still has plaintext PII %q: %s", pii, protected.MPPayloadRaw)
+		}
+	}
+	if !strings.Contains(string(protected.MPPayloadRaw), `"last_four_digits":"1234"`) {
+		t.Fatalf("non sensitive fields must be kept: %s", protected.MPPayloadRaw)
+	}
+	if !entities.IsEncrypted(protected.Details.PayerEmail) || protected.Details.Amount != 10 {
+		t.Fatalf("unexpected details: %+v", protected.Details)
+	}
+	payer := protected.MPPayload["payer"].(map[string]interface{})
+	if !entities.IsEncrypted(payer["email"].(string)) {
+		t.Fatalf("parsed payload still has plaintext PII: %+v", payer)
+	}
+	if original.MPPayload["payer"].(map[string]interface{})["email"] != "ana@test.com" {
+		t.Fatalf("input payment must not be modified")
+	}
+	if protector.NeedsProtection(protected) {
+		t.Fatalf("protected payment should not need protection")
+	}
+	if protected.Details.PayerEmailHash == "" || protected.Details.PayerDocHash == "" {
+		t.Fatalf("expected blind indexes, got %+v", protected.Details)
+	}
+
+	revealed, err := protector.Reveal(ctx, protected)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if revealed.Details.PayerEmail != "ana@test.com" || revealed.DataKey != nil {
+		t.Fatalf("unexpected revealed details: %+v", revealed.Details)
+	}
+	if !strings.Contains(string(revealed.MPPayloadRaw), "12345678909") || !strings.Contains(string(revealed.MPPayloadRaw), `"street_name":"Av. Paulista"`) {
+		t.Fatalf("unexpected revealed raw payload: %s", revealed.MPPayloadRaw)
+	}
+
+	t.Run("ciphertext is bound to the payment id", func(t *testing.T) {
+		moved := protected
+		moved.ID = "pay-2"
+		if _, err := protector.Reveal(ctx, moved); !errors.Is(err, ErrInvalidEncryptedValue) {
+			t.Fatalf("expected ErrInvalidEncryptedValue, got %v", err)
+		}
+	})
+
+	t.Run("re-encrypts after key rotation", func(t *testing.T) {
+		oldKeyPath := writeKeyFile(t, "old.key")
+		oldKeys, _ := NewLocalKeyProvider(oldKeyPath)
+		oldProtected, err := NewPaymentDataProtector(oldKeys, nil).Protect(ctx, samplePayment())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rotated, err := NewLocalKeyProvider(writeKeyFile(t, "new.key"), oldKeyPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		newProtector := NewPaymentDataProtector(rotated, nil)
+		if !newProtector.NeedsProtection(oldProtected) {
+			t.Fatalf("payment protected with previous key should need re-encryption")
+		}
+		reencrypted, err := newProtector.Protect(ctx, oldProtected)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if reencrypted.DataKey.KeyID != rotated.CurrentKeyID() {
+			t.Fatalf("expected current key id, got %s", reencrypted.DataKey.KeyID)
+		}
+		back, err := newProtector.Reveal(ctx, reencrypted)
+		if err != nil || back.Details.PayerDocNumber != "12345678909" {
+			t.Fatalf("unexpected reveal after rotation: %+v err=%v", back.Details, err)
+		}
+	})
+}
+
+func TestPaymentDataProtector_IndexOnly(t *testing.T) {
+	index := NewHMACBlindIndex([]byte("k"))
+	protector := NewPaymentDataProtector(nil, index)
+
+	p := samplePayment()
+	p.Details.PayerEmail = " Ana@Test.com "
+	p.Details.PayerDocNumber = "123.456.789-09"
+	if !protector.NeedsProtection(p) {
+		t.Fatalf("payment without blind indexes should need protection")
+	}
+
+	out, err := protector.Protect(context.Background(), p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.DataKey != nil || out.Details.PayerEmail != " Ana@Test.com " {
+		t.Fatalf("index-only mode must not encrypt: %+v", out)
+	}
+	if out.Details.PayerEmailHash != index.Hash("ana@test.com") || out.Details.PayerDocHash != index.Hash("12345678909") {
+		t.Fatalf("unexpected hashes: %+v", out.Details)
+	}
+	if protector.NeedsProtection(out) {
+		t.Fatalf("indexed payment should not need protection")
+	}
+	if index.Hash("") != "" {
+		t.Fatalf("empty values must not be indexed")
+	}
+}
+
+func TestRecordSealer(t *testing.T) {
+	ctx := context.Background()
+	keys, err := NewLocalKeyProvider(writeKeyFile(t, "k.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewRecordSealer(keys)
+
+	name, email, empty := "João Silva", "joao@test.com", ""
+	key, err := s.Seal(ctx, "doc-1", &name, &email, &empty)
+	if err != nil || key == nil {
+		t.Fatalf("unexpected seal result key=%v err=%v", key, err)
+	}
+	if !entities.IsEncrypted(name) || !entities.IsEncrypted(email) || empty != "" {
+		t.Fatalf("values not sealed: %q %q %q", name, email, empty)
+	}
+
+	moved := name
+	if err := s.Open(ctx, "doc-2", key, &moved); !errors.Is(err, ErrInvalidEncryptedValue) {
+		t.Fatalf("expected ciphertext bound to the record, got %v", err)
+	}
+	if err := s.Open(ctx, "doc-1", key, &name, &email); err != nil || name != "João Silva" || email != "joao@test.com" {
+		t.Fatalf("unexpected open result %q %q err=%v", name, email, err)
+	}
+
+	plain := NewRecordSealer(nil)
+	if key, err := plain.Seal(ctx, "doc-1", &email); key != nil || err != nil || email != "joao@test.com" {
+		t.Fatalf("expected plaintext without key provider, got %q key=%v err=%v", email, key, err)
+	}
+}
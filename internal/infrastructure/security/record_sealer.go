@@ -0,0 +1,72 @@
+package security
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+)
+
+// RecordSealer applies the envelope encryption of PaymentDataProtector to arbitrary
+// fields of a record: one data key per record, ciphertexts bound to the record id.
+// Without a key provider values are kept in plaintext.
+type RecordSealer struct {
+	keys interfaces.IKeyProvider
+}
+
+var _ interfaces.IRecordSealer = (*RecordSealer)(nil)
+
+func NewRecordSealer(keys interfaces.IKeyProvider) *RecordSealer {
+	return &RecordSealer{keys: keys}
+}
+
+func (s *RecordSealer) Seal(ctx context.Context, recordID string, values ...*string) (*entities.EncryptedDataKey, error) {
+	if s.keys == nil {
+		return nil, nil
+	}
+	dataKey, wrapped, keyID, err := s.keys.GenerateDataKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range values {
+		if v == nil || *v == "" || entities.IsEncrypted(*v) {
+			continue
+		}
+		sealed, err := sealAESGCM(dataKey, []byte(*v), []byte(recordID))
+		if err != nil {
+			return nil, err
+		}
+		*v = entities.EncryptedValuePrefix + base64.RawStdEncoding.EncodeToString(sealed)
+	}
+	return &entities.EncryptedDataKey{KeyID: keyID, WrappedKey: wrapped}, nil
+}
+
+func (s *RecordSealer) Open(ctx context.Context, recordID string, key *entities.EncryptedDataKey, values ...*string) error {
+	if key == nil {
+		return nil
+	}
+	if s.keys == nil {
+		return ErrUnknownKeyID
+	}
+	dataKey, err := s.keys.DecryptDataKey(ctx, key.KeyID, key.WrappedKey)
+	if err != nil {
+		return err
+	}
+	for _, v := range values {
+		if v == nil || !entities.IsEncrypted(*v) {
+			continue
+		}
+		sealed, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(*v, entities.EncryptedValuePrefix))
+		if err != nil {
+			return ErrInvalidEncryptedValue
+		}
+		plain, err := openAESGCM(dataKey, sealed, []byte(recordID))
+		if err != nil {
+			return ErrInvalidEncryptedValue
+		}
+		*v = string(plain)
+	}
+	return nil
+}
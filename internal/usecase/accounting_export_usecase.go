@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidAccountingFormat   = errors.New("invalid accounting export format")
+	ErrInvalidAccountingExportID = errors.New("invalid accounting export id")
+	ErrAccountingExportNotFound  = errors.New("accounting export not found")
+	ErrAccountingExportConflict  = errors.New("accounting export already in progress")
+	ErrAccountingExportUpToDate  = errors.New("accounting export up to date")
+	ErrAccountingExportBlocked   = errors.New("accounting export blocked by a payment without amount")
+)
+
+// accountingSettleDelay keeps the most recent events out of an export: a status event
+// written with an earlier timestamp after the export ran would otherwise be skipped.
+const accountingSettleDelay = time.Minute
+
+// IAccountingExportUseCase produces incremental accounting exports.
+type IAccountingExportUseCase interface {
+	Export(ctx context.Context, format entities.AccountingExportFormat, actor string) (entities.AccountingExport, error)
+	GetExport(ctx context.Context, id string) (entities.AccountingExport, error)
+	RenderExport(ctx context.Context, id string) (entities.AccountingExport, AccountingFile, error)
+}
+
+// AccountingFile is a rendered accounting export.
+type AccountingFile struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+type AccountingExportUseCase struct {
+	events     interfaces.IPaymentStatusEventRepository
+	payments   interfaces.IBillingPaymentRepository
+	exports    interfaces.IAccountingExportRepository
+	chart      entities.ChartOfAccounts
+	formatters map[entities.AccountingExportFormat]interfaces.IJournalFormatter
+	now        func() time.Time
+}
+
+var _ IAccountingExportUseCase = (*AccountingExportUseCase)(nil)
+
+func NewAccountingExportUseCase(
+	events interfaces.IPaymentStatusEventRepository,
+	payments interfaces.IBillingPaymentRepository,
+	exports interfaces.IAccountingExportRepository,
+	chart entities.ChartOfAccounts,
+	formatters map[entities.AccountingExportFormat]interfaces.IJournalFormatter,
+) *AccountingExportUseCase {
+	return &AccountingExportUseCase{
+		events:     events,
+		payments:   payments,
+		exports:    exports,
+		chart:      chart,
+		formatters: formatters,
+		now:        time.Now,
+	}
+}
+
+// Export creates a run covering the status events since the watermark, stores its journal
+// entries and moves the watermark forward. Concurrent exports are rejected with
+// ErrAccountingExportConflict.
+//
+// A payment without amount stops the run just before its event, so the event is exported
+// by a later run once the amount is backfilled; when that event is the first one after the
+// watermark, ErrAccountingExportBlocked is returned and nothing is stored.
+func (u *AccountingExportUseCase) Export(ctx context.Context, format entities.AccountingExportFormat, actor string) (entities.AccountingExport, error) {
+	format = entities.AccountingExportFormat(strings.ToLower(strings.TrimSpace(string(format))))
+	if _, ok := u.formatters[format]; !ok {
+		return entities.AccountingExport{}, ErrInvalidAccountingFormat
+	}
+
+	watermark, err := u.exports.GetWatermark(ctx)
+	if err != nil {
+		return entities.AccountingExport{}, err
+	}
+	now := u.now().UTC()
+	cutoff := now.Add(-accountingSettleDelay)
+	if !cutoff.After(watermark) {
+		return entities.AccountingExport{}, ErrAccountingExportUpToDate
+	}
+
+	run := entities.AccountingExport{
+		ID:        uuid.NewString(),
+		Format:    format,
+		From:      watermark,
+		To:        cutoff,
+		Actor:     actor,
+		CreatedAt: now,
+	}
+	entries, blocker, err := u.journal(ctx, run)
+	if err != nil {
+		return entities.AccountingExport{}, err
+	}
+	if blocker.ID != "" {
+		run.To = blocker.CreatedAt.Add(-time.Nanosecond)
+		run.BlockedByPaymentID = blocker.PaymentID
+		if !run.To.After(run.From) {
+			return entities.AccountingExport{}, fmt.Errorf("%w: payment %s", ErrAccountingExportBlocked, blocker.PaymentID)
+		}
+	}
+	run.EntryCount = len(entries)
+
+	if err := u.exports.Commit(ctx, run, entries, watermark); err != nil {
+		if errors.Is(err, entities.ErrAccountingWatermarkMoved) {
+			return entities.AccountingExport{}, ErrAccountingExportConflict
+		}
+		log.Printf("[payment][accounting] export commit failed export_id=%s err=%v", run.ID, err)
+		return entities.AccountingExport{}, err
+	}
+	log.Printf("[payment][accounting] export created export_id=%s format=%s entries=%d to=%s blocked_by=%s actor=%s",
+		run.ID, run.Format, run.EntryCount, run.To.Format(time.RFC3339Nano), run.BlockedByPaymentID, actor)
+	return run, nil
+}
+
+func (u *AccountingExportUseCase) GetExport(ctx context.Context, id string) (entities.AccountingExport, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return entities.AccountingExport{}, ErrInvalidAccountingExportID
+	}
+	run, err := u.exports.GetByID(ctx, id)
+	if err != nil {
+		return entities.AccountingExport{}, err
+	}
+	if run.ID == "" {
+		return entities.AccountingExport{}, ErrAccountingExportNotFound
+	}
+	return run, nil
+}
+
+// RenderExport writes the file of a run from the journal entries stored with it, so a
+// delivered export never changes, whatever happens later to payments or to the chart of
+// accounts.
+func (u *AccountingExportUseCase) RenderExport(ctx context.Context, id string) (entities.AccountingExport, AccountingFile, error) {
+	run, err := u.GetExport(ctx, id)
+	if err != nil {
+		return entities.AccountingExport{}, AccountingFile{}, err
+	}
+	formatter, ok := u.formatters[run.Format]
+	if !ok {
+		return entities.AccountingExport{}, AccountingFile{}, ErrInvalidAccountingFormat
+	}
+	entries, err := u.exports.ListEntries(ctx, run.ID)
+	if err != nil {
+		return entities.AccountingExport{}, AccountingFile{}, err
+	}
+
+	var buf bytes.Buffer
+	if err := formatter.Write(&buf, run, entries); err != nil {
+		return entities.AccountingExport{}, AccountingFile{}, err
+	}
+	return run, AccountingFile{
+		Name:        fmt.Sprintf("contabil-%s.%s", run.ID, formatter.FileExtension()),
+		ContentType: formatter.ContentType(),
+		Content:     buf.Bytes(),
+	}, nil
+}
+
+// journal builds the entries of the status transitions created in (run.From, run.To], in
+// event order: approvals yield a payment entry and, when the provider charged fees, a fee
+// entry; refunds yield a refund entry. Repeated reports of the same status are not
+// transitions and are ignored.
+//
+// The first transition whose payment has no amount (details not backfilled) is returned
+// as the blocker; only the entries of events created before it are returned.
+func (u *AccountingExportUseCase) journal(ctx context.Context, run entities.AccountingExport) ([]entities.JournalEntry, entities.PaymentStatusEvent, error) {
+	from := run.From.Add(time.Nanosecond)
+	var events []entities.PaymentStatusEvent
+	for _, status := range []entities.PaymentStatus{entities.PaymentStatusAprovado, entities.PaymentStatusReembolsado} {
+		page, err := u.events.ListByStatusBetween(ctx, status, from, run.To)
+		if err != nil {
+			log.Printf("[payment][accounting] list events failed status=%s err=%v", status, err)
+			return nil, entities.PaymentStatusEvent{}, err
+		}
+		events = append(events, page...)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].CreatedAt.Equal(events[j].CreatedAt) {
+			return events[i].CreatedAt.Before(events[j].CreatedAt)
+		}
+		return events[i].ID < events[j].ID
+	})
+
+	var entries []entities.JournalEntry
+	payments := map[string]entities.BillingPayment{}
+	for _, e := range events {
+		if e.PreviousStatus == e.Status {
+			continue
+		}
+		p, ok := payments[e.PaymentID]
+		if !ok {
+			var err error
+			if p, err = u.payments.GetByID(ctx, e.PaymentID); err != nil {
+				return nil, entities.PaymentStatusEvent{}, err
+			}
+			payments[e.PaymentID] = p
+		}
+		if p.ID == "" || p.Details.Amount <= 0 {
+			log.Printf("[payment][accounting] export stopped event_id=%s payment_id=%s: payment or amount missing", e.ID, e.PaymentID)
+			// Entries of events sharing the blocker's timestamp would be exported again.
+			kept := entries[:0]
+			for _, entry := range entries {
+				if entry.Date.Before(e.CreatedAt) {
+					kept = append(kept, entry)
+				}
+			}
+			return kept, e, nil
+		}
+		entries = append(entries, u.entriesFor(e, p)...)
+	}
+	return entries, entities.PaymentStatusEvent{}, nil
+}
+
+func (u *AccountingExportUseCase) entriesFor(e entities.PaymentStatusEvent, p entities.BillingPayment) []entities.JournalEntry {
+	bank := u.chart.BankFor(p.Details.PaymentMethodID)
+	entry := func(kind entities.JournalEntryKind, debit, credit string, amount float64, description string) entities.JournalEntry {
+		return entities.JournalEntry{
+			ID:              e.ID + "-" + string(kind),
+			Kind:            kind,
+			Date:            e.CreatedAt,
+			DebitAccount:    debit,
+			CreditAccount:   credit,
+			Amount:          amount,
+			PaymentID:       p.ID,
+			EstimateID:      p.EstimateID,
+			PaymentMethodID: p.Details.PaymentMethodID,
+			Description:     description,
+		}
+	}
+
+	if e.Status == entities.PaymentStatusReembolsado {
+		return []entities.JournalEntry{
+			entry(entities.JournalEntryRefund, u.chart.Refunds, bank, p.Details.Amount, "Reembolso pagamento "+p.ID),
+		}
+	}
+	out := []entities.JournalEntry{
+		entry(entities.JournalEntryPayment, bank, u.chart.Revenue, p.Details.Amount, "Recebimento orçamento "+p.EstimateID),
+	}
+	if fees := p.Details.TotalFees(); fees > 0 {
+		out = append(out, entry(entities.JournalEntryFee, u.chart.Fees, bank, fees, "Tarifa pagamento "+p.ID))
+	}
+	return out
+}
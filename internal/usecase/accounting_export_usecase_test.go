@@ -0,0 +1,268 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+	mock_interfaces "mecanica_xpto/internal/usecase/interfaces/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+var accountingTestNow = time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+func TestAccountingExportUseCase_Export(t *testing.T) {
+	watermark := time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC)
+	cutoff := accountingTestNow.Add(-accountingSettleDelay)
+	at := func(h int) time.Time { return watermark.Add(time.Duration(h) * time.Hour) }
+
+	t.Run("invalid format", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		events := mock_interfaces.NewMockIPaymentStatusEventRepository(ctrl)
+		payRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		exports := mock_interfaces.NewMockIAccountingExportRepository(ctrl)
+		formatter := mock_interfaces.NewMockIJournalFormatter(ctrl)
+		chart := entities.DefaultChartOfAccounts
+		chart.BankByPaymentMethod = map[string]string{"bolbradesco": "1.1.2.01"}
+		uc := NewAccountingExportUseCase(events, payRepo, exports, chart,
+			map[entities.AccountingExportFormat]interfaces.IJournalFormatter{entities.AccountingExportCSV: formatter})
+		uc.now = func() time.Time { return accountingTestNow }
+		if _, err := uc.Export(context.Background(), "xlsx", "ops"); !errors.Is(err, ErrInvalidAccountingFormat) {
+			t.Fatalf("expected ErrInvalidAccountingFormat, got %v", err)
+		}
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		events := mock_interfaces.NewMockIPaymentStatusEventRepository(ctrl)
+		payRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		exports := mock_interfaces.NewMockIAccountingExportRepository(ctrl)
+		formatter := mock_interfaces.NewMockIJournalFormatter(ctrl)
+		chart := entities.DefaultChartOfAccounts
+		chart.BankByPaymentMethod = map[string]string{"bolbradesco": "1.1.2.01"}
+		uc := NewAccountingExportUseCase(events, payRepo, exports, chart,
+			map[entities.AccountingExportFormat]interfaces.IJournalFormatter{entities.AccountingExportCSV: formatter})
+		uc.now = func() time.Time { return accountingTestNow }
+		exports.EXPECT().GetWatermark(gomock.Any()).Return(cutoff, nil)
+		if _, err := uc.Export(context.Background(), "csv", "ops"); !errors.Is(err, ErrAccountingExportUpToDate) {
+			t.Fatalf("expected ErrAccountingExportUpToDate, got %v", err)
+		}
+	})
+
+	t.Run("exports transitions since the watermark", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		events := mock_interfaces.NewMockIPaymentStatusEventRepository(ctrl)
+		payRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		exports := mock_interfaces.NewMockIAccountingExportRepository(ctrl)
+		formatter := mock_interfaces.NewMockIJournalFormatter(ctrl)
+		chart := entities.DefaultChartOfAccounts
+		chart.BankByPaymentMethod = map[string]string{"bolbradesco": "1.1.2.01"}
+		uc := NewAccountingExportUseCase(events, payRepo, exports, chart,
+			map[entities.AccountingExportFormat]interfaces.IJournalFormatter{entities.AccountingExportCSV: formatter})
+		uc.now = func() time.Time { return accountingTestNow }
+		exports.EXPECT().GetWatermark(gomock.Any()).Return(watermark, nil)
+
+		events.EXPECT().ListByStatusBetween(gomock.Any(), entities.PaymentStatusAprovado, watermark.Add(time.Nanosecond), cutoff).
+			Return([]entities.PaymentStatusEvent{
+				{ID: "ev1", PaymentID: "p1", Status: entities.PaymentStatusAprovado, PreviousStatus: entities.PaymentStatusPendente, CreatedAt: at(1)},
+				// repeated webhook: not a transition
+				{ID: "ev2", PaymentID: "p1", Status: entities.PaymentStatusAprovado, PreviousStatus: entities.PaymentStatusAprovado, CreatedAt: at(2)},
+			}, nil)
+		events.EXPECT().ListByStatusBetween(gomock.Any(), entities.PaymentStatusReembolsado, gomock.Any(), cutoff).
+			Return([]entities.PaymentStatusEvent{
+				{ID: "ev4", PaymentID: "p3", Status: entities.PaymentStatusReembolsado, PreviousStatus: entities.PaymentStatusAprovado, CreatedAt: at(0)},
+			}, nil)
+
+		payRepo.EXPECT().GetByID(gomock.Any(), "p1").Return(entities.BillingPayment{
+			ID: "p1", EstimateID: "e1",
+			Details: entities.PaymentDetails{Amount: 200, PaymentMethodID: "pix", Fees: []entities.PaymentFee{{Type: "mercadopago_fee", Amount: 1.98}}},
+		}, nil)
+		payRepo.EXPECT().GetByID(gomock.Any(), "p3").Return(entities.BillingPayment{
+			ID: "p3", EstimateID: "e3", Details: entities.PaymentDetails{Amount: 90, PaymentMethodID: "bolbradesco"},
+		}, nil)
+
+		exports.EXPECT().Commit(gomock.Any(), gomock.Any(), gomock.Any(), watermark).DoAndReturn(
+			func(_ context.Context, run entities.AccountingExport, entries []entities.JournalEntry, _ time.Time) error {
+				if !run.From.Equal(watermark) || !run.To.Equal(cutoff) || run.Actor != "ops" || run.ID == "" || run.BlockedByPaymentID != "" {
+					t.Fatalf("unexpected run: %+v", run)
+				}
+				if len(entries) != 3 || entries[0].ID != "ev4-refund" || entries[1].ID != "ev1-payment" || entries[2].ID != "ev1-fee" {
+					t.Fatalf("unexpected entries: %+v", entries)
+				}
+				return nil
+			})
+
+		run, err := uc.Export(context.Background(), " CSV ", "ops")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if run.Format != entities.AccountingExportCSV || run.EntryCount != 3 {
+			t.Fatalf("unexpected run: %+v", run)
+		}
+	})
+
+	t.Run("payment without amount stops the watermark before its event", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		events := mock_interfaces.NewMockIPaymentStatusEventRepository(ctrl)
+		payRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		exports := mock_interfaces.NewMockIAccountingExportRepository(ctrl)
+		formatter := mock_interfaces.NewMockIJournalFormatter(ctrl)
+		chart := entities.DefaultChartOfAccounts
+		chart.BankByPaymentMethod = map[string]string{"bolbradesco": "1.1.2.01"}
+		uc := NewAccountingExportUseCase(events, payRepo, exports, chart,
+			map[entities.AccountingExportFormat]interfaces.IJournalFormatter{entities.AccountingExportCSV: formatter})
+		uc.now = func() time.Time { return accountingTestNow }
+		exports.EXPECT().GetWatermark(gomock.Any()).Return(watermark, nil)
+		events.EXPECT().ListByStatusBetween(gomock.Any(), entities.PaymentStatusAprovado, gomock.Any(), cutoff).
+			Return([]entities.PaymentStatusEvent{
+				{ID: "ev1", PaymentID: "p1", Status: entities.PaymentStatusAprovado, PreviousStatus: entities.PaymentStatusPendente, CreatedAt: at(1)},
+				// same timestamp as the blocker: exported by the next run with it
+				{ID: "ev2", PaymentID: "p1", Status: entities.PaymentStatusAprovado, PreviousStatus: entities.PaymentStatusPendente, CreatedAt: at(3)},
+				// legacy payment without details
+				{ID: "ev3", PaymentID: "p2", Status: entities.PaymentStatusAprovado, PreviousStatus: entities.PaymentStatusPendente, CreatedAt: at(3)},
+				{ID: "ev5", PaymentID: "p4", Status: entities.PaymentStatusAprovado, PreviousStatus: entities.PaymentStatusPendente, CreatedAt: at(4)},
+			}, nil)
+		events.EXPECT().ListByStatusBetween(gomock.Any(), entities.PaymentStatusReembolsado, gomock.Any(), cutoff).Return(nil, nil)
+		payRepo.EXPECT().GetByID(gomock.Any(), "p1").Return(entities.BillingPayment{ID: "p1", EstimateID: "e1", Details: entities.PaymentDetails{Amount: 200}}, nil)
+		payRepo.EXPECT().GetByID(gomock.Any(), "p2").Return(entities.BillingPayment{ID: "p2", EstimateID: "e2"}, nil)
+
+		exports.EXPECT().Commit(gomock.Any(), gomock.Any(), gomock.Any(), watermark).DoAndReturn(
+			func(_ context.Context, run entities.AccountingExport, entries []entities.JournalEntry, _ time.Time) error {
+				if !run.To.Equal(at(3).Add(-time.Nanosecond)) || run.BlockedByPaymentID != "p2" {
+					t.Fatalf("unexpected run: %+v", run)
+				}
+				if len(entries) != 1 || entries[0].ID != "ev1-payment" {
+					t.Fatalf("unexpected entries: %+v", entries)
+				}
+				return nil
+			})
+
+		run, err := uc.Export(context.Background(), "csv", "ops")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if run.EntryCount != 1 || run.BlockedByPaymentID != "p2" {
+			t.Fatalf("unexpected run: %+v", run)
+		}
+	})
+
+	t.Run("blocked right after the watermark", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		events := mock_interfaces.NewMockIPaymentStatusEventRepository(ctrl)
+		payRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		exports := mock_interfaces.NewMockIAccountingExportRepository(ctrl)
+		formatter := mock_interfaces.NewMockIJournalFormatter(ctrl)
+		chart := entities.DefaultChartOfAccounts
+		chart.BankByPaymentMethod = map[string]string{"bolbradesco": "1.1.2.01"}
+		uc := NewAccountingExportUseCase(events, payRepo, exports, chart,
+			map[entities.AccountingExportFormat]interfaces.IJournalFormatter{entities.AccountingExportCSV: formatter})
+		uc.now = func() time.Time { return accountingTestNow }
+		exports.EXPECT().GetWatermark(gomock.Any()).Return(watermark, nil)
+		events.EXPECT().ListByStatusBetween(gomock.Any(), entities.PaymentStatusAprovado, gomock.Any(), cutoff).
+			Return([]entities.PaymentStatusEvent{
+				{ID: "ev3", PaymentID: "p2", Status: entities.PaymentStatusAprovado, PreviousStatus: entities.PaymentStatusPendente, CreatedAt: watermark.Add(time.Nanosecond)},
+			}, nil)
+		events.EXPECT().ListByStatusBetween(gomock.Any(), entities.PaymentStatusReembolsado, gomock.Any(), cutoff).Return(nil, nil)
+		payRepo.EXPECT().GetByID(gomock.Any(), "p2").Return(entities.BillingPayment{}, nil)
+
+		if _, err := uc.Export(context.Background(), "csv", "ops"); !errors.Is(err, ErrAccountingExportBlocked) {
+			t.Fatalf("expected ErrAccountingExportBlocked, got %v", err)
+		}
+	})
+
+	t.Run("concurrent export", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		events := mock_interfaces.NewMockIPaymentStatusEventRepository(ctrl)
+		payRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		exports := mock_interfaces.NewMockIAccountingExportRepository(ctrl)
+		formatter := mock_interfaces.NewMockIJournalFormatter(ctrl)
+		chart := entities.DefaultChartOfAccounts
+		chart.BankByPaymentMethod = map[string]string{"bolbradesco": "1.1.2.01"}
+		uc := NewAccountingExportUseCase(events, payRepo, exports, chart,
+			map[entities.AccountingExportFormat]interfaces.IJournalFormatter{entities.AccountingExportCSV: formatter})
+		uc.now = func() time.Time { return accountingTestNow }
+		exports.EXPECT().GetWatermark(gomock.Any()).Return(time.Time{}, nil)
+		events.EXPECT().ListByStatusBetween(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+		exports.EXPECT().Commit(gomock.Any(), gomock.Any(), gomock.Any(), time.Time{}).Return(entities.ErrAccountingWatermarkMoved)
+
+		if _, err := uc.Export(context.Background(), "csv", "ops"); !errors.Is(err, ErrAccountingExportConflict) {
+			t.Fatalf("expected ErrAccountingExportConflict, got %v", err)
+		}
+	})
+}
+
+func TestAccountingExportUseCase_RenderExport(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		events := mock_interfaces.NewMockIPaymentStatusEventRepository(ctrl)
+		payRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		exports := mock_interfaces.NewMockIAccountingExportRepository(ctrl)
+		formatter := mock_interfaces.NewMockIJournalFormatter(ctrl)
+		chart := entities.DefaultChartOfAccounts
+		chart.BankByPaymentMethod = map[string]string{"bolbradesco": "1.1.2.01"}
+		uc := NewAccountingExportUseCase(events, payRepo, exports, chart,
+			map[entities.AccountingExportFormat]interfaces.IJournalFormatter{entities.AccountingExportCSV: formatter})
+		uc.now = func() time.Time { return accountingTestNow }
+		exports.EXPECT().GetByID(gomock.Any(), "missing").Return(entities.AccountingExport{}, nil)
+		if _, _, err := uc.RenderExport(context.Background(), "missing"); !errors.Is(err, ErrAccountingExportNotFound) {
+			t.Fatalf("expected ErrAccountingExportNotFound, got %v", err)
+		}
+		if _, err := uc.GetExport(context.Background(), " "); !errors.Is(err, ErrInvalidAccountingExportID) {
+			t.Fatalf("expected ErrInvalidAccountingExportID, got %v", err)
+		}
+	})
+
+	t.Run("renders the stored entries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		events := mock_interfaces.NewMockIPaymentStatusEventRepository(ctrl)
+		payRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		exports := mock_interfaces.NewMockIAccountingExportRepository(ctrl)
+		formatter := mock_interfaces.NewMockIJournalFormatter(ctrl)
+		chart := entities.DefaultChartOfAccounts
+		chart.BankByPaymentMethod = map[string]string{"bolbradesco": "1.1.2.01"}
+		uc := NewAccountingExportUseCase(events, payRepo, exports, chart,
+			map[entities.AccountingExportFormat]interfaces.IJournalFormatter{entities.AccountingExportCSV: formatter})
+		uc.now = func() time.Time { return accountingTestNow }
+		run := entities.AccountingExport{ID: "run-1", Format: entities.AccountingExportCSV, From: accountingTestNow.Add(-48 * time.Hour), To: accountingTestNow.Add(-24 * time.Hour)}
+		want := entities.JournalEntry{
+			ID: "ev9-refund", Kind: entities.JournalEntryRefund, Date: run.To,
+			DebitAccount: "3.2.1.01", CreditAccount: "1.1.2.01", Amount: 50,
+			PaymentID: "p9", EstimateID: "e9", PaymentMethodID: "bolbradesco", Description: "Reembolso pagamento p9",
+		}
+		exports.EXPECT().GetByID(gomock.Any(), "run-1").Return(run, nil)
+		// Payments and the chart of accounts are not read again.
+		exports.EXPECT().ListEntries(gomock.Any(), "run-1").Return([]entities.JournalEntry{want}, nil)
+		formatter.EXPECT().Write(gomock.Any(), run, gomock.Any()).DoAndReturn(
+			func(w io.Writer, _ entities.AccountingExport, entries []entities.JournalEntry) error {
+				if len(entries) != 1 || entries[0] != want {
+					t.Fatalf("unexpected entries: %+v", entries)
+				}
+				_, err := w.Write([]byte("ok"))
+				return err
+			})
+		formatter.EXPECT().FileExtension().Return("csv")
+		formatter.EXPECT().ContentType().Return("text/csv")
+
+		_, file, err := uc.RenderExport(context.Background(), "run-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if file.Name != "contabil-run-1.csv" || file.ContentType != "text/csv" || !bytes.Equal(file.Content, []byte("ok")) {
+			t.Fatalf("unexpected file: %+v", file)
+		}
+	})
+}
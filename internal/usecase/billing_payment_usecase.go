@@ -12,19 +12,47 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 var (
 	ErrBillingPaymentNotFound         = errors.New("billing payment not found")
 	ErrInvalidPaymentEstimateID       = errors.New("invalid estimate_id")
+	ErrInvalidPaymentID               = errors.New("invalid payment id")
 	ErrInvalidMPPayload               = errors.New("invalid mercado pago payload")
 	ErrEstimateNotApproved            = errors.New("estimate not approved")
 	ErrPaymentGatewayBadRequest       = errors.New("payment gateway bad request")
 	ErrPaymentGatewayUnauthorized     = errors.New("payment gateway unauthorized")
 	ErrPaymentGatewayInvalidUsers     = errors.New("payment gateway invalid users involved")
 	ErrPaymentGatewayCustomerNotFound = errors.New("payment gateway customer not found")
+	ErrPaymentPayloadSchemaViolation  = errors.New("payment payload schema violation")
+	ErrInvalidPaymentStatus           = errors.New("invalid payment status")
+	ErrInvalidPaymentEventSource      = errors.New("invalid payment event source")
+	ErrInvalidPaymentDateRange        = errors.New("invalid payment date range")
+	ErrPaymentStatusConflict          = errors.New("payment status changed concurrently")
+	ErrPaymentTransitionNotAllowed    = errors.New("payment status transition not allowed")
+)
+
+const (
+	defaultPaymentPageLimit int32 = 20
+	maxPaymentPageLimit     int32 = 100
 )
 
+// PaymentPayloadSchemaError reports the fields of an outgoing provider payload that
+// violate the provider schema. It unwraps to ErrPaymentPayloadSchemaViolation.
+type PaymentPayloadSchemaError struct {
+	Pointers []string
+}
+
+func (e *PaymentPayloadSchemaError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrPaymentPayloadSchemaViolation, strings.Join(e.Pointers, ", "))
+}
+
+func (e *PaymentPayloadSchemaError) Unwrap() error {
+	return ErrPaymentPayloadSchemaViolation
+}
+
 // IBillingPaymentUseCase encapsulates the "create and process payment" behavior.
 //
 // Requested behavior:
@@ -33,13 +61,43 @@ var (
 type IBillingPaymentUseCase interface {
 	CreateAndApprove(ctx context.Context, estimateID string, mpPayload json.RawMessage) (entities.BillingPayment, error)
 	GetByID(ctx context.Context, id string) (entities.BillingPayment, error)
-	ListByEstimateID(ctx context.Context, estimateID string) ([]entities.BillingPayment, error)
+	ListByEstimateID(ctx context.Context, estimateID string, page entities.PageRequest) (entities.Page[entities.BillingPayment], error)
+	LatestByEstimateID(ctx context.Context, estimateID string) (entities.BillingPayment, error)
+	ListByStatus(ctx context.Context, filter PaymentListFilter, page entities.PageRequest) (entities.Page[entities.BillingPayment], error)
+	Reveal(ctx context.Context, id string) (entities.BillingPayment, error)
+	ChangeStatus(ctx context.Context, id string, change PaymentStatusChange) (entities.BillingPayment, error)
+	History(ctx context.Context, id string) ([]entities.PaymentStatusEvent, error)
+}
+
+// PaymentStatusChange describes a status transition reported by a webhook, the reconciler
+// or an operator. When Status is empty it is derived from ProviderStatus.
+type PaymentStatusChange struct {
+	Status               entities.PaymentStatus
+	Source               entities.PaymentEventSource
+	Actor                string
+	ProviderStatus       string
+	ProviderStatusDetail string
+}
+
+// PaymentListFilter selects payments by status and, optionally, a date range
+// (both bounds inclusive).
+type PaymentListFilter struct {
+	Status entities.PaymentStatus
+	From   *time.Time
+	To     *time.Time
 }
 
 type BillingPaymentUseCase struct {
 	repo         interfaces.IBillingPaymentRepository
 	estimateRepo interfaces.IEstimateRepository
 	gateway      interfaces.IPaymentGateway
+	validator    interfaces.IPaymentPayloadValidator
+	extractor    interfaces.IPaymentDetailsExtractor
+	protector    interfaces.ISensitiveDataProtector
+	history      interfaces.IPaymentStatusEventRepository
+	conversions  interfaces.IEstimateConversionRepository
+	ledger       interfaces.ILedgerRepository
+	invoices     IInvoiceUseCase
 }
 
 var _ IBillingPaymentUseCase = (*BillingPaymentUseCase)(nil)
@@ -48,6 +106,53 @@ func NewBillingPaymentUseCase(repo interfaces.IBillingPaymentRepository, estimat
 	return &BillingPaymentUseCase{repo: repo, estimateRepo: estimateRepo, gateway: gateway}
 }
 
+// WithPayloadValidator enables schema validation of the enriched payload right before
+// it is sent to the payment gateway. Without a validator the payload is sent as-is.
+func (u *BillingPaymentUseCase) WithPayloadValidator(v interfaces.IPaymentPayloadValidator) *BillingPaymentUseCase {
+	u.validator = v
+	return u
+}
+
+// WithDetailsExtractor fills BillingPayment.Details from the provider response.
+// Without an extractor only the raw payload is stored.
+func (u *BillingPaymentUseCase) WithDetailsExtractor(e interfaces.IPaymentDetailsExtractor) *BillingPaymentUseCase {
+	u.extractor = e
+	return u
+}
+
+// WithSensitiveDataProtector encrypts payer personal data before the payment is persisted.
+func (u *BillingPaymentUseCase) WithSensitiveDataProtector(p interfaces.ISensitiveDataProtector) *BillingPaymentUseCase {
+	u.protector = p
+	return u
+}
+
+// WithStatusHistory enables the append-only payment status timeline.
+func (u *BillingPaymentUseCase) WithStatusHistory(h interfaces.IPaymentStatusEventRepository) *BillingPaymentUseCase {
+	u.history = h
+	return u
+}
+
+// WithConversionProjection records the first approved payment of each estimate in the
+// analytics read model.
+func (u *BillingPaymentUseCase) WithConversionProjection(c interfaces.IEstimateConversionRepository) *BillingPaymentUseCase {
+	u.conversions = c
+	return u
+}
+
+// WithLedgerPosting posts the ledger entry of each payment, refund and chargeback (see
+// entities.LedgerEntryForTransition), in the same transaction as the payment write.
+func (u *BillingPaymentUseCase) WithLedgerPosting(l interfaces.ILedgerRepository) *BillingPaymentUseCase {
+	u.ledger = l
+	return u
+}
+
+// WithInvoicing applies captured payments to the invoice of their estimate and takes
+// refunds and lost chargebacks back out.
+func (u *BillingPaymentUseCase) WithInvoicing(i IInvoiceUseCase) *BillingPaymentUseCase {
+	u.invoices = i
+	return u
+}
+
 func (u *BillingPaymentUseCase) CreateAndApprove(ctx context.Context, estimateID string, mpPayload json.RawMessage) (entities.BillingPayment, error) {
 	log.Printf("[payment][usecase] create-and-approve start raw_estimate_id=%q payload_len=%d", estimateID, len(mpPayload))
 	mockMode := isPaymentGatewayMockEnabled()
@@ -92,6 +197,10 @@ func (u *BillingPaymentUseCase) CreateAndApprove(ctx context.Context, estimateID
 		log.Printf("[payment][usecase] estimate not found estimate_id=%s", estimateID)
 		return entities.BillingPayment{}, ErrEstimateNotFound
 	}
+	if est.AwaitsInternalApproval() {
+		log.Printf("[payment][usecase] estimate awaits internal approval estimate_id=%s", estimateID)
+		return entities.BillingPayment{}, ErrInternalApprovalPending
+	}
 	if !mockMode && est.Status != entities.EstimateStatusAprovado {
 		log.Printf("[payment][usecase] estimate not approved estimate_id=%s status=%s", estimateID, est.Status)
 		return entities.BillingPayment{}, ErrEstimateNotApproved
@@ -163,6 +272,9 @@ func (u *BillingPaymentUseCase) CreateAndApprove(ctx context.Context, estimateID
 		}
 		providerResp = b
 	} else {
+		if err := u.validatePayload(ctx, estimateID, mpPayload); err != nil {
+			return entities.BillingPayment{}, err
+		}
 		log.Printf("[payment][usecase] calling payment gateway estimate_id=%s", estimateID)
 		providerPaymentID, providerStatus, providerResp, err = u.gateway.CreatePayment(ctx, mpPayload)
 		if err != nil {
@@ -191,25 +303,79 @@ func (u *BillingPaymentUseCase) CreateAndApprove(ctx context.Context, estimateID
 		log.Printf("[payment][usecase] provider response unmarshal failed estimate_id=%s err=%v", estimateID, err)
 	}
 
+	var details entities.PaymentDetails
+	if u.extractor != nil {
+		if details, err = u.extractor.ExtractDetails(providerResp); err != nil {
+			log.Printf("[payment][usecase] provider details extraction failed estimate_id=%s err=%v", estimateID, err)
+		}
+	}
+
 	now := time.Now().UTC()
 	p := entities.BillingPayment{
 		ID:           providerPaymentID,
 		EstimateID:   estimateID,
 		Date:         now,
 		Status:       status,
+		Details:      details,
 		MPPayloadRaw: providerResp,
 		MPPayload:    parsed,
 	}
 
-	created, err := u.repo.Create(ctx, p)
+	if u.protector != nil {
+		if p, err = u.protector.Protect(ctx, p); err != nil {
+			log.Printf("[payment][usecase] sensitive data encryption failed estimate_id=%s payment_id=%s err=%v", estimateID, p.ID, err)
+			return entities.BillingPayment{}, err
+		}
+	}
+
+	posting := entities.PaymentPosting{Event: entities.PaymentStatusEvent{
+		ID:                   uuid.NewString(),
+		PaymentID:            p.ID,
+		Status:               p.Status,
+		Source:               entities.PaymentEventSourceAPI,
+		ProviderStatus:       providerStatus,
+		ProviderStatusDetail: stringField(parsed, "status_detail"),
+		CreatedAt:            now,
+	}}
+	if entry, ok := u.ledgerEntry(p, "", p.Status, now); ok {
+		posting.Entry = &entry
+	}
+	created, err := u.repo.CreateWithPosting(ctx, p, posting)
+	if err != nil && !errors.Is(err, entities.ErrPaymentAlreadyExists) {
+		// The payment is already charged at this point: keep it and leave its posting in
+		// the outbox, which backfill-ledger drains.
+		log.Printf("[payment][usecase] payment posting failed payment_id=%s err=%v (posting deferred to the outbox)", p.ID, err)
+		created, err = u.repo.CreateWithPendingPosting(ctx, p, posting)
+	}
 	if err != nil {
 		log.Printf("[payment][usecase] payment repository create failed estimate_id=%s payment_id=%s err=%v", estimateID, p.ID, err)
 		return entities.BillingPayment{}, err
 	}
 	log.Printf("[payment][usecase] create-and-approve success estimate_id=%s payment_id=%s status=%s", estimateID, created.ID, created.Status)
+
+	if created.Status == entities.PaymentStatusAprovado {
+		u.projectPaid(ctx, created.EstimateID, now)
+	}
+	u.applyToInvoice(ctx, created, "", created.Status)
 	return created, nil
 }
 
+func (u *BillingPaymentUseCase) validatePayload(ctx context.Context, estimateID string, payload json.RawMessage) error {
+	if u.validator == nil {
+		return nil
+	}
+	pointers, err := u.validator.Validate(ctx, payload)
+	if err != nil {
+		log.Printf("[payment][usecase] payload validation failed estimate_id=%s err=%v", estimateID, err)
+		return err
+	}
+	if len(pointers) > 0 {
+		log.Printf("[payment][usecase] payload schema violation estimate_id=%s pointers=%v", estimateID, pointers)
+		return &PaymentPayloadSchemaError{Pointers: pointers}
+	}
+	return nil
+}
+
 func hasNonEmptyString(m map[string]any, key string) bool {
 	v, ok := m[key]
 	if !ok {
@@ -356,7 +522,7 @@ func isGatewayCustomerNotFound(err error) bool {
 func (u *BillingPaymentUseCase) GetByID(ctx context.Context, id string) (entities.BillingPayment, error) {
 	id = strings.TrimSpace(id)
 	if id == "" {
-		return entities.BillingPayment{}, errors.New("invalid payment id")
+		return entities.BillingPayment{}, ErrInvalidPaymentID
 	}
 
 	p, err := u.repo.GetByID(ctx, id)
@@ -369,10 +535,234 @@ func (u *BillingPaymentUseCase) GetByID(ctx context.Context, id string) (entitie
 	return p, nil
 }
 
-func (u *BillingPaymentUseCase) ListByEstimateID(ctx context.Context, estimateID string) ([]entities.BillingPayment, error) {
+// ListByEstimateID returns a page of the estimate payments ordered by date (oldest first
+// unless page.Descending is set).
+func (u *BillingPaymentUseCase) ListByEstimateID(ctx context.Context, estimateID string, page entities.PageRequest) (entities.Page[entities.BillingPayment], error) {
 	estimateID = strings.TrimSpace(estimateID)
 	if estimateID == "" {
-		return nil, ErrInvalidPaymentEstimateID
+		return entities.Page[entities.BillingPayment]{}, ErrInvalidPaymentEstimateID
+	}
+	return u.repo.ListByEstimateID(ctx, estimateID, normalizePaymentPage(page))
+}
+
+// LatestByEstimateID returns the most recent payment of an estimate.
+func (u *BillingPaymentUseCase) LatestByEstimateID(ctx context.Context, estimateID string) (entities.BillingPayment, error) {
+	page, err := u.ListByEstimateID(ctx, estimateID, entities.PageRequest{Limit: 1, Descending: true})
+	if err != nil {
+		return entities.BillingPayment{}, err
+	}
+	if len(page.Items) == 0 {
+		return entities.BillingPayment{}, ErrBillingPaymentNotFound
+	}
+	return page.Items[0], nil
+}
+
+// ListByStatus returns a page of payments with the given status ordered by date.
+func (u *BillingPaymentUseCase) ListByStatus(ctx context.Context, filter PaymentListFilter, page entities.PageRequest) (entities.Page[entities.BillingPayment], error) {
+	if !filter.Status.IsValid() {
+		return entities.Page[entities.BillingPayment]{}, ErrInvalidPaymentStatus
+	}
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return entities.Page[entities.BillingPayment]{}, ErrInvalidPaymentDateRange
+	}
+	return u.repo.ListByStatus(ctx, filter.Status, filter.From, filter.To, normalizePaymentPage(page))
+}
+
+func normalizePaymentPage(page entities.PageRequest) entities.PageRequest {
+	page.Cursor = strings.TrimSpace(page.Cursor)
+	switch {
+	case page.Limit <= 0:
+		page.Limit = defaultPaymentPageLimit
+	case page.Limit > maxPaymentPageLimit:
+		page.Limit = maxPaymentPageLimit
+	}
+	return page
+}
+
+// Reveal returns a payment with payer personal data decrypted (privileged access).
+func (u *BillingPaymentUseCase) Reveal(ctx context.Context, id string) (entities.BillingPayment, error) {
+	p, err := u.GetByID(ctx, id)
+	if err != nil {
+		return entities.BillingPayment{}, err
+	}
+	if u.protector == nil {
+		return p, nil
+	}
+	revealed, err := u.protector.Reveal(ctx, p)
+	if err != nil {
+		log.Printf("[payment][usecase] reveal failed payment_id=%s err=%v", p.ID, err)
+		return entities.BillingPayment{}, err
+	}
+	log.Printf("[payment][usecase] sensitive data revealed payment_id=%s", p.ID)
+	return revealed, nil
+}
+
+// ChangeStatus updates the current status of a payment and appends the transition to its
+// status history, in the same write as the status (and its ledger entry). Repeated
+// reports (same status and same provider status/detail as the latest event) are ignored,
+// so webhooks and the reconciler can be retried safely; a transition the payment cannot
+// make (see entities.PaymentStatus.CanTransitionTo) fails with
+// ErrPaymentTransitionNotAllowed.
+func (u *BillingPaymentUseCase) ChangeStatus(ctx context.Context, id string, change PaymentStatusChange) (entities.BillingPayment, error) {
+	if change.Status == "" {
+		change.Status = entities.PaymentStatusFromProvider(change.ProviderStatus)
+	}
+	if !change.Status.IsValid() {
+		return entities.BillingPayment{}, ErrInvalidPaymentStatus
+	}
+	if !change.Source.IsValid() {
+		return entities.BillingPayment{}, ErrInvalidPaymentEventSource
+	}
+
+	p, err := u.GetByID(ctx, id)
+	if err != nil {
+		return entities.BillingPayment{}, err
+	}
+	if p.Status == change.Status {
+		repeated, err := u.isRepeatedStatusReport(ctx, p.ID, change)
+		if err != nil {
+			return entities.BillingPayment{}, err
+		}
+		if repeated {
+			return p, nil
+		}
+	}
+
+	if p.Status != change.Status && !p.Status.CanTransitionTo(change.Status) {
+		log.Printf("[payment][usecase] status change rejected payment_id=%s from=%s to=%s", p.ID, p.Status, change.Status)
+		return entities.BillingPayment{}, ErrPaymentTransitionNotAllowed
+	}
+
+	now := time.Now().UTC()
+	event := entities.PaymentStatusEvent{
+		ID:                   uuid.NewString(),
+		PaymentID:            p.ID,
+		Status:               change.Status,
+		PreviousStatus:       p.Status,
+		Source:               change.Source,
+		Actor:                change.Actor,
+		ProviderStatus:       change.ProviderStatus,
+		ProviderStatusDetail: change.ProviderStatusDetail,
+		CreatedAt:            now,
+	}
+	if p.Status != change.Status {
+		var entry *entities.LedgerEntry
+		if e, ok := u.ledgerEntry(p, p.Status, change.Status, now); ok {
+			entry = &e
+		}
+		if err := u.repo.UpdateStatusWithEvent(ctx, event, entry); err != nil {
+			log.Printf("[payment][usecase] status update failed payment_id=%s err=%v", p.ID, err)
+			if errors.Is(err, entities.ErrPaymentStatusChanged) || errors.Is(err, entities.ErrLedgerConflict) {
+				return entities.BillingPayment{}, ErrPaymentStatusConflict
+			}
+			return entities.BillingPayment{}, err
+		}
+	} else if err := u.appendStatusEvent(ctx, event); err != nil {
+		log.Printf("[payment][usecase] status history append failed payment_id=%s err=%v", p.ID, err)
+		return entities.BillingPayment{}, err
+	}
+	log.Printf("[payment][usecase] status changed payment_id=%s from=%s to=%s source=%s", p.ID, p.Status, change.Status, change.Source)
+	if change.Status == entities.PaymentStatusAprovado && p.Status != change.Status {
+		u.projectPaid(ctx, p.EstimateID, event.CreatedAt)
+	}
+	u.applyToInvoice(ctx, p, p.Status, change.Status)
+
+	p.Status = change.Status
+	return p, nil
+}
+
+// History returns the status timeline of a payment, oldest event first.
+func (u *BillingPaymentUseCase) History(ctx context.Context, id string) ([]entities.PaymentStatusEvent, error) {
+	p, err := u.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if u.history == nil {
+		return []entities.PaymentStatusEvent{}, nil
+	}
+	events, err := u.history.ListByPaymentID(ctx, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	if events == nil {
+		events = []entities.PaymentStatusEvent{}
+	}
+	return events, nil
+}
+
+func (u *BillingPaymentUseCase) isRepeatedStatusReport(ctx context.Context, paymentID string, change PaymentStatusChange) (bool, error) {
+	if u.history == nil {
+		return true, nil
+	}
+	events, err := u.history.ListByPaymentID(ctx, paymentID)
+	if err != nil {
+		return false, err
+	}
+	if len(events) == 0 {
+		return change.ProviderStatus == "", nil
+	}
+	last := events[len(events)-1]
+	return last.Status == change.Status &&
+		last.ProviderStatus == change.ProviderStatus &&
+		last.ProviderStatusDetail == change.ProviderStatusDetail, nil
+}
+
+// ledgerEntry returns the ledger entry of the transition when ledger posting is enabled.
+func (u *BillingPaymentUseCase) ledgerEntry(p entities.BillingPayment, previous, next entities.PaymentStatus, at time.Time) (entities.LedgerEntry, bool) {
+	if u.ledger == nil {
+		return entities.LedgerEntry{}, false
+	}
+	entry, ok := entities.LedgerEntryForTransition(p, previous, next, at)
+	if !ok && p.Details.Amount <= 0 && next != previous {
+		log.Printf("[payment][usecase] ledger entry not posted payment_id=%s status=%s: amount missing", p.ID, next)
+	}
+	return entry, ok
+}
+
+// projectPaid records the payment in the analytics read model; failures are only logged.
+func (u *BillingPaymentUseCase) projectPaid(ctx context.Context, estimateID string, paidAt time.Time) {
+	if u.conversions == nil {
+		return
+	}
+	if err := u.conversions.MarkPaid(ctx, estimateID, paidAt); err != nil {
+		log.Printf("[payment][usecase] conversion projection failed estimate_id=%s err=%v", estimateID, err)
+	}
+}
+
+// applyToInvoice reflects a payment transition on the estimate invoice. The payment is
+// already persisted, so a failure is logged and left to the repair-invoices command.
+func (u *BillingPaymentUseCase) applyToInvoice(ctx context.Context, p entities.BillingPayment, previous, next entities.PaymentStatus) {
+	if u.invoices == nil {
+		return
+	}
+	kind, ok := entities.PaymentTransitionKind(previous, next)
+	if !ok {
+		return
+	}
+	var err error
+	if kind == entities.LedgerEntryPayment {
+		err = u.invoices.ApplyPayment(ctx, p)
+	} else {
+		err = u.invoices.ReversePayment(ctx, p)
+	}
+	if err != nil {
+		log.Printf("[payment][usecase] invoice update failed payment_id=%s estimate_id=%s kind=%s err=%v (run repair-invoices)", p.ID, p.EstimateID, kind, err)
+	}
+}
+
+func (u *BillingPaymentUseCase) appendStatusEvent(ctx context.Context, e entities.PaymentStatusEvent) error {
+	if u.history == nil {
+		return nil
+	}
+	if e.ID == "" {
+		e.ID = uuid.NewString()
+	}
+	return u.history.Append(ctx, e)
+}
+
+func stringField(m map[string]interface{}, key string) string {
+	if v, ok := m[key].(string); ok {
+		return v
 	}
-	return u.repo.ListByEstimateID(ctx, estimateID)
+	return ""
 }
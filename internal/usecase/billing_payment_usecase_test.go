@@ -117,6 +117,23 @@ func TestBillingPaymentUseCase_CreateAndApprove_EstimateChecks(t *testing.T) {
 			t.Fatalf("expected ErrEstimateNotApproved, got %v", err)
 		}
 	})
+
+	t.Run("estimate awaiting internal approval", func(t *testing.T) {
+		t.Setenv("PAYMENT_GATEWAY_MOCK", "true")
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		gateway := mock_interfaces.NewMockIPaymentGateway(ctrl)
+		uc := NewBillingPaymentUseCase(repo, estRepo, gateway)
+
+		estRepo.EXPECT().GetByID(gomock.Any(), "est-1").Return(entities.Estimate{ID: "est-1", Status: entities.EstimateStatusAguardandoAprovacao}, nil)
+
+		_, err := uc.CreateAndApprove(context.Background(), "est-1", nil)
+		if !errors.Is(err, ErrInternalApprovalPending) {
+			t.Fatalf("expected ErrInternalApprovalPending, got %v", err)
+		}
+	})
 }
 
 func TestBillingPaymentUseCase_CreateAndApprove_PayloadValidation(t *testing.T) {
@@ -158,6 +175,218 @@ func TestBillingPaymentUseCase_CreateAndApprove_PayloadValidation(t *testing.T)
 	})
 }
 
+func TestBillingPaymentUseCase_CreateAndApprove_SchemaValidation(t *testing.T) {
+	t.Setenv("PAYMENT_GATEWAY_MOCK", "")
+	t.Setenv("MERCADOPAGO_MOCK", "")
+
+	t.Run("violations stop the gateway call", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		gateway := mock_interfaces.NewMockIPaymentGateway(ctrl)
+		validator := mock_interfaces.NewMockIPaymentPayloadValidator(ctrl)
+		uc := NewBillingPaymentUseCase(repo, estRepo, gateway).WithPayloadValidator(validator)
+
+		estRepo.EXPECT().GetByID(gomock.Any(), "est-1").Return(entities.Estimate{ID: "est-1", Status: entities.EstimateStatusAprovado, Price: 10}, nil)
+		validator.EXPECT().Validate(gomock.Any(), gomock.Any()).Return([]string{"/token"}, nil)
+
+		_, err := uc.CreateAndApprove(context.Background(), "est-1", json.RawMessage(`{"payment_method_id":"visa","payer":{"email":"x@test.com"}}`))
+		if !errors.Is(err, ErrPaymentPayloadSchemaViolation) {
+			t.Fatalf("expected ErrPaymentPayloadSchemaViolation, got %v", err)
+		}
+		var schemaErr *PaymentPayloadSchemaError
+		if !errors.As(err, &schemaErr) || len(schemaErr.Pointers) != 1 || schemaErr.Pointers[0] != "/token" {
+			t.Fatalf("expected pointers [/token], got %v", err)
+		}
+	})
+
+	t.Run("validator error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		gateway := mock_interfaces.NewMockIPaymentGateway(ctrl)
+		validator := mock_interfaces.NewMockIPaymentPayloadValidator(ctrl)
+		uc := NewBillingPaymentUseCase(repo, estRepo, gateway).WithPayloadValidator(validator)
+
+		estRepo.EXPECT().GetByID(gomock.Any(), "est-1").Return(entities.Estimate{ID: "est-1", Status: entities.EstimateStatusAprovado, Price: 10}, nil)
+		validator.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil, errors.New("schema"))
+
+		_, err := uc.CreateAndApprove(context.Background(), "est-1", json.RawMessage(`{"payment_method_id":"pix","payer":{"email":"x@test.com"}}`))
+		if err == nil || err.Error() != "schema" {
+			t.Fatalf("expected schema error, got %v", err)
+		}
+	})
+
+	t.Run("valid payload reaches the gateway", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		gateway := mock_interfaces.NewMockIPaymentGateway(ctrl)
+		validator := mock_interfaces.NewMockIPaymentPayloadValidator(ctrl)
+		uc := NewBillingPaymentUseCase(repo, estRepo, gateway).WithPayloadValidator(validator)
+
+		estRepo.EXPECT().GetByID(gomock.Any(), "est-1").Return(entities.Estimate{ID: "est-1", Status: entities.EstimateStatusAprovado, Price: 10}, nil)
+		validator.EXPECT().Validate(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, payload json.RawMessage) ([]string, error) {
+				var body map[string]any
+				_ = json.Unmarshal(payload, &body)
+				if body["external_reference"] != "est-1" || body["transaction_amount"] != float64(10) {
+					t.Fatalf("validator must receive the enriched payload: %s", payload)
+				}
+				return nil, nil
+			},
+		)
+		gateway.EXPECT().CreatePayment(gomock.Any(), gomock.Any()).Return("pay-1", "approved", json.RawMessage(`{"id":1}`), nil)
+		repo.EXPECT().CreateWithPosting(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, p entities.BillingPayment, _ entities.PaymentPosting) (entities.BillingPayment, error) {
+			return p, nil
+		})
+
+		if _, err := uc.CreateAndApprove(context.Background(), "est-1", json.RawMessage(`{"payment_method_id":"pix","payer":{"email":"x@test.com"}}`)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestBillingPaymentUseCase_CreateAndApprove_DetailsExtraction(t *testing.T) {
+	t.Setenv("PAYMENT_GATEWAY_MOCK", "")
+	t.Setenv("MERCADOPAGO_MOCK", "")
+
+	cases := []struct {
+		name       string
+		details    entities.PaymentDetails
+		extractErr error
+		wantAmount float64
+	}{
+		{name: "details stored", details: entities.PaymentDetails{Amount: 10, PaymentMethodID: "pix"}, wantAmount: 10},
+		{name: "extraction failure does not block payment", extractErr: errors.New("parse"), wantAmount: 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+			estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+			gateway := mock_interfaces.NewMockIPaymentGateway(ctrl)
+			extractor := mock_interfaces.NewMockIPaymentDetailsExtractor(ctrl)
+			uc := NewBillingPaymentUseCase(repo, estRepo, gateway).WithDetailsExtractor(extractor)
+
+			providerResp := json.RawMessage(`{"id":1,"transaction_amount":10}`)
+			estRepo.EXPECT().GetByID(gomock.Any(), "est-1").Return(entities.Estimate{ID: "est-1", Status: entities.EstimateStatusAprovado, Price: 10}, nil)
+			gateway.EXPECT().CreatePayment(gomock.Any(), gomock.Any()).Return("pay-1", "approved", providerResp, nil)
+			extractor.EXPECT().ExtractDetails(providerResp).Return(tc.details, tc.extractErr)
+			repo.EXPECT().CreateWithPosting(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, p entities.BillingPayment, _ entities.PaymentPosting) (entities.BillingPayment, error) {
+				return p, nil
+			})
+
+			res, err := uc.CreateAndApprove(context.Background(), "est-1", json.RawMessage(`{"payment_method_id":"pix","payer":{"email":"x@test.com"}}`))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if res.Details.Amount != tc.wantAmount {
+				t.Fatalf("expected amount %v, got %+v", tc.wantAmount, res.Details)
+			}
+		})
+	}
+}
+
+func TestBillingPaymentUseCase_SensitiveData(t *testing.T) {
+	t.Setenv("PAYMENT_GATEWAY_MOCK", "")
+	t.Setenv("MERCADOPAGO_MOCK", "")
+
+	t.Run("payment is encrypted before persisting", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		gateway := mock_interfaces.NewMockIPaymentGateway(ctrl)
+		protector := mock_interfaces.NewMockISensitiveDataProtector(ctrl)
+		uc := NewBillingPaymentUseCase(repo, estRepo, gateway).WithSensitiveDataProtector(protector)
+
+		estRepo.EXPECT().GetByID(gomock.Any(), "est-1").Return(entities.Estimate{ID: "est-1", Status: entities.EstimateStatusAprovado, Price: 10}, nil)
+		gateway.EXPECT().CreatePayment(gomock.Any(), gomock.Any()).Return("pay-1", "approved", json.RawMessage(`{"id":1}`), nil)
+		protector.EXPECT().Protect(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, p entities.BillingPayment) (entities.BillingPayment, error) {
+			p.DataKey = &entities.EncryptedDataKey{KeyID: "k1"}
+			return p, nil
+		})
+		repo.EXPECT().CreateWithPosting(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, p entities.BillingPayment, _ entities.PaymentPosting) (entities.BillingPayment, error) {
+			if p.DataKey == nil || p.DataKey.KeyID != "k1" {
+				t.Fatalf("payment must be protected before create: %+v", p)
+			}
+			return p, nil
+		})
+
+		if _, err := uc.CreateAndApprove(context.Background(), "est-1", json.RawMessage(`{"payment_method_id":"pix","payer":{"email":"x@test.com"}}`)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("encryption failure aborts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		gateway := mock_interfaces.NewMockIPaymentGateway(ctrl)
+		protector := mock_interfaces.NewMockISensitiveDataProtector(ctrl)
+		uc := NewBillingPaymentUseCase(repo, estRepo, gateway).WithSensitiveDataProtector(protector)
+
+		estRepo.EXPECT().GetByID(gomock.Any(), "est-1").Return(entities.Estimate{ID: "est-1", Status: entities.EstimateStatusAprovado, Price: 10}, nil)
+		gateway.EXPECT().CreatePayment(gomock.Any(), gomock.Any()).Return("pay-1", "approved", json.RawMessage(`{"id":1}`), nil)
+		protector.EXPECT().Protect(gomock.Any(), gomock.Any()).Return(entities.BillingPayment{}, errors.New("kms"))
+
+		_, err := uc.CreateAndApprove(context.Background(), "est-1", json.RawMessage(`{"payment_method_id":"pix","payer":{"email":"x@test.com"}}`))
+		if err == nil || err.Error() != "kms" {
+			t.Fatalf("expected kms error, got %v", err)
+		}
+	})
+
+	t.Run("Reveal decrypts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		protector := mock_interfaces.NewMockISensitiveDataProtector(ctrl)
+		uc := NewBillingPaymentUseCase(repo, nil, nil).WithSensitiveDataProtector(protector)
+
+		stored := entities.BillingPayment{ID: "pay-1", DataKey: &entities.EncryptedDataKey{KeyID: "k1"}}
+		repo.EXPECT().GetByID(gomock.Any(), "pay-1").Return(stored, nil)
+		protector.EXPECT().Reveal(gomock.Any(), stored).Return(entities.BillingPayment{ID: "pay-1", Details: entities.PaymentDetails{PayerEmail: "x@test.com"}}, nil)
+
+		p, err := uc.Reveal(context.Background(), "pay-1")
+		if err != nil || p.Details.PayerEmail != "x@test.com" {
+			t.Fatalf("unexpected result: %+v err=%v", p, err)
+		}
+	})
+
+	t.Run("Reveal without protector returns stored payment", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		uc := NewBillingPaymentUseCase(repo, nil, nil)
+
+		repo.EXPECT().GetByID(gomock.Any(), "pay-1").Return(entities.BillingPayment{ID: "pay-1"}, nil)
+
+		if _, err := uc.Reveal(context.Background(), "pay-1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Reveal not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		uc := NewBillingPaymentUseCase(repo, nil, nil)
+
+		repo.EXPECT().GetByID(gomock.Any(), "pay-1").Return(entities.BillingPayment{}, nil)
+
+		if _, err := uc.Reveal(context.Background(), "pay-1"); !errors.Is(err, ErrBillingPaymentNotFound) {
+			t.Fatalf("expected ErrBillingPaymentNotFound, got %v", err)
+		}
+	})
+}
+
 func TestBillingPaymentUseCase_CreateAndApprove_GatewayErrorMapping(t *testing.T) {
 	t.Setenv("PAYMENT_GATEWAY_MOCK", "")
 	t.Setenv("MERCADOPAGO_MOCK", "")
@@ -263,8 +492,8 @@ func TestBillingPaymentUseCase_CreateAndApprove_SuccessAndStatuses(t *testing.T)
 				},
 			)
 
-			repo.EXPECT().Create(gomock.Any(), gomock.AssignableToTypeOf(entities.BillingPayment{})).DoAndReturn(
-				func(_ context.Context, p entities.BillingPayment) (entities.BillingPayment, error) {
+			repo.EXPECT().CreateWithPosting(gomock.Any(), gomock.AssignableToTypeOf(entities.BillingPayment{}), gomock.Any()).DoAndReturn(
+				func(_ context.Context, p entities.BillingPayment, _ entities.PaymentPosting) (entities.BillingPayment, error) {
 					if p.ID != "pay-1" || p.EstimateID != "est-1" || p.Status != tc.want {
 						t.Fatalf("unexpected payment: %+v", p)
 					}
@@ -295,7 +524,8 @@ func TestBillingPaymentUseCase_CreateAndApprove_SuccessAndStatuses(t *testing.T)
 
 		estRepo.EXPECT().GetByID(gomock.Any(), "est-1").Return(entities.Estimate{ID: "est-1", Status: entities.EstimateStatusAprovado, Price: 11}, nil)
 		gateway.EXPECT().CreatePayment(gomock.Any(), gomock.Any()).Return("pay-1", "approved", json.RawMessage(`{"id":123}`), nil)
-		repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(entities.BillingPayment{}, errors.New("db-create"))
+		repo.EXPECT().CreateWithPosting(gomock.Any(), gomock.Any(), gomock.Any()).Return(entities.BillingPayment{}, errors.New("transaction canceled"))
+		repo.EXPECT().CreateWithPendingPosting(gomock.Any(), gomock.Any(), gomock.Any()).Return(entities.BillingPayment{}, errors.New("db-create"))
 
 		_, err := uc.CreateAndApprove(context.Background(), "est-1", json.RawMessage(`{"payment_method_id":"pix","payer":{"email":"x@test.com"}}`))
 		if err == nil || err.Error() != "db-create" {
@@ -357,22 +587,73 @@ func TestBillingPaymentUseCase_Getters(t *testing.T) {
 
 	t.Run("ListByEstimateID invalid", func(t *testing.T) {
 		uc := NewBillingPaymentUseCase(nil, nil, nil)
-		_, err := uc.ListByEstimateID(context.Background(), " ")
+		_, err := uc.ListByEstimateID(context.Background(), " ", entities.PageRequest{})
 		if !errors.Is(err, ErrInvalidPaymentEstimateID) {
 			t.Fatalf("expected ErrInvalidPaymentEstimateID, got %v", err)
 		}
 	})
 
-	t.Run("ListByEstimateID success", func(t *testing.T) {
+	t.Run("ListByEstimateID applies default and max page size", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
 		uc := NewBillingPaymentUseCase(repo, nil, nil)
-		expected := []entities.BillingPayment{{ID: "p1", Date: time.Now()}}
-		repo.EXPECT().ListByEstimateID(gomock.Any(), "est-1").Return(expected, nil)
+		expected := entities.Page[entities.BillingPayment]{Items: []entities.BillingPayment{{ID: "p1", Date: time.Now()}}, NextCursor: "c2"}
+		repo.EXPECT().ListByEstimateID(gomock.Any(), "est-1", entities.PageRequest{Limit: 20, Cursor: "c1"}).Return(expected, nil)
+		repo.EXPECT().ListByEstimateID(gomock.Any(), "est-1", entities.PageRequest{Limit: 100, Descending: true}).Return(expected, nil)
 
-		res, err := uc.ListByEstimateID(context.Background(), " est-1 ")
-		if err != nil || len(res) != 1 || res[0].ID != "p1" {
+		res, err := uc.ListByEstimateID(context.Background(), " est-1 ", entities.PageRequest{Cursor: " c1 "})
+		if err != nil || len(res.Items) != 1 || res.Items[0].ID != "p1" || res.NextCursor != "c2" {
+			t.Fatalf("unexpected result err=%v res=%+v", err, res)
+		}
+		if _, err := uc.ListByEstimateID(context.Background(), "est-1", entities.PageRequest{Limit: 500, Descending: true}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("LatestByEstimateID", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		uc := NewBillingPaymentUseCase(repo, nil, nil)
+		latestPage := entities.PageRequest{Limit: 1, Descending: true}
+		repo.EXPECT().ListByEstimateID(gomock.Any(), "est-1", latestPage).
+			Return(entities.Page[entities.BillingPayment]{Items: []entities.BillingPayment{{ID: "p2"}}}, nil)
+		repo.EXPECT().ListByEstimateID(gomock.Any(), "est-2", latestPage).
+			Return(entities.Page[entities.BillingPayment]{}, nil)
+
+		if p, err := uc.LatestByEstimateID(context.Background(), "est-1"); err != nil || p.ID != "p2" {
+			t.Fatalf("unexpected result err=%v p=%+v", err, p)
+		}
+		if _, err := uc.LatestByEstimateID(context.Background(), "est-2"); !errors.Is(err, ErrBillingPaymentNotFound) {
+			t.Fatalf("expected ErrBillingPaymentNotFound, got %v", err)
+		}
+	})
+
+	t.Run("ListByStatus validates filter", func(t *testing.T) {
+		uc := NewBillingPaymentUseCase(nil, nil, nil)
+		if _, err := uc.ListByStatus(context.Background(), PaymentListFilter{Status: "x"}, entities.PageRequest{}); !errors.Is(err, ErrInvalidPaymentStatus) {
+			t.Fatalf("expected ErrInvalidPaymentStatus, got %v", err)
+		}
+		from := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+		to := from.Add(-time.Hour)
+		filter := PaymentListFilter{Status: entities.PaymentStatusAprovado, From: &from, To: &to}
+		if _, err := uc.ListByStatus(context.Background(), filter, entities.PageRequest{}); !errors.Is(err, ErrInvalidPaymentDateRange) {
+			t.Fatalf("expected ErrInvalidPaymentDateRange, got %v", err)
+		}
+	})
+
+	t.Run("ListByStatus success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		uc := NewBillingPaymentUseCase(repo, nil, nil)
+		from := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+		repo.EXPECT().ListByStatus(gomock.Any(), entities.PaymentStatusAprovado, &from, nil, entities.PageRequest{Limit: 5}).
+			Return(entities.Page[entities.BillingPayment]{Items: []entities.BillingPayment{{ID: "p1"}}}, nil)
+
+		res, err := uc.ListByStatus(context.Background(), PaymentListFilter{Status: entities.PaymentStatusAprovado, From: &from}, entities.PageRequest{Limit: 5})
+		if err != nil || len(res.Items) != 1 {
 			t.Fatalf("unexpected result err=%v res=%+v", err, res)
 		}
 	})
@@ -504,7 +785,7 @@ func TestBillingPaymentUseCase_HelperFunctions(t *testing.T) {
 
 		estRepo.EXPECT().GetByID(gomock.Any(), "est-1").Return(entities.Estimate{ID: "est-1", Status: entities.EstimateStatusAprovado, Price: 42}, nil)
 		gateway.EXPECT().CreatePayment(gomock.Any(), json.RawMessage(`[]`)).Return("pay-1", "approved", json.RawMessage(`{"id":1}`), nil)
-		repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(entities.BillingPayment{ID: "pay-1", EstimateID: "est-1", Status: entities.PaymentStatusAprovado}, nil)
+		repo.EXPECT().CreateWithPosting(gomock.Any(), gomock.Any(), gomock.Any()).Return(entities.BillingPayment{ID: "pay-1", EstimateID: "est-1", Status: entities.PaymentStatusAprovado}, nil)
 
 		res, err := uc.CreateAndApprove(context.Background(), "est-1", json.RawMessage(`[]`))
 		if err != nil {
@@ -533,3 +814,319 @@ func TestBillingPaymentUseCase_HelperFunctions(t *testing.T) {
 		}
 	})
 }
+
+func TestBillingPaymentUseCase_StatusHistory(t *testing.T) {
+	t.Setenv("PAYMENT_GATEWAY_MOCK", "")
+	t.Setenv("MERCADOPAGO_MOCK", "")
+
+	t.Run("create writes the api event with the payment", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		gateway := mock_interfaces.NewMockIPaymentGateway(ctrl)
+		history := mock_interfaces.NewMockIPaymentStatusEventRepository(ctrl)
+		uc := NewBillingPaymentUseCase(repo, estRepo, gateway).WithStatusHistory(history)
+
+		estRepo.EXPECT().GetByID(gomock.Any(), "est-1").Return(entities.Estimate{ID: "est-1", Status: entities.EstimateStatusAprovado, Price: 42}, nil)
+		gateway.EXPECT().CreatePayment(gomock.Any(), gomock.Any()).Return("pay-1", "approved", json.RawMessage(`{"id":1,"status":"approved","status_detail":"accredited"}`), nil)
+		repo.EXPECT().CreateWithPosting(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, p entities.BillingPayment, posting entities.PaymentPosting) (entities.BillingPayment, error) {
+			e := posting.Event
+			if e.PaymentID != "pay-1" || e.Source != entities.PaymentEventSourceAPI || e.Status != entities.PaymentStatusAprovado {
+				t.Fatalf("unexpected event: %+v", e)
+			}
+			if e.ProviderStatus != "approved" || e.ProviderStatusDetail != "accredited" || e.ID == "" {
+				t.Fatalf("unexpected provider fields: %+v", e)
+			}
+			if posting.Entry != nil {
+				t.Fatalf("unexpected ledger entry without ledger posting: %+v", posting.Entry)
+			}
+			return p, nil
+		})
+
+		if _, err := uc.CreateAndApprove(context.Background(), "est-1", json.RawMessage(`{"payment_method_id":"pix","payer":{"email":"x@test.com"}}`)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("change status validations", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := NewBillingPaymentUseCase(mock_interfaces.NewMockIBillingPaymentRepository(ctrl), nil, nil)
+
+		if _, err := uc.ChangeStatus(context.Background(), "pay-1", PaymentStatusChange{ProviderStatus: "weird", Source: entities.PaymentEventSourceAdmin}); !errors.Is(err, ErrInvalidPaymentStatus) {
+			t.Fatalf("expected ErrInvalidPaymentStatus, got %v", err)
+		}
+		if _, err := uc.ChangeStatus(context.Background(), "pay-1", PaymentStatusChange{Status: entities.PaymentStatusReembolsado, Source: "cron"}); !errors.Is(err, ErrInvalidPaymentEventSource) {
+			t.Fatalf("expected ErrInvalidPaymentEventSource, got %v", err)
+		}
+	})
+
+	t.Run("change status updates and appends", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		history := mock_interfaces.NewMockIPaymentStatusEventRepository(ctrl)
+		uc := NewBillingPaymentUseCase(repo, nil, nil).WithStatusHistory(history)
+
+		repo.EXPECT().GetByID(gomock.Any(), "pay-1").Return(entities.BillingPayment{ID: "pay-1", Status: entities.PaymentStatusAprovado}, nil)
+		repo.EXPECT().UpdateStatusWithEvent(gomock.Any(), gomock.Any(), nil).DoAndReturn(func(_ context.Context, e entities.PaymentStatusEvent, _ *entities.LedgerEntry) error {
+			if e.PaymentID != "pay-1" || e.PreviousStatus != entities.PaymentStatusAprovado || e.Status != entities.PaymentStatusReembolsado || e.Actor != "ops" || e.ID == "" {
+				t.Fatalf("unexpected event: %+v", e)
+			}
+			return nil
+		})
+
+		p, err := uc.ChangeStatus(context.Background(), "pay-1", PaymentStatusChange{ProviderStatus: "refunded", Source: entities.PaymentEventSourceAdmin, Actor: "ops"})
+		if err != nil || p.Status != entities.PaymentStatusReembolsado {
+			t.Fatalf("unexpected result: %+v err=%v", p, err)
+		}
+	})
+
+	t.Run("approval is projected", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		conversions := mock_interfaces.NewMockIEstimateConversionRepository(ctrl)
+		uc := NewBillingPaymentUseCase(repo, nil, nil).WithConversionProjection(conversions)
+
+		repo.EXPECT().GetByID(gomock.Any(), "pay-1").Return(entities.BillingPayment{ID: "pay-1", EstimateID: "est-1", Status: entities.PaymentStatusPendente}, nil)
+		repo.EXPECT().UpdateStatusWithEvent(gomock.Any(), gomock.Any(), nil).Return(nil)
+		conversions.EXPECT().MarkPaid(gomock.Any(), "est-1", gomock.Any()).Return(nil)
+
+		if _, err := uc.ChangeStatus(context.Background(), "pay-1", PaymentStatusChange{ProviderStatus: "approved", Source: entities.PaymentEventSourceWebhook}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("transition not allowed", func(t *testing.T) {
+		for _, tc := range []struct{ from, to entities.PaymentStatus }{
+			{entities.PaymentStatusReembolsado, entities.PaymentStatusAprovado},
+			{entities.PaymentStatusNegado, entities.PaymentStatusReembolsado},
+			{entities.PaymentStatusEstornado, entities.PaymentStatusContestado},
+			{entities.PaymentStatusPendente, entities.PaymentStatusReembolsado},
+		} {
+			ctrl := gomock.NewController(t)
+			repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+			uc := NewBillingPaymentUseCase(repo, nil, nil)
+
+			repo.EXPECT().GetByID(gomock.Any(), "pay-1").Return(entities.BillingPayment{ID: "pay-1", Status: tc.from}, nil)
+
+			if _, err := uc.ChangeStatus(context.Background(), "pay-1", PaymentStatusChange{Status: tc.to, Source: entities.PaymentEventSourceAdmin}); !errors.Is(err, ErrPaymentTransitionNotAllowed) {
+				t.Fatalf("%s -> %s: expected ErrPaymentTransitionNotAllowed, got %v", tc.from, tc.to, err)
+			}
+			ctrl.Finish()
+		}
+	})
+
+	t.Run("provider detail change appends an event only", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		history := mock_interfaces.NewMockIPaymentStatusEventRepository(ctrl)
+		uc := NewBillingPaymentUseCase(repo, nil, nil).WithStatusHistory(history)
+
+		repo.EXPECT().GetByID(gomock.Any(), "pay-1").Return(entities.BillingPayment{ID: "pay-1", Status: entities.PaymentStatusPendente}, nil)
+		history.EXPECT().ListByPaymentID(gomock.Any(), "pay-1").Return([]entities.PaymentStatusEvent{
+			{Status: entities.PaymentStatusPendente, ProviderStatus: "pending", ProviderStatusDetail: "pending_waiting_payment"},
+		}, nil)
+		history.EXPECT().Append(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e entities.PaymentStatusEvent) error {
+			if e.PreviousStatus != entities.PaymentStatusPendente || e.ProviderStatus != "in_process" {
+				t.Fatalf("unexpected event: %+v", e)
+			}
+			return nil
+		})
+
+		if _, err := uc.ChangeStatus(context.Background(), "pay-1", PaymentStatusChange{ProviderStatus: "in_process", Source: entities.PaymentEventSourceWebhook}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("repeated report is ignored", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		history := mock_interfaces.NewMockIPaymentStatusEventRepository(ctrl)
+		uc := NewBillingPaymentUseCase(repo, nil, nil).WithStatusHistory(history)
+
+		repo.EXPECT().GetByID(gomock.Any(), "pay-1").Return(entities.BillingPayment{ID: "pay-1", Status: entities.PaymentStatusContestado}, nil)
+		history.EXPECT().ListByPaymentID(gomock.Any(), "pay-1").Return([]entities.PaymentStatusEvent{
+			{Status: entities.PaymentStatusAprovado, ProviderStatus: "approved"},
+			{Status: entities.PaymentStatusContestado, ProviderStatus: "charged_back"},
+		}, nil)
+
+		if _, err := uc.ChangeStatus(context.Background(), "pay-1", PaymentStatusChange{ProviderStatus: "charged_back", Source: entities.PaymentEventSourceWebhook}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("history", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		history := mock_interfaces.NewMockIPaymentStatusEventRepository(ctrl)
+		uc := NewBillingPaymentUseCase(repo, nil, nil).WithStatusHistory(history)
+
+		repo.EXPECT().GetByID(gomock.Any(), "missing").Return(entities.BillingPayment{}, nil)
+		if _, err := uc.History(context.Background(), "missing"); !errors.Is(err, ErrBillingPaymentNotFound) {
+			t.Fatalf("expected not found, got %v", err)
+		}
+
+		repo.EXPECT().GetByID(gomock.Any(), "pay-1").Return(entities.BillingPayment{ID: "pay-1"}, nil)
+		history.EXPECT().ListByPaymentID(gomock.Any(), "pay-1").Return(nil, nil)
+		events, err := uc.History(context.Background(), "pay-1")
+		if err != nil || events == nil || len(events) != 0 {
+			t.Fatalf("expected empty timeline, got %+v err=%v", events, err)
+		}
+	})
+}
+
+func TestBillingPaymentUseCase_LedgerPosting(t *testing.T) {
+	t.Setenv("PAYMENT_GATEWAY_MOCK", "")
+	t.Setenv("MERCADOPAGO_MOCK", "")
+
+	t.Run("create posts the payment entry", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		gateway := mock_interfaces.NewMockIPaymentGateway(ctrl)
+		extractor := mock_interfaces.NewMockIPaymentDetailsExtractor(ctrl)
+		ledger := mock_interfaces.NewMockILedgerRepository(ctrl)
+		uc := NewBillingPaymentUseCase(repo, estRepo, gateway).WithDetailsExtractor(extractor).WithLedgerPosting(ledger)
+
+		estRepo.EXPECT().GetByID(gomock.Any(), "est-1").Return(entities.Estimate{ID: "est-1", Status: entities.EstimateStatusAprovado, Price: 100}, nil)
+		gateway.EXPECT().CreatePayment(gomock.Any(), gomock.Any()).Return("pay-1", "approved", json.RawMessage(`{"id":1}`), nil)
+		extractor.EXPECT().ExtractDetails(gomock.Any()).Return(entities.PaymentDetails{Amount: 100, Fees: []entities.PaymentFee{{Amount: 0.99}}}, nil)
+		repo.EXPECT().CreateWithPosting(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, p entities.BillingPayment, posting entities.PaymentPosting) (entities.BillingPayment, error) {
+				e := posting.Entry
+				if e == nil || e.ID != "pay-1-payment" || e.EstimateID != "est-1" || len(e.Lines) != 4 || e.Validate() != nil {
+					t.Fatalf("unexpected entry: %+v", e)
+				}
+				if e.Lines[0] != (entities.LedgerLine{Account: entities.LedgerAccountProviderClearing, Debit: 10000}) ||
+					e.Lines[2] != (entities.LedgerLine{Account: entities.LedgerAccountFees, Debit: 99}) {
+					t.Fatalf("unexpected lines: %+v", e.Lines)
+				}
+				if posting.Event.PaymentID != "pay-1" || posting.Event.Status != entities.PaymentStatusAprovado {
+					t.Fatalf("unexpected event: %+v", posting.Event)
+				}
+				return p, nil
+			})
+
+		if _, err := uc.CreateAndApprove(context.Background(), "est-1", json.RawMessage(`{"payment_method_id":"pix","payer":{"email":"x@test.com"}}`)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("posting failure keeps the captured payment in the outbox", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		gateway := mock_interfaces.NewMockIPaymentGateway(ctrl)
+		extractor := mock_interfaces.NewMockIPaymentDetailsExtractor(ctrl)
+		ledger := mock_interfaces.NewMockILedgerRepository(ctrl)
+		uc := NewBillingPaymentUseCase(repo, estRepo, gateway).WithDetailsExtractor(extractor).WithLedgerPosting(ledger)
+
+		estRepo.EXPECT().GetByID(gomock.Any(), "est-1").Return(entities.Estimate{ID: "est-1", Status: entities.EstimateStatusAprovado, Price: 100}, nil)
+		gateway.EXPECT().CreatePayment(gomock.Any(), gomock.Any()).Return("pay-1", "approved", json.RawMessage(`{"id":1}`), nil)
+		extractor.EXPECT().ExtractDetails(gomock.Any()).Return(entities.PaymentDetails{Amount: 100}, nil)
+		var posted entities.PaymentPosting
+		failed := repo.EXPECT().CreateWithPosting(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ entities.BillingPayment, posting entities.PaymentPosting) (entities.BillingPayment, error) {
+				posted = posting
+				return entities.BillingPayment{}, errors.New("transaction canceled")
+			})
+		repo.EXPECT().CreateWithPendingPosting(gomock.Any(), gomock.Any(), gomock.Any()).After(failed).DoAndReturn(
+			func(_ context.Context, p entities.BillingPayment, posting entities.PaymentPosting) (entities.BillingPayment, error) {
+				if posting.Entry == nil || posting.Event.ID != posted.Event.ID {
+					t.Fatalf("expected the same posting in the outbox, got %+v", posting)
+				}
+				return p, nil
+			})
+
+		got, err := uc.CreateAndApprove(context.Background(), "est-1", json.RawMessage(`{"payment_method_id":"pix","payer":{"email":"x@test.com"}}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.ID != "pay-1" || got.Status != entities.PaymentStatusAprovado {
+			t.Fatalf("unexpected payment: %+v", got)
+		}
+	})
+
+	t.Run("duplicate payment skips the outbox", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		gateway := mock_interfaces.NewMockIPaymentGateway(ctrl)
+		uc := NewBillingPaymentUseCase(repo, estRepo, gateway)
+
+		estRepo.EXPECT().GetByID(gomock.Any(), "est-1").Return(entities.Estimate{ID: "est-1", Status: entities.EstimateStatusAprovado, Price: 100}, nil)
+		gateway.EXPECT().CreatePayment(gomock.Any(), gomock.Any()).Return("pay-1", "approved", json.RawMessage(`{"id":1}`), nil)
+		repo.EXPECT().CreateWithPosting(gomock.Any(), gomock.Any(), gomock.Any()).Return(entities.BillingPayment{}, entities.ErrPaymentAlreadyExists)
+
+		if _, err := uc.CreateAndApprove(context.Background(), "est-1", json.RawMessage(`{"payment_method_id":"pix","payer":{"email":"x@test.com"}}`)); !errors.Is(err, entities.ErrPaymentAlreadyExists) {
+			t.Fatalf("expected ErrPaymentAlreadyExists, got %v", err)
+		}
+	})
+
+	t.Run("status changes", func(t *testing.T) {
+		cases := []struct {
+			name      string
+			previous  entities.PaymentStatus
+			next      entities.PaymentStatus
+			wantEntry string
+		}{
+			{"refund", entities.PaymentStatusAprovado, entities.PaymentStatusReembolsado, "pay-1-refund"},
+			{"lost chargeback", entities.PaymentStatusContestado, entities.PaymentStatusEstornado, "pay-1-chargeback"},
+			{"late approval", entities.PaymentStatusPendente, entities.PaymentStatusAprovado, "pay-1-payment"},
+			{"dispute opened", entities.PaymentStatusAprovado, entities.PaymentStatusContestado, ""},
+			{"dispute won", entities.PaymentStatusContestado, entities.PaymentStatusAprovado, ""},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+				repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+				uc := NewBillingPaymentUseCase(repo, nil, nil).WithLedgerPosting(mock_interfaces.NewMockILedgerRepository(ctrl))
+
+				repo.EXPECT().GetByID(gomock.Any(), "pay-1").Return(entities.BillingPayment{
+					ID: "pay-1", EstimateID: "est-1", Status: tc.previous, Details: entities.PaymentDetails{Amount: 50},
+				}, nil)
+				repo.EXPECT().UpdateStatusWithEvent(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, ev entities.PaymentStatusEvent, e *entities.LedgerEntry) error {
+						if ev.PreviousStatus != tc.previous || ev.Status != tc.next {
+							t.Fatalf("unexpected event: %+v", ev)
+						}
+						switch {
+						case tc.wantEntry == "" && e != nil:
+							t.Fatalf("unexpected entry: %+v", e)
+						case tc.wantEntry != "" && (e == nil || e.ID != tc.wantEntry || e.Validate() != nil):
+							t.Fatalf("unexpected entry: %+v", e)
+						}
+						return nil
+					})
+
+				if _, err := uc.ChangeStatus(context.Background(), "pay-1", PaymentStatusChange{Status: tc.next, Source: entities.PaymentEventSourceWebhook}); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			})
+		}
+	})
+
+	t.Run("concurrent change", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		uc := NewBillingPaymentUseCase(repo, nil, nil).WithLedgerPosting(mock_interfaces.NewMockILedgerRepository(ctrl))
+
+		repo.EXPECT().GetByID(gomock.Any(), "pay-1").Return(entities.BillingPayment{ID: "pay-1", EstimateID: "est-1", Status: entities.PaymentStatusAprovado, Details: entities.PaymentDetails{Amount: 50}}, nil)
+		repo.EXPECT().UpdateStatusWithEvent(gomock.Any(), gomock.Any(), gomock.Any()).Return(entities.ErrPaymentStatusChanged)
+
+		if _, err := uc.ChangeStatus(context.Background(), "pay-1", PaymentStatusChange{Status: entities.PaymentStatusReembolsado, Source: entities.PaymentEventSourceAdmin}); !errors.Is(err, ErrPaymentStatusConflict) {
+			t.Fatalf("expected ErrPaymentStatusConflict, got %v", err)
+		}
+	})
+}
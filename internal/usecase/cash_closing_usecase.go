@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+)
+
+var ErrInvalidBusinessDay = errors.New("invalid business day")
+
+// CashClosingTimeZone is the workshop time zone used to delimit business days.
+const CashClosingTimeZone = "America/Sao_Paulo"
+
+// ICashClosingUseCase builds the daily cash closing report.
+type ICashClosingUseCase interface {
+	Report(ctx context.Context, businessDay string) (entities.CashClosingReport, error)
+}
+
+type CashClosingUseCase struct {
+	events   interfaces.IPaymentStatusEventRepository
+	payments interfaces.IBillingPaymentRepository
+	location *time.Location
+	now      func() time.Time
+}
+
+var _ ICashClosingUseCase = (*CashClosingUseCase)(nil)
+
+func NewCashClosingUseCase(events interfaces.IPaymentStatusEventRepository, payments interfaces.IBillingPaymentRepository) *CashClosingUseCase {
+	return &CashClosingUseCase{
+		events:   events,
+		payments: payments,
+		location: businessDayLocation(),
+		now:      time.Now,
+	}
+}
+
+// businessDayLocation falls back to a fixed UTC-3 offset when the host has no tz database
+// (Brazil has not observed daylight saving time since 2019).
+func businessDayLocation() *time.Location {
+	if loc, err := time.LoadLocation(CashClosingTimeZone); err == nil {
+		return loc
+	}
+	log.Printf("[payment][cash-closing] tz database unavailable; using fixed UTC-3 for %s", CashClosingTimeZone)
+	return time.FixedZone(CashClosingTimeZone, -3*60*60)
+}
+
+// Report aggregates the payments that were approved, refunded or became pending during
+// the business day (YYYY-MM-DD, today when empty), by the date of the status event: a
+// payment created yesterday and refunded today is reported as refunded today, and stays
+// approved in yesterday's report. Events are read through the status index of the
+// status history, one query per status. Repeated reports and a won dispute (contestado
+// back to aprovado) move no money and are not counted.
+func (u *CashClosingUseCase) Report(ctx context.Context, businessDay string) (entities.CashClosingReport, error) {
+	businessDay = strings.TrimSpace(businessDay)
+	if businessDay == "" {
+		businessDay = u.now().In(u.location).Format(time.DateOnly)
+	}
+	day, err := time.ParseInLocation(time.DateOnly, businessDay, u.location)
+	if err != nil {
+		return entities.CashClosingReport{}, ErrInvalidBusinessDay
+	}
+	from := day
+	to := day.AddDate(0, 0, 1)
+
+	report := entities.CashClosingReport{
+		BusinessDay: businessDay,
+		TimeZone:    CashClosingTimeZone,
+		From:        from,
+		To:          to,
+		ByStatus:    []entities.CashClosingStatusLine{},
+		ByMethod:    []entities.CashClosingMethodLine{},
+		ByEstimate:  []entities.CashClosingEstimateLine{},
+		GeneratedAt: u.now().UTC(),
+	}
+
+	type methodKey struct {
+		status                  entities.PaymentStatus
+		provider, method, ptype string
+	}
+	type estimateKey struct {
+		status     entities.PaymentStatus
+		estimateID string
+	}
+	byMethod := map[methodKey]*entities.CashClosingMethodLine{}
+	byEstimate := map[estimateKey]*entities.CashClosingEstimateLine{}
+
+	// The range is inclusive on both ends; stop one nanosecond before the next day.
+	last := to.Add(-time.Nanosecond)
+	payments := map[string]entities.BillingPayment{}
+	for _, status := range entities.CashClosingStatuses {
+		line := entities.CashClosingStatusLine{Status: status}
+		events, err := u.events.ListByStatusBetween(ctx, status, from, last)
+		if err != nil {
+			log.Printf("[payment][cash-closing] list events failed day=%s status=%s err=%v", businessDay, status, err)
+			return entities.CashClosingReport{}, err
+		}
+		for _, e := range events {
+			if !cashClosingCounts(e) {
+				continue
+			}
+			p, ok := payments[e.PaymentID]
+			if !ok {
+				if p, err = u.payments.GetByID(ctx, e.PaymentID); err != nil {
+					log.Printf("[payment][cash-closing] payment read failed day=%s payment_id=%s err=%v", businessDay, e.PaymentID, err)
+					return entities.CashClosingReport{}, err
+				}
+				payments[e.PaymentID] = p
+			}
+			if p.ID == "" {
+				log.Printf("[payment][cash-closing] payment missing day=%s event_id=%s payment_id=%s", businessDay, e.ID, e.PaymentID)
+				continue
+			}
+			line.Add(p)
+
+			mk := methodKey{status, entities.PaymentProviderMercadoPago, p.Details.PaymentMethodID, p.Details.PaymentTypeID}
+			if byMethod[mk] == nil {
+				byMethod[mk] = &entities.CashClosingMethodLine{Status: status, Provider: mk.provider, PaymentMethodID: mk.method, PaymentTypeID: mk.ptype}
+			}
+			byMethod[mk].Add(p)
+
+			ek := estimateKey{status, p.EstimateID}
+			if byEstimate[ek] == nil {
+				byEstimate[ek] = &entities.CashClosingEstimateLine{Status: status, EstimateID: p.EstimateID}
+			}
+			byEstimate[ek].Add(p)
+			byEstimate[ek].PaymentIDs = append(byEstimate[ek].PaymentIDs, p.ID)
+		}
+		report.ByStatus = append(report.ByStatus, line)
+	}
+
+	for _, l := range byMethod {
+		report.ByMethod = append(report.ByMethod, *l)
+	}
+	sort.Slice(report.ByMethod, func(i, j int) bool {
+		a, b := report.ByMethod[i], report.ByMethod[j]
+		if a.Status != b.Status {
+			return cashClosingStatusRank(a.Status) < cashClosingStatusRank(b.Status)
+		}
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		if a.PaymentMethodID != b.PaymentMethodID {
+			return a.PaymentMethodID < b.PaymentMethodID
+		}
+		return a.PaymentTypeID < b.PaymentTypeID
+	})
+	for _, l := range byEstimate {
+		report.ByEstimate = append(report.ByEstimate, *l)
+	}
+	sort.Slice(report.ByEstimate, func(i, j int) bool {
+		a, b := report.ByEstimate[i], report.ByEstimate[j]
+		if a.EstimateID != b.EstimateID {
+			return a.EstimateID < b.EstimateID
+		}
+		return cashClosingStatusRank(a.Status) < cashClosingStatusRank(b.Status)
+	})
+
+	log.Printf("[payment][cash-closing] report built day=%s methods=%d estimates=%d", businessDay, len(report.ByMethod), len(report.ByEstimate))
+	return report, nil
+}
+
+// cashClosingCounts reports whether the event puts the payment in a reported status: an
+// approval or refund that moves money (see entities.PaymentTransitionKind), or a payment
+// becoming pending.
+func cashClosingCounts(e entities.PaymentStatusEvent) bool {
+	if e.Status == entities.PaymentStatusPendente {
+		return e.PreviousStatus != e.Status
+	}
+	_, ok := entities.PaymentTransitionKind(e.PreviousStatus, e.Status)
+	return ok
+}
+
+func cashClosingStatusRank(s entities.PaymentStatus) int {
+	for i, status := range entities.CashClosingStatuses {
+		if status == s {
+			return i
+		}
+	}
+	return len(entities.CashClosingStatuses)
+}
+
+// WriteCashClosingCSV writes the report as CSV. The `section` column tells the grouping
+// of each row: status, method or estimate.
+func WriteCashClosingCSV(w io.Writer, report entities.CashClosingReport) error {
+	cw := csv.NewWriter(w)
+	rows := [][]string{{"business_day", "section", "status", "provider", "payment_method_id", "payment_type_id", "estimate_id", "count", "gross", "fees", "net"}}
+	row := func(section string, status entities.PaymentStatus, provider, method, ptype, estimateID string, t entities.CashClosingTotals) []string {
+		return []string{
+			report.BusinessDay, section, string(status), provider, method, ptype, estimateID,
+			strconv.Itoa(t.Count),
+			strconv.FormatFloat(t.Gross, 'f', 2, 64),
+			strconv.FormatFloat(t.Fees, 'f', 2, 64),
+			strconv.FormatFloat(t.Net, 'f', 2, 64),
+		}
+	}
+	for _, l := range report.ByStatus {
+		rows = append(rows, row("status", l.Status, "", "", "", "", l.CashClosingTotals))
+	}
+	for _, l := range report.ByMethod {
+		rows = append(rows, row("method", l.Status, l.Provider, l.PaymentMethodID, l.PaymentTypeID, "", l.CashClosingTotals))
+	}
+	for _, l := range report.ByEstimate {
+		rows = append(rows, row("estimate", l.Status, "", "", "", l.EstimateID, l.CashClosingTotals))
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
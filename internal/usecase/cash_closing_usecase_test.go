@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	mock_interfaces "mecanica_xpto/internal/usecase/interfaces/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestCashClosingUseCase_Report(t *testing.T) {
+	t.Run("invalid day", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		uc := NewCashClosingUseCase(mock_interfaces.NewMockIPaymentStatusEventRepository(ctrl), mock_interfaces.NewMockIBillingPaymentRepository(ctrl))
+		// 01:30 UTC on March 2 is still March 1 in São Paulo.
+		uc.now = func() time.Time { return time.Date(2026, 3, 2, 1, 30, 0, 0, time.UTC) }
+		if _, err := uc.Report(context.Background(), "01/03/2026"); !errors.Is(err, ErrInvalidBusinessDay) {
+			t.Fatalf("expected ErrInvalidBusinessDay, got %v", err)
+		}
+	})
+
+	t.Run("aggregates by status, method and estimate", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		events := mock_interfaces.NewMockIPaymentStatusEventRepository(ctrl)
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		uc := NewCashClosingUseCase(events, repo)
+		// 01:30 UTC on March 2 is still March 1 in São Paulo.
+		uc.now = func() time.Time { return time.Date(2026, 3, 2, 1, 30, 0, 0, time.UTC) }
+
+		pix := entities.PaymentDetails{Amount: 100, NetReceivedAmount: 99, PaymentMethodID: "pix", PaymentTypeID: "bank_transfer", Fees: []entities.PaymentFee{{Amount: 1}}}
+		visa := entities.PaymentDetails{Amount: 200, PaymentMethodID: "visa", PaymentTypeID: "credit_card", Fees: []entities.PaymentFee{{Amount: 8}, {Amount: 2}}}
+		payments := map[string]entities.BillingPayment{
+			"p1": {ID: "p1", EstimateID: "e1", Status: entities.PaymentStatusAprovado, Details: pix},
+			"p2": {ID: "p2", EstimateID: "e2", Status: entities.PaymentStatusAprovado, Details: visa},
+			"p3": {ID: "p3", EstimateID: "e1", Status: entities.PaymentStatusAprovado, Details: pix},
+			// Approved the day before and refunded today.
+			"p4": {ID: "p4", EstimateID: "e2", Status: entities.PaymentStatusReembolsado, Details: visa},
+		}
+		approved := []entities.PaymentStatusEvent{
+			{ID: "ev1", PaymentID: "p1", Status: entities.PaymentStatusAprovado},
+			{ID: "ev2", PaymentID: "p2", Status: entities.PaymentStatusAprovado},
+			// Repeated report with a new provider detail: no money moves.
+			{ID: "ev3", PaymentID: "p1", Status: entities.PaymentStatusAprovado, PreviousStatus: entities.PaymentStatusAprovado},
+			{ID: "ev4", PaymentID: "p3", Status: entities.PaymentStatusAprovado, PreviousStatus: entities.PaymentStatusPendente},
+			// Won dispute: the payment was counted when it was captured.
+			{ID: "ev5", PaymentID: "p5", Status: entities.PaymentStatusAprovado, PreviousStatus: entities.PaymentStatusContestado},
+		}
+		refunded := []entities.PaymentStatusEvent{
+			{ID: "ev6", PaymentID: "p4", Status: entities.PaymentStatusReembolsado, PreviousStatus: entities.PaymentStatusAprovado},
+		}
+
+		wantFrom := time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)
+		wantLast := time.Date(2026, 3, 2, 3, 0, 0, 0, time.UTC).Add(-time.Nanosecond)
+		events.EXPECT().ListByStatusBetween(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, status entities.PaymentStatus, from, to time.Time) ([]entities.PaymentStatusEvent, error) {
+				if !from.Equal(wantFrom) || !to.Equal(wantLast) {
+					t.Fatalf("unexpected range %v..%v", from, to)
+				}
+				switch status {
+				case entities.PaymentStatusAprovado:
+					return approved, nil
+				case entities.PaymentStatusReembolsado:
+					return refunded, nil
+				}
+				return nil, nil
+			}).Times(3)
+		repo.EXPECT().GetByID(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, id string) (entities.BillingPayment, error) { return payments[id], nil }).Times(4)
+
+		report, err := uc.Report(context.Background(), "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if report.BusinessDay != "2026-03-01" || report.TimeZone != CashClosingTimeZone {
+			t.Fatalf("unexpected day: %+v", report)
+		}
+
+		if len(report.ByStatus) != 3 {
+			t.Fatalf("expected one line per status, got %+v", report.ByStatus)
+		}
+		ap := report.ByStatus[0]
+		if ap.Status != entities.PaymentStatusAprovado || ap.Count != 3 || ap.Gross != 400 || ap.Fees != 12 || math.Abs(ap.Net-388) > 1e-9 {
+			t.Fatalf("unexpected approved totals: %+v", ap)
+		}
+		if report.ByStatus[2].Status != entities.PaymentStatusPendente || report.ByStatus[2].Count != 0 {
+			t.Fatalf("unexpected pending totals: %+v", report.ByStatus[2])
+		}
+
+		if len(report.ByMethod) != 3 {
+			t.Fatalf("unexpected method lines: %+v", report.ByMethod)
+		}
+		if m := report.ByMethod[0]; m.Status != entities.PaymentStatusAprovado || m.PaymentMethodID != "pix" || m.Provider != entities.PaymentProviderMercadoPago || m.Count != 2 {
+			t.Fatalf("unexpected first method line: %+v", m)
+		}
+		if m := report.ByMethod[2]; m.Status != entities.PaymentStatusReembolsado || m.PaymentMethodID != "visa" || m.Net != 190 {
+			t.Fatalf("unexpected refund method line: %+v", m)
+		}
+
+		if len(report.ByEstimate) != 3 {
+			t.Fatalf("unexpected estimate lines: %+v", report.ByEstimate)
+		}
+		if e := report.ByEstimate[0]; e.EstimateID != "e1" || len(e.PaymentIDs) != 2 || e.Gross != 200 {
+			t.Fatalf("unexpected estimate line: %+v", e)
+		}
+		if e := report.ByEstimate[2]; e.EstimateID != "e2" || e.Status != entities.PaymentStatusReembolsado {
+			t.Fatalf("unexpected estimate order: %+v", report.ByEstimate)
+		}
+
+		var buf bytes.Buffer
+		if err := WriteCashClosingCSV(&buf, report); err != nil {
+			t.Fatalf("unexpected csv error: %v", err)
+		}
+		rows, err := csv.NewReader(&buf).ReadAll()
+		if err != nil {
+			t.Fatalf("invalid csv: %v", err)
+		}
+		if len(rows) != 1+3+3+3 || rows[1][1] != "status" || rows[1][8] != "400.00" || rows[4][1] != "method" || rows[7][6] != "e1" {
+			t.Fatalf("unexpected csv rows: %v", rows)
+		}
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		events := mock_interfaces.NewMockIPaymentStatusEventRepository(ctrl)
+		uc := NewCashClosingUseCase(events, mock_interfaces.NewMockIBillingPaymentRepository(ctrl))
+		// 01:30 UTC on March 2 is still March 1 in São Paulo.
+		uc.now = func() time.Time { return time.Date(2026, 3, 2, 1, 30, 0, 0, time.UTC) }
+		events.EXPECT().ListByStatusBetween(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("ddb"))
+		if _, err := uc.Report(context.Background(), "2026-03-01"); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+)
+
+var (
+	ErrCatalogItemNotFound = errors.New("catalog item not found")
+	ErrInvalidCatalogCSV   = errors.New("invalid catalog csv")
+)
+
+// catalogCSVColumns is the required header of an import, in any order; "," or ";" separated.
+var catalogCSVColumns = []string{"kind", "code", "name", "price"}
+
+// CatalogSnapshot is the catalog at a version.
+type CatalogSnapshot struct {
+	Version int64
+	Items   []entities.CatalogItem
+}
+
+// ICatalogUseCase manages the service and parts price catalog estimates are checked
+// against (see EstimateUseCase.WithCatalog).
+//
+// Notes:
+//   - Codes are case-insensitive and stored upper case; a code is unique per kind.
+//   - Import upserts every line of a CSV as a single catalog version; a file with an
+//     invalid line is rejected as a whole.
+type ICatalogUseCase interface {
+	Upsert(ctx context.Context, item entities.CatalogItem, actor string) (entities.CatalogItem, error)
+	Get(ctx context.Context, kind entities.EstimateItemKind, code string) (entities.CatalogItem, error)
+	List(ctx context.Context) (CatalogSnapshot, error)
+	Delete(ctx context.Context, kind entities.EstimateItemKind, code string) (entities.CatalogItem, error)
+	Import(ctx context.Context, csvFile io.Reader, actor string) (CatalogSnapshot, error)
+}
+
+type CatalogUseCase struct {
+	repo interfaces.ICatalogRepository
+	now  func() time.Time
+}
+
+var _ ICatalogUseCase = (*CatalogUseCase)(nil)
+
+func NewCatalogUseCase(repo interfaces.ICatalogRepository) *CatalogUseCase {
+	return &CatalogUseCase{repo: repo, now: time.Now}
+}
+
+func (u *CatalogUseCase) Upsert(ctx context.Context, item entities.CatalogItem, actor string) (entities.CatalogItem, error) {
+	item.Code = entities.NormalizeCatalogCode(item.Code)
+	item.Name = strings.TrimSpace(item.Name)
+	if !item.IsValid() {
+		return entities.CatalogItem{}, entities.ErrInvalidCatalogItem
+	}
+	item.UpdatedBy = actor
+	item.UpdatedAt = u.now().UTC()
+	return u.repo.Put(ctx, item)
+}
+
+func (u *CatalogUseCase) Get(ctx context.Context, kind entities.EstimateItemKind, code string) (entities.CatalogItem, error) {
+	code = entities.NormalizeCatalogCode(code)
+	if code == "" {
+		return entities.CatalogItem{}, entities.ErrInvalidCatalogItem
+	}
+	item, err := u.repo.Get(ctx, kind, code)
+	if err != nil {
+		return entities.CatalogItem{}, err
+	}
+	if item.Code == "" {
+		return entities.CatalogItem{}, ErrCatalogItemNotFound
+	}
+	return item, nil
+}
+
+func (u *CatalogUseCase) List(ctx context.Context) (CatalogSnapshot, error) {
+	version, err := u.repo.CurrentVersion(ctx)
+	if err != nil {
+		return CatalogSnapshot{}, err
+	}
+	items, err := u.repo.List(ctx)
+	if err != nil {
+		return CatalogSnapshot{}, err
+	}
+	return CatalogSnapshot{Version: version, Items: items}, nil
+}
+
+func (u *CatalogUseCase) Delete(ctx context.Context, kind entities.EstimateItemKind, code string) (entities.CatalogItem, error) {
+	code = entities.NormalizeCatalogCode(code)
+	if code == "" {
+		return entities.CatalogItem{}, entities.ErrInvalidCatalogItem
+	}
+	item, err := u.repo.Delete(ctx, kind, code)
+	if err != nil {
+		return entities.CatalogItem{}, err
+	}
+	if item.Code == "" {
+		return entities.CatalogItem{}, ErrCatalogItemNotFound
+	}
+	return item, nil
+}
+
+// Import upserts the items of a CSV (kind, code, name, price) and returns the new version
+// with the imported items.
+func (u *CatalogUseCase) Import(ctx context.Context, csvFile io.Reader, actor string) (CatalogSnapshot, error) {
+	items, err := parseCatalogCSV(csvFile)
+	if err != nil {
+		return CatalogSnapshot{}, err
+	}
+	now := u.now().UTC()
+	for i := range items {
+		items[i].UpdatedBy = actor
+		items[i].UpdatedAt = now
+	}
+	version, err := u.repo.PutMany(ctx, items)
+	if err != nil {
+		return CatalogSnapshot{}, err
+	}
+	for i := range items {
+		items[i].Version = version
+	}
+	return CatalogSnapshot{Version: version, Items: items}, nil
+}
+
+func parseCatalogCSV(r io.Reader) ([]entities.CatalogItem, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(br.Size())
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, err
+	}
+	cr := csv.NewReader(br)
+	if line, _, _ := strings.Cut(string(header), "\n"); strings.Count(line, ";") > strings.Count(line, ",") {
+		cr.Comma = ';'
+	}
+	cr.TrimLeadingSpace = true
+
+	columns, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrInvalidCatalogCSV, err)
+	}
+	index := map[string]int{}
+	for i, name := range columns {
+		index[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range catalogCSVColumns {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("%w: missing column %s", ErrInvalidCatalogCSV, name)
+		}
+	}
+
+	var items []entities.CatalogItem
+	seen := map[string]int{}
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidCatalogCSV, line, err)
+		}
+		price, err := parseCatalogPrice(record[index["price"]])
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: price", ErrInvalidCatalogCSV, line)
+		}
+		item := entities.CatalogItem{
+			Kind:  entities.EstimateItemKind(strings.ToLower(strings.TrimSpace(record[index["kind"]]))),
+			Code:  entities.NormalizeCatalogCode(record[index["code"]]),
+			Name:  strings.TrimSpace(record[index["name"]]),
+			Price: price,
+		}
+		if !item.IsValid() {
+			return nil, fmt.Errorf("%w: line %d", ErrInvalidCatalogCSV, line)
+		}
+		key := string(item.Kind) + "#" + item.Code
+		if first, ok := seen[key]; ok {
+			return nil, fmt.Errorf("%w: line %d repeats line %d", ErrInvalidCatalogCSV, line, first)
+		}
+		seen[key] = line
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: no items", ErrInvalidCatalogCSV)
+	}
+	return items, nil
+}
+
+// parseCatalogPrice accepts "1234.50" and the Brazilian "1234,50".
+func parseCatalogPrice(v string) (float64, error) {
+	v = strings.TrimSpace(v)
+	if !strings.Contains(v, ".") {
+		v = strings.Replace(v, ",", ".", 1)
+	}
+	return strconv.ParseFloat(v, 64)
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	mock_interfaces "mecanica_xpto/internal/usecase/interfaces/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestCatalogUseCase_Upsert(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mock_interfaces.NewMockICatalogRepository(ctrl)
+	uc := NewCatalogUseCase(repo)
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	uc.now = func() time.Time { return now }
+
+	invalid := []entities.CatalogItem{
+		{Kind: entities.EstimateItemServico, Code: " ", Name: "Troca de óleo", Price: 80},
+		{Kind: "outro", Code: "SRV-1", Name: "Troca de óleo", Price: 80},
+		{Kind: entities.EstimateItemPeca, Code: "FIL-1", Name: " ", Price: 30},
+		{Kind: entities.EstimateItemPeca, Code: "FIL-1", Name: "Filtro", Price: 0},
+	}
+	for _, item := range invalid {
+		if _, err := uc.Upsert(context.Background(), item, "ops"); !errors.Is(err, entities.ErrInvalidCatalogItem) {
+			t.Fatalf("expected ErrInvalidCatalogItem for %+v, got %v", item, err)
+		}
+	}
+
+	repo.EXPECT().Put(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, item entities.CatalogItem) (entities.CatalogItem, error) {
+		item.Version = 4
+		return item, nil
+	})
+	item, err := uc.Upsert(context.Background(), entities.CatalogItem{Kind: entities.EstimateItemPeca, Code: " fil-1 ", Name: " Filtro de óleo ", Price: 32.5}, "ops")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if item.Code != "FIL-1" || item.Name != "Filtro de óleo" || item.Version != 4 || item.UpdatedBy != "ops" || !item.UpdatedAt.Equal(now) {
+		t.Fatalf("unexpected item: %+v", item)
+	}
+}
+
+func TestCatalogUseCase_GetListDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mock_interfaces.NewMockICatalogRepository(ctrl)
+	uc := NewCatalogUseCase(repo)
+
+	repo.EXPECT().Get(gomock.Any(), entities.EstimateItemPeca, "NOPE").Return(entities.CatalogItem{}, nil)
+	if _, err := uc.Get(context.Background(), entities.EstimateItemPeca, "nope"); !errors.Is(err, ErrCatalogItemNotFound) {
+		t.Fatalf("expected ErrCatalogItemNotFound, got %v", err)
+	}
+	repo.EXPECT().Delete(gomock.Any(), entities.EstimateItemPeca, "NOPE").Return(entities.CatalogItem{}, nil)
+	if _, err := uc.Delete(context.Background(), entities.EstimateItemPeca, "nope"); !errors.Is(err, ErrCatalogItemNotFound) {
+		t.Fatalf("expected ErrCatalogItemNotFound, got %v", err)
+	}
+	if _, err := uc.Delete(context.Background(), entities.EstimateItemPeca, " "); !errors.Is(err, entities.ErrInvalidCatalogItem) {
+		t.Fatalf("expected ErrInvalidCatalogItem, got %v", err)
+	}
+
+	items := []entities.CatalogItem{{Kind: entities.EstimateItemPeca, Code: "FIL-1", Name: "Filtro", Price: 30, Version: 2}}
+	repo.EXPECT().CurrentVersion(gomock.Any()).Return(int64(3), nil)
+	repo.EXPECT().List(gomock.Any()).Return(items, nil)
+	snapshot, err := uc.List(context.Background())
+	if err != nil || snapshot.Version != 3 || len(snapshot.Items) != 1 {
+		t.Fatalf("unexpected snapshot: %+v err=%v", snapshot, err)
+	}
+}
+
+func TestCatalogUseCase_Import(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mock_interfaces.NewMockICatalogRepository(ctrl)
+	uc := NewCatalogUseCase(repo)
+
+	var saved []entities.CatalogItem
+	repo.EXPECT().PutMany(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, items []entities.CatalogItem) (int64, error) {
+		saved = items
+		return 7, nil
+	})
+	csvFile := "\ufeffCode;Kind;Name;Price\nsrv-1;servico;Troca de óleo;80\nfil-1;peca;\"Filtro; óleo\";32,50\n"
+	snapshot, err := uc.Import(context.Background(), strings.NewReader(csvFile), "ops")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if snapshot.Version != 7 || len(saved) != 2 {
+		t.Fatalf("unexpected snapshot: %+v", snapshot)
+	}
+	part := snapshot.Items[1]
+	if part.Kind != entities.EstimateItemPeca || part.Code != "FIL-1" || part.Name != "Filtro; óleo" || part.Price != 32.5 || part.Version != 7 || part.UpdatedBy != "ops" {
+		t.Fatalf("unexpected item: %+v", part)
+	}
+
+	invalid := map[string]string{
+		"missing column": "kind,code,name\nservico,SRV-1,Troca\n",
+		"bad price":      "kind,code,name,price\nservico,SRV-1,Troca,abc\n",
+		"invalid kind":   "kind,code,name,price\noutro,SRV-1,Troca,80\n",
+		"duplicate":      "kind,code,name,price\nservico,SRV-1,Troca,80\nservico,srv-1,Troca,90\n",
+		"no items":       "kind,code,name,price\n",
+		"empty":          "",
+	}
+	for name, content := range invalid {
+		if _, err := uc.Import(context.Background(), strings.NewReader(content), "ops"); !errors.Is(err, ErrInvalidCatalogCSV) {
+			t.Fatalf("%s: expected ErrInvalidCatalogCSV, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+)
+
+var (
+	ErrInvalidAnalyticsRange       = errors.New("invalid analytics date range")
+	ErrInvalidAnalyticsGranularity = errors.New("invalid analytics granularity")
+)
+
+// maxAnalyticsRangeDays bounds a report to roughly one year of estimates.
+const maxAnalyticsRangeDays = 366
+
+// IConversionAnalyticsUseCase computes the estimate conversion metrics.
+type IConversionAnalyticsUseCase interface {
+	Report(ctx context.Context, filter ConversionFilter) (entities.ConversionReport, error)
+	RejectionReasons(ctx context.Context, filter ConversionFilter) (entities.RejectionReasonReport, error)
+}
+
+// ConversionFilter selects the estimates created between From and To (YYYY-MM-DD business
+// days, both inclusive) and how they are grouped. Granularity defaults to day.
+type ConversionFilter struct {
+	From        string
+	To          string
+	Granularity entities.AnalyticsGranularity
+}
+
+// ConversionRebuildReport summarizes a rebuild of the analytics read model.
+type ConversionRebuildReport struct {
+	Projected int `json:"projected"`
+	Paid      int `json:"paid"`
+	Failed    int `json:"failed"`
+}
+
+type ConversionAnalyticsUseCase struct {
+	conversions  interfaces.IEstimateConversionRepository
+	estimateRepo interfaces.IEstimateRepository
+	paymentRepo  interfaces.IBillingPaymentRepository
+	location     *time.Location
+	now          func() time.Time
+}
+
+var _ IConversionAnalyticsUseCase = (*ConversionAnalyticsUseCase)(nil)
+
+func NewConversionAnalyticsUseCase(conversions interfaces.IEstimateConversionRepository, estimateRepo interfaces.IEstimateRepository, paymentRepo interfaces.IBillingPaymentRepository) *ConversionAnalyticsUseCase {
+	return &ConversionAnalyticsUseCase{
+		conversions:  conversions,
+		estimateRepo: estimateRepo,
+		paymentRepo:  paymentRepo,
+		location:     businessDayLocation(),
+		now:          time.Now,
+	}
+}
+
+// conversionTotals accumulates the sums behind the averages of ConversionMetrics.
+type conversionTotals struct {
+	metrics         entities.ConversionMetrics
+	approvedPrice   float64
+	approvalHours   float64
+	approvalSamples int
+	paymentHours    float64
+	paymentSamples  int
+}
+
+func (t *conversionTotals) add(c entities.EstimateConversion) {
+	t.metrics.Estimates++
+	switch c.Status {
+	case entities.EstimateStatusRejeitado:
+		t.metrics.Rejected++
+	case entities.EstimateStatusCancelado:
+		t.metrics.Canceled++
+	case entities.EstimateStatusExpirado:
+		t.metrics.Expired++
+	}
+	if c.ApprovedAt != nil {
+		t.metrics.Approved++
+		t.approvedPrice += c.Price
+		t.approvalHours += c.ApprovedAt.Sub(c.CreatedAt).Hours()
+		t.approvalSamples++
+	}
+	if c.FirstPaidAt != nil {
+		t.metrics.Paid++
+		if c.ApprovedAt != nil {
+			t.paymentHours += c.FirstPaidAt.Sub(*c.ApprovedAt).Hours()
+			t.paymentSamples++
+		}
+	}
+}
+
+func (t conversionTotals) result() entities.ConversionMetrics {
+	m := t.metrics
+	if m.Estimates > 0 {
+		m.ApprovalRate = float64(m.Approved) / float64(m.Estimates)
+	}
+	if m.Approved > 0 {
+		m.AverageTicket = t.approvedPrice / float64(m.Approved)
+	}
+	if t.approvalSamples > 0 {
+		m.AvgHoursToApproval = t.approvalHours / float64(t.approvalSamples)
+	}
+	if t.paymentSamples > 0 {
+		m.AvgHoursApprovalToPayment = t.paymentHours / float64(t.paymentSamples)
+	}
+	return m
+}
+
+// Report reads the estimates created in the range from the analytics read model and
+// groups them by the period of their creation. Every period of the range is listed, even
+// when empty.
+func (u *ConversionAnalyticsUseCase) Report(ctx context.Context, filter ConversionFilter) (entities.ConversionReport, error) {
+	granularity := entities.AnalyticsGranularity(strings.ToLower(strings.TrimSpace(string(filter.Granularity))))
+	if granularity == "" {
+		granularity = entities.AnalyticsGranularityDay
+	}
+	if !granularity.IsValid() {
+		return entities.ConversionReport{}, ErrInvalidAnalyticsGranularity
+	}
+	from, to, items, err := u.listCreated(ctx, filter)
+	if err != nil {
+		return entities.ConversionReport{}, err
+	}
+	end := to.AddDate(0, 0, 1)
+
+	var starts []time.Time
+	periods := map[time.Time]*conversionTotals{}
+	for start := granularity.PeriodStart(from); start.Before(end); start = granularity.Next(start) {
+		starts = append(starts, start)
+		periods[start] = &conversionTotals{metrics: entities.ConversionMetrics{PeriodStart: start}}
+	}
+	total := conversionTotals{metrics: entities.ConversionMetrics{PeriodStart: starts[0]}}
+	for _, c := range items {
+		p := periods[granularity.PeriodStart(c.CreatedAt.In(u.location))]
+		if p == nil {
+			continue
+		}
+		p.add(c)
+		total.add(c)
+	}
+
+	report := entities.ConversionReport{
+		From:        from.Format(time.DateOnly),
+		To:          to.Format(time.DateOnly),
+		TimeZone:    CashClosingTimeZone,
+		Granularity: granularity,
+		Periods:     make([]entities.ConversionMetrics, 0, len(starts)),
+		Total:       total.result(),
+		GeneratedAt: u.now().UTC(),
+	}
+	for _, start := range starts {
+		report.Periods = append(report.Periods, periods[start].result())
+	}
+	return report, nil
+}
+
+// RejectionReasons groups the rejected and canceled estimates created in the range by
+// status and reason, most frequent first.
+func (u *ConversionAnalyticsUseCase) RejectionReasons(ctx context.Context, filter ConversionFilter) (entities.RejectionReasonReport, error) {
+	from, to, items, err := u.listCreated(ctx, filter)
+	if err != nil {
+		return entities.RejectionReasonReport{}, err
+	}
+
+	report := entities.RejectionReasonReport{
+		From:        from.Format(time.DateOnly),
+		To:          to.Format(time.DateOnly),
+		TimeZone:    CashClosingTimeZone,
+		Reasons:     []entities.ReasonMetrics{},
+		GeneratedAt: u.now().UTC(),
+	}
+	index := map[[2]string]int{}
+	for _, c := range items {
+		report.Estimates++
+		switch c.Status {
+		case entities.EstimateStatusRejeitado:
+			report.Rejected++
+		case entities.EstimateStatusCancelado:
+			report.Canceled++
+		default:
+			continue
+		}
+		key := [2]string{string(c.Status), c.ReasonCode}
+		i, ok := index[key]
+		if !ok {
+			i = len(report.Reasons)
+			index[key] = i
+			report.Reasons = append(report.Reasons, entities.ReasonMetrics{Status: c.Status, Code: c.ReasonCode, Label: c.ReasonLabel})
+		}
+		report.Reasons[i].Count++
+		report.Reasons[i].Value += c.Price
+	}
+	for i, r := range report.Reasons {
+		decided := report.Rejected
+		if r.Status == entities.EstimateStatusCancelado {
+			decided = report.Canceled
+		}
+		report.Reasons[i].Share = float64(r.Count) / float64(decided)
+	}
+	sort.SliceStable(report.Reasons, func(i, j int) bool {
+		a, b := report.Reasons[i], report.Reasons[j]
+		if a.Status != b.Status {
+			return a.Status == entities.EstimateStatusRejeitado
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Code < b.Code
+	})
+	return report, nil
+}
+
+// listCreated validates the range of filter and reads the estimates created in it.
+func (u *ConversionAnalyticsUseCase) listCreated(ctx context.Context, filter ConversionFilter) (time.Time, time.Time, []entities.EstimateConversion, error) {
+	from, err := time.ParseInLocation(time.DateOnly, strings.TrimSpace(filter.From), u.location)
+	if err != nil {
+		return time.Time{}, time.Time{}, nil, ErrInvalidAnalyticsRange
+	}
+	to, err := time.ParseInLocation(time.DateOnly, strings.TrimSpace(filter.To), u.location)
+	if err != nil || to.Before(from) || to.Sub(from) > maxAnalyticsRangeDays*24*time.Hour {
+		return time.Time{}, time.Time{}, nil, ErrInvalidAnalyticsRange
+	}
+
+	items, err := u.conversions.ListCreatedBetween(ctx, from, to.AddDate(0, 0, 1).Add(-time.Nanosecond))
+	if err != nil {
+		log.Printf("[payment][analytics] read model query failed from=%s to=%s err=%v", filter.From, filter.To, err)
+		return time.Time{}, time.Time{}, nil, err
+	}
+	return from, to, items, nil
+}
+
+// Rebuild projects every estimate into the analytics read model, with the first approved
+// payment of each one. It is safe to run while the API is serving traffic.
+func (u *ConversionAnalyticsUseCase) Rebuild(ctx context.Context) (ConversionRebuildReport, error) {
+	var report ConversionRebuildReport
+	statuses := []entities.EstimateStatus{
+		entities.EstimateStatusPendente,
+		entities.EstimateStatusAguardandoAprovacao,
+		entities.EstimateStatusAprovado,
+		entities.EstimateStatusRejeitado,
+		entities.EstimateStatusCancelado,
+		entities.EstimateStatusExpirado,
+	}
+	for _, status := range statuses {
+		estimates, err := u.estimateRepo.ListByStatus(ctx, status)
+		if err != nil {
+			return report, err
+		}
+		for _, e := range estimates {
+			if err := u.conversions.Upsert(ctx, entities.NewEstimateConversion(e)); err != nil {
+				log.Printf("[payment][analytics] rebuild projection failed estimate_id=%s err=%v", e.ID, err)
+				report.Failed++
+				continue
+			}
+			report.Projected++
+
+			paidAt, err := u.firstPaidAt(ctx, e.ID)
+			if err != nil {
+				log.Printf("[payment][analytics] rebuild payments lookup failed estimate_id=%s err=%v", e.ID, err)
+				report.Failed++
+				continue
+			}
+			if paidAt == nil {
+				continue
+			}
+			if err := u.conversions.MarkPaid(ctx, e.ID, *paidAt); err != nil {
+				log.Printf("[payment][analytics] rebuild mark paid failed estimate_id=%s err=%v", e.ID, err)
+				report.Failed++
+				continue
+			}
+			report.Paid++
+		}
+	}
+	return report, nil
+}
+
+// firstPaidAt returns the date of the oldest payment of the estimate that was approved at
+// some point (refunded and charged back payments included).
+func (u *ConversionAnalyticsUseCase) firstPaidAt(ctx context.Context, estimateID string) (*time.Time, error) {
+	page := entities.PageRequest{Limit: maxPaymentPageLimit}
+	for {
+		res, err := u.paymentRepo.ListByEstimateID(ctx, estimateID, page)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range res.Items {
+			if p.Status != entities.PaymentStatusPendente && p.Status != entities.PaymentStatusNegado {
+				paidAt := p.Date
+				return &paidAt, nil
+			}
+		}
+		if res.NextCursor == "" {
+			return nil, nil
+		}
+		page.Cursor = res.NextCursor
+	}
+}
@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	mock_interfaces "mecanica_xpto/internal/usecase/interfaces/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestConversionAnalyticsUseCase_Report(t *testing.T) {
+	t.Run("invalid input", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		conversions := mock_interfaces.NewMockIEstimateConversionRepository(ctrl)
+		estimateRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		paymentRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		uc := NewConversionAnalyticsUseCase(conversions, estimateRepo, paymentRepo)
+		uc.location = time.FixedZone(CashClosingTimeZone, -3*60*60)
+		uc.now = func() time.Time { return time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC) }
+		cases := []struct {
+			filter ConversionFilter
+			want   error
+		}{
+			{ConversionFilter{From: "2026-03-01", To: "2026-03-31", Granularity: "year"}, ErrInvalidAnalyticsGranularity},
+			{ConversionFilter{From: "01/03/2026", To: "2026-03-31"}, ErrInvalidAnalyticsRange},
+			{ConversionFilter{From: "2026-03-31", To: "2026-03-01"}, ErrInvalidAnalyticsRange},
+			{ConversionFilter{From: "2024-01-01", To: "2026-03-01"}, ErrInvalidAnalyticsRange},
+		}
+		for _, tc := range cases {
+			if _, err := uc.Report(context.Background(), tc.filter); !errors.Is(err, tc.want) {
+				t.Fatalf("%+v: expected %v, got %v", tc.filter, tc.want, err)
+			}
+		}
+	})
+
+	t.Run("groups by week", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		conversions := mock_interfaces.NewMockIEstimateConversionRepository(ctrl)
+		estimateRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		paymentRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		uc := NewConversionAnalyticsUseCase(conversions, estimateRepo, paymentRepo)
+		uc.location = time.FixedZone(CashClosingTimeZone, -3*60*60)
+		uc.now = func() time.Time { return time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC) }
+
+		at := func(day, hour int) *time.Time {
+			v := time.Date(2026, 3, day, hour, 0, 0, 0, time.UTC)
+			return &v
+		}
+		items := []entities.EstimateConversion{
+			// week of Mon 2026-03-02
+			{EstimateID: "e1", Price: 100, Status: entities.EstimateStatusAprovado, CreatedAt: *at(2, 12), ApprovedAt: at(3, 12), FirstPaidAt: at(3, 14)},
+			{EstimateID: "e2", Price: 300, Status: entities.EstimateStatusAprovado, CreatedAt: *at(4, 12), ApprovedAt: at(4, 14)},
+			{EstimateID: "e3", Price: 50, Status: entities.EstimateStatusRejeitado, CreatedAt: *at(5, 12)},
+			// 2026-03-09 01:00 UTC is still Sunday 2026-03-08 in São Paulo.
+			{EstimateID: "e4", Price: 80, Status: entities.EstimateStatusPendente, CreatedAt: *at(9, 1)},
+			// week of Mon 2026-03-09
+			{EstimateID: "e5", Price: 200, Status: entities.EstimateStatusCancelado, CreatedAt: *at(10, 12)},
+		}
+		wantFrom := time.Date(2026, 3, 2, 3, 0, 0, 0, time.UTC)
+		wantTo := time.Date(2026, 3, 13, 3, 0, 0, 0, time.UTC).Add(-time.Nanosecond)
+		conversions.EXPECT().ListCreatedBetween(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, from, to time.Time) ([]entities.EstimateConversion, error) {
+				if !from.Equal(wantFrom) || !to.Equal(wantTo) {
+					t.Fatalf("unexpected range %v..%v", from, to)
+				}
+				return items, nil
+			})
+
+		report, err := uc.Report(context.Background(), ConversionFilter{From: "2026-03-02", To: "2026-03-12", Granularity: "WEEK"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if report.Granularity != entities.AnalyticsGranularityWeek || len(report.Periods) != 2 {
+			t.Fatalf("unexpected periods: %+v", report)
+		}
+		w1 := report.Periods[0]
+		if w1.PeriodStart.Format(time.DateOnly) != "2026-03-02" || w1.Estimates != 4 || w1.Approved != 2 || w1.Rejected != 1 || w1.Paid != 1 {
+			t.Fatalf("unexpected first week: %+v", w1)
+		}
+		if w1.ApprovalRate != 0.5 || w1.AverageTicket != 200 || w1.AvgHoursToApproval != 13 || w1.AvgHoursApprovalToPayment != 2 {
+			t.Fatalf("unexpected first week metrics: %+v", w1)
+		}
+		if w2 := report.Periods[1]; w2.Estimates != 1 || w2.Canceled != 1 || w2.ApprovalRate != 0 || w2.AverageTicket != 0 {
+			t.Fatalf("unexpected second week: %+v", w2)
+		}
+		if report.Total.Estimates != 5 || math.Abs(report.Total.ApprovalRate-0.4) > 1e-9 {
+			t.Fatalf("unexpected total: %+v", report.Total)
+		}
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		conversions := mock_interfaces.NewMockIEstimateConversionRepository(ctrl)
+		estimateRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		paymentRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		uc := NewConversionAnalyticsUseCase(conversions, estimateRepo, paymentRepo)
+		uc.location = time.FixedZone(CashClosingTimeZone, -3*60*60)
+		uc.now = func() time.Time { return time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC) }
+		conversions.EXPECT().ListCreatedBetween(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("ddb"))
+		if _, err := uc.Report(context.Background(), ConversionFilter{From: "2026-03-01", To: "2026-03-01"}); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
+
+func TestConversionAnalyticsUseCase_RejectionReasons(t *testing.T) {
+	t.Run("groups by reason", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		conversions := mock_interfaces.NewMockIEstimateConversionRepository(ctrl)
+		estimateRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		paymentRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		uc := NewConversionAnalyticsUseCase(conversions, estimateRepo, paymentRepo)
+		uc.location = time.FixedZone(CashClosingTimeZone, -3*60*60)
+		uc.now = func() time.Time { return time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC) }
+		created := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+		conversions.EXPECT().ListCreatedBetween(gomock.Any(), gomock.Any(), gomock.Any()).Return([]entities.EstimateConversion{
+			{EstimateID: "e1", Price: 100, Status: entities.EstimateStatusRejeitado, ReasonCode: "preco", ReasonLabel: "Preço", CreatedAt: created},
+			{EstimateID: "e2", Price: 300, Status: entities.EstimateStatusRejeitado, ReasonCode: "preco", ReasonLabel: "Preço", CreatedAt: created},
+			{EstimateID: "e3", Price: 50, Status: entities.EstimateStatusRejeitado, ReasonCode: "prazo", ReasonLabel: "Prazo", CreatedAt: created},
+			// Decided before reasons existed.
+			{EstimateID: "e4", Price: 70, Status: entities.EstimateStatusRejeitado, CreatedAt: created},
+			{EstimateID: "e5", Price: 200, Status: entities.EstimateStatusCancelado, ReasonCode: "duplicado", ReasonLabel: "Duplicado", CreatedAt: created},
+			{EstimateID: "e6", Price: 80, Status: entities.EstimateStatusAprovado, CreatedAt: created},
+		}, nil)
+
+		report, err := uc.RejectionReasons(context.Background(), ConversionFilter{From: "2026-03-01", To: "2026-03-31"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if report.Estimates != 6 || report.Rejected != 4 || report.Canceled != 1 || len(report.Reasons) != 4 {
+			t.Fatalf("unexpected report: %+v", report)
+		}
+		top := report.Reasons[0]
+		if top.Code != "preco" || top.Count != 2 || top.Share != 0.5 || top.Value != 400 {
+			t.Fatalf("unexpected top reason: %+v", top)
+		}
+		if report.Reasons[1].Code != "" || report.Reasons[2].Code != "prazo" {
+			t.Fatalf("unexpected order: %+v", report.Reasons)
+		}
+		if last := report.Reasons[3]; last.Status != entities.EstimateStatusCancelado || last.Share != 1 {
+			t.Fatalf("unexpected cancellation reason: %+v", last)
+		}
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		conversions := mock_interfaces.NewMockIEstimateConversionRepository(ctrl)
+		estimateRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		paymentRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		uc := NewConversionAnalyticsUseCase(conversions, estimateRepo, paymentRepo)
+		uc.location = time.FixedZone(CashClosingTimeZone, -3*60*60)
+		uc.now = func() time.Time { return time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC) }
+		if _, err := uc.RejectionReasons(context.Background(), ConversionFilter{From: "2026-03-31", To: "2026-03-01"}); !errors.Is(err, ErrInvalidAnalyticsRange) {
+			t.Fatalf("expected ErrInvalidAnalyticsRange, got %v", err)
+		}
+	})
+}
+
+func TestConversionAnalyticsUseCase_Rebuild(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	conversions := mock_interfaces.NewMockIEstimateConversionRepository(ctrl)
+	estimateRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+	paymentRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+	uc := NewConversionAnalyticsUseCase(conversions, estimateRepo, paymentRepo)
+	uc.location = time.FixedZone(CashClosingTimeZone, -3*60*60)
+	uc.now = func() time.Time { return time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC) }
+
+	approvedAt := time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC)
+	paidAt := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	estimateRepo.EXPECT().ListByStatus(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, status entities.EstimateStatus) ([]entities.Estimate, error) {
+			switch status {
+			case entities.EstimateStatusAprovado:
+				return []entities.Estimate{{ID: "e1", Status: status, ApprovedAt: &approvedAt}}, nil
+			case entities.EstimateStatusPendente:
+				return []entities.Estimate{{ID: "e2", Status: status}}, nil
+			}
+			return nil, nil
+		}).Times(6)
+
+	conversions.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, c entities.EstimateConversion) error {
+			if c.EstimateID == "e1" && (c.ApprovedAt == nil || !c.ApprovedAt.Equal(approvedAt) || c.DecidedAt == nil) {
+				t.Fatalf("unexpected projection: %+v", c)
+			}
+			return nil
+		}).Times(2)
+	paymentRepo.EXPECT().ListByEstimateID(gomock.Any(), "e1", gomock.Any()).Return(entities.Page[entities.BillingPayment]{Items: []entities.BillingPayment{
+		{ID: "p0", Status: entities.PaymentStatusNegado, Date: approvedAt},
+		{ID: "p1", Status: entities.PaymentStatusReembolsado, Date: paidAt},
+	}}, nil)
+	paymentRepo.EXPECT().ListByEstimateID(gomock.Any(), "e2", gomock.Any()).Return(entities.Page[entities.BillingPayment]{}, nil)
+	conversions.EXPECT().MarkPaid(gomock.Any(), "e1", paidAt).Return(nil)
+
+	report, err := uc.Rebuild(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report != (ConversionRebuildReport{Projected: 2, Paid: 1}) {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+)
+
+var ErrInvalidCoupon = errors.New("invalid coupon")
+
+// ICouponUseCase manages the discount coupons redeemed by estimates (see
+// EstimateUseCase.WithCoupons).
+//
+// Notes:
+//   - Codes are case-insensitive and stored upper case.
+//   - Coupons are not deleted, only deactivated, so the estimates using them keep a valid
+//     reference.
+type ICouponUseCase interface {
+	Create(ctx context.Context, c entities.Coupon, actor string) (entities.Coupon, error)
+	GetByCode(ctx context.Context, code string) (entities.Coupon, error)
+	Deactivate(ctx context.Context, code string) (entities.Coupon, error)
+}
+
+type CouponUseCase struct {
+	repo interfaces.ICouponRepository
+	now  func() time.Time
+}
+
+var _ ICouponUseCase = (*CouponUseCase)(nil)
+
+func NewCouponUseCase(repo interfaces.ICouponRepository) *CouponUseCase {
+	return &CouponUseCase{repo: repo, now: time.Now}
+}
+
+// Create registers an active coupon; an existing code is rejected with
+// entities.ErrCouponAlreadyExists.
+func (u *CouponUseCase) Create(ctx context.Context, c entities.Coupon, actor string) (entities.Coupon, error) {
+	c.Code = entities.NormalizeCouponCode(c.Code)
+	c.Description = strings.TrimSpace(c.Description)
+	if !c.IsValid() {
+		return entities.Coupon{}, ErrInvalidCoupon
+	}
+	c.Redemptions = 0
+	c.Active = true
+	c.CreatedBy = actor
+	c.CreatedAt = u.now().UTC()
+	if err := u.repo.Create(ctx, c); err != nil {
+		return entities.Coupon{}, err
+	}
+	return c, nil
+}
+
+func (u *CouponUseCase) GetByCode(ctx context.Context, code string) (entities.Coupon, error) {
+	code = entities.NormalizeCouponCode(code)
+	if code == "" {
+		return entities.Coupon{}, ErrInvalidCoupon
+	}
+	c, err := u.repo.GetByCode(ctx, code)
+	if err != nil {
+		return entities.Coupon{}, err
+	}
+	if c.Code == "" {
+		return entities.Coupon{}, ErrCouponNotFound
+	}
+	return c, nil
+}
+
+// Deactivate stops new redemptions of a coupon; estimates already discounted keep it.
+func (u *CouponUseCase) Deactivate(ctx context.Context, code string) (entities.Coupon, error) {
+	code = entities.NormalizeCouponCode(code)
+	if code == "" {
+		return entities.Coupon{}, ErrInvalidCoupon
+	}
+	c, err := u.repo.SetActive(ctx, code, false)
+	if err != nil {
+		return entities.Coupon{}, err
+	}
+	if c.Code == "" {
+		return entities.Coupon{}, ErrCouponNotFound
+	}
+	return c, nil
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	mock_interfaces "mecanica_xpto/internal/usecase/interfaces/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestCouponUseCase_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mock_interfaces.NewMockICouponRepository(ctrl)
+	uc := NewCouponUseCase(repo)
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	uc.now = func() time.Time { return now }
+	rule := entities.DiscountRule{Type: entities.DiscountPercentual, Value: 15, Scope: entities.DiscountScopeOrcamento}
+
+	before := now.AddDate(0, -1, 0)
+	invalid := []entities.Coupon{
+		{Code: " ", Rule: rule},
+		{Code: "X", Rule: entities.DiscountRule{Type: entities.DiscountPercentual, Value: 120, Scope: entities.DiscountScopeOrcamento}},
+		{Code: "X", Rule: entities.DiscountRule{Type: entities.DiscountFixo, Value: 10, Scope: entities.DiscountScopeOrcamento, ItemKind: entities.EstimateItemPeca}},
+		{Code: "X", Rule: rule, ValidFrom: &now, ExpiresAt: &before},
+		{Code: "X", Rule: rule, MaxPerCustomer: -1},
+	}
+	for _, c := range invalid {
+		if _, err := uc.Create(context.Background(), c, "ops"); !errors.Is(err, ErrInvalidCoupon) {
+			t.Fatalf("expected ErrInvalidCoupon for %+v, got %v", c, err)
+		}
+	}
+
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(entities.ErrCouponAlreadyExists)
+	if _, err := uc.Create(context.Background(), entities.Coupon{Code: "promo", Rule: rule}, "ops"); !errors.Is(err, entities.ErrCouponAlreadyExists) {
+		t.Fatalf("expected ErrCouponAlreadyExists, got %v", err)
+	}
+
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	c, err := uc.Create(context.Background(), entities.Coupon{Code: " promo15 ", Rule: rule, Redemptions: 7, MaxRedemptions: 100}, "ops")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Code != "PROMO15" || !c.Active || c.Redemptions != 0 || c.CreatedBy != "ops" || !c.CreatedAt.Equal(now) {
+		t.Fatalf("unexpected coupon: %+v", c)
+	}
+}
+
+func TestCouponUseCase_GetAndDeactivate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mock_interfaces.NewMockICouponRepository(ctrl)
+	uc := NewCouponUseCase(repo)
+
+	if _, err := uc.GetByCode(context.Background(), " "); !errors.Is(err, ErrInvalidCoupon) {
+		t.Fatalf("expected ErrInvalidCoupon, got %v", err)
+	}
+	repo.EXPECT().GetByCode(gomock.Any(), "NOPE").Return(entities.Coupon{}, nil)
+	if _, err := uc.GetByCode(context.Background(), "nope"); !errors.Is(err, ErrCouponNotFound) {
+		t.Fatalf("expected ErrCouponNotFound, got %v", err)
+	}
+	repo.EXPECT().GetByCode(gomock.Any(), "PROMO").Return(entities.Coupon{Code: "PROMO", Active: true}, nil)
+	if c, err := uc.GetByCode(context.Background(), "promo"); err != nil || c.Code != "PROMO" {
+		t.Fatalf("unexpected result: %+v err=%v", c, err)
+	}
+
+	repo.EXPECT().SetActive(gomock.Any(), "NOPE", false).Return(entities.Coupon{}, nil)
+	if _, err := uc.Deactivate(context.Background(), "nope"); !errors.Is(err, ErrCouponNotFound) {
+		t.Fatalf("expected ErrCouponNotFound, got %v", err)
+	}
+	repo.EXPECT().SetActive(gomock.Any(), "PROMO", false).Return(entities.Coupon{Code: "PROMO"}, nil)
+	if c, err := uc.Deactivate(context.Background(), "promo"); err != nil || c.Active {
+		t.Fatalf("unexpected result: %+v err=%v", c, err)
+	}
+}
@@ -0,0 +1,353 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidDataSubject = errors.New("payer email or document is required")
+
+// maxAnonymizeAttempts bounds the re-reads of a record changed during an anonymization.
+const maxAnonymizeAttempts = 3
+
+// DataSubject identifies a payer or customer by email, CPF/CNPJ and/or phone (LGPD
+// "titular"). The phone only matches approval links sent by SMS.
+type DataSubject struct {
+	Email    string
+	Document string
+	Phone    string
+}
+
+// DataSubjectExport is everything the service holds about a data subject.
+type DataSubjectExport struct {
+	Payments      []entities.BillingPayment
+	Estimates     []entities.Estimate
+	NFSeDocuments []entities.NFSeDocument
+	ApprovalLinks []entities.ApprovalLink
+	GeneratedAt   time.Time
+}
+
+// DataSubjectAnonymization summarizes an anonymization request. RetainedNFSeIDs are the
+// NFS-e documents issued to the data subject, kept as issued for the fiscal retention
+// period.
+type DataSubjectAnonymization struct {
+	PaymentIDs        []string
+	ApprovalLinkIDs   []string
+	RetainedNFSeIDs   []string
+	Anonymized        int
+	AlreadyAnonymized int
+	AnonymizedAt      time.Time
+}
+
+// IDataSubjectUseCase answers LGPD data subject requests (access and erasure).
+type IDataSubjectUseCase interface {
+	Export(ctx context.Context, subject DataSubject, actor string) (DataSubjectExport, error)
+	Anonymize(ctx context.Context, subject DataSubject, actor string) (DataSubjectAnonymization, error)
+}
+
+type DataSubjectUseCase struct {
+	repo         interfaces.IBillingPaymentRepository
+	estimateRepo interfaces.IEstimateRepository
+	index        interfaces.IBlindIndex
+	protector    interfaces.ISensitiveDataProtector
+	audit        interfaces.IAuditLogRepository
+	nfse         INFSeUseCase
+	approvals    IEstimateApprovalUseCase
+	now          func() time.Time
+}
+
+var _ IDataSubjectUseCase = (*DataSubjectUseCase)(nil)
+
+// NewDataSubjectUseCase builds the use case. protector may be nil when payer data is
+// stored in plaintext.
+func NewDataSubjectUseCase(
+	repo interfaces.IBillingPaymentRepository,
+	estimateRepo interfaces.IEstimateRepository,
+	index interfaces.IBlindIndex,
+	protector interfaces.ISensitiveDataProtector,
+	audit interfaces.IAuditLogRepository,
+) *DataSubjectUseCase {
+	return &DataSubjectUseCase{
+		repo:         repo,
+		estimateRepo: estimateRepo,
+		index:        index,
+		protector:    protector,
+		audit:        audit,
+		now:          time.Now,
+	}
+}
+
+// WithNFSeDocuments includes the NFS-e documents issued to the data subject (as tomador)
+// in the export.
+func (u *DataSubjectUseCase) WithNFSeDocuments(nfse INFSeUseCase) *DataSubjectUseCase {
+	u.nfse = nfse
+	return u
+}
+
+// WithApprovalLinks includes the approval links sent to the data subject in the export
+// and erases their recipient and decision IP/user agent on anonymization.
+func (u *DataSubjectUseCase) WithApprovalLinks(approvals IEstimateApprovalUseCase) *DataSubjectUseCase {
+	u.approvals = approvals
+	return u
+}
+
+// Export returns the decrypted payments, the NFS-e documents issued to the data subject,
+// the approval links sent to it and the estimates of both.
+// The audit record is written before any data is returned.
+func (u *DataSubjectUseCase) Export(ctx context.Context, subject DataSubject, actor string) (DataSubjectExport, error) {
+	ref, payments, err := u.findPayments(ctx, subject)
+	if err != nil {
+		return DataSubjectExport{}, err
+	}
+
+	out := DataSubjectExport{
+		Payments:      make([]entities.BillingPayment, 0, len(payments)),
+		Estimates:     []entities.Estimate{},
+		NFSeDocuments: []entities.NFSeDocument{},
+		ApprovalLinks: []entities.ApprovalLink{},
+		GeneratedAt:   u.now().UTC(),
+	}
+	seenEstimates := map[string]bool{}
+	addEstimate := func(id string) error {
+		if id == "" || seenEstimates[id] {
+			return nil
+		}
+		seenEstimates[id] = true
+		est, err := u.estimateRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if est.ID != "" {
+			out.Estimates = append(out.Estimates, est)
+		}
+		return nil
+	}
+	for _, p := range payments {
+		if u.protector != nil {
+			revealed, err := u.protector.Reveal(ctx, p)
+			if err != nil {
+				log.Printf("[payment][data-subject] reveal failed payment_id=%s err=%v", p.ID, err)
+				return DataSubjectExport{}, err
+			}
+			p = revealed
+		}
+		out.Payments = append(out.Payments, p)
+		if err := addEstimate(p.EstimateID); err != nil {
+			return DataSubjectExport{}, err
+		}
+	}
+
+	if u.nfse != nil {
+		if out.NFSeDocuments, err = u.nfse.FindByTomador(ctx, subject.Document, subject.Email); err != nil {
+			return DataSubjectExport{}, err
+		}
+	}
+	if u.approvals != nil {
+		if out.ApprovalLinks, err = u.approvals.FindByRecipient(ctx, subject.Email, subject.Phone); err != nil {
+			return DataSubjectExport{}, err
+		}
+		for _, l := range out.ApprovalLinks {
+			if err := addEstimate(l.EstimateID); err != nil {
+				return DataSubjectExport{}, err
+			}
+		}
+	}
+
+	if err := u.writeAudit(ctx, entities.AuditActionDataSubjectExport, actor, ref, map[string]string{
+		"payments":       strconv.Itoa(len(out.Payments)),
+		"estimates":      strconv.Itoa(len(out.Estimates)),
+		"nfse_documents": strconv.Itoa(len(out.NFSeDocuments)),
+		"approval_links": strconv.Itoa(len(out.ApprovalLinks)),
+	}); err != nil {
+		return DataSubjectExport{}, err
+	}
+	return out, nil
+}
+
+// Anonymize erases payer personal data from every payment of the data subject, keeping
+// monetary and fiscal fields required for accounting retention, and the recipient and
+// decision IP/user agent of its approval links, on the links and on the history of their
+// estimates. NFS-e documents are legal records and are only reported.
+func (u *DataSubjectUseCase) Anonymize(ctx context.Context, subject DataSubject, actor string) (DataSubjectAnonymization, error) {
+	ref, payments, err := u.findPayments(ctx, subject)
+	if err != nil {
+		return DataSubjectAnonymization{}, err
+	}
+
+	now := u.now().UTC()
+	out := DataSubjectAnonymization{
+		PaymentIDs:      make([]string, 0, len(payments)),
+		ApprovalLinkIDs: []string{},
+		RetainedNFSeIDs: []string{},
+		AnonymizedAt:    now,
+	}
+	for _, p := range payments {
+		out.PaymentIDs = append(out.PaymentIDs, p.ID)
+		anonymized, err := u.anonymizePayment(ctx, p, now)
+		if err != nil {
+			log.Printf("[payment][data-subject] anonymize failed payment_id=%s err=%v", p.ID, err)
+			return out, err
+		}
+		if anonymized {
+			out.Anonymized++
+		} else {
+			out.AlreadyAnonymized++
+		}
+	}
+
+	if u.approvals != nil {
+		links, err := u.approvals.FindByRecipient(ctx, subject.Email, subject.Phone)
+		if err != nil {
+			return out, err
+		}
+		linksByEstimate := map[string][]string{}
+		var estimateIDs []string
+		for _, l := range links {
+			if err := u.approvals.AnonymizeLink(ctx, l, now); err != nil {
+				log.Printf("[payment][data-subject] anonymize failed link_id=%s err=%v", l.ID, err)
+				return out, err
+			}
+			out.ApprovalLinkIDs = append(out.ApprovalLinkIDs, l.ID)
+			if _, ok := linksByEstimate[l.EstimateID]; !ok {
+				estimateIDs = append(estimateIDs, l.EstimateID)
+			}
+			linksByEstimate[l.EstimateID] = append(linksByEstimate[l.EstimateID], l.ID)
+		}
+		for _, id := range estimateIDs {
+			if err := u.eraseDecisionEvidence(ctx, id, linksByEstimate[id]); err != nil {
+				log.Printf("[payment][data-subject] anonymize failed estimate_id=%s err=%v", id, err)
+				return out, err
+			}
+		}
+	}
+
+	if u.nfse != nil {
+		docs, err := u.nfse.FindByTomador(ctx, subject.Document, subject.Email)
+		if err != nil {
+			return out, err
+		}
+		for _, doc := range docs {
+			out.RetainedNFSeIDs = append(out.RetainedNFSeIDs, doc.ID)
+		}
+	}
+
+	if err := u.writeAudit(ctx, entities.AuditActionDataSubjectAnonymize, actor, ref, map[string]string{
+		"payment_ids":       strings.Join(out.PaymentIDs, ","),
+		"approval_link_ids": strings.Join(out.ApprovalLinkIDs, ","),
+		"retained_nfse_ids": strings.Join(out.RetainedNFSeIDs, ","),
+		"anonymized":        strconv.Itoa(out.Anonymized),
+	}); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// anonymizePayment erases the payer data of p, re-reading it when its status changed
+// since read. It reports false when the payment was already anonymized.
+func (u *DataSubjectUseCase) anonymizePayment(ctx context.Context, p entities.BillingPayment, at time.Time) (bool, error) {
+	for attempt := 0; ; attempt++ {
+		if p.AnonymizedAt != nil {
+			return false, nil
+		}
+		err := u.repo.Anonymize(ctx, p, at)
+		if !errors.Is(err, entities.ErrPaymentStatusChanged) || attempt == maxAnonymizeAttempts-1 {
+			return err == nil, err
+		}
+		if p, err = u.repo.GetByID(ctx, p.ID); err != nil {
+			return false, err
+		}
+	}
+}
+
+// eraseDecisionEvidence removes the evidence of the decisions made through linkIDs from
+// the estimate history, re-reading the estimate when it changed since read.
+func (u *DataSubjectUseCase) eraseDecisionEvidence(ctx context.Context, estimateID string, linkIDs []string) error {
+	for attempt := 0; ; attempt++ {
+		est, err := u.estimateRepo.GetByID(ctx, estimateID)
+		if err != nil || est.ID == "" {
+			return err
+		}
+		_, err = u.estimateRepo.EraseDecisionEvidence(ctx, est, linkIDs)
+		if !errors.Is(err, entities.ErrEstimateChanged) || attempt == maxAnonymizeAttempts-1 {
+			return err
+		}
+	}
+}
+
+// findPayments resolves the subject through the blind indexes and returns its payments
+// (deduplicated, oldest first) along with a non-reversible reference for the audit log.
+func (u *DataSubjectUseCase) findPayments(ctx context.Context, subject DataSubject) (string, []entities.BillingPayment, error) {
+	emailHash := u.index.Hash(entities.NormalizePayerEmail(subject.Email))
+	docHash := u.index.Hash(entities.NormalizePayerDocument(subject.Document))
+	// Phones are only digits, like documents.
+	phoneHash := u.index.Hash(entities.NormalizePayerDocument(subject.Phone))
+	if emailHash == "" && docHash == "" && phoneHash == "" {
+		return "", nil, ErrInvalidDataSubject
+	}
+
+	var refs []string
+	byID := map[string]entities.BillingPayment{}
+	if emailHash != "" {
+		refs = append(refs, "email:"+emailHash)
+		found, err := u.repo.ListByPayerEmailHash(ctx, emailHash)
+		if err != nil {
+			return "", nil, err
+		}
+		for _, p := range found {
+			byID[p.ID] = p
+		}
+	}
+	if docHash != "" {
+		refs = append(refs, "doc:"+docHash)
+		found, err := u.repo.ListByPayerDocHash(ctx, docHash)
+		if err != nil {
+			return "", nil, err
+		}
+		for _, p := range found {
+			byID[p.ID] = p
+		}
+	}
+
+	if phoneHash != "" {
+		refs = append(refs, "phone:"+phoneHash)
+	}
+
+	payments := make([]entities.BillingPayment, 0, len(byID))
+	for _, p := range byID {
+		payments = append(payments, p)
+	}
+	sort.Slice(payments, func(i, j int) bool {
+		if payments[i].Date.Equal(payments[j].Date) {
+			return payments[i].ID < payments[j].ID
+		}
+		return payments[i].Date.Before(payments[j].Date)
+	})
+	return strings.Join(refs, ";"), payments, nil
+}
+
+func (u *DataSubjectUseCase) writeAudit(ctx context.Context, action entities.AuditAction, actor, subject string, metadata map[string]string) error {
+	rec := entities.AuditRecord{
+		ID:        uuid.NewString(),
+		Action:    action,
+		Actor:     actor,
+		Subject:   subject,
+		Metadata:  metadata,
+		CreatedAt: u.now().UTC(),
+	}
+	if err := u.audit.Create(ctx, rec); err != nil {
+		log.Printf("[payment][data-subject] audit write failed action=%s err=%v", action, err)
+		return err
+	}
+	log.Printf("[payment][data-subject] action=%s actor=%s subject=%s", action, actor, subject)
+	return nil
+}
@@ -0,0 +1,259 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	mock_interfaces "mecanica_xpto/internal/usecase/interfaces/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestDataSubjectUseCase_Export(t *testing.T) {
+	t.Run("requires email or document", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		index := mock_interfaces.NewMockIBlindIndex(ctrl)
+		uc := NewDataSubjectUseCase(nil, nil, index, nil, nil)
+
+		index.EXPECT().Hash("").Return("").Times(3)
+
+		if _, err := uc.Export(context.Background(), DataSubject{}, "admin"); !errors.Is(err, ErrInvalidDataSubject) {
+			t.Fatalf("expected ErrInvalidDataSubject, got %v", err)
+		}
+	})
+
+	t.Run("merges lookups, reveals payments and audits with hashes only", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		index := mock_interfaces.NewMockIBlindIndex(ctrl)
+		protector := mock_interfaces.NewMockISensitiveDataProtector(ctrl)
+		audit := mock_interfaces.NewMockIAuditLogRepository(ctrl)
+		uc := NewDataSubjectUseCase(repo, estRepo, index, protector, audit)
+
+		index.EXPECT().Hash("ana@example.com").Return("eh")
+		index.EXPECT().Hash("12345678909").Return("dh")
+		index.EXPECT().Hash("").Return("")
+		p1 := entities.BillingPayment{ID: "p1", EstimateID: "e1", Date: time.Unix(10, 0)}
+		p2 := entities.BillingPayment{ID: "p2", EstimateID: "e1", Date: time.Unix(20, 0)}
+		repo.EXPECT().ListByPayerEmailHash(gomock.Any(), "eh").Return([]entities.BillingPayment{p2, p1}, nil)
+		repo.EXPECT().ListByPayerDocHash(gomock.Any(), "dh").Return([]entities.BillingPayment{p1}, nil)
+		protector.EXPECT().Reveal(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, p entities.BillingPayment) (entities.BillingPayment, error) {
+				p.Details.PayerEmail = "ana@example.com"
+				return p, nil
+			}).Times(2)
+		estRepo.EXPECT().GetByID(gomock.Any(), "e1").Return(entities.Estimate{ID: "e1"}, nil)
+		audit.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, r entities.AuditRecord) error {
+				if r.Action != entities.AuditActionDataSubjectExport || r.Actor != "dpo" {
+					t.Fatalf("unexpected audit record: %+v", r)
+				}
+				if r.Subject != "email:eh;doc:dh" {
+					t.Fatalf("expected hashed subject, got %q", r.Subject)
+				}
+				return nil
+			})
+
+		out, err := uc.Export(context.Background(), DataSubject{Email: " Ana@Example.com ", Document: "123.456.789-09"}, "dpo")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(out.Payments) != 2 || out.Payments[0].ID != "p1" || out.Payments[1].ID != "p2" {
+			t.Fatalf("unexpected payments: %+v", out.Payments)
+		}
+		if out.Payments[0].Details.PayerEmail != "ana@example.com" {
+			t.Fatalf("expected revealed payment")
+		}
+		if len(out.Estimates) != 1 || out.Estimates[0].ID != "e1" {
+			t.Fatalf("unexpected estimates: %+v", out.Estimates)
+		}
+	})
+
+	t.Run("includes the NFS-e documents issued to the subject", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		index := mock_interfaces.NewMockIBlindIndex(ctrl)
+		audit := mock_interfaces.NewMockIAuditLogRepository(ctrl)
+		nfseRepo := mock_interfaces.NewMockINFSeRepository(ctrl)
+		uc := NewDataSubjectUseCase(repo, nil, index, nil, audit).
+			WithNFSeDocuments(NewNFSeUseCase(nfseRepo, nil, nil, nil).WithDataProtection(nil, index))
+
+		index.EXPECT().Hash("12345678909").Return("dh").Times(2)
+		index.EXPECT().Hash("").Return("").AnyTimes()
+		repo.EXPECT().ListByPayerDocHash(gomock.Any(), "dh").Return(nil, nil)
+		doc := entities.NFSeDocument{ID: "n1", RPS: entities.NFSeRPS{Tomador: entities.NFSeTomador{Document: "12345678909", Name: "Ana"}}}
+		nfseRepo.EXPECT().ListByTomadorDocHash(gomock.Any(), "dh").Return([]entities.NFSeDocument{doc}, nil)
+		audit.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, r entities.AuditRecord) error {
+				if r.Metadata["nfse_documents"] != "1" {
+					t.Fatalf("unexpected audit metadata: %+v", r.Metadata)
+				}
+				return nil
+			})
+
+		out, err := uc.Export(context.Background(), DataSubject{Document: "123.456.789-09"}, "dpo")
+		if err != nil || len(out.NFSeDocuments) != 1 || out.NFSeDocuments[0].RPS.Tomador.Name != "Ana" {
+			t.Fatalf("unexpected export %+v err=%v", out.NFSeDocuments, err)
+		}
+	})
+
+	t.Run("audit failure withholds data", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		index := mock_interfaces.NewMockIBlindIndex(ctrl)
+		audit := mock_interfaces.NewMockIAuditLogRepository(ctrl)
+		uc := NewDataSubjectUseCase(repo, nil, index, nil, audit)
+
+		index.EXPECT().Hash("ana@example.com").Return("eh")
+		index.EXPECT().Hash("").Return("").Times(2)
+		repo.EXPECT().ListByPayerEmailHash(gomock.Any(), "eh").Return(nil, nil)
+		audit.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("ddb"))
+
+		if _, err := uc.Export(context.Background(), DataSubject{Email: "ana@example.com"}, "admin"); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
+
+func TestDataSubjectUseCase_Anonymize(t *testing.T) {
+	t.Run("erases payer data of the payments not yet anonymized", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		index := mock_interfaces.NewMockIBlindIndex(ctrl)
+		audit := mock_interfaces.NewMockIAuditLogRepository(ctrl)
+		nfseRepo := mock_interfaces.NewMockINFSeRepository(ctrl)
+		uc := NewDataSubjectUseCase(repo, nil, index, nil, audit).
+			WithNFSeDocuments(NewNFSeUseCase(nfseRepo, nil, nil, nil).WithDataProtection(nil, index))
+
+		index.EXPECT().Hash("12345678909").Return("dh").Times(2)
+		index.EXPECT().Hash("").Return("").AnyTimes()
+		done := time.Unix(1, 0)
+		p1 := entities.BillingPayment{ID: "p1", Status: entities.PaymentStatusAprovado, Details: entities.PaymentDetails{Amount: 150, PayerDocHash: "dh"}}
+		p2 := entities.BillingPayment{ID: "p2", Date: time.Unix(5, 0), AnonymizedAt: &done}
+		repo.EXPECT().ListByPayerDocHash(gomock.Any(), "dh").Return([]entities.BillingPayment{p1, p2}, nil)
+		repo.EXPECT().Anonymize(gomock.Any(), p1, gomock.Any()).Return(nil)
+		nfseRepo.EXPECT().ListByTomadorDocHash(gomock.Any(), "dh").Return([]entities.NFSeDocument{{ID: "n1"}}, nil)
+		audit.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, r entities.AuditRecord) error {
+				if r.Action != entities.AuditActionDataSubjectAnonymize || r.Metadata["payment_ids"] != "p1,p2" {
+					t.Fatalf("unexpected audit record: %+v", r)
+				}
+				if r.Metadata["retained_nfse_ids"] != "n1" {
+					t.Fatalf("expected retained NFS-e in audit: %+v", r.Metadata)
+				}
+				return nil
+			})
+
+		out, err := uc.Anonymize(context.Background(), DataSubject{Document: "123.456.789-09"}, "admin")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.Anonymized != 1 || out.AlreadyAnonymized != 1 || len(out.RetainedNFSeIDs) != 1 {
+			t.Fatalf("unexpected report: %+v", out)
+		}
+	})
+
+	t.Run("re-reads a payment whose status changed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		index := mock_interfaces.NewMockIBlindIndex(ctrl)
+		audit := mock_interfaces.NewMockIAuditLogRepository(ctrl)
+		uc := NewDataSubjectUseCase(repo, nil, index, nil, audit)
+
+		index.EXPECT().Hash("ana@example.com").Return("eh")
+		index.EXPECT().Hash("").Return("").Times(2)
+		read := entities.BillingPayment{ID: "p1", Status: entities.PaymentStatusPendente}
+		current := entities.BillingPayment{ID: "p1", Status: entities.PaymentStatusAprovado}
+		repo.EXPECT().ListByPayerEmailHash(gomock.Any(), "eh").Return([]entities.BillingPayment{read}, nil)
+		gomock.InOrder(
+			repo.EXPECT().Anonymize(gomock.Any(), read, gomock.Any()).Return(entities.ErrPaymentStatusChanged),
+			repo.EXPECT().GetByID(gomock.Any(), "p1").Return(current, nil),
+			repo.EXPECT().Anonymize(gomock.Any(), current, gomock.Any()).Return(nil),
+		)
+		audit.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+		out, err := uc.Anonymize(context.Background(), DataSubject{Email: "ana@example.com"}, "admin")
+		if err != nil || out.Anonymized != 1 {
+			t.Fatalf("unexpected report %+v err=%v", out, err)
+		}
+	})
+}
+
+func TestDataSubjectUseCase_ApprovalLinks(t *testing.T) {
+	link := entities.ApprovalLink{ID: "l1", EstimateID: "e1", Channel: entities.NotificationChannelSMS, Recipient: "11987654321",
+		Evidence: &entities.DecisionEvidence{LinkID: "l1", IP: "203.0.113.7", UserAgent: "Mozilla/5.0"}}
+
+	t.Run("a phone finds the links sent to it and their estimates", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		index := mock_interfaces.NewMockIBlindIndex(ctrl)
+		audit := mock_interfaces.NewMockIAuditLogRepository(ctrl)
+		links := mock_interfaces.NewMockIApprovalLinkRepository(ctrl)
+		uc := NewDataSubjectUseCase(nil, estRepo, index, nil, audit).
+			WithApprovalLinks(NewEstimateApprovalUseCase(links, NewEstimateUseCase(estRepo), nil, nil).WithDataProtection(nil, index))
+
+		index.EXPECT().Hash("11987654321").Return("ph").Times(2)
+		index.EXPECT().Hash("").Return("").AnyTimes()
+		links.EXPECT().ListByRecipientHash(gomock.Any(), "ph").Return([]entities.ApprovalLink{link}, nil)
+		estRepo.EXPECT().GetByID(gomock.Any(), "e1").Return(entities.Estimate{ID: "e1"}, nil)
+		audit.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, r entities.AuditRecord) error {
+				if r.Subject != "phone:ph" || r.Metadata["approval_links"] != "1" {
+					t.Fatalf("unexpected audit record: %+v", r)
+				}
+				return nil
+			})
+
+		out, err := uc.Export(context.Background(), DataSubject{Phone: "(11) 98765-4321"}, "dpo")
+		if err != nil || len(out.ApprovalLinks) != 1 || out.ApprovalLinks[0].Evidence.IP != "203.0.113.7" || len(out.Estimates) != 1 {
+			t.Fatalf("unexpected export %+v err=%v", out, err)
+		}
+	})
+
+	t.Run("anonymizes the links and the evidence on their estimates", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		index := mock_interfaces.NewMockIBlindIndex(ctrl)
+		audit := mock_interfaces.NewMockIAuditLogRepository(ctrl)
+		links := mock_interfaces.NewMockIApprovalLinkRepository(ctrl)
+		uc := NewDataSubjectUseCase(nil, estRepo, index, nil, audit).
+			WithApprovalLinks(NewEstimateApprovalUseCase(links, NewEstimateUseCase(estRepo), nil, nil).WithDataProtection(nil, index))
+
+		index.EXPECT().Hash("11987654321").Return("ph").Times(2)
+		index.EXPECT().Hash("").Return("").AnyTimes()
+		links.EXPECT().ListByRecipientHash(gomock.Any(), "ph").Return([]entities.ApprovalLink{link}, nil)
+		links.EXPECT().Anonymize(gomock.Any(), link, gomock.Any()).Return(nil)
+		stale := entities.Estimate{ID: "e1", UpdatedAt: time.Unix(10, 0)}
+		current := entities.Estimate{ID: "e1", UpdatedAt: time.Unix(20, 0)}
+		gomock.InOrder(
+			estRepo.EXPECT().GetByID(gomock.Any(), "e1").Return(stale, nil),
+			estRepo.EXPECT().EraseDecisionEvidence(gomock.Any(), stale, []string{"l1"}).Return(entities.Estimate{}, entities.ErrEstimateChanged),
+			estRepo.EXPECT().GetByID(gomock.Any(), "e1").Return(current, nil),
+			estRepo.EXPECT().EraseDecisionEvidence(gomock.Any(), current, []string{"l1"}).Return(current, nil),
+		)
+		audit.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, r entities.AuditRecord) error {
+				if r.Metadata["approval_link_ids"] != "l1" {
+					t.Fatalf("unexpected audit record: %+v", r)
+				}
+				return nil
+			})
+
+		anon, err := uc.Anonymize(context.Background(), DataSubject{Phone: "(11) 98765-4321"}, "dpo")
+		if err != nil || len(anon.ApprovalLinkIDs) != 1 || len(anon.PaymentIDs) != 0 {
+			t.Fatalf("unexpected anonymization %+v err=%v", anon, err)
+		}
+	})
+}
@@ -0,0 +1,260 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+)
+
+var (
+	ErrDisputeNotFound        = errors.New("dispute not found")
+	ErrInvalidDisputeID       = errors.New("invalid dispute id")
+	ErrInvalidDisputeEvidence = errors.New("invalid dispute evidence")
+	ErrDisputeClosed          = errors.New("dispute already resolved")
+)
+
+// disputeNotificationActor identifies provider-driven changes in the payment history.
+const disputeNotificationActor = "mercadopago"
+
+// DisputeEvidenceInput is an evidence reference attached by an operator.
+type DisputeEvidenceInput struct {
+	Reference   string
+	Description string
+}
+
+// IDisputeUseCase manages chargeback disputes.
+type IDisputeUseCase interface {
+	HandleChargebackNotification(ctx context.Context, chargebackID string) (entities.Dispute, error)
+	GetByID(ctx context.Context, id string) (entities.Dispute, error)
+	ListOpen(ctx context.Context) ([]entities.Dispute, error)
+	AddEvidence(ctx context.Context, id string, evidence DisputeEvidenceInput, actor string) (entities.Dispute, error)
+}
+
+type DisputeUseCase struct {
+	repo         interfaces.IDisputeRepository
+	provider     interfaces.IChargebackProvider
+	payments     IBillingPaymentUseCase
+	estimateRepo interfaces.IEstimateRepository
+	now          func() time.Time
+}
+
+var _ IDisputeUseCase = (*DisputeUseCase)(nil)
+
+func NewDisputeUseCase(repo interfaces.IDisputeRepository, provider interfaces.IChargebackProvider, payments IBillingPaymentUseCase, estimateRepo interfaces.IEstimateRepository) *DisputeUseCase {
+	return &DisputeUseCase{
+		repo:         repo,
+		provider:     provider,
+		payments:     payments,
+		estimateRepo: estimateRepo,
+		now:          time.Now,
+	}
+}
+
+// HandleChargebackNotification syncs a dispute with the provider chargeback state.
+// The first notification opens the dispute and marks the payment as contested; later ones
+// move the dispute forward. Repeated notifications are no-ops.
+func (u *DisputeUseCase) HandleChargebackNotification(ctx context.Context, chargebackID string) (entities.Dispute, error) {
+	chargebackID = strings.TrimSpace(chargebackID)
+	if chargebackID == "" {
+		return entities.Dispute{}, ErrInvalidDisputeID
+	}
+	if u.provider == nil {
+		return entities.Dispute{}, errors.New("chargeback provider not configured")
+	}
+
+	cb, err := u.provider.GetChargeback(ctx, chargebackID)
+	if err != nil {
+		log.Printf("[dispute][usecase] chargeback fetch failed chargeback_id=%s err=%v", chargebackID, err)
+		return entities.Dispute{}, err
+	}
+
+	d, err := u.repo.GetByID(ctx, cb.ID)
+	if err != nil {
+		return entities.Dispute{}, err
+	}
+	if d.ID == "" {
+		if d, err = u.open(ctx, cb); err != nil {
+			return entities.Dispute{}, err
+		}
+	} else if d.Status.IsOpen() && (d.Amount != cb.Amount || !sameTime(d.EvidenceDeadline, cb.EvidenceDeadline)) {
+		d.Amount = cb.Amount
+		d.EvidenceDeadline = cb.EvidenceDeadline
+		d.UpdatedAt = u.now().UTC()
+		if err := u.repo.Update(ctx, d); err != nil {
+			return entities.Dispute{}, err
+		}
+	}
+
+	if cb.Status != d.Status && d.Status.CanTransitionTo(cb.Status) {
+		return u.transition(ctx, d, cb.Status)
+	}
+	return d, nil
+}
+
+func (u *DisputeUseCase) open(ctx context.Context, cb entities.ProviderChargeback) (entities.Dispute, error) {
+	p, err := u.payments.GetByID(ctx, cb.PaymentID)
+	if err != nil {
+		log.Printf("[dispute][usecase] payment lookup failed chargeback_id=%s payment_id=%s err=%v", cb.ID, cb.PaymentID, err)
+		return entities.Dispute{}, err
+	}
+
+	// The payment is contested before the dispute exists: if this fails, the provider's
+	// retry finds no dispute and runs it again.
+	if _, err := u.payments.ChangeStatus(ctx, p.ID, PaymentStatusChange{
+		Status: entities.PaymentStatusContestado,
+		Source: entities.PaymentEventSourceWebhook,
+		Actor:  disputeNotificationActor,
+	}); err != nil {
+		return entities.Dispute{}, err
+	}
+
+	now := u.now().UTC()
+	amount := cb.Amount
+	if amount == 0 {
+		amount = p.Details.Amount
+	}
+	d := entities.Dispute{
+		ID:               cb.ID,
+		PaymentID:        p.ID,
+		EstimateID:       p.EstimateID,
+		Status:           entities.DisputeStatusAberta,
+		Amount:           amount,
+		Reason:           cb.Reason,
+		EvidenceDeadline: cb.EvidenceDeadline,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if d, err = u.repo.Create(ctx, d); err != nil {
+		return entities.Dispute{}, err
+	}
+	log.Printf("[dispute][usecase] dispute opened dispute_id=%s payment_id=%s amount=%.2f", d.ID, d.PaymentID, d.Amount)
+	return d, nil
+}
+
+// transition moves the dispute to next. Side effects on the payment and estimate run
+// before the dispute is saved, so a failure is retried by the next provider notification;
+// the balance due is added once per dispute, so the retry does not charge it again.
+func (u *DisputeUseCase) transition(ctx context.Context, d entities.Dispute, next entities.DisputeStatus) (entities.Dispute, error) {
+	switch next {
+	case entities.DisputeStatusPerdida:
+		if _, err := u.payments.ChangeStatus(ctx, d.PaymentID, PaymentStatusChange{
+			Status: entities.PaymentStatusEstornado,
+			Source: entities.PaymentEventSourceWebhook,
+			Actor:  disputeNotificationActor,
+		}); err != nil {
+			return entities.Dispute{}, err
+		}
+		if _, err := u.estimateRepo.AddBalanceDue(ctx, d.EstimateID, d.Amount, "dispute#"+d.ID); err != nil {
+			log.Printf("[dispute][usecase] estimate balance update failed dispute_id=%s estimate_id=%s err=%v", d.ID, d.EstimateID, err)
+			return entities.Dispute{}, err
+		}
+	case entities.DisputeStatusGanha:
+		if _, err := u.payments.ChangeStatus(ctx, d.PaymentID, PaymentStatusChange{
+			Status: entities.PaymentStatusAprovado,
+			Source: entities.PaymentEventSourceWebhook,
+			Actor:  disputeNotificationActor,
+		}); err != nil {
+			return entities.Dispute{}, err
+		}
+	}
+
+	now := u.now().UTC()
+	d.Status = next
+	d.UpdatedAt = now
+	if !next.IsOpen() {
+		d.ResolvedAt = &now
+	}
+	if err := u.repo.Update(ctx, d); err != nil {
+		return entities.Dispute{}, err
+	}
+	log.Printf("[dispute][usecase] dispute updated dispute_id=%s status=%s", d.ID, d.Status)
+	return d, nil
+}
+
+func (u *DisputeUseCase) GetByID(ctx context.Context, id string) (entities.Dispute, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return entities.Dispute{}, ErrInvalidDisputeID
+	}
+	d, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return entities.Dispute{}, err
+	}
+	if d.ID == "" {
+		return entities.Dispute{}, ErrDisputeNotFound
+	}
+	return d, nil
+}
+
+// ListOpen returns disputes waiting for a provider decision, closest deadline first.
+func (u *DisputeUseCase) ListOpen(ctx context.Context) ([]entities.Dispute, error) {
+	var out []entities.Dispute
+	for _, status := range []entities.DisputeStatus{entities.DisputeStatusAberta, entities.DisputeStatusEvidenciaEnviada} {
+		items, err := u.repo.ListByStatus(ctx, status)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, items...)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i].EvidenceDeadline, out[j].EvidenceDeadline
+		switch {
+		case a == nil && b == nil:
+			return out[i].CreatedAt.Before(out[j].CreatedAt)
+		case a == nil:
+			return false
+		case b == nil:
+			return true
+		}
+		return a.Before(*b)
+	})
+	if out == nil {
+		out = []entities.Dispute{}
+	}
+	return out, nil
+}
+
+// AddEvidence attaches an evidence reference to an open dispute.
+func (u *DisputeUseCase) AddEvidence(ctx context.Context, id string, evidence DisputeEvidenceInput, actor string) (entities.Dispute, error) {
+	evidence.Reference = strings.TrimSpace(evidence.Reference)
+	if evidence.Reference == "" {
+		return entities.Dispute{}, ErrInvalidDisputeEvidence
+	}
+
+	d, err := u.GetByID(ctx, id)
+	if err != nil {
+		return entities.Dispute{}, err
+	}
+	if !d.Status.IsOpen() {
+		return entities.Dispute{}, ErrDisputeClosed
+	}
+
+	now := u.now().UTC()
+	d.Evidence = append(d.Evidence, entities.DisputeEvidence{
+		Reference:   evidence.Reference,
+		Description: strings.TrimSpace(evidence.Description),
+		AddedBy:     actor,
+		AddedAt:     now,
+	})
+	d.Status = entities.DisputeStatusEvidenciaEnviada
+	d.UpdatedAt = now
+	if err := u.repo.Update(ctx, d); err != nil {
+		return entities.Dispute{}, err
+	}
+	log.Printf("[dispute][usecase] evidence added dispute_id=%s actor=%s", d.ID, actor)
+	return d, nil
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	mock_interfaces "mecanica_xpto/internal/usecase/interfaces/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestDisputeUseCase_HandleChargebackNotification(t *testing.T) {
+	t.Run("invalid id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIDisputeRepository(ctrl)
+		provider := mock_interfaces.NewMockIChargebackProvider(ctrl)
+		payRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		uc := NewDisputeUseCase(repo, provider, NewBillingPaymentUseCase(payRepo, estRepo, nil), estRepo)
+		if _, err := uc.HandleChargebackNotification(context.Background(), " "); !errors.Is(err, ErrInvalidDisputeID) {
+			t.Fatalf("expected ErrInvalidDisputeID, got %v", err)
+		}
+	})
+
+	t.Run("opens dispute and contests payment", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIDisputeRepository(ctrl)
+		provider := mock_interfaces.NewMockIChargebackProvider(ctrl)
+		payRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		uc := NewDisputeUseCase(repo, provider, NewBillingPaymentUseCase(payRepo, estRepo, nil), estRepo)
+		deadline := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+		provider.EXPECT().GetChargeback(gomock.Any(), "cb1").Return(entities.ProviderChargeback{
+			ID: "cb1", PaymentID: "pay-1", Status: entities.DisputeStatusAberta, EvidenceDeadline: &deadline,
+		}, nil)
+		repo.EXPECT().GetByID(gomock.Any(), "cb1").Return(entities.Dispute{}, nil)
+		payment := entities.BillingPayment{ID: "pay-1", EstimateID: "est-1", Status: entities.PaymentStatusAprovado, Details: entities.PaymentDetails{Amount: 80}}
+		payRepo.EXPECT().GetByID(gomock.Any(), "pay-1").Return(payment, nil).Times(2)
+		contested := payRepo.EXPECT().UpdateStatusWithEvent(gomock.Any(), statusEvent("pay-1", entities.PaymentStatusContestado), nil).Return(nil)
+		repo.EXPECT().Create(gomock.Any(), gomock.Any()).After(contested).DoAndReturn(func(_ context.Context, d entities.Dispute) (entities.Dispute, error) {
+			if d.EstimateID != "est-1" || d.Amount != 80 || d.Status != entities.DisputeStatusAberta {
+				t.Fatalf("unexpected dispute: %+v", d)
+			}
+			return d, nil
+		})
+
+		d, err := uc.HandleChargebackNotification(context.Background(), "cb1")
+		if err != nil || d.ID != "cb1" {
+			t.Fatalf("unexpected result: %+v err=%v", d, err)
+		}
+	})
+
+	t.Run("failed contest leaves no dispute for the retry to find", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIDisputeRepository(ctrl)
+		provider := mock_interfaces.NewMockIChargebackProvider(ctrl)
+		payRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		uc := NewDisputeUseCase(repo, provider, NewBillingPaymentUseCase(payRepo, estRepo, nil), estRepo)
+		provider.EXPECT().GetChargeback(gomock.Any(), "cb1").Return(entities.ProviderChargeback{ID: "cb1", PaymentID: "pay-1", Status: entities.DisputeStatusAberta}, nil)
+		repo.EXPECT().GetByID(gomock.Any(), "cb1").Return(entities.Dispute{}, nil)
+		payment := entities.BillingPayment{ID: "pay-1", EstimateID: "est-1", Status: entities.PaymentStatusAprovado}
+		payRepo.EXPECT().GetByID(gomock.Any(), "pay-1").Return(payment, nil).Times(2)
+		payRepo.EXPECT().UpdateStatusWithEvent(gomock.Any(), statusEvent("pay-1", entities.PaymentStatusContestado), nil).Return(errors.New("ddb"))
+
+		if _, err := uc.HandleChargebackNotification(context.Background(), "cb1"); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("lost dispute reverses payment and estimate balance", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIDisputeRepository(ctrl)
+		provider := mock_interfaces.NewMockIChargebackProvider(ctrl)
+		payRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		uc := NewDisputeUseCase(repo, provider, NewBillingPaymentUseCase(payRepo, estRepo, nil), estRepo)
+		provider.EXPECT().GetChargeback(gomock.Any(), "cb1").Return(entities.ProviderChargeback{
+			ID: "cb1", PaymentID: "pay-1", Amount: 80, Status: entities.DisputeStatusPerdida,
+		}, nil)
+		repo.EXPECT().GetByID(gomock.Any(), "cb1").Return(entities.Dispute{
+			ID: "cb1", PaymentID: "pay-1", EstimateID: "est-1", Amount: 80, Status: entities.DisputeStatusEvidenciaEnviada,
+		}, nil)
+		payRepo.EXPECT().GetByID(gomock.Any(), "pay-1").Return(entities.BillingPayment{ID: "pay-1", Status: entities.PaymentStatusContestado}, nil)
+		payRepo.EXPECT().UpdateStatusWithEvent(gomock.Any(), statusEvent("pay-1", entities.PaymentStatusEstornado), nil).Return(nil)
+		estRepo.EXPECT().AddBalanceDue(gomock.Any(), "est-1", 80.0, "dispute#cb1").Return(entities.Estimate{ID: "est-1", BalanceDue: 80}, nil)
+		repo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, d entities.Dispute) error {
+			if d.Status != entities.DisputeStatusPerdida || d.ResolvedAt == nil {
+				t.Fatalf("unexpected dispute: %+v", d)
+			}
+			return nil
+		})
+
+		if _, err := uc.HandleChargebackNotification(context.Background(), "cb1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("retry after a failed dispute update adds the balance once", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIDisputeRepository(ctrl)
+		provider := mock_interfaces.NewMockIChargebackProvider(ctrl)
+		payRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		uc := NewDisputeUseCase(repo, provider, NewBillingPaymentUseCase(payRepo, estRepo, nil), estRepo)
+		provider.EXPECT().GetChargeback(gomock.Any(), "cb1").Return(entities.ProviderChargeback{
+			ID: "cb1", PaymentID: "pay-1", Amount: 80, Status: entities.DisputeStatusPerdida,
+		}, nil).Times(2)
+		repo.EXPECT().GetByID(gomock.Any(), "cb1").Return(entities.Dispute{
+			ID: "cb1", PaymentID: "pay-1", EstimateID: "est-1", Amount: 80, Status: entities.DisputeStatusAberta,
+		}, nil).Times(2)
+		payRepo.EXPECT().GetByID(gomock.Any(), "pay-1").Return(entities.BillingPayment{ID: "pay-1", Status: entities.PaymentStatusContestado}, nil)
+		payRepo.EXPECT().UpdateStatusWithEvent(gomock.Any(), statusEvent("pay-1", entities.PaymentStatusEstornado), nil).Return(nil)
+		payRepo.EXPECT().GetByID(gomock.Any(), "pay-1").Return(entities.BillingPayment{ID: "pay-1", Status: entities.PaymentStatusEstornado}, nil)
+
+		// The repository applies each ref once: the retry gets an empty Estimate.
+		added := map[string]bool{}
+		estRepo.EXPECT().AddBalanceDue(gomock.Any(), "est-1", 80.0, "dispute#cb1").DoAndReturn(
+			func(_ context.Context, id string, _ float64, ref string) (entities.Estimate, error) {
+				if added[ref] {
+					return entities.Estimate{}, nil
+				}
+				added[ref] = true
+				return entities.Estimate{ID: id, BalanceDue: 80}, nil
+			}).Times(2)
+		repo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(errors.New("ddb"))
+		repo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+		if _, err := uc.HandleChargebackNotification(context.Background(), "cb1"); err == nil {
+			t.Fatalf("expected error from the dispute update")
+		}
+		if _, err := uc.HandleChargebackNotification(context.Background(), "cb1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(added) != 1 {
+			t.Fatalf("expected a single balance addition, got %v", added)
+		}
+	})
+
+	t.Run("resolved dispute ignores repeated notification", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIDisputeRepository(ctrl)
+		provider := mock_interfaces.NewMockIChargebackProvider(ctrl)
+		payRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		uc := NewDisputeUseCase(repo, provider, NewBillingPaymentUseCase(payRepo, estRepo, nil), estRepo)
+		provider.EXPECT().GetChargeback(gomock.Any(), "cb1").Return(entities.ProviderChargeback{ID: "cb1", PaymentID: "pay-1", Status: entities.DisputeStatusPerdida}, nil)
+		repo.EXPECT().GetByID(gomock.Any(), "cb1").Return(entities.Dispute{ID: "cb1", Status: entities.DisputeStatusPerdida}, nil)
+
+		if _, err := uc.HandleChargebackNotification(context.Background(), "cb1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestDisputeUseCase_AddEvidence(t *testing.T) {
+	t.Run("requires reference", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIDisputeRepository(ctrl)
+		provider := mock_interfaces.NewMockIChargebackProvider(ctrl)
+		payRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		uc := NewDisputeUseCase(repo, provider, NewBillingPaymentUseCase(payRepo, estRepo, nil), estRepo)
+		if _, err := uc.AddEvidence(context.Background(), "cb1", DisputeEvidenceInput{}, "ops"); !errors.Is(err, ErrInvalidDisputeEvidence) {
+			t.Fatalf("expected ErrInvalidDisputeEvidence, got %v", err)
+		}
+	})
+
+	t.Run("closed dispute", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIDisputeRepository(ctrl)
+		provider := mock_interfaces.NewMockIChargebackProvider(ctrl)
+		payRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		uc := NewDisputeUseCase(repo, provider, NewBillingPaymentUseCase(payRepo, estRepo, nil), estRepo)
+		repo.EXPECT().GetByID(gomock.Any(), "cb1").Return(entities.Dispute{ID: "cb1", Status: entities.DisputeStatusGanha}, nil)
+		if _, err := uc.AddEvidence(context.Background(), "cb1", DisputeEvidenceInput{Reference: "s3://os.pdf"}, "ops"); !errors.Is(err, ErrDisputeClosed) {
+			t.Fatalf("expected ErrDisputeClosed, got %v", err)
+		}
+	})
+
+	t.Run("appends evidence", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIDisputeRepository(ctrl)
+		provider := mock_interfaces.NewMockIChargebackProvider(ctrl)
+		payRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		uc := NewDisputeUseCase(repo, provider, NewBillingPaymentUseCase(payRepo, estRepo, nil), estRepo)
+		repo.EXPECT().GetByID(gomock.Any(), "cb1").Return(entities.Dispute{ID: "cb1", Status: entities.DisputeStatusAberta}, nil)
+		repo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+		d, err := uc.AddEvidence(context.Background(), "cb1", DisputeEvidenceInput{Reference: "s3://os.pdf"}, "ops")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d.Status != entities.DisputeStatusEvidenciaEnviada || len(d.Evidence) != 1 || d.Evidence[0].AddedBy != "ops" {
+			t.Fatalf("unexpected dispute: %+v", d)
+		}
+	})
+}
+
+func TestDisputeUseCase_ListOpen(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := mock_interfaces.NewMockIDisputeRepository(ctrl)
+	provider := mock_interfaces.NewMockIChargebackProvider(ctrl)
+	payRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+	estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+	uc := NewDisputeUseCase(repo, provider, NewBillingPaymentUseCase(payRepo, estRepo, nil), estRepo)
+	early := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	repo.EXPECT().ListByStatus(gomock.Any(), entities.DisputeStatusAberta).Return([]entities.Dispute{{ID: "none"}, {ID: "late", EvidenceDeadline: &late}}, nil)
+	repo.EXPECT().ListByStatus(gomock.Any(), entities.DisputeStatusEvidenciaEnviada).Return([]entities.Dispute{{ID: "early", EvidenceDeadline: &early}}, nil)
+
+	out, err := uc.ListOpen(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out) != 3 || out[0].ID != "early" || out[1].ID != "late" || out[2].ID != "none" {
+		t.Fatalf("unexpected order: %+v", out)
+	}
+}
+
+// statusEvent matches the status event moving paymentID to status.
+func statusEvent(paymentID string, status entities.PaymentStatus) gomock.Matcher {
+	return gomock.Cond(func(e entities.PaymentStatusEvent) bool { return e.PaymentID == paymentID && e.Status == status })
+}
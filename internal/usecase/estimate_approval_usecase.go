@@ -0,0 +1,541 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidApprovalToken  = errors.New("invalid or expired approval link")
+	ErrApprovalLinkUsed      = errors.New("approval link already used")
+	ErrInvalidRecipient      = errors.New("invalid notification recipient")
+	ErrOTPNotSent            = errors.New("otp not sent or expired")
+	ErrInvalidOTP            = errors.New("invalid otp")
+	ErrOTPLocked             = errors.New("too many invalid otp attempts")
+	ErrOTPSendLimit          = errors.New("otp send limit reached")
+	ErrInvalidApprovalAction = errors.New("invalid approval action")
+)
+
+const (
+	ApprovalActionApprove = "aprovar"
+	ApprovalActionReject  = "rejeitar"
+)
+
+const (
+	defaultApprovalLinkTTL = 72 * time.Hour
+	approvalOTPTTL         = 10 * time.Minute
+	maxOTPSends            = 5
+	maxOTPAttempts         = 5
+	// customerActor is recorded as who decided estimates through an approval link.
+	customerActor = "cliente"
+)
+
+// ApprovalLinkRequest is where the customer is reached: the link and the OTPs go to
+// Recipient through Channel. CreatedBy is the operator who issued the link.
+type ApprovalLinkRequest struct {
+	Channel   entities.NotificationChannel
+	Recipient string
+	CreatedBy string
+}
+
+// IssuedApprovalLink is a link just created, with its token and URL. The token is only
+// returned here: the link keeps no copy of it.
+type IssuedApprovalLink struct {
+	Link  entities.ApprovalLink
+	Token string
+	URL   string
+}
+
+// ApprovalView is what the customer sees through an approval link.
+type ApprovalView struct {
+	Link     entities.ApprovalLink
+	Estimate entities.Estimate
+}
+
+// CustomerDecision is the customer answer through an approval link: Action is "aprovar"
+// or "rejeitar", confirmed by the OTP sent to the link recipient. ItemIDs approve part of
+// the estimate, as in ApproveByOSID. IP and UserAgent are kept as evidence.
+type CustomerDecision struct {
+	Action     string
+	OTP        string
+	ItemIDs    []string
+	ReasonCode string
+	ReasonText string
+	IP         string
+	UserAgent  string
+}
+
+// IEstimateApprovalUseCase lets customers approve or reject estimates through signed
+// links, confirming the decision with an OTP.
+type IEstimateApprovalUseCase interface {
+	CreateLink(ctx context.Context, estimateID string, req ApprovalLinkRequest) (IssuedApprovalLink, error)
+	View(ctx context.Context, token string) (ApprovalView, error)
+	SendOTP(ctx context.Context, token string) (entities.ApprovalLink, error)
+	Decide(ctx context.Context, token string, decision CustomerDecision) (entities.Estimate, error)
+	FindByRecipient(ctx context.Context, email, phone string) ([]entities.ApprovalLink, error)
+	AnonymizeLink(ctx context.Context, link entities.ApprovalLink, at time.Time) error
+}
+
+type EstimateApprovalUseCase struct {
+	repo      interfaces.IApprovalLinkRepository
+	estimates IEstimateUseCase
+	signer    interfaces.ILinkSigner
+	notifier  interfaces.INotifier
+	sealer    interfaces.IRecordSealer
+	index     interfaces.IBlindIndex
+	baseURL   string
+	ttl       time.Duration
+	now       func() time.Time
+}
+
+var _ IEstimateApprovalUseCase = (*EstimateApprovalUseCase)(nil)
+
+func NewEstimateApprovalUseCase(repo interfaces.IApprovalLinkRepository, estimates IEstimateUseCase, signer interfaces.ILinkSigner, notifier interfaces.INotifier) *EstimateApprovalUseCase {
+	return &EstimateApprovalUseCase{
+		repo:      repo,
+		estimates: estimates,
+		signer:    signer,
+		notifier:  notifier,
+		ttl:       defaultApprovalLinkTTL,
+		now:       time.Now,
+	}
+}
+
+// WithBaseURL sets the address the customer opens, to which the token is appended
+// (e.g. https://oficina.example.com/aprovacao).
+func (u *EstimateApprovalUseCase) WithBaseURL(baseURL string) *EstimateApprovalUseCase {
+	u.baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	return u
+}
+
+// WithLinkTTL sets how long a link is valid; it never outlives the estimate validity.
+func (u *EstimateApprovalUseCase) WithLinkTTL(ttl time.Duration) *EstimateApprovalUseCase {
+	if ttl > 0 {
+		u.ttl = ttl
+	}
+	return u
+}
+
+// WithDataProtection seals the recipient and the decision IP/user agent of links at rest
+// and indexes the recipient for data subject requests.
+func (u *EstimateApprovalUseCase) WithDataProtection(sealer interfaces.IRecordSealer, index interfaces.IBlindIndex) *EstimateApprovalUseCase {
+	u.sealer = sealer
+	u.index = index
+	return u
+}
+
+// CreateLink issues an approval link for a pending estimate and sends it to the customer.
+// The link is returned even if the message fails, so it can be shared another way.
+func (u *EstimateApprovalUseCase) CreateLink(ctx context.Context, estimateID string, req ApprovalLinkRequest) (IssuedApprovalLink, error) {
+	recipient, err := normalizeRecipient(req.Channel, req.Recipient)
+	if err != nil {
+		return IssuedApprovalLink{}, err
+	}
+	e, err := u.estimates.GetByID(ctx, estimateID)
+	if err != nil {
+		return IssuedApprovalLink{}, err
+	}
+	now := u.now().UTC()
+	if err := checkDecidable(e, now); err != nil {
+		return IssuedApprovalLink{}, err
+	}
+
+	expiresAt := now.Add(u.ttl)
+	if e.ExpiresAt != nil && e.ExpiresAt.Before(expiresAt) {
+		expiresAt = *e.ExpiresAt
+	}
+	link := entities.ApprovalLink{
+		ID:         uuid.NewString(),
+		EstimateID: e.ID,
+		OSID:       e.OSID,
+		Channel:    req.Channel,
+		Recipient:  recipient,
+		CreatedBy:  strings.TrimSpace(req.CreatedBy),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		ExpiresAt:  expiresAt,
+	}
+	created, err := u.create(ctx, link)
+	if err != nil {
+		return IssuedApprovalLink{}, err
+	}
+
+	token := u.signer.Sign(created.ID, created.ExpiresAt)
+	issued := IssuedApprovalLink{Link: created, Token: token, URL: u.baseURL + "/" + token}
+	msg := entities.Notification{
+		Channel:   created.Channel,
+		Recipient: created.Recipient,
+		Subject:   "Orçamento disponível para aprovação",
+		Body: fmt.Sprintf("Seu orçamento de R$ %.2f está disponível até %s: %s",
+			e.Price, created.ExpiresAt.Format("02/01/2006 15:04"), issued.URL),
+	}
+	if err := u.notifier.Notify(ctx, msg); err != nil {
+		log.Printf("[estimate][approval] link notification failed link_id=%s err=%v", created.ID, err)
+	}
+	return issued, nil
+}
+
+// View returns the link and its estimate. A used link can still be viewed, showing the
+// decision made with it.
+func (u *EstimateApprovalUseCase) View(ctx context.Context, token string) (ApprovalView, error) {
+	link, err := u.resolve(ctx, token)
+	if err != nil {
+		return ApprovalView{}, err
+	}
+	e, err := u.estimates.GetByID(ctx, link.EstimateID)
+	if err != nil {
+		return ApprovalView{}, err
+	}
+	return ApprovalView{Link: link, Estimate: e}, nil
+}
+
+// SendOTP sends a new code to the link recipient, replacing any previous one. Only a
+// digest of the code is kept.
+func (u *EstimateApprovalUseCase) SendOTP(ctx context.Context, token string) (entities.ApprovalLink, error) {
+	link, err := u.usable(ctx, token)
+	if err != nil {
+		return entities.ApprovalLink{}, err
+	}
+	if link.OTPSends >= maxOTPSends {
+		return entities.ApprovalLink{}, ErrOTPSendLimit
+	}
+	e, err := u.estimates.GetByID(ctx, link.EstimateID)
+	if err != nil {
+		return entities.ApprovalLink{}, err
+	}
+	now := u.now().UTC()
+	if err := checkDecidable(e, now); err != nil {
+		return entities.ApprovalLink{}, err
+	}
+
+	code, err := newOTP()
+	if err != nil {
+		return entities.ApprovalLink{}, err
+	}
+	previous := link.UpdatedAt
+	otpExpiresAt := now.Add(approvalOTPTTL)
+	link.OTPDigest = u.otpDigest(link.ID, code)
+	link.OTPExpiresAt = &otpExpiresAt
+	link.OTPSends++
+	link.UpdatedAt = now
+	updated, err := u.update(ctx, link, previous)
+	if err != nil {
+		return entities.ApprovalLink{}, err
+	}
+
+	msg := entities.Notification{
+		Channel:   updated.Channel,
+		Recipient: updated.Recipient,
+		Subject:   "Código de confirmação do orçamento",
+		Body:      fmt.Sprintf("Seu código de confirmação é %s. Ele vale por %d minutos.", code, int(approvalOTPTTL.Minutes())),
+	}
+	if err := u.notifier.Notify(ctx, msg); err != nil {
+		log.Printf("[estimate][approval] otp notification failed link_id=%s err=%v", updated.ID, err)
+		return entities.ApprovalLink{}, err
+	}
+	return updated, nil
+}
+
+// Decide approves or rejects the estimate of the link once the OTP is confirmed. The link
+// is consumed before the status changes, so it decides only once; if the change fails
+// (e.g. an unknown reason) it is released and can be used again. The decision goes through
+// ApproveByOSID/RejectByOSID, recording the customer as actor and the evidence in the
+// estimate history.
+func (u *EstimateApprovalUseCase) Decide(ctx context.Context, token string, decision CustomerDecision) (entities.Estimate, error) {
+	action := strings.ToLower(strings.TrimSpace(decision.Action))
+	status := entities.EstimateStatusAprovado
+	switch action {
+	case ApprovalActionApprove:
+	case ApprovalActionReject:
+		status = entities.EstimateStatusRejeitado
+	default:
+		return entities.Estimate{}, ErrInvalidApprovalAction
+	}
+
+	link, err := u.usable(ctx, token)
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	e, err := u.estimates.GetByID(ctx, link.EstimateID)
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	now := u.now().UTC()
+	if err := checkDecidable(e, now); err != nil {
+		return entities.Estimate{}, err
+	}
+	if !link.HasValidOTP(now) {
+		return entities.Estimate{}, ErrOTPNotSent
+	}
+
+	previous := link.UpdatedAt
+	link.UpdatedAt = now
+	digest := u.otpDigest(link.ID, strings.TrimSpace(decision.OTP))
+	if subtle.ConstantTimeCompare([]byte(digest), []byte(link.OTPDigest)) != 1 {
+		link.FailedAttempts++
+		if _, err := u.update(ctx, link, previous); err != nil {
+			return entities.Estimate{}, err
+		}
+		if link.FailedAttempts >= maxOTPAttempts {
+			return entities.Estimate{}, ErrOTPLocked
+		}
+		return entities.Estimate{}, ErrInvalidOTP
+	}
+
+	evidence := &entities.DecisionEvidence{
+		LinkID:    link.ID,
+		IP:        strings.TrimSpace(decision.IP),
+		UserAgent: strings.TrimSpace(decision.UserAgent),
+		Recipient: link.MaskedRecipient(),
+	}
+	link.UsedAt, link.Decision, link.Evidence = &now, status, evidence
+	consumed, err := u.update(ctx, link, previous)
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+
+	change := StatusDecision{
+		Actor:      customerActor,
+		ReasonCode: decision.ReasonCode,
+		ReasonText: decision.ReasonText,
+		Evidence:   evidence,
+	}
+	var decided entities.Estimate
+	if status == entities.EstimateStatusAprovado {
+		change.ItemIDs = decision.ItemIDs
+		decided, err = u.estimates.ApproveByOSID(ctx, consumed.OSID, change)
+	} else {
+		decided, err = u.estimates.RejectByOSID(ctx, consumed.OSID, change)
+	}
+	if err != nil {
+		u.release(ctx, consumed)
+		return entities.Estimate{}, err
+	}
+	return decided, nil
+}
+
+// release makes a consumed link usable again after its decision failed. It is best
+// effort: a link left consumed only needs a new one to be issued.
+func (u *EstimateApprovalUseCase) release(ctx context.Context, link entities.ApprovalLink) {
+	previous := link.UpdatedAt
+	link.UsedAt, link.Decision, link.Evidence = nil, "", nil
+	link.UpdatedAt = u.now().UTC()
+	if _, err := u.update(ctx, link, previous); err != nil {
+		log.Printf("[estimate][approval] link release failed link_id=%s err=%v", link.ID, err)
+	}
+}
+
+// resolve verifies the token and loads its link.
+func (u *EstimateApprovalUseCase) resolve(ctx context.Context, token string) (entities.ApprovalLink, error) {
+	now := u.now().UTC()
+	id, err := u.signer.Verify(strings.TrimSpace(token), now)
+	if err != nil {
+		return entities.ApprovalLink{}, ErrInvalidApprovalToken
+	}
+	link, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return entities.ApprovalLink{}, err
+	}
+	if link.ID == "" || link.IsExpired(now) || link.AnonymizedAt != nil {
+		return entities.ApprovalLink{}, ErrInvalidApprovalToken
+	}
+	return u.open(ctx, link)
+}
+
+// FindByRecipient returns the links (opened) sent to the e-mail address or phone number.
+// Without a blind index links cannot be looked up and none are returned.
+func (u *EstimateApprovalUseCase) FindByRecipient(ctx context.Context, email, phone string) ([]entities.ApprovalLink, error) {
+	if u.index == nil {
+		return []entities.ApprovalLink{}, nil
+	}
+	var recipients []string
+	if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+		recipients = append(recipients, email)
+	}
+	// An invalid number cannot have received a link.
+	if digits, err := normalizeRecipient(entities.NotificationChannelSMS, phone); err == nil {
+		recipients = append(recipients, digits)
+	}
+
+	links := []entities.ApprovalLink{}
+	for _, r := range recipients {
+		found, err := u.repo.ListByRecipientHash(ctx, u.index.Hash(r))
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range found {
+			opened, err := u.open(ctx, l)
+			if err != nil {
+				return nil, err
+			}
+			links = append(links, opened)
+		}
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].CreatedAt.Equal(links[j].CreatedAt) {
+			return links[i].ID < links[j].ID
+		}
+		return links[i].CreatedAt.Before(links[j].CreatedAt)
+	})
+	return links, nil
+}
+
+// AnonymizeLink erases the recipient and the decision IP/user agent of link, which can no
+// longer be used. link must be the last version read.
+func (u *EstimateApprovalUseCase) AnonymizeLink(ctx context.Context, link entities.ApprovalLink, at time.Time) error {
+	if link.AnonymizedAt != nil {
+		return nil
+	}
+	return u.repo.Anonymize(ctx, link, at.UTC())
+}
+
+func (u *EstimateApprovalUseCase) create(ctx context.Context, link entities.ApprovalLink) (entities.ApprovalLink, error) {
+	stored, err := u.seal(ctx, link)
+	if err != nil {
+		return entities.ApprovalLink{}, err
+	}
+	if _, err := u.repo.Create(ctx, stored); err != nil {
+		return entities.ApprovalLink{}, err
+	}
+	return link, nil
+}
+
+func (u *EstimateApprovalUseCase) update(ctx context.Context, link entities.ApprovalLink, previous time.Time) (entities.ApprovalLink, error) {
+	stored, err := u.seal(ctx, link)
+	if err != nil {
+		return entities.ApprovalLink{}, err
+	}
+	if _, err := u.repo.Update(ctx, stored, previous); err != nil {
+		return entities.ApprovalLink{}, err
+	}
+	return link, nil
+}
+
+// seal returns the stored form of link: recipient blind index filled and, with a sealer,
+// the recipient and the evidence IP/user agent encrypted.
+func (u *EstimateApprovalUseCase) seal(ctx context.Context, link entities.ApprovalLink) (entities.ApprovalLink, error) {
+	if u.index != nil {
+		link.RecipientHash = u.index.Hash(link.Recipient)
+	}
+	if u.sealer == nil {
+		return link, nil
+	}
+	key, err := u.sealer.Seal(ctx, link.ID, approvalLinkSensitiveFields(&link)...)
+	if err != nil {
+		return entities.ApprovalLink{}, err
+	}
+	link.DataKey = key
+	return link, nil
+}
+
+// open reverts seal on a stored link.
+func (u *EstimateApprovalUseCase) open(ctx context.Context, link entities.ApprovalLink) (entities.ApprovalLink, error) {
+	if link.DataKey == nil || u.sealer == nil {
+		return link, nil
+	}
+	if err := u.sealer.Open(ctx, link.ID, link.DataKey, approvalLinkSensitiveFields(&link)...); err != nil {
+		log.Printf("[estimate][approval] open sealed link failed link_id=%s err=%v", link.ID, err)
+		return entities.ApprovalLink{}, err
+	}
+	link.DataKey = nil
+	return link, nil
+}
+
+// approvalLinkSensitiveFields points at the personal data of link; the evidence is copied
+// first so the caller's link (and the estimate history sharing it) is never modified.
+func approvalLinkSensitiveFields(link *entities.ApprovalLink) []*string {
+	fields := []*string{&link.Recipient}
+	if link.Evidence != nil {
+		e := *link.Evidence
+		link.Evidence = &e
+		fields = append(fields, &e.IP, &e.UserAgent)
+	}
+	return fields
+}
+
+// usable resolves a link that can still decide its estimate.
+func (u *EstimateApprovalUseCase) usable(ctx context.Context, token string) (entities.ApprovalLink, error) {
+	link, err := u.resolve(ctx, token)
+	if err != nil {
+		return entities.ApprovalLink{}, err
+	}
+	if link.UsedAt != nil {
+		return entities.ApprovalLink{}, ErrApprovalLinkUsed
+	}
+	if link.FailedAttempts >= maxOTPAttempts {
+		return entities.ApprovalLink{}, ErrOTPLocked
+	}
+	return link, nil
+}
+
+// otpDigest binds the code to the link, so a code is never valid for another link.
+func (u *EstimateApprovalUseCase) otpDigest(linkID, code string) string {
+	return u.signer.Digest(linkID + ":" + code)
+}
+
+func checkDecidable(e entities.Estimate, at time.Time) error {
+	if e.AwaitsInternalApproval() {
+		return ErrInternalApprovalPending
+	}
+	if e.Status != entities.EstimateStatusPendente {
+		return ErrEstimateNotPending
+	}
+	if e.IsExpired(at) {
+		return ErrEstimateExpired
+	}
+	return nil
+}
+
+// newOTP returns a random 6-digit code.
+func newOTP() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// normalizeRecipient checks the recipient of the channel: an e-mail address, or a phone
+// number with 10 to 13 digits (DDD and country code optional), kept as digits only.
+func normalizeRecipient(channel entities.NotificationChannel, recipient string) (string, error) {
+	recipient = strings.TrimSpace(recipient)
+	switch channel {
+	case entities.NotificationChannelEmail:
+		addr, err := mail.ParseAddress(recipient)
+		if err != nil || addr.Address != recipient {
+			return "", ErrInvalidRecipient
+		}
+		return strings.ToLower(recipient), nil
+	case entities.NotificationChannelSMS:
+		digits := strings.Map(func(r rune) rune {
+			switch {
+			case r >= '0' && r <= '9':
+				return r
+			case strings.ContainsRune("+-() ", r):
+				return -1
+			default:
+				return 'x'
+			}
+		}, recipient)
+		if strings.ContainsRune(digits, 'x') || len(digits) < 10 || len(digits) > 13 {
+			return "", ErrInvalidRecipient
+		}
+		return digits, nil
+	default:
+		return "", ErrInvalidRecipient
+	}
+}
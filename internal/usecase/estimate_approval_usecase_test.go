@@ -0,0 +1,394 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	mock_interfaces "mecanica_xpto/internal/usecase/interfaces/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+var approvalNow = time.Date(2026, 5, 5, 12, 0, 0, 0, time.UTC)
+
+// expectLinkStore makes links hold *stored, updating it only when previousUpdatedAt still
+// matches as DynamoDB does, signer accept "tok-1" for link-1 and notifier collect into
+// *sent.
+func expectLinkStore(links *mock_interfaces.MockIApprovalLinkRepository, signer *mock_interfaces.MockILinkSigner, notifier *mock_interfaces.MockINotifier, stored *entities.ApprovalLink, sent *[]entities.Notification) {
+	signer.EXPECT().Digest(gomock.Any()).DoAndReturn(func(v string) string { return "digest:" + v }).AnyTimes()
+	signer.EXPECT().Verify(gomock.Any(), gomock.Any()).DoAndReturn(func(token string, _ time.Time) (string, error) {
+		if token != "tok-1" {
+			return "", errors.New("bad token")
+		}
+		return "link-1", nil
+	}).AnyTimes()
+	links.EXPECT().GetByID(gomock.Any(), "link-1").DoAndReturn(func(context.Context, string) (entities.ApprovalLink, error) {
+		return *stored, nil
+	}).AnyTimes()
+	links.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, l entities.ApprovalLink, previous time.Time) (entities.ApprovalLink, error) {
+		if !previous.Equal(stored.UpdatedAt) {
+			return entities.ApprovalLink{}, entities.ErrApprovalLinkChanged
+		}
+		*stored = l
+		return l, nil
+	}).AnyTimes()
+	notifier.EXPECT().Notify(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, n entities.Notification) error {
+		*sent = append(*sent, n)
+		return nil
+	}).AnyTimes()
+}
+
+func pendingEstimate() entities.Estimate {
+	expiresAt := approvalNow.Add(48 * time.Hour)
+	return entities.Estimate{ID: "est-1", OSID: "os-1", Price: 150, Status: entities.EstimateStatusPendente, ExpiresAt: &expiresAt}
+}
+
+func activeLink() entities.ApprovalLink {
+	return entities.ApprovalLink{
+		ID: "link-1", EstimateID: "est-1", OSID: "os-1",
+		Channel: entities.NotificationChannelSMS, Recipient: "11987654321",
+		CreatedAt: approvalNow.Add(-time.Hour), UpdatedAt: approvalNow.Add(-time.Hour), ExpiresAt: approvalNow.Add(24 * time.Hour),
+	}
+}
+
+var otpCode = regexp.MustCompile(`\d{6}`)
+
+func TestEstimateApprovalUseCase_CreateLink(t *testing.T) {
+	t.Run("invalid recipient", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		links := mock_interfaces.NewMockIApprovalLinkRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		signer := mock_interfaces.NewMockILinkSigner(ctrl)
+		notifier := mock_interfaces.NewMockINotifier(ctrl)
+		var stored entities.ApprovalLink
+		var sent []entities.Notification
+		expectLinkStore(links, signer, notifier, &stored, &sent)
+		estimateUC := NewEstimateUseCase(estRepo)
+		estimateUC.now = func() time.Time { return approvalNow }
+		uc := NewEstimateApprovalUseCase(links, estimateUC, signer, notifier).WithBaseURL("https://oficina.example.com/aprovacao/")
+		uc.now = func() time.Time { return approvalNow }
+		for _, req := range []ApprovalLinkRequest{
+			{Channel: entities.NotificationChannelEmail, Recipient: "cliente"},
+			{Channel: entities.NotificationChannelSMS, Recipient: "1234"},
+			{Channel: "pombo", Recipient: "cliente@example.com"},
+		} {
+			if _, err := uc.CreateLink(context.Background(), "est-1", req); !errors.Is(err, ErrInvalidRecipient) {
+				t.Fatalf("expected ErrInvalidRecipient for %+v, got %v", req, err)
+			}
+		}
+	})
+
+	t.Run("estimate not pending", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		links := mock_interfaces.NewMockIApprovalLinkRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		signer := mock_interfaces.NewMockILinkSigner(ctrl)
+		notifier := mock_interfaces.NewMockINotifier(ctrl)
+		var stored entities.ApprovalLink
+		var sent []entities.Notification
+		expectLinkStore(links, signer, notifier, &stored, &sent)
+		estimateUC := NewEstimateUseCase(estRepo)
+		estimateUC.now = func() time.Time { return approvalNow }
+		uc := NewEstimateApprovalUseCase(links, estimateUC, signer, notifier).WithBaseURL("https://oficina.example.com/aprovacao/")
+		uc.now = func() time.Time { return approvalNow }
+		e := pendingEstimate()
+		e.Status = entities.EstimateStatusAprovado
+		estRepo.EXPECT().GetByID(gomock.Any(), "est-1").Return(e, nil)
+		_, err := uc.CreateLink(context.Background(), "est-1", ApprovalLinkRequest{Channel: entities.NotificationChannelEmail, Recipient: "cliente@example.com"})
+		if !errors.Is(err, ErrEstimateNotPending) {
+			t.Fatalf("expected ErrEstimateNotPending, got %v", err)
+		}
+	})
+
+	t.Run("estimate awaiting internal approval", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		links := mock_interfaces.NewMockIApprovalLinkRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		signer := mock_interfaces.NewMockILinkSigner(ctrl)
+		notifier := mock_interfaces.NewMockINotifier(ctrl)
+		var stored entities.ApprovalLink
+		var sent []entities.Notification
+		expectLinkStore(links, signer, notifier, &stored, &sent)
+		estimateUC := NewEstimateUseCase(estRepo)
+		estimateUC.now = func() time.Time { return approvalNow }
+		uc := NewEstimateApprovalUseCase(links, estimateUC, signer, notifier).WithBaseURL("https://oficina.example.com/aprovacao/")
+		uc.now = func() time.Time { return approvalNow }
+		e := pendingEstimate()
+		e.Status = entities.EstimateStatusAguardandoAprovacao
+		estRepo.EXPECT().GetByID(gomock.Any(), "est-1").Return(e, nil)
+		_, err := uc.CreateLink(context.Background(), "est-1", ApprovalLinkRequest{Channel: entities.NotificationChannelEmail, Recipient: "cliente@example.com"})
+		if !errors.Is(err, ErrInternalApprovalPending) {
+			t.Fatalf("expected ErrInternalApprovalPending, got %v", err)
+		}
+	})
+
+	t.Run("issues link within estimate validity", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		links := mock_interfaces.NewMockIApprovalLinkRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		signer := mock_interfaces.NewMockILinkSigner(ctrl)
+		notifier := mock_interfaces.NewMockINotifier(ctrl)
+		var stored entities.ApprovalLink
+		var sent []entities.Notification
+		expectLinkStore(links, signer, notifier, &stored, &sent)
+		estimateUC := NewEstimateUseCase(estRepo)
+		estimateUC.now = func() time.Time { return approvalNow }
+		uc := NewEstimateApprovalUseCase(links, estimateUC, signer, notifier).WithBaseURL("https://oficina.example.com/aprovacao/")
+		uc.now = func() time.Time { return approvalNow }
+		e := pendingEstimate()
+		estRepo.EXPECT().GetByID(gomock.Any(), "est-1").Return(e, nil)
+		links.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, l entities.ApprovalLink) (entities.ApprovalLink, error) {
+			return l, nil
+		})
+		signer.EXPECT().Sign(gomock.Any(), e.ExpiresAt.UTC()).Return("tok-1")
+
+		issued, err := uc.CreateLink(context.Background(), "est-1", ApprovalLinkRequest{
+			Channel: entities.NotificationChannelSMS, Recipient: "+55 (11) 98765-4321", CreatedBy: "atendente",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if issued.URL != "https://oficina.example.com/aprovacao/tok-1" || issued.Link.Recipient != "5511987654321" || !issued.Link.ExpiresAt.Equal(*e.ExpiresAt) {
+			t.Fatalf("unexpected link: %+v", issued)
+		}
+		if len(sent) != 1 || !strings.Contains(sent[0].Body, issued.URL) {
+			t.Fatalf("expected link notification, got %+v", sent)
+		}
+	})
+}
+
+func TestEstimateApprovalUseCase_Decide(t *testing.T) {
+	t.Run("invalid token and action", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		links := mock_interfaces.NewMockIApprovalLinkRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		signer := mock_interfaces.NewMockILinkSigner(ctrl)
+		notifier := mock_interfaces.NewMockINotifier(ctrl)
+		var stored entities.ApprovalLink
+		var sent []entities.Notification
+		expectLinkStore(links, signer, notifier, &stored, &sent)
+		estimateUC := NewEstimateUseCase(estRepo)
+		estimateUC.now = func() time.Time { return approvalNow }
+		uc := NewEstimateApprovalUseCase(links, estimateUC, signer, notifier).WithBaseURL("https://oficina.example.com/aprovacao/")
+		uc.now = func() time.Time { return approvalNow }
+		if _, err := uc.View(context.Background(), "forged"); !errors.Is(err, ErrInvalidApprovalToken) {
+			t.Fatalf("expected ErrInvalidApprovalToken, got %v", err)
+		}
+		if _, err := uc.Decide(context.Background(), "tok-1", CustomerDecision{Action: "cancelar"}); !errors.Is(err, ErrInvalidApprovalAction) {
+			t.Fatalf("expected ErrInvalidApprovalAction, got %v", err)
+		}
+	})
+
+	t.Run("approves after otp confirmation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		links := mock_interfaces.NewMockIApprovalLinkRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		signer := mock_interfaces.NewMockILinkSigner(ctrl)
+		notifier := mock_interfaces.NewMockINotifier(ctrl)
+		var stored entities.ApprovalLink
+		var sent []entities.Notification
+		expectLinkStore(links, signer, notifier, &stored, &sent)
+		estimateUC := NewEstimateUseCase(estRepo)
+		estimateUC.now = func() time.Time { return approvalNow }
+		uc := NewEstimateApprovalUseCase(links, estimateUC, signer, notifier).WithBaseURL("https://oficina.example.com/aprovacao/")
+		uc.now = func() time.Time { return approvalNow }
+		stored = activeLink()
+		e := pendingEstimate()
+		estRepo.EXPECT().GetByID(gomock.Any(), "est-1").Return(e, nil).AnyTimes()
+
+		if _, err := uc.Decide(context.Background(), "tok-1", CustomerDecision{Action: "aprovar", OTP: "000000"}); !errors.Is(err, ErrOTPNotSent) {
+			t.Fatalf("expected ErrOTPNotSent, got %v", err)
+		}
+		link, err := uc.SendOTP(context.Background(), "tok-1")
+		if err != nil || link.OTPSends != 1 || link.OTPDigest == "" {
+			t.Fatalf("unexpected link: %+v err=%v", link, err)
+		}
+		code := otpCode.FindString(sent[0].Body)
+		if sent[0].Recipient != "11987654321" || code == "" || link.OTPDigest != "digest:link-1:"+code {
+			t.Fatalf("unexpected otp notification: %+v", sent)
+		}
+
+		wrong := "000000"
+		if code == wrong {
+			wrong = "111111"
+		}
+		if _, err := uc.Decide(context.Background(), "tok-1", CustomerDecision{Action: "aprovar", OTP: wrong}); !errors.Is(err, ErrInvalidOTP) {
+			t.Fatalf("expected ErrInvalidOTP, got %v", err)
+		}
+		if stored.FailedAttempts != 1 {
+			t.Fatalf("expected failed attempt recorded, got %+v", stored)
+		}
+
+		estRepo.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(e, nil)
+		evidence := &entities.DecisionEvidence{LinkID: "link-1", IP: "203.0.113.7", UserAgent: "Mozilla/5.0", Recipient: "*******4321"}
+		estRepo.EXPECT().UpdateStatus(gomock.Any(), e, entities.EstimateStatusChange{
+			Status: entities.EstimateStatusAprovado, Actor: "cliente", Evidence: evidence,
+		}).Return(entities.Estimate{ID: "est-1", Status: entities.EstimateStatusAprovado}, nil)
+
+		res, err := uc.Decide(context.Background(), "tok-1", CustomerDecision{Action: "aprovar", OTP: code, IP: "203.0.113.7", UserAgent: "Mozilla/5.0"})
+		if err != nil || res.Status != entities.EstimateStatusAprovado {
+			t.Fatalf("unexpected result: %+v err=%v", res, err)
+		}
+		if stored.UsedAt == nil || stored.Decision != entities.EstimateStatusAprovado || *stored.Evidence != *evidence {
+			t.Fatalf("expected consumed link, got %+v", stored)
+		}
+		if _, err := uc.Decide(context.Background(), "tok-1", CustomerDecision{Action: "aprovar", OTP: code}); !errors.Is(err, ErrApprovalLinkUsed) {
+			t.Fatalf("expected ErrApprovalLinkUsed, got %v", err)
+		}
+	})
+
+	t.Run("failed decision releases the link", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		links := mock_interfaces.NewMockIApprovalLinkRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		signer := mock_interfaces.NewMockILinkSigner(ctrl)
+		notifier := mock_interfaces.NewMockINotifier(ctrl)
+		var stored entities.ApprovalLink
+		var sent []entities.Notification
+		expectLinkStore(links, signer, notifier, &stored, &sent)
+		estimateUC := NewEstimateUseCase(estRepo)
+		estimateUC.now = func() time.Time { return approvalNow }
+		uc := NewEstimateApprovalUseCase(links, estimateUC, signer, notifier).WithBaseURL("https://oficina.example.com/aprovacao/")
+		uc.now = func() time.Time { return approvalNow }
+		otpExpiresAt := approvalNow.Add(5 * time.Minute)
+		stored = activeLink()
+		stored.OTPDigest, stored.OTPExpiresAt = "digest:link-1:123456", &otpExpiresAt
+		estRepo.EXPECT().GetByID(gomock.Any(), "est-1").Return(pendingEstimate(), nil)
+
+		_, err := uc.Decide(context.Background(), "tok-1", CustomerDecision{Action: "rejeitar", OTP: "123456", ReasonCode: "desconhecido"})
+		if !errors.Is(err, ErrUnknownReason) {
+			t.Fatalf("expected ErrUnknownReason, got %v", err)
+		}
+		if stored.UsedAt != nil || stored.Evidence != nil || stored.Decision != "" {
+			t.Fatalf("expected released link, got %+v", stored)
+		}
+	})
+
+	t.Run("locks after too many invalid otps", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		links := mock_interfaces.NewMockIApprovalLinkRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		signer := mock_interfaces.NewMockILinkSigner(ctrl)
+		notifier := mock_interfaces.NewMockINotifier(ctrl)
+		var stored entities.ApprovalLink
+		var sent []entities.Notification
+		expectLinkStore(links, signer, notifier, &stored, &sent)
+		estimateUC := NewEstimateUseCase(estRepo)
+		estimateUC.now = func() time.Time { return approvalNow }
+		uc := NewEstimateApprovalUseCase(links, estimateUC, signer, notifier).WithBaseURL("https://oficina.example.com/aprovacao/")
+		uc.now = func() time.Time { return approvalNow }
+		otpExpiresAt := approvalNow.Add(5 * time.Minute)
+		stored = activeLink()
+		stored.OTPDigest, stored.OTPExpiresAt = "digest:link-1:123456", &otpExpiresAt
+		stored.FailedAttempts = maxOTPAttempts - 1
+		estRepo.EXPECT().GetByID(gomock.Any(), "est-1").Return(pendingEstimate(), nil)
+
+		if _, err := uc.Decide(context.Background(), "tok-1", CustomerDecision{Action: "aprovar", OTP: "654321"}); !errors.Is(err, ErrOTPLocked) {
+			t.Fatalf("expected ErrOTPLocked, got %v", err)
+		}
+		if _, err := uc.SendOTP(context.Background(), "tok-1"); !errors.Is(err, ErrOTPLocked) {
+			t.Fatalf("expected ErrOTPLocked on resend, got %v", err)
+		}
+	})
+
+	t.Run("otp send limit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		links := mock_interfaces.NewMockIApprovalLinkRepository(ctrl)
+		estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		signer := mock_interfaces.NewMockILinkSigner(ctrl)
+		notifier := mock_interfaces.NewMockINotifier(ctrl)
+		var stored entities.ApprovalLink
+		var sent []entities.Notification
+		expectLinkStore(links, signer, notifier, &stored, &sent)
+		estimateUC := NewEstimateUseCase(estRepo)
+		estimateUC.now = func() time.Time { return approvalNow }
+		uc := NewEstimateApprovalUseCase(links, estimateUC, signer, notifier).WithBaseURL("https://oficina.example.com/aprovacao/")
+		uc.now = func() time.Time { return approvalNow }
+		stored = activeLink()
+		stored.OTPSends = maxOTPSends
+		if _, err := uc.SendOTP(context.Background(), "tok-1"); !errors.Is(err, ErrOTPSendLimit) {
+			t.Fatalf("expected ErrOTPSendLimit, got %v", err)
+		}
+	})
+}
+
+func TestEstimateApprovalUseCase_DataProtection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	links := mock_interfaces.NewMockIApprovalLinkRepository(ctrl)
+	estRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+	signer := mock_interfaces.NewMockILinkSigner(ctrl)
+	notifier := mock_interfaces.NewMockINotifier(ctrl)
+	var stored entities.ApprovalLink
+	var sent []entities.Notification
+	expectLinkStore(links, signer, notifier, &stored, &sent)
+	estimateUC := NewEstimateUseCase(estRepo)
+	estimateUC.now = func() time.Time { return approvalNow }
+	uc := NewEstimateApprovalUseCase(links, estimateUC, signer, notifier).WithBaseURL("https://oficina.example.com/aprovacao/")
+	uc.now = func() time.Time { return approvalNow }
+	sealer := mock_interfaces.NewMockIRecordSealer(ctrl)
+	index := mock_interfaces.NewMockIBlindIndex(ctrl)
+	uc.WithDataProtection(sealer, index)
+	index.EXPECT().Hash(gomock.Any()).DoAndReturn(func(v string) string { return "h:" + v }).AnyTimes()
+	key := &entities.EncryptedDataKey{KeyID: "k1"}
+	sealer.EXPECT().Seal(gomock.Any(), "link-1", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, values ...*string) (*entities.EncryptedDataKey, error) {
+		for _, v := range values {
+			*v = entities.EncryptedValuePrefix + *v
+		}
+		return key, nil
+	}).AnyTimes()
+	sealer.EXPECT().Open(gomock.Any(), "link-1", key, gomock.Any()).DoAndReturn(func(_ context.Context, _ string, _ *entities.EncryptedDataKey, values ...*string) error {
+		for _, v := range values {
+			*v = strings.TrimPrefix(*v, entities.EncryptedValuePrefix)
+		}
+		return nil
+	}).AnyTimes()
+
+	// Stored sealed, used in plaintext: the OTP goes to the real number.
+	stored, _ = uc.seal(context.Background(), activeLink())
+	estRepo.EXPECT().GetByID(gomock.Any(), "est-1").Return(pendingEstimate(), nil).AnyTimes()
+	if _, err := uc.SendOTP(context.Background(), "tok-1"); err != nil || sent[0].Recipient != "11987654321" {
+		t.Fatalf("unexpected otp send %+v err=%v", sent, err)
+	}
+	if stored.Recipient != entities.EncryptedValuePrefix+"11987654321" || stored.RecipientHash != "h:11987654321" || stored.DataKey != key {
+		t.Fatalf("expected sealed link, got %+v", stored)
+	}
+
+	consumed := stored
+	consumed.Evidence = &entities.DecisionEvidence{LinkID: "link-1", IP: "203.0.113.7", UserAgent: "Mozilla/5.0", Recipient: "*******4321"}
+	sealed, _ := uc.seal(context.Background(), consumed)
+	if !entities.IsEncrypted(sealed.Evidence.IP) || !entities.IsEncrypted(sealed.Evidence.UserAgent) || sealed.Evidence.Recipient != "*******4321" || consumed.Evidence.IP != "203.0.113.7" {
+		t.Fatalf("expected sealed evidence copy, got %+v (source %+v)", sealed.Evidence, consumed.Evidence)
+	}
+
+	// Data subject requests find the link by phone and erase it.
+	links.EXPECT().ListByRecipientHash(gomock.Any(), "h:11987654321").Return([]entities.ApprovalLink{stored}, nil)
+	found, err := uc.FindByRecipient(context.Background(), "", "(11) 98765-4321")
+	if err != nil || len(found) != 1 || found[0].Recipient != "11987654321" {
+		t.Fatalf("unexpected lookup %+v err=%v", found, err)
+	}
+	links.EXPECT().Anonymize(gomock.Any(), found[0], approvalNow).DoAndReturn(func(_ context.Context, _ entities.ApprovalLink, at time.Time) error {
+		stored.AnonymizedAt = &at
+		return nil
+	})
+	if err := uc.AnonymizeLink(context.Background(), found[0], approvalNow); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if _, err := uc.View(context.Background(), "tok-1"); !errors.Is(err, ErrInvalidApprovalToken) {
+		t.Fatalf("expected anonymized link disabled, got %v", err)
+	}
+}
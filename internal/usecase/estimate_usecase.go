@@ -3,8 +3,11 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
 	"mecanica_xpto/internal/domain/entities"
 	"mecanica_xpto/internal/usecase/interfaces"
+	"sort"
 	"strings"
 	"time"
 
@@ -17,43 +20,220 @@ var (
 	ErrInvalidOSID           = errors.New("invalid os_id")
 	ErrInvalidEstimateID     = errors.New("invalid estimate id")
 	ErrInvalidEstimateVal    = errors.New("invalid estimate value")
+	ErrEstimateNotPending    = errors.New("estimate not pending")
+	ErrInvalidDiscount       = errors.New("invalid discount")
+	ErrDiscountNotApplicable = errors.New("discount not applicable to the estimate")
+	ErrDiscountExceedsPrice  = errors.New("discounts exceed the estimate price")
+	ErrCouponNotFound        = errors.New("coupon not found")
+	ErrCouponNeedsCustomer   = errors.New("coupon requires a customer")
+	ErrEstimateClosed        = errors.New("estimate rejected or canceled")
+	ErrUnknownSkillTier      = errors.New("unknown skill tier")
+	ErrInvalidLaborHours     = errors.New("invalid labor hours")
+	ErrEstimateExpired       = errors.New("estimate expired")
+	ErrInvalidValidity       = errors.New("invalid validity days")
+	ErrUnknownReason         = errors.New("unknown reason code")
+	ErrInvalidReasonText     = errors.New("invalid reason text")
+	ErrUnknownEstimateItem   = errors.New("unknown estimate item")
+
+	ErrEstimateTransitionNotAllowed = errors.New("estimate status transition not allowed")
+
+	ErrInternalApprovalPending   = errors.New("estimate awaits internal approval")
+	ErrNoInternalApprovalPending = errors.New("estimate does not await internal approval")
+	ErrInvalidInternalDecision   = errors.New("invalid internal approval decision")
 )
 
+// CatalogDeviationError reports the estimate items that do not match the catalog when
+// validating in reject mode. It unwraps to entities.ErrCatalogDeviation.
+type CatalogDeviationError struct {
+	Deviations []entities.CatalogDeviation
+}
+
+func (e *CatalogDeviationError) Error() string {
+	items := make([]string, 0, len(e.Deviations))
+	for _, d := range e.Deviations {
+		name := d.Code
+		if name == "" {
+			name = d.Name
+		}
+		items = append(items, fmt.Sprintf("%s (%s)", name, d.Reason))
+	}
+	return fmt.Sprintf("%s: %s", entities.ErrCatalogDeviation, strings.Join(items, ", "))
+}
+
+func (e *CatalogDeviationError) Unwrap() error {
+	return entities.ErrCatalogDeviation
+}
+
+// EstimatePricing is what an estimate is priced from: the gross price (the sum of the
+// items, when given), the items, and the coupons and manual discounts to apply.
+// CustomerSegment (e.g. "frota") is matched by the pricing rules. Services quoted in hours
+// are priced by the use case (see WithLaborRates) and added to the gross price.
+// ValidityDays, when positive, overrides the default validity (see WithValidity).
+type EstimatePricing struct {
+	Price           float64
+	Items           []entities.EstimateItem
+	Municipality    string
+	CustomerID      string
+	CustomerSegment string
+	CouponCodes     []string
+	ManualDiscounts []entities.ManualDiscount
+	ValidityDays    int
+}
+
+// StatusDecision is who approves, rejects or cancels an estimate and why. ReasonCode is a
+// code of the reason catalog (see WithReasonCatalog) for the target status; without it no
+// reason is recorded. ItemIDs are the items approved (see ApproveByOSID) and Evidence how
+// the customer confirmed the decision, when made through an approval link.
+type StatusDecision struct {
+	Actor      string
+	ReasonCode string
+	ReasonText string
+	ItemIDs    []string
+	Evidence   *entities.DecisionEvidence
+}
+
+// InternalDecision is a manager's answer to an internal approval request: Action is
+// "aprovar" or "rejeitar" (the latter needs a Comment saying what to revise).
+type InternalDecision struct {
+	Action   string
+	Approver string
+	Comment  string
+}
+
 // IEstimateUseCase exposes billing estimate operations.
 //
 // These operations directly map to the draw.io requirements:
 //   - "Calcula Orçamento" => CalculateEstimate()
 //   - PATCH /os/{id}/estimate (acao aprovar/rejeitar/cancelar) => UpdateStatusByOSAction()
 //   - "Recalcula Orçamento Total" => UpdateEstimatePrice()
+//   - Actual hours of the finished OS => RecordActualHours()
+//   - Expired estimates => RenewEstimate(); ExpireDue() is run by the expiration sweeper
+//   - Manager sign-off above the threshold => ListAwaitingInternalApproval(),
+//     DecideInternalApproval()
 
 type IEstimateUseCase interface {
-	CalculateEstimate(ctx context.Context, osID string, price float64) (entities.Estimate, error)
-	ApproveByOSID(ctx context.Context, osID string) (entities.Estimate, error)
-	RejectByOSID(ctx context.Context, osID string) (entities.Estimate, error)
-	CancelByOSID(ctx context.Context, osID string) (entities.Estimate, error)
-	UpdateEstimatePrice(ctx context.Context, estimateID string, newPrice float64) (entities.Estimate, error)
+	CalculateEstimate(ctx context.Context, osID string, pricing EstimatePricing) (entities.Estimate, error)
+	PreviewEstimate(ctx context.Context, osID string, pricing EstimatePricing) (entities.Estimate, error)
+	ApproveByOSID(ctx context.Context, osID string, decision StatusDecision) (entities.Estimate, error)
+	RejectByOSID(ctx context.Context, osID string, decision StatusDecision) (entities.Estimate, error)
+	CancelByOSID(ctx context.Context, osID string, decision StatusDecision) (entities.Estimate, error)
+	ReasonCatalog() entities.ReasonCatalog
+	UpdateEstimatePrice(ctx context.Context, estimateID string, pricing EstimatePricing) (entities.Estimate, error)
+	RecordActualHours(ctx context.Context, estimateID string, hours map[string]float64) (entities.Estimate, error)
+	RenewEstimate(ctx context.Context, estimateID string, pricing EstimatePricing) (entities.Estimate, error)
+	ExpireDue(ctx context.Context) (int, error)
+	ListAwaitingInternalApproval(ctx context.Context) ([]entities.Estimate, error)
+	DecideInternalApproval(ctx context.Context, estimateID string, decision InternalDecision) (entities.Estimate, error)
 	GetByID(ctx context.Context, id string) (entities.Estimate, error)
 	GetByOSID(ctx context.Context, osID string) (entities.Estimate, error)
 }
 
 type EstimateUseCase struct {
-	repo interfaces.IEstimateRepository
+	repo        interfaces.IEstimateRepository
+	conversions interfaces.IEstimateConversionRepository
+	invoices    IInvoiceUseCase
+	taxes       *entities.TaxTable
+	coupons     interfaces.ICouponRepository
+	rules       *entities.PricingRuleSet
+	catalog     interfaces.ICatalogRepository
+	policy      entities.CatalogPolicy
+	labor       *entities.LaborRateTable
+	validity    int
+	reasons     entities.ReasonCatalog
+	threshold   float64
+	now         func() time.Time
 }
 
 var _ IEstimateUseCase = (*EstimateUseCase)(nil)
 
 func NewEstimateUseCase(repo interfaces.IEstimateRepository) *EstimateUseCase {
-	return &EstimateUseCase{repo: repo}
+	return &EstimateUseCase{repo: repo, reasons: entities.DefaultReasonCatalog(), now: time.Now}
+}
+
+// WithConversionProjection keeps the analytics read model up to date on every change.
+func (u *EstimateUseCase) WithConversionProjection(c interfaces.IEstimateConversionRepository) *EstimateUseCase {
+	u.conversions = c
+	return u
+}
+
+// WithInvoicing issues the invoice of an estimate when it is approved.
+func (u *EstimateUseCase) WithInvoicing(i IInvoiceUseCase) *EstimateUseCase {
+	u.invoices = i
+	return u
+}
+
+// WithTaxes computes the tax breakdown of new estimates with the given rates.
+func (u *EstimateUseCase) WithTaxes(t entities.TaxTable) *EstimateUseCase {
+	u.taxes = &t
+	return u
+}
+
+// WithCoupons enables coupon discounts, redeemed with the estimate write.
+func (u *EstimateUseCase) WithCoupons(c interfaces.ICouponRepository) *EstimateUseCase {
+	u.coupons = c
+	return u
+}
+
+// WithPricingRules adjusts the items of estimates with the given rules before discounts.
+func (u *EstimateUseCase) WithPricingRules(rules entities.PricingRuleSet) *EstimateUseCase {
+	u.rules = &rules
+	return u
+}
+
+// WithCatalog checks the items of estimates against the price catalog: deviations are
+// recorded on the estimate (flag mode) or reject it (reject mode).
+func (u *EstimateUseCase) WithCatalog(c interfaces.ICatalogRepository, policy entities.CatalogPolicy) *EstimateUseCase {
+	u.catalog = c
+	u.policy = policy
+	return u
+}
+
+// WithLaborRates prices the services quoted in hours with the hourly rate of their skill
+// tier. Without it such services are rejected.
+func (u *EstimateUseCase) WithLaborRates(rates entities.LaborRateTable) *EstimateUseCase {
+	u.labor = &rates
+	return u
+}
+
+// WithValidity makes new and renewed estimates expire the given number of days after
+// being priced, unless the request gives its own validity. 0 keeps them valid until
+// decided.
+func (u *EstimateUseCase) WithValidity(days int) *EstimateUseCase {
+	u.validity = days
+	return u
 }
 
-func (u *EstimateUseCase) CalculateEstimate(ctx context.Context, osID string, price float64) (entities.Estimate, error) {
+// WithReasonCatalog replaces the default reasons estimates are rejected or canceled with.
+func (u *EstimateUseCase) WithReasonCatalog(c entities.ReasonCatalog) *EstimateUseCase {
+	u.reasons = c
+	return u
+}
+
+// WithInternalApproval makes estimates priced above threshold wait for a manager's
+// sign-off before the customer can approve them; 0 disables it.
+func (u *EstimateUseCase) WithInternalApproval(threshold float64) *EstimateUseCase {
+	u.threshold = threshold
+	return u
+}
+
+// CalculateEstimate creates the estimate of an OS. Items (optional) are stored with their
+// share of the discounts and their taxes, computed with the rates effective now for the
+// municipality (IBGE code).
+func (u *EstimateUseCase) CalculateEstimate(ctx context.Context, osID string, pricing EstimatePricing) (entities.Estimate, error) {
 	osID = strings.TrimSpace(osID)
 	if osID == "" {
 		return entities.Estimate{}, ErrInvalidOSID
 	}
-	if price <= 0 {
+	pricing, err := u.priceLabor(pricing)
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	if pricing.Price <= 0 {
 		return entities.Estimate{}, ErrInvalidEstimateVal
 	}
+	if pricing.ValidityDays < 0 {
+		return entities.Estimate{}, ErrInvalidValidity
+	}
 
 	// Enforce: 1 estimate per OS.
 	if existing, err := u.repo.GetByOSID(ctx, osID); err != nil {
@@ -62,65 +242,715 @@ func (u *EstimateUseCase) CalculateEstimate(ctx context.Context, osID string, pr
 		return entities.Estimate{}, ErrEstimateAlreadyExists
 	}
 
-	now := time.Now().UTC()
-	e := entities.Estimate{
-		ID:        uuid.NewString(),
-		OSID:      osID,
-		Price:     price,
-		Status:    entities.EstimateStatusPendente,
-		CreatedAt: now,
-		UpdatedAt: now,
+	e := u.newEstimate(osID, pricing)
+	if err := u.checkCatalog(ctx, &e, pricing.Items); err != nil {
+		return entities.Estimate{}, err
+	}
+	redemptions, err := u.applyPricing(ctx, &e, pricing)
+	if err != nil {
+		return entities.Estimate{}, err
 	}
-	return u.repo.Create(ctx, e)
+	u.requireInternalApproval(&e)
+
+	var created entities.Estimate
+	if len(redemptions) > 0 {
+		created, err = u.repo.CreateWithRedemptions(ctx, e, redemptions)
+	} else {
+		created, err = u.repo.Create(ctx, e)
+	}
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	u.project(ctx, created)
+	return created, nil
 }
 
-func (u *EstimateUseCase) ApproveByOSID(ctx context.Context, osID string) (entities.Estimate, error) {
-	return u.updateStatusByOSID(ctx, osID, entities.EstimateStatusAprovado)
+// PreviewEstimate prices an estimate as CalculateEstimate would, without storing it nor
+// redeeming coupons, so pricing rules and discounts can be checked beforehand.
+func (u *EstimateUseCase) PreviewEstimate(ctx context.Context, osID string, pricing EstimatePricing) (entities.Estimate, error) {
+	osID = strings.TrimSpace(osID)
+	if osID == "" {
+		return entities.Estimate{}, ErrInvalidOSID
+	}
+	pricing, err := u.priceLabor(pricing)
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	if pricing.Price <= 0 {
+		return entities.Estimate{}, ErrInvalidEstimateVal
+	}
+	if pricing.ValidityDays < 0 {
+		return entities.Estimate{}, ErrInvalidValidity
+	}
+
+	e := u.newEstimate(osID, pricing)
+	e.ID = ""
+	if err := u.checkCatalog(ctx, &e, pricing.Items); err != nil {
+		return entities.Estimate{}, err
+	}
+	if _, err := u.applyPricing(ctx, &e, pricing); err != nil {
+		return entities.Estimate{}, err
+	}
+	u.requireInternalApproval(&e)
+	return e, nil
 }
 
-func (u *EstimateUseCase) RejectByOSID(ctx context.Context, osID string) (entities.Estimate, error) {
-	return u.updateStatusByOSID(ctx, osID, entities.EstimateStatusRejeitado)
+func (u *EstimateUseCase) newEstimate(osID string, pricing EstimatePricing) entities.Estimate {
+	now := u.now().UTC()
+	return entities.Estimate{
+		ID:           uuid.NewString(),
+		OSID:         osID,
+		Status:       entities.EstimateStatusPendente,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		Municipality: strings.TrimSpace(pricing.Municipality),
+		CustomerID:   strings.TrimSpace(pricing.CustomerID),
+		ExpiresAt:    u.expiresAt(now, pricing.ValidityDays),
+	}
 }
 
-func (u *EstimateUseCase) CancelByOSID(ctx context.Context, osID string) (entities.Estimate, error) {
-	return u.updateStatusByOSID(ctx, osID, entities.EstimateStatusCancelado)
+// expiresAt is the end of the validity of an estimate priced at pricedAt, nil when it
+// does not expire.
+func (u *EstimateUseCase) expiresAt(pricedAt time.Time, days int) *time.Time {
+	if days <= 0 {
+		days = u.validity
+	}
+	if days <= 0 {
+		return nil
+	}
+	at := pricedAt.AddDate(0, 0, days)
+	return &at
 }
 
-func (u *EstimateUseCase) updateStatusByOSID(ctx context.Context, osID string, status entities.EstimateStatus) (entities.Estimate, error) {
-	osID = strings.TrimSpace(osID)
-	if osID == "" {
-		return entities.Estimate{}, ErrInvalidOSID
+// ApproveByOSID approves the estimate of an OS, unless it expired (see RenewEstimate) or
+// still awaits internal approval.
+// decision.ItemIDs, when given, are the items the customer approved: the others are
+// declined and the estimate is priced again with the approved ones only (see
+// approveItems). Without them, or with every item, the whole estimate is approved.
+func (u *EstimateUseCase) ApproveByOSID(ctx context.Context, osID string, decision StatusDecision) (entities.Estimate, error) {
+	change, err := u.decide(entities.EstimateStatusAprovado, decision)
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	current, err := u.GetByOSID(ctx, osID)
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	if current.AwaitsInternalApproval() {
+		return entities.Estimate{}, ErrInternalApprovalPending
+	}
+	if current.IsExpired(u.now()) {
+		return entities.Estimate{}, ErrEstimateExpired
 	}
 
-	updated, err := u.repo.UpdateStatusByOSID(ctx, osID, status)
+	approved := map[string]bool{}
+	for _, id := range decision.ItemIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			approved[id] = true
+		}
+	}
+	if len(approved) == 0 {
+		return u.updateStatus(ctx, current, change)
+	}
+	return u.approveItems(ctx, current, approved, change)
+}
+
+// approveItems approves the items of a pending estimate in approved (by ID) and declines
+// the others. The approved items are priced again as RecordActualHours does, keeping the
+// discounts and the catalog check of the quote, so the price is what the customer owes for
+// them; the status change is written with the new pricing, only if the estimate did not
+// change since read.
+func (u *EstimateUseCase) approveItems(ctx context.Context, current entities.Estimate, approved map[string]bool, change entities.EstimateStatusChange) (entities.Estimate, error) {
+	if current.Status != entities.EstimateStatusPendente {
+		return entities.Estimate{}, ErrEstimateNotPending
+	}
+
+	pricing := EstimatePricing{CustomerSegment: current.CustomerSegment}
+	var declined []entities.EstimateItem
+	found := map[string]bool{}
+	for _, line := range current.Items {
+		if line.ID == "" || !approved[line.ID] {
+			declined = append(declined, line)
+			continue
+		}
+		found[line.ID] = true
+		item := line.Quoted()
+		pricing.Price += item.Total
+		pricing.Items = append(pricing.Items, item)
+	}
+	if len(found) != len(approved) {
+		return entities.Estimate{}, ErrUnknownEstimateItem
+	}
+	if len(declined) == 0 {
+		return u.updateStatus(ctx, current, change)
+	}
+
+	now := u.now().UTC()
+	e := current
+	e.UpdatedAt = now
+	if _, err := u.applyPricing(ctx, &e, pricing); err != nil {
+		return entities.Estimate{}, err
+	}
+	e.Status = entities.EstimateStatusAprovado
+	e.ApprovedAt = &now
+	e.Reason, e.DecidedBy = nil, change.Actor
+	e.DeclinedItems = declined
+	change.At = now
+	e.History = append(append([]entities.EstimateStatusChange(nil), current.History...), change)
+
+	updated, err := u.repo.UpdatePricing(ctx, e, current.UpdatedAt, nil)
 	if err != nil {
 		return entities.Estimate{}, err
 	}
-	if updated.ID == "" {
-		return entities.Estimate{}, ErrEstimateNotFound
+	u.project(ctx, updated)
+	u.issueInvoice(ctx, updated)
+	return updated, nil
+}
+
+// RejectByOSID rejects the estimate of an OS, recording the reason and who rejected it.
+func (u *EstimateUseCase) RejectByOSID(ctx context.Context, osID string, decision StatusDecision) (entities.Estimate, error) {
+	change, err := u.decide(entities.EstimateStatusRejeitado, decision)
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	current, err := u.GetByOSID(ctx, osID)
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	return u.updateStatus(ctx, current, change)
+}
+
+// CancelByOSID cancels the estimate of an OS, recording the reason and who canceled it.
+func (u *EstimateUseCase) CancelByOSID(ctx context.Context, osID string, decision StatusDecision) (entities.Estimate, error) {
+	change, err := u.decide(entities.EstimateStatusCancelado, decision)
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	current, err := u.GetByOSID(ctx, osID)
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	return u.updateStatus(ctx, current, change)
+}
+
+// ReasonCatalog returns the reasons estimates can be rejected or canceled with.
+func (u *EstimateUseCase) ReasonCatalog() entities.ReasonCatalog {
+	return u.reasons
+}
+
+// decide checks the reason of a decision against the catalog of the status.
+func (u *EstimateUseCase) decide(status entities.EstimateStatus, decision StatusDecision) (entities.EstimateStatusChange, error) {
+	change := entities.EstimateStatusChange{Status: status, Actor: strings.TrimSpace(decision.Actor), Evidence: decision.Evidence}
+	if strings.TrimSpace(decision.ReasonCode) == "" {
+		if strings.TrimSpace(decision.ReasonText) != "" {
+			return entities.EstimateStatusChange{}, ErrUnknownReason
+		}
+		return change, nil
+	}
+	code, ok := u.reasons.Lookup(status, decision.ReasonCode)
+	if !ok {
+		return entities.EstimateStatusChange{}, ErrUnknownReason
+	}
+	reason, ok := code.Reason(decision.ReasonText)
+	if !ok {
+		return entities.EstimateStatusChange{}, ErrInvalidReasonText
+	}
+	change.Reason = &reason
+	return change, nil
+}
+
+// updateStatus writes change on current, only if it did not change since read
+// (entities.ErrEstimateChanged otherwise). A change current cannot make (see
+// entities.EstimateStatus.CanTransitionTo) fails with ErrEstimateTransitionNotAllowed.
+func (u *EstimateUseCase) updateStatus(ctx context.Context, current entities.Estimate, change entities.EstimateStatusChange) (entities.Estimate, error) {
+	if !current.Status.CanTransitionTo(change.Status) {
+		return entities.Estimate{}, ErrEstimateTransitionNotAllowed
+	}
+	updated, err := u.repo.UpdateStatus(ctx, current, change)
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	u.project(ctx, updated)
+	if change.Status == entities.EstimateStatusAprovado {
+		u.issueInvoice(ctx, updated)
 	}
 	return updated, nil
 }
 
-func (u *EstimateUseCase) UpdateEstimatePrice(ctx context.Context, estimateID string, newPrice float64) (entities.Estimate, error) {
+// issueInvoice issues the invoice of an approved estimate. The approval is already
+// persisted, so a failure is logged and left to the repair-invoices command (or to the
+// OS close, which issues it again).
+func (u *EstimateUseCase) issueInvoice(ctx context.Context, e entities.Estimate) {
+	if u.invoices == nil {
+		return
+	}
+	if _, err := u.invoices.Issue(ctx, e.ID, ""); err != nil {
+		log.Printf("[estimate][usecase] invoice issue failed estimate_id=%s err=%v (run repair-invoices)", e.ID, err)
+	}
+}
+
+// UpdateEstimatePrice recalculates a pending estimate from a new gross price and items.
+// Its discounts are applied again to the new total (coupons are not redeemed twice) along
+// with the new ones, and taxes use the rates effective when the estimate was priced
+// (created or renewed). Expired estimates must be renewed instead. An estimate awaiting
+// internal approval can be revised too, which submits the new price for sign-off.
+func (u *EstimateUseCase) UpdateEstimatePrice(ctx context.Context, estimateID string, pricing EstimatePricing) (entities.Estimate, error) {
 	estimateID = strings.TrimSpace(estimateID)
 	if estimateID == "" {
 		return entities.Estimate{}, ErrInvalidEstimateID
 	}
-	if newPrice <= 0 {
+	pricing, err := u.priceLabor(pricing)
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	if pricing.Price <= 0 {
 		return entities.Estimate{}, ErrInvalidEstimateVal
 	}
+	if pricing.ValidityDays < 0 {
+		return entities.Estimate{}, ErrInvalidValidity
+	}
 
-	updated, err := u.repo.UpdatePriceByID(ctx, estimateID, newPrice)
+	current, err := u.GetByID(ctx, estimateID)
 	if err != nil {
 		return entities.Estimate{}, err
 	}
-	if updated.ID == "" {
-		return entities.Estimate{}, ErrEstimateNotFound
+	if current.IsExpired(u.now()) {
+		return entities.Estimate{}, ErrEstimateExpired
+	}
+	if current.Status != entities.EstimateStatusPendente && !current.AwaitsInternalApproval() {
+		return entities.Estimate{}, ErrEstimateNotPending
+	}
+	e := current
+	e.UpdatedAt = u.now().UTC()
+	return u.reprice(ctx, current, e, pricing)
+}
+
+// RenewEstimate reissues a pending or expired estimate from a new gross price and items:
+// it is priced again as UpdateEstimatePrice does, but with the pricing rules and tax
+// rates effective now, and is pending for a new validity period.
+func (u *EstimateUseCase) RenewEstimate(ctx context.Context, estimateID string, pricing EstimatePricing) (entities.Estimate, error) {
+	estimateID = strings.TrimSpace(estimateID)
+	if estimateID == "" {
+		return entities.Estimate{}, ErrInvalidEstimateID
+	}
+	pricing, err := u.priceLabor(pricing)
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	if pricing.Price <= 0 {
+		return entities.Estimate{}, ErrInvalidEstimateVal
+	}
+	if pricing.ValidityDays < 0 {
+		return entities.Estimate{}, ErrInvalidValidity
+	}
+
+	current, err := u.GetByID(ctx, estimateID)
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	if current.Status != entities.EstimateStatusPendente && current.Status != entities.EstimateStatusExpirado && !current.AwaitsInternalApproval() {
+		return entities.Estimate{}, ErrEstimateNotPending
+	}
+	now := u.now().UTC()
+	e := current
+	e.Status = entities.EstimateStatusPendente
+	e.UpdatedAt = now
+	e.RenewedAt = &now
+	e.ExpiresAt = u.expiresAt(now, pricing.ValidityDays)
+	e.History = append(append([]entities.EstimateStatusChange(nil), current.History...),
+		entities.EstimateStatusChange{Status: entities.EstimateStatusPendente, At: now})
+	return u.reprice(ctx, current, e, pricing)
+}
+
+// reprice prices e, a change of current, from pricing and writes it if current was not
+// changed meanwhile. The new price goes through the internal approval threshold again.
+func (u *EstimateUseCase) reprice(ctx context.Context, current, e entities.Estimate, pricing EstimatePricing) (entities.Estimate, error) {
+	if e.Municipality == "" {
+		e.Municipality = strings.TrimSpace(pricing.Municipality)
+	}
+	if e.CustomerID == "" {
+		e.CustomerID = strings.TrimSpace(pricing.CustomerID)
+	}
+	if err := u.checkCatalog(ctx, &e, pricing.Items); err != nil {
+		return entities.Estimate{}, err
 	}
+	redemptions, err := u.applyPricing(ctx, &e, pricing)
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	u.requireInternalApproval(&e)
+
+	updated, err := u.repo.UpdatePricing(ctx, e, current.UpdatedAt, redemptions)
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	u.project(ctx, updated)
 	return updated, nil
 }
 
+// requireInternalApproval puts an estimate priced above the threshold in
+// aguardando_aprovacao, unless a manager already signed off a price at least as high, and
+// sends one that no longer needs the sign-off back to pendente. A new estimate starts its
+// history with the resulting status.
+func (u *EstimateUseCase) requireInternalApproval(e *entities.Estimate) {
+	if e.Status != entities.EstimateStatusPendente && !e.AwaitsInternalApproval() {
+		return
+	}
+	at, previous := e.UpdatedAt, e.Status
+	needed := u.threshold > 0 && e.Price > u.threshold
+	switch {
+	case needed && e.InternalApproval != nil && e.InternalApproval.Covers(e.Price):
+	case needed:
+		e.InternalApproval = &entities.InternalApproval{Threshold: u.threshold, Price: e.Price, RequestedAt: at}
+		e.Status = entities.EstimateStatusAguardandoAprovacao
+	case e.AwaitsInternalApproval():
+		e.InternalApproval = nil
+		e.Status = entities.EstimateStatusPendente
+	}
+	if len(e.History) == 0 {
+		e.History = []entities.EstimateStatusChange{{Status: e.Status, At: at}}
+	} else if e.Status != previous {
+		e.History = append(append([]entities.EstimateStatusChange(nil), e.History...),
+			entities.EstimateStatusChange{Status: e.Status, At: at})
+	}
+}
+
+// ListAwaitingInternalApproval returns the estimates waiting for a manager's sign-off,
+// oldest request first. Those sent back for revision are left out until revised.
+func (u *EstimateUseCase) ListAwaitingInternalApproval(ctx context.Context) ([]entities.Estimate, error) {
+	awaiting, err := u.repo.ListByStatus(ctx, entities.EstimateStatusAguardandoAprovacao)
+	if err != nil {
+		return nil, err
+	}
+	queue := make([]entities.Estimate, 0, len(awaiting))
+	for _, e := range awaiting {
+		if e.InternalApproval != nil && e.InternalApproval.IsPending() {
+			queue = append(queue, e)
+		}
+	}
+	sort.SliceStable(queue, func(i, j int) bool {
+		return queue[i].InternalApproval.RequestedAt.Before(queue[j].InternalApproval.RequestedAt)
+	})
+	return queue, nil
+}
+
+// DecideInternalApproval records a manager's sign-off of an estimate awaiting internal
+// approval. Approved, it becomes pendente and the customer can approve it; rejected, it
+// keeps waiting until revised (UpdateEstimatePrice) or canceled. Both are recorded in the
+// history with the approver and the comment, only if the estimate did not change since
+// read.
+func (u *EstimateUseCase) DecideInternalApproval(ctx context.Context, estimateID string, decision InternalDecision) (entities.Estimate, error) {
+	approver := strings.TrimSpace(decision.Approver)
+	comment := strings.TrimSpace(decision.Comment)
+	var outcome entities.InternalApprovalDecision
+	switch strings.ToLower(strings.TrimSpace(decision.Action)) {
+	case ApprovalActionApprove:
+		outcome = entities.InternalApprovalAprovado
+	case ApprovalActionReject:
+		outcome = entities.InternalApprovalRejeitado
+	}
+	if outcome == "" || approver == "" || (outcome == entities.InternalApprovalRejeitado && comment == "") {
+		return entities.Estimate{}, ErrInvalidInternalDecision
+	}
+
+	current, err := u.GetByID(ctx, estimateID)
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	if !current.AwaitsInternalApproval() || current.InternalApproval == nil || !current.InternalApproval.IsPending() {
+		return entities.Estimate{}, ErrNoInternalApprovalPending
+	}
+
+	now := u.now().UTC()
+	e := current
+	e.UpdatedAt = now
+	signOff := *current.InternalApproval
+	signOff.Decision, signOff.Approver, signOff.Comment, signOff.DecidedAt = outcome, approver, comment, &now
+	e.InternalApproval = &signOff
+	if outcome == entities.InternalApprovalAprovado {
+		e.Status = entities.EstimateStatusPendente
+	}
+	e.History = append(append([]entities.EstimateStatusChange(nil), current.History...),
+		entities.EstimateStatusChange{Status: e.Status, At: now, Actor: approver, Comment: comment})
+
+	updated, err := u.repo.UpdatePricing(ctx, e, current.UpdatedAt, nil)
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	u.project(ctx, updated)
+	return updated, nil
+}
+
+// ExpireDue expires the pending estimates past their validity and returns how many were
+// expired. An estimate approved or renewed meanwhile is left alone, so it is safe to run
+// from several instances at once.
+func (u *EstimateUseCase) ExpireDue(ctx context.Context) (int, error) {
+	pending, err := u.repo.ListByStatus(ctx, entities.EstimateStatusPendente)
+	if err != nil {
+		return 0, err
+	}
+	now := u.now().UTC()
+	expired := 0
+	for _, e := range pending {
+		if !e.IsExpired(now) {
+			continue
+		}
+		updated, err := u.repo.Expire(ctx, e.ID, *e.ExpiresAt)
+		if err != nil {
+			return expired, err
+		}
+		if updated.ID == "" {
+			continue
+		}
+		u.project(ctx, updated)
+		expired++
+	}
+	return expired, nil
+}
+
+// RecordActualHours reprices the labor lines of an estimate (by item ID) with the hours
+// actually worked, once the service order finishes. The estimate is priced again from its
+// own items as quoted, keeping its discounts and catalog check (the catalog may have
+// changed since the quote); an invoice already issued is not changed (void it and issue
+// again).
+func (u *EstimateUseCase) RecordActualHours(ctx context.Context, estimateID string, hours map[string]float64) (entities.Estimate, error) {
+	estimateID = strings.TrimSpace(estimateID)
+	if estimateID == "" {
+		return entities.Estimate{}, ErrInvalidEstimateID
+	}
+	if len(hours) == 0 {
+		return entities.Estimate{}, ErrInvalidLaborHours
+	}
+
+	current, err := u.GetByID(ctx, estimateID)
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	if current.Status == entities.EstimateStatusRejeitado || current.Status == entities.EstimateStatusCancelado {
+		return entities.Estimate{}, ErrEstimateClosed
+	}
+
+	pricing := EstimatePricing{CustomerSegment: current.CustomerSegment}
+	recorded := 0
+	for _, line := range current.Items {
+		item := line.Quoted()
+		if h, ok := hours[line.ID]; ok && line.ID != "" {
+			if !line.IsLabor() || h <= 0 {
+				return entities.Estimate{}, ErrInvalidLaborHours
+			}
+			item.ActualHours = h
+			item = item.PriceLabor()
+			recorded++
+		}
+		pricing.Price += item.Total
+		pricing.Items = append(pricing.Items, item)
+	}
+	if recorded != len(hours) {
+		return entities.Estimate{}, ErrInvalidLaborHours
+	}
+
+	e := current
+	e.UpdatedAt = u.now().UTC()
+	if _, err := u.applyPricing(ctx, &e, pricing); err != nil {
+		return entities.Estimate{}, err
+	}
+	updated, err := u.repo.UpdatePricing(ctx, e, current.UpdatedAt, nil)
+	if err != nil {
+		return entities.Estimate{}, err
+	}
+	u.project(ctx, updated)
+	return updated, nil
+}
+
+// priceLabor prices the services quoted in hours at the rate of their skill tier and adds
+// them to the gross price. Lines without an ID get one, so they can be approved on their
+// own and labor lines can record their actual hours.
+func (u *EstimateUseCase) priceLabor(pricing EstimatePricing) (EstimatePricing, error) {
+	items := make([]entities.EstimateItem, 0, len(pricing.Items))
+	for _, item := range pricing.Items {
+		if item.ID == "" {
+			item.ID = uuid.NewString()
+		}
+		if item.Hours == 0 && item.SkillTier == "" {
+			items = append(items, item)
+			continue
+		}
+		if item.Kind != entities.EstimateItemServico || item.Hours <= 0 {
+			return EstimatePricing{}, ErrInvalidLaborHours
+		}
+		ok := false
+		if u.labor != nil {
+			item.SkillTier, item.HourlyRate, ok = u.labor.Rate(item.SkillTier)
+		}
+		if !ok {
+			return EstimatePricing{}, ErrUnknownSkillTier
+		}
+		item.ActualHours = 0
+		item = item.PriceLabor()
+		pricing.Price += item.Total
+		items = append(items, item)
+	}
+	pricing.Items = items
+	return pricing, nil
+}
+
+// applyPricing prices e from pricing: the pricing rules adjust the items, then the
+// discounts e already has and the new ones are applied and the taxes computed. Rule
+// schedules and tax rates are read at PricedAt. It returns the redemptions of the coupons
+// e did not have yet. Items quoted anew are checked against the catalog first
+// (checkCatalog); items already on e keep the check recorded when they were quoted.
+func (u *EstimateUseCase) applyPricing(ctx context.Context, e *entities.Estimate, pricing EstimatePricing) ([]entities.CouponRedemption, error) {
+	discounts := append([]entities.EstimateDiscount(nil), e.Discounts...)
+	added := len(discounts)
+
+	for _, m := range pricing.ManualDiscounts {
+		if !m.IsValid() {
+			return nil, ErrInvalidDiscount
+		}
+		discounts = append(discounts, entities.EstimateDiscount{
+			Source:     entities.DiscountSourceManual,
+			Rule:       m.Rule,
+			Reason:     strings.TrimSpace(m.Reason),
+			ApprovedBy: strings.TrimSpace(m.ApprovedBy),
+		})
+	}
+	redemptions, couponDiscounts, err := u.redeemCoupons(ctx, e, pricing.CouponCodes)
+	if err != nil {
+		return nil, err
+	}
+	discounts = append(discounts, couponDiscounts...)
+
+	gross, items := pricing.Price, pricing.Items
+	e.CustomerSegment = strings.TrimSpace(pricing.CustomerSegment)
+	e.PricingVersion, e.Adjustments, e.AdjustmentTotal = "", nil, 0
+	if u.rules != nil && len(items) > 0 {
+		pc := entities.PricingContext{At: e.PricedAt(), CustomerSegment: pricing.CustomerSegment}
+		items, e.Adjustments, e.AdjustmentTotal = u.rules.Apply(items, pc)
+		e.PricingVersion = u.rules.Version
+		gross += e.AdjustmentTotal
+	}
+
+	e.ApplyPricing(gross, items, discounts)
+	for _, d := range e.Discounts[added:] {
+		if d.Amount <= 0 {
+			return nil, ErrDiscountNotApplicable
+		}
+	}
+	if e.Price <= 0 {
+		return nil, ErrDiscountExceedsPrice
+	}
+
+	e.Taxes, e.TaxTotal = nil, 0
+	if u.taxes != nil && len(e.Items) > 0 {
+		if e.Municipality == "" {
+			e.Municipality = u.taxes.DefaultMunicipality
+		}
+		e.Items, e.Taxes, e.TaxTotal = u.taxes.Apply(e.Items, e.Municipality, e.PricedAt())
+	}
+	return redemptions, nil
+}
+
+// checkCatalog compares the items, as quoted, with the catalog and records the catalog
+// version used and the deviations found on e.
+func (u *EstimateUseCase) checkCatalog(ctx context.Context, e *entities.Estimate, items []entities.EstimateItem) error {
+	e.CatalogVersion, e.CatalogDeviations = 0, nil
+	if u.catalog == nil || u.policy.Mode == "" || u.policy.Mode == entities.CatalogModeOff || len(items) == 0 {
+		return nil
+	}
+	version, err := u.catalog.CurrentVersion(ctx)
+	if err != nil {
+		return err
+	}
+	var deviations []entities.CatalogDeviation
+	for _, item := range items {
+		var entry entities.CatalogItem
+		if item.Code != "" {
+			if entry, err = u.catalog.Get(ctx, item.Kind, item.Code); err != nil {
+				return err
+			}
+		}
+		if d, ok := u.policy.Check(item, entry, entry.Code != ""); ok {
+			deviations = append(deviations, d)
+		}
+	}
+	if len(deviations) > 0 && u.policy.Mode == entities.CatalogModeReject {
+		return &CatalogDeviationError{Deviations: deviations}
+	}
+	e.CatalogVersion, e.CatalogDeviations = version, deviations
+	return nil
+}
+
+// redeemCoupons checks the coupons not yet on e and returns their redemptions and
+// discounts. The checks give early, clear errors; the limits are enforced again by the
+// repository when the redemptions are written.
+func (u *EstimateUseCase) redeemCoupons(ctx context.Context, e *entities.Estimate, codes []string) ([]entities.CouponRedemption, []entities.EstimateDiscount, error) {
+	seen := map[string]bool{}
+	for _, d := range e.Discounts {
+		if d.Source == entities.DiscountSourceCupom {
+			seen[d.CouponCode] = true
+		}
+	}
+
+	var redemptions []entities.CouponRedemption
+	var discounts []entities.EstimateDiscount
+	for _, code := range codes {
+		code = entities.NormalizeCouponCode(code)
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		if u.coupons == nil {
+			return nil, nil, ErrCouponNotFound
+		}
+		c, err := u.coupons.GetByCode(ctx, code)
+		if err != nil {
+			return nil, nil, err
+		}
+		if c.Code == "" {
+			return nil, nil, ErrCouponNotFound
+		}
+		if !c.UsableAt(e.UpdatedAt) {
+			return nil, nil, entities.ErrCouponUnavailable
+		}
+		if c.MaxPerCustomer > 0 {
+			if e.CustomerID == "" {
+				return nil, nil, ErrCouponNeedsCustomer
+			}
+			used, err := u.coupons.CustomerRedemptions(ctx, code, e.CustomerID)
+			if err != nil {
+				return nil, nil, err
+			}
+			if used >= c.MaxPerCustomer {
+				return nil, nil, entities.ErrCouponCustomerLimit
+			}
+		}
+		redemptions = append(redemptions, entities.CouponRedemption{
+			Code:           code,
+			CustomerID:     e.CustomerID,
+			MaxPerCustomer: c.MaxPerCustomer,
+			RedeemedAt:     e.UpdatedAt,
+		})
+		discounts = append(discounts, entities.EstimateDiscount{
+			Source:     entities.DiscountSourceCupom,
+			CouponCode: code,
+			Rule:       c.Rule,
+			Reason:     c.Description,
+		})
+	}
+	return redemptions, discounts, nil
+}
+
+// project updates the analytics read model. The estimate is already persisted, so a
+// projection failure is only logged; the rebuild command repairs the read model.
+func (u *EstimateUseCase) project(ctx context.Context, e entities.Estimate) {
+	if u.conversions == nil {
+		return
+	}
+	if err := u.conversions.Upsert(ctx, entities.NewEstimateConversion(e)); err != nil {
+		log.Printf("[estimate][usecase] conversion projection failed estimate_id=%s err=%v", e.ID, err)
+	}
+}
+
 func (u *EstimateUseCase) GetByID(ctx context.Context, id string) (entities.Estimate, error) {
 	id = strings.TrimSpace(id)
 	if id == "" {
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,7 +16,7 @@ import (
 func TestEstimateUseCase_CalculateEstimate(t *testing.T) {
 	t.Run("invalid os id", func(t *testing.T) {
 		uc := NewEstimateUseCase(nil)
-		_, err := uc.CalculateEstimate(context.Background(), "   ", 10)
+		_, err := uc.CalculateEstimate(context.Background(), "   ", EstimatePricing{Price: 10})
 		if !errors.Is(err, ErrInvalidOSID) {
 			t.Fatalf("expected ErrInvalidOSID, got %v", err)
 		}
@@ -23,7 +24,7 @@ func TestEstimateUseCase_CalculateEstimate(t *testing.T) {
 
 	t.Run("invalid value", func(t *testing.T) {
 		uc := NewEstimateUseCase(nil)
-		_, err := uc.CalculateEstimate(context.Background(), "os-1", 0)
+		_, err := uc.CalculateEstimate(context.Background(), "os-1", EstimatePricing{})
 		if !errors.Is(err, ErrInvalidEstimateVal) {
 			t.Fatalf("expected ErrInvalidEstimateVal, got %v", err)
 		}
@@ -37,7 +38,7 @@ func TestEstimateUseCase_CalculateEstimate(t *testing.T) {
 
 		repo.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(entities.Estimate{}, errors.New("db"))
 
-		_, err := uc.CalculateEstimate(context.Background(), "os-1", 10)
+		_, err := uc.CalculateEstimate(context.Background(), "os-1", EstimatePricing{Price: 10})
 		if err == nil || err.Error() != "db" {
 			t.Fatalf("expected db error, got %v", err)
 		}
@@ -51,7 +52,7 @@ func TestEstimateUseCase_CalculateEstimate(t *testing.T) {
 
 		repo.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(entities.Estimate{ID: "existing"}, nil)
 
-		_, err := uc.CalculateEstimate(context.Background(), "os-1", 10)
+		_, err := uc.CalculateEstimate(context.Background(), "os-1", EstimatePricing{Price: 10})
 		if !errors.Is(err, ErrEstimateAlreadyExists) {
 			t.Fatalf("expected ErrEstimateAlreadyExists, got %v", err)
 		}
@@ -76,7 +77,7 @@ func TestEstimateUseCase_CalculateEstimate(t *testing.T) {
 			},
 		)
 
-		res, err := uc.CalculateEstimate(context.Background(), " os-1 ", 125.5)
+		res, err := uc.CalculateEstimate(context.Background(), " os-1 ", EstimatePricing{Price: 125.5})
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -84,17 +85,77 @@ func TestEstimateUseCase_CalculateEstimate(t *testing.T) {
 			t.Fatalf("expected generated id")
 		}
 	})
+
+	t.Run("create with tax breakdown", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		lastYear := time.Now().AddDate(-1, 0, 0)
+		lastMonth := time.Now().AddDate(0, -1, 0)
+		uc := NewEstimateUseCase(repo).WithTaxes(entities.TaxTable{
+			DefaultMunicipality: "3550308",
+			Rates: []entities.TaxRate{
+				{Tax: entities.TaxISS, ItemKind: entities.EstimateItemServico, Rate: 2, EffectiveFrom: lastYear},
+				{Tax: entities.TaxISS, ItemKind: entities.EstimateItemServico, Municipality: "3550308", Rate: 3, EffectiveFrom: lastYear, EffectiveTo: &lastMonth},
+				{Tax: entities.TaxISS, ItemKind: entities.EstimateItemServico, Municipality: "3550308", Rate: 5, EffectiveFrom: lastMonth},
+				{Tax: entities.TaxICMS, ItemKind: entities.EstimateItemPeca, Rate: 18, EffectiveFrom: lastYear},
+				{Tax: entities.TaxIPI, ItemKind: entities.EstimateItemPeca, Rate: 3.25, EffectiveFrom: lastYear},
+			},
+		})
+
+		repo.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(entities.Estimate{}, nil)
+		repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e entities.Estimate) (entities.Estimate, error) {
+			return e, nil
+		})
+
+		items := []entities.EstimateItem{
+			{Kind: entities.EstimateItemServico, Name: "Troca de óleo", Quantity: 1, UnitPrice: 80, Total: 80},
+			{Kind: entities.EstimateItemPeca, Name: "Filtro", Quantity: 3, UnitPrice: 33.33, Total: 99.99},
+		}
+		res, err := uc.CalculateEstimate(context.Background(), "os-1", EstimatePricing{Price: 179.99, Items: items})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.Municipality != "3550308" || res.Price != 179.99 {
+			t.Fatalf("unexpected estimate: %+v", res)
+		}
+		if got := res.Items[0].Taxes; len(got) != 1 || got[0].Rate != 5 || got[0].Amount != 4 {
+			t.Fatalf("unexpected service taxes: %+v", got)
+		}
+		if got := res.Items[1].Taxes; len(got) != 2 || got[0].Name != entities.TaxICMS || got[0].Amount != 18 || got[1].Amount != 3.25 {
+			t.Fatalf("unexpected part taxes: %+v", got)
+		}
+		if len(res.Taxes) != 3 || res.TaxTotal != 25.25 {
+			t.Fatalf("unexpected totals: %+v total=%v", res.Taxes, res.TaxTotal)
+		}
+
+		// In another municipality the general ISS rate applies.
+		repo.EXPECT().GetByOSID(gomock.Any(), "os-2").Return(entities.Estimate{}, nil)
+		repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e entities.Estimate) (entities.Estimate, error) {
+			return e, nil
+		})
+		res, err = uc.CalculateEstimate(context.Background(), "os-2", EstimatePricing{Price: 80, Items: items[:1], Municipality: "3304557"})
+		if err != nil || res.Items[0].Taxes[0].Rate != 2 || res.TaxTotal != 1.6 {
+			t.Fatalf("unexpected estimate: %+v err=%v", res, err)
+		}
+	})
 }
 
-func TestEstimateUseCase_UpdateStatusByOSIDFlows(t *testing.T) {
+func TestEstimateUseCase_StatusDecisionFlows(t *testing.T) {
 	cases := []struct {
 		name   string
 		call   func(uc *EstimateUseCase, ctx context.Context, osID string) (entities.Estimate, error)
 		status entities.EstimateStatus
 	}{
-		{name: "approve", call: (*EstimateUseCase).ApproveByOSID, status: entities.EstimateStatusAprovado},
-		{name: "reject", call: (*EstimateUseCase).RejectByOSID, status: entities.EstimateStatusRejeitado},
-		{name: "cancel", call: (*EstimateUseCase).CancelByOSID, status: entities.EstimateStatusCancelado},
+		{name: "approve", call: func(uc *EstimateUseCase, ctx context.Context, osID string) (entities.Estimate, error) {
+			return uc.ApproveByOSID(ctx, osID, StatusDecision{})
+		}, status: entities.EstimateStatusAprovado},
+		{name: "reject", call: func(uc *EstimateUseCase, ctx context.Context, osID string) (entities.Estimate, error) {
+			return uc.RejectByOSID(ctx, osID, StatusDecision{})
+		}, status: entities.EstimateStatusRejeitado},
+		{name: "cancel", call: func(uc *EstimateUseCase, ctx context.Context, osID string) (entities.Estimate, error) {
+			return uc.CancelByOSID(ctx, osID, StatusDecision{})
+		}, status: entities.EstimateStatusCancelado},
 	}
 
 	for _, tc := range cases {
@@ -111,7 +172,8 @@ func TestEstimateUseCase_UpdateStatusByOSIDFlows(t *testing.T) {
 			defer ctrl.Finish()
 			repo := mock_interfaces.NewMockIEstimateRepository(ctrl)
 			uc := NewEstimateUseCase(repo)
-			repo.EXPECT().UpdateStatusByOSID(gomock.Any(), "os-1", tc.status).Return(entities.Estimate{}, errors.New("db"))
+			pending := expectPending(repo)
+			repo.EXPECT().UpdateStatus(gomock.Any(), pending, entities.EstimateStatusChange{Status: tc.status}).Return(entities.Estimate{}, errors.New("db"))
 
 			_, err := tc.call(uc, context.Background(), "os-1")
 			if err == nil || err.Error() != "db" {
@@ -124,7 +186,7 @@ func TestEstimateUseCase_UpdateStatusByOSIDFlows(t *testing.T) {
 			defer ctrl.Finish()
 			repo := mock_interfaces.NewMockIEstimateRepository(ctrl)
 			uc := NewEstimateUseCase(repo)
-			repo.EXPECT().UpdateStatusByOSID(gomock.Any(), "os-1", tc.status).Return(entities.Estimate{}, nil)
+			repo.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(entities.Estimate{}, nil)
 
 			_, err := tc.call(uc, context.Background(), "os-1")
 			if !errors.Is(err, ErrEstimateNotFound) {
@@ -132,13 +194,53 @@ func TestEstimateUseCase_UpdateStatusByOSIDFlows(t *testing.T) {
 			}
 		})
 
+		t.Run(tc.name+" status changed after read", func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+			conversions := mock_interfaces.NewMockIEstimateConversionRepository(ctrl)
+			uc := NewEstimateUseCase(repo).WithConversionProjection(conversions)
+			pending := expectPending(repo)
+
+			// The sweeper expires the estimate between the read and the write; the write is
+			// conditioned on what was read, as the repository does.
+			stored := pending
+			stored.Status = entities.EstimateStatusExpirado
+			stored.UpdatedAt = pending.UpdatedAt.Add(time.Second)
+			repo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), entities.EstimateStatusChange{Status: tc.status}).DoAndReturn(
+				func(_ context.Context, current entities.Estimate, _ entities.EstimateStatusChange) (entities.Estimate, error) {
+					if current.Status != stored.Status || !current.UpdatedAt.Equal(stored.UpdatedAt) {
+						return entities.Estimate{}, entities.ErrEstimateChanged
+					}
+					t.Fatalf("write with a stale read")
+					return entities.Estimate{}, nil
+				})
+
+			if _, err := tc.call(uc, context.Background(), "os-1"); !errors.Is(err, entities.ErrEstimateChanged) {
+				t.Fatalf("expected ErrEstimateChanged, got %v", err)
+			}
+		})
+
+		t.Run(tc.name+" canceled estimate", func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+			uc := NewEstimateUseCase(repo)
+			repo.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(entities.Estimate{ID: "id-1", OSID: "os-1", Status: entities.EstimateStatusCancelado}, nil)
+
+			if _, err := tc.call(uc, context.Background(), "os-1"); !errors.Is(err, ErrEstimateTransitionNotAllowed) {
+				t.Fatalf("expected ErrEstimateTransitionNotAllowed, got %v", err)
+			}
+		})
+
 		t.Run(tc.name+" success", func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			repo := mock_interfaces.NewMockIEstimateRepository(ctrl)
 			uc := NewEstimateUseCase(repo)
 			expected := entities.Estimate{ID: "id-1", OSID: "os-1", Status: tc.status}
-			repo.EXPECT().UpdateStatusByOSID(gomock.Any(), "os-1", tc.status).Return(expected, nil)
+			pending := expectPending(repo)
+			repo.EXPECT().UpdateStatus(gomock.Any(), pending, entities.EstimateStatusChange{Status: tc.status}).Return(expected, nil)
 
 			res, err := tc.call(uc, context.Background(), " os-1 ")
 			if err != nil {
@@ -151,10 +253,521 @@ func TestEstimateUseCase_UpdateStatusByOSIDFlows(t *testing.T) {
 	}
 }
 
+// expectPending expects the decision to read the pending estimate of os-1, which it
+// returns for the conditional status write.
+func expectPending(repo *mock_interfaces.MockIEstimateRepository) entities.Estimate {
+	pending := entities.Estimate{ID: "id-1", OSID: "os-1", Status: entities.EstimateStatusPendente, UpdatedAt: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)}
+	repo.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(pending, nil)
+	return pending
+}
+
+func TestEstimateUseCase_StatusReasons(t *testing.T) {
+	t.Run("invalid reasons", func(t *testing.T) {
+		uc := NewEstimateUseCase(nil)
+		cases := []struct {
+			call func() (entities.Estimate, error)
+			want error
+		}{
+			{func() (entities.Estimate, error) {
+				return uc.RejectByOSID(context.Background(), "os-1", StatusDecision{ReasonCode: "desconhecido"})
+			}, ErrUnknownReason},
+			// duplicado is a cancellation reason only.
+			{func() (entities.Estimate, error) {
+				return uc.RejectByOSID(context.Background(), "os-1", StatusDecision{ReasonCode: "duplicado"})
+			}, ErrUnknownReason},
+			{func() (entities.Estimate, error) {
+				return uc.CancelByOSID(context.Background(), "os-1", StatusDecision{ReasonText: "sem código"})
+			}, ErrUnknownReason},
+			{func() (entities.Estimate, error) {
+				return uc.CancelByOSID(context.Background(), "os-1", StatusDecision{ReasonCode: "outro", ReasonText: "  "})
+			}, ErrInvalidReasonText},
+			{func() (entities.Estimate, error) {
+				return uc.RejectByOSID(context.Background(), "os-1", StatusDecision{ReasonCode: "preco", ReasonText: strings.Repeat("a", 501)})
+			}, ErrInvalidReasonText},
+		}
+		for i, tc := range cases {
+			if _, err := tc.call(); !errors.Is(err, tc.want) {
+				t.Fatalf("case %d: expected %v, got %v", i, tc.want, err)
+			}
+		}
+	})
+
+	t.Run("records reason and actor", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		uc := NewEstimateUseCase(repo)
+		change := entities.EstimateStatusChange{
+			Status: entities.EstimateStatusRejeitado,
+			Actor:  "cliente@example.com",
+			Reason: &entities.StatusReason{Code: "outro", Label: "Outro motivo", Text: "Vai vender o carro"},
+		}
+		pending := expectPending(repo)
+		repo.EXPECT().UpdateStatus(gomock.Any(), pending, change).Return(entities.Estimate{ID: "id-1", Status: change.Status, Reason: change.Reason}, nil)
+
+		res, err := uc.RejectByOSID(context.Background(), "os-1", StatusDecision{Actor: " cliente@example.com ", ReasonCode: "OUTRO", ReasonText: " Vai vender o carro "})
+		if err != nil || res.Reason == nil || res.Reason.Code != "outro" {
+			t.Fatalf("unexpected result: %+v err=%v", res, err)
+		}
+	})
+
+	t.Run("configured catalog", func(t *testing.T) {
+		uc := NewEstimateUseCase(nil).WithReasonCatalog(entities.ReasonCatalog{
+			Rejection:    []entities.ReasonCode{{Code: "garantia", Label: "Coberto pela garantia"}},
+			Cancellation: []entities.ReasonCode{{Code: "outro", Label: "Outro", TextRequired: true}},
+		})
+		if len(uc.ReasonCatalog().Rejection) != 1 {
+			t.Fatalf("unexpected catalog: %+v", uc.ReasonCatalog())
+		}
+		if _, err := uc.RejectByOSID(context.Background(), "os-1", StatusDecision{ReasonCode: "preco"}); !errors.Is(err, ErrUnknownReason) {
+			t.Fatalf("expected ErrUnknownReason, got %v", err)
+		}
+	})
+}
+
+func TestEstimateUseCase_PartialApproval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+	uc := NewEstimateUseCase(repo)
+	now := time.Date(2026, 5, 5, 12, 0, 0, 0, time.UTC)
+	uc.now = func() time.Time { return now }
+
+	brakes := entities.EstimateItem{ID: "brk-1", Kind: entities.EstimateItemServico, Name: "Freios", Quantity: 1, UnitPrice: 300, Total: 300, Discount: 30}
+	tires := entities.EstimateItem{ID: "tyr-1", Kind: entities.EstimateItemPeca, Name: "Pneus", Quantity: 4, UnitPrice: 200, Total: 800, Discount: 80}
+	pending := entities.Estimate{
+		ID: "est-1", OSID: "os-1", Status: entities.EstimateStatusPendente, Price: 990, Subtotal: 1100, DiscountTotal: 110,
+		Items: []entities.EstimateItem{brakes, tires},
+		Discounts: []entities.EstimateDiscount{{
+			Source: entities.DiscountSourceManual,
+			Rule:   entities.DiscountRule{Type: entities.DiscountPercentual, Value: 10, Scope: entities.DiscountScopeOrcamento},
+			Amount: 110,
+		}},
+		CreatedAt: now.Add(-time.Hour),
+		UpdatedAt: now.Add(-time.Hour),
+		History:   []entities.EstimateStatusChange{{Status: entities.EstimateStatusPendente, At: now.Add(-time.Hour)}},
+	}
+
+	t.Run("approves the brakes only", func(t *testing.T) {
+		repo.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(pending, nil)
+		repo.EXPECT().UpdatePricing(gomock.Any(), gomock.Any(), pending.UpdatedAt, gomock.Nil()).DoAndReturn(
+			func(_ context.Context, e entities.Estimate, _ time.Time, _ []entities.CouponRedemption) (entities.Estimate, error) {
+				return e, nil
+			})
+
+		res, err := uc.ApproveByOSID(context.Background(), "os-1", StatusDecision{ItemIDs: []string{" brk-1 ", "brk-1"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// The 10% discount now applies to the brakes only.
+		if res.Status != entities.EstimateStatusAprovado || res.Price != 270 || res.Subtotal != 300 || len(res.Items) != 1 || res.Items[0].Discount != 30 {
+			t.Fatalf("unexpected approval: %+v", res)
+		}
+		if !res.IsPartiallyApproved() || len(res.DeclinedItems) != 1 || res.DeclinedItems[0].Total != 800 || res.DeclinedItems[0].Discount != 80 {
+			t.Fatalf("unexpected declined items: %+v", res.DeclinedItems)
+		}
+		if res.ApprovedAt == nil || !res.ApprovedAt.Equal(now) || len(res.History) != 2 || res.History[1].Status != entities.EstimateStatusAprovado {
+			t.Fatalf("unexpected history: %+v", res)
+		}
+		if len(pending.History) != 1 {
+			t.Fatalf("read estimate changed: %+v", pending.History)
+		}
+	})
+
+	t.Run("approved estimate is not approved again", func(t *testing.T) {
+		approved := pending
+		approved.Status = entities.EstimateStatusAprovado
+		repo.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(approved, nil)
+
+		if _, err := uc.ApproveByOSID(context.Background(), "os-1", StatusDecision{}); !errors.Is(err, ErrEstimateTransitionNotAllowed) {
+			t.Fatalf("expected ErrEstimateTransitionNotAllowed, got %v", err)
+		}
+	})
+
+	t.Run("every item approves the whole estimate", func(t *testing.T) {
+		repo.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(pending, nil)
+		repo.EXPECT().UpdateStatus(gomock.Any(), pending, entities.EstimateStatusChange{Status: entities.EstimateStatusAprovado}).
+			Return(entities.Estimate{ID: "est-1", OSID: "os-1", Status: entities.EstimateStatusAprovado}, nil)
+
+		if _, err := uc.ApproveByOSID(context.Background(), "os-1", StatusDecision{ItemIDs: []string{"brk-1", "tyr-1"}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		repo.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(pending, nil)
+		if _, err := uc.ApproveByOSID(context.Background(), "os-1", StatusDecision{ItemIDs: []string{"brk-1", "nope"}}); !errors.Is(err, ErrUnknownEstimateItem) {
+			t.Fatalf("expected ErrUnknownEstimateItem, got %v", err)
+		}
+
+		approved := pending
+		approved.Status = entities.EstimateStatusAprovado
+		repo.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(approved, nil)
+		if _, err := uc.ApproveByOSID(context.Background(), "os-1", StatusDecision{ItemIDs: []string{"brk-1"}}); !errors.Is(err, ErrEstimateNotPending) {
+			t.Fatalf("expected ErrEstimateNotPending, got %v", err)
+		}
+	})
+}
+
+func TestEstimateUseCase_Discounts(t *testing.T) {
+	now := time.Date(2026, 5, 4, 10, 0, 0, 0, time.UTC)
+	expires := now.AddDate(0, 1, 0)
+	items := []entities.EstimateItem{
+		{Kind: entities.EstimateItemServico, Name: "Mão de obra", Quantity: 1, UnitPrice: 100, Total: 100},
+		{Kind: entities.EstimateItemPeca, Name: "Pastilha", Quantity: 2, UnitPrice: 50, Total: 100},
+	}
+	coupon := entities.Coupon{
+		Code:           "PROMO10",
+		Rule:           entities.DiscountRule{Type: entities.DiscountPercentual, Value: 10, Scope: entities.DiscountScopeItem, ItemKind: entities.EstimateItemServico},
+		ExpiresAt:      &expires,
+		MaxPerCustomer: 1,
+		Active:         true,
+	}
+	manual := entities.ManualDiscount{
+		Rule:       entities.DiscountRule{Type: entities.DiscountFixo, Value: 30, Scope: entities.DiscountScopeOrcamento},
+		Reason:     "cliente frota",
+		ApprovedBy: "gerente",
+	}
+
+	setup := func(t *testing.T) (*EstimateUseCase, *mock_interfaces.MockIEstimateRepository, *mock_interfaces.MockICouponRepository) {
+		ctrl := gomock.NewController(t)
+		repo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		coupons := mock_interfaces.NewMockICouponRepository(ctrl)
+		uc := NewEstimateUseCase(repo).WithCoupons(coupons).WithTaxes(entities.TaxTable{
+			DefaultMunicipality: "3550308",
+			Rates: []entities.TaxRate{
+				{Tax: entities.TaxISS, ItemKind: entities.EstimateItemServico, Rate: 5, EffectiveFrom: now.AddDate(-1, 0, 0)},
+			},
+		})
+		uc.now = func() time.Time { return now }
+		return uc, repo, coupons
+	}
+
+	t.Run("coupon and manual discount", func(t *testing.T) {
+		uc, repo, coupons := setup(t)
+		repo.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(entities.Estimate{}, nil)
+		coupons.EXPECT().GetByCode(gomock.Any(), "PROMO10").Return(coupon, nil)
+		coupons.EXPECT().CustomerRedemptions(gomock.Any(), "PROMO10", "cust-1").Return(0, nil)
+		repo.EXPECT().CreateWithRedemptions(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, e entities.Estimate, reds []entities.CouponRedemption) (entities.Estimate, error) {
+				if len(reds) != 1 || reds[0].Code != "PROMO10" || reds[0].CustomerID != "cust-1" || reds[0].MaxPerCustomer != 1 {
+					t.Fatalf("unexpected redemptions: %+v", reds)
+				}
+				return e, nil
+			})
+
+		res, err := uc.CalculateEstimate(context.Background(), "os-1", EstimatePricing{
+			Price:           200,
+			Items:           items,
+			CustomerID:      "cust-1",
+			CouponCodes:     []string{" promo10 ", "PROMO10"},
+			ManualDiscounts: []entities.ManualDiscount{manual},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// 10% of the service line, then R$ 30 spread over the remaining 190.
+		if res.Subtotal != 200 || res.DiscountTotal != 40 || res.Price != 160 {
+			t.Fatalf("unexpected totals: %+v", res)
+		}
+		if len(res.Discounts) != 2 || res.Discounts[0].Source != entities.DiscountSourceManual || res.Discounts[0].Amount != 30 || res.Discounts[1].Amount != 10 {
+			t.Fatalf("unexpected discounts: %+v", res.Discounts)
+		}
+		if res.Items[0].Discount != 24.21 || res.Items[1].Discount != 15.79 {
+			t.Fatalf("unexpected line discounts: %+v", res.Items)
+		}
+		if res.TaxTotal != 3.79 {
+			t.Fatalf("expected ISS on the discounted line, got %v", res.TaxTotal)
+		}
+	})
+
+	t.Run("coupon errors", func(t *testing.T) {
+		expired := coupon
+		expired.ExpiresAt = &now
+		cases := []struct {
+			name     string
+			coupon   entities.Coupon
+			customer string
+			used     int
+			want     error
+		}{
+			{name: "not found", coupon: entities.Coupon{}, customer: "cust-1", want: ErrCouponNotFound},
+			{name: "expired", coupon: expired, customer: "cust-1", want: entities.ErrCouponUnavailable},
+			{name: "without customer", coupon: coupon, want: ErrCouponNeedsCustomer},
+			{name: "customer limit", coupon: coupon, customer: "cust-1", used: 1, want: entities.ErrCouponCustomerLimit},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				uc, repo, coupons := setup(t)
+				repo.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(entities.Estimate{}, nil)
+				coupons.EXPECT().GetByCode(gomock.Any(), "PROMO10").Return(tc.coupon, nil)
+				if tc.customer != "" && tc.want == entities.ErrCouponCustomerLimit {
+					coupons.EXPECT().CustomerRedemptions(gomock.Any(), "PROMO10", tc.customer).Return(tc.used, nil)
+				}
+				_, err := uc.CalculateEstimate(context.Background(), "os-1", EstimatePricing{Price: 200, Items: items, CustomerID: tc.customer, CouponCodes: []string{"promo10"}})
+				if !errors.Is(err, tc.want) {
+					t.Fatalf("expected %v, got %v", tc.want, err)
+				}
+			})
+		}
+	})
+
+	t.Run("invalid manual discount", func(t *testing.T) {
+		uc, repo, _ := setup(t)
+		repo.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(entities.Estimate{}, nil)
+		noReason := manual
+		noReason.Reason = " "
+		_, err := uc.CalculateEstimate(context.Background(), "os-1", EstimatePricing{Price: 200, ManualDiscounts: []entities.ManualDiscount{noReason}})
+		if !errors.Is(err, ErrInvalidDiscount) {
+			t.Fatalf("expected ErrInvalidDiscount, got %v", err)
+		}
+	})
+
+	t.Run("discount not applicable", func(t *testing.T) {
+		uc, repo, coupons := setup(t)
+		repo.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(entities.Estimate{}, nil)
+		coupons.EXPECT().GetByCode(gomock.Any(), "PROMO10").Return(coupon, nil)
+		coupons.EXPECT().CustomerRedemptions(gomock.Any(), "PROMO10", "cust-1").Return(0, nil)
+		// Only parts: the service coupon takes nothing off.
+		_, err := uc.CalculateEstimate(context.Background(), "os-1", EstimatePricing{Price: 100, Items: items[1:], CustomerID: "cust-1", CouponCodes: []string{"PROMO10"}})
+		if !errors.Is(err, ErrDiscountNotApplicable) {
+			t.Fatalf("expected ErrDiscountNotApplicable, got %v", err)
+		}
+	})
+
+	t.Run("discount exceeds price", func(t *testing.T) {
+		uc, repo, _ := setup(t)
+		repo.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(entities.Estimate{}, nil)
+		full := manual
+		full.Rule = entities.DiscountRule{Type: entities.DiscountPercentual, Value: 100, Scope: entities.DiscountScopeOrcamento}
+		_, err := uc.CalculateEstimate(context.Background(), "os-1", EstimatePricing{Price: 200, ManualDiscounts: []entities.ManualDiscount{full}})
+		if !errors.Is(err, ErrDiscountExceedsPrice) {
+			t.Fatalf("expected ErrDiscountExceedsPrice, got %v", err)
+		}
+	})
+}
+
+func TestEstimateUseCase_PricingRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+	sp, _ := time.LoadLocation("America/Sao_Paulo")
+	uc := NewEstimateUseCase(repo).WithPricingRules(entities.PricingRuleSet{
+		Version:  "2026-05",
+		Location: sp,
+		Rules: []entities.PricingRule{
+			{ID: "minimo", Type: entities.PricingMinimumLabor, Amount: 150},
+			{ID: "sabado", Type: entities.PricingSurcharge, Percent: 10, ItemKind: entities.EstimateItemServico, Weekdays: []time.Weekday{time.Saturday}},
+		},
+	})
+	// Saturday morning in São Paulo.
+	uc.now = func() time.Time { return time.Date(2026, 5, 9, 13, 0, 0, 0, time.UTC) }
+	items := []entities.EstimateItem{
+		{Kind: entities.EstimateItemServico, Name: "Diagnóstico", Quantity: 1, UnitPrice: 100, Total: 100},
+		{Kind: entities.EstimateItemPeca, Name: "Fusível", Quantity: 1, UnitPrice: 10, Total: 10},
+	}
+	manual := entities.ManualDiscount{
+		Rule:       entities.DiscountRule{Type: entities.DiscountFixo, Value: 15, Scope: entities.DiscountScopeOrcamento},
+		Reason:     "cortesia",
+		ApprovedBy: "gerente",
+	}
+
+	// The preview is not stored: no repository call is expected.
+	preview, err := uc.PreviewEstimate(context.Background(), "os-1", EstimatePricing{Price: 110, Items: items, ManualDiscounts: []entities.ManualDiscount{manual}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if preview.ID != "" || preview.PricingVersion != "2026-05" || len(preview.Adjustments) != 2 || preview.AdjustmentTotal != 65 {
+		t.Fatalf("unexpected preview: %+v", preview)
+	}
+	// 150 of labor + 10% on Saturday, plus the part; the discount applies on the adjusted total.
+	if preview.Items[0].Total != 165 || preview.Subtotal != 175 || preview.Price != 160 {
+		t.Fatalf("unexpected pricing: %+v", preview)
+	}
+
+	repo.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(entities.Estimate{}, nil)
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e entities.Estimate) (entities.Estimate, error) {
+		return e, nil
+	})
+	created, err := uc.CalculateEstimate(context.Background(), "os-1", EstimatePricing{Price: 110, Items: items, ManualDiscounts: []entities.ManualDiscount{manual}})
+	if err != nil || created.ID == "" || created.Price != preview.Price || created.Adjustments[1].Reason != "sábado" {
+		t.Fatalf("unexpected estimate: %+v err=%v", created, err)
+	}
+
+	if _, err := uc.PreviewEstimate(context.Background(), " ", EstimatePricing{Price: 10}); !errors.Is(err, ErrInvalidOSID) {
+		t.Fatalf("expected ErrInvalidOSID, got %v", err)
+	}
+}
+
+func TestEstimateUseCase_Catalog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+	catalog := mock_interfaces.NewMockICatalogRepository(ctrl)
+	items := []entities.EstimateItem{
+		{Kind: entities.EstimateItemServico, Code: "SRV-1", Name: "Troca de óleo", Quantity: 1, UnitPrice: 84, Total: 84},
+		{Kind: entities.EstimateItemPeca, Code: "FIL-1", Name: "Filtro", Quantity: 1, UnitPrice: 40, Total: 40},
+		{Kind: entities.EstimateItemPeca, Name: "Abraçadeira", Quantity: 2, UnitPrice: 3, Total: 6},
+	}
+	pricing := EstimatePricing{Price: 130, Items: items}
+	catalog.EXPECT().CurrentVersion(gomock.Any()).Return(int64(12), nil).Times(2)
+	catalog.EXPECT().Get(gomock.Any(), entities.EstimateItemServico, "SRV-1").
+		Return(entities.CatalogItem{Kind: entities.EstimateItemServico, Code: "SRV-1", Price: 80}, nil).Times(2)
+	catalog.EXPECT().Get(gomock.Any(), entities.EstimateItemPeca, "FIL-1").Return(entities.CatalogItem{}, nil).Times(2)
+
+	// Flag mode: the estimate is created with the deviations beyond 5% recorded.
+	uc := NewEstimateUseCase(repo).WithCatalog(catalog, entities.CatalogPolicy{Mode: entities.CatalogModeFlag, Tolerance: 5})
+	repo.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(entities.Estimate{}, nil)
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e entities.Estimate) (entities.Estimate, error) {
+		return e, nil
+	})
+	created, err := uc.CalculateEstimate(context.Background(), "os-1", pricing)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.CatalogVersion != 12 || len(created.CatalogDeviations) != 2 ||
+		created.CatalogDeviations[0].Reason != entities.CatalogNotFound || created.CatalogDeviations[1].Reason != entities.CatalogNoCode {
+		t.Fatalf("unexpected catalog check: %+v", created)
+	}
+
+	// Reject mode with no tolerance: the service price deviates by 5% too.
+	uc.WithCatalog(catalog, entities.CatalogPolicy{Mode: entities.CatalogModeReject})
+	repo.EXPECT().GetByOSID(gomock.Any(), "os-2").Return(entities.Estimate{}, nil)
+	_, err = uc.CalculateEstimate(context.Background(), "os-2", pricing)
+	var deviationErr *CatalogDeviationError
+	if !errors.Is(err, entities.ErrCatalogDeviation) || !errors.As(err, &deviationErr) || len(deviationErr.Deviations) != 3 {
+		t.Fatalf("expected CatalogDeviationError, got %v", err)
+	}
+	if d := deviationErr.Deviations[0]; d.Reason != entities.CatalogPriceDeviation || d.ListPrice != 80 || d.Deviation != 5 {
+		t.Fatalf("unexpected deviation: %+v", d)
+	}
+
+	// Approving some items keeps the check of the quote, although it would now be
+	// rejected: the catalog is not read again.
+	repo.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(created, nil)
+	repo.EXPECT().UpdatePricing(gomock.Any(), gomock.Any(), created.UpdatedAt, gomock.Nil()).DoAndReturn(
+		func(_ context.Context, e entities.Estimate, _ time.Time, _ []entities.CouponRedemption) (entities.Estimate, error) {
+			return e, nil
+		})
+	partial, err := uc.ApproveByOSID(context.Background(), "os-1", StatusDecision{ItemIDs: []string{created.Items[0].ID}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if partial.CatalogVersion != 12 || len(partial.CatalogDeviations) != 2 || len(partial.Items) != 1 {
+		t.Fatalf("unexpected catalog check after partial approval: %+v", partial)
+	}
+
+	// Off: the catalog is not read.
+	uc.WithCatalog(catalog, entities.CatalogPolicy{Mode: entities.CatalogModeOff})
+	if preview, err := uc.PreviewEstimate(context.Background(), "os-3", pricing); err != nil || preview.CatalogVersion != 0 {
+		t.Fatalf("unexpected preview: %+v err=%v", preview, err)
+	}
+}
+
+func TestEstimateUseCase_LaborHours(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+	uc := NewEstimateUseCase(repo).WithLaborRates(entities.LaborRateTable{
+		DefaultTier: "pleno",
+		Rates:       map[string]float64{"pleno": 120, "senior": 160},
+	})
+	pricing := EstimatePricing{
+		Price: 50,
+		Items: []entities.EstimateItem{
+			{ID: "srv-1", Kind: entities.EstimateItemServico, Name: "Retífica", Hours: 2.5, SkillTier: " Senior "},
+			{Kind: entities.EstimateItemServico, Name: "Diagnóstico", Hours: 0.5},
+			{Kind: entities.EstimateItemPeca, Name: "Junta", Quantity: 1, UnitPrice: 50, Total: 50},
+		},
+	}
+
+	var created entities.Estimate
+	repo.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(entities.Estimate{}, nil)
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e entities.Estimate) (entities.Estimate, error) {
+		created = e
+		return e, nil
+	})
+	if _, err := uc.CalculateEstimate(context.Background(), "os-1", pricing); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 2.5h × 160 + 0.5h × 120 (default tier) + the part.
+	retifica, diagnostico := created.Items[0], created.Items[1]
+	if created.Price != 510 || retifica.Total != 400 || retifica.SkillTier != "senior" || retifica.HourlyRate != 160 ||
+		diagnostico.Total != 60 || diagnostico.SkillTier != "pleno" || diagnostico.ID == "" {
+		t.Fatalf("unexpected labor pricing: %+v", created)
+	}
+	if pricing.Items[0].Total != 0 {
+		t.Fatalf("pricing items changed: %+v", pricing.Items[0])
+	}
+
+	invalid := map[error]entities.EstimateItem{
+		ErrUnknownSkillTier:  {Kind: entities.EstimateItemServico, Name: "x", Hours: 1, SkillTier: "estagiario"},
+		ErrInvalidLaborHours: {Kind: entities.EstimateItemPeca, Name: "x", Hours: 1},
+	}
+	for want, item := range invalid {
+		if _, err := uc.PreviewEstimate(context.Background(), "os-1", EstimatePricing{Items: []entities.EstimateItem{item}}); !errors.Is(err, want) {
+			t.Fatalf("expected %v, got %v", want, err)
+		}
+	}
+
+	// The OS took 3h of the senior mechanic: only that line changes, at the quoted rate.
+	// The catalog check of the quote is kept; the catalog is not read again.
+	uc.WithCatalog(mock_interfaces.NewMockICatalogRepository(ctrl), entities.CatalogPolicy{Mode: entities.CatalogModeReject})
+	approved := created
+	approved.Status = entities.EstimateStatusAprovado
+	approved.CatalogVersion = 7
+	repo.EXPECT().GetByID(gomock.Any(), "est-1").Return(approved, nil).Times(3)
+	repo.EXPECT().UpdatePricing(gomock.Any(), gomock.Any(), approved.UpdatedAt, gomock.Nil()).DoAndReturn(
+		func(_ context.Context, e entities.Estimate, _ time.Time, _ []entities.CouponRedemption) (entities.Estimate, error) {
+			return e, nil
+		})
+	updated, err := uc.RecordActualHours(context.Background(), "est-1", map[string]float64{"srv-1": 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Price != 590 || updated.Items[0].ActualHours != 3 || updated.Items[0].Hours != 2.5 || updated.Items[0].Total != 480 ||
+		updated.Items[1].Total != 60 || updated.Status != entities.EstimateStatusAprovado || updated.CatalogVersion != 7 {
+		t.Fatalf("unexpected repricing: %+v", updated)
+	}
+
+	for _, hours := range []map[string]float64{{"nope": 3}, {diagnostico.ID: 0}} {
+		if _, err := uc.RecordActualHours(context.Background(), "est-1", hours); !errors.Is(err, ErrInvalidLaborHours) {
+			t.Fatalf("expected ErrInvalidLaborHours for %v, got %v", hours, err)
+		}
+	}
+
+	canceled := created
+	canceled.Status = entities.EstimateStatusCancelado
+	repo.EXPECT().GetByID(gomock.Any(), "est-2").Return(canceled, nil)
+	if _, err := uc.RecordActualHours(context.Background(), "est-2", map[string]float64{"srv-1": 3}); !errors.Is(err, ErrEstimateClosed) {
+		t.Fatalf("expected ErrEstimateClosed, got %v", err)
+	}
+}
+
 func TestEstimateUseCase_UpdateEstimatePrice(t *testing.T) {
+	createdAt := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	updatedAt := createdAt.Add(time.Hour)
+	now := time.Date(2026, 5, 4, 10, 0, 0, 0, time.UTC)
+	current := entities.Estimate{
+		ID:         "id-1",
+		OSID:       "os-1",
+		Status:     entities.EstimateStatusPendente,
+		CustomerID: "cust-1",
+		Subtotal:   100,
+		Price:      90,
+		CreatedAt:  createdAt,
+		UpdatedAt:  updatedAt,
+		Discounts: []entities.EstimateDiscount{{
+			Source:     entities.DiscountSourceCupom,
+			CouponCode: "PROMO10",
+			Rule:       entities.DiscountRule{Type: entities.DiscountPercentual, Value: 10, Scope: entities.DiscountScopeOrcamento},
+			Amount:     10,
+		}},
+	}
+
 	t.Run("invalid id", func(t *testing.T) {
 		uc := NewEstimateUseCase(nil)
-		_, err := uc.UpdateEstimatePrice(context.Background(), " ", 10)
+		_, err := uc.UpdateEstimatePrice(context.Background(), " ", EstimatePricing{Price: 10})
 		if !errors.Is(err, ErrInvalidEstimateID) {
 			t.Fatalf("expected ErrInvalidEstimateID, got %v", err)
 		}
@@ -162,57 +775,289 @@ func TestEstimateUseCase_UpdateEstimatePrice(t *testing.T) {
 
 	t.Run("invalid value", func(t *testing.T) {
 		uc := NewEstimateUseCase(nil)
-		_, err := uc.UpdateEstimatePrice(context.Background(), "id-1", 0)
+		_, err := uc.UpdateEstimatePrice(context.Background(), "id-1", EstimatePricing{})
 		if !errors.Is(err, ErrInvalidEstimateVal) {
 			t.Fatalf("expected ErrInvalidEstimateVal, got %v", err)
 		}
 	})
 
-	t.Run("repo error", func(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		repo := mock_interfaces.NewMockIEstimateRepository(ctrl)
 		uc := NewEstimateUseCase(repo)
-		repo.EXPECT().UpdatePriceByID(gomock.Any(), "id-1", 10.5).Return(entities.Estimate{}, errors.New("db"))
+		repo.EXPECT().GetByID(gomock.Any(), "id-1").Return(entities.Estimate{}, nil)
 
-		_, err := uc.UpdateEstimatePrice(context.Background(), "id-1", 10.5)
-		if err == nil || err.Error() != "db" {
-			t.Fatalf("expected db error, got %v", err)
+		_, err := uc.UpdateEstimatePrice(context.Background(), "id-1", EstimatePricing{Price: 10.5})
+		if !errors.Is(err, ErrEstimateNotFound) {
+			t.Fatalf("expected ErrEstimateNotFound, got %v", err)
 		}
 	})
 
-	t.Run("not found", func(t *testing.T) {
+	t.Run("not pending", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		repo := mock_interfaces.NewMockIEstimateRepository(ctrl)
 		uc := NewEstimateUseCase(repo)
-		repo.EXPECT().UpdatePriceByID(gomock.Any(), "id-1", 10.5).Return(entities.Estimate{}, nil)
+		approved := current
+		approved.Status = entities.EstimateStatusAprovado
+		repo.EXPECT().GetByID(gomock.Any(), "id-1").Return(approved, nil)
 
-		_, err := uc.UpdateEstimatePrice(context.Background(), "id-1", 10.5)
-		if !errors.Is(err, ErrEstimateNotFound) {
-			t.Fatalf("expected ErrEstimateNotFound, got %v", err)
+		_, err := uc.UpdateEstimatePrice(context.Background(), "id-1", EstimatePricing{Price: 10.5})
+		if !errors.Is(err, ErrEstimateNotPending) {
+			t.Fatalf("expected ErrEstimateNotPending, got %v", err)
 		}
 	})
 
-	t.Run("success", func(t *testing.T) {
+	t.Run("concurrent change", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		repo := mock_interfaces.NewMockIEstimateRepository(ctrl)
 		uc := NewEstimateUseCase(repo)
-		now := time.Now()
-		expected := entities.Estimate{ID: "id-1", Price: 10.5, UpdatedAt: now}
-		repo.EXPECT().UpdatePriceByID(gomock.Any(), "id-1", 10.5).Return(expected, nil)
+		repo.EXPECT().GetByID(gomock.Any(), "id-1").Return(current, nil)
+		repo.EXPECT().UpdatePricing(gomock.Any(), gomock.Any(), updatedAt, gomock.Nil()).Return(entities.Estimate{}, entities.ErrEstimateChanged)
+
+		_, err := uc.UpdateEstimatePrice(context.Background(), "id-1", EstimatePricing{Price: 10.5})
+		if !errors.Is(err, entities.ErrEstimateChanged) {
+			t.Fatalf("expected ErrEstimateChanged, got %v", err)
+		}
+	})
+
+	t.Run("success keeps discounts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		coupons := mock_interfaces.NewMockICouponRepository(ctrl)
+		rateChange := createdAt.AddDate(0, 2, 0)
+		uc := NewEstimateUseCase(repo).WithCoupons(coupons).WithTaxes(entities.TaxTable{
+			DefaultMunicipality: "3550308",
+			Rates: []entities.TaxRate{
+				{Tax: entities.TaxISS, ItemKind: entities.EstimateItemServico, Rate: 2, EffectiveFrom: createdAt.AddDate(-1, 0, 0), EffectiveTo: &rateChange},
+				{Tax: entities.TaxISS, ItemKind: entities.EstimateItemServico, Rate: 5, EffectiveFrom: rateChange},
+			},
+		})
+		uc.now = func() time.Time { return now }
+		repo.EXPECT().GetByID(gomock.Any(), "id-1").Return(current, nil)
+		repo.EXPECT().UpdatePricing(gomock.Any(), gomock.Any(), updatedAt, gomock.Nil()).DoAndReturn(
+			func(_ context.Context, e entities.Estimate, _ time.Time, _ []entities.CouponRedemption) (entities.Estimate, error) {
+				return e, nil
+			})
 
-		res, err := uc.UpdateEstimatePrice(context.Background(), " id-1 ", 10.5)
+		items := []entities.EstimateItem{{Kind: entities.EstimateItemServico, Name: "Mão de obra", Quantity: 1, UnitPrice: 200, Total: 200}}
+		// The coupon is already on the estimate: it is not looked up nor redeemed again.
+		res, err := uc.UpdateEstimatePrice(context.Background(), " id-1 ", EstimatePricing{Price: 200, Items: items, CouponCodes: []string{"PROMO10"}})
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
-		if res.ID != "id-1" || res.Price != 10.5 {
-			t.Fatalf("unexpected result: %+v", res)
+		if res.Subtotal != 200 || res.DiscountTotal != 20 || res.Price != 180 || len(res.Discounts) != 1 {
+			t.Fatalf("unexpected pricing: %+v", res)
+		}
+		if res.TaxTotal != 3.6 || !res.UpdatedAt.Equal(now) {
+			t.Fatalf("expected the rate of the creation date, got %+v", res)
 		}
 	})
 }
 
+func TestEstimateUseCase_Validity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+	conversions := mock_interfaces.NewMockIEstimateConversionRepository(ctrl)
+	rateChange := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	uc := NewEstimateUseCase(repo).WithValidity(10).WithConversionProjection(conversions).WithTaxes(entities.TaxTable{
+		DefaultMunicipality: "3550308",
+		Rates: []entities.TaxRate{
+			{Tax: entities.TaxISS, ItemKind: entities.EstimateItemServico, Rate: 2, EffectiveFrom: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), EffectiveTo: &rateChange},
+			{Tax: entities.TaxISS, ItemKind: entities.EstimateItemServico, Rate: 5, EffectiveFrom: rateChange},
+		},
+	})
+	createdAt := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+	now := createdAt
+	uc.now = func() time.Time { return now }
+	conversions.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	if _, err := uc.CalculateEstimate(context.Background(), "os-1", EstimatePricing{Price: 10, ValidityDays: -1}); !errors.Is(err, ErrInvalidValidity) {
+		t.Fatalf("expected ErrInvalidValidity, got %v", err)
+	}
+	repo.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(entities.Estimate{}, nil)
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e entities.Estimate) (entities.Estimate, error) {
+		return e, nil
+	})
+	items := []entities.EstimateItem{{Kind: entities.EstimateItemServico, Name: "Mão de obra", Quantity: 1, UnitPrice: 100, Total: 100}}
+	created, err := uc.CalculateEstimate(context.Background(), "os-1", EstimatePricing{Price: 100, Items: items, ValidityDays: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.ExpiresAt == nil || !created.ExpiresAt.Equal(createdAt.AddDate(0, 0, 3)) || created.TaxTotal != 2 {
+		t.Fatalf("unexpected estimate: %+v", created)
+	}
+
+	// Past its validity the estimate can neither be approved nor recalculated.
+	now = createdAt.AddDate(0, 0, 3)
+	repo.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(created, nil)
+	if _, err := uc.ApproveByOSID(context.Background(), "os-1", StatusDecision{}); !errors.Is(err, ErrEstimateExpired) {
+		t.Fatalf("expected ErrEstimateExpired, got %v", err)
+	}
+	repo.EXPECT().GetByID(gomock.Any(), created.ID).Return(created, nil)
+	if _, err := uc.UpdateEstimatePrice(context.Background(), created.ID, EstimatePricing{Price: 100}); !errors.Is(err, ErrEstimateExpired) {
+		t.Fatalf("expected ErrEstimateExpired, got %v", err)
+	}
+
+	// The sweeper expires it unless it was renewed or decided meanwhile.
+	other := entities.Estimate{ID: "id-2", Status: entities.EstimateStatusPendente, ExpiresAt: created.ExpiresAt}
+	later := createdAt.AddDate(0, 0, 30)
+	valid := entities.Estimate{ID: "id-3", Status: entities.EstimateStatusPendente, ExpiresAt: &later}
+	expired := created
+	expired.Status = entities.EstimateStatusExpirado
+	repo.EXPECT().ListByStatus(gomock.Any(), entities.EstimateStatusPendente).Return([]entities.Estimate{created, other, valid, {ID: "id-4", Status: entities.EstimateStatusPendente}}, nil)
+	repo.EXPECT().Expire(gomock.Any(), created.ID, *created.ExpiresAt).Return(expired, nil)
+	repo.EXPECT().Expire(gomock.Any(), "id-2", *created.ExpiresAt).Return(entities.Estimate{}, nil)
+	if n, err := uc.ExpireDue(context.Background()); err != nil || n != 1 {
+		t.Fatalf("expected 1 expired estimate, got %d err=%v", n, err)
+	}
+
+	// Renewal prices it again at the renewal date and restarts the default validity.
+	now = time.Date(2026, 5, 10, 9, 0, 0, 0, time.UTC)
+	repo.EXPECT().GetByID(gomock.Any(), created.ID).Return(expired, nil)
+	repo.EXPECT().UpdatePricing(gomock.Any(), gomock.Any(), expired.UpdatedAt, gomock.Nil()).DoAndReturn(
+		func(_ context.Context, e entities.Estimate, _ time.Time, _ []entities.CouponRedemption) (entities.Estimate, error) {
+			return e, nil
+		})
+	renewed, err := uc.RenewEstimate(context.Background(), created.ID, EstimatePricing{Price: 100, Items: items})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if renewed.Status != entities.EstimateStatusPendente || renewed.RenewedAt == nil || !renewed.RenewedAt.Equal(now) ||
+		!renewed.ExpiresAt.Equal(now.AddDate(0, 0, 10)) || !renewed.CreatedAt.Equal(createdAt) || renewed.TaxTotal != 5 {
+		t.Fatalf("unexpected renewal: %+v", renewed)
+	}
+
+	approved := created
+	approved.Status = entities.EstimateStatusAprovado
+	repo.EXPECT().GetByID(gomock.Any(), created.ID).Return(approved, nil)
+	if _, err := uc.RenewEstimate(context.Background(), created.ID, EstimatePricing{Price: 100}); !errors.Is(err, ErrEstimateNotPending) {
+		t.Fatalf("expected ErrEstimateNotPending, got %v", err)
+	}
+}
+
+func TestEstimateUseCase_InternalApproval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+	uc := NewEstimateUseCase(repo).WithInternalApproval(1000)
+	createdAt := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	now := createdAt
+	uc.now = func() time.Time { return now }
+	passThrough := func(_ context.Context, e entities.Estimate, _ time.Time, _ []entities.CouponRedemption) (entities.Estimate, error) {
+		return e, nil
+	}
+
+	// Below the threshold the estimate is pending as usual.
+	repo.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(entities.Estimate{}, nil)
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e entities.Estimate) (entities.Estimate, error) {
+		return e, nil
+	})
+	small, err := uc.CalculateEstimate(context.Background(), "os-1", EstimatePricing{Price: 1000})
+	if err != nil || small.Status != entities.EstimateStatusPendente || small.InternalApproval != nil || len(small.History) != 1 {
+		t.Fatalf("unexpected estimate: %+v err=%v", small, err)
+	}
+
+	repo.EXPECT().GetByOSID(gomock.Any(), "os-2").Return(entities.Estimate{}, nil)
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e entities.Estimate) (entities.Estimate, error) {
+		return e, nil
+	})
+	created, err := uc.CalculateEstimate(context.Background(), "os-2", EstimatePricing{Price: 1500})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.Status != entities.EstimateStatusAguardandoAprovacao || created.InternalApproval == nil ||
+		created.InternalApproval.Threshold != 1000 || created.InternalApproval.Price != 1500 ||
+		len(created.History) != 1 || created.History[0].Status != entities.EstimateStatusAguardandoAprovacao {
+		t.Fatalf("unexpected estimate: %+v", created)
+	}
+
+	// Neither the customer nor the payment can go ahead before the sign-off.
+	repo.EXPECT().GetByOSID(gomock.Any(), "os-2").Return(created, nil)
+	if _, err := uc.ApproveByOSID(context.Background(), "os-2", StatusDecision{}); !errors.Is(err, ErrInternalApprovalPending) {
+		t.Fatalf("expected ErrInternalApprovalPending, got %v", err)
+	}
+
+	// The queue lists the pending requests, oldest first.
+	older := entities.Estimate{ID: "id-old", Status: entities.EstimateStatusAguardandoAprovacao,
+		InternalApproval: &entities.InternalApproval{RequestedAt: createdAt.Add(-time.Hour)}}
+	rejected := entities.Estimate{ID: "id-rejected", Status: entities.EstimateStatusAguardandoAprovacao,
+		InternalApproval: &entities.InternalApproval{Decision: entities.InternalApprovalRejeitado}}
+	repo.EXPECT().ListByStatus(gomock.Any(), entities.EstimateStatusAguardandoAprovacao).Return([]entities.Estimate{created, rejected, older}, nil)
+	queue, err := uc.ListAwaitingInternalApproval(context.Background())
+	if err != nil || len(queue) != 2 || queue[0].ID != "id-old" || queue[1].ID != created.ID {
+		t.Fatalf("unexpected queue: %+v err=%v", queue, err)
+	}
+
+	// Rejecting needs a comment and keeps the estimate waiting for a revision.
+	if _, err := uc.DecideInternalApproval(context.Background(), created.ID, InternalDecision{Action: "rejeitar", Approver: "gerente"}); !errors.Is(err, ErrInvalidInternalDecision) {
+		t.Fatalf("expected ErrInvalidInternalDecision, got %v", err)
+	}
+	if _, err := uc.DecideInternalApproval(context.Background(), created.ID, InternalDecision{Action: "aprovar"}); !errors.Is(err, ErrInvalidInternalDecision) {
+		t.Fatalf("expected ErrInvalidInternalDecision, got %v", err)
+	}
+	now = createdAt.Add(time.Hour)
+	repo.EXPECT().GetByID(gomock.Any(), created.ID).Return(created, nil)
+	repo.EXPECT().UpdatePricing(gomock.Any(), gomock.Any(), created.UpdatedAt, gomock.Nil()).DoAndReturn(passThrough)
+	sentBack, err := uc.DecideInternalApproval(context.Background(), created.ID, InternalDecision{Action: "rejeitar", Approver: "gerente", Comment: "revisar peças"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sentBack.Status != entities.EstimateStatusAguardandoAprovacao || sentBack.InternalApproval.Decision != entities.InternalApprovalRejeitado ||
+		sentBack.History[len(sentBack.History)-1].Comment != "revisar peças" || created.InternalApproval.Decision != "" {
+		t.Fatalf("unexpected estimate: %+v", sentBack)
+	}
+	repo.EXPECT().GetByID(gomock.Any(), created.ID).Return(sentBack, nil)
+	if _, err := uc.DecideInternalApproval(context.Background(), created.ID, InternalDecision{Action: "aprovar", Approver: "gerente"}); !errors.Is(err, ErrNoInternalApprovalPending) {
+		t.Fatalf("expected ErrNoInternalApprovalPending, got %v", err)
+	}
+
+	// The revision is submitted again, and the approval lets the customer decide.
+	now = createdAt.Add(2 * time.Hour)
+	repo.EXPECT().GetByID(gomock.Any(), created.ID).Return(sentBack, nil)
+	repo.EXPECT().UpdatePricing(gomock.Any(), gomock.Any(), sentBack.UpdatedAt, gomock.Nil()).DoAndReturn(passThrough)
+	revised, err := uc.UpdateEstimatePrice(context.Background(), created.ID, EstimatePricing{Price: 1200})
+	if err != nil || !revised.InternalApproval.IsPending() || revised.InternalApproval.Price != 1200 || !revised.InternalApproval.RequestedAt.Equal(now) {
+		t.Fatalf("unexpected revision: %+v err=%v", revised, err)
+	}
+
+	now = createdAt.Add(3 * time.Hour)
+	repo.EXPECT().GetByID(gomock.Any(), created.ID).Return(revised, nil)
+	repo.EXPECT().UpdatePricing(gomock.Any(), gomock.Any(), revised.UpdatedAt, gomock.Nil()).DoAndReturn(passThrough)
+	approved, err := uc.DecideInternalApproval(context.Background(), created.ID, InternalDecision{Action: " APROVAR ", Approver: "gerente"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	last := approved.History[len(approved.History)-1]
+	if approved.Status != entities.EstimateStatusPendente || approved.InternalApproval.Approver != "gerente" ||
+		approved.InternalApproval.DecidedAt == nil || last.Status != entities.EstimateStatusPendente || last.Actor != "gerente" {
+		t.Fatalf("unexpected estimate: %+v", approved)
+	}
+
+	// A lower price keeps the sign-off; a higher one needs a new one.
+	repo.EXPECT().GetByID(gomock.Any(), created.ID).Return(approved, nil)
+	repo.EXPECT().UpdatePricing(gomock.Any(), gomock.Any(), approved.UpdatedAt, gomock.Nil()).DoAndReturn(passThrough)
+	if e, err := uc.UpdateEstimatePrice(context.Background(), created.ID, EstimatePricing{Price: 1100}); err != nil || e.Status != entities.EstimateStatusPendente {
+		t.Fatalf("unexpected estimate: %+v err=%v", e, err)
+	}
+	repo.EXPECT().GetByID(gomock.Any(), created.ID).Return(approved, nil)
+	repo.EXPECT().UpdatePricing(gomock.Any(), gomock.Any(), approved.UpdatedAt, gomock.Nil()).DoAndReturn(passThrough)
+	if e, err := uc.UpdateEstimatePrice(context.Background(), created.ID, EstimatePricing{Price: 1300}); err != nil ||
+		e.Status != entities.EstimateStatusAguardandoAprovacao || !e.InternalApproval.IsPending() {
+		t.Fatalf("unexpected estimate: %+v err=%v", e, err)
+	}
+
+	// Below the threshold again, the estimate no longer waits.
+	repo.EXPECT().GetByID(gomock.Any(), created.ID).Return(revised, nil)
+	repo.EXPECT().UpdatePricing(gomock.Any(), gomock.Any(), revised.UpdatedAt, gomock.Nil()).DoAndReturn(passThrough)
+	if e, err := uc.UpdateEstimatePrice(context.Background(), created.ID, EstimatePricing{Price: 900}); err != nil ||
+		e.Status != entities.EstimateStatusPendente || e.InternalApproval != nil {
+		t.Fatalf("unexpected estimate: %+v err=%v", e, err)
+	}
+}
+
 func TestEstimateUseCase_Getters(t *testing.T) {
 	t.Run("GetByID", func(t *testing.T) {
 		t.Run("invalid id", func(t *testing.T) {
@@ -320,3 +1165,53 @@ func TestEstimateUseCase_Getters(t *testing.T) {
 		})
 	})
 }
+
+func TestEstimateUseCase_ConversionProjection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+	conversions := mock_interfaces.NewMockIEstimateConversionRepository(ctrl)
+	uc := NewEstimateUseCase(repo).WithConversionProjection(conversions)
+
+	approvedAt := time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC)
+	approved := entities.Estimate{ID: "id-1", OSID: "os-1", Price: 100, Status: entities.EstimateStatusAprovado, ApprovedAt: &approvedAt}
+	pending := expectPending(repo)
+	repo.EXPECT().UpdateStatus(gomock.Any(), pending, entities.EstimateStatusChange{Status: entities.EstimateStatusAprovado}).Return(approved, nil)
+	conversions.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, c entities.EstimateConversion) error {
+			if c.EstimateID != "id-1" || c.ApprovedAt == nil || !c.ApprovedAt.Equal(approvedAt) {
+				t.Fatalf("unexpected projection: %+v", c)
+			}
+			// A projection failure must not fail the estimate change.
+			return errors.New("ddb")
+		})
+
+	if _, err := uc.ApproveByOSID(context.Background(), "os-1", StatusDecision{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestEstimateUseCase_IssuesInvoiceOnApproval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+	invoices := mock_interfaces.NewMockIInvoiceRepository(ctrl)
+	uc := NewEstimateUseCase(repo).WithInvoicing(NewInvoiceUseCase(invoices, repo, nil))
+
+	approved := entities.Estimate{ID: "id-1", OSID: "os-1", Price: 100, Status: entities.EstimateStatusAprovado}
+	pending := expectPending(repo)
+	repo.EXPECT().UpdateStatus(gomock.Any(), pending, entities.EstimateStatusChange{Status: entities.EstimateStatusAprovado}).Return(approved, nil)
+	repo.EXPECT().GetByID(gomock.Any(), "id-1").Return(approved, nil)
+	invoices.EXPECT().GetActiveByEstimateID(gomock.Any(), "id-1").Return(entities.Invoice{}, nil)
+	// An invoice failure must not fail the approval.
+	invoices.EXPECT().Create(gomock.Any(), gomock.Any()).Return(entities.Invoice{}, errors.New("ddb"))
+	if _, err := uc.ApproveByOSID(context.Background(), "os-1", StatusDecision{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repo.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(pending, nil)
+	repo.EXPECT().UpdateStatus(gomock.Any(), pending, entities.EstimateStatusChange{Status: entities.EstimateStatusRejeitado}).Return(entities.Estimate{ID: "id-1", Status: entities.EstimateStatusRejeitado}, nil)
+	if _, err := uc.RejectByOSID(context.Background(), "os-1", StatusDecision{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
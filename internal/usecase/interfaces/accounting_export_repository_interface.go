@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+	"mecanica_xpto/internal/domain/entities"
+	"time"
+)
+
+// IAccountingExportRepository persists accounting export runs and the export watermark.
+//
+// Notes:
+//   - GetWatermark returns the zero time before the first export.
+//   - Commit stores the run with its journal entries and moves the watermark to run.To
+//     atomically, only if the watermark is still previous; otherwise it returns
+//     entities.ErrAccountingWatermarkMoved.
+//   - GetByID returns an empty run when it does not exist.
+//   - ListEntries returns the entries stored with the run, in export order.
+
+type IAccountingExportRepository interface {
+	GetWatermark(ctx context.Context) (time.Time, error)
+	Commit(ctx context.Context, run entities.AccountingExport, entries []entities.JournalEntry, previous time.Time) error
+	GetByID(ctx context.Context, id string) (entities.AccountingExport, error)
+	ListEntries(ctx context.Context, exportID string) ([]entities.JournalEntry, error)
+}
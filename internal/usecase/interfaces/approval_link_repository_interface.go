@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	"context"
+	"mecanica_xpto/internal/domain/entities"
+	"time"
+)
+
+// IApprovalLinkRepository abstracts DynamoDB persistence for ApprovalLink.
+//
+// Update replaces the link only if it is unchanged since previousUpdatedAt
+// (entities.ErrApprovalLinkChanged otherwise), so an OTP is never confirmed twice nor a
+// failed attempt lost. Anonymize erases the personal data of a link under the same
+// condition; ListByRecipientHash looks links up by the blind index of their recipient.
+
+type IApprovalLinkRepository interface {
+	Create(ctx context.Context, l entities.ApprovalLink) (entities.ApprovalLink, error)
+	GetByID(ctx context.Context, id string) (entities.ApprovalLink, error)
+	Update(ctx context.Context, l entities.ApprovalLink, previousUpdatedAt time.Time) (entities.ApprovalLink, error)
+	ListByRecipientHash(ctx context.Context, hash string) ([]entities.ApprovalLink, error)
+	Anonymize(ctx context.Context, l entities.ApprovalLink, at time.Time) error
+}
@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	"context"
+	"mecanica_xpto/internal/domain/entities"
+)
+
+// IAuditLogRepository abstracts persistence of audit records (append-only).
+type IAuditLogRepository interface {
+	Create(ctx context.Context, r entities.AuditRecord) error
+}
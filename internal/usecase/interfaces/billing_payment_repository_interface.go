@@ -3,12 +3,45 @@ package interfaces
 import (
 	"context"
 	"mecanica_xpto/internal/domain/entities"
+	"time"
 )
 
 // IBillingPaymentRepository abstracts DynamoDB persistence for BillingPayment.
+//
+// List methods are sorted by payment date in the query itself (date sort key).
+// ScanLegacyDatePage and RewriteDate migrate date sort keys written before the sortable
+// layout, which would otherwise break that order and the page cursors.
+//
+// UpdateStatusWithEvent changes the status, appends the status event and posts the ledger
+// entry (when not nil) atomically, so a transition is never stored without its event; it
+// returns entities.ErrPaymentStatusChanged when the payment is no longer in the event's
+// previous status and entities.ErrLedgerConflict when the entry was already posted.
+// CreateWithPosting writes a new payment with its posting (first status event and ledger
+// entry) in the same way. When that transaction fails, CreateWithPendingPosting keeps the
+// payment, which the provider already captured, and puts the posting in the outbox
+// without touching the ledger; ListPendingPostings and CompletePosting drain the outbox
+// (a posting already completed is ignored). Both creates return
+// entities.ErrPaymentAlreadyExists when the ID is taken.
+//
+// Anonymize erases only the payer personal data of the payment as read; it returns
+// entities.ErrPaymentStatusChanged when the payment changed status or was already
+// anonymized, so a concurrent status change is neither overwritten nor lost.
 
 type IBillingPaymentRepository interface {
-	Create(ctx context.Context, p entities.BillingPayment) (entities.BillingPayment, error)
+	CreateWithPosting(ctx context.Context, p entities.BillingPayment, posting entities.PaymentPosting) (entities.BillingPayment, error)
+	CreateWithPendingPosting(ctx context.Context, p entities.BillingPayment, posting entities.PaymentPosting) (entities.BillingPayment, error)
+	ListPendingPostings(ctx context.Context) ([]entities.PaymentPosting, error)
+	CompletePosting(ctx context.Context, posting entities.PaymentPosting) error
 	GetByID(ctx context.Context, id string) (entities.BillingPayment, error)
-	ListByEstimateID(ctx context.Context, estimateID string) ([]entities.BillingPayment, error)
+	ListByEstimateID(ctx context.Context, estimateID string, page entities.PageRequest) (entities.Page[entities.BillingPayment], error)
+	ListByStatus(ctx context.Context, status entities.PaymentStatus, from, to *time.Time, page entities.PageRequest) (entities.Page[entities.BillingPayment], error)
+	ScanPage(ctx context.Context, startAfterID string, limit int32) ([]entities.BillingPayment, string, error)
+	ScanLegacyDatePage(ctx context.Context, startAfterID string, limit int32) ([]entities.BillingPayment, string, error)
+	RewriteDate(ctx context.Context, id string, date time.Time) error
+	UpdateDetails(ctx context.Context, id string, details entities.PaymentDetails) error
+	Replace(ctx context.Context, p entities.BillingPayment) error
+	Anonymize(ctx context.Context, p entities.BillingPayment, at time.Time) error
+	UpdateStatusWithEvent(ctx context.Context, event entities.PaymentStatusEvent, entry *entities.LedgerEntry) error
+	ListByPayerEmailHash(ctx context.Context, hash string) ([]entities.BillingPayment, error)
+	ListByPayerDocHash(ctx context.Context, hash string) ([]entities.BillingPayment, error)
 }
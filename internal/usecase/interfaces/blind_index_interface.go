@@ -0,0 +1,7 @@
+package interfaces
+
+// IBlindIndex computes keyed, non-reversible hashes of normalized personal data so that
+// records can be looked up by email/document without storing them in plaintext.
+type IBlindIndex interface {
+	Hash(value string) string
+}
@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	"context"
+	"mecanica_xpto/internal/domain/entities"
+)
+
+// ICatalogRepository persists the service and parts price catalog.
+//
+// Notes:
+//   - Every change (Put, PutMany, Delete) moves the catalog to a new version, stamped on the
+//     items written; CurrentVersion returns the latest one.
+//   - Get and Delete return an empty item when it does not exist.
+
+type ICatalogRepository interface {
+	Put(ctx context.Context, item entities.CatalogItem) (entities.CatalogItem, error)
+	PutMany(ctx context.Context, items []entities.CatalogItem) (int64, error)
+	Get(ctx context.Context, kind entities.EstimateItemKind, code string) (entities.CatalogItem, error)
+	List(ctx context.Context) ([]entities.CatalogItem, error)
+	Delete(ctx context.Context, kind entities.EstimateItemKind, code string) (entities.CatalogItem, error)
+	CurrentVersion(ctx context.Context) (int64, error)
+}
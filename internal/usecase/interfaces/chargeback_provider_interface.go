@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+	"mecanica_xpto/internal/domain/entities"
+)
+
+// IChargebackProvider fetches chargeback details from the payment provider.
+//
+// Provider notifications only carry the chargeback id; the state is always read back
+// from the provider API so a forged notification cannot change a dispute.
+type IChargebackProvider interface {
+	GetChargeback(ctx context.Context, chargebackID string) (entities.ProviderChargeback, error)
+}
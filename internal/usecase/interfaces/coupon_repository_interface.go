@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+	"mecanica_xpto/internal/domain/entities"
+)
+
+// ICouponRepository persists discount coupons.
+//
+// Notes:
+//   - Create returns entities.ErrCouponAlreadyExists for a taken code.
+//   - GetByCode and SetActive return an empty coupon when it does not exist.
+//   - Redemptions are counted by IEstimateRepository with the estimate write.
+
+type ICouponRepository interface {
+	Create(ctx context.Context, c entities.Coupon) error
+	GetByCode(ctx context.Context, code string) (entities.Coupon, error)
+	CustomerRedemptions(ctx context.Context, code, customerID string) (int, error)
+	SetActive(ctx context.Context, code string, active bool) (entities.Coupon, error)
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+	"mecanica_xpto/internal/domain/entities"
+)
+
+// IDisputeRepository abstracts DynamoDB persistence for Dispute.
+
+type IDisputeRepository interface {
+	Create(ctx context.Context, d entities.Dispute) (entities.Dispute, error)
+	GetByID(ctx context.Context, id string) (entities.Dispute, error)
+	Update(ctx context.Context, d entities.Dispute) error
+	ListByStatus(ctx context.Context, status entities.DisputeStatus) ([]entities.Dispute, error)
+}
@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+	"mecanica_xpto/internal/domain/entities"
+	"time"
+)
+
+// IEstimateConversionRepository persists the analytics read model of estimates.
+//
+// Notes:
+//   - Upsert keeps FirstPaidAt and the first DecidedAt already recorded.
+//   - MarkPaid records FirstPaidAt only once and is a no-op for unknown estimates.
+//   - ListCreatedBetween reads the estimates created in [from, to] without scanning the table.
+
+type IEstimateConversionRepository interface {
+	Upsert(ctx context.Context, c entities.EstimateConversion) error
+	MarkPaid(ctx context.Context, estimateID string, paidAt time.Time) error
+	ListCreatedBetween(ctx context.Context, from, to time.Time) ([]entities.EstimateConversion, error)
+}
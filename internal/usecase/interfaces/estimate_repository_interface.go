@@ -3,19 +3,50 @@ package interfaces
 import (
 	"context"
 	"mecanica_xpto/internal/domain/entities"
+	"time"
 )
 
 // IEstimateRepository abstracts DynamoDB persistence for Estimate.
 //
 // The billing-service must be able to:
 //   - create an estimate when OS Service requests calculation
-//   - update estimate status by OS ID (approve/reject/cancel)
+//   - update estimate status by OS ID (approve/reject/cancel), with its reason and actor
 //   - update estimate value by estimate ID (recalculation with additional repairs)
+//   - list estimates by status (receivables aging, expiration, internal approval queue)
+//
+// CreateWithRedemptions and UpdatePricing count the coupon redemptions in the same
+// transaction as the estimate write, failing with entities.ErrCouponUnavailable or
+// entities.ErrCouponCustomerLimit. UpdatePricing replaces the pricing fields (price,
+// subtotal, items, taxes, discounts) only if the estimate is unchanged since
+// previousUpdatedAt (entities.ErrEstimateChanged otherwise); the partial approval also
+// writes its status, declined items and history through it, in the same condition, and so
+// does the internal approval decision.
+//
+// UpdateStatus and Expire append the status change to the estimate history, dated when
+// written. UpdateStatus writes only if the estimate still has the status and UpdatedAt
+// current was read with (entities.ErrEstimateChanged otherwise), so the checks made on
+// current hold when the decision is written.
+//
+// AddBalanceDue adds delta once per ref (e.g. "dispute#<id>"): a ref already added leaves
+// the balance alone and returns an empty Estimate, so a retried reversal is not charged
+// twice.
+//
+// EraseDecisionEvidence removes the IP, user agent and recipient of the approval link
+// decisions in the history (LGPD erasure), under the same UpdatedAt condition as
+// UpdateStatus.
+//
+// Expire sets a pending estimate expirado only if its ExpiresAt is still expiresAt, i.e.
+// it was neither decided nor renewed since read; otherwise it returns an empty Estimate.
 
 type IEstimateRepository interface {
 	Create(ctx context.Context, e entities.Estimate) (entities.Estimate, error)
 	GetByID(ctx context.Context, id string) (entities.Estimate, error)
 	GetByOSID(ctx context.Context, osID string) (entities.Estimate, error)
-	UpdateStatusByOSID(ctx context.Context, osID string, status entities.EstimateStatus) (entities.Estimate, error)
-	UpdatePriceByID(ctx context.Context, id string, newPrice float64) (entities.Estimate, error)
+	UpdateStatus(ctx context.Context, current entities.Estimate, change entities.EstimateStatusChange) (entities.Estimate, error)
+	CreateWithRedemptions(ctx context.Context, e entities.Estimate, redemptions []entities.CouponRedemption) (entities.Estimate, error)
+	UpdatePricing(ctx context.Context, e entities.Estimate, previousUpdatedAt time.Time, redemptions []entities.CouponRedemption) (entities.Estimate, error)
+	AddBalanceDue(ctx context.Context, id string, delta float64, ref string) (entities.Estimate, error)
+	ListByStatus(ctx context.Context, status entities.EstimateStatus) ([]entities.Estimate, error)
+	Expire(ctx context.Context, id string, expiresAt time.Time) (entities.Estimate, error)
+	EraseDecisionEvidence(ctx context.Context, current entities.Estimate, linkIDs []string) (entities.Estimate, error)
 }
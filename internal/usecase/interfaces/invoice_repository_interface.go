@@ -0,0 +1,26 @@
+package interfaces
+
+import (
+	"context"
+	"mecanica_xpto/internal/domain/entities"
+)
+
+// IInvoiceRepository persists invoices and their sequential numbers.
+//
+// Notes:
+//   - Create assigns the next invoice number and reserves the estimate in the same
+//     transaction; it returns entities.ErrInvoiceAlreadyIssued when the estimate already
+//     has an active invoice. Numbers have no gaps.
+//   - Update replaces the invoice if its Version is unchanged (entities.ErrInvoiceChanged
+//     otherwise) and releases the estimate when the invoice is voided.
+//   - AddCreditNote appends note with the next credit note number, under the same
+//     version check.
+//   - Get methods return an empty invoice when it does not exist.
+
+type IInvoiceRepository interface {
+	Create(ctx context.Context, inv entities.Invoice) (entities.Invoice, error)
+	GetByID(ctx context.Context, id string) (entities.Invoice, error)
+	GetActiveByEstimateID(ctx context.Context, estimateID string) (entities.Invoice, error)
+	Update(ctx context.Context, inv entities.Invoice) (entities.Invoice, error)
+	AddCreditNote(ctx context.Context, inv entities.Invoice, note entities.CreditNote) (entities.Invoice, error)
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"io"
+	"mecanica_xpto/internal/domain/entities"
+)
+
+// IJournalFormatter writes journal entries in an accounting export format.
+type IJournalFormatter interface {
+	Write(w io.Writer, run entities.AccountingExport, entries []entities.JournalEntry) error
+	ContentType() string
+	FileExtension() string
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import "context"
+
+// IKeyProvider issues and unwraps data keys for envelope encryption.
+//
+// The contract follows the KMS GenerateDataKey/Decrypt model, so the same code
+// works with a local key file (development) or a managed KMS (production).
+type IKeyProvider interface {
+	CurrentKeyID() string
+	GenerateDataKey(ctx context.Context) (plaintext []byte, wrapped []byte, keyID string, err error)
+	DecryptDataKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
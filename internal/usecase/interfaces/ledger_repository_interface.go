@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"context"
+	"mecanica_xpto/internal/domain/entities"
+)
+
+// ILedgerRepository reads and posts the double-entry ledger.
+//
+// Notes:
+//   - Post validates the entry and rejects an ID already posted with
+//     entities.ErrLedgerConflict. Postings canceled by a concurrent one are retried
+//     before an error is returned. Payment writes post through the payment repository,
+//     in the same transaction; Post is for the backfill.
+//   - GetAccountBalance sums the estimate balances of the account and returns a zero
+//     balance for an account without entries.
+
+type ILedgerRepository interface {
+	Post(ctx context.Context, entry entities.LedgerEntry) error
+	GetAccountBalance(ctx context.Context, account entities.LedgerAccount) (entities.LedgerBalance, error)
+	ListEstimateBalances(ctx context.Context, estimateID string) ([]entities.LedgerBalance, error)
+	ListEntriesByEstimate(ctx context.Context, estimateID string) ([]entities.LedgerEntry, error)
+}
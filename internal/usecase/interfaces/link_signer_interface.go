@@ -0,0 +1,14 @@
+package interfaces
+
+import "time"
+
+// ILinkSigner signs the tokens of the approval links and keys the digests of their OTPs.
+//
+// Sign binds the link ID to its expiration; Verify returns the ID of a token it signed
+// that is not expired at the given time. Digest is a keyed, non-reversible hash, so stored
+// OTPs cannot be guessed offline.
+type ILinkSigner interface {
+	Sign(id string, expiresAt time.Time) string
+	Verify(token string, at time.Time) (string, error)
+	Digest(value string) string
+}
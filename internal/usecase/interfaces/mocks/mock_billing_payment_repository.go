@@ -13,6 +13,7 @@ import (
 	context "context"
 	entities "mecanica_xpto/internal/domain/entities"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -41,19 +42,62 @@ func (m *MockIBillingPaymentRepository) EXPECT() *MockIBillingPaymentRepositoryM
 	return m.recorder
 }
 
-// Create mocks base method.
-func (m *MockIBillingPaymentRepository) Create(ctx context.Context, p entities.BillingPayment) (entities.BillingPayment, error) {
+// Anonymize mocks base method.
+func (m *MockIBillingPaymentRepository) Anonymize(ctx context.Context, p entities.BillingPayment, at time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, p)
+	ret := m.ctrl.Call(m, "Anonymize", ctx, p, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Anonymize indicates an expected call of Anonymize.
+func (mr *MockIBillingPaymentRepositoryMockRecorder) Anonymize(ctx, p, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Anonymize", reflect.TypeOf((*MockIBillingPaymentRepository)(nil).Anonymize), ctx, p, at)
+}
+
+// CompletePosting mocks base method.
+func (m *MockIBillingPaymentRepository) CompletePosting(ctx context.Context, posting entities.PaymentPosting) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompletePosting", ctx, posting)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompletePosting indicates an expected call of CompletePosting.
+func (mr *MockIBillingPaymentRepositoryMockRecorder) CompletePosting(ctx, posting any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompletePosting", reflect.TypeOf((*MockIBillingPaymentRepository)(nil).CompletePosting), ctx, posting)
+}
+
+// CreateWithPendingPosting mocks base method.
+func (m *MockIBillingPaymentRepository) CreateWithPendingPosting(ctx context.Context, p entities.BillingPayment, posting entities.PaymentPosting) (entities.BillingPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWithPendingPosting", ctx, p, posting)
 	ret0, _ := ret[0].(entities.BillingPayment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Create indicates an expected call of Create.
-func (mr *MockIBillingPaymentRepositoryMockRecorder) Create(ctx, p any) *gomock.Call {
+// CreateWithPendingPosting indicates an expected call of CreateWithPendingPosting.
+func (mr *MockIBillingPaymentRepositoryMockRecorder) CreateWithPendingPosting(ctx, p, posting any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIBillingPaymentRepository)(nil).Create), ctx, p)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWithPendingPosting", reflect.TypeOf((*MockIBillingPaymentRepository)(nil).CreateWithPendingPosting), ctx, p, posting)
+}
+
+// CreateWithPosting mocks base method.
+func (m *MockIBillingPaymentRepository) CreateWithPosting(ctx context.Context, p entities.BillingPayment, posting entities.PaymentPosting) (entities.BillingPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWithPosting", ctx, p, posting)
+	ret0, _ := ret[0].(entities.BillingPayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWithPosting indicates an expected call of CreateWithPosting.
+func (mr *MockIBillingPaymentRepositoryMockRecorder) CreateWithPosting(ctx, p, posting any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWithPosting", reflect.TypeOf((*MockIBillingPaymentRepository)(nil).CreateWithPosting), ctx, p, posting)
 }
 
 // GetByID mocks base method.
@@ -72,16 +116,164 @@ func (mr *MockIBillingPaymentRepositoryMockRecorder) GetByID(ctx, id any) *gomoc
 }
 
 // ListByEstimateID mocks base method.
-func (m *MockIBillingPaymentRepository) ListByEstimateID(ctx context.Context, estimateID string) ([]entities.BillingPayment, error) {
+func (m *MockIBillingPaymentRepository) ListByEstimateID(ctx context.Context, estimateID string, page entities.PageRequest) (entities.Page[entities.BillingPayment], error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListByEstimateID", ctx, estimateID)
-	ret0, _ := ret[0].([]entities.BillingPayment)
+	ret := m.ctrl.Call(m, "ListByEstimateID", ctx, estimateID, page)
+	ret0, _ := ret[0].(entities.Page[entities.BillingPayment])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListByEstimateID indicates an expected call of ListByEstimateID.
-func (mr *MockIBillingPaymentRepositoryMockRecorder) ListByEstimateID(ctx, estimateID any) *gomock.Call {
+func (mr *MockIBillingPaymentRepositoryMockRecorder) ListByEstimateID(ctx, estimateID, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByEstimateID", reflect.TypeOf((*MockIBillingPaymentRepository)(nil).ListByEstimateID), ctx, estimateID, page)
+}
+
+// ListByPayerDocHash mocks base method.
+func (m *MockIBillingPaymentRepository) ListByPayerDocHash(ctx context.Context, hash string) ([]entities.BillingPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByPayerDocHash", ctx, hash)
+	ret0, _ := ret[0].([]entities.BillingPayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByPayerDocHash indicates an expected call of ListByPayerDocHash.
+func (mr *MockIBillingPaymentRepositoryMockRecorder) ListByPayerDocHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByPayerDocHash", reflect.TypeOf((*MockIBillingPaymentRepository)(nil).ListByPayerDocHash), ctx, hash)
+}
+
+// ListByPayerEmailHash mocks base method.
+func (m *MockIBillingPaymentRepository) ListByPayerEmailHash(ctx context.Context, hash string) ([]entities.BillingPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByPayerEmailHash", ctx, hash)
+	ret0, _ := ret[0].([]entities.BillingPayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByPayerEmailHash indicates an expected call of ListByPayerEmailHash.
+func (mr *MockIBillingPaymentRepositoryMockRecorder) ListByPayerEmailHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByPayerEmailHash", reflect.TypeOf((*MockIBillingPaymentRepository)(nil).ListByPayerEmailHash), ctx, hash)
+}
+
+// ListByStatus mocks base method.
+func (m *MockIBillingPaymentRepository) ListByStatus(ctx context.Context, status entities.PaymentStatus, from *time.Time, to *time.Time, page entities.PageRequest) (entities.Page[entities.BillingPayment], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByStatus", ctx, status, from, to, page)
+	ret0, _ := ret[0].(entities.Page[entities.BillingPayment])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByStatus indicates an expected call of ListByStatus.
+func (mr *MockIBillingPaymentRepositoryMockRecorder) ListByStatus(ctx, status, from, to, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByStatus", reflect.TypeOf((*MockIBillingPaymentRepository)(nil).ListByStatus), ctx, status, from, to, page)
+}
+
+// ListPendingPostings mocks base method.
+func (m *MockIBillingPaymentRepository) ListPendingPostings(ctx context.Context) ([]entities.PaymentPosting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingPostings", ctx)
+	ret0, _ := ret[0].([]entities.PaymentPosting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingPostings indicates an expected call of ListPendingPostings.
+func (mr *MockIBillingPaymentRepositoryMockRecorder) ListPendingPostings(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingPostings", reflect.TypeOf((*MockIBillingPaymentRepository)(nil).ListPendingPostings), ctx)
+}
+
+// Replace mocks base method.
+func (m *MockIBillingPaymentRepository) Replace(ctx context.Context, p entities.BillingPayment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", ctx, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockIBillingPaymentRepositoryMockRecorder) Replace(ctx, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockIBillingPaymentRepository)(nil).Replace), ctx, p)
+}
+
+// RewriteDate mocks base method.
+func (m *MockIBillingPaymentRepository) RewriteDate(ctx context.Context, id string, date time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RewriteDate", ctx, id, date)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RewriteDate indicates an expected call of RewriteDate.
+func (mr *MockIBillingPaymentRepositoryMockRecorder) RewriteDate(ctx, id, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RewriteDate", reflect.TypeOf((*MockIBillingPaymentRepository)(nil).RewriteDate), ctx, id, date)
+}
+
+// ScanLegacyDatePage mocks base method.
+func (m *MockIBillingPaymentRepository) ScanLegacyDatePage(ctx context.Context, startAfterID string, limit int32) ([]entities.BillingPayment, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScanLegacyDatePage", ctx, startAfterID, limit)
+	ret0, _ := ret[0].([]entities.BillingPayment)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ScanLegacyDatePage indicates an expected call of ScanLegacyDatePage.
+func (mr *MockIBillingPaymentRepositoryMockRecorder) ScanLegacyDatePage(ctx, startAfterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanLegacyDatePage", reflect.TypeOf((*MockIBillingPaymentRepository)(nil).ScanLegacyDatePage), ctx, startAfterID, limit)
+}
+
+// ScanPage mocks base method.
+func (m *MockIBillingPaymentRepository) ScanPage(ctx context.Context, startAfterID string, limit int32) ([]entities.BillingPayment, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScanPage", ctx, startAfterID, limit)
+	ret0, _ := ret[0].([]entities.BillingPayment)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ScanPage indicates an expected call of ScanPage.
+func (mr *MockIBillingPaymentRepositoryMockRecorder) ScanPage(ctx, startAfterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanPage", reflect.TypeOf((*MockIBillingPaymentRepository)(nil).ScanPage), ctx, startAfterID, limit)
+}
+
+// UpdateDetails mocks base method.
+func (m *MockIBillingPaymentRepository) UpdateDetails(ctx context.Context, id string, details entities.PaymentDetails) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDetails", ctx, id, details)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDetails indicates an expected call of UpdateDetails.
+func (mr *MockIBillingPaymentRepositoryMockRecorder) UpdateDetails(ctx, id, details any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDetails", reflect.TypeOf((*MockIBillingPaymentRepository)(nil).UpdateDetails), ctx, id, details)
+}
+
+// UpdateStatusWithEvent mocks base method.
+func (m *MockIBillingPaymentRepository) UpdateStatusWithEvent(ctx context.Context, event entities.PaymentStatusEvent, entry *entities.LedgerEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatusWithEvent", ctx, event, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatusWithEvent indicates an expected call of UpdateStatusWithEvent.
+func (mr *MockIBillingPaymentRepositoryMockRecorder) UpdateStatusWithEvent(ctx, event, entry any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByEstimateID", reflect.TypeOf((*MockIBillingPaymentRepository)(nil).ListByEstimateID), ctx, estimateID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatusWithEvent", reflect.TypeOf((*MockIBillingPaymentRepository)(nil).UpdateStatusWithEvent), ctx, event, entry)
 }
@@ -13,6 +13,7 @@ import (
 	context "context"
 	entities "mecanica_xpto/internal/domain/entities"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -41,6 +42,21 @@ func (m *MockIEstimateRepository) EXPECT() *MockIEstimateRepositoryMockRecorder
 	return m.recorder
 }
 
+// AddBalanceDue mocks base method.
+func (m *MockIEstimateRepository) AddBalanceDue(ctx context.Context, id string, delta float64, ref string) (entities.Estimate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBalanceDue", ctx, id, delta, ref)
+	ret0, _ := ret[0].(entities.Estimate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddBalanceDue indicates an expected call of AddBalanceDue.
+func (mr *MockIEstimateRepositoryMockRecorder) AddBalanceDue(ctx, id, delta, ref any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBalanceDue", reflect.TypeOf((*MockIEstimateRepository)(nil).AddBalanceDue), ctx, id, delta, ref)
+}
+
 // Create mocks base method.
 func (m *MockIEstimateRepository) Create(ctx context.Context, e entities.Estimate) (entities.Estimate, error) {
 	m.ctrl.T.Helper()
@@ -56,6 +72,51 @@ func (mr *MockIEstimateRepositoryMockRecorder) Create(ctx, e any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIEstimateRepository)(nil).Create), ctx, e)
 }
 
+// CreateWithRedemptions mocks base method.
+func (m *MockIEstimateRepository) CreateWithRedemptions(ctx context.Context, e entities.Estimate, redemptions []entities.CouponRedemption) (entities.Estimate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWithRedemptions", ctx, e, redemptions)
+	ret0, _ := ret[0].(entities.Estimate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWithRedemptions indicates an expected call of CreateWithRedemptions.
+func (mr *MockIEstimateRepositoryMockRecorder) CreateWithRedemptions(ctx, e, redemptions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWithRedemptions", reflect.TypeOf((*MockIEstimateRepository)(nil).CreateWithRedemptions), ctx, e, redemptions)
+}
+
+// EraseDecisionEvidence mocks base method.
+func (m *MockIEstimateRepository) EraseDecisionEvidence(ctx context.Context, current entities.Estimate, linkIDs []string) (entities.Estimate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EraseDecisionEvidence", ctx, current, linkIDs)
+	ret0, _ := ret[0].(entities.Estimate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EraseDecisionEvidence indicates an expected call of EraseDecisionEvidence.
+func (mr *MockIEstimateRepositoryMockRecorder) EraseDecisionEvidence(ctx, current, linkIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EraseDecisionEvidence", reflect.TypeOf((*MockIEstimateRepository)(nil).EraseDecisionEvidence), ctx, current, linkIDs)
+}
+
+// Expire mocks base method.
+func (m *MockIEstimateRepository) Expire(ctx context.Context, id string, expiresAt time.Time) (entities.Estimate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Expire", ctx, id, expiresAt)
+	ret0, _ := ret[0].(entities.Estimate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Expire indicates an expected call of Expire.
+func (mr *MockIEstimateRepositoryMockRecorder) Expire(ctx, id, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expire", reflect.TypeOf((*MockIEstimateRepository)(nil).Expire), ctx, id, expiresAt)
+}
+
 // GetByID mocks base method.
 func (m *MockIEstimateRepository) GetByID(ctx context.Context, id string) (entities.Estimate, error) {
 	m.ctrl.T.Helper()
@@ -86,32 +147,47 @@ func (mr *MockIEstimateRepositoryMockRecorder) GetByOSID(ctx, osID any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByOSID", reflect.TypeOf((*MockIEstimateRepository)(nil).GetByOSID), ctx, osID)
 }
 
-// UpdatePriceByID mocks base method.
-func (m *MockIEstimateRepository) UpdatePriceByID(ctx context.Context, id string, newPrice float64) (entities.Estimate, error) {
+// ListByStatus mocks base method.
+func (m *MockIEstimateRepository) ListByStatus(ctx context.Context, status entities.EstimateStatus) ([]entities.Estimate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByStatus", ctx, status)
+	ret0, _ := ret[0].([]entities.Estimate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByStatus indicates an expected call of ListByStatus.
+func (mr *MockIEstimateRepositoryMockRecorder) ListByStatus(ctx, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByStatus", reflect.TypeOf((*MockIEstimateRepository)(nil).ListByStatus), ctx, status)
+}
+
+// UpdatePricing mocks base method.
+func (m *MockIEstimateRepository) UpdatePricing(ctx context.Context, e entities.Estimate, previousUpdatedAt time.Time, redemptions []entities.CouponRedemption) (entities.Estimate, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdatePriceByID", ctx, id, newPrice)
+	ret := m.ctrl.Call(m, "UpdatePricing", ctx, e, previousUpdatedAt, redemptions)
 	ret0, _ := ret[0].(entities.Estimate)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdatePriceByID indicates an expected call of UpdatePriceByID.
-func (mr *MockIEstimateRepositoryMockRecorder) UpdatePriceByID(ctx, id, newPrice any) *gomock.Call {
+// UpdatePricing indicates an expected call of UpdatePricing.
+func (mr *MockIEstimateRepositoryMockRecorder) UpdatePricing(ctx, e, previousUpdatedAt, redemptions any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePriceByID", reflect.TypeOf((*MockIEstimateRepository)(nil).UpdatePriceByID), ctx, id, newPrice)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePricing", reflect.TypeOf((*MockIEstimateRepository)(nil).UpdatePricing), ctx, e, previousUpdatedAt, redemptions)
 }
 
-// UpdateStatusByOSID mocks base method.
-func (m *MockIEstimateRepository) UpdateStatusByOSID(ctx context.Context, osID string, status entities.EstimateStatus) (entities.Estimate, error) {
+// UpdateStatus mocks base method.
+func (m *MockIEstimateRepository) UpdateStatus(ctx context.Context, current entities.Estimate, change entities.EstimateStatusChange) (entities.Estimate, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateStatusByOSID", ctx, osID, status)
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, current, change)
 	ret0, _ := ret[0].(entities.Estimate)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateStatusByOSID indicates an expected call of UpdateStatusByOSID.
-func (mr *MockIEstimateRepositoryMockRecorder) UpdateStatusByOSID(ctx, osID, status any) *gomock.Call {
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockIEstimateRepositoryMockRecorder) UpdateStatus(ctx, current, change any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatusByOSID", reflect.TypeOf((*MockIEstimateRepository)(nil).UpdateStatusByOSID), ctx, osID, status)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockIEstimateRepository)(nil).UpdateStatus), ctx, current, change)
 }
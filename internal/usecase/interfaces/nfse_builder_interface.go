@@ -0,0 +1,11 @@
+package interfaces
+
+import "mecanica_xpto/internal/domain/entities"
+
+// INFSeBuilder renders an RPS as the municipality XML and signs it (XMLDSig). Series is
+// the RPS series configured for this provider.
+
+type INFSeBuilder interface {
+	Series() string
+	Build(rps entities.NFSeRPS) ([]byte, error)
+}
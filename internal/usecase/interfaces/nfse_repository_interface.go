@@ -0,0 +1,27 @@
+package interfaces
+
+import (
+	"context"
+	"mecanica_xpto/internal/domain/entities"
+)
+
+// INFSeRepository persists signed NFS-e documents.
+//
+// Notes:
+//   - RPS numbers are gapless: Create stores doc only if doc.RPS.Number is the number
+//     right after CurrentRPSNumber, returning entities.ErrRPSNumberTaken otherwise (the
+//     caller rebuilds and re-signs with the next number).
+//   - Create returns entities.ErrNFSeAlreadyGenerated when the invoice already has a document.
+//   - UpdateTransmission stores status, protocol, error, attempts and transmitted_at.
+//   - Get methods return an empty document when it does not exist.
+//   - ListByTomador...Hash look documents up by the blind indexes of the tomador.
+
+type INFSeRepository interface {
+	CurrentRPSNumber(ctx context.Context) (int64, error)
+	Create(ctx context.Context, doc entities.NFSeDocument) error
+	GetByID(ctx context.Context, id string) (entities.NFSeDocument, error)
+	GetByInvoiceID(ctx context.Context, invoiceID string) (entities.NFSeDocument, error)
+	UpdateTransmission(ctx context.Context, doc entities.NFSeDocument) error
+	ListByTomadorDocHash(ctx context.Context, hash string) ([]entities.NFSeDocument, error)
+	ListByTomadorEmailHash(ctx context.Context, hash string) ([]entities.NFSeDocument, error)
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+	"mecanica_xpto/internal/domain/entities"
+)
+
+// INFSeTransmitter delivers a signed document to the municipality and returns the
+// reception protocol.
+
+type INFSeTransmitter interface {
+	Transmit(ctx context.Context, doc entities.NFSeDocument) (string, error)
+}
@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+	"mecanica_xpto/internal/domain/entities"
+)
+
+// INotifier delivers a message to a customer by e-mail or SMS.
+
+type INotifier interface {
+	Notify(ctx context.Context, n entities.Notification) error
+}
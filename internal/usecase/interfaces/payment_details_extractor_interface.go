@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"mecanica_xpto/internal/domain/entities"
+)
+
+// IPaymentDetailsExtractor turns a provider payment payload into typed payment details.
+//
+// Implemented by the payment gateway adapters, which know the provider schema.
+type IPaymentDetailsExtractor interface {
+	ExtractDetails(providerResponse json.RawMessage) (entities.PaymentDetails, error)
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// IPaymentPayloadValidator checks an outgoing provider payload before it is sent.
+//
+// Validate returns the JSON pointers (RFC 6901) of the offending fields; an empty
+// slice means the payload is valid. The error is reserved for validator failures
+// (e.g. unparsable payload or missing schema), not for schema violations.
+type IPaymentPayloadValidator interface {
+	Validate(ctx context.Context, payload json.RawMessage) ([]string, error)
+}
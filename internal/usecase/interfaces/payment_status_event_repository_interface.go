@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+	"mecanica_xpto/internal/domain/entities"
+	"time"
+)
+
+// IPaymentStatusEventRepository abstracts the append-only payment status history.
+//
+// ListByStatusBetween returns the events of every payment that reported status within
+// [from, to], oldest first (accounting export).
+
+type IPaymentStatusEventRepository interface {
+	Append(ctx context.Context, e entities.PaymentStatusEvent) error
+	ListByPaymentID(ctx context.Context, paymentID string) ([]entities.PaymentStatusEvent, error)
+	ListByStatusBetween(ctx context.Context, status entities.PaymentStatus, from, to time.Time) ([]entities.PaymentStatusEvent, error)
+}
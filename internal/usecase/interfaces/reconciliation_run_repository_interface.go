@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+	"mecanica_xpto/internal/domain/entities"
+)
+
+// IReconciliationRunRepository abstracts persistence of settlement reconciliation runs.
+// Runs are immutable once stored.
+//
+// GetByID returns the run without its discrepancies (Summary holds their counts);
+// ForEachDiscrepancy reads them in the order they were found, without holding the
+// whole list in memory.
+type IReconciliationRunRepository interface {
+	Create(ctx context.Context, run entities.ReconciliationRun) error
+	GetByID(ctx context.Context, id string) (entities.ReconciliationRun, error)
+	ForEachDiscrepancy(ctx context.Context, runID string, fn func(entities.ReconciliationDiscrepancy) error) error
+}
@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+	"mecanica_xpto/internal/domain/entities"
+)
+
+// IRecordSealer envelope-encrypts personal data kept outside payments (NFS-e tomador,
+// approval link recipients) with the payment PII keys.
+//
+//   - Seal encrypts the non-empty plaintext values in place with a new data key bound to
+//     recordID and returns the wrapped key (nil when no key provider is configured).
+//   - Open decrypts in place the values sealed for recordID; a nil key leaves them as is.
+type IRecordSealer interface {
+	Seal(ctx context.Context, recordID string, values ...*string) (*entities.EncryptedDataKey, error)
+	Open(ctx context.Context, recordID string, key *entities.EncryptedDataKey, values ...*string) error
+}
@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+	"mecanica_xpto/internal/domain/entities"
+)
+
+// ISensitiveDataProtector encrypts/decrypts payer personal data stored with a payment.
+//
+//   - Protect encrypts every sensitive field with the current key (re-encrypting when
+//     the payment was protected with an older key) and fills the payer blind indexes.
+//   - Reveal returns the payment with sensitive fields in plaintext.
+//   - NeedsProtection reports whether Protect would change the stored payment.
+type ISensitiveDataProtector interface {
+	Protect(ctx context.Context, p entities.BillingPayment) (entities.BillingPayment, error)
+	Reveal(ctx context.Context, p entities.BillingPayment) (entities.BillingPayment, error)
+	NeedsProtection(p entities.BillingPayment) bool
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"io"
+	"mecanica_xpto/internal/domain/entities"
+)
+
+// ISettlementReportParser reads a provider settlement/release report (CSV) into
+// normalized lines.
+//
+// Implemented by the payment gateway adapters, which know the provider report layout.
+type ISettlementReportParser interface {
+	ParseSettlementReport(r io.Reader) ([]entities.SettlementLine, error)
+}
@@ -0,0 +1,392 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidInvoiceID           = errors.New("invalid invoice id")
+	ErrInvalidInvoiceEstimateID   = errors.New("invalid invoice estimate id")
+	ErrInvoiceNotFound            = errors.New("invoice not found")
+	ErrInvoiceEstimateNotApproved = errors.New("estimate not approved")
+	ErrInvoiceVoided              = errors.New("invoice voided")
+	ErrInvoiceNotVoidable         = errors.New("invoice has applied payments")
+	ErrInvalidInvoiceVoid         = errors.New("invalid invoice void")
+	ErrInvalidCreditNote          = errors.New("invalid credit note")
+	ErrInvoiceConflict            = errors.New("invoice changed concurrently")
+)
+
+// maxInvoiceUpdateAttempts bounds the re-reads when a payment races another invoice update.
+const maxInvoiceUpdateAttempts = 3
+
+// IInvoiceUseCase issues and maintains the invoices (faturas) of approved estimates.
+//
+// Notes:
+//   - Issue is idempotent: an estimate with an active invoice gets it back.
+//   - Captured payments are applied to the active invoice of their estimate; refunds and
+//     lost chargebacks take them back out.
+//   - An invoice with applied payments cannot be voided; a credit note reduces what is owed.
+//   - Approvals and payment transitions update invoices best effort; Repair (the
+//     repair-invoices command) catches up with the updates that failed.
+type IInvoiceUseCase interface {
+	Issue(ctx context.Context, estimateID, actor string) (entities.Invoice, error)
+	IssueForServiceOrder(ctx context.Context, osID, actor string) (entities.Invoice, error)
+	GetByID(ctx context.Context, id string) (entities.Invoice, error)
+	GetByEstimateID(ctx context.Context, estimateID string) (entities.Invoice, error)
+	Void(ctx context.Context, id, reason, actor string) (entities.Invoice, error)
+	AddCreditNote(ctx context.Context, id string, amount float64, reason, actor string) (entities.Invoice, error)
+	ApplyPayment(ctx context.Context, p entities.BillingPayment) error
+	ReversePayment(ctx context.Context, p entities.BillingPayment) error
+}
+
+type InvoiceUseCase struct {
+	invoices     interfaces.IInvoiceRepository
+	estimateRepo interfaces.IEstimateRepository
+	payments     interfaces.IBillingPaymentRepository
+	now          func() time.Time
+}
+
+var _ IInvoiceUseCase = (*InvoiceUseCase)(nil)
+
+func NewInvoiceUseCase(invoices interfaces.IInvoiceRepository, estimateRepo interfaces.IEstimateRepository, payments interfaces.IBillingPaymentRepository) *InvoiceUseCase {
+	return &InvoiceUseCase{invoices: invoices, estimateRepo: estimateRepo, payments: payments, now: time.Now}
+}
+
+// Issue snapshots an approved estimate into a new numbered invoice, applying the payments
+// already captured for it.
+func (u *InvoiceUseCase) Issue(ctx context.Context, estimateID, actor string) (entities.Invoice, error) {
+	estimateID = strings.TrimSpace(estimateID)
+	if estimateID == "" {
+		return entities.Invoice{}, ErrInvalidInvoiceEstimateID
+	}
+	if existing, err := u.invoices.GetActiveByEstimateID(ctx, estimateID); err != nil {
+		return entities.Invoice{}, err
+	} else if existing.ID != "" {
+		return existing, nil
+	}
+
+	est, err := u.estimateRepo.GetByID(ctx, estimateID)
+	if err != nil {
+		return entities.Invoice{}, err
+	}
+	if est.ID == "" {
+		return entities.Invoice{}, ErrEstimateNotFound
+	}
+	if est.Status != entities.EstimateStatusAprovado {
+		return entities.Invoice{}, ErrInvoiceEstimateNotApproved
+	}
+
+	now := u.now().UTC()
+	inv := entities.NewInvoiceFromEstimate(est, nil, now)
+	inv.ID = uuid.NewString()
+	inv.IssuedBy = actor
+	if inv.Payments, err = u.capturedPayments(ctx, estimateID, now); err != nil {
+		return entities.Invoice{}, err
+	}
+
+	created, err := u.invoices.Create(ctx, inv)
+	if errors.Is(err, entities.ErrInvoiceAlreadyIssued) {
+		// Issued concurrently (approval and a manual issue racing).
+		return u.GetByEstimateID(ctx, estimateID)
+	}
+	if err != nil {
+		return entities.Invoice{}, err
+	}
+	log.Printf("[invoice][usecase] issued invoice_id=%s number=%d estimate_id=%s total=%.2f", created.ID, created.Number, estimateID, created.Total)
+	return created, nil
+}
+
+// IssueForServiceOrder issues the invoice of the approved estimate of a closed service
+// order (OS). Like Issue, it returns the active invoice when there is one.
+func (u *InvoiceUseCase) IssueForServiceOrder(ctx context.Context, osID, actor string) (entities.Invoice, error) {
+	osID = strings.TrimSpace(osID)
+	if osID == "" {
+		return entities.Invoice{}, ErrInvalidOSID
+	}
+	est, err := u.estimateRepo.GetByOSID(ctx, osID)
+	if err != nil {
+		return entities.Invoice{}, err
+	}
+	if est.ID == "" {
+		return entities.Invoice{}, ErrEstimateNotFound
+	}
+	return u.Issue(ctx, est.ID, actor)
+}
+
+// Repair issues the missing invoices of approved estimates and applies or takes out the
+// payments their invoices missed, for when the best-effort update after an approval or a
+// payment transition failed. It can be re-run; with dryRun nothing is written.
+//
+// An estimate whose invoice was voided has no active invoice and gets a new one, as with
+// Issue.
+func (u *InvoiceUseCase) Repair(ctx context.Context, dryRun bool) (PaymentMigrationReport, error) {
+	var report PaymentMigrationReport
+	approved, err := u.estimateRepo.ListByStatus(ctx, entities.EstimateStatusAprovado)
+	if err != nil {
+		return report, err
+	}
+	for _, est := range approved {
+		report.Scanned++
+		changed, err := u.repairEstimate(ctx, est.ID, dryRun)
+		switch {
+		case err != nil:
+			log.Printf("[invoice][usecase] repair failed estimate_id=%s err=%v", est.ID, err)
+			report.Failed++
+		case changed:
+			report.Updated++
+		default:
+			report.Skipped++
+		}
+	}
+	return report, nil
+}
+
+func (u *InvoiceUseCase) repairEstimate(ctx context.Context, estimateID string, dryRun bool) (bool, error) {
+	inv, err := u.invoices.GetActiveByEstimateID(ctx, estimateID)
+	if err != nil {
+		return false, err
+	}
+	if inv.ID == "" {
+		if dryRun {
+			return true, nil
+		}
+		created, err := u.Issue(ctx, estimateID, "repair")
+		if err != nil {
+			return false, err
+		}
+		log.Printf("[invoice][usecase] repair issued invoice_id=%s estimate_id=%s", created.ID, estimateID)
+		return true, nil
+	}
+
+	captured, err := u.capturedPayments(ctx, estimateID, u.now().UTC())
+	if err != nil {
+		return false, err
+	}
+	if !syncInvoicePayments(&inv, captured) {
+		return false, nil
+	}
+	if dryRun {
+		return true, nil
+	}
+	err = u.updateActive(ctx, estimateID, func(inv *entities.Invoice) bool {
+		return syncInvoicePayments(inv, captured)
+	})
+	if err != nil {
+		return false, err
+	}
+	log.Printf("[invoice][usecase] repair synced payments invoice_id=%s estimate_id=%s", inv.ID, estimateID)
+	return true, nil
+}
+
+// syncInvoicePayments makes the invoice payments the captured ones, keeping when the
+// payments already applied were applied. It reports whether anything changed.
+func syncInvoicePayments(inv *entities.Invoice, captured []entities.InvoicePayment) bool {
+	isCaptured := make(map[string]bool, len(captured))
+	for _, ip := range captured {
+		isCaptured[ip.PaymentID] = true
+	}
+	kept := make([]entities.InvoicePayment, 0, len(captured))
+	for _, ip := range inv.Payments {
+		if isCaptured[ip.PaymentID] {
+			kept = append(kept, ip)
+		}
+	}
+	changed := len(kept) != len(inv.Payments)
+	for _, ip := range captured {
+		if !inv.HasPayment(ip.PaymentID) {
+			kept = append(kept, ip)
+			changed = true
+		}
+	}
+	if changed {
+		inv.Payments = kept
+	}
+	return changed
+}
+
+func (u *InvoiceUseCase) capturedPayments(ctx context.Context, estimateID string, at time.Time) ([]entities.InvoicePayment, error) {
+	applied := []entities.InvoicePayment{}
+	if u.payments == nil {
+		return applied, nil
+	}
+	page := entities.PageRequest{Limit: maxPaymentPageLimit}
+	for {
+		res, err := u.payments.ListByEstimateID(ctx, estimateID, page)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range res.Items {
+			if p.Status != entities.PaymentStatusAprovado && p.Status != entities.PaymentStatusContestado {
+				continue
+			}
+			if p.Details.Amount <= 0 {
+				log.Printf("[invoice][usecase] payment not applied payment_id=%s: amount missing", p.ID)
+				continue
+			}
+			applied = append(applied, entities.InvoicePayment{PaymentID: p.ID, Amount: p.Details.Amount, AppliedAt: at})
+		}
+		if res.NextCursor == "" {
+			return applied, nil
+		}
+		page.Cursor = res.NextCursor
+	}
+}
+
+func (u *InvoiceUseCase) GetByID(ctx context.Context, id string) (entities.Invoice, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return entities.Invoice{}, ErrInvalidInvoiceID
+	}
+	inv, err := u.invoices.GetByID(ctx, id)
+	if err != nil {
+		return entities.Invoice{}, err
+	}
+	if inv.ID == "" {
+		return entities.Invoice{}, ErrInvoiceNotFound
+	}
+	return inv, nil
+}
+
+// GetByEstimateID returns the active invoice of an estimate.
+func (u *InvoiceUseCase) GetByEstimateID(ctx context.Context, estimateID string) (entities.Invoice, error) {
+	estimateID = strings.TrimSpace(estimateID)
+	if estimateID == "" {
+		return entities.Invoice{}, ErrInvalidInvoiceEstimateID
+	}
+	inv, err := u.invoices.GetActiveByEstimateID(ctx, estimateID)
+	if err != nil {
+		return entities.Invoice{}, err
+	}
+	if inv.ID == "" {
+		return entities.Invoice{}, ErrInvoiceNotFound
+	}
+	return inv, nil
+}
+
+// Void cancels an invoice without payments, releasing the estimate for a new invoice.
+func (u *InvoiceUseCase) Void(ctx context.Context, id, reason, actor string) (entities.Invoice, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return entities.Invoice{}, ErrInvalidInvoiceVoid
+	}
+	inv, err := u.GetByID(ctx, id)
+	if err != nil {
+		return entities.Invoice{}, err
+	}
+	if inv.Status == entities.InvoiceStatusCancelada {
+		return entities.Invoice{}, ErrInvoiceVoided
+	}
+	if len(inv.Payments) > 0 {
+		return entities.Invoice{}, ErrInvoiceNotVoidable
+	}
+
+	now := u.now().UTC()
+	inv.Status = entities.InvoiceStatusCancelada
+	inv.VoidedAt = &now
+	inv.VoidReason = reason
+	inv.VoidedBy = actor
+	updated, err := u.invoices.Update(ctx, inv)
+	if errors.Is(err, entities.ErrInvoiceChanged) {
+		return entities.Invoice{}, ErrInvoiceConflict
+	}
+	if err != nil {
+		return entities.Invoice{}, err
+	}
+	log.Printf("[invoice][usecase] voided invoice_id=%s number=%d actor=%s", updated.ID, updated.Number, actor)
+	return updated, nil
+}
+
+// AddCreditNote credits amount on an active invoice; the credited total cannot exceed the
+// invoice total.
+func (u *InvoiceUseCase) AddCreditNote(ctx context.Context, id string, amount float64, reason, actor string) (entities.Invoice, error) {
+	reason = strings.TrimSpace(reason)
+	if amount <= 0 || reason == "" {
+		return entities.Invoice{}, ErrInvalidCreditNote
+	}
+	inv, err := u.GetByID(ctx, id)
+	if err != nil {
+		return entities.Invoice{}, err
+	}
+	if inv.Status == entities.InvoiceStatusCancelada {
+		return entities.Invoice{}, ErrInvoiceVoided
+	}
+	if entities.ToCents(inv.CreditedAmount()+amount) > entities.ToCents(inv.Total) {
+		return entities.Invoice{}, ErrInvalidCreditNote
+	}
+
+	updated, err := u.invoices.AddCreditNote(ctx, inv, entities.CreditNote{
+		Amount:    amount,
+		Reason:    reason,
+		Actor:     actor,
+		CreatedAt: u.now().UTC(),
+	})
+	if errors.Is(err, entities.ErrInvoiceChanged) {
+		return entities.Invoice{}, ErrInvoiceConflict
+	}
+	if err != nil {
+		return entities.Invoice{}, err
+	}
+	return updated, nil
+}
+
+// ApplyPayment applies a captured payment to the active invoice of its estimate. Payments
+// of estimates without an invoice and payments already applied are ignored.
+func (u *InvoiceUseCase) ApplyPayment(ctx context.Context, p entities.BillingPayment) error {
+	if p.Details.Amount <= 0 {
+		log.Printf("[invoice][usecase] payment not applied payment_id=%s: amount missing", p.ID)
+		return nil
+	}
+	return u.updateActive(ctx, p.EstimateID, func(inv *entities.Invoice) bool {
+		if inv.HasPayment(p.ID) {
+			return false
+		}
+		inv.Payments = append(inv.Payments, entities.InvoicePayment{PaymentID: p.ID, Amount: p.Details.Amount, AppliedAt: u.now().UTC()})
+		return true
+	})
+}
+
+// ReversePayment takes a refunded or charged back payment out of its invoice.
+func (u *InvoiceUseCase) ReversePayment(ctx context.Context, p entities.BillingPayment) error {
+	return u.updateActive(ctx, p.EstimateID, func(inv *entities.Invoice) bool {
+		kept := make([]entities.InvoicePayment, 0, len(inv.Payments))
+		for _, ip := range inv.Payments {
+			if ip.PaymentID != p.ID {
+				kept = append(kept, ip)
+			}
+		}
+		if len(kept) == len(inv.Payments) {
+			return false
+		}
+		inv.Payments = kept
+		return true
+	})
+}
+
+// updateActive applies change to the active invoice of an estimate, re-reading it when
+// another update won the race.
+func (u *InvoiceUseCase) updateActive(ctx context.Context, estimateID string, change func(inv *entities.Invoice) bool) error {
+	for attempt := 0; attempt < maxInvoiceUpdateAttempts; attempt++ {
+		inv, err := u.invoices.GetActiveByEstimateID(ctx, estimateID)
+		if err != nil {
+			return err
+		}
+		if inv.ID == "" || !change(&inv) {
+			return nil
+		}
+		_, err = u.invoices.Update(ctx, inv)
+		if errors.Is(err, entities.ErrInvoiceChanged) {
+			continue
+		}
+		return err
+	}
+	return ErrInvoiceConflict
+}
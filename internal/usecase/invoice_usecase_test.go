@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	mock_interfaces "mecanica_xpto/internal/usecase/interfaces/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestInvoiceUseCase_Issue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	invoices := mock_interfaces.NewMockIInvoiceRepository(ctrl)
+	estimates := mock_interfaces.NewMockIEstimateRepository(ctrl)
+	payments := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+	uc := NewInvoiceUseCase(invoices, estimates, payments)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	uc.now = func() time.Time { return now }
+
+	invoices.EXPECT().GetActiveByEstimateID(gomock.Any(), "e-pend").Return(entities.Invoice{}, nil)
+	estimates.EXPECT().GetByID(gomock.Any(), "e-pend").Return(entities.Estimate{ID: "e-pend", Status: entities.EstimateStatusPendente}, nil)
+	if _, err := uc.Issue(context.Background(), "e-pend", ""); !errors.Is(err, ErrInvoiceEstimateNotApproved) {
+		t.Fatalf("expected ErrInvoiceEstimateNotApproved, got %v", err)
+	}
+
+	invoices.EXPECT().GetActiveByEstimateID(gomock.Any(), "e1").Return(entities.Invoice{}, nil)
+	estimates.EXPECT().GetByID(gomock.Any(), "e1").Return(entities.Estimate{ID: "e1", OSID: "os-1", Price: 150.5, Status: entities.EstimateStatusAprovado}, nil)
+	payments.EXPECT().ListByEstimateID(gomock.Any(), "e1", gomock.Any()).Return(entities.Page[entities.BillingPayment]{Items: []entities.BillingPayment{
+		{ID: "p1", EstimateID: "e1", Status: entities.PaymentStatusAprovado, Details: entities.PaymentDetails{Amount: 100}},
+		{ID: "p2", EstimateID: "e1", Status: entities.PaymentStatusReembolsado, Details: entities.PaymentDetails{Amount: 50}},
+	}}, nil)
+	invoices.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, inv entities.Invoice) (entities.Invoice, error) {
+		inv.Number = 7
+		return inv, nil
+	})
+	inv, err := uc.Issue(context.Background(), "e1", "ops")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if inv.Number != 7 || inv.Total != 150.5 || inv.OSID != "os-1" || len(inv.Lines) != 1 || inv.IssuedBy != "ops" {
+		t.Fatalf("unexpected invoice: %+v", inv)
+	}
+	if len(inv.Payments) != 1 || inv.Payments[0].PaymentID != "p1" || inv.Balance() != 50.5 || inv.IsPaid() {
+		t.Fatalf("unexpected payments: %+v balance=%v", inv.Payments, inv.Balance())
+	}
+
+	// Idempotent: a concurrent issue returns the winner.
+	invoices.EXPECT().GetActiveByEstimateID(gomock.Any(), "e1").Return(entities.Invoice{}, nil)
+	estimates.EXPECT().GetByID(gomock.Any(), "e1").Return(entities.Estimate{ID: "e1", Price: 10, Status: entities.EstimateStatusAprovado}, nil)
+	payments.EXPECT().ListByEstimateID(gomock.Any(), "e1", gomock.Any()).Return(entities.Page[entities.BillingPayment]{}, nil)
+	invoices.EXPECT().Create(gomock.Any(), gomock.Any()).Return(entities.Invoice{}, entities.ErrInvoiceAlreadyIssued)
+	invoices.EXPECT().GetActiveByEstimateID(gomock.Any(), "e1").Return(entities.Invoice{ID: "inv-1", Number: 7}, nil)
+	if inv, err := uc.Issue(context.Background(), "e1", ""); err != nil || inv.ID != "inv-1" {
+		t.Fatalf("expected existing invoice, got %+v err=%v", inv, err)
+	}
+}
+
+func TestInvoiceUseCase_VoidAndCreditNote(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	invoices := mock_interfaces.NewMockIInvoiceRepository(ctrl)
+	uc := NewInvoiceUseCase(invoices, nil, nil)
+
+	paid := entities.Invoice{ID: "inv-1", Status: entities.InvoiceStatusEmitida, Total: 100,
+		Payments: []entities.InvoicePayment{{PaymentID: "p1", Amount: 100}}}
+	invoices.EXPECT().GetByID(gomock.Any(), "inv-1").Return(paid, nil).AnyTimes()
+	if _, err := uc.Void(context.Background(), "inv-1", "erro", "ops"); !errors.Is(err, ErrInvoiceNotVoidable) {
+		t.Fatalf("expected ErrInvoiceNotVoidable, got %v", err)
+	}
+	if _, err := uc.AddCreditNote(context.Background(), "inv-1", 100.01, "desconto", "ops"); !errors.Is(err, ErrInvalidCreditNote) {
+		t.Fatalf("expected ErrInvalidCreditNote, got %v", err)
+	}
+	invoices.EXPECT().AddCreditNote(gomock.Any(), paid, gomock.Any()).Return(entities.Invoice{}, entities.ErrInvoiceChanged)
+	if _, err := uc.AddCreditNote(context.Background(), "inv-1", 10, "desconto", "ops"); !errors.Is(err, ErrInvoiceConflict) {
+		t.Fatalf("expected ErrInvoiceConflict, got %v", err)
+	}
+
+	open := entities.Invoice{ID: "inv-2", EstimateID: "e2", Status: entities.InvoiceStatusEmitida, Total: 100}
+	invoices.EXPECT().GetByID(gomock.Any(), "inv-2").Return(open, nil)
+	invoices.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, inv entities.Invoice) (entities.Invoice, error) {
+		if inv.Status != entities.InvoiceStatusCancelada || inv.VoidReason != "erro" || inv.VoidedBy != "ops" || inv.VoidedAt == nil {
+			t.Fatalf("unexpected voided invoice: %+v", inv)
+		}
+		return inv, nil
+	})
+	if _, err := uc.Void(context.Background(), "inv-2", " erro ", "ops"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
+
+func TestInvoiceUseCase_ApplyAndReversePayment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	invoices := mock_interfaces.NewMockIInvoiceRepository(ctrl)
+	uc := NewInvoiceUseCase(invoices, nil, nil)
+	p := entities.BillingPayment{ID: "p1", EstimateID: "e1", Details: entities.PaymentDetails{Amount: 100}}
+
+	// Retried after a concurrent update.
+	invoices.EXPECT().GetActiveByEstimateID(gomock.Any(), "e1").Return(entities.Invoice{ID: "inv-1", Status: entities.InvoiceStatusEmitida, Total: 100, Version: 1}, nil)
+	invoices.EXPECT().Update(gomock.Any(), gomock.Any()).Return(entities.Invoice{}, entities.ErrInvoiceChanged)
+	invoices.EXPECT().GetActiveByEstimateID(gomock.Any(), "e1").Return(entities.Invoice{ID: "inv-1", Status: entities.InvoiceStatusEmitida, Total: 100, Version: 2}, nil)
+	invoices.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, inv entities.Invoice) (entities.Invoice, error) {
+		if inv.Version != 2 || !inv.HasPayment("p1") || !inv.IsPaid() {
+			t.Fatalf("unexpected invoice: %+v", inv)
+		}
+		return inv, nil
+	})
+	if err := uc.ApplyPayment(context.Background(), p); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	// Already applied: nothing written.
+	applied := entities.Invoice{ID: "inv-1", Total: 100, Payments: []entities.InvoicePayment{{PaymentID: "p1", Amount: 100}}}
+	invoices.EXPECT().GetActiveByEstimateID(gomock.Any(), "e1").Return(applied, nil)
+	if err := uc.ApplyPayment(context.Background(), p); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	invoices.EXPECT().GetActiveByEstimateID(gomock.Any(), "e1").Return(applied, nil)
+	invoices.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, inv entities.Invoice) (entities.Invoice, error) {
+		if len(inv.Payments) != 0 || inv.Balance() != 100 {
+			t.Fatalf("unexpected invoice: %+v", inv)
+		}
+		return inv, nil
+	})
+	if err := uc.ReversePayment(context.Background(), p); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	// No invoice for the estimate.
+	invoices.EXPECT().GetActiveByEstimateID(gomock.Any(), "e1").Return(entities.Invoice{}, nil)
+	if err := uc.ApplyPayment(context.Background(), p); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
+
+func TestInvoiceUseCase_IssueForServiceOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	invoices := mock_interfaces.NewMockIInvoiceRepository(ctrl)
+	estimates := mock_interfaces.NewMockIEstimateRepository(ctrl)
+	uc := NewInvoiceUseCase(invoices, estimates, nil)
+
+	if _, err := uc.IssueForServiceOrder(context.Background(), " ", ""); !errors.Is(err, ErrInvalidOSID) {
+		t.Fatalf("expected ErrInvalidOSID, got %v", err)
+	}
+
+	estimates.EXPECT().GetByOSID(gomock.Any(), "os-x").Return(entities.Estimate{}, nil)
+	if _, err := uc.IssueForServiceOrder(context.Background(), "os-x", ""); !errors.Is(err, ErrEstimateNotFound) {
+		t.Fatalf("expected ErrEstimateNotFound, got %v", err)
+	}
+
+	// Already issued on approval: the OS close gets the same invoice.
+	estimates.EXPECT().GetByOSID(gomock.Any(), "os-1").Return(entities.Estimate{ID: "e1", OSID: "os-1", Status: entities.EstimateStatusAprovado}, nil)
+	invoices.EXPECT().GetActiveByEstimateID(gomock.Any(), "e1").Return(entities.Invoice{ID: "inv-1", EstimateID: "e1"}, nil)
+	inv, err := uc.IssueForServiceOrder(context.Background(), "os-1", "os-service")
+	if err != nil || inv.ID != "inv-1" {
+		t.Fatalf("unexpected result inv=%+v err=%v", inv, err)
+	}
+}
+
+func TestInvoiceUseCase_Repair(t *testing.T) {
+	newUseCase := func(t *testing.T) (*InvoiceUseCase, *mock_interfaces.MockIInvoiceRepository, *mock_interfaces.MockIEstimateRepository, *mock_interfaces.MockIBillingPaymentRepository) {
+		ctrl := gomock.NewController(t)
+		invoices := mock_interfaces.NewMockIInvoiceRepository(ctrl)
+		estimates := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		payments := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		return NewInvoiceUseCase(invoices, estimates, payments), invoices, estimates, payments
+	}
+	applied := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("issues missing invoices and syncs payments", func(t *testing.T) {
+		uc, invoices, estimates, payments := newUseCase(t)
+		estimates.EXPECT().ListByStatus(gomock.Any(), entities.EstimateStatusAprovado).Return([]entities.Estimate{
+			{ID: "e-missing"}, {ID: "e-stale"}, {ID: "e-ok"}, {ID: "e-broken"},
+		}, nil)
+
+		// Approval failed to issue the invoice.
+		invoices.EXPECT().GetActiveByEstimateID(gomock.Any(), "e-missing").Return(entities.Invoice{}, nil).Times(2)
+		estimates.EXPECT().GetByID(gomock.Any(), "e-missing").Return(entities.Estimate{ID: "e-missing", Price: 80, Status: entities.EstimateStatusAprovado}, nil)
+		payments.EXPECT().ListByEstimateID(gomock.Any(), "e-missing", gomock.Any()).Return(entities.Page[entities.BillingPayment]{}, nil)
+		invoices.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, inv entities.Invoice) (entities.Invoice, error) {
+			if inv.EstimateID != "e-missing" || inv.IssuedBy != "repair" {
+				t.Fatalf("unexpected invoice: %+v", inv)
+			}
+			return inv, nil
+		})
+
+		// p1 was refunded and p3 approved, but both invoice updates failed.
+		stale := entities.Invoice{ID: "inv-stale", EstimateID: "e-stale", Total: 300, Payments: []entities.InvoicePayment{
+			{PaymentID: "p1", Amount: 100, AppliedAt: applied},
+			{PaymentID: "p2", Amount: 100, AppliedAt: applied},
+		}}
+		invoices.EXPECT().GetActiveByEstimateID(gomock.Any(), "e-stale").Return(stale, nil).Times(2)
+		payments.EXPECT().ListByEstimateID(gomock.Any(), "e-stale", gomock.Any()).Return(entities.Page[entities.BillingPayment]{Items: []entities.BillingPayment{
+			{ID: "p1", Status: entities.PaymentStatusReembolsado, Details: entities.PaymentDetails{Amount: 100}},
+			{ID: "p2", Status: entities.PaymentStatusAprovado, Details: entities.PaymentDetails{Amount: 100}},
+			{ID: "p3", Status: entities.PaymentStatusAprovado, Details: entities.PaymentDetails{Amount: 100}},
+		}}, nil)
+		invoices.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, inv entities.Invoice) (entities.Invoice, error) {
+			if len(inv.Payments) != 2 || inv.HasPayment("p1") || !inv.HasPayment("p3") || !inv.Payments[0].AppliedAt.Equal(applied) {
+				t.Fatalf("unexpected payments: %+v", inv.Payments)
+			}
+			return inv, nil
+		})
+
+		invoices.EXPECT().GetActiveByEstimateID(gomock.Any(), "e-ok").Return(entities.Invoice{ID: "inv-ok", Payments: []entities.InvoicePayment{{PaymentID: "p9", Amount: 10}}}, nil)
+		payments.EXPECT().ListByEstimateID(gomock.Any(), "e-ok", gomock.Any()).Return(entities.Page[entities.BillingPayment]{Items: []entities.BillingPayment{
+			{ID: "p9", Status: entities.PaymentStatusAprovado, Details: entities.PaymentDetails{Amount: 10}},
+		}}, nil)
+
+		invoices.EXPECT().GetActiveByEstimateID(gomock.Any(), "e-broken").Return(entities.Invoice{}, errors.New("ddb"))
+
+		report, err := uc.Repair(context.Background(), false)
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		if report != (PaymentMigrationReport{Scanned: 4, Updated: 2, Skipped: 1, Failed: 1}) {
+			t.Fatalf("unexpected report: %+v", report)
+		}
+	})
+
+	t.Run("dry run writes nothing", func(t *testing.T) {
+		uc, invoices, estimates, payments := newUseCase(t)
+		estimates.EXPECT().ListByStatus(gomock.Any(), entities.EstimateStatusAprovado).Return([]entities.Estimate{{ID: "e-missing"}, {ID: "e-stale"}}, nil)
+		invoices.EXPECT().GetActiveByEstimateID(gomock.Any(), "e-missing").Return(entities.Invoice{}, nil)
+		invoices.EXPECT().GetActiveByEstimateID(gomock.Any(), "e-stale").Return(entities.Invoice{ID: "inv-stale"}, nil)
+		payments.EXPECT().ListByEstimateID(gomock.Any(), "e-stale", gomock.Any()).Return(entities.Page[entities.BillingPayment]{Items: []entities.BillingPayment{
+			{ID: "p3", Status: entities.PaymentStatusAprovado, Details: entities.PaymentDetails{Amount: 100}},
+		}}, nil)
+
+		report, err := uc.Repair(context.Background(), true)
+		if err != nil || report.Updated != 2 {
+			t.Fatalf("unexpected result report=%+v err=%v", report, err)
+		}
+	})
+}
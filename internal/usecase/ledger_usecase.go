@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+)
+
+var (
+	ErrInvalidLedgerAccount    = errors.New("invalid ledger account")
+	ErrInvalidLedgerEstimateID = errors.New("invalid ledger estimate id")
+)
+
+// ILedgerUseCase serves the balances of the double-entry ledger.
+type ILedgerUseCase interface {
+	AccountBalances(ctx context.Context, account entities.LedgerAccount) ([]entities.LedgerBalance, error)
+	EstimateLedger(ctx context.Context, estimateID string) (entities.EstimateLedger, error)
+}
+
+type LedgerUseCase struct {
+	ledger   interfaces.ILedgerRepository
+	payments interfaces.IBillingPaymentRepository
+}
+
+var _ ILedgerUseCase = (*LedgerUseCase)(nil)
+
+func NewLedgerUseCase(ledger interfaces.ILedgerRepository, payments interfaces.IBillingPaymentRepository) *LedgerUseCase {
+	return &LedgerUseCase{ledger: ledger, payments: payments}
+}
+
+// AccountBalances returns the ledger-wide balance of account, or of every account when
+// account is empty.
+func (u *LedgerUseCase) AccountBalances(ctx context.Context, account entities.LedgerAccount) ([]entities.LedgerBalance, error) {
+	accounts := entities.LedgerAccounts
+	if account = entities.LedgerAccount(strings.TrimSpace(string(account))); account != "" {
+		if !account.IsValid() {
+			return nil, ErrInvalidLedgerAccount
+		}
+		accounts = []entities.LedgerAccount{account}
+	}
+
+	balances := make([]entities.LedgerBalance, 0, len(accounts))
+	for _, a := range accounts {
+		b, err := u.ledger.GetAccountBalance(ctx, a)
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, b)
+	}
+	return balances, nil
+}
+
+// EstimateLedger returns the balances and entries of an estimate.
+func (u *LedgerUseCase) EstimateLedger(ctx context.Context, estimateID string) (entities.EstimateLedger, error) {
+	estimateID = strings.TrimSpace(estimateID)
+	if estimateID == "" {
+		return entities.EstimateLedger{}, ErrInvalidLedgerEstimateID
+	}
+	balances, err := u.ledger.ListEstimateBalances(ctx, estimateID)
+	if err != nil {
+		return entities.EstimateLedger{}, err
+	}
+	entries, err := u.ledger.ListEntriesByEstimate(ctx, estimateID)
+	if err != nil {
+		return entities.EstimateLedger{}, err
+	}
+	return entities.EstimateLedger{EstimateID: estimateID, Balances: balances, Entries: entries}, nil
+}
+
+// Backfill posts the entries of payments persisted before the ledger existed, replaying
+// their current status from an approval at the payment date: a refunded payment gets its
+// payment and refund entries. Entries already posted are left alone, so it can be re-run.
+// With dryRun nothing is written.
+func (u *LedgerUseCase) Backfill(ctx context.Context, batchSize int32, dryRun bool) (PaymentMigrationReport, error) {
+	if batchSize <= 0 {
+		batchSize = defaultBackfillBatchSize
+	}
+
+	var report PaymentMigrationReport
+	cursor := ""
+	for {
+		page, next, err := u.payments.ScanPage(ctx, cursor, batchSize)
+		if err != nil {
+			return report, err
+		}
+
+		for _, p := range page {
+			report.Scanned++
+			entries := ledgerReplay(p)
+			if len(entries) == 0 {
+				report.Skipped++
+				continue
+			}
+			if dryRun {
+				report.Updated++
+				continue
+			}
+
+			posted, failed := 0, false
+			for _, e := range entries {
+				err := u.ledger.Post(ctx, e)
+				if errors.Is(err, entities.ErrLedgerConflict) {
+					continue
+				}
+				if err != nil {
+					log.Printf("[payment][ledger] backfill post failed payment_id=%s entry_id=%s err=%v", p.ID, e.ID, err)
+					failed = true
+					break
+				}
+				posted++
+			}
+			switch {
+			case failed:
+				report.Failed++
+			case posted == 0:
+				report.Skipped++
+			default:
+				report.Updated++
+			}
+		}
+
+		if next == "" {
+			return report, nil
+		}
+		cursor = next
+	}
+}
+
+// PostPending completes the postings of payments created while their ledger transaction
+// failed (see IBillingPaymentRepository.CreateWithPendingPosting). An entry already posted
+// by the backfill leaves only the status event to write. With dryRun nothing is written.
+func (u *LedgerUseCase) PostPending(ctx context.Context, dryRun bool) (PaymentMigrationReport, error) {
+	var report PaymentMigrationReport
+	postings, err := u.payments.ListPendingPostings(ctx)
+	if err != nil {
+		return report, err
+	}
+	for _, posting := range postings {
+		report.Scanned++
+		if dryRun {
+			report.Updated++
+			continue
+		}
+		err := u.payments.CompletePosting(ctx, posting)
+		if errors.Is(err, entities.ErrLedgerConflict) {
+			posting.Entry = nil
+			err = u.payments.CompletePosting(ctx, posting)
+		}
+		if err != nil {
+			log.Printf("[payment][ledger] pending posting failed payment_id=%s err=%v", posting.Event.PaymentID, err)
+			report.Failed++
+			continue
+		}
+		report.Updated++
+	}
+	return report, nil
+}
+
+// ledgerReplay returns the entries of a payment in its current status.
+func ledgerReplay(p entities.BillingPayment) []entities.LedgerEntry {
+	switch p.Status {
+	case entities.PaymentStatusAprovado, entities.PaymentStatusContestado,
+		entities.PaymentStatusReembolsado, entities.PaymentStatusEstornado:
+	default:
+		return nil
+	}
+	payment, ok := entities.LedgerEntryForTransition(p, "", entities.PaymentStatusAprovado, p.Date)
+	if !ok {
+		return nil
+	}
+	entries := []entities.LedgerEntry{payment}
+	if e, ok := entities.LedgerEntryForTransition(p, entities.PaymentStatusAprovado, p.Status, p.Date); ok {
+		entries = append(entries, e)
+	}
+	return entries
+}
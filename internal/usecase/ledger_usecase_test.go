@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	mock_interfaces "mecanica_xpto/internal/usecase/interfaces/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestLedgerUseCase_Balances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ledger := mock_interfaces.NewMockILedgerRepository(ctrl)
+	uc := NewLedgerUseCase(ledger, nil)
+
+	if _, err := uc.AccountBalances(context.Background(), "cash"); !errors.Is(err, ErrInvalidLedgerAccount) {
+		t.Fatalf("expected ErrInvalidLedgerAccount, got %v", err)
+	}
+	if _, err := uc.EstimateLedger(context.Background(), " "); !errors.Is(err, ErrInvalidLedgerEstimateID) {
+		t.Fatalf("expected ErrInvalidLedgerEstimateID, got %v", err)
+	}
+
+	ledger.EXPECT().GetAccountBalance(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, a entities.LedgerAccount) (entities.LedgerBalance, error) {
+			return entities.LedgerBalance{Account: a, Debits: 100, Credits: 300}, nil
+		}).Times(len(entities.LedgerAccounts))
+	balances, err := uc.AccountBalances(context.Background(), "")
+	if err != nil || len(balances) != len(entities.LedgerAccounts) {
+		t.Fatalf("unexpected balances: %+v err=%v", balances, err)
+	}
+	for _, b := range balances {
+		want := int64(-200)
+		if b.Account == entities.LedgerAccountRevenue {
+			want = 200
+		}
+		if b.Balance() != want {
+			t.Fatalf("unexpected balance of %s: %d", b.Account, b.Balance())
+		}
+	}
+
+	ledger.EXPECT().ListEstimateBalances(gomock.Any(), "est-1").Return([]entities.LedgerBalance{{Account: entities.LedgerAccountRevenue, Credits: 100}}, nil)
+	ledger.EXPECT().ListEntriesByEstimate(gomock.Any(), "est-1").Return([]entities.LedgerEntry{{ID: "pay-1-payment"}}, nil)
+	l, err := uc.EstimateLedger(context.Background(), "est-1")
+	if err != nil || l.EstimateID != "est-1" || len(l.Balances) != 1 || len(l.Entries) != 1 {
+		t.Fatalf("unexpected ledger: %+v err=%v", l, err)
+	}
+}
+
+func TestLedgerUseCase_Backfill(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ledger := mock_interfaces.NewMockILedgerRepository(ctrl)
+	payments := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+	uc := NewLedgerUseCase(ledger, payments)
+
+	date := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	details := entities.PaymentDetails{Amount: 80}
+	payments.EXPECT().ScanPage(gomock.Any(), "", int32(2)).Return([]entities.BillingPayment{
+		{ID: "p1", EstimateID: "e1", Date: date, Status: entities.PaymentStatusReembolsado, Details: details},
+		{ID: "p2", EstimateID: "e2", Date: date, Status: entities.PaymentStatusNegado, Details: details},
+	}, "p2", nil)
+	payments.EXPECT().ScanPage(gomock.Any(), "p2", int32(2)).Return([]entities.BillingPayment{
+		{ID: "p3", EstimateID: "e3", Date: date, Status: entities.PaymentStatusAprovado, Details: details},
+		{ID: "p4", EstimateID: "e4", Date: date, Status: entities.PaymentStatusAprovado},
+	}, "", nil)
+
+	var posted []string
+	ledger.EXPECT().Post(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e entities.LedgerEntry) error {
+		if !e.CreatedAt.Equal(date) {
+			t.Fatalf("unexpected entry date: %+v", e)
+		}
+		posted = append(posted, e.ID)
+		if e.ID == "p3-payment" {
+			return entities.ErrLedgerConflict
+		}
+		return nil
+	}).Times(3)
+
+	report, err := uc.Backfill(context.Background(), 2, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report != (PaymentMigrationReport{Scanned: 4, Updated: 1, Skipped: 3}) {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if len(posted) != 3 || posted[0] != "p1-payment" || posted[1] != "p1-refund" {
+		t.Fatalf("unexpected entries: %v", posted)
+	}
+}
+
+func TestLedgerUseCase_PostPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	payments := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+	uc := NewLedgerUseCase(nil, payments)
+
+	entry := entities.LedgerEntry{ID: "pay-2-payment", PaymentID: "pay-2"}
+	postings := []entities.PaymentPosting{
+		{Event: entities.PaymentStatusEvent{ID: "ev-1", PaymentID: "pay-1"}},
+		{Event: entities.PaymentStatusEvent{ID: "ev-2", PaymentID: "pay-2"}, Entry: &entry},
+		{Event: entities.PaymentStatusEvent{ID: "ev-3", PaymentID: "pay-3"}},
+	}
+	payments.EXPECT().ListPendingPostings(gomock.Any()).Return(postings, nil).Times(2)
+
+	report, err := uc.PostPending(context.Background(), true)
+	if err != nil || report.Scanned != 3 || report.Updated != 3 {
+		t.Fatalf("unexpected dry-run report: %+v err=%v", report, err)
+	}
+
+	payments.EXPECT().CompletePosting(gomock.Any(), postings[0]).Return(nil)
+	// The backfill already posted the entry: only the event is left to write.
+	payments.EXPECT().CompletePosting(gomock.Any(), postings[1]).Return(entities.ErrLedgerConflict)
+	payments.EXPECT().CompletePosting(gomock.Any(), entities.PaymentPosting{Event: postings[1].Event}).Return(nil)
+	payments.EXPECT().CompletePosting(gomock.Any(), postings[2]).Return(errors.New("ddb"))
+
+	report, err = uc.PostPending(context.Background(), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Scanned != 3 || report.Updated != 2 || report.Failed != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+}
@@ -0,0 +1,292 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNFSeNotConfigured    = errors.New("nfse not configured")
+	ErrInvalidNFSeID        = errors.New("invalid nfse id")
+	ErrInvalidNFSeInvoiceID = errors.New("invalid nfse invoice id")
+	ErrInvalidNFSeTomador   = errors.New("invalid nfse tomador")
+	ErrNFSeNotFound         = errors.New("nfse not found")
+)
+
+// maxRPSAttempts bounds the rebuilds when another document took the RPS number.
+const maxRPSAttempts = 5
+
+// INFSeUseCase generates the signed NFS-e (RPS) of invoices and delivers them to the
+// municipality.
+//
+// Notes:
+//   - Generate is idempotent per invoice: an invoice with a document gets it back.
+//   - A transmission failure does not fail Generate; the document is kept with status
+//     falha and Transmit retries it.
+type INFSeUseCase interface {
+	Generate(ctx context.Context, invoiceID string, tomador entities.NFSeTomador, actor string) (entities.NFSeDocument, error)
+	GetByID(ctx context.Context, id string) (entities.NFSeDocument, error)
+	Transmit(ctx context.Context, id string) (entities.NFSeDocument, error)
+	FindByTomador(ctx context.Context, document, email string) ([]entities.NFSeDocument, error)
+}
+
+type NFSeUseCase struct {
+	docs        interfaces.INFSeRepository
+	invoices    interfaces.IInvoiceRepository
+	builder     interfaces.INFSeBuilder
+	transmitter interfaces.INFSeTransmitter
+	sealer      interfaces.IRecordSealer
+	index       interfaces.IBlindIndex
+	now         func() time.Time
+}
+
+var _ INFSeUseCase = (*NFSeUseCase)(nil)
+
+// NewNFSeUseCase builds the use case; with a nil builder (no certificate configured)
+// generation returns ErrNFSeNotConfigured.
+func NewNFSeUseCase(docs interfaces.INFSeRepository, invoices interfaces.IInvoiceRepository, builder interfaces.INFSeBuilder, transmitter interfaces.INFSeTransmitter) *NFSeUseCase {
+	return &NFSeUseCase{docs: docs, invoices: invoices, builder: builder, transmitter: transmitter, now: time.Now}
+}
+
+// WithDataProtection seals the tomador and the signed XML (which carries it) at rest and
+// indexes the tomador document/email for data subject requests.
+func (u *NFSeUseCase) WithDataProtection(sealer interfaces.IRecordSealer, index interfaces.IBlindIndex) *NFSeUseCase {
+	u.sealer = sealer
+	u.index = index
+	return u
+}
+
+// Generate builds, signs and stores the RPS of an active invoice, then transmits it.
+func (u *NFSeUseCase) Generate(ctx context.Context, invoiceID string, tomador entities.NFSeTomador, actor string) (entities.NFSeDocument, error) {
+	if u.builder == nil {
+		return entities.NFSeDocument{}, ErrNFSeNotConfigured
+	}
+	invoiceID = strings.TrimSpace(invoiceID)
+	if invoiceID == "" {
+		return entities.NFSeDocument{}, ErrInvalidNFSeInvoiceID
+	}
+	if tomador = tomador.Normalize(); !tomador.IsValid() {
+		return entities.NFSeDocument{}, ErrInvalidNFSeTomador
+	}
+	if existing, err := u.getByInvoiceID(ctx, invoiceID); err != nil {
+		return entities.NFSeDocument{}, err
+	} else if existing.ID != "" {
+		return existing, nil
+	}
+
+	inv, err := u.invoices.GetByID(ctx, invoiceID)
+	if err != nil {
+		return entities.NFSeDocument{}, err
+	}
+	if inv.ID == "" {
+		return entities.NFSeDocument{}, ErrInvoiceNotFound
+	}
+	if inv.Status != entities.InvoiceStatusEmitida {
+		return entities.NFSeDocument{}, ErrInvoiceVoided
+	}
+
+	now := u.now().UTC()
+	doc := entities.NFSeDocument{
+		ID:         uuid.NewString(),
+		InvoiceID:  inv.ID,
+		EstimateID: inv.EstimateID,
+		RPS:        entities.NewNFSeRPS(inv, tomador, u.builder.Series(), now),
+		Status:     entities.NFSeStatusAssinada,
+		CreatedBy:  actor,
+		CreatedAt:  now,
+	}
+	if err := u.create(ctx, &doc); err != nil {
+		if errors.Is(err, entities.ErrNFSeAlreadyGenerated) {
+			return u.getByInvoiceID(ctx, invoiceID)
+		}
+		return entities.NFSeDocument{}, err
+	}
+	log.Printf("[nfse][usecase] rps signed nfse_id=%s invoice_id=%s rps=%s-%d", doc.ID, doc.InvoiceID, doc.RPS.Series, doc.RPS.Number)
+
+	return u.transmit(ctx, doc)
+}
+
+// create numbers, signs and stores doc. The XML carries the RPS number, so it is signed
+// again whenever another document takes the number first.
+func (u *NFSeUseCase) create(ctx context.Context, doc *entities.NFSeDocument) error {
+	for attempt := 0; attempt < maxRPSAttempts; attempt++ {
+		current, err := u.docs.CurrentRPSNumber(ctx)
+		if err != nil {
+			return err
+		}
+		doc.RPS.Number = current + 1
+		if doc.SignedXML, err = u.builder.Build(doc.RPS); err != nil {
+			return err
+		}
+		stored, err := u.seal(ctx, *doc)
+		if err != nil {
+			return err
+		}
+		err = u.docs.Create(ctx, stored)
+		if errors.Is(err, entities.ErrRPSNumberTaken) {
+			continue
+		}
+		return err
+	}
+	return entities.ErrRPSNumberTaken
+}
+
+func (u *NFSeUseCase) GetByID(ctx context.Context, id string) (entities.NFSeDocument, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return entities.NFSeDocument{}, ErrInvalidNFSeID
+	}
+	doc, err := u.docs.GetByID(ctx, id)
+	if err != nil {
+		return entities.NFSeDocument{}, err
+	}
+	if doc.ID == "" {
+		return entities.NFSeDocument{}, ErrNFSeNotFound
+	}
+	return u.open(ctx, doc)
+}
+
+// FindByTomador returns the documents (opened, oldest first) whose tomador has the given
+// CPF/CNPJ or email. Without a blind index documents cannot be looked up and none are
+// returned.
+func (u *NFSeUseCase) FindByTomador(ctx context.Context, document, email string) ([]entities.NFSeDocument, error) {
+	if u.index == nil {
+		return []entities.NFSeDocument{}, nil
+	}
+	byID := map[string]entities.NFSeDocument{}
+	if hash := u.index.Hash(entities.NormalizePayerDocument(document)); hash != "" {
+		found, err := u.docs.ListByTomadorDocHash(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range found {
+			byID[d.ID] = d
+		}
+	}
+	if hash := u.index.Hash(entities.NormalizePayerEmail(email)); hash != "" {
+		found, err := u.docs.ListByTomadorEmailHash(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range found {
+			byID[d.ID] = d
+		}
+	}
+
+	docs := make([]entities.NFSeDocument, 0, len(byID))
+	for _, d := range byID {
+		opened, err := u.open(ctx, d)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, opened)
+	}
+	sort.Slice(docs, func(i, j int) bool {
+		if docs[i].CreatedAt.Equal(docs[j].CreatedAt) {
+			return docs[i].ID < docs[j].ID
+		}
+		return docs[i].CreatedAt.Before(docs[j].CreatedAt)
+	})
+	return docs, nil
+}
+
+func (u *NFSeUseCase) getByInvoiceID(ctx context.Context, invoiceID string) (entities.NFSeDocument, error) {
+	doc, err := u.docs.GetByInvoiceID(ctx, invoiceID)
+	if err != nil || doc.ID == "" {
+		return doc, err
+	}
+	return u.open(ctx, doc)
+}
+
+// seal returns the stored form of doc: tomador blind indexes filled and, with a sealer,
+// the tomador fields and the signed XML encrypted.
+func (u *NFSeUseCase) seal(ctx context.Context, doc entities.NFSeDocument) (entities.NFSeDocument, error) {
+	if u.index != nil {
+		doc.TomadorDocHash = u.index.Hash(entities.NormalizePayerDocument(doc.RPS.Tomador.Document))
+		doc.TomadorEmailHash = u.index.Hash(entities.NormalizePayerEmail(doc.RPS.Tomador.Email))
+	}
+	if u.sealer == nil {
+		return doc, nil
+	}
+	xml := string(doc.SignedXML)
+	key, err := u.sealer.Seal(ctx, doc.ID, nfseSensitiveFields(&doc, &xml)...)
+	if err != nil {
+		return entities.NFSeDocument{}, err
+	}
+	doc.SignedXML = []byte(xml)
+	doc.DataKey = key
+	return doc, nil
+}
+
+// open reverts seal on a stored document.
+func (u *NFSeUseCase) open(ctx context.Context, doc entities.NFSeDocument) (entities.NFSeDocument, error) {
+	if doc.DataKey == nil || u.sealer == nil {
+		return doc, nil
+	}
+	xml := string(doc.SignedXML)
+	if err := u.sealer.Open(ctx, doc.ID, doc.DataKey, nfseSensitiveFields(&doc, &xml)...); err != nil {
+		log.Printf("[nfse][usecase] open sealed document failed nfse_id=%s err=%v", doc.ID, err)
+		return entities.NFSeDocument{}, err
+	}
+	doc.SignedXML = []byte(xml)
+	doc.DataKey = nil
+	return doc, nil
+}
+
+// nfseSensitiveFields points at the personal data of doc; the address is copied first so
+// the caller's document is never modified.
+func nfseSensitiveFields(doc *entities.NFSeDocument, xml *string) []*string {
+	t := &doc.RPS.Tomador
+	fields := []*string{&t.Document, &t.Name, &t.Email, xml}
+	if t.Address != nil {
+		a := *t.Address
+		t.Address = &a
+		fields = append(fields, &a.Street, &a.Number, &a.Complement, &a.District, &a.PostalCode)
+	}
+	return fields
+}
+
+// Transmit sends a document not yet accepted by the transmitter; a sent document is
+// returned unchanged.
+func (u *NFSeUseCase) Transmit(ctx context.Context, id string) (entities.NFSeDocument, error) {
+	doc, err := u.GetByID(ctx, id)
+	if err != nil {
+		return entities.NFSeDocument{}, err
+	}
+	if doc.Status == entities.NFSeStatusEnviada {
+		return doc, nil
+	}
+	return u.transmit(ctx, doc)
+}
+
+func (u *NFSeUseCase) transmit(ctx context.Context, doc entities.NFSeDocument) (entities.NFSeDocument, error) {
+	if u.transmitter == nil {
+		return doc, nil
+	}
+	doc.Attempts++
+	protocol, err := u.transmitter.Transmit(ctx, doc)
+	if err != nil {
+		log.Printf("[nfse][usecase] transmission failed nfse_id=%s attempt=%d err=%v", doc.ID, doc.Attempts, err)
+		doc.Status = entities.NFSeStatusFalha
+		doc.TransmissionError = err.Error()
+	} else {
+		now := u.now().UTC()
+		doc.Status = entities.NFSeStatusEnviada
+		doc.Protocol = protocol
+		doc.TransmissionError = ""
+		doc.TransmittedAt = &now
+	}
+	if err := u.docs.UpdateTransmission(ctx, doc); err != nil {
+		return entities.NFSeDocument{}, err
+	}
+	return doc, nil
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	mock_interfaces "mecanica_xpto/internal/usecase/interfaces/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+var testTomador = entities.NFSeTomador{Document: "123.456.789-09", Name: "Maria Silva"}
+
+func TestNFSeUseCase_Generate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	docs := mock_interfaces.NewMockINFSeRepository(ctrl)
+	invoices := mock_interfaces.NewMockIInvoiceRepository(ctrl)
+	builder := mock_interfaces.NewMockINFSeBuilder(ctrl)
+	transmitter := mock_interfaces.NewMockINFSeTransmitter(ctrl)
+	uc := NewNFSeUseCase(docs, invoices, builder, transmitter)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	uc.now = func() time.Time { return now }
+
+	if _, err := uc.Generate(context.Background(), "inv-1", entities.NFSeTomador{Document: "123", Name: "x"}, "ops"); !errors.Is(err, ErrInvalidNFSeTomador) {
+		t.Fatalf("expected ErrInvalidNFSeTomador, got %v", err)
+	}
+
+	docs.EXPECT().GetByInvoiceID(gomock.Any(), "inv-void").Return(entities.NFSeDocument{}, nil)
+	invoices.EXPECT().GetByID(gomock.Any(), "inv-void").Return(entities.Invoice{ID: "inv-void", Status: entities.InvoiceStatusCancelada}, nil)
+	if _, err := uc.Generate(context.Background(), "inv-void", testTomador, "ops"); !errors.Is(err, ErrInvoiceVoided) {
+		t.Fatalf("expected ErrInvoiceVoided, got %v", err)
+	}
+
+	// The first number is taken by a concurrent document, so the RPS is signed again.
+	docs.EXPECT().GetByInvoiceID(gomock.Any(), "inv-1").Return(entities.NFSeDocument{}, nil)
+	invoices.EXPECT().GetByID(gomock.Any(), "inv-1").Return(entities.Invoice{ID: "inv-1", EstimateID: "e1", Status: entities.InvoiceStatusEmitida, Subtotal: 150.5,
+		Lines: []entities.InvoiceLine{{Description: "Troca de óleo"}, {Description: "Alinhamento"}}}, nil)
+	builder.EXPECT().Series().Return("A")
+	gomock.InOrder(
+		docs.EXPECT().CurrentRPSNumber(gomock.Any()).Return(int64(9), nil),
+		builder.EXPECT().Build(gomock.Any()).Return([]byte("<rps10/>"), nil),
+		docs.EXPECT().Create(gomock.Any(), gomock.Any()).Return(entities.ErrRPSNumberTaken),
+		docs.EXPECT().CurrentRPSNumber(gomock.Any()).Return(int64(10), nil),
+		builder.EXPECT().Build(gomock.Any()).DoAndReturn(func(rps entities.NFSeRPS) ([]byte, error) {
+			if rps.Number != 11 || rps.ServiceDescription != "Troca de óleo; Alinhamento" || rps.Tomador.Document != "12345678909" {
+				t.Fatalf("unexpected rps: %+v", rps)
+			}
+			return []byte("<rps11/>"), nil
+		}),
+		docs.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil),
+	)
+	transmitter.EXPECT().Transmit(gomock.Any(), gomock.Any()).Return("", errors.New("prefeitura indisponível"))
+	docs.EXPECT().UpdateTransmission(gomock.Any(), gomock.Any()).Return(nil)
+	doc, err := uc.Generate(context.Background(), "inv-1", testTomador, "ops")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if doc.RPS.Number != 11 || string(doc.SignedXML) != "<rps11/>" || doc.Status != entities.NFSeStatusFalha ||
+		doc.Attempts != 1 || doc.TransmissionError != "prefeitura indisponível" || doc.EstimateID != "e1" {
+		t.Fatalf("unexpected document: %+v", doc)
+	}
+
+	// Idempotent: the invoice already has a document.
+	docs.EXPECT().GetByInvoiceID(gomock.Any(), "inv-1").Return(doc, nil)
+	if got, err := uc.Generate(context.Background(), "inv-1", testTomador, "ops"); err != nil || got.ID != doc.ID {
+		t.Fatalf("expected existing document, got %+v err=%v", got, err)
+	}
+
+	// Retrying the failed transmission.
+	docs.EXPECT().GetByID(gomock.Any(), doc.ID).Return(doc, nil)
+	transmitter.EXPECT().Transmit(gomock.Any(), gomock.Any()).Return("file:/tmp/rps-A-11.xml", nil)
+	docs.EXPECT().UpdateTransmission(gomock.Any(), gomock.Any()).Return(nil)
+	sent, err := uc.Transmit(context.Background(), doc.ID)
+	if err != nil || sent.Status != entities.NFSeStatusEnviada || sent.Attempts != 2 || sent.TransmissionError != "" ||
+		sent.TransmittedAt == nil || !sent.TransmittedAt.Equal(now) {
+		t.Fatalf("unexpected transmission: %+v err=%v", sent, err)
+	}
+}
+
+func TestNFSeUseCase_NotConfigured(t *testing.T) {
+	uc := NewNFSeUseCase(nil, nil, nil, nil)
+	if _, err := uc.Generate(context.Background(), "inv-1", testTomador, "ops"); !errors.Is(err, ErrNFSeNotConfigured) {
+		t.Fatalf("expected ErrNFSeNotConfigured, got %v", err)
+	}
+}
+
+func TestNFSeUseCase_SealsTomador(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	docs := mock_interfaces.NewMockINFSeRepository(ctrl)
+	invoices := mock_interfaces.NewMockIInvoiceRepository(ctrl)
+	builder := mock_interfaces.NewMockINFSeBuilder(ctrl)
+	sealer := mock_interfaces.NewMockIRecordSealer(ctrl)
+	index := mock_interfaces.NewMockIBlindIndex(ctrl)
+	uc := NewNFSeUseCase(docs, invoices, builder, nil).WithDataProtection(sealer, index)
+	index.EXPECT().Hash(gomock.Any()).DoAndReturn(func(v string) string {
+		if v == "" {
+			return ""
+		}
+		return "h:" + v
+	}).AnyTimes()
+	key := &entities.EncryptedDataKey{KeyID: "k1"}
+	sealer.EXPECT().Seal(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _ string, values ...*string) (*entities.EncryptedDataKey, error) {
+		for _, v := range values {
+			if *v != "" {
+				*v = entities.EncryptedValuePrefix + *v
+			}
+		}
+		return key, nil
+	}).AnyTimes()
+	sealer.EXPECT().Open(gomock.Any(), gomock.Any(), key, gomock.Any()).DoAndReturn(func(_ context.Context, _ string, _ *entities.EncryptedDataKey, values ...*string) error {
+		for _, v := range values {
+			*v = strings.TrimPrefix(*v, entities.EncryptedValuePrefix)
+		}
+		return nil
+	}).AnyTimes()
+
+	tomador := entities.NFSeTomador{Document: "123.456.789-09", Name: "Maria Silva", Email: "maria@test.com",
+		Address: &entities.NFSeAddress{Street: "Rua A", Number: "10", District: "Centro", CityCode: "3550308", State: "SP", PostalCode: "01000-000"}}
+	docs.EXPECT().GetByInvoiceID(gomock.Any(), "inv-1").Return(entities.NFSeDocument{}, nil)
+	invoices.EXPECT().GetByID(gomock.Any(), "inv-1").Return(entities.Invoice{ID: "inv-1", Status: entities.InvoiceStatusEmitida, Subtotal: 100,
+		Lines: []entities.InvoiceLine{{Description: "Troca de óleo"}}}, nil)
+	builder.EXPECT().Series().Return("A")
+	docs.EXPECT().CurrentRPSNumber(gomock.Any()).Return(int64(0), nil)
+	builder.EXPECT().Build(gomock.Any()).Return([]byte("<rps><Cpf>12345678909</Cpf></rps>"), nil)
+	var stored entities.NFSeDocument
+	docs.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, doc entities.NFSeDocument) error {
+		stored = doc
+		return nil
+	})
+	doc, err := uc.Generate(context.Background(), "inv-1", tomador, "ops")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	st := stored.RPS.Tomador
+	for _, v := range []string{st.Document, st.Name, st.Email, st.Address.Street, st.Address.PostalCode, string(stored.SignedXML)} {
+		if !entities.IsEncrypted(v) {
+			t.Fatalf("expected sealed value, got %q", v)
+		}
+	}
+	if stored.DataKey != key || stored.TomadorDocHash != "h:12345678909" || stored.TomadorEmailHash != "h:maria@test.com" || st.Address.CityCode != "3550308" {
+		t.Fatalf("unexpected stored document: %+v", stored)
+	}
+	if doc.RPS.Tomador.Name != "Maria Silva" || doc.RPS.Tomador.Address.Street != "Rua A" || string(doc.SignedXML) != "<rps><Cpf>12345678909</Cpf></rps>" {
+		t.Fatalf("expected plaintext document, got %+v", doc)
+	}
+
+	// The data subject lookup finds the document by document or email and opens it once.
+	docs.EXPECT().ListByTomadorDocHash(gomock.Any(), "h:12345678909").Return([]entities.NFSeDocument{stored}, nil)
+	docs.EXPECT().ListByTomadorEmailHash(gomock.Any(), "h:maria@test.com").Return([]entities.NFSeDocument{stored}, nil)
+	found, err := uc.FindByTomador(context.Background(), "123.456.789-09", " Maria@Test.com ")
+	if err != nil || len(found) != 1 || found[0].RPS.Tomador.Email != "maria@test.com" || found[0].DataKey != nil {
+		t.Fatalf("unexpected lookup %+v err=%v", found, err)
+	}
+}
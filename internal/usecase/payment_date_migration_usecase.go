@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"mecanica_xpto/internal/usecase/interfaces"
+)
+
+// PaymentDateMigrationUseCase rewrites the date sort key of payments persisted before the
+// fixed-width layout used by the estimate_id-date-index and status-date-index GSIs.
+// Until then those rows sort apart from the new ones, and cursor pagination can skip or
+// repeat the payments around them.
+type PaymentDateMigrationUseCase struct {
+	repo interfaces.IBillingPaymentRepository
+}
+
+func NewPaymentDateMigrationUseCase(repo interfaces.IBillingPaymentRepository) *PaymentDateMigrationUseCase {
+	return &PaymentDateMigrationUseCase{repo: repo}
+}
+
+// Run scans the payments table in batches and rewrites the legacy dates; the instant is
+// kept, only its format changes. With dryRun nothing is written. It can run again safely,
+// e.g. after old replicas stopped writing during a rolling deploy.
+func (u *PaymentDateMigrationUseCase) Run(ctx context.Context, batchSize int32, dryRun bool) (PaymentMigrationReport, error) {
+	if u.repo == nil {
+		return PaymentMigrationReport{}, errors.New("payment date migration not configured")
+	}
+	if batchSize <= 0 {
+		batchSize = defaultBackfillBatchSize
+	}
+
+	var report PaymentMigrationReport
+	cursor := ""
+	for {
+		page, next, err := u.repo.ScanLegacyDatePage(ctx, cursor, batchSize)
+		if err != nil {
+			return report, err
+		}
+
+		for _, p := range page {
+			report.Scanned++
+			if p.Date.IsZero() {
+				log.Printf("[payment][date-migration] unparsable date payment_id=%s", p.ID)
+				report.Skipped++
+				continue
+			}
+			if dryRun {
+				report.Updated++
+				continue
+			}
+			if err := u.repo.RewriteDate(ctx, p.ID, p.Date); err != nil {
+				log.Printf("[payment][date-migration] rewrite failed payment_id=%s err=%v", p.ID, err)
+				report.Failed++
+				continue
+			}
+			report.Updated++
+		}
+
+		if next == "" {
+			return report, nil
+		}
+		cursor = next
+	}
+}
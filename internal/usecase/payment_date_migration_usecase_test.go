@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	mock_interfaces "mecanica_xpto/internal/usecase/interfaces/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestPaymentDateMigrationUseCase_Run(t *testing.T) {
+	t.Run("not configured", func(t *testing.T) {
+		if _, err := NewPaymentDateMigrationUseCase(nil).Run(context.Background(), 10, false); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("scan error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		repo.EXPECT().ScanLegacyDatePage(gomock.Any(), "", int32(100)).Return(nil, "", errors.New("db"))
+
+		if _, err := NewPaymentDateMigrationUseCase(repo).Run(context.Background(), 0, false); err == nil || err.Error() != "db" {
+			t.Fatalf("expected db error, got %v", err)
+		}
+	})
+
+	t.Run("paginates through empty pages and rewrites legacy dates", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		uc := NewPaymentDateMigrationUseCase(repo)
+
+		d1 := time.Date(2026, 3, 1, 10, 0, 5, 0, time.UTC)
+		d2 := time.Date(2026, 3, 1, 10, 0, 5, 120000000, time.UTC)
+		repo.EXPECT().ScanLegacyDatePage(gomock.Any(), "", int32(2)).Return(nil, "p0", nil)
+		repo.EXPECT().ScanLegacyDatePage(gomock.Any(), "p0", int32(2)).Return([]entities.BillingPayment{
+			{ID: "p1", Date: d1},
+			{ID: "p2"},
+		}, "p2", nil)
+		repo.EXPECT().ScanLegacyDatePage(gomock.Any(), "p2", int32(2)).Return([]entities.BillingPayment{{ID: "p3", Date: d2}}, "", nil)
+		repo.EXPECT().RewriteDate(gomock.Any(), "p1", d1).Return(nil)
+		repo.EXPECT().RewriteDate(gomock.Any(), "p3", d2).Return(errors.New("throttled"))
+
+		report, err := uc.Run(context.Background(), 2, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if report != (PaymentMigrationReport{Scanned: 3, Updated: 1, Skipped: 1, Failed: 1}) {
+			t.Fatalf("unexpected report: %+v", report)
+		}
+	})
+
+	t.Run("dry run does not write", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		repo.EXPECT().ScanLegacyDatePage(gomock.Any(), "", int32(100)).Return([]entities.BillingPayment{{ID: "p1", Date: time.Now()}}, "", nil)
+
+		report, err := NewPaymentDateMigrationUseCase(repo).Run(context.Background(), 0, true)
+		if err != nil || report.Updated != 1 {
+			t.Fatalf("unexpected report: %+v err=%v", report, err)
+		}
+	})
+}
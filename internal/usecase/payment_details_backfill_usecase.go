@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"mecanica_xpto/internal/usecase/interfaces"
+)
+
+const defaultBackfillBatchSize = 100
+
+// PaymentMigrationReport summarizes a run of a payments data migration.
+type PaymentMigrationReport struct {
+	Scanned int `json:"scanned"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+}
+
+// PaymentDetailsBackfillUseCase fills BillingPayment.Details for rows persisted before
+// the typed fields existed, by re-extracting them from the stored MPPayloadRaw.
+type PaymentDetailsBackfillUseCase struct {
+	repo      interfaces.IBillingPaymentRepository
+	extractor interfaces.IPaymentDetailsExtractor
+}
+
+func NewPaymentDetailsBackfillUseCase(repo interfaces.IBillingPaymentRepository, extractor interfaces.IPaymentDetailsExtractor) *PaymentDetailsBackfillUseCase {
+	return &PaymentDetailsBackfillUseCase{repo: repo, extractor: extractor}
+}
+
+// Run scans the payments table in batches. Rows that already have details, or whose raw
+// payload cannot be parsed, are skipped. With dryRun nothing is written.
+func (u *PaymentDetailsBackfillUseCase) Run(ctx context.Context, batchSize int32, dryRun bool) (PaymentMigrationReport, error) {
+	if u.repo == nil || u.extractor == nil {
+		return PaymentMigrationReport{}, errors.New("payment details backfill not configured")
+	}
+	if batchSize <= 0 {
+		batchSize = defaultBackfillBatchSize
+	}
+
+	var report PaymentMigrationReport
+	cursor := ""
+	for {
+		page, next, err := u.repo.ScanPage(ctx, cursor, batchSize)
+		if err != nil {
+			return report, err
+		}
+
+		for _, p := range page {
+			report.Scanned++
+			if !p.Details.IsZero() || len(p.MPPayloadRaw) == 0 {
+				report.Skipped++
+				continue
+			}
+
+			details, err := u.extractor.ExtractDetails(p.MPPayloadRaw)
+			if err != nil {
+				log.Printf("[payment][backfill] extraction failed payment_id=%s err=%v", p.ID, err)
+				report.Failed++
+				continue
+			}
+			if details.IsZero() {
+				report.Skipped++
+				continue
+			}
+
+			if dryRun {
+				report.Updated++
+				continue
+			}
+			if err := u.repo.UpdateDetails(ctx, p.ID, details); err != nil {
+				log.Printf("[payment][backfill] update failed payment_id=%s err=%v", p.ID, err)
+				report.Failed++
+				continue
+			}
+			report.Updated++
+		}
+
+		if next == "" {
+			return report, nil
+		}
+		cursor = next
+	}
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"mecanica_xpto/internal/domain/entities"
+	mock_interfaces "mecanica_xpto/internal/usecase/interfaces/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestPaymentDetailsBackfillUseCase_Run(t *testing.T) {
+	t.Run("not configured", func(t *testing.T) {
+		uc := NewPaymentDetailsBackfillUseCase(nil, nil)
+		if _, err := uc.Run(context.Background(), 10, false); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("scan error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		extractor := mock_interfaces.NewMockIPaymentDetailsExtractor(ctrl)
+		uc := NewPaymentDetailsBackfillUseCase(repo, extractor)
+
+		repo.EXPECT().ScanPage(gomock.Any(), "", int32(100)).Return(nil, "", errors.New("db"))
+
+		if _, err := uc.Run(context.Background(), 0, false); err == nil || err.Error() != "db" {
+			t.Fatalf("expected db error, got %v", err)
+		}
+	})
+
+	t.Run("paginates and updates only missing details", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		extractor := mock_interfaces.NewMockIPaymentDetailsExtractor(ctrl)
+		uc := NewPaymentDetailsBackfillUseCase(repo, extractor)
+
+		raw := json.RawMessage(`{"transaction_amount":10}`)
+		repo.EXPECT().ScanPage(gomock.Any(), "", int32(2)).Return([]entities.BillingPayment{
+			{ID: "p1", MPPayloadRaw: raw},
+			{ID: "p2", MPPayloadRaw: raw, Details: entities.PaymentDetails{Amount: 5}},
+		}, "p2", nil)
+		repo.EXPECT().ScanPage(gomock.Any(), "p2", int32(2)).Return([]entities.BillingPayment{
+			{ID: "p3", MPPayloadRaw: json.RawMessage(`{`)},
+			{ID: "p4"},
+		}, "", nil)
+
+		extractor.EXPECT().ExtractDetails(raw).Return(entities.PaymentDetails{Amount: 10}, nil)
+		extractor.EXPECT().ExtractDetails(json.RawMessage(`{`)).Return(entities.PaymentDetails{}, errors.New("parse"))
+		repo.EXPECT().UpdateDetails(gomock.Any(), "p1", entities.PaymentDetails{Amount: 10}).Return(nil)
+
+		report, err := uc.Run(context.Background(), 2, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := PaymentMigrationReport{Scanned: 4, Updated: 1, Skipped: 2, Failed: 1}
+		if report != want {
+			t.Fatalf("expected %+v, got %+v", want, report)
+		}
+	})
+
+	t.Run("dry run does not write", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		extractor := mock_interfaces.NewMockIPaymentDetailsExtractor(ctrl)
+		uc := NewPaymentDetailsBackfillUseCase(repo, extractor)
+
+		repo.EXPECT().ScanPage(gomock.Any(), "", int32(10)).Return([]entities.BillingPayment{{ID: "p1", MPPayloadRaw: json.RawMessage(`{}`)}}, "", nil)
+		extractor.EXPECT().ExtractDetails(gomock.Any()).Return(entities.PaymentDetails{Amount: 1}, nil)
+
+		report, err := uc.Run(context.Background(), 10, true)
+		if err != nil || report.Updated != 1 {
+			t.Fatalf("unexpected result: %+v err=%v", report, err)
+		}
+	})
+}
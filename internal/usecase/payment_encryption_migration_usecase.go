@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"mecanica_xpto/internal/usecase/interfaces"
+)
+
+// PaymentEncryptionMigrationUseCase encrypts payer personal data of payments stored in
+// plaintext, and re-encrypts payments protected with a key other than the current one
+// (key rotation).
+type PaymentEncryptionMigrationUseCase struct {
+	repo      interfaces.IBillingPaymentRepository
+	protector interfaces.ISensitiveDataProtector
+}
+
+func NewPaymentEncryptionMigrationUseCase(repo interfaces.IBillingPaymentRepository, protector interfaces.ISensitiveDataProtector) *PaymentEncryptionMigrationUseCase {
+	return &PaymentEncryptionMigrationUseCase{repo: repo, protector: protector}
+}
+
+func (u *PaymentEncryptionMigrationUseCase) Run(ctx context.Context, batchSize int32, dryRun bool) (PaymentMigrationReport, error) {
+	if u.repo == nil || u.protector == nil {
+		return PaymentMigrationReport{}, errors.New("payment encryption migration not configured")
+	}
+	if batchSize <= 0 {
+		batchSize = defaultBackfillBatchSize
+	}
+
+	var report PaymentMigrationReport
+	cursor := ""
+	for {
+		page, next, err := u.repo.ScanPage(ctx, cursor, batchSize)
+		if err != nil {
+			return report, err
+		}
+
+		for _, p := range page {
+			report.Scanned++
+			if !u.protector.NeedsProtection(p) {
+				report.Skipped++
+				continue
+			}
+			if dryRun {
+				report.Updated++
+				continue
+			}
+
+			protected, err := u.protector.Protect(ctx, p)
+			if err != nil {
+				log.Printf("[payment][encryption-migration] encrypt failed payment_id=%s err=%v", p.ID, err)
+				report.Failed++
+				continue
+			}
+			if err := u.repo.Replace(ctx, protected); err != nil {
+				log.Printf("[payment][encryption-migration] update failed payment_id=%s err=%v", p.ID, err)
+				report.Failed++
+				continue
+			}
+			report.Updated++
+		}
+
+		if next == "" {
+			return report, nil
+		}
+		cursor = next
+	}
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"mecanica_xpto/internal/domain/entities"
+	mock_interfaces "mecanica_xpto/internal/usecase/interfaces/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestPaymentEncryptionMigrationUseCase_Run(t *testing.T) {
+	t.Run("not configured", func(t *testing.T) {
+		uc := NewPaymentEncryptionMigrationUseCase(nil, nil)
+		if _, err := uc.Run(context.Background(), 10, false); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("encrypts only payments needing protection", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		protector := mock_interfaces.NewMockISensitiveDataProtector(ctrl)
+		uc := NewPaymentEncryptionMigrationUseCase(repo, protector)
+
+		p1 := entities.BillingPayment{ID: "p1"}
+		p2 := entities.BillingPayment{ID: "p2"}
+		p3 := entities.BillingPayment{ID: "p3"}
+		repo.EXPECT().ScanPage(gomock.Any(), "", int32(100)).Return([]entities.BillingPayment{p1, p2, p3}, "", nil)
+		protector.EXPECT().NeedsProtection(p1).Return(true)
+		protector.EXPECT().NeedsProtection(p2).Return(false)
+		protector.EXPECT().NeedsProtection(p3).Return(true)
+
+		protected := entities.BillingPayment{ID: "p1", DataKey: &entities.EncryptedDataKey{KeyID: "k"}}
+		protector.EXPECT().Protect(gomock.Any(), p1).Return(protected, nil)
+		protector.EXPECT().Protect(gomock.Any(), p3).Return(entities.BillingPayment{}, errors.New("kms"))
+		repo.EXPECT().Replace(gomock.Any(), protected).Return(nil)
+
+		report, err := uc.Run(context.Background(), 0, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := PaymentMigrationReport{Scanned: 3, Updated: 1, Skipped: 1, Failed: 1}
+		if report != want {
+			t.Fatalf("expected %+v, got %+v", want, report)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		protector := mock_interfaces.NewMockISensitiveDataProtector(ctrl)
+		uc := NewPaymentEncryptionMigrationUseCase(repo, protector)
+
+		repo.EXPECT().ScanPage(gomock.Any(), "", int32(5)).Return([]entities.BillingPayment{{ID: "p1"}}, "", nil)
+		protector.EXPECT().NeedsProtection(gomock.Any()).Return(true)
+
+		report, err := uc.Run(context.Background(), 5, true)
+		if err != nil || report.Updated != 1 {
+			t.Fatalf("unexpected result: %+v err=%v", report, err)
+		}
+	})
+}
@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+)
+
+// IReceivablesAgingUseCase builds the accounts receivable aging report.
+type IReceivablesAgingUseCase interface {
+	Report(ctx context.Context) (entities.ReceivablesAgingReport, error)
+}
+
+type ReceivablesAgingUseCase struct {
+	estimateRepo interfaces.IEstimateRepository
+	paymentRepo  interfaces.IBillingPaymentRepository
+	now          func() time.Time
+}
+
+var _ IReceivablesAgingUseCase = (*ReceivablesAgingUseCase)(nil)
+
+func NewReceivablesAgingUseCase(estimateRepo interfaces.IEstimateRepository, paymentRepo interfaces.IBillingPaymentRepository) *ReceivablesAgingUseCase {
+	return &ReceivablesAgingUseCase{
+		estimateRepo: estimateRepo,
+		paymentRepo:  paymentRepo,
+		now:          time.Now,
+	}
+}
+
+// Report cross-references the approved estimates with their payments and lists the ones
+// whose price is not covered by approved (or disputed) payments. Days are counted from
+// the approval; estimates approved before the approval time was recorded use their last
+// update instead.
+func (u *ReceivablesAgingUseCase) Report(ctx context.Context) (entities.ReceivablesAgingReport, error) {
+	asOf := u.now().UTC()
+	estimates, err := u.estimateRepo.ListByStatus(ctx, entities.EstimateStatusAprovado)
+	if err != nil {
+		log.Printf("[payment][ar-aging] list approved estimates failed err=%v", err)
+		return entities.ReceivablesAgingReport{}, err
+	}
+
+	report := entities.ReceivablesAgingReport{
+		AsOf:    asOf,
+		Buckets: make([]entities.AgingBucketTotals, 0, len(entities.AgingBuckets)),
+		Items:   []entities.ReceivableItem{},
+	}
+	totals := map[string]*entities.AgingBucketTotals{}
+	for _, b := range entities.AgingBuckets {
+		report.Buckets = append(report.Buckets, entities.AgingBucketTotals{Bucket: b})
+		totals[b] = &report.Buckets[len(report.Buckets)-1]
+	}
+
+	for _, est := range estimates {
+		paid, paymentIDs, err := u.paidAmount(ctx, est.ID)
+		if err != nil {
+			log.Printf("[payment][ar-aging] list payments failed estimate_id=%s err=%v", est.ID, err)
+			return entities.ReceivablesAgingReport{}, err
+		}
+		outstanding := est.Price - paid
+		if outstanding < 0.005 {
+			continue
+		}
+
+		approvedAt := est.ApprovalTime()
+		days := 0
+		if elapsed := asOf.Sub(approvedAt); elapsed > 0 {
+			days = int(elapsed / (24 * time.Hour))
+		}
+		item := entities.ReceivableItem{
+			EstimateID:      est.ID,
+			OSID:            est.OSID,
+			ApprovedAt:      approvedAt,
+			DaysOutstanding: days,
+			Bucket:          entities.AgingBucketFor(days),
+			Price:           est.Price,
+			Paid:            paid,
+			Outstanding:     outstanding,
+			PaymentIDs:      paymentIDs,
+		}
+		report.Items = append(report.Items, item)
+		totals[item.Bucket].Count++
+		totals[item.Bucket].Outstanding += outstanding
+		report.TotalOutstanding += outstanding
+	}
+
+	sort.Slice(report.Items, func(i, j int) bool {
+		a, b := report.Items[i], report.Items[j]
+		if !a.ApprovedAt.Equal(b.ApprovedAt) {
+			return a.ApprovedAt.Before(b.ApprovedAt)
+		}
+		return a.EstimateID < b.EstimateID
+	})
+
+	log.Printf("[payment][ar-aging] report built estimates=%d outstanding=%d", len(estimates), len(report.Items))
+	return report, nil
+}
+
+// paidAmount sums the payments of an estimate that count towards its price and returns
+// their IDs.
+func (u *ReceivablesAgingUseCase) paidAmount(ctx context.Context, estimateID string) (float64, []string, error) {
+	paid := 0.0
+	ids := []string{}
+	page := entities.PageRequest{Limit: maxPaymentPageLimit}
+	for {
+		res, err := u.paymentRepo.ListByEstimateID(ctx, estimateID, page)
+		if err != nil {
+			return 0, nil, err
+		}
+		for _, p := range res.Items {
+			if !entities.PaymentSettlesEstimate(p.Status) {
+				continue
+			}
+			paid += p.Details.Amount
+			ids = append(ids, p.ID)
+		}
+		if res.NextCursor == "" {
+			return paid, ids, nil
+		}
+		page.Cursor = res.NextCursor
+	}
+}
+
+// WriteReceivablesAgingCSV writes one row per outstanding estimate.
+func WriteReceivablesAgingCSV(w io.Writer, report entities.ReceivablesAgingReport) error {
+	cw := csv.NewWriter(w)
+	rows := [][]string{{"estimate_id", "os_id", "approved_at", "days_outstanding", "bucket", "price", "paid", "outstanding"}}
+	for _, it := range report.Items {
+		rows = append(rows, []string{
+			it.EstimateID,
+			it.OSID,
+			it.ApprovedAt.UTC().Format(time.RFC3339),
+			strconv.Itoa(it.DaysOutstanding),
+			it.Bucket,
+			strconv.FormatFloat(it.Price, 'f', 2, 64),
+			strconv.FormatFloat(it.Paid, 'f', 2, 64),
+			strconv.FormatFloat(it.Outstanding, 'f', 2, 64),
+		})
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"testing"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	mock_interfaces "mecanica_xpto/internal/usecase/interfaces/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestReceivablesAgingUseCase_Report(t *testing.T) {
+	now := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+	newUseCase := func(t *testing.T) (*ReceivablesAgingUseCase, *mock_interfaces.MockIEstimateRepository, *mock_interfaces.MockIBillingPaymentRepository) {
+		ctrl := gomock.NewController(t)
+		estimateRepo := mock_interfaces.NewMockIEstimateRepository(ctrl)
+		paymentRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		uc := NewReceivablesAgingUseCase(estimateRepo, paymentRepo)
+		uc.now = func() time.Time { return now }
+		return uc, estimateRepo, paymentRepo
+	}
+
+	t.Run("buckets outstanding balances", func(t *testing.T) {
+		uc, estimateRepo, paymentRepo := newUseCase(t)
+
+		approved := func(days int) *time.Time {
+			at := now.Add(-time.Duration(days)*24*time.Hour - time.Hour)
+			return &at
+		}
+		estimates := []entities.Estimate{
+			{ID: "e-paid", OSID: "os-1", Price: 100, ApprovedAt: approved(10)},
+			{ID: "e-partial", OSID: "os-2", Price: 300, ApprovedAt: approved(45)},
+			{ID: "e-none", OSID: "os-3", Price: 80, ApprovedAt: approved(5)},
+			// approved before approved_at was recorded: falls back to updated_at.
+			{ID: "e-legacy", OSID: "os-4", Price: 50, UpdatedAt: *approved(120)},
+			{ID: "e-refunded", OSID: "os-5", Price: 70, ApprovedAt: approved(90)},
+		}
+		estimateRepo.EXPECT().ListByStatus(gomock.Any(), entities.EstimateStatusAprovado).Return(estimates, nil)
+
+		payments := map[string][]entities.BillingPayment{
+			"e-paid":     {{ID: "p1", Status: entities.PaymentStatusAprovado, Details: entities.PaymentDetails{Amount: 100}}},
+			"e-partial":  {{ID: "p2", Status: entities.PaymentStatusContestado, Details: entities.PaymentDetails{Amount: 100}}, {ID: "p3", Status: entities.PaymentStatusNegado, Details: entities.PaymentDetails{Amount: 200}}},
+			"e-refunded": {{ID: "p4", Status: entities.PaymentStatusReembolsado, Details: entities.PaymentDetails{Amount: 70}}},
+		}
+		paymentRepo.EXPECT().ListByEstimateID(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, estimateID string, page entities.PageRequest) (entities.Page[entities.BillingPayment], error) {
+				if estimateID == "e-partial" && page.Cursor == "" {
+					return entities.Page[entities.BillingPayment]{Items: payments[estimateID][:1], NextCursor: "next"}, nil
+				}
+				if estimateID == "e-partial" {
+					return entities.Page[entities.BillingPayment]{Items: payments[estimateID][1:]}, nil
+				}
+				return entities.Page[entities.BillingPayment]{Items: payments[estimateID]}, nil
+			}).Times(6)
+
+		report, err := uc.Report(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(report.Items) != 4 || report.TotalOutstanding != 400 {
+			t.Fatalf("unexpected report: %+v", report)
+		}
+		if it := report.Items[0]; it.EstimateID != "e-legacy" || it.Bucket != entities.AgingBucketOver90 || it.DaysOutstanding != 120 {
+			t.Fatalf("unexpected oldest item: %+v", it)
+		}
+		if it := report.Items[1]; it.EstimateID != "e-refunded" || it.Bucket != entities.AgingBucket61To90 || it.Outstanding != 70 {
+			t.Fatalf("unexpected refunded item: %+v", it)
+		}
+		if it := report.Items[2]; it.EstimateID != "e-partial" || it.Bucket != entities.AgingBucket31To60 || it.Paid != 100 || it.Outstanding != 200 || len(it.PaymentIDs) != 1 {
+			t.Fatalf("unexpected partial item: %+v", it)
+		}
+		want := []entities.AgingBucketTotals{
+			{Bucket: "0-30", Count: 1, Outstanding: 80},
+			{Bucket: "31-60", Count: 1, Outstanding: 200},
+			{Bucket: "61-90", Count: 1, Outstanding: 70},
+			{Bucket: "90+", Count: 1, Outstanding: 50},
+		}
+		for i, b := range want {
+			if report.Buckets[i] != b {
+				t.Fatalf("unexpected bucket %d: %+v", i, report.Buckets[i])
+			}
+		}
+
+		var buf bytes.Buffer
+		if err := WriteReceivablesAgingCSV(&buf, report); err != nil {
+			t.Fatalf("unexpected csv error: %v", err)
+		}
+		rows, err := csv.NewReader(&buf).ReadAll()
+		if err != nil {
+			t.Fatalf("invalid csv: %v", err)
+		}
+		if len(rows) != 5 || rows[3][1] != "os-2" || rows[3][4] != "31-60" || rows[3][7] != "200.00" {
+			t.Fatalf("unexpected csv rows: %v", rows)
+		}
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		uc, estimateRepo, _ := newUseCase(t)
+		estimateRepo.EXPECT().ListByStatus(gomock.Any(), gomock.Any()).Return(nil, errors.New("ddb"))
+		if _, err := uc.Report(context.Background()); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
+
+func TestAgingBucketFor(t *testing.T) {
+	cases := map[int]string{0: "0-30", 30: "0-30", 31: "31-60", 60: "31-60", 61: "61-90", 90: "61-90", 91: "90+"}
+	for days, want := range cases {
+		if got := entities.AgingBucketFor(days); got != want {
+			t.Fatalf("days=%d: expected %s, got %s", days, want, got)
+		}
+	}
+}
@@ -0,0 +1,352 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	"mecanica_xpto/internal/usecase/interfaces"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidSettlementReport    = errors.New("invalid settlement report")
+	ErrReconciliationRunNotFound  = errors.New("reconciliation run not found")
+	ErrInvalidReconciliationRunID = errors.New("invalid reconciliation run id")
+)
+
+// reconciliationAmountTolerance absorbs float rounding between report and stored amounts.
+const reconciliationAmountTolerance = 0.005
+
+// SettlementReport is a provider settlement/release report to reconcile.
+type SettlementReport struct {
+	FileName string
+	Actor    string
+	Content  io.Reader
+}
+
+// ISettlementReconciliationUseCase reconciles provider settlement reports with payments.
+type ISettlementReconciliationUseCase interface {
+	Reconcile(ctx context.Context, report SettlementReport) (entities.ReconciliationRun, error)
+	GetRun(ctx context.Context, id string) (entities.ReconciliationRun, error)
+	WriteRunReport(ctx context.Context, id string, w io.Writer) error
+}
+
+type SettlementReconciliationUseCase struct {
+	parser   interfaces.ISettlementReportParser
+	payments interfaces.IBillingPaymentRepository
+	runs     interfaces.IReconciliationRunRepository
+	now      func() time.Time
+}
+
+var _ ISettlementReconciliationUseCase = (*SettlementReconciliationUseCase)(nil)
+
+func NewSettlementReconciliationUseCase(
+	parser interfaces.ISettlementReportParser,
+	payments interfaces.IBillingPaymentRepository,
+	runs interfaces.IReconciliationRunRepository,
+) *SettlementReconciliationUseCase {
+	return &SettlementReconciliationUseCase{
+		parser:   parser,
+		payments: payments,
+		runs:     runs,
+		now:      time.Now,
+	}
+}
+
+// Reconcile matches every payment, refund and chargeback line of the report to a
+// BillingPayment (by provider ID, falling back to external_reference) and stores the run.
+//
+// Findings:
+//   - missing_payment: line without a matching payment;
+//   - amount_mismatch: payment line amount differs from the payment amount, or a
+//     refund/chargeback exceeds it;
+//   - status_mismatch: the payment status contradicts the line type;
+//   - missing_in_report: payment approved inside the report period (transaction dates)
+//     that the report does not settle.
+func (u *SettlementReconciliationUseCase) Reconcile(ctx context.Context, report SettlementReport) (entities.ReconciliationRun, error) {
+	if report.Content == nil {
+		return entities.ReconciliationRun{}, ErrInvalidSettlementReport
+	}
+	lines, err := u.parser.ParseSettlementReport(report.Content)
+	if err != nil {
+		log.Printf("[payment][reconciliation] report parse failed file=%s err=%v", report.FileName, err)
+		return entities.ReconciliationRun{}, fmt.Errorf("%w: %v", ErrInvalidSettlementReport, err)
+	}
+	if len(lines) == 0 {
+		return entities.ReconciliationRun{}, fmt.Errorf("%w: report has no lines", ErrInvalidSettlementReport)
+	}
+
+	run := entities.ReconciliationRun{
+		ID:            uuid.NewString(),
+		FileName:      strings.TrimSpace(report.FileName),
+		Actor:         report.Actor,
+		TotalLines:    len(lines),
+		Discrepancies: []entities.ReconciliationDiscrepancy{},
+		CreatedAt:     u.now().UTC(),
+	}
+
+	settled := map[string]bool{}
+	for _, line := range lines {
+		if line.Type == entities.SettlementLineOther {
+			run.IgnoredLines++
+			continue
+		}
+		if line.Type == entities.SettlementLinePayment && line.TransactionDate != nil {
+			run.PeriodStart, run.PeriodEnd = widenPeriod(run.PeriodStart, run.PeriodEnd, *line.TransactionDate)
+		}
+
+		p, err := u.matchPayment(ctx, line)
+		if err != nil {
+			log.Printf("[payment][reconciliation] payment lookup failed line=%d source_id=%s err=%v", line.Line, line.SourceID, err)
+			return entities.ReconciliationRun{}, err
+		}
+		if p.ID == "" {
+			run.Discrepancies = append(run.Discrepancies, lineDiscrepancy(entities.DiscrepancyMissingPayment, line, p,
+				"no payment found for source_id or external_reference"))
+			continue
+		}
+		run.MatchedLines++
+		if line.Type == entities.SettlementLinePayment {
+			settled[p.ID] = true
+		}
+		run.Discrepancies = append(run.Discrepancies, checkSettlementLine(line, p)...)
+	}
+
+	if run.PeriodStart != nil {
+		missing, err := u.findUnsettled(ctx, *run.PeriodStart, *run.PeriodEnd, settled)
+		if err != nil {
+			log.Printf("[payment][reconciliation] period lookup failed err=%v", err)
+			return entities.ReconciliationRun{}, err
+		}
+		run.Discrepancies = append(run.Discrepancies, missing...)
+	}
+
+	run.Summary = run.CountByKind()
+	if err := u.runs.Create(ctx, run); err != nil {
+		log.Printf("[payment][reconciliation] run store failed run_id=%s err=%v", run.ID, err)
+		return entities.ReconciliationRun{}, err
+	}
+	log.Printf("[payment][reconciliation] run stored run_id=%s file=%s lines=%d matched=%d ignored=%d discrepancies=%d",
+		run.ID, run.FileName, run.TotalLines, run.MatchedLines, run.IgnoredLines, len(run.Discrepancies))
+	return run, nil
+}
+
+// GetRun returns a stored run with its discrepancies.
+func (u *SettlementReconciliationUseCase) GetRun(ctx context.Context, id string) (entities.ReconciliationRun, error) {
+	run, err := u.getRunHeader(ctx, id)
+	if err != nil {
+		return entities.ReconciliationRun{}, err
+	}
+	run.Discrepancies = []entities.ReconciliationDiscrepancy{}
+	err = u.runs.ForEachDiscrepancy(ctx, run.ID, func(d entities.ReconciliationDiscrepancy) error {
+		run.Discrepancies = append(run.Discrepancies, d)
+		return nil
+	})
+	if err != nil {
+		return entities.ReconciliationRun{}, err
+	}
+	return run, nil
+}
+
+// WriteRunReport streams the discrepancies of a stored run to w as CSV (see
+// WriteDiscrepancyReport). Nothing is written when the run cannot be read.
+func (u *SettlementReconciliationUseCase) WriteRunReport(ctx context.Context, id string, w io.Writer) error {
+	run, err := u.getRunHeader(ctx, id)
+	if err != nil {
+		return err
+	}
+	cw, err := newDiscrepancyCSV(w)
+	if err != nil {
+		return err
+	}
+	err = u.runs.ForEachDiscrepancy(ctx, run.ID, func(d entities.ReconciliationDiscrepancy) error {
+		return writeDiscrepancyRow(cw, d)
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (u *SettlementReconciliationUseCase) getRunHeader(ctx context.Context, id string) (entities.ReconciliationRun, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return entities.ReconciliationRun{}, ErrInvalidReconciliationRunID
+	}
+	run, err := u.runs.GetByID(ctx, id)
+	if err != nil {
+		return entities.ReconciliationRun{}, err
+	}
+	if run.ID == "" {
+		return entities.ReconciliationRun{}, ErrReconciliationRunNotFound
+	}
+	return run, nil
+}
+
+// matchPayment looks the line up by provider payment ID; when the report line has no
+// known ID it falls back to the estimate payments (external_reference), picking the one
+// with the same amount when the estimate has several.
+func (u *SettlementReconciliationUseCase) matchPayment(ctx context.Context, line entities.SettlementLine) (entities.BillingPayment, error) {
+	if line.SourceID != "" {
+		p, err := u.payments.GetByID(ctx, line.SourceID)
+		if err != nil || p.ID != "" {
+			return p, err
+		}
+	}
+	if line.ExternalReference == "" {
+		return entities.BillingPayment{}, nil
+	}
+
+	page, err := u.payments.ListByEstimateID(ctx, line.ExternalReference, entities.PageRequest{Limit: maxPaymentPageLimit})
+	if err != nil {
+		return entities.BillingPayment{}, err
+	}
+	if len(page.Items) == 1 {
+		return page.Items[0], nil
+	}
+	for _, p := range page.Items {
+		if amountsEqual(p.Details.Amount, line.GrossAmount) {
+			return p, nil
+		}
+	}
+	return entities.BillingPayment{}, nil
+}
+
+// findUnsettled lists payments approved (at some point) in [from, to] that the report
+// did not settle.
+func (u *SettlementReconciliationUseCase) findUnsettled(ctx context.Context, from, to time.Time, settled map[string]bool) ([]entities.ReconciliationDiscrepancy, error) {
+	var out []entities.ReconciliationDiscrepancy
+	for _, status := range entities.SettlementLinePayment.ExpectedPaymentStatuses() {
+		page := entities.PageRequest{Limit: maxPaymentPageLimit}
+		for {
+			res, err := u.payments.ListByStatus(ctx, status, &from, &to, page)
+			if err != nil {
+				return nil, err
+			}
+			for _, p := range res.Items {
+				if settled[p.ID] {
+					continue
+				}
+				out = append(out, entities.ReconciliationDiscrepancy{
+					Kind:          entities.DiscrepancyMissingInReport,
+					PaymentID:     p.ID,
+					PaymentAmount: p.Details.Amount,
+					PaymentStatus: p.Status,
+					Detail:        "payment inside the report period is not in the report",
+				})
+			}
+			if res.NextCursor == "" {
+				break
+			}
+			page.Cursor = res.NextCursor
+		}
+	}
+	return out, nil
+}
+
+func checkSettlementLine(line entities.SettlementLine, p entities.BillingPayment) []entities.ReconciliationDiscrepancy {
+	var out []entities.ReconciliationDiscrepancy
+	switch {
+	case line.Type == entities.SettlementLinePayment && !amountsEqual(line.GrossAmount, p.Details.Amount):
+		out = append(out, lineDiscrepancy(entities.DiscrepancyAmountMismatch, line, p, "report amount differs from payment amount"))
+	case line.Type != entities.SettlementLinePayment && line.GrossAmount-p.Details.Amount > reconciliationAmountTolerance:
+		out = append(out, lineDiscrepancy(entities.DiscrepancyAmountMismatch, line, p, fmt.Sprintf("%s exceeds payment amount", line.Type)))
+	}
+	if expected := line.Type.ExpectedPaymentStatuses(); !slices.Contains(expected, p.Status) {
+		out = append(out, lineDiscrepancy(entities.DiscrepancyStatusMismatch, line, p,
+			fmt.Sprintf("%s line expects payment status %s", line.Type, joinStatuses(expected))))
+	}
+	return out
+}
+
+func lineDiscrepancy(kind entities.DiscrepancyKind, line entities.SettlementLine, p entities.BillingPayment, detail string) entities.ReconciliationDiscrepancy {
+	return entities.ReconciliationDiscrepancy{
+		Kind:              kind,
+		Line:              line.Line,
+		SourceID:          line.SourceID,
+		ExternalReference: line.ExternalReference,
+		LineType:          line.Type,
+		PaymentID:         p.ID,
+		ReportAmount:      line.GrossAmount,
+		PaymentAmount:     p.Details.Amount,
+		PaymentStatus:     p.Status,
+		Detail:            detail,
+	}
+}
+
+func widenPeriod(start, end *time.Time, t time.Time) (*time.Time, *time.Time) {
+	t = t.UTC()
+	if start == nil || t.Before(*start) {
+		start = &t
+	}
+	if end == nil || t.After(*end) {
+		end = &t
+	}
+	return start, end
+}
+
+func amountsEqual(a, b float64) bool {
+	return math.Abs(a-b) <= reconciliationAmountTolerance
+}
+
+func joinStatuses(statuses []entities.PaymentStatus) string {
+	parts := make([]string, 0, len(statuses))
+	for _, s := range statuses {
+		parts = append(parts, string(s))
+	}
+	return strings.Join(parts, "|")
+}
+
+// WriteDiscrepancyReport writes the run discrepancies as CSV (one finding per row).
+func WriteDiscrepancyReport(w io.Writer, run entities.ReconciliationRun) error {
+	cw, err := newDiscrepancyCSV(w)
+	if err != nil {
+		return err
+	}
+	for _, d := range run.Discrepancies {
+		if err := writeDiscrepancyRow(cw, d); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func newDiscrepancyCSV(w io.Writer) (*csv.Writer, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"kind", "line", "source_id", "external_reference", "line_type", "payment_id", "report_amount", "payment_amount", "payment_status", "detail"}); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func writeDiscrepancyRow(cw *csv.Writer, d entities.ReconciliationDiscrepancy) error {
+	line := ""
+	if d.Line > 0 {
+		line = strconv.Itoa(d.Line)
+	}
+	return cw.Write([]string{
+		string(d.Kind),
+		line,
+		d.SourceID,
+		d.ExternalReference,
+		string(d.LineType),
+		d.PaymentID,
+		strconv.FormatFloat(d.ReportAmount, 'f', 2, 64),
+		strconv.FormatFloat(d.PaymentAmount, 'f', 2, 64),
+		string(d.PaymentStatus),
+		d.Detail,
+	})
+}
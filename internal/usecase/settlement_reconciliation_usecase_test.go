@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"mecanica_xpto/internal/domain/entities"
+	mock_interfaces "mecanica_xpto/internal/usecase/interfaces/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestSettlementReconciliationUseCase_Reconcile(t *testing.T) {
+	t.Run("invalid report", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		parser := mock_interfaces.NewMockISettlementReportParser(ctrl)
+		payRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		runs := mock_interfaces.NewMockIReconciliationRunRepository(ctrl)
+		uc := NewSettlementReconciliationUseCase(parser, payRepo, runs)
+		uc.now = func() time.Time { return time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC) }
+		parser.EXPECT().ParseSettlementReport(gomock.Any()).Return(nil, errors.New("no SOURCE_ID"))
+
+		if _, err := uc.Reconcile(context.Background(), SettlementReport{Content: strings.NewReader("x")}); !errors.Is(err, ErrInvalidSettlementReport) {
+			t.Fatalf("expected ErrInvalidSettlementReport, got %v", err)
+		}
+	})
+
+	t.Run("flags missing, amount and status mismatches", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		parser := mock_interfaces.NewMockISettlementReportParser(ctrl)
+		payRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		runs := mock_interfaces.NewMockIReconciliationRunRepository(ctrl)
+		uc := NewSettlementReconciliationUseCase(parser, payRepo, runs)
+		uc.now = func() time.Time { return time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC) }
+		d1 := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+		d2 := time.Date(2026, 3, 5, 10, 0, 0, 0, time.UTC)
+		lines := []entities.SettlementLine{
+			{Line: 2, SourceID: "p1", Type: entities.SettlementLinePayment, GrossAmount: 150, TransactionDate: &d1},
+			{Line: 3, SourceID: "p2", Type: entities.SettlementLinePayment, GrossAmount: 90, TransactionDate: &d2},
+			{Line: 4, SourceID: "x9", ExternalReference: "est-3", Type: entities.SettlementLinePayment, GrossAmount: 40, TransactionDate: &d2},
+			{Line: 5, SourceID: "p4", Type: entities.SettlementLineRefund, GrossAmount: 60},
+			{Line: 6, SourceID: "zz", Type: entities.SettlementLinePayment, GrossAmount: 10},
+			{Line: 7, ProviderType: "WITHDRAWAL", Type: entities.SettlementLineOther},
+		}
+		parser.EXPECT().ParseSettlementReport(gomock.Any()).Return(lines, nil)
+
+		payRepo.EXPECT().GetByID(gomock.Any(), "p1").Return(entities.BillingPayment{ID: "p1", Status: entities.PaymentStatusAprovado, Details: entities.PaymentDetails{Amount: 150}}, nil)
+		payRepo.EXPECT().GetByID(gomock.Any(), "p2").Return(entities.BillingPayment{ID: "p2", Status: entities.PaymentStatusAprovado, Details: entities.PaymentDetails{Amount: 100}}, nil)
+		payRepo.EXPECT().GetByID(gomock.Any(), "x9").Return(entities.BillingPayment{}, nil)
+		payRepo.EXPECT().ListByEstimateID(gomock.Any(), "est-3", gomock.Any()).Return(entities.Page[entities.BillingPayment]{Items: []entities.BillingPayment{
+			{ID: "p3a", Status: entities.PaymentStatusNegado, Details: entities.PaymentDetails{Amount: 25}},
+			{ID: "p3b", Status: entities.PaymentStatusPendente, Details: entities.PaymentDetails{Amount: 40}},
+		}}, nil)
+		payRepo.EXPECT().GetByID(gomock.Any(), "p4").Return(entities.BillingPayment{ID: "p4", Status: entities.PaymentStatusAprovado, Details: entities.PaymentDetails{Amount: 50}}, nil)
+		payRepo.EXPECT().GetByID(gomock.Any(), "zz").Return(entities.BillingPayment{}, nil)
+
+		payRepo.EXPECT().ListByStatus(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, status entities.PaymentStatus, from, to *time.Time, page entities.PageRequest) (entities.Page[entities.BillingPayment], error) {
+				if !from.Equal(d1) || !to.Equal(d2) {
+					t.Fatalf("unexpected period %v..%v", from, to)
+				}
+				if status == entities.PaymentStatusAprovado && page.Cursor == "" {
+					return entities.Page[entities.BillingPayment]{Items: []entities.BillingPayment{{ID: "p1"}}, NextCursor: "c"}, nil
+				}
+				if status == entities.PaymentStatusAprovado {
+					return entities.Page[entities.BillingPayment]{Items: []entities.BillingPayment{{ID: "p5", Status: entities.PaymentStatusAprovado}}}, nil
+				}
+				return entities.Page[entities.BillingPayment]{}, nil
+			}).Times(5)
+
+		var stored entities.ReconciliationRun
+		runs.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, run entities.ReconciliationRun) error {
+			stored = run
+			return nil
+		})
+
+		run, err := uc.Reconcile(context.Background(), SettlementReport{FileName: " settlement.csv ", Actor: "finance", Content: strings.NewReader("csv")})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if run.ID == "" || stored.ID != run.ID || run.FileName != "settlement.csv" || run.Actor != "finance" {
+			t.Fatalf("unexpected run: %+v", run)
+		}
+		if run.TotalLines != 6 || run.MatchedLines != 4 || run.IgnoredLines != 1 {
+			t.Fatalf("unexpected counters: %+v", run)
+		}
+
+		type finding struct {
+			kind entities.DiscrepancyKind
+			id   string
+		}
+		var got []finding
+		for _, d := range run.Discrepancies {
+			got = append(got, finding{d.Kind, d.PaymentID + d.SourceID})
+		}
+		want := []finding{
+			{entities.DiscrepancyAmountMismatch, "p2p2"},
+			{entities.DiscrepancyStatusMismatch, "p3bx9"},
+			{entities.DiscrepancyAmountMismatch, "p4p4"},
+			{entities.DiscrepancyStatusMismatch, "p4p4"},
+			{entities.DiscrepancyMissingPayment, "zz"},
+			{entities.DiscrepancyMissingInReport, "p5"},
+		}
+		if len(got) != len(want) {
+			t.Fatalf("unexpected discrepancies: %+v", run.Discrepancies)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("discrepancy %d: expected %+v, got %+v", i, want[i], got[i])
+			}
+		}
+	})
+
+	t.Run("release report skips missing_in_report check", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		parser := mock_interfaces.NewMockISettlementReportParser(ctrl)
+		payRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		runs := mock_interfaces.NewMockIReconciliationRunRepository(ctrl)
+		uc := NewSettlementReconciliationUseCase(parser, payRepo, runs)
+		uc.now = func() time.Time { return time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC) }
+		parser.EXPECT().ParseSettlementReport(gomock.Any()).Return([]entities.SettlementLine{
+			{Line: 2, SourceID: "p1", Type: entities.SettlementLinePayment, GrossAmount: 150},
+		}, nil)
+		payRepo.EXPECT().GetByID(gomock.Any(), "p1").Return(entities.BillingPayment{ID: "p1", Status: entities.PaymentStatusAprovado, Details: entities.PaymentDetails{Amount: 150}}, nil)
+		runs.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+		run, err := uc.Reconcile(context.Background(), SettlementReport{Content: strings.NewReader("csv")})
+		if err != nil || len(run.Discrepancies) != 0 || run.PeriodStart != nil {
+			t.Fatalf("unexpected result err=%v run=%+v", err, run)
+		}
+	})
+}
+
+func TestSettlementReconciliationUseCase_GetRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	parser := mock_interfaces.NewMockISettlementReportParser(ctrl)
+	payRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+	runs := mock_interfaces.NewMockIReconciliationRunRepository(ctrl)
+	uc := NewSettlementReconciliationUseCase(parser, payRepo, runs)
+	uc.now = func() time.Time { return time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC) }
+	runs.EXPECT().GetByID(gomock.Any(), "missing").Return(entities.ReconciliationRun{}, nil)
+	runs.EXPECT().GetByID(gomock.Any(), "run-1").Return(entities.ReconciliationRun{ID: "run-1"}, nil)
+	runs.EXPECT().ForEachDiscrepancy(gomock.Any(), "run-1", gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, fn func(entities.ReconciliationDiscrepancy) error) error {
+			return fn(entities.ReconciliationDiscrepancy{Kind: entities.DiscrepancyMissingPayment, Line: 4})
+		})
+
+	if _, err := uc.GetRun(context.Background(), " "); !errors.Is(err, ErrInvalidReconciliationRunID) {
+		t.Fatalf("expected ErrInvalidReconciliationRunID, got %v", err)
+	}
+	if _, err := uc.GetRun(context.Background(), "missing"); !errors.Is(err, ErrReconciliationRunNotFound) {
+		t.Fatalf("expected ErrReconciliationRunNotFound, got %v", err)
+	}
+	run, err := uc.GetRun(context.Background(), "run-1")
+	if err != nil || run.ID != "run-1" || len(run.Discrepancies) != 1 || run.Discrepancies[0].Line != 4 {
+		t.Fatalf("unexpected result err=%v run=%+v", err, run)
+	}
+}
+
+func TestSettlementReconciliationUseCase_WriteRunReport(t *testing.T) {
+	t.Run("streams the stored discrepancies", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		parser := mock_interfaces.NewMockISettlementReportParser(ctrl)
+		payRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		runs := mock_interfaces.NewMockIReconciliationRunRepository(ctrl)
+		uc := NewSettlementReconciliationUseCase(parser, payRepo, runs)
+		uc.now = func() time.Time { return time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC) }
+		runs.EXPECT().GetByID(gomock.Any(), "run-1").Return(entities.ReconciliationRun{ID: "run-1"}, nil)
+		runs.EXPECT().ForEachDiscrepancy(gomock.Any(), "run-1", gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ string, fn func(entities.ReconciliationDiscrepancy) error) error {
+				for i := 1; i <= 3; i++ {
+					if err := fn(entities.ReconciliationDiscrepancy{Kind: entities.DiscrepancyMissingPayment, Line: i + 1}); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+
+		var buf bytes.Buffer
+		if err := uc.WriteRunReport(context.Background(), "run-1", &buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		rows, err := csv.NewReader(&buf).ReadAll()
+		if err != nil {
+			t.Fatalf("invalid csv: %v", err)
+		}
+		if len(rows) != 4 || rows[0][0] != "kind" || rows[3][1] != "4" {
+			t.Fatalf("unexpected rows: %v", rows)
+		}
+	})
+
+	t.Run("missing run writes nothing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		parser := mock_interfaces.NewMockISettlementReportParser(ctrl)
+		payRepo := mock_interfaces.NewMockIBillingPaymentRepository(ctrl)
+		runs := mock_interfaces.NewMockIReconciliationRunRepository(ctrl)
+		uc := NewSettlementReconciliationUseCase(parser, payRepo, runs)
+		uc.now = func() time.Time { return time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC) }
+		runs.EXPECT().GetByID(gomock.Any(), "missing").Return(entities.ReconciliationRun{}, nil)
+
+		var buf bytes.Buffer
+		if err := uc.WriteRunReport(context.Background(), "missing", &buf); !errors.Is(err, ErrReconciliationRunNotFound) {
+			t.Fatalf("expected ErrReconciliationRunNotFound, got %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Fatalf("expected no output, got %q", buf.String())
+		}
+	})
+}
+
+func TestWriteDiscrepancyReport(t *testing.T) {
+	run := entities.ReconciliationRun{Discrepancies: []entities.ReconciliationDiscrepancy{
+		{Kind: entities.DiscrepancyAmountMismatch, Line: 3, SourceID: "p2", PaymentID: "p2", ReportAmount: 90, PaymentAmount: 100, Detail: "report amount differs, check fees"},
+		{Kind: entities.DiscrepancyMissingInReport, PaymentID: "p5", PaymentAmount: 10},
+	}}
+	var buf bytes.Buffer
+	if err := WriteDiscrepancyReport(&buf, run); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid csv: %v", err)
+	}
+	if len(rows) != 3 || rows[0][0] != "kind" {
+		t.Fatalf("unexpected rows: %v", rows)
+	}
+	if rows[1][1] != "3" || rows[1][6] != "90.00" || rows[1][9] != "report amount differs, check fees" {
+		t.Fatalf("unexpected row: %v", rows[1])
+	}
+	if rows[2][1] != "" || rows[2][5] != "p5" {
+		t.Fatalf("unexpected row: %v", rows[2])
+	}
+}
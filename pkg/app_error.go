@@ -17,17 +17,19 @@ const (
 
 // ErrorResponse é a estrutura para enviar erros para o cliente HTTP
 type ErrorResponse struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
+	Code    string   `json:"code"`
+	Message string   `json:"message"`
+	Details []string `json:"details,omitempty"`
 }
 
 // AppError é o erro customizado do sistema, com info estruturada
 type AppError struct {
-	Code       string // Código identificador único do erro
-	Message    string // Mensagem amigável para o usuário
-	Layer      Layer  // Camada onde ocorreu o erro
-	Err        error  // Erro interno original (para logs)
-	HTTPStatus int    // Código HTTP associado (ex: 400, 404, 500)
+	Code       string   // Código identificador único do erro
+	Message    string   // Mensagem amigável para o usuário
+	Layer      Layer    // Camada onde ocorreu o erro
+	Err        error    // Erro interno original (para logs)
+	HTTPStatus int      // Código HTTP associado (ex: 400, 404, 500)
+	Details    []string // Detalhes opcionais expostos ao cliente (ex: campos inválidos)
 }
 
 // Error implementa a interface error para o AppError
@@ -46,7 +48,13 @@ func (e *AppError) Unwrap() error {
 
 // ToHTTPError transforma o AppError em um ErrorResponse para API
 func (e *AppError) ToHTTPError() ErrorResponse {
-	return ErrorResponse{Code: e.Code, Message: e.Message}
+	return ErrorResponse{Code: e.Code, Message: e.Message, Details: e.Details}
+}
+
+// WithDetails anexa detalhes ao erro e retorna o próprio AppError (encadeável)
+func (e *AppError) WithDetails(details ...string) *AppError {
+	e.Details = append(e.Details, details...)
+	return e
 }
 
 // ToJSON retorna o JSON serializado do ErrorResponse (útil para API)
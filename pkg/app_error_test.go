@@ -85,6 +85,23 @@ func TestAppErrorToJSON(t *testing.T) {
 	}
 }
 
+func TestAppErrorWithDetails(t *testing.T) {
+	appErr := NewDomainErrorSimple("C1", "msg", 400).WithDetails("/a", "/b/c")
+
+	resp := appErr.ToHTTPError()
+	if len(resp.Details) != 2 || resp.Details[0] != "/a" || resp.Details[1] != "/b/c" {
+		t.Errorf("ToHTTPError() retornou %+v, esperado Details=[/a /b/c]", resp)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(NewDomainErrorSimple("C2", "msg", 400).ToJSON(), &decoded); err != nil {
+		t.Fatalf("Erro ao deserializar JSON: %v", err)
+	}
+	if _, ok := decoded["details"]; ok {
+		t.Errorf("ToJSON() não deveria incluir details vazio: %+v", decoded)
+	}
+}
+
 func TestFactoryFunctions(t *testing.T) {
 	internalErr := errors.New("internal error")
 